@@ -0,0 +1,65 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Salladin95/rmqtools"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	AmqpExchange = "broker"
+
+	EmailVerificationRequestedKey = "auth.email-verification.requested"
+	PasswordResetRequestedKey     = "auth.password-reset.requested"
+	// ProfileUpdatedKey is consumed by the cards service, it counts study days in the timezone of the user
+	ProfileUpdatedKey = "auth.profile.updated"
+)
+
+type EmailVerificationRequested struct {
+	UserID    string `json:"userId"`
+	Email     string `json:"email"`
+	Name      string `json:"name"`
+	Token     string `json:"token"`
+	ExpiresAt int64  `json:"expiresAt"`
+}
+
+type PasswordResetRequested struct {
+	UserID    string `json:"userId"`
+	Email     string `json:"email"`
+	Name      string `json:"name"`
+	Token     string `json:"token"`
+	ExpiresAt int64  `json:"expiresAt"`
+}
+
+type ProfileUpdated struct {
+	UserID    string `json:"userId"`
+	Name      string `json:"name"`
+	Timezone  string `json:"timezone"`
+	UpdatedAt int64  `json:"updatedAt"`
+}
+
+// Publisher publishes auth events to the broker exchange
+type Publisher interface {
+	Publish(ctx context.Context, key string, payload any) error
+}
+
+type rabbitPublisher struct {
+	rabbit *amqp.Connection
+}
+
+func NewRabbitPublisher(rabbit *amqp.Connection) Publisher {
+	return &rabbitPublisher{rabbit: rabbit}
+}
+
+func (p *rabbitPublisher) Publish(ctx context.Context, key string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	emitter, err := rmqtools.NewEventEmitter(p.rabbit, AmqpExchange)
+	if err != nil {
+		return err
+	}
+	return emitter.Push(ctx, key, data)
+}
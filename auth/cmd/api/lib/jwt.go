@@ -0,0 +1,40 @@
+package lib
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+	"time"
+)
+
+const (
+	// ScopeFull grants access to every api
+	ScopeFull = "full"
+	// ScopeUnverified is granted to users who haven't verified their email yet
+	ScopeUnverified = "unverified"
+)
+
+type AccessClaims struct {
+	Email string `json:"email"`
+	Scope string `json:"scope"`
+	jwt.RegisteredClaims
+}
+
+// GenerateAccessToken signs a short-lived access token for the given user session
+func GenerateAccessToken(secret, sessionID, userID, email, scope string, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims := AccessClaims{
+		Email: email,
+		Scope: scope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        sessionID,
+			Subject:   userID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
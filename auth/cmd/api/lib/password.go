@@ -0,0 +1,30 @@
+package lib
+
+import "golang.org/x/crypto/bcrypt"
+
+// dummyPasswordHash is compared against when there is no real hash, it matches no password users could send
+const dummyPasswordHash = "$2a$10$RNd9.v5OLtL6arv5dwPc2.Vcy/4OVVzxxgmkHQp4UelP.ombh.0Uq"
+
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CompareHashAndPassword reports whether the password matches the hash. An empty hash,
+// e.g. of accounts created with an identity provider, never matches.
+func CompareHashAndPassword(hash, password string) bool {
+	if hash == "" {
+		CompareDummyPassword(password)
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// CompareDummyPassword takes as long as checking a real password, it's called for unknown accounts
+// so response times don't tell which emails are registered
+func CompareDummyPassword(password string) {
+	_ = bcrypt.CompareHashAndPassword([]byte(dummyPasswordHash), []byte(password))
+}
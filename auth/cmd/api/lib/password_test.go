@@ -0,0 +1,39 @@
+package lib
+
+import (
+	"golang.org/x/crypto/bcrypt"
+	"testing"
+)
+
+func TestDummyPasswordHashCostsAsMuchAsRealHashes(t *testing.T) {
+	// a cheaper dummy would let response times tell registered emails apart again
+	cost, err := bcrypt.Cost([]byte(dummyPasswordHash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost != bcrypt.DefaultCost {
+		t.Errorf("dummy hash cost %d, HashPassword uses %d", cost, bcrypt.DefaultCost)
+	}
+}
+
+func TestCompareHashAndPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		hash     string
+		password string
+		want     bool
+	}{
+		{hash, "correct horse", true},
+		{hash, "wrong horse", false},
+		{"", "", false},
+		{"", "correct horse", false},
+	}
+	for _, tt := range tests {
+		if got := CompareHashAndPassword(tt.hash, tt.password); got != tt.want {
+			t.Errorf("CompareHashAndPassword(%q, %q) = %v, want %v", tt.hash, tt.password, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package lib
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 key of the RFC 6238 test vectors, "12345678901234567890" in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCodeMatchesRFC6238(t *testing.T) {
+	// the RFC lists 8 digit codes, 6 digit codes are their last digits
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		code, err := TOTPCode(rfcSecret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != tt.code {
+			t.Errorf("TOTPCode at %d = %s, want %s", tt.unix, code, tt.code)
+		}
+		step, ok := ValidateTOTP(rfcSecret, tt.code, time.Unix(tt.unix, 0))
+		if !ok || step != tt.unix/totpPeriod {
+			t.Errorf("ValidateTOTP at %d = %d, %v, want step %d", tt.unix, step, ok, tt.unix/totpPeriod)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := now.Unix() / totpPeriod
+	codeAt := func(offset int64) string {
+		code, err := TOTPCode(rfcSecret, time.Unix((step+offset)*totpPeriod, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		step   int64
+		ok     bool
+	}{
+		{"current step", rfcSecret, codeAt(0), step, true},
+		{"previous step within the skew", rfcSecret, codeAt(-1), step - 1, true},
+		{"next step within the skew", rfcSecret, codeAt(1), step + 1, true},
+		{"step beyond the skew", rfcSecret, codeAt(-2), 0, false},
+		{"lowercase secret", "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", codeAt(0), step, true},
+		{"short code", rfcSecret, codeAt(0)[:5], 0, false},
+		{"invalid secret", "not base32!", codeAt(0), 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ValidateTOTP(tt.secret, tt.code, now)
+		if got != tt.step || ok != tt.ok {
+			t.Errorf("%s: got %d, %v, want %d, %v", tt.name, got, ok, tt.step, tt.ok)
+		}
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri, err := url.Parse(TOTPURI("Card Quizzler", "ann@example.com", rfcSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := uri.Query()
+	if uri.Scheme != "otpauth" || uri.Host != "totp" || uri.Path != "/Card Quizzler:ann@example.com" {
+		t.Errorf("uri %s", uri)
+	}
+	if query.Get("secret") != rfcSecret || query.Get("issuer") != "Card Quizzler" || query.Get("digits") != "6" || query.Get("period") != "30" {
+		t.Errorf("query %v", query)
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	format := regexp.MustCompile(`^[a-z0-9]{5}-[a-z0-9]{5}$`)
+	seen := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		if !format.MatchString(code) || seen[code] {
+			t.Errorf("code %q is malformed or repeated", code)
+		}
+		seen[code] = true
+		if typed := " " + strings.ToUpper(code[:3]) + " " + code[3:] + " "; NormalizeRecoveryCode(typed) != code {
+			t.Errorf("NormalizeRecoveryCode(%q) = %q, want %q", typed, NormalizeRecoveryCode(typed), code)
+		}
+	}
+	if len(codes) != 10 {
+		t.Errorf("generated %d codes, want 10", len(codes))
+	}
+}
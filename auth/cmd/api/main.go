@@ -3,6 +3,11 @@ package main
 import (
 	"context"
 	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/config"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/oauth"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/repositories"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/server"
 	auth "github.com/Salladin95/card-quizzler-microservices/auth-service/proto"
 	"github.com/Salladin95/rmqtools"
 	"github.com/rabbitmq/amqp091-go"
@@ -12,26 +17,29 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 )
 
 type App struct {
 	rabbit *amqp091.Connection
+	config config.AppCfg
 }
 
 func main() {
-	rabbitURL := os.Getenv("RABBITMQ_URL")
-	if rabbitURL == "" {
-		rabbitURL = defaultRabbitURL
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Println(err)
+		os.Exit(1)
 	}
 
-	rabbitConn, err := rmqtools.ConnectToRabbit(rabbitURL)
+	rabbitConn, err := rmqtools.ConnectToRabbit(cfg.AppCfg.RABBIT_URL)
 	if err != nil {
 		log.Println(err)
 		os.Exit(1)
 	}
 	defer rabbitConn.Close()
 
-	app := App{rabbit: rabbitConn}
+	app := App{rabbit: rabbitConn, config: cfg.AppCfg}
 	go app.gRPCListen()
 	waitForTerminationSignal()
 
@@ -45,44 +53,82 @@ func main() {
 	//}
 }
 
-type AuthServer struct {
-	auth.UnimplementedAuthServer
-}
-
-func (as *AuthServer) SignIn(ctx context.Context, req *auth.SignInRequest) (*auth.SignInResponse, error) {
-	fmt.Println("**** SIGN IN **** STH IS HAPPENING")
-	payload := req.GetPayload()
-	log.Printf("sign in: incoming payload - %v\n\n", payload)
-
-	// return response
-	res := &auth.SignInResponse{Message: fmt.Sprintf("sign-in: get your payload - %v", payload)}
-	return res, nil
-}
+func (app *App) gRPCListen() {
+	port := app.config.GRPC_PORT
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", port))
+	if err != nil {
+		log.Fatalf("failed to listen tcp port - %s. Err - %s", port, err.Error())
+	}
 
-func (as *AuthServer) SignUp(ctx context.Context, req *auth.SignUpRequest) (*auth.SignUpResponse, error) {
-	payload := req.GetPayload()
-	log.Printf("sign up: incoming payload - %v\n\n", payload)
-	// return response
-	res := &auth.SignUpResponse{Message: fmt.Sprintf("sign-up: get your payload - %v", payload)}
-	return res, nil
-}
+	repos, closeRepos, err := openRepositories(app.config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer closeRepos()
 
-func (app *App) gRPCListen() {
-	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", gRPCPort))
+	providers, err := oauth.NewProviders(oauthProviderConfigs(app.config))
 	if err != nil {
-		log.Fatalf("failed to listen tcp port - %s. Err - %s", gRPCPort, err.Error())
+		log.Fatalf("failed to configure identity providers: %v", err)
 	}
+	authServer := server.NewAuthServer(app.config, repos, events.NewRabbitPublisher(app.rabbit), providers)
 
 	gRPCServer := grpc.NewServer()
-	auth.RegisterAuthServer(gRPCServer, &AuthServer{})
+	auth.RegisterAuthServer(gRPCServer, authServer)
 
-	log.Printf("gRPC Server started on port %s", gRPCPort)
+	log.Printf("gRPC Server started on port %s", port)
 
 	if err := gRPCServer.Serve(listener); err != nil {
 		log.Fatalf("Failed to listen for gRPC: %v", err)
 	}
 }
 
+// openRepositories builds the repositories of the configured storage, the returned func releases them
+func openRepositories(cfg config.AppCfg) (server.Repositories, func(), error) {
+	if cfg.STORAGE == config.StorageMemory {
+		return server.Repositories{
+			Users:              repositories.NewMemoryUserRepository(),
+			Sessions:           repositories.NewMemorySessionRepository(),
+			VerificationTokens: repositories.NewMemoryOneTimeTokenRepository(),
+			ResetTokens:        repositories.NewMemoryOneTimeTokenRepository(),
+			SignInChallenges:   repositories.NewMemoryOneTimeTokenRepository(),
+			Identities:         repositories.NewMemoryIdentityRepository(),
+			OAuthStates:        repositories.NewMemoryOAuthStateRepository(),
+		}, func() {}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	db, err := repositories.OpenPostgres(ctx, cfg.DATABASE_URL)
+	if err != nil {
+		return server.Repositories{}, nil, fmt.Errorf("failed to open postgres: %w", err)
+	}
+	return server.Repositories{
+		Users:              repositories.NewPostgresUserRepository(db),
+		Sessions:           repositories.NewPostgresSessionRepository(db),
+		VerificationTokens: repositories.NewPostgresOneTimeTokenRepository(db, repositories.TokenKindVerification),
+		ResetTokens:        repositories.NewPostgresOneTimeTokenRepository(db, repositories.TokenKindPasswordReset),
+		SignInChallenges:   repositories.NewPostgresOneTimeTokenRepository(db, repositories.TokenKindSignInChallenge),
+		Identities:         repositories.NewPostgresIdentityRepository(db),
+		OAuthStates:        repositories.NewPostgresOAuthStateRepository(db),
+	}, func() { db.Close() }, nil
+}
+
+func oauthProviderConfigs(cfg config.AppCfg) []oauth.ProviderConfig {
+	configs := make([]oauth.ProviderConfig, 0, len(cfg.OAUTH_PROVIDERS))
+	for _, provider := range cfg.OAUTH_PROVIDERS {
+		configs = append(configs, oauth.ProviderConfig{
+			Name:         provider.NAME,
+			Type:         provider.TYPE,
+			Issuer:       provider.ISSUER,
+			ClientID:     provider.CLIENT_ID,
+			ClientSecret: provider.CLIENT_SECRET,
+			RedirectURL:  provider.REDIRECT_URL,
+			Scopes:       provider.SCOPES,
+		})
+	}
+	return configs
+}
+
 func waitForTerminationSignal() {
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
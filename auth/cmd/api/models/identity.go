@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Identity links an account of an external identity provider to a user
+type Identity struct {
+	Provider  string
+	Subject   string
+	UserID    string
+	Email     string
+	CreatedAt time.Time
+}
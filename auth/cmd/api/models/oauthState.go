@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// OAuthState remembers an authorization request started with an identity provider
+// until the provider redirects the user back with the code
+type OAuthState struct {
+	// StateHash is the hash of the state parameter sent to the provider
+	StateHash    string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+}
+
+func (s OAuthState) Expired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
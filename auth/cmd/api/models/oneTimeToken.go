@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// OneTimeToken is a single-use token sent to the user's email, e.g. to verify the address or reset the password.
+// Only the hash of the token is stored, the raw value is sent to the user.
+type OneTimeToken struct {
+	Hash      string
+	UserID    string
+	ExpiresAt time.Time
+	// Attempts counts failed uses of tokens that tolerate a few mistakes, e.g. sign-in challenges
+	Attempts int
+}
+
+func (t OneTimeToken) Expired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Session is created on sign in and lives as long as its refresh token
+type Session struct {
+	ID               string
+	UserID           string
+	RefreshTokenHash string
+	ExpiresAt        time.Time
+	CreatedAt        time.Time
+}
+
+func (s Session) Expired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// DefaultTimezone is the timezone of users who haven't picked one
+const DefaultTimezone = "UTC"
+
+type User struct {
+	ID       string
+	Name     string
+	Email    string
+	Birthday string
+	// Timezone is an IANA name, the cards service counts study days in it
+	Timezone      string
+	PasswordHash  string
+	EmailVerified bool
+	TOTP          TOTP
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// TOTP holds the two-factor authentication state of a user
+type TOTP struct {
+	Enabled bool
+	Secret  string
+	// PendingSecret is set during enrollment until the user confirms it with a valid code
+	PendingSecret string
+	// LastUsedStep is the time step of the last accepted code, codes can't be replayed within their window
+	LastUsedStep       int64
+	RecoveryCodeHashes []string
+}
@@ -0,0 +1,96 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	gitHubAuthorizeURL = "https://github.com/login/oauth/authorize"
+	gitHubTokenURL     = "https://github.com/login/oauth/access_token"
+	gitHubAPIURL       = "https://api.github.com"
+)
+
+// gitHubProvider uses plain OAuth2, there is no id token, so the identity comes from the api
+// and the nonce isn't needed: the access token is fetched directly from GitHub over tls
+type gitHubProvider struct {
+	config ProviderConfig
+	client *http.Client
+	// endpoints point to github.com, tests replace them with a local server
+	authorizeURL string
+	tokenURL     string
+	apiURL       string
+}
+
+func newGitHubProvider(cfg ProviderConfig, client *http.Client) *gitHubProvider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"read:user", "user:email"}
+	}
+	return &gitHubProvider{
+		config:       cfg,
+		client:       client,
+		authorizeURL: gitHubAuthorizeURL,
+		tokenURL:     gitHubTokenURL,
+		apiURL:       gitHubAPIURL,
+	}
+}
+
+func (p *gitHubProvider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	query := url.Values{}
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	return appendQuery(p.authorizeURL, query), nil
+}
+
+func (p *gitHubProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (Identity, error) {
+	tokens, err := exchangeCode(ctx, p.client, p.tokenURL, p.config, code, codeVerifier)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	var user struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	}
+	if err := getJSON(ctx, p.client, p.apiURL+"/user", tokens.AccessToken, &user); err != nil {
+		return Identity{}, err
+	}
+	if user.ID == 0 {
+		return Identity{}, fmt.Errorf("github returned no user id")
+	}
+
+	// the public profile email may be missing or unverified, the primary one from /user/emails is reliable
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := getJSON(ctx, p.client, p.apiURL+"/user/emails", tokens.AccessToken, &emails); err != nil {
+		return Identity{}, err
+	}
+
+	identity := Identity{
+		Provider: p.config.Name,
+		Subject:  strconv.FormatInt(user.ID, 10),
+		Name:     user.Name,
+	}
+	if identity.Name == "" {
+		identity.Name = user.Login
+	}
+	for _, email := range emails {
+		if email.Primary {
+			identity.Email = email.Email
+			identity.EmailVerified = email.Verified
+		}
+	}
+	return identity, nil
+}
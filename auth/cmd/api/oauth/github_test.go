@@ -0,0 +1,81 @@
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newMockGitHub serves the token endpoint and the user api of GitHub with the given emails
+func newMockGitHub(t *testing.T, emails string) *gitHubProvider {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("code") != "code" || r.PostForm.Get("code_verifier") != "verifier" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "bad_verification_code"})
+			return
+		}
+		writeJSON(w, http.StatusOK, tokenResponse{AccessToken: "access-token", TokenType: "bearer"})
+	})
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id":583231,"login":"octocat","name":""}`))
+	})
+	mux.HandleFunc("/user/emails", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(emails))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	provider := newGitHubProvider(ProviderConfig{Name: "github", Type: TypeGitHub, ClientID: "id", ClientSecret: "secret"}, server.Client())
+	provider.authorizeURL = server.URL + "/login/oauth/authorize"
+	provider.tokenURL = server.URL + "/login/oauth/access_token"
+	provider.apiURL = server.URL
+	return provider
+}
+
+func TestGitHubExchange(t *testing.T) {
+	tests := []struct {
+		name   string
+		emails string
+		want   Identity
+	}{
+		{
+			name:   "verified primary email",
+			emails: `[{"email":"old@example.com","primary":false,"verified":true},{"email":"octo@example.com","primary":true,"verified":true}]`,
+			want:   Identity{Provider: "github", Subject: "583231", Email: "octo@example.com", EmailVerified: true, Name: "octocat"},
+		},
+		{
+			// the auth server must not link such an identity to an existing account
+			name:   "unverified primary email",
+			emails: `[{"email":"octo@example.com","primary":true,"verified":false}]`,
+			want:   Identity{Provider: "github", Subject: "583231", Email: "octo@example.com", EmailVerified: false, Name: "octocat"},
+		},
+		{
+			name:   "no emails",
+			emails: `[]`,
+			want:   Identity{Provider: "github", Subject: "583231", Name: "octocat"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := newMockGitHub(t, tt.emails).Exchange(context.Background(), "code", "verifier", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if identity != tt.want {
+				t.Errorf("identity %+v, want %+v", identity, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitHubExchangeSendsCodeVerifier(t *testing.T) {
+	if _, err := newMockGitHub(t, `[]`).Exchange(context.Background(), "code", "other-verifier", ""); err == nil {
+		t.Error("exchange with a wrong verifier succeeded")
+	}
+}
@@ -0,0 +1,79 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+	TokenType   string `json:"token_type"`
+	Error       string `json:"error"`
+	ErrorDesc   string `json:"error_description"`
+}
+
+// exchangeCode performs the token request of the authorization code flow
+func exchangeCode(ctx context.Context, client *http.Client, endpoint string, cfg ProviderConfig, code, codeVerifier string) (tokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", cfg.RedirectURL)
+	form.Set("client_id", cfg.ClientID)
+	form.Set("client_secret", cfg.ClientSecret)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return tokenResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var res tokenResponse
+	if err := doJSON(client, req, &res); err != nil {
+		return tokenResponse{}, err
+	}
+	if res.Error != "" {
+		return tokenResponse{}, fmt.Errorf("token endpoint error - %s: %s", res.Error, res.ErrorDesc)
+	}
+	if res.AccessToken == "" {
+		return tokenResponse{}, fmt.Errorf("token endpoint returned no access token")
+	}
+	return res, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, endpoint, accessToken string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	return doJSON(client, req, target)
+}
+
+func doJSON(client *http.Client, req *http.Request, target any) error {
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	// token endpoints report errors as json with 400 status, let the caller look at them
+	if res.StatusCode >= 300 && !(res.StatusCode == http.StatusBadRequest && json.Valid(body)) {
+		return fmt.Errorf("%s %s responded with %d", req.Method, req.URL.Redacted(), res.StatusCode)
+	}
+	return json.Unmarshal(body, target)
+}
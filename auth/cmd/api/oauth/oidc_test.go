@@ -0,0 +1,254 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testClientID    = "quizzler"
+	testRedirectURL = "https://quizzler.example.com/oauth/callback"
+	testKeyID       = "key-1"
+)
+
+// mockOIDCProvider serves discovery, jwks and token endpoints of an identity provider.
+// Codes are issued by authorize, the token endpoint checks the PKCE verifier against their challenge.
+type mockOIDCProvider struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	// signingKey signs the id tokens, it differs from key to forge signatures
+	signingKey *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]issuedCode
+}
+
+type issuedCode struct {
+	challenge string
+	claims    jwt.MapClaims
+}
+
+func newMockOIDCProvider(t *testing.T) *mockOIDCProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &mockOIDCProvider{t: t, key: key, signingKey: key, codes: make(map[string]issuedCode)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, discoveryDocument{
+			Issuer:                m.server.URL,
+			AuthorizationEndpoint: m.server.URL + "/authorize",
+			TokenEndpoint:         m.server.URL + "/token",
+			JWKSURI:               m.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"keys": []jsonWebKey{{
+			Kty: "RSA",
+			Kid: testKeyID,
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", m.token)
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func (m *mockOIDCProvider) provider() *oidcProvider {
+	return newOIDCProvider(ProviderConfig{
+		Name:         "mock",
+		Type:         TypeOIDC,
+		Issuer:       m.server.URL,
+		ClientID:     testClientID,
+		ClientSecret: "secret",
+		RedirectURL:  testRedirectURL,
+	}, m.server.Client())
+}
+
+// claims returns valid id token claims for the nonce, tests break them to check the validation
+func (m *mockOIDCProvider) claims(nonce string) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":            m.server.URL,
+		"aud":            testClientID,
+		"sub":            "user-42",
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          nonce,
+		"email":          "ann@example.com",
+		"email_verified": "true",
+		"name":           "Ann",
+	}
+}
+
+// authorize plays the consent page: it reads the authorization url and issues a code for the claims
+func (m *mockOIDCProvider) authorize(authorizationURL string, claims jwt.MapClaims) string {
+	m.t.Helper()
+	parsed, err := url.Parse(authorizationURL)
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	query := parsed.Query()
+	if query.Get("code_challenge_method") != "S256" || query.Get("client_id") != testClientID {
+		m.t.Fatalf("authorization url %s", authorizationURL)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	code := "code-" + query.Get("state")
+	m.codes[code] = issuedCode{challenge: query.Get("code_challenge"), claims: claims}
+	return code
+}
+
+func (m *mockOIDCProvider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	m.mu.Lock()
+	issued, ok := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mu.Unlock()
+	if !ok || r.PostForm.Get("redirect_uri") != testRedirectURL {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	if S256Challenge(r.PostForm.Get("code_verifier")) != issued.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "PKCE verification failed"})
+		return
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, issued.claims)
+	token.Header["kid"] = testKeyID
+	idToken, err := token.SignedString(m.signingKey)
+	if err != nil {
+		m.t.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, tokenResponse{AccessToken: "access-token", IDToken: idToken, TokenType: "Bearer"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// startFlow returns the authorization url along with the values the auth server keeps in the oauth state
+func startFlow(t *testing.T, provider Provider) (authorizationURL, nonce, verifier string) {
+	t.Helper()
+	state, err := GenerateVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nonce, err = GenerateVerifier(); err != nil {
+		t.Fatal(err)
+	}
+	if verifier, err = GenerateVerifier(); err != nil {
+		t.Fatal(err)
+	}
+	authorizationURL, err = provider.AuthCodeURL(context.Background(), state, nonce, S256Challenge(verifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return authorizationURL, nonce, verifier
+}
+
+func TestOIDCExchange(t *testing.T) {
+	mock := newMockOIDCProvider(t)
+	provider := mock.provider()
+	authorizationURL, nonce, verifier := startFlow(t, provider)
+	if !strings.HasPrefix(authorizationURL, mock.server.URL+"/authorize?") {
+		t.Errorf("authorization url %s doesn't point to the discovered endpoint", authorizationURL)
+	}
+	code := mock.authorize(authorizationURL, mock.claims(nonce))
+
+	identity, err := provider.Exchange(context.Background(), code, verifier, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Identity{Provider: "mock", Subject: "user-42", Email: "ann@example.com", EmailVerified: true, Name: "Ann"}
+	if identity != want {
+		t.Errorf("identity %+v, want %+v", identity, want)
+	}
+}
+
+func TestOIDCExchangeRejectsInvalidIDTokens(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		setup func(mock *mockOIDCProvider, claims jwt.MapClaims)
+	}{
+		{"nonce mismatch", func(mock *mockOIDCProvider, claims jwt.MapClaims) { claims["nonce"] = "replayed-nonce" }},
+		{"bad signature", func(mock *mockOIDCProvider, claims jwt.MapClaims) { mock.signingKey = otherKey }},
+		{"expired", func(mock *mockOIDCProvider, claims jwt.MapClaims) {
+			claims["iat"] = time.Now().Add(-2 * time.Hour).Unix()
+			claims["exp"] = time.Now().Add(-time.Hour).Unix()
+		}},
+		{"no expiry", func(mock *mockOIDCProvider, claims jwt.MapClaims) { delete(claims, "exp") }},
+		{"other audience", func(mock *mockOIDCProvider, claims jwt.MapClaims) { claims["aud"] = "someone-else" }},
+		{"other issuer", func(mock *mockOIDCProvider, claims jwt.MapClaims) { claims["iss"] = "https://evil.example.com" }},
+		{"no subject", func(mock *mockOIDCProvider, claims jwt.MapClaims) { delete(claims, "sub") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newMockOIDCProvider(t)
+			provider := mock.provider()
+			authorizationURL, nonce, verifier := startFlow(t, provider)
+			claims := mock.claims(nonce)
+			tt.setup(mock, claims)
+			code := mock.authorize(authorizationURL, claims)
+
+			if _, err := provider.Exchange(context.Background(), code, verifier, nonce); !errors.Is(err, ErrInvalidIDToken) {
+				t.Errorf("exchange returned %v, want ErrInvalidIDToken", err)
+			}
+		})
+	}
+}
+
+func TestOIDCExchangeSendsCodeVerifier(t *testing.T) {
+	mock := newMockOIDCProvider(t)
+	provider := mock.provider()
+	authorizationURL, nonce, _ := startFlow(t, provider)
+	code := mock.authorize(authorizationURL, mock.claims(nonce))
+
+	// a verifier of another flow doesn't match the challenge sent with the authorization request
+	_, _, otherVerifier := startFlow(t, provider)
+	_, err := provider.Exchange(context.Background(), code, otherVerifier, nonce)
+	if err == nil || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Errorf("exchange with a wrong verifier returned %v, want the token endpoint error", err)
+	}
+}
+
+func TestOIDCDiscoveryRejectsIssuerMismatch(t *testing.T) {
+	mock := newMockOIDCProvider(t)
+	provider := mock.provider()
+	provider.config.Issuer = mock.server.URL + "/"
+	if _, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "challenge"); err == nil ||
+		!strings.Contains(err.Error(), "issuer mismatch") {
+		t.Errorf("discovery returned %v, want an issuer mismatch", err)
+	}
+}
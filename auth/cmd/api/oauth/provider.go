@@ -0,0 +1,75 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	// TypeOIDC is any OpenID Connect provider supporting discovery, e.g. Google
+	TypeOIDC = "oidc"
+	// TypeGitHub is GitHub, which speaks plain OAuth2 and exposes the profile through its api
+	TypeGitHub = "github"
+)
+
+var (
+	ErrUnknownProvider = errors.New("unknown provider")
+	ErrInvalidIDToken  = errors.New("invalid id token")
+)
+
+// ProviderConfig describes a configured identity provider
+type ProviderConfig struct {
+	Name         string
+	Type         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Identity is what a provider tells about the signed-in user
+type Identity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Provider runs the authorization code flow against an identity provider
+type Provider interface {
+	// AuthCodeURL returns the url of the provider's consent page
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange trades the authorization code for the user's identity, the nonce is checked against the id token
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (Identity, error)
+}
+
+// Providers holds configured providers by name
+type Providers map[string]Provider
+
+func NewProviders(configs []ProviderConfig) (Providers, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	providers := make(Providers, len(configs))
+	for _, cfg := range configs {
+		switch cfg.Type {
+		case TypeOIDC:
+			providers[cfg.Name] = newOIDCProvider(cfg, client)
+		case TypeGitHub:
+			providers[cfg.Name] = newGitHubProvider(cfg, client)
+		default:
+			return nil, errors.New("unsupported provider type - " + cfg.Type)
+		}
+	}
+	return providers, nil
+}
+
+func (p Providers) Get(name string) (Provider, error) {
+	provider, ok := p[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	return provider, nil
+}
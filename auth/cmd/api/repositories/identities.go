@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/models"
+	"sync"
+)
+
+type IdentityRepository interface {
+	Create(ctx context.Context, identity models.Identity) error
+	Get(ctx context.Context, provider, subject string) (models.Identity, error)
+}
+
+type memoryIdentityRepository struct {
+	mu         sync.RWMutex
+	identities map[string]models.Identity
+}
+
+// NewMemoryIdentityRepository creates an IdentityRepository keeping identities in memory
+func NewMemoryIdentityRepository() IdentityRepository {
+	return &memoryIdentityRepository{identities: make(map[string]models.Identity)}
+}
+
+func identityKey(provider, subject string) string {
+	return provider + "|" + subject
+}
+
+func (r *memoryIdentityRepository) Create(ctx context.Context, identity models.Identity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := identityKey(identity.Provider, identity.Subject)
+	if _, ok := r.identities[key]; ok {
+		return ErrAlreadyExists
+	}
+	r.identities[key] = identity
+	return nil
+}
+
+func (r *memoryIdentityRepository) Get(ctx context.Context, provider, subject string) (models.Identity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	identity, ok := r.identities[identityKey(provider, subject)]
+	if !ok {
+		return models.Identity{}, ErrNotFound
+	}
+	return identity, nil
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/models"
+	"sync"
+)
+
+type OAuthStateRepository interface {
+	Save(ctx context.Context, state models.OAuthState) error
+	// Consume removes the state with the given hash and returns it, so a callback can't be replayed
+	Consume(ctx context.Context, stateHash string) (models.OAuthState, error)
+}
+
+type memoryOAuthStateRepository struct {
+	mu     sync.Mutex
+	states map[string]models.OAuthState
+}
+
+// NewMemoryOAuthStateRepository creates an OAuthStateRepository keeping states in memory
+func NewMemoryOAuthStateRepository() OAuthStateRepository {
+	return &memoryOAuthStateRepository{states: make(map[string]models.OAuthState)}
+}
+
+func (r *memoryOAuthStateRepository) Save(ctx context.Context, state models.OAuthState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.states[state.StateHash]; ok {
+		return ErrAlreadyExists
+	}
+	r.states[state.StateHash] = state
+	return nil
+}
+
+func (r *memoryOAuthStateRepository) Consume(ctx context.Context, stateHash string) (models.OAuthState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state, ok := r.states[stateHash]
+	if !ok {
+		return models.OAuthState{}, ErrNotFound
+	}
+	delete(r.states, stateHash)
+	return state, nil
+}
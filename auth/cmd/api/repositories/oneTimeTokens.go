@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/models"
+	"sync"
+)
+
+type OneTimeTokenRepository interface {
+	Save(ctx context.Context, token models.OneTimeToken) error
+	// Consume removes the token with the given hash and returns it, so a token can only be used once
+	Consume(ctx context.Context, hash string) (models.OneTimeToken, error)
+	DeleteByUser(ctx context.Context, userID string) error
+}
+
+type memoryOneTimeTokenRepository struct {
+	mu     sync.Mutex
+	tokens map[string]models.OneTimeToken
+}
+
+// NewMemoryOneTimeTokenRepository creates a OneTimeTokenRepository keeping tokens in memory
+func NewMemoryOneTimeTokenRepository() OneTimeTokenRepository {
+	return &memoryOneTimeTokenRepository{tokens: make(map[string]models.OneTimeToken)}
+}
+
+func (r *memoryOneTimeTokenRepository) Save(ctx context.Context, token models.OneTimeToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tokens[token.Hash]; ok {
+		return ErrAlreadyExists
+	}
+	r.tokens[token.Hash] = token
+	return nil
+}
+
+func (r *memoryOneTimeTokenRepository) Consume(ctx context.Context, hash string) (models.OneTimeToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.tokens[hash]
+	if !ok {
+		return models.OneTimeToken{}, ErrNotFound
+	}
+	delete(r.tokens, hash)
+	return token, nil
+}
+
+func (r *memoryOneTimeTokenRepository) DeleteByUser(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for hash, token := range r.tokens {
+		if token.UserID == userID {
+			delete(r.tokens, hash)
+		}
+	}
+	return nil
+}
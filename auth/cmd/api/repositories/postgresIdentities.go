@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/models"
+)
+
+type postgresIdentityRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresIdentityRepository(db *sql.DB) IdentityRepository {
+	return &postgresIdentityRepository{db: db}
+}
+
+func (r *postgresIdentityRepository) Create(ctx context.Context, identity models.Identity) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO identities (provider, subject, user_id, email, created_at) VALUES ($1, $2, $3, $4, $5)`,
+		identity.Provider, identity.Subject, identity.UserID, identity.Email, identity.CreatedAt,
+	)
+	return mapPostgresError(err)
+}
+
+func (r *postgresIdentityRepository) Get(ctx context.Context, provider, subject string) (models.Identity, error) {
+	var identity models.Identity
+	err := r.db.QueryRowContext(ctx,
+		`SELECT provider, subject, user_id, email, created_at FROM identities WHERE provider = $1 AND subject = $2`,
+		provider, subject,
+	).Scan(&identity.Provider, &identity.Subject, &identity.UserID, &identity.Email, &identity.CreatedAt)
+	if err != nil {
+		return models.Identity{}, mapPostgresError(err)
+	}
+	return identity, nil
+}
@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/models"
+)
+
+type postgresOAuthStateRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresOAuthStateRepository(db *sql.DB) OAuthStateRepository {
+	return &postgresOAuthStateRepository{db: db}
+}
+
+func (r *postgresOAuthStateRepository) Save(ctx context.Context, state models.OAuthState) error {
+	// most abandoned sign-ins never come back, their states are dropped once expired
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM oauth_states WHERE expires_at <= now()`); err != nil {
+		return err
+	}
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO oauth_states (state_hash, provider, nonce, code_verifier, expires_at) VALUES ($1, $2, $3, $4, $5)`,
+		state.StateHash, state.Provider, state.Nonce, state.CodeVerifier, state.ExpiresAt,
+	)
+	return mapPostgresError(err)
+}
+
+func (r *postgresOAuthStateRepository) Consume(ctx context.Context, stateHash string) (models.OAuthState, error) {
+	var state models.OAuthState
+	err := r.db.QueryRowContext(ctx,
+		`DELETE FROM oauth_states WHERE state_hash = $1 RETURNING state_hash, provider, nonce, code_verifier, expires_at`,
+		stateHash,
+	).Scan(&state.StateHash, &state.Provider, &state.Nonce, &state.CodeVerifier, &state.ExpiresAt)
+	if err != nil {
+		return models.OAuthState{}, mapPostgresError(err)
+	}
+	return state, nil
+}
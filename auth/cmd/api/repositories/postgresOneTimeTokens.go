@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/models"
+)
+
+const (
+	// TokenKindVerification marks tokens confirming email addresses
+	TokenKindVerification = "verification"
+	// TokenKindPasswordReset marks tokens resetting passwords
+	TokenKindPasswordReset = "password-reset"
+	// TokenKindSignInChallenge marks tokens waiting for the second factor
+	TokenKindSignInChallenge = "sign-in-challenge"
+)
+
+type postgresOneTimeTokenRepository struct {
+	db   *sql.DB
+	kind string
+}
+
+// NewPostgresOneTimeTokenRepository creates a OneTimeTokenRepository for tokens of one kind,
+// a token of another kind is never found by it
+func NewPostgresOneTimeTokenRepository(db *sql.DB, kind string) OneTimeTokenRepository {
+	return &postgresOneTimeTokenRepository{db: db, kind: kind}
+}
+
+func (r *postgresOneTimeTokenRepository) Save(ctx context.Context, token models.OneTimeToken) error {
+	// tokens which were never used would stay forever, expired ones of the user are dropped here
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM one_time_tokens WHERE user_id = $1 AND kind = $2 AND expires_at <= now()`, token.UserID, r.kind,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx,
+		`INSERT INTO one_time_tokens (hash, kind, user_id, expires_at, attempts) VALUES ($1, $2, $3, $4, $5)`,
+		token.Hash, r.kind, token.UserID, token.ExpiresAt, token.Attempts,
+	)
+	return mapPostgresError(err)
+}
+
+func (r *postgresOneTimeTokenRepository) Consume(ctx context.Context, hash string) (models.OneTimeToken, error) {
+	var token models.OneTimeToken
+	err := r.db.QueryRowContext(ctx,
+		`DELETE FROM one_time_tokens WHERE hash = $1 AND kind = $2 RETURNING hash, user_id, expires_at, attempts`,
+		hash, r.kind,
+	).Scan(&token.Hash, &token.UserID, &token.ExpiresAt, &token.Attempts)
+	if err != nil {
+		return models.OneTimeToken{}, mapPostgresError(err)
+	}
+	return token, nil
+}
+
+func (r *postgresOneTimeTokenRepository) DeleteByUser(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM one_time_tokens WHERE user_id = $1 AND kind = $2`, userID, r.kind)
+	return err
+}
@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/models"
+)
+
+type postgresSessionRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresSessionRepository(db *sql.DB) SessionRepository {
+	return &postgresSessionRepository{db: db}
+}
+
+func (r *postgresSessionRepository) Save(ctx context.Context, session models.Session) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO sessions (id, user_id, refresh_token_hash, expires_at, created_at) VALUES ($1, $2, $3, $4, $5)`,
+		session.ID, session.UserID, session.RefreshTokenHash, session.ExpiresAt, session.CreatedAt,
+	)
+	return mapPostgresError(err)
+}
+
+func (r *postgresSessionRepository) ConsumeByRefreshToken(ctx context.Context, refreshTokenHash string) (models.Session, error) {
+	var session models.Session
+	// deleting and returning in one statement lets only one of concurrent refreshes win
+	err := r.db.QueryRowContext(ctx,
+		`DELETE FROM sessions WHERE refresh_token_hash = $1 RETURNING id, user_id, refresh_token_hash, expires_at, created_at`,
+		refreshTokenHash,
+	).Scan(&session.ID, &session.UserID, &session.RefreshTokenHash, &session.ExpiresAt, &session.CreatedAt)
+	if err != nil {
+		return models.Session{}, mapPostgresError(err)
+	}
+	return session, nil
+}
+
+func (r *postgresSessionRepository) DeleteByUser(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM sessions WHERE user_id = $1`, userID)
+	return err
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/models"
+	"github.com/lib/pq"
+	"strings"
+)
+
+const userColumns = `id, name, email, birthday, timezone, password_hash, email_verified, totp_enabled, totp_secret,
+	totp_pending_secret, totp_last_used_step, totp_recovery_code_hashes, created_at, updated_at`
+
+type postgresUserRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresUserRepository(db *sql.DB) UserRepository {
+	return &postgresUserRepository{db: db}
+}
+
+func (r *postgresUserRepository) Create(ctx context.Context, user models.User) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO users (`+userColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+		user.ID, user.Name, strings.TrimSpace(user.Email), user.Birthday, user.Timezone, user.PasswordHash, user.EmailVerified,
+		user.TOTP.Enabled, user.TOTP.Secret, user.TOTP.PendingSecret, user.TOTP.LastUsedStep,
+		textArray(user.TOTP.RecoveryCodeHashes), user.CreatedAt, user.UpdatedAt,
+	)
+	return mapPostgresError(err)
+}
+
+func (r *postgresUserRepository) Update(ctx context.Context, user models.User) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE users SET name = $2, email = $3, birthday = $4, timezone = $5, password_hash = $6,
+		email_verified = $7, totp_enabled = $8, totp_secret = $9, totp_pending_secret = $10, totp_last_used_step = $11,
+		totp_recovery_code_hashes = $12, updated_at = $13 WHERE id = $1`,
+		user.ID, user.Name, strings.TrimSpace(user.Email), user.Birthday, user.Timezone, user.PasswordHash, user.EmailVerified,
+		user.TOTP.Enabled, user.TOTP.Secret, user.TOTP.PendingSecret, user.TOTP.LastUsedStep,
+		textArray(user.TOTP.RecoveryCodeHashes), user.UpdatedAt,
+	)
+	if err != nil {
+		return mapPostgresError(err)
+	}
+	return expectAffected(result)
+}
+
+func (r *postgresUserRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result)
+}
+
+func (r *postgresUserRepository) GetByID(ctx context.Context, id string) (models.User, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1`, id)
+	return scanUser(row)
+}
+
+func (r *postgresUserRepository) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE lower(email) = $1`, normalizeEmail(email))
+	return scanUser(row)
+}
+
+func scanUser(row scanner) (models.User, error) {
+	var user models.User
+	var recoveryCodeHashes pq.StringArray
+	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Birthday, &user.Timezone, &user.PasswordHash, &user.EmailVerified,
+		&user.TOTP.Enabled, &user.TOTP.Secret, &user.TOTP.PendingSecret, &user.TOTP.LastUsedStep,
+		&recoveryCodeHashes, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return models.User{}, mapPostgresError(err)
+	}
+	if len(recoveryCodeHashes) > 0 {
+		user.TOTP.RecoveryCodeHashes = recoveryCodeHashes
+	}
+	return user, nil
+}
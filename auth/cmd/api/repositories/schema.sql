@@ -0,0 +1,59 @@
+CREATE TABLE IF NOT EXISTS users (
+    id             TEXT PRIMARY KEY,
+    name           TEXT        NOT NULL,
+    email          TEXT        NOT NULL,
+    birthday       TEXT        NOT NULL DEFAULT '',
+    timezone       TEXT        NOT NULL DEFAULT 'UTC',
+    password_hash  TEXT        NOT NULL DEFAULT '',
+    email_verified BOOLEAN     NOT NULL DEFAULT FALSE,
+    totp_enabled   BOOLEAN     NOT NULL DEFAULT FALSE,
+    totp_secret    TEXT        NOT NULL DEFAULT '',
+    totp_pending_secret       TEXT   NOT NULL DEFAULT '',
+    totp_last_used_step       BIGINT NOT NULL DEFAULT 0,
+    totp_recovery_code_hashes TEXT[] NOT NULL DEFAULT '{}',
+    created_at     TIMESTAMPTZ NOT NULL,
+    updated_at     TIMESTAMPTZ NOT NULL
+);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC';
+
+-- emails are unique regardless of case, like in the memory repository
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_idx ON users (lower(email));
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id                 TEXT PRIMARY KEY,
+    user_id            TEXT        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    refresh_token_hash TEXT        NOT NULL UNIQUE,
+    expires_at         TIMESTAMPTZ NOT NULL,
+    created_at         TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS sessions_user_id_idx ON sessions (user_id);
+
+-- verification, password reset and sign-in challenge tokens share the table, kind tells them apart
+CREATE TABLE IF NOT EXISTS one_time_tokens (
+    hash       TEXT PRIMARY KEY,
+    kind       TEXT        NOT NULL,
+    user_id    TEXT        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    attempts   INTEGER     NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS one_time_tokens_user_id_idx ON one_time_tokens (user_id, kind);
+
+CREATE TABLE IF NOT EXISTS identities (
+    provider   TEXT        NOT NULL,
+    subject    TEXT        NOT NULL,
+    user_id    TEXT        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    email      TEXT        NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (provider, subject)
+);
+
+CREATE TABLE IF NOT EXISTS oauth_states (
+    state_hash    TEXT PRIMARY KEY,
+    provider      TEXT        NOT NULL,
+    nonce         TEXT        NOT NULL,
+    code_verifier TEXT        NOT NULL,
+    expires_at    TIMESTAMPTZ NOT NULL
+);
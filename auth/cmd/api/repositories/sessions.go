@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/models"
+	"sync"
+)
+
+type SessionRepository interface {
+	Save(ctx context.Context, session models.Session) error
+	// ConsumeByRefreshToken removes the session owning the refresh token and returns it, so refresh tokens rotate
+	ConsumeByRefreshToken(ctx context.Context, refreshTokenHash string) (models.Session, error)
+	DeleteByUser(ctx context.Context, userID string) error
+}
+
+type memorySessionRepository struct {
+	mu       sync.Mutex
+	sessions map[string]models.Session
+}
+
+// NewMemorySessionRepository creates a SessionRepository keeping sessions in memory
+func NewMemorySessionRepository() SessionRepository {
+	return &memorySessionRepository{sessions: make(map[string]models.Session)}
+}
+
+func (r *memorySessionRepository) Save(ctx context.Context, session models.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.sessions[session.ID]; ok {
+		return ErrAlreadyExists
+	}
+	r.sessions[session.ID] = session
+	return nil
+}
+
+func (r *memorySessionRepository) ConsumeByRefreshToken(ctx context.Context, refreshTokenHash string) (models.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, session := range r.sessions {
+		if session.RefreshTokenHash == refreshTokenHash {
+			delete(r.sessions, id)
+			return session, nil
+		}
+	}
+	return models.Session{}, ErrNotFound
+}
+
+func (r *memorySessionRepository) DeleteByUser(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, session := range r.sessions {
+		if session.UserID == userID {
+			delete(r.sessions, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/models"
+	"strings"
+	"sync"
+)
+
+type UserRepository interface {
+	Create(ctx context.Context, user models.User) error
+	Update(ctx context.Context, user models.User) error
+	Delete(ctx context.Context, id string) error
+	GetByID(ctx context.Context, id string) (models.User, error)
+	GetByEmail(ctx context.Context, email string) (models.User, error)
+}
+
+type memoryUserRepository struct {
+	mu    sync.RWMutex
+	users map[string]models.User
+	// emails maps normalized emails to user ids
+	emails map[string]string
+}
+
+// NewMemoryUserRepository creates a UserRepository keeping users in memory
+func NewMemoryUserRepository() UserRepository {
+	return &memoryUserRepository{
+		users:  make(map[string]models.User),
+		emails: make(map[string]string),
+	}
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func (r *memoryUserRepository) Create(ctx context.Context, user models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	email := normalizeEmail(user.Email)
+	if _, ok := r.emails[email]; ok {
+		return ErrAlreadyExists
+	}
+	if _, ok := r.users[user.ID]; ok {
+		return ErrAlreadyExists
+	}
+	r.users[user.ID] = user
+	r.emails[email] = user.ID
+	return nil
+}
+
+func (r *memoryUserRepository) Update(ctx context.Context, user models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.users[user.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	oldEmail, newEmail := normalizeEmail(existing.Email), normalizeEmail(user.Email)
+	if oldEmail != newEmail {
+		if _, taken := r.emails[newEmail]; taken {
+			return ErrAlreadyExists
+		}
+		delete(r.emails, oldEmail)
+		r.emails[newEmail] = user.ID
+	}
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *memoryUserRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(r.emails, normalizeEmail(user.Email))
+	delete(r.users, id)
+	return nil
+}
+
+func (r *memoryUserRepository) GetByID(ctx context.Context, id string) (models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	user, ok := r.users[id]
+	if !ok {
+		return models.User{}, ErrNotFound
+	}
+	return user, nil
+}
+
+func (r *memoryUserRepository) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	id, ok := r.emails[normalizeEmail(email)]
+	if !ok {
+		return models.User{}, ErrNotFound
+	}
+	return r.users[id], nil
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/config"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/oauth"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/repositories"
+	auth "github.com/Salladin95/card-quizzler-microservices/auth-service/proto"
+	"github.com/go-playground/validator/v10"
+	"sync"
+)
+
+type AuthServer struct {
+	auth.UnimplementedAuthServer
+	config             config.AppCfg
+	users              repositories.UserRepository
+	sessions           repositories.SessionRepository
+	verificationTokens repositories.OneTimeTokenRepository
+	resetTokens        repositories.OneTimeTokenRepository
+	signInChallenges   repositories.OneTimeTokenRepository
+	identities         repositories.IdentityRepository
+	oauthStates        repositories.OAuthStateRepository
+	providers          oauth.Providers
+	publisher          events.Publisher
+	validate           *validator.Validate
+	// background tracks work finishing after the response was sent
+	background sync.WaitGroup
+}
+
+type Repositories struct {
+	Users              repositories.UserRepository
+	Sessions           repositories.SessionRepository
+	VerificationTokens repositories.OneTimeTokenRepository
+	ResetTokens        repositories.OneTimeTokenRepository
+	SignInChallenges   repositories.OneTimeTokenRepository
+	Identities         repositories.IdentityRepository
+	OAuthStates        repositories.OAuthStateRepository
+}
+
+func NewAuthServer(cfg config.AppCfg, repos Repositories, publisher events.Publisher, providers oauth.Providers) *AuthServer {
+	return &AuthServer{
+		config:             cfg,
+		users:              repos.Users,
+		sessions:           repos.Sessions,
+		verificationTokens: repos.VerificationTokens,
+		resetTokens:        repos.ResetTokens,
+		signInChallenges:   repos.SignInChallenges,
+		identities:         repos.Identities,
+		oauthStates:        repos.OAuthStates,
+		providers:          providers,
+		publisher:          publisher,
+		validate:           validator.New(),
+	}
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/lib"
+	auth "github.com/Salladin95/card-quizzler-microservices/auth-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (as *AuthServer) ChangePassword(ctx context.Context, req *auth.ChangePasswordRequest) (*auth.ChangePasswordResponse, error) {
+	payload := req.GetPayload()
+	dto := changePasswordDto{
+		UserID:          payload.GetUserId(),
+		CurrentPassword: payload.GetCurrentPassword(),
+		NewPassword:     payload.GetNewPassword(),
+	}
+	if err := as.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	user, err := as.getUser(ctx, dto.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !lib.CompareHashAndPassword(user.PasswordHash, dto.CurrentPassword) {
+		return nil, status.Error(codes.InvalidArgument, "current password is incorrect")
+	}
+
+	if err := as.setPassword(ctx, user, dto.NewPassword); err != nil {
+		return nil, err
+	}
+
+	return &auth.ChangePasswordResponse{Message: "password is changed, sign in again"}, nil
+}
@@ -0,0 +1,74 @@
+package server
+
+type signInDto struct {
+	Email    string `validate:"required,email"`
+	Password string `validate:"min=6,required"`
+}
+
+type signUpDto struct {
+	Name     string `validate:"required,min=1"`
+	Password string `validate:"required,min=6"`
+	Email    string `validate:"required,email"`
+	Birthday string `validate:"required,min=1"`
+	Timezone string `validate:"omitempty,timezone"`
+}
+
+type updateProfileDto struct {
+	UserID   string `validate:"required"`
+	Name     string
+	Birthday string
+	Timezone string `validate:"omitempty,timezone"`
+}
+
+type verifyEmailDto struct {
+	Token string `validate:"required"`
+}
+
+type refreshTokensDto struct {
+	RefreshToken string `validate:"required"`
+}
+
+type requestPasswordResetDto struct {
+	Email string `validate:"required,email"`
+}
+
+type resetPasswordDto struct {
+	Token       string `validate:"required"`
+	NewPassword string `validate:"required,min=6"`
+}
+
+type changePasswordDto struct {
+	UserID          string `validate:"required"`
+	CurrentPassword string `validate:"required"`
+	NewPassword     string `validate:"required,min=6"`
+}
+
+type verifySignInDto struct {
+	ChallengeToken string `validate:"required"`
+	Code           string `validate:"required"`
+}
+
+type enrollTOTPDto struct {
+	UserID string `validate:"required"`
+}
+
+type confirmTOTPDto struct {
+	UserID string `validate:"required"`
+	Code   string `validate:"required,len=6,numeric"`
+}
+
+type disableTOTPDto struct {
+	UserID   string `validate:"required"`
+	Password string `validate:"required"`
+	Code     string `validate:"required"`
+}
+
+type startOAuthDto struct {
+	Provider string `validate:"required"`
+}
+
+type completeOAuthDto struct {
+	Provider string `validate:"required"`
+	Code     string `validate:"required"`
+	State    string `validate:"required"`
+}
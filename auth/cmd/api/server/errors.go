@@ -0,0 +1,39 @@
+package server
+
+import (
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log"
+	"strings"
+)
+
+var (
+	errIncorrectCredentials = status.Error(codes.Unauthenticated, "login or password is incorrect")
+	errEmailNotVerified     = status.Error(codes.FailedPrecondition, "email is not verified")
+	errInvalidToken         = status.Error(codes.InvalidArgument, "token is invalid or expired")
+	errInvalidRefreshToken  = status.Error(codes.Unauthenticated, "refresh token is invalid or expired")
+	errInvalidChallenge     = status.Error(codes.Unauthenticated, "sign-in challenge is invalid or expired, sign in again")
+	errInvalidCode          = status.Error(codes.InvalidArgument, "code is invalid")
+	errUserNotFound         = status.Error(codes.NotFound, "user is not found")
+)
+
+// validationFailure converts validator errors into an InvalidArgument status
+func validationFailure(err error) error {
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	messages := make([]string, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		messages = append(messages, fmt.Sprintf("%s: failed on '%s'", strings.ToLower(fieldErr.Field()), fieldErr.Tag()))
+	}
+	return status.Error(codes.InvalidArgument, strings.Join(messages, "; "))
+}
+
+// operationFailure logs the underlying error and hides it from the caller
+func operationFailure(operation string, err error) error {
+	log.Printf("failed to %s: %s\n", operation, err.Error())
+	return status.Errorf(codes.Internal, "failed to %s", operation)
+}
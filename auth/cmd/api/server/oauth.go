@@ -0,0 +1,176 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/lib"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/oauth"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/repositories"
+	auth "github.com/Salladin95/card-quizzler-microservices/auth-service/proto"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log"
+	"time"
+)
+
+var (
+	errUnknownProvider = status.Error(codes.NotFound, "identity provider is not supported")
+	errInvalidState    = status.Error(codes.InvalidArgument, "sign-in request is invalid or expired, try again")
+	errProviderFailure = status.Error(codes.Unauthenticated, "failed to sign in with the identity provider")
+)
+
+func (as *AuthServer) StartOAuth(ctx context.Context, req *auth.StartOAuthRequest) (*auth.StartOAuthResponse, error) {
+	dto := startOAuthDto{Provider: req.GetPayload().GetProvider()}
+	if err := as.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	provider, err := as.providers.Get(dto.Provider)
+	if err != nil {
+		return nil, errUnknownProvider
+	}
+
+	state, err := oauth.GenerateVerifier()
+	if err != nil {
+		return nil, operationFailure("generate state", err)
+	}
+	nonce, err := oauth.GenerateVerifier()
+	if err != nil {
+		return nil, operationFailure("generate nonce", err)
+	}
+	codeVerifier, err := oauth.GenerateVerifier()
+	if err != nil {
+		return nil, operationFailure("generate code verifier", err)
+	}
+
+	err = as.oauthStates.Save(ctx, models.OAuthState{
+		StateHash:    lib.HashOpaqueToken(state),
+		Provider:     dto.Provider,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    time.Now().Add(as.config.OAUTH_STATE_TTL),
+	})
+	if err != nil {
+		return nil, operationFailure("save oauth state", err)
+	}
+
+	authorizationURL, err := provider.AuthCodeURL(ctx, state, nonce, oauth.S256Challenge(codeVerifier))
+	if err != nil {
+		log.Printf("start oauth: %s\n", err.Error())
+		return nil, status.Error(codes.Unavailable, "identity provider is unavailable")
+	}
+
+	return &auth.StartOAuthResponse{AuthorizationUrl: authorizationURL}, nil
+}
+
+func (as *AuthServer) CompleteOAuth(ctx context.Context, req *auth.CompleteOAuthRequest) (*auth.SignInResponse, error) {
+	payload := req.GetPayload()
+	dto := completeOAuthDto{Provider: payload.GetProvider(), Code: payload.GetCode(), State: payload.GetState()}
+	if err := as.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	provider, err := as.providers.Get(dto.Provider)
+	if err != nil {
+		return nil, errUnknownProvider
+	}
+
+	// the state proves the callback answers a request started by us, consuming it prevents replays
+	state, err := as.oauthStates.Consume(ctx, lib.HashOpaqueToken(dto.State))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, errInvalidState
+		}
+		return nil, operationFailure("consume oauth state", err)
+	}
+	if state.Expired(time.Now()) || state.Provider != dto.Provider {
+		return nil, errInvalidState
+	}
+
+	identity, err := provider.Exchange(ctx, dto.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		log.Printf("complete oauth with %s: %s\n", dto.Provider, err.Error())
+		return nil, errProviderFailure
+	}
+
+	user, err := as.resolveOAuthUser(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTP.Enabled {
+		return as.signInChallengeResponse(ctx, user)
+	}
+
+	scope, ok := as.scopeFor(user)
+	if !ok {
+		return nil, errEmailNotVerified
+	}
+	return as.signInResponse(ctx, user, scope)
+}
+
+// resolveOAuthUser finds the user linked to the identity. Identities with a verified email
+// are linked to the account with the same email, otherwise a new account is created.
+func (as *AuthServer) resolveOAuthUser(ctx context.Context, identity oauth.Identity) (models.User, error) {
+	linked, err := as.identities.Get(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		return as.getUser(ctx, linked.UserID)
+	}
+	if !errors.Is(err, repositories.ErrNotFound) {
+		return models.User{}, operationFailure("get identity", err)
+	}
+
+	if identity.Email == "" {
+		return models.User{}, status.Error(codes.FailedPrecondition, "identity provider didn't share an email address")
+	}
+
+	user, err := as.users.GetByEmail(ctx, identity.Email)
+	switch {
+	case err == nil:
+		// linking by an unverified email would let anyone claim someone else's account
+		if !identity.EmailVerified {
+			return models.User{}, status.Error(codes.AlreadyExists, "an account with this email already exists, sign in with the password")
+		}
+		if !user.EmailVerified {
+			user.EmailVerified = true
+			user.UpdatedAt = time.Now()
+			if err := as.users.Update(ctx, user); err != nil {
+				return models.User{}, operationFailure("update user", err)
+			}
+		}
+	case errors.Is(err, repositories.ErrNotFound):
+		now := time.Now()
+		user = models.User{
+			ID:            uuid.NewString(),
+			Name:          identity.Name,
+			Email:         identity.Email,
+			EmailVerified: identity.EmailVerified,
+			Timezone:      models.DefaultTimezone,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		if user.Name == "" {
+			user.Name = identity.Email
+		}
+		// there is no password, RequestPasswordReset can set one later
+		if err := as.users.Create(ctx, user); err != nil {
+			return models.User{}, operationFailure("create user", err)
+		}
+	default:
+		return models.User{}, operationFailure("get user", err)
+	}
+
+	err = as.identities.Create(ctx, models.Identity{
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		UserID:    user.ID,
+		Email:     identity.Email,
+		CreatedAt: time.Now(),
+	})
+	if err != nil && !errors.Is(err, repositories.ErrAlreadyExists) {
+		return models.User{}, operationFailure("link identity", err)
+	}
+	return user, nil
+}
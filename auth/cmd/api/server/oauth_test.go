@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/lib"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/oauth"
+	auth "github.com/Salladin95/card-quizzler-microservices/auth-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// fakeProvider checks the values the server sends against the ones of the authorization request
+type fakeProvider struct {
+	identity  oauth.Identity
+	nonce     string
+	challenge string
+}
+
+func (p *fakeProvider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	p.nonce, p.challenge = nonce, codeChallenge
+	return "https://idp.example.com/authorize?" + url.Values{"state": {state}}.Encode(), nil
+}
+
+func (p *fakeProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (oauth.Identity, error) {
+	if oauth.S256Challenge(codeVerifier) != p.challenge {
+		return oauth.Identity{}, errors.New("invalid_grant - PKCE verification failed")
+	}
+	if nonce != p.nonce {
+		return oauth.Identity{}, oauth.ErrInvalidIDToken
+	}
+	return p.identity, nil
+}
+
+func startOAuth(t *testing.T, as *AuthServer, provider string) string {
+	t.Helper()
+	res, err := as.StartOAuth(context.Background(), &auth.StartOAuthRequest{Payload: &auth.StartOAuthPayload{Provider: provider}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := url.Parse(res.GetAuthorizationUrl())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed.Query().Get("state")
+}
+
+func completeOAuth(as *AuthServer, provider, state string) (*auth.SignInResponse, error) {
+	return as.CompleteOAuth(context.Background(), &auth.CompleteOAuthRequest{
+		Payload: &auth.CompleteOAuthPayload{Provider: provider, Code: "code", State: state},
+	})
+}
+
+func TestCompleteOAuth(t *testing.T) {
+	provider := &fakeProvider{identity: oauth.Identity{Provider: "google", Subject: "42", Email: "ann@example.com", EmailVerified: true, Name: "Ann"}}
+	as := newTestServer(t, oauth.Providers{"google": provider})
+
+	res, err := completeOAuth(as, "google", startOAuth(t, as, "google"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.GetAccessToken() == "" || res.GetRefreshToken() == "" {
+		t.Errorf("response %+v, want tokens", res)
+	}
+	user, err := as.users.GetByEmail(context.Background(), "ann@example.com")
+	if err != nil || !user.EmailVerified || user.Name != "Ann" {
+		t.Errorf("created user %+v, %v", user, err)
+	}
+	if identity, err := as.identities.Get(context.Background(), "google", "42"); err != nil || identity.UserID != user.ID {
+		t.Errorf("linked identity %+v, %v", identity, err)
+	}
+}
+
+func TestCompleteOAuthRejectsInvalidState(t *testing.T) {
+	provider := &fakeProvider{identity: oauth.Identity{Provider: "google", Subject: "42", Email: "ann@example.com", EmailVerified: true}}
+	other := &fakeProvider{identity: oauth.Identity{Provider: "github", Subject: "42", Email: "ann@example.com", EmailVerified: true}}
+	as := newTestServer(t, oauth.Providers{"google": provider, "github": other})
+
+	state := startOAuth(t, as, "google")
+	if _, err := completeOAuth(as, "google", "forged-state"); err != errInvalidState {
+		t.Errorf("unknown state returned %v, want errInvalidState", err)
+	}
+	// the state is consumed by the first callback, whatever its outcome
+	if _, err := completeOAuth(as, "github", state); err != errInvalidState {
+		t.Errorf("state of another provider returned %v, want errInvalidState", err)
+	}
+	if _, err := completeOAuth(as, "google", state); err != errInvalidState {
+		t.Errorf("replayed state returned %v, want errInvalidState", err)
+	}
+
+	expired := startOAuth(t, as, "google")
+	stored, err := as.oauthStates.Consume(context.Background(), lib.HashOpaqueToken(expired))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored.ExpiresAt = time.Now().Add(-time.Second)
+	if err := as.oauthStates.Save(context.Background(), stored); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := completeOAuth(as, "google", expired); err != errInvalidState {
+		t.Errorf("expired state returned %v, want errInvalidState", err)
+	}
+}
+
+func TestCompleteOAuthPassesVerifierAndNonce(t *testing.T) {
+	provider := &fakeProvider{identity: oauth.Identity{Provider: "google", Subject: "42", Email: "ann@example.com", EmailVerified: true}}
+	as := newTestServer(t, oauth.Providers{"google": provider})
+
+	first := startOAuth(t, as, "google")
+	// the provider now expects the verifier and nonce of the second request
+	startOAuth(t, as, "google")
+	if _, err := completeOAuth(as, "google", first); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("callback of a superseded request returned %v, want a provider failure", err)
+	}
+}
+
+func TestCompleteOAuthDoesNotLinkUnverifiedEmail(t *testing.T) {
+	provider := &fakeProvider{identity: oauth.Identity{Provider: "github", Subject: "583231", Email: "ann@example.com", EmailVerified: false}}
+	as := newTestServer(t, oauth.Providers{"github": provider})
+	owner := models.User{ID: "owner", Name: "Ann", Email: "ann@example.com", EmailVerified: true}
+	if err := as.users.Create(context.Background(), owner); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := completeOAuth(as, "github", startOAuth(t, as, "github"))
+	if status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("unverified email of an existing account returned %v, want AlreadyExists", err)
+	}
+	if _, err := as.identities.Get(context.Background(), "github", "583231"); err == nil {
+		t.Error("identity with an unverified email was linked to the existing account")
+	}
+}
+
+func TestCompleteOAuthUnverifiedNewUser(t *testing.T) {
+	provider := &fakeProvider{identity: oauth.Identity{Provider: "github", Subject: "583231", Email: "octo@example.com", EmailVerified: false}}
+	as := newTestServer(t, oauth.Providers{"github": provider})
+
+	// the account is created but unverified users can't sign in with UNVERIFIED_SIGN_IN=reject
+	if _, err := completeOAuth(as, "github", startOAuth(t, as, "github")); err != errEmailNotVerified {
+		t.Errorf("unverified new user returned %v, want errEmailNotVerified", err)
+	}
+	user, err := as.users.GetByEmail(context.Background(), "octo@example.com")
+	if err != nil || user.EmailVerified || user.Name != "octo@example.com" {
+		t.Errorf("created user %+v, %v", user, err)
+	}
+}
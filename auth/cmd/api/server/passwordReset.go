@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/lib"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/repositories"
+	auth "github.com/Salladin95/card-quizzler-microservices/auth-service/proto"
+	"log"
+	"time"
+)
+
+// passwordResetRequestedMessage is returned whether the account exists or not, so the api can't be used to probe emails
+const passwordResetRequestedMessage = "if an account with this email exists, a password reset link has been sent to it"
+
+// backgroundTimeout bounds work left running after the response, like issuing a password reset
+const backgroundTimeout = 30 * time.Second
+
+// RequestPasswordReset answers before looking the email up and issues the reset in the background,
+// so neither the response nor its timing tells whether the account exists
+func (as *AuthServer) RequestPasswordReset(ctx context.Context, req *auth.RequestPasswordResetRequest) (*auth.RequestPasswordResetResponse, error) {
+	dto := requestPasswordResetDto{Email: req.GetPayload().GetEmail()}
+	if err := as.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	as.background.Add(1)
+	go func() {
+		defer as.background.Done()
+		// the request context ends with the response
+		ctx, cancel := context.WithTimeout(context.Background(), backgroundTimeout)
+		defer cancel()
+		as.issuePasswordReset(ctx, dto.Email)
+	}()
+
+	return &auth.RequestPasswordResetResponse{Message: passwordResetRequestedMessage}, nil
+}
+
+// issuePasswordReset sends a reset link to the account with the email, if there is one.
+// Failures are only logged, nobody waits for the result.
+func (as *AuthServer) issuePasswordReset(ctx context.Context, email string) {
+	user, err := as.users.GetByEmail(ctx, email)
+	if err != nil {
+		if !errors.Is(err, repositories.ErrNotFound) {
+			log.Printf("request password reset: failed to get user - %s\n", err.Error())
+		}
+		return
+	}
+
+	token, expiresAt, err := issueOneTimeToken(ctx, as.resetTokens, user.ID, as.config.PASSWORD_RESET_TOKEN_TTL)
+	if err != nil {
+		log.Printf("request password reset: failed to issue reset token - %s\n", err.Error())
+		return
+	}
+	err = as.publisher.Publish(ctx, events.PasswordResetRequestedKey, events.PasswordResetRequested{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Name:      user.Name,
+		Token:     token,
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		log.Printf("request password reset: failed to publish event - %s\n", err.Error())
+	}
+}
+
+func (as *AuthServer) ResetPassword(ctx context.Context, req *auth.ResetPasswordRequest) (*auth.ResetPasswordResponse, error) {
+	payload := req.GetPayload()
+	dto := resetPasswordDto{Token: payload.GetToken(), NewPassword: payload.GetNewPassword()}
+	if err := as.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	token, err := as.resetTokens.Consume(ctx, lib.HashOpaqueToken(dto.Token))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, errInvalidToken
+		}
+		return nil, operationFailure("consume reset token", err)
+	}
+	if token.Expired(time.Now()) {
+		return nil, errInvalidToken
+	}
+
+	user, err := as.users.GetByID(ctx, token.UserID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, errInvalidToken
+		}
+		return nil, operationFailure("get user", err)
+	}
+
+	// the reset link was delivered to the inbox, which proves the user owns the email
+	user.EmailVerified = true
+	if err := as.setPassword(ctx, user, dto.NewPassword); err != nil {
+		return nil, err
+	}
+	if err := as.resetTokens.DeleteByUser(ctx, user.ID); err != nil {
+		return nil, operationFailure("delete reset tokens", err)
+	}
+
+	return &auth.ResetPasswordResponse{Message: "password is reset, sign in with the new password"}, nil
+}
+
+// setPassword stores the new password of the user and signs them out everywhere
+func (as *AuthServer) setPassword(ctx context.Context, user models.User, password string) error {
+	passwordHash, err := lib.HashPassword(password)
+	if err != nil {
+		return operationFailure("hash password", err)
+	}
+	user.PasswordHash = passwordHash
+	user.UpdatedAt = time.Now()
+	if err := as.users.Update(ctx, user); err != nil {
+		return operationFailure("update user", err)
+	}
+	if err := as.revokeSessions(ctx, user.ID); err != nil {
+		return operationFailure("revoke sessions", err)
+	}
+	return nil
+}
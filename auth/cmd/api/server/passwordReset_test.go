@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/lib"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/models"
+	auth "github.com/Salladin95/card-quizzler-microservices/auth-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+// requestPasswordReset requests a reset and returns the token mailed to the user, empty when none was sent
+func requestPasswordReset(t *testing.T, as *AuthServer, email string) string {
+	t.Helper()
+	publisher := as.publisher.(*recordingPublisher)
+	publisher.mu.Lock()
+	sent := len(publisher.payloads)
+	publisher.mu.Unlock()
+
+	res, err := as.RequestPasswordReset(context.Background(), &auth.RequestPasswordResetRequest{
+		Payload: &auth.RequestPasswordResetPayload{Email: email},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.GetMessage() != passwordResetRequestedMessage {
+		t.Errorf("response to %s: %q", email, res.GetMessage())
+	}
+	as.background.Wait()
+
+	publisher.mu.Lock()
+	defer publisher.mu.Unlock()
+	for i := sent; i < len(publisher.payloads); i++ {
+		if publisher.events[i] == events.PasswordResetRequestedKey {
+			return publisher.payloads[i].(events.PasswordResetRequested).Token
+		}
+	}
+	return ""
+}
+
+func resetPassword(as *AuthServer, token, password string) error {
+	_, err := as.ResetPassword(context.Background(), &auth.ResetPasswordRequest{
+		Payload: &auth.ResetPasswordPayload{Token: token, NewPassword: password},
+	})
+	return err
+}
+
+func refreshTokens(as *AuthServer, refreshToken string) (*auth.RefreshTokensResponse, error) {
+	return as.RefreshTokens(context.Background(), &auth.RefreshTokensRequest{
+		Payload: &auth.RefreshTokensPayload{RefreshToken: refreshToken},
+	})
+}
+
+// signedIn signs in on a new device and returns its refresh token
+func signedIn(t *testing.T, as *AuthServer, email, password string) string {
+	t.Helper()
+	res, err := signIn(as, email, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.GetRefreshToken()
+}
+
+func TestPasswordResetTokensWorkOnce(t *testing.T) {
+	as := newTestServer(t, nil)
+	seedUser(t, as, "ann", "ann@example.com", "correct horse")
+	devices := []string{
+		signedIn(t, as, "ann@example.com", "correct horse"),
+		signedIn(t, as, "ann@example.com", "correct horse"),
+	}
+
+	// unknown emails get the same response and nothing is sent
+	if token := requestPasswordReset(t, as, "nobody@example.com"); token != "" {
+		t.Fatal("a reset was sent for an unknown email")
+	}
+	token := requestPasswordReset(t, as, "ann@example.com")
+	if token == "" {
+		t.Fatal("no reset was sent")
+	}
+	if err := resetPassword(as, token, "battery staple"); err != nil {
+		t.Fatal(err)
+	}
+	if err := resetPassword(as, token, "another staple"); err != errInvalidToken {
+		t.Errorf("reused token returned %v, want errInvalidToken", err)
+	}
+
+	// every device is signed out, only the new password works
+	for i, refreshToken := range devices {
+		if _, err := refreshTokens(as, refreshToken); err != errInvalidRefreshToken {
+			t.Errorf("device %d refreshed after the reset: %v", i, err)
+		}
+	}
+	if _, err := signIn(as, "ann@example.com", "correct horse"); err != errIncorrectCredentials {
+		t.Errorf("old password returned %v", err)
+	}
+	signedIn(t, as, "ann@example.com", "battery staple")
+}
+
+func TestPasswordResetRevokesOtherTokens(t *testing.T) {
+	as := newTestServer(t, nil)
+	seedUser(t, as, "ann", "ann@example.com", "correct horse")
+	first := requestPasswordReset(t, as, "ann@example.com")
+	second := requestPasswordReset(t, as, "ann@example.com")
+
+	if err := resetPassword(as, second, "battery staple"); err != nil {
+		t.Fatal(err)
+	}
+	if err := resetPassword(as, first, "another staple"); err != errInvalidToken {
+		t.Errorf("older token returned %v, want errInvalidToken", err)
+	}
+}
+
+func TestExpiredPasswordResetToken(t *testing.T) {
+	as := newTestServer(t, nil)
+	seedUser(t, as, "ann", "ann@example.com", "correct horse")
+	token, hash, err := lib.GenerateOpaqueToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired := models.OneTimeToken{Hash: hash, UserID: "ann", ExpiresAt: time.Now().Add(-time.Second)}
+	if err := as.resetTokens.Save(context.Background(), expired); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := resetPassword(as, token, "battery staple"); err != errInvalidToken {
+		t.Errorf("expired token returned %v, want errInvalidToken", err)
+	}
+	signedIn(t, as, "ann@example.com", "correct horse")
+}
+
+func TestChangePasswordSignsOutEverywhere(t *testing.T) {
+	as := newTestServer(t, nil)
+	ctx := context.Background()
+	seedUser(t, as, "ann", "ann@example.com", "correct horse")
+	refreshToken := signedIn(t, as, "ann@example.com", "correct horse")
+
+	_, err := as.ChangePassword(ctx, &auth.ChangePasswordRequest{Payload: &auth.ChangePasswordPayload{
+		UserId: "ann", CurrentPassword: "wrong horse", NewPassword: "battery staple",
+	}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("wrong current password returned %v", err)
+	}
+	_, err = as.ChangePassword(ctx, &auth.ChangePasswordRequest{Payload: &auth.ChangePasswordPayload{
+		UserId: "ann", CurrentPassword: "correct horse", NewPassword: "battery staple",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := refreshTokens(as, refreshToken); err != errInvalidRefreshToken {
+		t.Errorf("session survived the password change: %v", err)
+	}
+	signedIn(t, as, "ann@example.com", "battery staple")
+}
+
+func TestRefreshTokensRotate(t *testing.T) {
+	as := newTestServer(t, nil)
+	seedUser(t, as, "ann", "ann@example.com", "correct horse")
+	first := signedIn(t, as, "ann@example.com", "correct horse")
+
+	res, err := refreshTokens(as, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := res.GetRefreshToken()
+	if second == "" || second == first || res.GetAccessToken() == "" {
+		t.Fatalf("refresh returned %+v", res)
+	}
+	// a refresh token works once, the rotated one keeps working
+	if _, err := refreshTokens(as, first); err != errInvalidRefreshToken {
+		t.Errorf("reused refresh token returned %v, want errInvalidRefreshToken", err)
+	}
+	if _, err := refreshTokens(as, second); err != nil {
+		t.Errorf("rotated refresh token returned %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/models"
+	auth "github.com/Salladin95/card-quizzler-microservices/auth-service/proto"
+	"time"
+)
+
+func (as *AuthServer) GetProfile(ctx context.Context, req *auth.GetProfileRequest) (*auth.ProfileResponse, error) {
+	user, err := as.getUser(ctx, req.GetPayload().GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	return &auth.ProfileResponse{Profile: toProtoProfile(user)}, nil
+}
+
+// UpdateProfile changes the name, birthday and timezone of the user, the cards service learns the timezone from an event
+func (as *AuthServer) UpdateProfile(ctx context.Context, req *auth.UpdateProfileRequest) (*auth.ProfileResponse, error) {
+	payload := req.GetPayload()
+	dto := updateProfileDto{
+		UserID:   payload.GetUserId(),
+		Name:     payload.GetName(),
+		Birthday: payload.GetBirthday(),
+		Timezone: payload.GetTimezone(),
+	}
+	if err := as.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	user, err := as.getUser(ctx, dto.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if dto.Name != "" {
+		user.Name = dto.Name
+	}
+	if dto.Birthday != "" {
+		user.Birthday = dto.Birthday
+	}
+	if dto.Timezone != "" {
+		user.Timezone = dto.Timezone
+	}
+	user.UpdatedAt = time.Now()
+	if err := as.users.Update(ctx, user); err != nil {
+		return nil, operationFailure("update user", err)
+	}
+	if err := as.publishProfile(ctx, user); err != nil {
+		return nil, operationFailure("publish profile event", err)
+	}
+
+	return &auth.ProfileResponse{Profile: toProtoProfile(user)}, nil
+}
+
+func (as *AuthServer) publishProfile(ctx context.Context, user models.User) error {
+	return as.publisher.Publish(ctx, events.ProfileUpdatedKey, events.ProfileUpdated{
+		UserID:    user.ID,
+		Name:      user.Name,
+		Timezone:  user.Timezone,
+		UpdatedAt: user.UpdatedAt.UnixMilli(),
+	})
+}
+
+func toProtoProfile(user models.User) *auth.Profile {
+	timezone := user.Timezone
+	if timezone == "" {
+		timezone = models.DefaultTimezone
+	}
+	return &auth.Profile{
+		Id:               user.ID,
+		Name:             user.Name,
+		Email:            user.Email,
+		Birthday:         user.Birthday,
+		Timezone:         timezone,
+		EmailVerified:    user.EmailVerified,
+		TwoFactorEnabled: user.TOTP.Enabled,
+		CreatedAt:        user.CreatedAt.Unix(),
+	}
+}
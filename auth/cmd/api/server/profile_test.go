@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/events"
+	auth "github.com/Salladin95/card-quizzler-microservices/auth-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"slices"
+	"testing"
+)
+
+func TestProfileTimezone(t *testing.T) {
+	as := newTestServer(t, nil)
+	publisher := as.publisher.(*recordingPublisher)
+	ctx := context.Background()
+	signUp, err := as.SignUp(ctx, &auth.SignUpRequest{Payload: &auth.SignUpPayload{
+		Email: "ann@example.com", Password: "correct horse", Name: "Ann", Birthday: "1990-01-01",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile, err := as.GetProfile(ctx, &auth.GetProfileRequest{Payload: &auth.GetProfilePayload{UserId: signUp.GetUserId()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.GetProfile().GetTimezone() != "UTC" {
+		t.Errorf("timezone of a new user is %q", profile.GetProfile().GetTimezone())
+	}
+
+	update := func(timezone string) (*auth.ProfileResponse, error) {
+		return as.UpdateProfile(ctx, &auth.UpdateProfileRequest{Payload: &auth.UpdateProfilePayload{
+			UserId: signUp.GetUserId(), Timezone: timezone,
+		}})
+	}
+	for _, timezone := range []string{"Mars/Olympus", "Local"} {
+		if _, err := update(timezone); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("setting timezone %q returned %v", timezone, err)
+		}
+	}
+	updated, err := update("Asia/Kathmandu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.GetProfile().GetTimezone() != "Asia/Kathmandu" || updated.GetProfile().GetName() != "Ann" {
+		t.Errorf("updated profile %v", updated.GetProfile())
+	}
+	// the sign-up and the update announce the profile
+	published := 0
+	for _, key := range publisher.events {
+		if key == events.ProfileUpdatedKey {
+			published++
+		}
+	}
+	if published != 2 || !slices.Contains(publisher.events, events.EmailVerificationRequestedKey) {
+		t.Errorf("published %v", publisher.events)
+	}
+}
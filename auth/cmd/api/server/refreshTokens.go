@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/lib"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/repositories"
+	auth "github.com/Salladin95/card-quizzler-microservices/auth-service/proto"
+	"time"
+)
+
+func (as *AuthServer) RefreshTokens(ctx context.Context, req *auth.RefreshTokensRequest) (*auth.RefreshTokensResponse, error) {
+	dto := refreshTokensDto{RefreshToken: req.GetPayload().GetRefreshToken()}
+	if err := as.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	// the old session is consumed, so every refresh token works exactly once
+	session, err := as.sessions.ConsumeByRefreshToken(ctx, lib.HashOpaqueToken(dto.RefreshToken))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, errInvalidRefreshToken
+		}
+		return nil, operationFailure("consume session", err)
+	}
+	if session.Expired(time.Now()) {
+		return nil, errInvalidRefreshToken
+	}
+
+	user, err := as.users.GetByID(ctx, session.UserID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, errInvalidRefreshToken
+		}
+		return nil, operationFailure("get user", err)
+	}
+
+	scope, ok := as.scopeFor(user)
+	if !ok {
+		return nil, errEmailNotVerified
+	}
+
+	tokens, err := as.issueTokens(ctx, user, scope)
+	if err != nil {
+		return nil, operationFailure("issue tokens", err)
+	}
+
+	return &auth.RefreshTokensResponse{
+		AccessToken:           tokens.accessToken,
+		AccessTokenExpiresAt:  tokens.accessTokenExpiresAt.Unix(),
+		Scope:                 tokens.scope,
+		RefreshToken:          tokens.refreshToken,
+		RefreshTokenExpiresAt: tokens.refreshTokenExpiresAt.Unix(),
+	}, nil
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/config"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/lib"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/oauth"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/repositories"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingPublisher keeps published events instead of sending them to the broker
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []string
+	// payloads holds the payload of every event in events
+	payloads []any
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, key string, payload any) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, key)
+	p.payloads = append(p.payloads, payload)
+	return nil
+}
+
+func testConfig() config.AppCfg {
+	return config.AppCfg{
+		GRPC_PORT:                "8090",
+		RABBIT_URL:               "amqp://localhost:5672",
+		JWT_SECRET:               "test-secret",
+		ACCESS_TOKEN_TTL:         15 * time.Minute,
+		REFRESH_TOKEN_TTL:        24 * time.Hour,
+		VERIFICATION_TOKEN_TTL:   24 * time.Hour,
+		PASSWORD_RESET_TOKEN_TTL: time.Hour,
+		UNVERIFIED_SIGN_IN:       config.UnverifiedSignInReject,
+		TOTP_ISSUER:              "Quizzler",
+		SIGN_IN_CHALLENGE_TTL:    5 * time.Minute,
+		OAUTH_STATE_TTL:          10 * time.Minute,
+	}
+}
+
+func newTestServer(t *testing.T, providers oauth.Providers) *AuthServer {
+	t.Helper()
+	repos := Repositories{
+		Users:              repositories.NewMemoryUserRepository(),
+		Sessions:           repositories.NewMemorySessionRepository(),
+		VerificationTokens: repositories.NewMemoryOneTimeTokenRepository(),
+		ResetTokens:        repositories.NewMemoryOneTimeTokenRepository(),
+		SignInChallenges:   repositories.NewMemoryOneTimeTokenRepository(),
+		Identities:         repositories.NewMemoryIdentityRepository(),
+		OAuthStates:        repositories.NewMemoryOAuthStateRepository(),
+	}
+	return NewAuthServer(testConfig(), repos, &recordingPublisher{}, providers)
+}
+
+// seedUser stores a verified user signing in with the password
+func seedUser(t *testing.T, as *AuthServer, id, email, password string) models.User {
+	t.Helper()
+	hash, err := lib.HashPassword(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	user := models.User{ID: id, Name: id, Email: email, PasswordHash: hash, EmailVerified: true, CreatedAt: now, UpdatedAt: now}
+	if err := as.users.Create(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
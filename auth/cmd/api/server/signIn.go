@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/lib"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/repositories"
+	auth "github.com/Salladin95/card-quizzler-microservices/auth-service/proto"
+)
+
+func (as *AuthServer) SignIn(ctx context.Context, req *auth.SignInRequest) (*auth.SignInResponse, error) {
+	payload := req.GetPayload()
+	dto := signInDto{Email: payload.GetEmail(), Password: payload.GetPassword()}
+	if err := as.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	user, err := as.users.GetByEmail(ctx, dto.Email)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			lib.CompareDummyPassword(dto.Password)
+			return nil, errIncorrectCredentials
+		}
+		return nil, operationFailure("get user", err)
+	}
+	if !lib.CompareHashAndPassword(user.PasswordHash, dto.Password) {
+		return nil, errIncorrectCredentials
+	}
+
+	scope, ok := as.scopeFor(user)
+	if !ok {
+		return nil, errEmailNotVerified
+	}
+
+	if user.TOTP.Enabled {
+		return as.signInChallengeResponse(ctx, user)
+	}
+
+	return as.signInResponse(ctx, user, scope)
+}
+
+// signInChallengeResponse asks users with two-factor authentication for a code before issuing tokens
+func (as *AuthServer) signInChallengeResponse(ctx context.Context, user models.User) (*auth.SignInResponse, error) {
+	challenge, expiresAt, err := issueOneTimeToken(ctx, as.signInChallenges, user.ID, as.config.SIGN_IN_CHALLENGE_TTL)
+	if err != nil {
+		return nil, operationFailure("issue sign-in challenge", err)
+	}
+	return &auth.SignInResponse{
+		Message:            "enter the code from your authenticator app",
+		TwoFactorRequired:  true,
+		ChallengeToken:     challenge,
+		ChallengeExpiresAt: expiresAt.Unix(),
+	}, nil
+}
+
+// signInResponse starts a session for the user who passed every sign-in step
+func (as *AuthServer) signInResponse(ctx context.Context, user models.User, scope string) (*auth.SignInResponse, error) {
+	tokens, err := as.issueTokens(ctx, user, scope)
+	if err != nil {
+		return nil, operationFailure("issue tokens", err)
+	}
+
+	return &auth.SignInResponse{
+		Message:               "signed in",
+		AccessToken:           tokens.accessToken,
+		AccessTokenExpiresAt:  tokens.accessTokenExpiresAt.Unix(),
+		Scope:                 tokens.scope,
+		RefreshToken:          tokens.refreshToken,
+		RefreshTokenExpiresAt: tokens.refreshTokenExpiresAt.Unix(),
+	}, nil
+}
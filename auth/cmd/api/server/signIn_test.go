@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/lib"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/models"
+	auth "github.com/Salladin95/card-quizzler-microservices/auth-service/proto"
+	"testing"
+	"time"
+)
+
+func signIn(as *AuthServer, email, password string) (*auth.SignInResponse, error) {
+	return as.SignIn(context.Background(), &auth.SignInRequest{Payload: &auth.SignInPayload{Email: email, Password: password}})
+}
+
+func TestSignInHidesWhichEmailsExist(t *testing.T) {
+	as := newTestServer(t, nil)
+	hash, err := lib.HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	users := []models.User{
+		{ID: "ann", Name: "Ann", Email: "ann@example.com", PasswordHash: hash, EmailVerified: true, CreatedAt: now, UpdatedAt: now},
+		// accounts created by identity providers have no password
+		{ID: "bob", Name: "Bob", Email: "bob@example.com", EmailVerified: true, CreatedAt: now, UpdatedAt: now},
+	}
+	for _, user := range users {
+		if err := as.users.Create(context.Background(), user); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if res, err := signIn(as, "Ann@Example.com", "correct horse"); err != nil || res.GetAccessToken() == "" {
+		t.Fatalf("sign in returned %+v, %v", res, err)
+	}
+	tests := []struct {
+		name     string
+		email    string
+		password string
+	}{
+		{"wrong password", "ann@example.com", "wrong horse"},
+		{"unknown email", "nobody@example.com", "correct horse"},
+		{"account without password", "bob@example.com", "correct horse"},
+	}
+	for _, tt := range tests {
+		if _, err := signIn(as, tt.email, tt.password); err != errIncorrectCredentials {
+			t.Errorf("%s: %v, want errIncorrectCredentials", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/lib"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/repositories"
+	auth "github.com/Salladin95/card-quizzler-microservices/auth-service/proto"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log"
+	"time"
+)
+
+func (as *AuthServer) SignUp(ctx context.Context, req *auth.SignUpRequest) (*auth.SignUpResponse, error) {
+	payload := req.GetPayload()
+	dto := signUpDto{
+		Name:     payload.GetName(),
+		Password: payload.GetPassword(),
+		Email:    payload.GetEmail(),
+		Birthday: payload.GetBirthday(),
+		Timezone: payload.GetTimezone(),
+	}
+	if err := as.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	passwordHash, err := lib.HashPassword(dto.Password)
+	if err != nil {
+		return nil, operationFailure("hash password", err)
+	}
+
+	if dto.Timezone == "" {
+		dto.Timezone = models.DefaultTimezone
+	}
+	now := time.Now()
+	user := models.User{
+		ID:           uuid.NewString(),
+		Name:         dto.Name,
+		Email:        dto.Email,
+		Birthday:     dto.Birthday,
+		Timezone:     dto.Timezone,
+		PasswordHash: passwordHash,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := as.users.Create(ctx, user); err != nil {
+		if errors.Is(err, repositories.ErrAlreadyExists) {
+			return nil, status.Error(codes.AlreadyExists, "user with this email already exists")
+		}
+		return nil, operationFailure("create user", err)
+	}
+
+	if err := as.requestEmailVerification(ctx, user); err != nil {
+		// without a verification token the account can't be activated, so roll the sign-up back
+		_ = as.verificationTokens.DeleteByUser(ctx, user.ID)
+		_ = as.users.Delete(ctx, user.ID)
+		return nil, err
+	}
+	// a lost event leaves study days of the user in UTC until the profile is saved again
+	if err := as.publishProfile(ctx, user); err != nil {
+		log.Printf("failed to publish profile of %s: %s\n", user.ID, err.Error())
+	}
+
+	return &auth.SignUpResponse{
+		Message: "user is created, check your email to verify the account",
+		UserId:  user.ID,
+	}, nil
+}
+
+// requestEmailVerification issues a single-use verification token and asks the mailer to deliver it
+func (as *AuthServer) requestEmailVerification(ctx context.Context, user models.User) error {
+	token, expiresAt, err := issueOneTimeToken(ctx, as.verificationTokens, user.ID, as.config.VERIFICATION_TOKEN_TTL)
+	if err != nil {
+		return operationFailure("issue verification token", err)
+	}
+
+	err = as.publisher.Publish(ctx, events.EmailVerificationRequestedKey, events.EmailVerificationRequested{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Name:      user.Name,
+		Token:     token,
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return operationFailure("publish email verification event", err)
+	}
+	return nil
+}
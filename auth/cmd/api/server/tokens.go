@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/config"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/lib"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/repositories"
+	"github.com/google/uuid"
+	"time"
+)
+
+type tokenPair struct {
+	accessToken           string
+	accessTokenExpiresAt  time.Time
+	refreshToken          string
+	refreshTokenExpiresAt time.Time
+	scope                 string
+}
+
+// issueTokens starts a new session for the user and signs an access token bound to it
+func (as *AuthServer) issueTokens(ctx context.Context, user models.User, scope string) (tokenPair, error) {
+	refreshToken, refreshTokenHash, err := lib.GenerateOpaqueToken()
+	if err != nil {
+		return tokenPair{}, err
+	}
+
+	now := time.Now()
+	session := models.Session{
+		ID:               uuid.NewString(),
+		UserID:           user.ID,
+		RefreshTokenHash: refreshTokenHash,
+		ExpiresAt:        now.Add(as.config.REFRESH_TOKEN_TTL),
+		CreatedAt:        now,
+	}
+	if err := as.sessions.Save(ctx, session); err != nil {
+		return tokenPair{}, err
+	}
+
+	accessToken, accessTokenExpiresAt, err := lib.GenerateAccessToken(as.config.JWT_SECRET, session.ID, user.ID, user.Email, scope, as.config.ACCESS_TOKEN_TTL)
+	if err != nil {
+		return tokenPair{}, err
+	}
+
+	return tokenPair{
+		accessToken:           accessToken,
+		accessTokenExpiresAt:  accessTokenExpiresAt,
+		refreshToken:          refreshToken,
+		refreshTokenExpiresAt: session.ExpiresAt,
+		scope:                 scope,
+	}, nil
+}
+
+// revokeSessions ends every session of the user, their refresh tokens stop working immediately
+// and already issued access tokens die out within ACCESS_TOKEN_TTL
+func (as *AuthServer) revokeSessions(ctx context.Context, userID string) error {
+	return as.sessions.DeleteByUser(ctx, userID)
+}
+
+// issueOneTimeToken stores the hash of a new single-use token and returns the raw token
+func issueOneTimeToken(ctx context.Context, repo repositories.OneTimeTokenRepository, userID string, ttl time.Duration) (string, time.Time, error) {
+	token, hash, err := lib.GenerateOpaqueToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	oneTimeToken := models.OneTimeToken{
+		Hash:      hash,
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := repo.Save(ctx, oneTimeToken); err != nil {
+		return "", time.Time{}, err
+	}
+	return token, oneTimeToken.ExpiresAt, nil
+}
+
+// scopeFor resolves the scope of tokens issued to the user, ok is false when the user isn't allowed to sign in
+func (as *AuthServer) scopeFor(user models.User) (scope string, ok bool) {
+	if user.EmailVerified {
+		return lib.ScopeFull, true
+	}
+	if as.config.UNVERIFIED_SIGN_IN == config.UnverifiedSignInReject {
+		return "", false
+	}
+	return lib.ScopeUnverified, true
+}
@@ -0,0 +1,212 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/lib"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/repositories"
+	auth "github.com/Salladin95/card-quizzler-microservices/auth-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+const (
+	recoveryCodesCount = 10
+	// maxChallengeAttempts is how many wrong codes a sign-in challenge survives
+	maxChallengeAttempts = 5
+)
+
+func (as *AuthServer) VerifySignIn(ctx context.Context, req *auth.VerifySignInRequest) (*auth.SignInResponse, error) {
+	payload := req.GetPayload()
+	dto := verifySignInDto{ChallengeToken: payload.GetChallengeToken(), Code: payload.GetCode()}
+	if err := as.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	challenge, err := as.signInChallenges.Consume(ctx, lib.HashOpaqueToken(dto.ChallengeToken))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, errInvalidChallenge
+		}
+		return nil, operationFailure("consume sign-in challenge", err)
+	}
+	if challenge.Expired(time.Now()) {
+		return nil, errInvalidChallenge
+	}
+
+	user, err := as.users.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, errInvalidChallenge
+		}
+		return nil, operationFailure("get user", err)
+	}
+
+	if !as.verifySecondFactor(&user, dto.Code) {
+		// give the challenge back unless it ran out of attempts
+		challenge.Attempts++
+		if challenge.Attempts < maxChallengeAttempts {
+			if err := as.signInChallenges.Save(ctx, challenge); err != nil {
+				return nil, operationFailure("save sign-in challenge", err)
+			}
+			return nil, errInvalidCode
+		}
+		return nil, errInvalidChallenge
+	}
+	if err := as.users.Update(ctx, user); err != nil {
+		return nil, operationFailure("update user", err)
+	}
+
+	scope, ok := as.scopeFor(user)
+	if !ok {
+		return nil, errEmailNotVerified
+	}
+	return as.signInResponse(ctx, user, scope)
+}
+
+func (as *AuthServer) EnrollTOTP(ctx context.Context, req *auth.EnrollTOTPRequest) (*auth.EnrollTOTPResponse, error) {
+	dto := enrollTOTPDto{UserID: req.GetPayload().GetUserId()}
+	if err := as.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	user, err := as.getUser(ctx, dto.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTP.Enabled {
+		return nil, status.Error(codes.FailedPrecondition, "two-factor authentication is already enabled")
+	}
+
+	secret, err := lib.GenerateTOTPSecret()
+	if err != nil {
+		return nil, operationFailure("generate totp secret", err)
+	}
+	// the secret only becomes active once ConfirmTOTP proves the app is set up
+	user.TOTP.PendingSecret = secret
+	user.UpdatedAt = time.Now()
+	if err := as.users.Update(ctx, user); err != nil {
+		return nil, operationFailure("update user", err)
+	}
+
+	return &auth.EnrollTOTPResponse{
+		Secret:     secret,
+		OtpauthUri: lib.TOTPURI(as.config.TOTP_ISSUER, user.Email, secret),
+	}, nil
+}
+
+func (as *AuthServer) ConfirmTOTP(ctx context.Context, req *auth.ConfirmTOTPRequest) (*auth.ConfirmTOTPResponse, error) {
+	payload := req.GetPayload()
+	dto := confirmTOTPDto{UserID: payload.GetUserId(), Code: payload.GetCode()}
+	if err := as.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	user, err := as.getUser(ctx, dto.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTP.Enabled {
+		return nil, status.Error(codes.FailedPrecondition, "two-factor authentication is already enabled")
+	}
+	if user.TOTP.PendingSecret == "" {
+		return nil, status.Error(codes.FailedPrecondition, "two-factor authentication enrollment isn't started")
+	}
+
+	step, ok := lib.ValidateTOTP(user.TOTP.PendingSecret, dto.Code, time.Now())
+	if !ok {
+		return nil, errInvalidCode
+	}
+
+	recoveryCodes, err := lib.GenerateRecoveryCodes(recoveryCodesCount)
+	if err != nil {
+		return nil, operationFailure("generate recovery codes", err)
+	}
+	hashes := make([]string, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		hashes = append(hashes, lib.HashOpaqueToken(code))
+	}
+
+	user.TOTP = models.TOTP{
+		Enabled:            true,
+		Secret:             user.TOTP.PendingSecret,
+		LastUsedStep:       step,
+		RecoveryCodeHashes: hashes,
+	}
+	user.UpdatedAt = time.Now()
+	if err := as.users.Update(ctx, user); err != nil {
+		return nil, operationFailure("update user", err)
+	}
+
+	return &auth.ConfirmTOTPResponse{
+		Message:       "two-factor authentication is enabled, store the recovery codes in a safe place",
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+func (as *AuthServer) DisableTOTP(ctx context.Context, req *auth.DisableTOTPRequest) (*auth.DisableTOTPResponse, error) {
+	payload := req.GetPayload()
+	dto := disableTOTPDto{UserID: payload.GetUserId(), Password: payload.GetPassword(), Code: payload.GetCode()}
+	if err := as.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	user, err := as.getUser(ctx, dto.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TOTP.Enabled {
+		return nil, status.Error(codes.FailedPrecondition, "two-factor authentication isn't enabled")
+	}
+	if !lib.CompareHashAndPassword(user.PasswordHash, dto.Password) {
+		return nil, status.Error(codes.InvalidArgument, "password is incorrect")
+	}
+	if !as.verifySecondFactor(&user, dto.Code) {
+		return nil, errInvalidCode
+	}
+
+	user.TOTP = models.TOTP{}
+	user.UpdatedAt = time.Now()
+	if err := as.users.Update(ctx, user); err != nil {
+		return nil, operationFailure("update user", err)
+	}
+
+	return &auth.DisableTOTPResponse{Message: "two-factor authentication is disabled"}, nil
+}
+
+// verifySecondFactor accepts either a fresh totp code or an unused recovery code.
+// It updates the replay protection state of the user, the caller must persist the user on success.
+func (as *AuthServer) verifySecondFactor(user *models.User, code string) bool {
+	if step, ok := lib.ValidateTOTP(user.TOTP.Secret, code, time.Now()); ok {
+		if step <= user.TOTP.LastUsedStep {
+			return false
+		}
+		user.TOTP.LastUsedStep = step
+		return true
+	}
+
+	hash := lib.HashOpaqueToken(lib.NormalizeRecoveryCode(code))
+	for i, recoveryCodeHash := range user.TOTP.RecoveryCodeHashes {
+		if recoveryCodeHash == hash {
+			remaining := make([]string, 0, len(user.TOTP.RecoveryCodeHashes)-1)
+			remaining = append(remaining, user.TOTP.RecoveryCodeHashes[:i]...)
+			remaining = append(remaining, user.TOTP.RecoveryCodeHashes[i+1:]...)
+			user.TOTP.RecoveryCodeHashes = remaining
+			return true
+		}
+	}
+	return false
+}
+
+func (as *AuthServer) getUser(ctx context.Context, id string) (models.User, error) {
+	user, err := as.users.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return models.User{}, errUserNotFound
+		}
+		return models.User{}, operationFailure("get user", err)
+	}
+	return user, nil
+}
@@ -0,0 +1,205 @@
+package server
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/lib"
+	auth "github.com/Salladin95/card-quizzler-microservices/auth-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+	"testing"
+	"time"
+)
+
+// totpCode returns the code of the secret shifted by a number of 30 second steps
+func totpCode(t *testing.T, secret string, steps int) string {
+	t.Helper()
+	code, err := lib.TOTPCode(secret, time.Now().Add(time.Duration(steps)*30*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+// enableTOTP enrolls the user and returns the secret and the recovery codes
+func enableTOTP(t *testing.T, as *AuthServer, userID string) (string, []string) {
+	t.Helper()
+	ctx := context.Background()
+	enrolled, err := as.EnrollTOTP(ctx, &auth.EnrollTOTPRequest{Payload: &auth.EnrollTOTPPayload{UserId: userID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	confirmed, err := as.ConfirmTOTP(ctx, &auth.ConfirmTOTPRequest{Payload: &auth.ConfirmTOTPPayload{
+		UserId: userID, Code: totpCode(t, enrolled.GetSecret(), 0),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return enrolled.GetSecret(), confirmed.GetRecoveryCodes()
+}
+
+// challenge signs in with the password and returns the token of the second step
+func challenge(t *testing.T, as *AuthServer) string {
+	t.Helper()
+	res, err := signIn(as, "ann@example.com", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.GetTwoFactorRequired() || res.GetChallengeToken() == "" || res.GetAccessToken() != "" {
+		t.Fatalf("sign in returned %+v, want a challenge", res)
+	}
+	return res.GetChallengeToken()
+}
+
+func verifySignIn(as *AuthServer, challengeToken, code string) (*auth.SignInResponse, error) {
+	return as.VerifySignIn(context.Background(), &auth.VerifySignInRequest{Payload: &auth.VerifySignInPayload{
+		ChallengeToken: challengeToken, Code: code,
+	}})
+}
+
+func TestEnrollTOTP(t *testing.T) {
+	as := newTestServer(t, nil)
+	ctx := context.Background()
+	seedUser(t, as, "ann", "ann@example.com", "correct horse")
+
+	enrolled, err := as.EnrollTOTP(ctx, &auth.EnrollTOTPRequest{Payload: &auth.EnrollTOTPPayload{UserId: "ann"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(enrolled.GetOtpauthUri(), "otpauth://totp/Quizzler:ann@example.com?") {
+		t.Errorf("otpauth uri %s", enrolled.GetOtpauthUri())
+	}
+	// the secret isn't active before it is confirmed
+	if res, err := signIn(as, "ann@example.com", "correct horse"); err != nil || res.GetTwoFactorRequired() {
+		t.Fatalf("sign in before confirming returned %+v, %v", res, err)
+	}
+	_, err = as.ConfirmTOTP(ctx, &auth.ConfirmTOTPRequest{Payload: &auth.ConfirmTOTPPayload{
+		UserId: "ann", Code: totpCode(t, enrolled.GetSecret(), 3),
+	}})
+	if err != errInvalidCode {
+		t.Errorf("confirming with a code outside the window returned %v", err)
+	}
+
+	confirmed, err := as.ConfirmTOTP(ctx, &auth.ConfirmTOTPRequest{Payload: &auth.ConfirmTOTPPayload{
+		UserId: "ann", Code: totpCode(t, enrolled.GetSecret(), 0),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(confirmed.GetRecoveryCodes()) != recoveryCodesCount {
+		t.Errorf("got %d recovery codes, want %d", len(confirmed.GetRecoveryCodes()), recoveryCodesCount)
+	}
+	user, err := as.users.GetByID(ctx, "ann")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !user.TOTP.Enabled || user.TOTP.Secret != enrolled.GetSecret() || user.TOTP.PendingSecret != "" || user.TOTP.LastUsedStep == 0 {
+		t.Errorf("totp state after confirming %+v", user.TOTP)
+	}
+	for _, code := range confirmed.GetRecoveryCodes() {
+		for _, hash := range user.TOTP.RecoveryCodeHashes {
+			if hash == code {
+				t.Fatal("recovery codes are stored in plain text")
+			}
+		}
+	}
+
+	_, err = as.EnrollTOTP(ctx, &auth.EnrollTOTPRequest{Payload: &auth.EnrollTOTPPayload{UserId: "ann"}})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("enrolling again returned %v, want FailedPrecondition", err)
+	}
+}
+
+func TestVerifySignInRejectsReusedSteps(t *testing.T) {
+	as := newTestServer(t, nil)
+	seedUser(t, as, "ann", "ann@example.com", "correct horse")
+	secret, _ := enableTOTP(t, as, "ann")
+
+	// the code used to confirm the enrollment can't sign in
+	if _, err := verifySignIn(as, challenge(t, as), totpCode(t, secret, 0)); err != errInvalidCode {
+		t.Errorf("code of the confirmed step returned %v, want errInvalidCode", err)
+	}
+
+	next := totpCode(t, secret, 1)
+	token := challenge(t, as)
+	res, err := verifySignIn(as, token, next)
+	if err != nil || res.GetAccessToken() == "" {
+		t.Fatalf("fresh code returned %+v, %v", res, err)
+	}
+	// the challenge is used up, and so is the step of the code
+	if _, err := verifySignIn(as, token, next); err != errInvalidChallenge {
+		t.Errorf("reused challenge returned %v, want errInvalidChallenge", err)
+	}
+	if _, err := verifySignIn(as, challenge(t, as), next); err != errInvalidCode {
+		t.Errorf("replayed code returned %v, want errInvalidCode", err)
+	}
+}
+
+func TestRecoveryCodesWorkOnce(t *testing.T) {
+	as := newTestServer(t, nil)
+	seedUser(t, as, "ann", "ann@example.com", "correct horse")
+	_, recoveryCodes := enableTOTP(t, as, "ann")
+
+	// codes are typed loosely
+	typed := " " + strings.ToUpper(recoveryCodes[0]) + " "
+	if res, err := verifySignIn(as, challenge(t, as), typed); err != nil || res.GetAccessToken() == "" {
+		t.Fatalf("recovery code returned %+v, %v", res, err)
+	}
+	if _, err := verifySignIn(as, challenge(t, as), recoveryCodes[0]); err != errInvalidCode {
+		t.Errorf("used recovery code returned %v, want errInvalidCode", err)
+	}
+	if _, err := verifySignIn(as, challenge(t, as), recoveryCodes[1]); err != nil {
+		t.Errorf("another recovery code returned %v", err)
+	}
+
+	user, err := as.users.GetByID(context.Background(), "ann")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(user.TOTP.RecoveryCodeHashes) != recoveryCodesCount-2 {
+		t.Errorf("%d recovery codes left, want %d", len(user.TOTP.RecoveryCodeHashes), recoveryCodesCount-2)
+	}
+}
+
+func TestSignInChallengeAttemptLimit(t *testing.T) {
+	as := newTestServer(t, nil)
+	seedUser(t, as, "ann", "ann@example.com", "correct horse")
+	secret, _ := enableTOTP(t, as, "ann")
+
+	token := challenge(t, as)
+	for attempt := 1; attempt < maxChallengeAttempts; attempt++ {
+		if _, err := verifySignIn(as, token, "000000"); err != errInvalidCode {
+			t.Fatalf("wrong code %d returned %v, want errInvalidCode", attempt, err)
+		}
+	}
+	if _, err := verifySignIn(as, token, "000000"); err != errInvalidChallenge {
+		t.Fatalf("wrong code %d returned %v, want errInvalidChallenge", maxChallengeAttempts, err)
+	}
+	// the challenge is gone, even the right code has to sign in again
+	if _, err := verifySignIn(as, token, totpCode(t, secret, 1)); err != errInvalidChallenge {
+		t.Errorf("right code after the limit returned %v, want errInvalidChallenge", err)
+	}
+}
+
+func TestDisableTOTP(t *testing.T) {
+	as := newTestServer(t, nil)
+	ctx := context.Background()
+	seedUser(t, as, "ann", "ann@example.com", "correct horse")
+	secret, _ := enableTOTP(t, as, "ann")
+
+	_, err := as.DisableTOTP(ctx, &auth.DisableTOTPRequest{Payload: &auth.DisableTOTPPayload{
+		UserId: "ann", Password: "wrong horse", Code: totpCode(t, secret, 1),
+	}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("wrong password returned %v", err)
+	}
+	_, err = as.DisableTOTP(ctx, &auth.DisableTOTPRequest{Payload: &auth.DisableTOTPPayload{
+		UserId: "ann", Password: "correct horse", Code: totpCode(t, secret, 1),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res, err := signIn(as, "ann@example.com", "correct horse"); err != nil || res.GetTwoFactorRequired() {
+		t.Errorf("sign in after disabling returned %+v, %v", res, err)
+	}
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/lib"
+	"github.com/Salladin95/card-quizzler-microservices/auth-service/cmd/api/repositories"
+	auth "github.com/Salladin95/card-quizzler-microservices/auth-service/proto"
+	"time"
+)
+
+func (as *AuthServer) VerifyEmail(ctx context.Context, req *auth.VerifyEmailRequest) (*auth.VerifyEmailResponse, error) {
+	dto := verifyEmailDto{Token: req.GetPayload().GetToken()}
+	if err := as.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	// consuming the token removes it, so it can't be used twice
+	token, err := as.verificationTokens.Consume(ctx, lib.HashOpaqueToken(dto.Token))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, errInvalidToken
+		}
+		return nil, operationFailure("consume verification token", err)
+	}
+	if token.Expired(time.Now()) {
+		return nil, errInvalidToken
+	}
+
+	user, err := as.users.GetByID(ctx, token.UserID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, errInvalidToken
+		}
+		return nil, operationFailure("get user", err)
+	}
+
+	if !user.EmailVerified {
+		user.EmailVerified = true
+		user.UpdatedAt = time.Now()
+		if err := as.users.Update(ctx, user); err != nil {
+			return nil, operationFailure("update user", err)
+		}
+	}
+	// other outstanding tokens are useless once the email is verified
+	if err := as.verificationTokens.DeleteByUser(ctx, user.ID); err != nil {
+		return nil, operationFailure("delete verification tokens", err)
+	}
+
+	return &auth.VerifyEmailResponse{Message: "email is verified"}, nil
+}
@@ -11,7 +11,9 @@ type AppCfg struct {
 	BROKER_SERVICE_PORT string `validate:"required"`
 	AUTH_SERVICE_PORT   string `validate:"required"`
 	AUTH_SERVICE_URL    string `validate:"required"`
+	CARDS_SERVICE_URL   string `validate:"required"`
 	RABBIT_URL          string `validate:"required"`
+	JWT_SECRET          string `validate:"required"`
 }
 
 type Config struct {
@@ -20,8 +22,7 @@ type Config struct {
 
 func NewConfig() (*Config, error) {
 	env := loadEnv()
-	log.Infof("raw env - %v\n", env)
-	appCfg := AppCfg{BROKER_SERVICE_PORT: env["BROKER_SERVICE_PORT"], AUTH_SERVICE_PORT: env["AUTH_SERVICE_PORT"], AUTH_SERVICE_URL: env["AUTH_SERVICE_URL"], RABBIT_URL: env["RABBITMQ_URL"]}
+	appCfg := AppCfg{BROKER_SERVICE_PORT: env["BROKER_SERVICE_PORT"], AUTH_SERVICE_PORT: env["AUTH_SERVICE_PORT"], AUTH_SERVICE_URL: env["AUTH_SERVICE_URL"], CARDS_SERVICE_URL: env["CARDS_SERVICE_URL"], RABBIT_URL: env["RABBITMQ_URL"], JWT_SECRET: env["JWT_SECRET"]}
 	validate := validator.New()
 	if err := validate.Struct(appCfg); err != nil {
 		return nil, err
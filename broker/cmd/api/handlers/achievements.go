@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/broker-service/cards"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+// GetAchievements responds with every achievement, the user's badges and the progress towards the locked ones
+func (bh *brokerHandlers) GetAchievements(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.GetAchievements(ctx, &cards.AchievementsRequest{
+		Payload: &cards.AchievementsPayload{UserId: getUserID(c)},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
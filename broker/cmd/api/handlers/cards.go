@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/broker-service/cards"
+	"github.com/Salladin95/goErrorHandler"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+type CardDto struct {
+	Front string `json:"front" validate:"required"`
+	Back  string `json:"back" validate:"required"`
+	Hint  string `json:"hint"`
+	// Alternatives are other accepted answers for typed questions
+	Alternatives []string `json:"alternatives"`
+	Tags         []string `json:"tags"`
+	// AnswerType is "text" or "numeric", tolerances only apply to numeric answers
+	AnswerType        string  `json:"answerType"`
+	AbsoluteTolerance float64 `json:"absoluteTolerance"`
+	RelativeTolerance float64 `json:"relativeTolerance"`
+}
+
+type GradeAnswerDto struct {
+	Answer string `json:"answer"`
+	Strict bool   `json:"strict"`
+}
+
+func (bh *brokerHandlers) ListCards(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.ListCards(ctx, &cards.ListCardsRequest{
+		Payload: &cards.ListCardsPayload{UserId: getUserID(c), DeckId: c.Param("id")},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (bh *brokerHandlers) CreateCard(c echo.Context) error {
+	var cardDTO CardDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&cardDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.CreateCard(ctx, &cards.CreateCardRequest{
+		Payload: &cards.CreateCardPayload{
+			UserId:            getUserID(c),
+			DeckId:            c.Param("id"),
+			Front:             cardDTO.Front,
+			Back:              cardDTO.Back,
+			Hint:              cardDTO.Hint,
+			Alternatives:      cardDTO.Alternatives,
+			Tags:              cardDTO.Tags,
+			AnswerType:        cardDTO.AnswerType,
+			AbsoluteTolerance: cardDTO.AbsoluteTolerance,
+			RelativeTolerance: cardDTO.RelativeTolerance,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, res.GetCard())
+}
+
+func (bh *brokerHandlers) GetCard(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.GetCard(ctx, &cards.GetCardRequest{
+		Payload: &cards.GetCardPayload{
+			UserId: getUserID(c),
+			DeckId: c.Param("id"),
+			CardId: c.Param("cardId"),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res.GetCard())
+}
+
+func (bh *brokerHandlers) UpdateCard(c echo.Context) error {
+	var cardDTO CardDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&cardDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.UpdateCard(ctx, &cards.UpdateCardRequest{
+		Payload: &cards.UpdateCardPayload{
+			UserId:            getUserID(c),
+			DeckId:            c.Param("id"),
+			CardId:            c.Param("cardId"),
+			Front:             cardDTO.Front,
+			Back:              cardDTO.Back,
+			Hint:              cardDTO.Hint,
+			Alternatives:      cardDTO.Alternatives,
+			Tags:              cardDTO.Tags,
+			AnswerType:        cardDTO.AnswerType,
+			AbsoluteTolerance: cardDTO.AbsoluteTolerance,
+			RelativeTolerance: cardDTO.RelativeTolerance,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res.GetCard())
+}
+
+func (bh *brokerHandlers) DeleteCard(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.DeleteCard(ctx, &cards.DeleteCardRequest{
+		Payload: &cards.DeleteCardPayload{
+			UserId: getUserID(c),
+			DeckId: c.Param("id"),
+			CardId: c.Param("cardId"),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, JsonResponse{Message: res.GetMessage()})
+}
+
+func (bh *brokerHandlers) GradeAnswer(c echo.Context) error {
+	var gradeAnswerDTO GradeAnswerDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&gradeAnswerDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.GradeAnswer(ctx, &cards.GradeAnswerRequest{
+		Payload: &cards.GradeAnswerPayload{
+			UserId: getUserID(c),
+			DeckId: c.Param("id"),
+			CardId: c.Param("cardId"),
+			Answer: gradeAnswerDTO.Answer,
+			Strict: gradeAnswerDTO.Strict,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
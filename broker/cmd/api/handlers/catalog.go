@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/broker-service/cards"
+	"github.com/Salladin95/goErrorHandler"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+// BrowseCatalogDto is read from the query string, empty filters match every deck
+type BrowseCatalogDto struct {
+	Category string `query:"category"`
+	// Language is a BCP 47 tag, "pt" matches "pt-BR" too
+	Language string `query:"language"`
+	// popular | recent | title, recent when empty
+	Sort   string `query:"sort"`
+	Limit  int32  `query:"limit"`
+	Offset int32  `query:"offset"`
+}
+
+type PublishDeckDto struct {
+	Category string `json:"category"`
+	Language string `json:"language"`
+}
+
+type ForkDeckDto struct {
+	// FolderID files the fork in a folder of the user
+	FolderID string `json:"folderId"`
+}
+
+type MergeUpstreamDto struct {
+	// UpstreamCardIDs are the previewed changes to apply
+	UpstreamCardIDs []string `json:"upstreamCardIds"`
+}
+
+func (bh *brokerHandlers) BrowseCatalog(c echo.Context) error {
+	var browseDTO BrowseCatalogDto
+
+	// Read the query string and unmarshal it into the corresponding DTO
+	if err := c.Bind(&browseDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.BrowseCatalog(ctx, &cards.BrowseCatalogRequest{
+		Payload: &cards.BrowseCatalogPayload{
+			UserId:   getUserID(c),
+			Category: browseDTO.Category,
+			Language: browseDTO.Language,
+			Sort:     browseDTO.Sort,
+			Limit:    browseDTO.Limit,
+			Offset:   browseDTO.Offset,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetCatalogDeck responds with a published deck and its cards
+func (bh *brokerHandlers) GetCatalogDeck(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.GetCatalogDeck(ctx, &cards.CatalogDeckRequest{
+		Payload: &cards.CatalogDeckPayload{UserId: getUserID(c), DeckId: c.Param("id")},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (bh *brokerHandlers) ForkDeck(c echo.Context) error {
+	var forkDTO ForkDeckDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&forkDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.ForkDeck(ctx, &cards.ForkDeckRequest{
+		Payload: &cards.ForkDeckPayload{UserId: getUserID(c), DeckId: c.Param("id"), FolderId: forkDTO.FolderID},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, res.GetDeck())
+}
+
+func (bh *brokerHandlers) PublishDeck(c echo.Context) error {
+	var publishDTO PublishDeckDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&publishDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.PublishDeck(ctx, &cards.PublishDeckRequest{
+		Payload: &cards.PublishDeckPayload{
+			UserId:   getUserID(c),
+			DeckId:   c.Param("id"),
+			Category: publishDTO.Category,
+			Language: publishDTO.Language,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res.GetCatalogDeck())
+}
+
+func (bh *brokerHandlers) UnpublishDeck(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.UnpublishDeck(ctx, &cards.CatalogDeckRequest{
+		Payload: &cards.CatalogDeckPayload{UserId: getUserID(c), DeckId: c.Param("id")},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, JsonResponse{Message: res.GetMessage()})
+}
+
+// GetUpstreamChanges previews per card what pulling the upstream of a fork would change
+func (bh *brokerHandlers) GetUpstreamChanges(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.GetUpstreamChanges(ctx, &cards.CatalogDeckRequest{
+		Payload: &cards.CatalogDeckPayload{UserId: getUserID(c), DeckId: c.Param("id")},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (bh *brokerHandlers) MergeUpstream(c echo.Context) error {
+	var mergeDTO MergeUpstreamDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&mergeDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.MergeUpstream(ctx, &cards.MergeUpstreamRequest{
+		Payload: &cards.MergeUpstreamPayload{
+			UserId:          getUserID(c),
+			DeckId:          c.Param("id"),
+			UpstreamCardIds: mergeDTO.UpstreamCardIDs,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
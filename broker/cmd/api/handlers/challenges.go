@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/broker-service/cards"
+	"github.com/Salladin95/goErrorHandler"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+type ChallengeRecordsDto struct {
+	Limit int32 `query:"limit"`
+}
+
+func (bh *brokerHandlers) GetChallengeRecords(c echo.Context) error {
+	var challengeRecordsDTO ChallengeRecordsDto
+
+	// Read the query string and unmarshal it into the corresponding DTO
+	if err := c.Bind(&challengeRecordsDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.GetChallengeRecords(ctx, &cards.GetChallengeRecordsRequest{
+		Payload: &cards.GetChallengeRecordsPayload{
+			UserId: getUserID(c),
+			DeckId: c.Param("id"),
+			Limit:  challengeRecordsDTO.Limit,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/broker-service/cards"
+	"github.com/Salladin95/goErrorHandler"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+type ListDecksDto struct {
+	Limit  int32 `query:"limit"`
+	Offset int32 `query:"offset"`
+}
+
+type DeckDto struct {
+	Title       string   `json:"title" validate:"required"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	// ParentID nests a new deck inside another deck, updates keep the parent
+	ParentID string `json:"parentId"`
+	// FolderID files a new deck in a folder, updates keep it, decks are moved with PUT /decks/:id/folder
+	FolderID string `json:"folderId"`
+}
+
+func (bh *brokerHandlers) ListDecks(c echo.Context) error {
+	var listDecksDTO ListDecksDto
+
+	// Read the query string and unmarshal it into the corresponding DTO
+	if err := c.Bind(&listDecksDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.ListDecks(ctx, &cards.ListDecksRequest{
+		Payload: &cards.ListDecksPayload{
+			UserId: getUserID(c),
+			Limit:  listDecksDTO.Limit,
+			Offset: listDecksDTO.Offset,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (bh *brokerHandlers) CreateDeck(c echo.Context) error {
+	var deckDTO DeckDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&deckDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.CreateDeck(ctx, &cards.CreateDeckRequest{
+		Payload: &cards.CreateDeckPayload{
+			UserId:      getUserID(c),
+			Title:       deckDTO.Title,
+			Description: deckDTO.Description,
+			Tags:        deckDTO.Tags,
+			ParentId:    deckDTO.ParentID,
+			FolderId:    deckDTO.FolderID,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, res.GetDeck())
+}
+
+func (bh *brokerHandlers) GetDeck(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.GetDeck(ctx, &cards.GetDeckRequest{
+		Payload: &cards.GetDeckPayload{UserId: getUserID(c), DeckId: c.Param("id")},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res.GetDeck())
+}
+
+func (bh *brokerHandlers) UpdateDeck(c echo.Context) error {
+	var deckDTO DeckDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&deckDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.UpdateDeck(ctx, &cards.UpdateDeckRequest{
+		Payload: &cards.UpdateDeckPayload{
+			UserId:      getUserID(c),
+			DeckId:      c.Param("id"),
+			Title:       deckDTO.Title,
+			Description: deckDTO.Description,
+			Tags:        deckDTO.Tags,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res.GetDeck())
+}
+
+func (bh *brokerHandlers) DeleteDeck(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.DeleteDeck(ctx, &cards.DeleteDeckRequest{
+		Payload: &cards.DeleteDeckPayload{UserId: getUserID(c), DeckId: c.Param("id")},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, JsonResponse{Message: res.GetMessage()})
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/broker-service/cards"
+	"github.com/Salladin95/goErrorHandler"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+type FilteredDeckDto struct {
+	Name string `json:"name"`
+	// Query selects the cards, e.g. "tag:verbs -deck:Archive is:due lapses>=3"
+	Query string `json:"query"`
+	// Limit is the number of cards studied at once, 0 uses the default
+	Limit int32 `json:"limit"`
+}
+
+func (bh *brokerHandlers) ListFilteredDecks(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.ListFilteredDecks(ctx, &cards.ListFilteredDecksRequest{
+		Payload: &cards.ListFilteredDecksPayload{UserId: getUserID(c)},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (bh *brokerHandlers) CreateFilteredDeck(c echo.Context) error {
+	var filteredDeckDTO FilteredDeckDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&filteredDeckDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.CreateFilteredDeck(ctx, &cards.CreateFilteredDeckRequest{
+		Payload: &cards.CreateFilteredDeckPayload{
+			UserId: getUserID(c),
+			Name:   filteredDeckDTO.Name,
+			Query:  filteredDeckDTO.Query,
+			Limit:  filteredDeckDTO.Limit,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, res.GetFilteredDeck())
+}
+
+func (bh *brokerHandlers) GetFilteredDeck(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.GetFilteredDeck(ctx, &cards.FilteredDeckRequest{
+		Payload: &cards.FilteredDeckPayload{UserId: getUserID(c), FilteredDeckId: c.Param("id")},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res.GetFilteredDeck())
+}
+
+func (bh *brokerHandlers) UpdateFilteredDeck(c echo.Context) error {
+	var filteredDeckDTO FilteredDeckDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&filteredDeckDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.UpdateFilteredDeck(ctx, &cards.UpdateFilteredDeckRequest{
+		Payload: &cards.UpdateFilteredDeckPayload{
+			UserId:         getUserID(c),
+			FilteredDeckId: c.Param("id"),
+			Name:           filteredDeckDTO.Name,
+			Query:          filteredDeckDTO.Query,
+			Limit:          filteredDeckDTO.Limit,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res.GetFilteredDeck())
+}
+
+func (bh *brokerHandlers) DeleteFilteredDeck(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.DeleteFilteredDeck(ctx, &cards.FilteredDeckRequest{
+		Payload: &cards.FilteredDeckPayload{UserId: getUserID(c), FilteredDeckId: c.Param("id")},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, JsonResponse{Message: res.GetMessage()})
+}
+
+func (bh *brokerHandlers) GetFilteredDueCards(c echo.Context) error {
+	return bh.getDueCards(c, &cards.GetDueCardsPayload{UserId: getUserID(c), FilteredDeckId: c.Param("id")})
+}
+
+func (bh *brokerHandlers) StartFilteredQuiz(c echo.Context) error {
+	return bh.startQuiz(c, &cards.StartQuizPayload{FilteredDeckId: c.Param("id")})
+}
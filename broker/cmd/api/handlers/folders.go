@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/broker-service/cards"
+	"github.com/Salladin95/goErrorHandler"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+type FolderDto struct {
+	Name string `json:"name"`
+	// ParentID nests the folder in another one, an update with an empty parent moves it to the top level
+	ParentID string `json:"parentId"`
+}
+
+type MoveDeckDto struct {
+	// FolderID is empty to take the deck out of its folder
+	FolderID string `json:"folderId"`
+}
+
+func (bh *brokerHandlers) ListFolders(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.ListFolders(ctx, &cards.ListFoldersRequest{
+		Payload: &cards.ListFoldersPayload{UserId: getUserID(c)},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (bh *brokerHandlers) CreateFolder(c echo.Context) error {
+	var folderDTO FolderDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&folderDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.CreateFolder(ctx, &cards.CreateFolderRequest{
+		Payload: &cards.CreateFolderPayload{
+			UserId:   getUserID(c),
+			Name:     folderDTO.Name,
+			ParentId: folderDTO.ParentID,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, res.GetFolder())
+}
+
+func (bh *brokerHandlers) UpdateFolder(c echo.Context) error {
+	var folderDTO FolderDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&folderDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.UpdateFolder(ctx, &cards.UpdateFolderRequest{
+		Payload: &cards.UpdateFolderPayload{
+			UserId:   getUserID(c),
+			FolderId: c.Param("id"),
+			Name:     folderDTO.Name,
+			ParentId: folderDTO.ParentID,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res.GetFolder())
+}
+
+func (bh *brokerHandlers) DeleteFolder(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.DeleteFolder(ctx, &cards.DeleteFolderRequest{
+		Payload: &cards.DeleteFolderPayload{UserId: getUserID(c), FolderId: c.Param("id")},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, JsonResponse{Message: res.GetMessage()})
+}
+
+func (bh *brokerHandlers) MoveDeck(c echo.Context) error {
+	var moveDeckDTO MoveDeckDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&moveDeckDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.MoveDeck(ctx, &cards.MoveDeckRequest{
+		Payload: &cards.MoveDeckPayload{
+			UserId:   getUserID(c),
+			DeckId:   c.Param("id"),
+			FolderId: moveDeckDTO.FolderID,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res.GetDeck())
+}
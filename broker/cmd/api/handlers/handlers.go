@@ -25,10 +25,16 @@ type SighUpDto struct {
 	Password string `json:"password"  validate:"required,min=6"`
 	Email    string `json:"email"  validate:"required,email"`
 	Birthday string `json:"birthday"  validate:"required,min=1"`
+	// Timezone is an IANA name, e.g. "Europe/Berlin", study days are counted in it
+	Timezone string `json:"timezone"`
+}
+
+type VerifyEmailDto struct {
+	Token string `json:"token" validate:"required"`
 }
 
 type JsonResponse struct {
-	message string
+	Message string `json:"message"`
 }
 
 func (bh *brokerHandlers) SignIn(c echo.Context) error {
@@ -41,10 +47,10 @@ func (bh *brokerHandlers) SignIn(c echo.Context) error {
 	}
 
 	clientConn, err := bh.GetGRPCClientConn()
-	defer clientConn.Close()
 	if err != nil {
 		return err
 	}
+	defer clientConn.Close()
 	ah := auth.NewAuthClient(clientConn)
 
 	// Use a longer timeout for the gRPC call, adjust as needed
@@ -57,10 +63,8 @@ func (bh *brokerHandlers) SignIn(c echo.Context) error {
 			Password: signInDTO.Password,
 		},
 	})
-
-	// TODO: REFACTOR ERROR HANDLING
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, JsonResponse{message: err.Error()})
+		return err
 	}
 
 	return c.JSON(http.StatusOK, res)
@@ -76,13 +80,14 @@ func (bh *brokerHandlers) SignUp(c echo.Context) error {
 	}
 
 	clientConn, err := bh.GetGRPCClientConn()
-	defer clientConn.Close()
 	if err != nil {
 		return err
 	}
+	defer clientConn.Close()
 	ah := auth.NewAuthClient(clientConn)
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	// password hashing takes a moment, so give the call some room
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	res, err := ah.SignUp(ctx, &auth.SignUpRequest{
@@ -91,13 +96,177 @@ func (bh *brokerHandlers) SignUp(c echo.Context) error {
 			Password: signUpDTO.Password,
 			Name:     signUpDTO.Name,
 			Birthday: signUpDTO.Birthday,
+			Timezone: signUpDTO.Timezone,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+func (bh *brokerHandlers) VerifyEmail(c echo.Context) error {
+	var verifyEmailDTO VerifyEmailDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&verifyEmailDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetGRPCClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ah := auth.NewAuthClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ah.VerifyEmail(ctx, &auth.VerifyEmailRequest{
+		Payload: &auth.VerifyEmailPayload{Token: verifyEmailDTO.Token},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, JsonResponse{Message: res.GetMessage()})
+}
+
+type RefreshTokensDto struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+type RequestPasswordResetDto struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordDto struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"newPassword" validate:"required,min=6"`
+}
+
+type ChangePasswordDto struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required,min=6"`
+}
+
+func (bh *brokerHandlers) RefreshTokens(c echo.Context) error {
+	var refreshTokensDTO RefreshTokensDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&refreshTokensDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetGRPCClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ah := auth.NewAuthClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ah.RefreshTokens(ctx, &auth.RefreshTokensRequest{
+		Payload: &auth.RefreshTokensPayload{RefreshToken: refreshTokensDTO.RefreshToken},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (bh *brokerHandlers) RequestPasswordReset(c echo.Context) error {
+	var requestPasswordResetDTO RequestPasswordResetDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&requestPasswordResetDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetGRPCClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ah := auth.NewAuthClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ah.RequestPasswordReset(ctx, &auth.RequestPasswordResetRequest{
+		Payload: &auth.RequestPasswordResetPayload{Email: requestPasswordResetDTO.Email},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, JsonResponse{Message: res.GetMessage()})
+}
+
+func (bh *brokerHandlers) ResetPassword(c echo.Context) error {
+	var resetPasswordDTO ResetPasswordDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&resetPasswordDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetGRPCClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ah := auth.NewAuthClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ah.ResetPassword(ctx, &auth.ResetPasswordRequest{
+		Payload: &auth.ResetPasswordPayload{
+			Token:       resetPasswordDTO.Token,
+			NewPassword: resetPasswordDTO.NewPassword,
 		},
 	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, JsonResponse{Message: res.GetMessage()})
+}
+
+func (bh *brokerHandlers) ChangePassword(c echo.Context) error {
+	var changePasswordDTO ChangePasswordDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&changePasswordDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetGRPCClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ah := auth.NewAuthClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	// TODO: REFACTOR ERROR HANDLING
+	res, err := ah.ChangePassword(ctx, &auth.ChangePasswordRequest{
+		Payload: &auth.ChangePasswordPayload{
+			UserId:          getUserID(c),
+			CurrentPassword: changePasswordDTO.CurrentPassword,
+			NewPassword:     changePasswordDTO.NewPassword,
+		},
+	})
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, JsonResponse{message: err.Error()})
+		return err
 	}
 
-	return c.JSON(http.StatusOK, JsonResponse{message: res.GetMessage()})
+	return c.JSON(http.StatusOK, JsonResponse{Message: res.GetMessage()})
 }
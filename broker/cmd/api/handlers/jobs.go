@@ -0,0 +1,338 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/broker-service/cards"
+	"github.com/Salladin95/goErrorHandler"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// maxUploadBytes matches the default MAX_IMPORT_BYTES of the cards service, which enforces its own limit
+	maxUploadBytes = 32 << 20
+	// maxUploadBodyBytes bounds the request body of uploads, it leaves room for the multipart framing
+	maxUploadBodyBytes = maxUploadBytes + 1<<20
+	// maxFileMessageSize leaves room for the rest of a gRPC message carrying a file
+	maxFileMessageSize = maxUploadBytes + 1<<20
+	// fileTimeout is longer than usual, files take a while to transfer and small imports are processed in the request
+	fileTimeout = 30 * time.Second
+)
+
+// ImportCardsDto is read from the query string, the body holds the file
+type ImportCardsDto struct {
+	// Format is csv, tsv, apkg, quizlet or markdown, by default it follows the extension of the uploaded file
+	Format    string `query:"format"`
+	Delimiter string `query:"delimiter"`
+	NoHeader  bool   `query:"noHeader"`
+	DryRun    bool   `query:"dryRun"`
+	// Mapping maps headers to card fields, e.g. "Term=front,Definition=back"
+	Mapping string `query:"mapping"`
+	// TermSeparator and CardSeparator split a Quizlet export, tab and new line by default
+	TermSeparator string `query:"termSeparator"`
+	CardSeparator string `query:"cardSeparator"`
+}
+
+// PreviewImportDto carries pasted text, unlike imports it doesn't need a deck
+type PreviewImportDto struct {
+	Format        string `json:"format"`
+	Content       string `json:"content"`
+	TermSeparator string `json:"termSeparator"`
+	CardSeparator string `json:"cardSeparator"`
+}
+
+type ExportCardsDto struct {
+	// Format is csv, tsv, apkg or pdf
+	Format    string   `json:"format"`
+	Delimiter string   `json:"delimiter"`
+	NoHeader  bool     `json:"noHeader"`
+	Columns   []string `json:"columns"`
+	// Print lays out a pdf export
+	Print *PrintOptionsDto `json:"print"`
+}
+
+type PrintOptionsDto struct {
+	// Layout is flashcards or test
+	Layout       string  `json:"layout"`
+	Paper        string  `json:"paper"`
+	CardsPerPage int32   `json:"cardsPerPage"`
+	FontSize     float64 `json:"fontSize"`
+	// Questions is written, choice or mixed
+	Questions     string `json:"questions"`
+	QuestionCount int32  `json:"questionCount"`
+	Seed          int64  `json:"seed"`
+}
+
+// ImportCards accepts the file as the "file" field of a multipart form or as the raw request body.
+// Files are limited to maxUploadBytes (32 MiB), larger uploads are rejected with 413 before they are buffered.
+// It responds with 202 when the file is large enough to be imported in the background.
+func (bh *brokerHandlers) ImportCards(c echo.Context) error {
+	var importDTO ImportCardsDto
+
+	// Read the query string and unmarshal it into the corresponding DTO
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &importDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+	mapping, err := parseMapping(importDTO.Mapping)
+	if err != nil {
+		return err
+	}
+	content, filename, err := readUpload(c)
+	if err != nil {
+		return err
+	}
+	if importDTO.Format == "" {
+		importDTO.Format = formatFromExtension(filename)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), fileTimeout)
+	defer cancel()
+
+	res, err := ch.ImportCards(ctx, &cards.ImportCardsRequest{
+		Payload: &cards.ImportCardsPayload{
+			UserId:    getUserID(c),
+			DeckId:    c.Param("id"),
+			Format:    importDTO.Format,
+			Delimiter: importDTO.Delimiter,
+			NoHeader:  importDTO.NoHeader,
+			Mapping:   mapping,
+			DryRun:    importDTO.DryRun,
+			Content:   content,
+
+			TermSeparator: importDTO.TermSeparator,
+			CardSeparator: importDTO.CardSeparator,
+		},
+	}, grpc.MaxCallSendMsgSize(maxFileMessageSize))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(jobStatusCode(res.GetJob()), res)
+}
+
+// PreviewImport parses pasted text into cards and warnings, nothing is stored
+func (bh *brokerHandlers) PreviewImport(c echo.Context) error {
+	var previewDTO PreviewImportDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&previewDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), fileTimeout)
+	defer cancel()
+
+	res, err := ch.PreviewImport(ctx, &cards.PreviewImportRequest{
+		Payload: &cards.PreviewImportPayload{
+			UserId:        getUserID(c),
+			Format:        previewDTO.Format,
+			Content:       previewDTO.Content,
+			TermSeparator: previewDTO.TermSeparator,
+			CardSeparator: previewDTO.CardSeparator,
+		},
+	}, grpc.MaxCallSendMsgSize(maxFileMessageSize))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// ExportCards responds with 202 when the deck is large enough to be exported in the background,
+// PDFs are always printed in the background. The file is downloaded from the job either way.
+func (bh *brokerHandlers) ExportCards(c echo.Context) error {
+	var exportDTO ExportCardsDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&exportDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), fileTimeout)
+	defer cancel()
+
+	res, err := ch.ExportCards(ctx, &cards.ExportCardsRequest{
+		Payload: &cards.ExportCardsPayload{
+			UserId:    getUserID(c),
+			DeckId:    c.Param("id"),
+			Format:    exportDTO.Format,
+			Delimiter: exportDTO.Delimiter,
+			NoHeader:  exportDTO.NoHeader,
+			Columns:   exportDTO.Columns,
+			Print:     toProtoPrintOptions(exportDTO.Print),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(jobStatusCode(res.GetJob()), res)
+}
+
+func (bh *brokerHandlers) GetJob(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.GetJob(ctx, &cards.JobRequest{
+		Payload: &cards.JobPayload{UserId: getUserID(c), JobId: c.Param("jobId")},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// DownloadJobResult sends the file of a finished export as an attachment
+func (bh *brokerHandlers) DownloadJobResult(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), fileTimeout)
+	defer cancel()
+
+	res, err := ch.GetJobResult(ctx, &cards.JobRequest{
+		Payload: &cards.JobPayload{UserId: getUserID(c), JobId: c.Param("jobId")},
+	}, grpc.MaxCallRecvMsgSize(maxFileMessageSize))
+	if err != nil {
+		return err
+	}
+
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": res.GetFilename()})
+	c.Response().Header().Set(echo.HeaderContentDisposition, disposition)
+	return c.Blob(http.StatusOK, res.GetContentType(), res.GetContent())
+}
+
+func toProtoPrintOptions(dto *PrintOptionsDto) *cards.PrintOptions {
+	if dto == nil {
+		return nil
+	}
+	return &cards.PrintOptions{
+		Layout:        dto.Layout,
+		Paper:         dto.Paper,
+		CardsPerPage:  dto.CardsPerPage,
+		FontSize:      dto.FontSize,
+		Questions:     dto.Questions,
+		QuestionCount: dto.QuestionCount,
+		Seed:          dto.Seed,
+	}
+}
+
+// readUpload returns the uploaded file and its name, the name is empty for a raw body.
+// The file is held in memory to be sent over gRPC, so the body is cut off at maxUploadBodyBytes
+// and multipart forms can't spill more than that to temporary files either.
+func readUpload(c echo.Context) ([]byte, string, error) {
+	req := c.Request()
+	req.Body = http.MaxBytesReader(c.Response(), req.Body, maxUploadBodyBytes)
+	reader := req.Body
+	filename := ""
+	if strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, "", uploadFailure(err)
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, "", goErrorHandler.OperationFailure("open uploaded file", err)
+		}
+		defer file.Close()
+		reader, filename = file, header.Filename
+	}
+
+	content, err := io.ReadAll(io.LimitReader(reader, maxUploadBytes+1))
+	if err != nil {
+		return nil, "", uploadFailure(err)
+	}
+	if len(content) > maxUploadBytes {
+		return nil, "", errUploadTooLarge
+	}
+	return content, filename, nil
+}
+
+var errUploadTooLarge = echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("file is larger than %d bytes", maxUploadBytes))
+
+// uploadFailure tells bodies cut off by the size limit from malformed ones
+func uploadFailure(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return errUploadTooLarge
+	}
+	return goErrorHandler.BindRequestToBodyFailure(err)
+}
+
+// formatFromExtension leaves the format empty for unknown extensions, the cards service defaults to csv
+func formatFromExtension(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".tsv":
+		return "tsv"
+	case ".apkg":
+		return "apkg"
+	case ".md", ".markdown":
+		return "markdown"
+	}
+	return ""
+}
+
+// parseMapping reads "Header=field" pairs separated by commas
+func parseMapping(raw string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	if raw == "" {
+		return mapping, nil
+	}
+	for _, pair := range strings.Split(raw, ",") {
+		header, field, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(header) == "" {
+			return nil, goErrorHandler.ValidationFailure(map[string]string{"mapping": "expected Header=field pairs separated by commas"})
+		}
+		mapping[strings.TrimSpace(header)] = strings.TrimSpace(field)
+	}
+	return mapping, nil
+}
+
+// jobStatusCode tells a job that is still waiting in the queue apart from a finished one
+func jobStatusCode(job *cards.Job) int {
+	if job.GetStatus() == "queued" {
+		return http.StatusAccepted
+	}
+	return http.StatusOK
+}
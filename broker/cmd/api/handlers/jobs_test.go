@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"bytes"
+	"github.com/labstack/echo/v4"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func uploadContext(body []byte, contentType string) echo.Context {
+	req := httptest.NewRequest(http.MethodPost, "/v1/api/decks/1/import", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, contentType)
+	return echo.New().NewContext(req, httptest.NewRecorder())
+}
+
+func multipartUpload(t *testing.T, filename string, content []byte) ([]byte, string) {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := form.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return body.Bytes(), form.FormDataContentType()
+}
+
+func TestReadUpload(t *testing.T) {
+	content, filename, err := readUpload(uploadContext([]byte("front,back\n"), "text/csv"))
+	if err != nil || string(content) != "front,back\n" || filename != "" {
+		t.Errorf("raw body read as %q, %q, %v", content, filename, err)
+	}
+
+	body, contentType := multipartUpload(t, "deck.tsv", []byte("front\tback\n"))
+	content, filename, err = readUpload(uploadContext(body, contentType))
+	if err != nil || string(content) != "front\tback\n" || filename != "deck.tsv" {
+		t.Errorf("multipart form read as %q, %q, %v", content, filename, err)
+	}
+}
+
+func TestReadUploadRejectsLargeBodies(t *testing.T) {
+	justFits := bytes.Repeat([]byte("a"), maxUploadBytes)
+	oneOver := bytes.Repeat([]byte("a"), maxUploadBytes+1)
+	tooLarge := bytes.Repeat([]byte("a"), maxUploadBodyBytes+1)
+	multipartBody, multipartType := multipartUpload(t, "deck.csv", tooLarge)
+
+	if _, _, err := readUpload(uploadContext(justFits, "text/csv")); err != nil {
+		t.Errorf("file of maxUploadBytes returned %v", err)
+	}
+	tests := []struct {
+		name        string
+		body        []byte
+		contentType string
+	}{
+		{"file over the limit", oneOver, "text/csv"},
+		{"body over the limit", tooLarge, "text/csv"},
+		{"multipart form", multipartBody, multipartType},
+	}
+	for _, tt := range tests {
+		_, _, err := readUpload(uploadContext(tt.body, tt.contentType))
+		if err != errUploadTooLarge {
+			t.Errorf("%s: %v, want errUploadTooLarge", tt.name, err)
+		}
+	}
+}
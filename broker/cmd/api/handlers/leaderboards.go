@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/broker-service/cards"
+	"github.com/Salladin95/goErrorHandler"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+// LeaderboardDto is read from the query string, the metric is challenge or volume from the path
+type LeaderboardDto struct {
+	// global | following, global when empty, the deck routes always read the board of the deck
+	Scope string `query:"scope"`
+	// weeks before the current one, up to 4
+	WeeksAgo int32 `query:"weeksAgo"`
+	Offset   int32 `query:"offset"`
+	Limit    int32 `query:"limit"`
+	// Around returns the page centered on the user's rank instead of the offset
+	Around bool `query:"around"`
+}
+
+// GetLeaderboard responds with a page of the weekly leaderboard and the user's own rank,
+// of the deck in the path or of the scope in the query string
+func (bh *brokerHandlers) GetLeaderboard(c echo.Context) error {
+	var leaderboardDTO LeaderboardDto
+
+	// Read the query string and unmarshal it into the corresponding DTO
+	if err := c.Bind(&leaderboardDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+	scope := leaderboardDTO.Scope
+	if scope == "" {
+		scope = "global"
+	}
+	if c.Param("id") != "" {
+		scope = "deck"
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.GetLeaderboard(ctx, &cards.LeaderboardRequest{
+		Payload: &cards.LeaderboardPayload{
+			UserId:   getUserID(c),
+			Metric:   c.Param("metric"),
+			Scope:    scope,
+			DeckId:   c.Param("id"),
+			WeeksAgo: leaderboardDTO.WeeksAgo,
+			Offset:   leaderboardDTO.Offset,
+			Limit:    leaderboardDTO.Limit,
+			AroundMe: leaderboardDTO.Around,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// ListFollowing responds with the ids of the users ranked on the user's following leaderboards
+func (bh *brokerHandlers) ListFollowing(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.ListFollowing(ctx, &cards.FollowingRequest{Payload: &cards.FollowingPayload{UserId: getUserID(c)}})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// FollowUser adds the user in the path to the following leaderboards
+func (bh *brokerHandlers) FollowUser(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.FollowUser(ctx, &cards.FollowRequest{
+		Payload: &cards.FollowPayload{UserId: getUserID(c), FolloweeId: c.Param("userId")},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// UnfollowUser removes the user in the path from the following leaderboards
+func (bh *brokerHandlers) UnfollowUser(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.UnfollowUser(ctx, &cards.FollowRequest{
+		Payload: &cards.FollowPayload{UserId: getUserID(c), FolloweeId: c.Param("userId")},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
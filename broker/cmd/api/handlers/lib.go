@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/broker-service/cmd/api/middlewares"
 	"github.com/Salladin95/goErrorHandler"
 	"github.com/Salladin95/rmqtools"
 	"github.com/labstack/echo/v4"
@@ -45,3 +46,15 @@ func (bh *brokerHandlers) pushToQueueFromEndpoint(c echo.Context, key string) er
 	c.String(http.StatusOK, fmt.Sprintf("PUSHED TO QUEUE FROM %s", key))
 	return nil
 }
+
+// getUserID returns the id of the user authenticated by middlewares.Authenticate
+func getUserID(c echo.Context) string {
+	userID, _ := c.Get(middlewares.UserIDKey).(string)
+	return userID
+}
+
+// getUserEmail returns the email of the user authenticated by middlewares.Authenticate
+func getUserEmail(c echo.Context) string {
+	email, _ := c.Get(middlewares.UserEmailKey).(string)
+	return email
+}
@@ -16,6 +16,85 @@ const (
 type BrokerHandlersInterface interface {
 	SignIn(c echo.Context) error
 	SignUp(c echo.Context) error
+	GetProfile(c echo.Context) error
+	UpdateProfile(c echo.Context) error
+	VerifyEmail(c echo.Context) error
+	RefreshTokens(c echo.Context) error
+	RequestPasswordReset(c echo.Context) error
+	ResetPassword(c echo.Context) error
+	ChangePassword(c echo.Context) error
+	VerifySignIn(c echo.Context) error
+	EnrollTOTP(c echo.Context) error
+	ConfirmTOTP(c echo.Context) error
+	DisableTOTP(c echo.Context) error
+	OAuthRedirect(c echo.Context) error
+	OAuthCallback(c echo.Context) error
+	ListDecks(c echo.Context) error
+	CreateDeck(c echo.Context) error
+	GetDeck(c echo.Context) error
+	UpdateDeck(c echo.Context) error
+	DeleteDeck(c echo.Context) error
+	ListCards(c echo.Context) error
+	CreateCard(c echo.Context) error
+	GetCard(c echo.Context) error
+	UpdateCard(c echo.Context) error
+	DeleteCard(c echo.Context) error
+	SubmitReview(c echo.Context) error
+	GetDueCards(c echo.Context) error
+	StartQuiz(c echo.Context) error
+	GetQuizSession(c echo.Context) error
+	GetNextQuestion(c echo.Context) error
+	SubmitAnswer(c echo.Context) error
+	SkipQuestion(c echo.Context) error
+	PauseQuiz(c echo.Context) error
+	ResumeQuiz(c echo.Context) error
+	FinishQuiz(c echo.Context) error
+	GradeAnswer(c echo.Context) error
+	GetChallengeRecords(c echo.Context) error
+	ImportCards(c echo.Context) error
+	PreviewImport(c echo.Context) error
+	ExportCards(c echo.Context) error
+	GetJob(c echo.Context) error
+	DownloadJobResult(c echo.Context) error
+	Search(c echo.Context) error
+	Autocomplete(c echo.Context) error
+	ListFolders(c echo.Context) error
+	CreateFolder(c echo.Context) error
+	UpdateFolder(c echo.Context) error
+	DeleteFolder(c echo.Context) error
+	MoveDeck(c echo.Context) error
+	ListFilteredDecks(c echo.Context) error
+	CreateFilteredDeck(c echo.Context) error
+	GetFilteredDeck(c echo.Context) error
+	UpdateFilteredDeck(c echo.Context) error
+	DeleteFilteredDeck(c echo.Context) error
+	GetFilteredDueCards(c echo.Context) error
+	StartFilteredQuiz(c echo.Context) error
+	ListSharedDecks(c echo.Context) error
+	ListMembers(c echo.Context) error
+	UpdateMember(c echo.Context) error
+	RemoveMember(c echo.Context) error
+	ListInvitations(c echo.Context) error
+	CreateInvitation(c echo.Context) error
+	RevokeInvitation(c echo.Context) error
+	AcceptInvitation(c echo.Context) error
+	BrowseCatalog(c echo.Context) error
+	GetCatalogDeck(c echo.Context) error
+	ForkDeck(c echo.Context) error
+	PublishDeck(c echo.Context) error
+	UnpublishDeck(c echo.Context) error
+	GetUpstreamChanges(c echo.Context) error
+	MergeUpstream(c echo.Context) error
+	GetStudyStats(c echo.Context) error
+	GetReviewHeatmap(c echo.Context) error
+	GetReviewForecast(c echo.Context) error
+	GetStreak(c echo.Context) error
+	UpdateStudySettings(c echo.Context) error
+	GetAchievements(c echo.Context) error
+	GetLeaderboard(c echo.Context) error
+	ListFollowing(c echo.Context) error
+	FollowUser(c echo.Context) error
+	UnfollowUser(c echo.Context) error
 }
 
 type brokerHandlers struct {
@@ -30,14 +109,20 @@ func NewHandlers(cfg config.AppCfg, rabbit *amqp091.Connection) BrokerHandlersIn
 	}
 }
 
+// GetGRPCClientConn dials the auth service
 func (bh *brokerHandlers) GetGRPCClientConn() (*grpc.ClientConn, error) {
-	conn, err := grpc.Dial(bh.config.AUTH_SERVICE_URL, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
-	if err != nil {
-		return nil, goErrorHandler.OperationFailure("connect to gRPC", err)
-	}
+	return dialGRPC(bh.config.AUTH_SERVICE_URL)
+}
 
+// GetCardsClientConn dials the cards service
+func (bh *brokerHandlers) GetCardsClientConn() (*grpc.ClientConn, error) {
+	return dialGRPC(bh.config.CARDS_SERVICE_URL)
+}
+
+func dialGRPC(target string) (*grpc.ClientConn, error) {
+	conn, err := grpc.Dial(target, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
 	if err != nil {
-		return nil, goErrorHandler.OperationFailure("get auth client", err)
+		return nil, goErrorHandler.OperationFailure("connect to gRPC", err)
 	}
 	return conn, nil
 }
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/broker-service/auth"
+	"github.com/Salladin95/goErrorHandler"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+// OAuthRedirect sends the user to the consent page of the identity provider
+func (bh *brokerHandlers) OAuthRedirect(c echo.Context) error {
+	clientConn, err := bh.GetGRPCClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ah := auth.NewAuthClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ah.StartOAuth(ctx, &auth.StartOAuthRequest{
+		Payload: &auth.StartOAuthPayload{Provider: c.Param("provider")},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.Redirect(http.StatusFound, res.GetAuthorizationUrl())
+}
+
+// OAuthCallback is where the identity provider sends the user back with the authorization code
+func (bh *brokerHandlers) OAuthCallback(c echo.Context) error {
+	if providerError := c.QueryParam("error"); providerError != "" {
+		return goErrorHandler.NewError(goErrorHandler.ErrUnauthorized, fmt.Errorf("identity provider declined the sign-in: %s", providerError))
+	}
+
+	clientConn, err := bh.GetGRPCClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ah := auth.NewAuthClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	res, err := ah.CompleteOAuth(ctx, &auth.CompleteOAuthRequest{
+		Payload: &auth.CompleteOAuthPayload{
+			Provider: c.Param("provider"),
+			Code:     c.QueryParam("code"),
+			State:    c.QueryParam("state"),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
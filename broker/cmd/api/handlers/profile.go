@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/broker-service/auth"
+	"github.com/Salladin95/goErrorHandler"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+// UpdateProfileDto leaves the fields missing from the body as they are
+type UpdateProfileDto struct {
+	Name     string `json:"name"`
+	Birthday string `json:"birthday"`
+	// Timezone is an IANA name, e.g. "Europe/Berlin", study days and streaks are counted in it
+	Timezone string `json:"timezone"`
+}
+
+func (bh *brokerHandlers) GetProfile(c echo.Context) error {
+	clientConn, err := bh.GetGRPCClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ah := auth.NewAuthClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ah.GetProfile(ctx, &auth.GetProfileRequest{
+		Payload: &auth.GetProfilePayload{UserId: getUserID(c)},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res.GetProfile())
+}
+
+func (bh *brokerHandlers) UpdateProfile(c echo.Context) error {
+	var updateProfileDTO UpdateProfileDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&updateProfileDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetGRPCClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ah := auth.NewAuthClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ah.UpdateProfile(ctx, &auth.UpdateProfileRequest{
+		Payload: &auth.UpdateProfilePayload{
+			UserId:   getUserID(c),
+			Name:     updateProfileDTO.Name,
+			Birthday: updateProfileDTO.Birthday,
+			Timezone: updateProfileDTO.Timezone,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res.GetProfile())
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/broker-service/cards"
+	"github.com/Salladin95/goErrorHandler"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc"
+	"net/http"
+	"time"
+)
+
+type StartQuizDto struct {
+	CardCount int32 `json:"cardCount"`
+	// sequential | random
+	Order string `json:"order"`
+	// flashcard | multiple-choice | typed | matching | true-false, challenges can't use flashcard
+	Modes       []string `json:"modes"`
+	ChoiceCount int32    `json:"choiceCount"`
+	Seed        int64    `json:"seed"`
+	// TimeLimitSeconds starts a timed challenge instead of a regular quiz
+	TimeLimitSeconds int32 `json:"timeLimitSeconds"`
+}
+
+type SubmitAnswerDto struct {
+	QuestionIndex int32  `json:"questionIndex"`
+	Answer        string `json:"answer"`
+}
+
+type SkipQuestionDto struct {
+	QuestionIndex int32 `json:"questionIndex"`
+}
+
+func (bh *brokerHandlers) StartQuiz(c echo.Context) error {
+	return bh.startQuiz(c, &cards.StartQuizPayload{DeckId: c.Param("id")})
+}
+
+// startQuiz starts a quiz on the deck or the filtered deck set in the payload
+func (bh *brokerHandlers) startQuiz(c echo.Context, payload *cards.StartQuizPayload) error {
+	var startQuizDTO StartQuizDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&startQuizDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	payload.UserId = getUserID(c)
+	payload.CardCount = startQuizDTO.CardCount
+	payload.Order = startQuizDTO.Order
+	payload.Modes = startQuizDTO.Modes
+	payload.ChoiceCount = startQuizDTO.ChoiceCount
+	payload.Seed = startQuizDTO.Seed
+	payload.TimeLimitSeconds = startQuizDTO.TimeLimitSeconds
+	res, err := ch.StartQuiz(ctx, &cards.StartQuizRequest{Payload: payload})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+func (bh *brokerHandlers) GetQuizSession(c echo.Context) error {
+	return bh.quizSessionCall(c, cards.CardsClient.GetQuizSession)
+}
+
+func (bh *brokerHandlers) GetNextQuestion(c echo.Context) error {
+	return bh.quizSessionCall(c, cards.CardsClient.GetNextQuestion)
+}
+
+func (bh *brokerHandlers) PauseQuiz(c echo.Context) error {
+	return bh.quizSessionCall(c, cards.CardsClient.PauseQuiz)
+}
+
+func (bh *brokerHandlers) ResumeQuiz(c echo.Context) error {
+	return bh.quizSessionCall(c, cards.CardsClient.ResumeQuiz)
+}
+
+func (bh *brokerHandlers) FinishQuiz(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.FinishQuiz(ctx, &cards.QuizSessionRequest{
+		Payload: &cards.QuizSessionPayload{UserId: getUserID(c), SessionId: c.Param("sessionId")},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res.GetSummary())
+}
+
+func (bh *brokerHandlers) SubmitAnswer(c echo.Context) error {
+	var submitAnswerDTO SubmitAnswerDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&submitAnswerDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.SubmitAnswer(ctx, &cards.SubmitAnswerRequest{
+		Payload: &cards.SubmitAnswerPayload{
+			UserId:        getUserID(c),
+			SessionId:     c.Param("sessionId"),
+			QuestionIndex: submitAnswerDTO.QuestionIndex,
+			Answer:        submitAnswerDTO.Answer,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (bh *brokerHandlers) SkipQuestion(c echo.Context) error {
+	var skipQuestionDTO SkipQuestionDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&skipQuestionDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.SkipQuestion(ctx, &cards.SkipQuestionRequest{
+		Payload: &cards.SkipQuestionPayload{
+			UserId:        getUserID(c),
+			SessionId:     c.Param("sessionId"),
+			QuestionIndex: skipQuestionDTO.QuestionIndex,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+type quizSessionMethod func(cards.CardsClient, context.Context, *cards.QuizSessionRequest, ...grpc.CallOption) (*cards.QuizSessionResponse, error)
+
+// quizSessionCall calls one of the session RPCs that only need the session id
+func (bh *brokerHandlers) quizSessionCall(c echo.Context, method quizSessionMethod) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := method(ch, ctx, &cards.QuizSessionRequest{
+		Payload: &cards.QuizSessionPayload{UserId: getUserID(c), SessionId: c.Param("sessionId")},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/broker-service/cards"
+	"github.com/Salladin95/goErrorHandler"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+type SubmitReviewDto struct {
+	// 1 - again, 2 - hard, 3 - good, 4 - easy
+	Grade int32 `json:"grade" validate:"required,min=1,max=4"`
+	// AnswerMs is how long the user took to answer, it feeds the average answer time of the stats
+	AnswerMs int32 `json:"answerMs" validate:"min=0"`
+}
+
+func (bh *brokerHandlers) SubmitReview(c echo.Context) error {
+	var submitReviewDTO SubmitReviewDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&submitReviewDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.SubmitReview(ctx, &cards.SubmitReviewRequest{
+		Payload: &cards.SubmitReviewPayload{
+			UserId:   getUserID(c),
+			DeckId:   c.Param("id"),
+			CardId:   c.Param("cardId"),
+			Grade:    submitReviewDTO.Grade,
+			AnswerMs: submitReviewDTO.AnswerMs,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res.GetState())
+}
+
+func (bh *brokerHandlers) GetDueCards(c echo.Context) error {
+	return bh.getDueCards(c, &cards.GetDueCardsPayload{UserId: getUserID(c), DeckId: c.Param("id")})
+}
+
+// getDueCards returns the study queue of the deck or the filtered deck set in the payload
+func (bh *brokerHandlers) getDueCards(c echo.Context, payload *cards.GetDueCardsPayload) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.GetDueCards(ctx, &cards.GetDueCardsRequest{Payload: payload})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
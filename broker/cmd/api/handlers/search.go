@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/broker-service/cards"
+	"github.com/Salladin95/goErrorHandler"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+// SearchDto is read from the query string, q holds the words to look for
+type SearchDto struct {
+	Query string `query:"q"`
+	// deck | card, both when empty
+	Kind   string `query:"kind"`
+	DeckID string `query:"deckId"`
+	Limit  int32  `query:"limit"`
+	Offset int32  `query:"offset"`
+}
+
+type AutocompleteDto struct {
+	Prefix string `query:"q"`
+	Limit  int32  `query:"limit"`
+}
+
+// Search responds with the user's decks and cards matching the words, best matches first.
+// Decks shared with the user are not searched, only their owner finds them.
+// Snippets in the highlights are HTML-escaped with the matches wrapped in <mark>.
+func (bh *brokerHandlers) Search(c echo.Context) error {
+	var searchDTO SearchDto
+
+	// Read the query string and unmarshal it into the corresponding DTO
+	if err := c.Bind(&searchDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.Search(ctx, &cards.SearchRequest{
+		Payload: &cards.SearchPayload{
+			UserId: getUserID(c),
+			Query:  searchDTO.Query,
+			Kind:   searchDTO.Kind,
+			DeckId: searchDTO.DeckID,
+			Limit:  searchDTO.Limit,
+			Offset: searchDTO.Offset,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// Autocomplete completes the last word typed into the search box with deck titles, card fronts and tags
+func (bh *brokerHandlers) Autocomplete(c echo.Context) error {
+	var autocompleteDTO AutocompleteDto
+
+	// Read the query string and unmarshal it into the corresponding DTO
+	if err := c.Bind(&autocompleteDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := ch.Autocomplete(ctx, &cards.AutocompleteRequest{
+		Payload: &cards.AutocompletePayload{
+			UserId: getUserID(c),
+			Prefix: autocompleteDTO.Prefix,
+			Limit:  autocompleteDTO.Limit,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
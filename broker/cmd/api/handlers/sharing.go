@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/broker-service/cards"
+	"github.com/Salladin95/goErrorHandler"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+type InvitationDto struct {
+	// Role is viewer, editor or owner
+	Role string `json:"role"`
+	// Email mails the invitation to one person, without it the response holds the token of a link anyone can join with
+	Email string `json:"email"`
+	// ExpiresInHours is 1 to 720, 0 uses the default of the cards service
+	ExpiresInHours int32 `json:"expiresInHours"`
+}
+
+type AcceptInvitationDto struct {
+	Token string `json:"token"`
+}
+
+type MemberRoleDto struct {
+	Role string `json:"role"`
+}
+
+func (bh *brokerHandlers) ListSharedDecks(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.ListSharedDecks(ctx, &cards.ListSharedDecksRequest{
+		Payload: &cards.ListSharedDecksPayload{UserId: getUserID(c)},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (bh *brokerHandlers) ListMembers(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.ListMembers(ctx, &cards.DeckSharingRequest{
+		Payload: &cards.DeckSharingPayload{UserId: getUserID(c), DeckId: c.Param("id")},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (bh *brokerHandlers) UpdateMember(c echo.Context) error {
+	var memberDTO MemberRoleDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&memberDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.UpdateMember(ctx, &cards.UpdateMemberRequest{
+		Payload: &cards.UpdateMemberPayload{
+			UserId:   getUserID(c),
+			DeckId:   c.Param("id"),
+			MemberId: c.Param("memberId"),
+			Role:     memberDTO.Role,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res.GetMember())
+}
+
+// RemoveMember removes a member of the deck, members remove themselves to leave it
+func (bh *brokerHandlers) RemoveMember(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.RemoveMember(ctx, &cards.RemoveMemberRequest{
+		Payload: &cards.RemoveMemberPayload{UserId: getUserID(c), DeckId: c.Param("id"), MemberId: c.Param("memberId")},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, JsonResponse{Message: res.GetMessage()})
+}
+
+func (bh *brokerHandlers) ListInvitations(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.ListInvitations(ctx, &cards.DeckSharingRequest{
+		Payload: &cards.DeckSharingPayload{UserId: getUserID(c), DeckId: c.Param("id")},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (bh *brokerHandlers) CreateInvitation(c echo.Context) error {
+	var invitationDTO InvitationDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&invitationDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.CreateInvitation(ctx, &cards.CreateInvitationRequest{
+		Payload: &cards.CreateInvitationPayload{
+			UserId:         getUserID(c),
+			DeckId:         c.Param("id"),
+			Role:           invitationDTO.Role,
+			Email:          invitationDTO.Email,
+			InviterEmail:   getUserEmail(c),
+			ExpiresInHours: invitationDTO.ExpiresInHours,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+func (bh *brokerHandlers) RevokeInvitation(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.RevokeInvitation(ctx, &cards.RevokeInvitationRequest{
+		Payload: &cards.RevokeInvitationPayload{
+			UserId:       getUserID(c),
+			DeckId:       c.Param("id"),
+			InvitationId: c.Param("invitationId"),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, JsonResponse{Message: res.GetMessage()})
+}
+
+// AcceptInvitation joins the deck of an invitation link, email invitations are accepted by the user they were sent to
+func (bh *brokerHandlers) AcceptInvitation(c echo.Context) error {
+	var acceptDTO AcceptInvitationDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&acceptDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.AcceptInvitation(ctx, &cards.AcceptInvitationRequest{
+		Payload: &cards.AcceptInvitationPayload{UserId: getUserID(c), Email: getUserEmail(c), Token: acceptDTO.Token},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res.GetSharedDeck())
+}
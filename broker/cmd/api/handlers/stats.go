@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/broker-service/cards"
+	"github.com/Salladin95/goErrorHandler"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+// StudyStatsDto is read from the query string, the days are counted in the IANA timezone tz, the profile timezone when empty
+type StudyStatsDto struct {
+	// inclusive dates as YYYY-MM-DD
+	From     string `query:"from"`
+	To       string `query:"to"`
+	Timezone string `query:"tz"`
+}
+
+type ReviewForecastDto struct {
+	Days     int32  `query:"days"`
+	Timezone string `query:"tz"`
+}
+
+// GetStudyStats responds with the reviews per day, retention, average answer time and card counts,
+// of the deck in the path or of every deck of the user
+func (bh *brokerHandlers) GetStudyStats(c echo.Context) error {
+	var statsDTO StudyStatsDto
+
+	// Read the query string and unmarshal it into the corresponding DTO
+	if err := c.Bind(&statsDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.GetStudyStats(ctx, &cards.StudyStatsRequest{Payload: toStudyStatsPayload(c, statsDTO)})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetReviewHeatmap responds with the reviews of every day of the range, a year when it's not set
+func (bh *brokerHandlers) GetReviewHeatmap(c echo.Context) error {
+	var statsDTO StudyStatsDto
+
+	// Read the query string and unmarshal it into the corresponding DTO
+	if err := c.Bind(&statsDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.GetReviewHeatmap(ctx, &cards.StudyStatsRequest{Payload: toStudyStatsPayload(c, statsDTO)})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetReviewForecast responds with the number of cards falling due in each of the coming days
+func (bh *brokerHandlers) GetReviewForecast(c echo.Context) error {
+	var forecastDTO ReviewForecastDto
+
+	// Read the query string and unmarshal it into the corresponding DTO
+	if err := c.Bind(&forecastDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.GetReviewForecast(ctx, &cards.ReviewForecastRequest{
+		Payload: &cards.ReviewForecastPayload{
+			UserId:   getUserID(c),
+			DeckId:   c.Param("id"),
+			Days:     forecastDTO.Days,
+			Timezone: forecastDTO.Timezone,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func toStudyStatsPayload(c echo.Context, statsDTO StudyStatsDto) *cards.StudyStatsPayload {
+	return &cards.StudyStatsPayload{
+		UserId:   getUserID(c),
+		DeckId:   c.Param("id"),
+		From:     statsDTO.From,
+		To:       statsDTO.To,
+		Timezone: statsDTO.Timezone,
+	}
+}
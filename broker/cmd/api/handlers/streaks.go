@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/broker-service/cards"
+	"github.com/Salladin95/goErrorHandler"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+// UpdateStudySettingsDto sets the daily goal, the timezone of the days is the one of the profile
+type UpdateStudySettingsDto struct {
+	// hour in the user's timezone a study day starts at, 0 - 23
+	RolloverHour int32 `json:"rolloverHour"`
+	// cards | minutes
+	GoalKind   string `json:"goalKind"`
+	GoalTarget int32  `json:"goalTarget"`
+}
+
+// GetStreak responds with the streak of the user, its freezes and the progress towards today's goal
+func (bh *brokerHandlers) GetStreak(c echo.Context) error {
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.GetStreak(ctx, &cards.StreakRequest{Payload: &cards.StreakPayload{UserId: getUserID(c)}})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// UpdateStudySettings changes the daily goal and the hour study days start at
+func (bh *brokerHandlers) UpdateStudySettings(c echo.Context) error {
+	var settingsDTO UpdateStudySettingsDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&settingsDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetCardsClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ch := cards.NewCardsClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ch.UpdateStudySettings(ctx, &cards.UpdateStudySettingsRequest{
+		Payload: &cards.UpdateStudySettingsPayload{
+			UserId:       getUserID(c),
+			RolloverHour: settingsDTO.RolloverHour,
+			GoalKind:     settingsDTO.GoalKind,
+			GoalTarget:   settingsDTO.GoalTarget,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/broker-service/auth"
+	"github.com/Salladin95/goErrorHandler"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+type VerifySignInDto struct {
+	ChallengeToken string `json:"challengeToken" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
+
+type ConfirmTOTPDto struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type DisableTOTPDto struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+func (bh *brokerHandlers) VerifySignIn(c echo.Context) error {
+	var verifySignInDTO VerifySignInDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&verifySignInDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetGRPCClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ah := auth.NewAuthClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ah.VerifySignIn(ctx, &auth.VerifySignInRequest{
+		Payload: &auth.VerifySignInPayload{
+			ChallengeToken: verifySignInDTO.ChallengeToken,
+			Code:           verifySignInDTO.Code,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (bh *brokerHandlers) EnrollTOTP(c echo.Context) error {
+	clientConn, err := bh.GetGRPCClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ah := auth.NewAuthClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ah.EnrollTOTP(ctx, &auth.EnrollTOTPRequest{
+		Payload: &auth.EnrollTOTPPayload{UserId: getUserID(c)},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (bh *brokerHandlers) ConfirmTOTP(c echo.Context) error {
+	var confirmTOTPDTO ConfirmTOTPDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&confirmTOTPDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetGRPCClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ah := auth.NewAuthClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ah.ConfirmTOTP(ctx, &auth.ConfirmTOTPRequest{
+		Payload: &auth.ConfirmTOTPPayload{UserId: getUserID(c), Code: confirmTOTPDTO.Code},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (bh *brokerHandlers) DisableTOTP(c echo.Context) error {
+	var disableTOTPDTO DisableTOTPDto
+
+	// Read the request body and unmarshal it into the corresponding DTO
+	if err := c.Bind(&disableTOTPDTO); err != nil {
+		return goErrorHandler.BindRequestToBodyFailure(err)
+	}
+
+	clientConn, err := bh.GetGRPCClientConn()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+	ah := auth.NewAuthClient(clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := ah.DisableTOTP(ctx, &auth.DisableTOTPRequest{
+		Payload: &auth.DisableTOTPPayload{
+			UserId:   getUserID(c),
+			Password: disableTOTPDTO.Password,
+			Code:     disableTOTPDTO.Code,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, JsonResponse{Message: res.GetMessage()})
+}
@@ -0,0 +1,50 @@
+package middlewares
+
+import (
+	"github.com/Salladin95/goErrorHandler"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"strings"
+)
+
+const (
+	// UserIDKey is the echo context key holding the id of the authenticated user
+	UserIDKey = "userID"
+	// UserEmailKey is the echo context key holding the email of the authenticated user
+	UserEmailKey = "userEmail"
+	// ScopeKey is the echo context key holding the scope of the access token
+	ScopeKey = "scope"
+)
+
+type accessClaims struct {
+	Email string `json:"email"`
+	Scope string `json:"scope"`
+	jwt.RegisteredClaims
+}
+
+// Authenticate verifies the bearer access token issued by the auth service
+// and puts the user id, the user email and the token scope into the echo context
+func Authenticate(secret string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Request().Header.Get(echo.HeaderAuthorization)
+			tokenString, found := strings.CutPrefix(header, "Bearer ")
+			if !found || tokenString == "" {
+				return goErrorHandler.Unauthorized()
+			}
+
+			var claims accessClaims
+			_, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
+				return []byte(secret), nil
+			}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+			if err != nil || claims.Subject == "" {
+				return goErrorHandler.Unauthorized()
+			}
+
+			c.Set(UserIDKey, claims.Subject)
+			c.Set(UserEmailKey, claims.Email)
+			c.Set(ScopeKey, claims.Scope)
+			return next(c)
+		}
+	}
+}
@@ -1,11 +1,51 @@
 package middlewares
 
 import (
+	"fmt"
 	"github.com/Salladin95/goErrorHandler"
 	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
 )
 
+// grpcCodeToStatus maps gRPC status codes returned by the services to http statuses
+var grpcCodeToStatus = map[codes.Code]int{
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.Aborted:            http.StatusConflict,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.FailedPrecondition: http.StatusForbidden,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+}
+
+func mapGRPCErrorToAPIError(err error) (goErrorHandler.APIError, bool) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return goErrorHandler.APIError{}, false
+	}
+	httpStatus, ok := grpcCodeToStatus[st.Code()]
+	if !ok {
+		httpStatus = http.StatusInternalServerError
+	}
+	return goErrorHandler.APIError{Status: httpStatus, Message: st.Message()}, true
+}
+
 func errorHandler(err error, c echo.Context) {
+	if apiError, ok := mapGRPCErrorToAPIError(err); ok {
+		c.String(apiError.Status, apiError.Message)
+		return
+	}
+	if httpError, ok := err.(*echo.HTTPError); ok {
+		c.String(httpError.Code, fmt.Sprint(httpError.Message))
+		return
+	}
 	apiError := goErrorHandler.MapServiceErrorToAPIError(err)
 	c.String(apiError.Status, apiError.Message)
 }
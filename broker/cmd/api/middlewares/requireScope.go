@@ -0,0 +1,22 @@
+package middlewares
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// ScopeFull is the scope of access tokens issued to users with verified emails
+const ScopeFull = "full"
+
+// RequireScope rejects requests whose access token was issued with another scope,
+// it must run after Authenticate
+func RequireScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if tokenScope, _ := c.Get(ScopeKey).(string); tokenScope != scope {
+				return echo.NewHTTPError(http.StatusForbidden, "access token scope is insufficient, verify your email first")
+			}
+			return next(c)
+		}
+	}
+}
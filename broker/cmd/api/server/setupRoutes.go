@@ -2,6 +2,7 @@ package server
 
 import (
 	"github.com/Salladin95/card-quizzler-microservices/broker-service/cmd/api/handlers"
+	"github.com/Salladin95/card-quizzler-microservices/broker-service/cmd/api/middlewares"
 )
 
 func (app *App) setupRoutes() {
@@ -11,4 +12,107 @@ func (app *App) setupRoutes() {
 	// ****************** AUTH **********************
 	routes.POST("/auth/sign-in", bHandlers.SignIn)
 	routes.POST("/auth/sign-up", bHandlers.SignUp)
+	routes.POST("/auth/verify-sign-in", bHandlers.VerifySignIn)
+	routes.POST("/auth/verify-email", bHandlers.VerifyEmail)
+	routes.POST("/auth/refresh", bHandlers.RefreshTokens)
+	routes.POST("/auth/request-password-reset", bHandlers.RequestPasswordReset)
+	routes.POST("/auth/reset-password", bHandlers.ResetPassword)
+	routes.GET("/auth/oauth/:provider/redirect", bHandlers.OAuthRedirect)
+	routes.GET("/auth/oauth/:provider/callback", bHandlers.OAuthCallback)
+
+	// routes below require a valid access token
+	authenticate := middlewares.Authenticate(app.config.JWT_SECRET)
+	routes.POST("/auth/change-password", bHandlers.ChangePassword, authenticate)
+	routes.GET("/auth/profile", bHandlers.GetProfile, authenticate)
+	routes.PATCH("/auth/profile", bHandlers.UpdateProfile, authenticate)
+	routes.POST("/auth/totp/enroll", bHandlers.EnrollTOTP, authenticate)
+	routes.POST("/auth/totp/confirm", bHandlers.ConfirmTOTP, authenticate)
+	routes.POST("/auth/totp/disable", bHandlers.DisableTOTP, authenticate)
+
+	// ****************** CARDS **********************
+	decks := routes.Group("/decks", authenticate, middlewares.RequireScope(middlewares.ScopeFull))
+	decks.GET("", bHandlers.ListDecks)
+	decks.GET("/shared", bHandlers.ListSharedDecks)
+	decks.POST("", bHandlers.CreateDeck)
+	decks.GET("/:id", bHandlers.GetDeck)
+	decks.PUT("/:id", bHandlers.UpdateDeck)
+	decks.DELETE("/:id", bHandlers.DeleteDeck)
+	decks.GET("/:id/cards", bHandlers.ListCards)
+	decks.POST("/:id/cards", bHandlers.CreateCard)
+	decks.GET("/:id/cards/:cardId", bHandlers.GetCard)
+	decks.PUT("/:id/cards/:cardId", bHandlers.UpdateCard)
+	decks.DELETE("/:id/cards/:cardId", bHandlers.DeleteCard)
+	decks.POST("/import/preview", bHandlers.PreviewImport)
+	decks.POST("/:id/import", bHandlers.ImportCards)
+	decks.POST("/:id/export", bHandlers.ExportCards)
+	decks.PUT("/:id/folder", bHandlers.MoveDeck)
+	decks.GET("/:id/members", bHandlers.ListMembers)
+	decks.PUT("/:id/members/:memberId", bHandlers.UpdateMember)
+	decks.DELETE("/:id/members/:memberId", bHandlers.RemoveMember)
+	decks.GET("/:id/invitations", bHandlers.ListInvitations)
+	decks.POST("/:id/invitations", bHandlers.CreateInvitation)
+	decks.DELETE("/:id/invitations/:invitationId", bHandlers.RevokeInvitation)
+	invitations := routes.Group("/invitations", authenticate, middlewares.RequireScope(middlewares.ScopeFull))
+	invitations.POST("/accept", bHandlers.AcceptInvitation)
+	decks.PUT("/:id/publish", bHandlers.PublishDeck)
+	decks.DELETE("/:id/publish", bHandlers.UnpublishDeck)
+	decks.GET("/:id/upstream", bHandlers.GetUpstreamChanges)
+	decks.POST("/:id/upstream/merge", bHandlers.MergeUpstream)
+	catalog := routes.Group("/catalog", authenticate, middlewares.RequireScope(middlewares.ScopeFull))
+	catalog.GET("", bHandlers.BrowseCatalog)
+	catalog.GET("/:id", bHandlers.GetCatalogDeck)
+	catalog.POST("/:id/fork", bHandlers.ForkDeck)
+	folders := routes.Group("/folders", authenticate, middlewares.RequireScope(middlewares.ScopeFull))
+	folders.GET("", bHandlers.ListFolders)
+	folders.POST("", bHandlers.CreateFolder)
+	folders.PUT("/:id", bHandlers.UpdateFolder)
+	folders.DELETE("/:id", bHandlers.DeleteFolder)
+	filteredDecks := routes.Group("/filtered-decks", authenticate, middlewares.RequireScope(middlewares.ScopeFull))
+	filteredDecks.GET("", bHandlers.ListFilteredDecks)
+	filteredDecks.POST("", bHandlers.CreateFilteredDeck)
+	filteredDecks.GET("/:id", bHandlers.GetFilteredDeck)
+	filteredDecks.PUT("/:id", bHandlers.UpdateFilteredDeck)
+	filteredDecks.DELETE("/:id", bHandlers.DeleteFilteredDeck)
+	jobs := routes.Group("/jobs", authenticate, middlewares.RequireScope(middlewares.ScopeFull))
+	jobs.GET("/:jobId", bHandlers.GetJob)
+	jobs.GET("/:jobId/download", bHandlers.DownloadJobResult)
+	search := routes.Group("/search", authenticate, middlewares.RequireScope(middlewares.ScopeFull))
+	search.GET("", bHandlers.Search)
+	search.GET("/autocomplete", bHandlers.Autocomplete)
+	// ****************** STUDY **********************
+	decks.GET("/:id/due", bHandlers.GetDueCards)
+	decks.POST("/:id/cards/:cardId/review", bHandlers.SubmitReview)
+	decks.POST("/:id/cards/:cardId/grade", bHandlers.GradeAnswer)
+	decks.POST("/:id/quiz", bHandlers.StartQuiz)
+	decks.GET("/:id/challenges/records", bHandlers.GetChallengeRecords)
+	// the same handlers serve the stats of a deck and of every deck of the user
+	decks.GET("/:id/stats", bHandlers.GetStudyStats)
+	decks.GET("/:id/stats/heatmap", bHandlers.GetReviewHeatmap)
+	decks.GET("/:id/stats/forecast", bHandlers.GetReviewForecast)
+	stats := routes.Group("/stats", authenticate, middlewares.RequireScope(middlewares.ScopeFull))
+	stats.GET("", bHandlers.GetStudyStats)
+	stats.GET("/heatmap", bHandlers.GetReviewHeatmap)
+	stats.GET("/forecast", bHandlers.GetReviewForecast)
+	stats.GET("/streak", bHandlers.GetStreak)
+	stats.PUT("/goal", bHandlers.UpdateStudySettings)
+	routes.GET("/achievements", bHandlers.GetAchievements, authenticate, middlewares.RequireScope(middlewares.ScopeFull))
+	filteredDecks.GET("/:id/due", bHandlers.GetFilteredDueCards)
+	filteredDecks.POST("/:id/quiz", bHandlers.StartFilteredQuiz)
+	quiz := routes.Group("/quiz", authenticate, middlewares.RequireScope(middlewares.ScopeFull))
+	quiz.GET("/:sessionId", bHandlers.GetQuizSession)
+	quiz.GET("/:sessionId/next", bHandlers.GetNextQuestion)
+	quiz.POST("/:sessionId/answer", bHandlers.SubmitAnswer)
+	quiz.POST("/:sessionId/skip", bHandlers.SkipQuestion)
+	quiz.POST("/:sessionId/pause", bHandlers.PauseQuiz)
+	quiz.POST("/:sessionId/resume", bHandlers.ResumeQuiz)
+	quiz.POST("/:sessionId/finish", bHandlers.FinishQuiz)
+	// ****************** LEADERBOARDS **********************
+	// metric is challenge or volume, the deck route ranks the members of the deck
+	decks.GET("/:id/leaderboards/:metric", bHandlers.GetLeaderboard)
+	leaderboards := routes.Group("/leaderboards", authenticate, middlewares.RequireScope(middlewares.ScopeFull))
+	leaderboards.GET("/:metric", bHandlers.GetLeaderboard)
+	following := routes.Group("/following", authenticate, middlewares.RequireScope(middlewares.ScopeFull))
+	following.GET("", bHandlers.ListFollowing)
+	following.PUT("/:userId", bHandlers.FollowUser)
+	following.DELETE("/:userId", bHandlers.UnfollowUser)
 }
@@ -0,0 +1,126 @@
+// Package achievements declares the badges users earn. The rules live in rules.json, every achievement lists
+// conditions on counters of the user's activity and is awarded once all of them are met:
+//
+//	{
+//	  "id": "reviews-1000",
+//	  "title": "Thousand Cards",
+//	  "description": "Review 1000 cards",
+//	  "icon": "cards-gold",
+//	  "conditions": [{"counter": "reviews", "atLeast": 1000}]
+//	}
+//
+// Counters are kept for every user whether a rule uses them or not, so an achievement added later is
+// awarded to the users already meeting it. The id of an achievement must never change, it's stored with
+// the badges.
+package achievements
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// CounterReviews counts the answered cards, CounterNewCards the ones answered for the first time
+	CounterReviews  = "reviews"
+	CounterNewCards = "new_cards"
+	// CounterSessions counts the completed quiz sessions, CounterPerfectSessions the ones without a mistake
+	CounterSessions        = "sessions"
+	CounterPerfectSessions = "perfect_sessions"
+	// CounterLongestStreak is raised at the streak milestones, rules on it use milestone lengths, e.g. 7 or 30
+	CounterLongestStreak = "longest_streak"
+	// CounterPublishedDecks counts the decks the user published to the catalog, republishing one counts once
+	CounterPublishedDecks = "published_decks"
+)
+
+// counters tells whether each counter keeps the largest value reported, the others add up
+var counters = map[string]bool{
+	CounterReviews:         false,
+	CounterNewCards:        false,
+	CounterSessions:        false,
+	CounterPerfectSessions: false,
+	CounterLongestStreak:   true,
+	CounterPublishedDecks:  false,
+}
+
+//go:embed rules.json
+var rulesJSON []byte
+
+var builtin = MustParse(rulesJSON)
+
+type Condition struct {
+	Counter string `json:"counter"`
+	AtLeast int    `json:"atLeast"`
+}
+
+type Achievement struct {
+	ID          string      `json:"id"`
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	Icon        string      `json:"icon"`
+	Conditions  []Condition `json:"conditions"`
+}
+
+// Met reports whether the counters of a user meet every condition, missing counters are 0
+func (a Achievement) Met(counters map[string]int) bool {
+	for _, condition := range a.Conditions {
+		if counters[condition.Counter] < condition.AtLeast {
+			return false
+		}
+	}
+	return true
+}
+
+// Builtin returns the achievements of rules.json in their order, callers must not modify them
+func Builtin() []Achievement {
+	return builtin
+}
+
+// Maximum reports whether the counter keeps the largest value reported instead of adding them up
+func Maximum(counter string) bool {
+	return counters[counter]
+}
+
+// Parse reads and validates a list of achievements
+func Parse(data []byte) ([]Achievement, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var list []Achievement
+	if err := decoder.Decode(&list); err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]bool, len(list))
+	for i, achievement := range list {
+		switch {
+		case achievement.ID == "":
+			return nil, fmt.Errorf("achievement %d: missing id", i)
+		case ids[achievement.ID]:
+			return nil, fmt.Errorf("achievement %s: duplicate id", achievement.ID)
+		case achievement.Title == "":
+			return nil, fmt.Errorf("achievement %s: missing title", achievement.ID)
+		case len(achievement.Conditions) == 0:
+			return nil, fmt.Errorf("achievement %s: no conditions", achievement.ID)
+		}
+		for _, condition := range achievement.Conditions {
+			if _, ok := counters[condition.Counter]; !ok {
+				return nil, fmt.Errorf("achievement %s: unknown counter %q", achievement.ID, condition.Counter)
+			}
+			if condition.AtLeast < 1 {
+				return nil, fmt.Errorf("achievement %s: %s must be at least 1", achievement.ID, condition.Counter)
+			}
+		}
+		ids[achievement.ID] = true
+	}
+	return list, nil
+}
+
+// MustParse is Parse for rules embedded in the binary, it panics when they are invalid
+func MustParse(data []byte) []Achievement {
+	list, err := Parse(data)
+	if err != nil {
+		panic(fmt.Sprintf("achievements: %v", err))
+	}
+	return list
+}
@@ -0,0 +1,60 @@
+package achievements
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuiltin(t *testing.T) {
+	if len(Builtin()) == 0 {
+		t.Fatal("no builtin achievements")
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		err  string
+	}{
+		{"valid", `[{"id": "a", "title": "A", "conditions": [{"counter": "reviews", "atLeast": 5}]}]`, ""},
+		{"missing id", `[{"title": "A", "conditions": [{"counter": "reviews", "atLeast": 5}]}]`, "missing id"},
+		{"duplicate id", `[
+			{"id": "a", "title": "A", "conditions": [{"counter": "reviews", "atLeast": 5}]},
+			{"id": "a", "title": "B", "conditions": [{"counter": "sessions", "atLeast": 5}]}
+		]`, "duplicate id"},
+		{"no conditions", `[{"id": "a", "title": "A", "conditions": []}]`, "no conditions"},
+		{"unknown counter", `[{"id": "a", "title": "A", "conditions": [{"counter": "likes", "atLeast": 5}]}]`, "unknown counter"},
+		{"zero threshold", `[{"id": "a", "title": "A", "conditions": [{"counter": "reviews", "atLeast": 0}]}]`, "at least 1"},
+		{"unknown field", `[{"id": "a", "title": "A", "points": 10, "conditions": [{"counter": "reviews", "atLeast": 5}]}]`, "unknown field"},
+	}
+	for _, tt := range tests {
+		_, err := Parse([]byte(tt.json))
+		if tt.err == "" && err != nil {
+			t.Errorf("%s: got %v", tt.name, err)
+		}
+		if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+			t.Errorf("%s: got %v, want an error containing %q", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestMet(t *testing.T) {
+	scholar := Achievement{ID: "scholar", Conditions: []Condition{
+		{Counter: CounterReviews, AtLeast: 1000},
+		{Counter: CounterLongestStreak, AtLeast: 30},
+	}}
+	tests := []struct {
+		counters map[string]int
+		want     bool
+	}{
+		{map[string]int{}, false},
+		{map[string]int{CounterReviews: 1500, CounterLongestStreak: 14}, false},
+		{map[string]int{CounterReviews: 1000, CounterLongestStreak: 30}, true},
+	}
+	for _, tt := range tests {
+		if got := scholar.Met(tt.counters); got != tt.want {
+			t.Errorf("%v: got %v, want %v", tt.counters, got, tt.want)
+		}
+	}
+}
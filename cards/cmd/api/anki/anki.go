@@ -0,0 +1,100 @@
+// Package anki reads and writes Anki packages (.apkg).
+//
+// A package is a zip archive holding an SQLite collection and a media map. Only the legacy collection
+// format (schema 11) is supported, newer Anki versions write it when "Support older Anki versions" is checked.
+// Notes are rendered through the templates of their note type, so every Anki card becomes a card with
+// plain text sides, cloze deletions included.
+package anki
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+const (
+	FormatApkg  = "apkg"
+	ContentType = "application/octet-stream"
+
+	// maxCollectionBytes bounds the unpacked collection, packages are compressed and could be zip bombs
+	maxCollectionBytes = 512 << 20
+)
+
+var (
+	ErrInvalidPackage = errors.New("file is not an Anki package")
+	// ErrUnsupportedCollection is returned for packages holding only the collection format of Anki 2.1.50+
+	ErrUnsupportedCollection = errors.New(`the package uses a collection format of newer Anki versions, export it again with "Support older Anki versions" checked`)
+)
+
+// Card is a card read from a package
+type Card struct {
+	// Position is the 1-based place of the card in the package, used to report problems
+	Position int
+	// NoteGUID identifies the note of the card across collections
+	NoteGUID     string
+	Front        string
+	Back         string
+	Hint         string
+	Alternatives []string
+	Tags         []string
+	// Deck is the name of the Anki deck holding the card, subdecks are separated by "::"
+	Deck string
+	// Schedule is nil for cards that were never studied
+	Schedule *Schedule
+}
+
+// Schedule is the review state of a studied card. Anki collections rarely use FSRS,
+// Stability and Difficulty are then estimated from the interval and the ease.
+type Schedule struct {
+	Repetitions  int
+	Lapses       int
+	IntervalDays int
+	Ease         float64
+	Stability    float64
+	Difficulty   float64
+	Due          time.Time
+	LastReview   time.Time
+}
+
+// Skipped is a card of the package that has no counterpart, e.g. an empty cloze
+type Skipped struct {
+	Position int
+	Reason   string
+}
+
+type Package struct {
+	Cards   []Card
+	Skipped []Skipped
+	// Media names the media files of the package, cards refer to them by name
+	Media []string
+}
+
+// Note is a card written to a package
+type Note struct {
+	// GUID identifies the note, Anki updates the note instead of adding a copy when it imports the same guid again
+	GUID         string
+	Front        string
+	Back         string
+	Hint         string
+	Alternatives []string
+	Tags         []string
+	// Schedule is nil for cards that were never studied
+	Schedule *Schedule
+}
+
+const (
+	defaultEase   = 2.5
+	minDifficulty = 1.0
+	maxDifficulty = 10.0
+)
+
+// estimateDifficulty maps an SM-2 ease onto the FSRS difficulty scale, the default ease lands in the middle
+func estimateDifficulty(ease float64) float64 {
+	difficulty := 5 + (defaultEase-ease)*5/1.2
+	return math.Min(math.Max(difficulty, minDifficulty), maxDifficulty)
+}
+
+// estimateStability treats the interval as the time the card is remembered with the usual retention
+func estimateStability(intervalDays int) float64 {
+	return math.Max(float64(intervalDays), 1)
+}
@@ -0,0 +1,174 @@
+package anki
+
+// The legacy collection schema, version 11
+const collectionSchema = `
+CREATE TABLE col (
+    id     INTEGER PRIMARY KEY,
+    crt    INTEGER NOT NULL,
+    mod    INTEGER NOT NULL,
+    scm    INTEGER NOT NULL,
+    ver    INTEGER NOT NULL,
+    dty    INTEGER NOT NULL,
+    usn    INTEGER NOT NULL,
+    ls     INTEGER NOT NULL,
+    conf   TEXT    NOT NULL,
+    models TEXT    NOT NULL,
+    decks  TEXT    NOT NULL,
+    dconf  TEXT    NOT NULL,
+    tags   TEXT    NOT NULL
+);
+CREATE TABLE notes (
+    id    INTEGER PRIMARY KEY,
+    guid  TEXT    NOT NULL,
+    mid   INTEGER NOT NULL,
+    mod   INTEGER NOT NULL,
+    usn   INTEGER NOT NULL,
+    tags  TEXT    NOT NULL,
+    flds  TEXT    NOT NULL,
+    sfld  INTEGER NOT NULL,
+    csum  INTEGER NOT NULL,
+    flags INTEGER NOT NULL,
+    data  TEXT    NOT NULL
+);
+CREATE TABLE cards (
+    id     INTEGER PRIMARY KEY,
+    nid    INTEGER NOT NULL,
+    did    INTEGER NOT NULL,
+    ord    INTEGER NOT NULL,
+    mod    INTEGER NOT NULL,
+    usn    INTEGER NOT NULL,
+    type   INTEGER NOT NULL,
+    queue  INTEGER NOT NULL,
+    due    INTEGER NOT NULL,
+    ivl    INTEGER NOT NULL,
+    factor INTEGER NOT NULL,
+    reps   INTEGER NOT NULL,
+    lapses INTEGER NOT NULL,
+    left   INTEGER NOT NULL,
+    odue   INTEGER NOT NULL,
+    odid   INTEGER NOT NULL,
+    flags  INTEGER NOT NULL,
+    data   TEXT    NOT NULL
+);
+CREATE TABLE revlog (
+    id      INTEGER PRIMARY KEY,
+    cid     INTEGER NOT NULL,
+    usn     INTEGER NOT NULL,
+    ease    INTEGER NOT NULL,
+    ivl     INTEGER NOT NULL,
+    lastIvl INTEGER NOT NULL,
+    factor  INTEGER NOT NULL,
+    time    INTEGER NOT NULL,
+    type    INTEGER NOT NULL
+);
+CREATE TABLE graves (
+    usn  INTEGER NOT NULL,
+    oid  INTEGER NOT NULL,
+    type INTEGER NOT NULL
+);
+CREATE INDEX ix_notes_usn ON notes (usn);
+CREATE INDEX ix_cards_usn ON cards (usn);
+CREATE INDEX ix_revlog_usn ON revlog (usn);
+CREATE INDEX ix_cards_nid ON cards (nid);
+CREATE INDEX ix_cards_sched ON cards (did, queue, due);
+CREATE INDEX ix_revlog_cid ON revlog (cid);
+CREATE INDEX ix_notes_csum ON notes (csum);
+`
+
+const (
+	collectionFile       = "collection.anki2"
+	collection21File     = "collection.anki21"
+	collection21bFile    = "collection.anki21b"
+	mediaFile            = "media"
+	collectionVersion    = 11
+	fieldSeparator       = "\x1f"
+	noteTypeCloze        = 1
+	defaultDeckID        = 1
+	defaultDeckConfigID  = 1
+	secondsPerDay        = 24 * 60 * 60
+	unixTimestampCeiling = 1_000_000_000
+)
+
+// card types and queues of the cards table
+const (
+	cardNew        = 0
+	cardLearning   = 1
+	cardReview     = 2
+	cardRelearning = 3
+
+	queueNew    = 0
+	queueReview = 2
+
+	// revlogManual marks rescheduling done by hand, it isn't an answer
+	revlogManual = 4
+	easeAgain    = 1
+)
+
+type noteType struct {
+	ID        int64           `json:"id"`
+	Name      string          `json:"name"`
+	Type      int             `json:"type"`
+	Mod       int64           `json:"mod"`
+	Usn       int             `json:"usn"`
+	SortField int             `json:"sortf"`
+	DeckID    int64           `json:"did"`
+	Templates []cardTemplate  `json:"tmpls"`
+	Fields    []noteTypeField `json:"flds"`
+	CSS       string          `json:"css"`
+	LatexPre  string          `json:"latexPre"`
+	LatexPost string          `json:"latexPost"`
+	Req       []any           `json:"req"`
+	Tags      []string        `json:"tags"`
+	Vers      []any           `json:"vers"`
+}
+
+type cardTemplate struct {
+	Name           string `json:"name"`
+	Ord            int    `json:"ord"`
+	QuestionFormat string `json:"qfmt"`
+	AnswerFormat   string `json:"afmt"`
+	BrowserQFormat string `json:"bqfmt"`
+	BrowserAFormat string `json:"bafmt"`
+	DeckID         *int64 `json:"did"`
+}
+
+type noteTypeField struct {
+	Name   string `json:"name"`
+	Ord    int    `json:"ord"`
+	Sticky bool   `json:"sticky"`
+	RTL    bool   `json:"rtl"`
+	Font   string `json:"font"`
+	Size   int    `json:"size"`
+	Media  []any  `json:"media"`
+}
+
+type deck struct {
+	ID               int64  `json:"id"`
+	Name             string `json:"name"`
+	Description      string `json:"desc"`
+	Mod              int64  `json:"mod"`
+	Usn              int    `json:"usn"`
+	Dynamic          int    `json:"dyn"`
+	ConfigID         int64  `json:"conf"`
+	Collapsed        bool   `json:"collapsed"`
+	BrowserCollapsed bool   `json:"browserCollapsed"`
+	NewToday         [2]int `json:"newToday"`
+	ReviewToday      [2]int `json:"revToday"`
+	LearnToday       [2]int `json:"lrnToday"`
+	TimeToday        [2]int `json:"timeToday"`
+	ExtendNew        int    `json:"extendNew"`
+	ExtendReview     int    `json:"extendRev"`
+}
+
+// fsrsData is kept in the data column of cards by collections using FSRS
+type fsrsData struct {
+	Stability  float64 `json:"s,omitempty"`
+	Difficulty float64 `json:"d,omitempty"`
+}
+
+// defaultDeckConfig is the "Default" options group of a new Anki collection
+const defaultDeckConfig = `{"1": {"id": 1, "name": "Default", "mod": 0, "usn": 0, "maxTaken": 60, "autoplay": true,
+	"timer": 0, "replayq": true, "dyn": false,
+	"new": {"delays": [1, 10], "ints": [1, 4, 7], "initialFactor": 2500, "order": 1, "perDay": 20, "bury": false},
+	"rev": {"perDay": 200, "ease4": 1.3, "ivlFct": 1, "maxIvl": 36500, "bury": false, "hardFactor": 1.2},
+	"lapse": {"delays": [10], "mult": 0, "minInt": 1, "leechFails": 8, "leechAction": 0}}}`
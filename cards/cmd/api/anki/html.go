@@ -0,0 +1,42 @@
+package anki
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	hiddenBlocks = regexp.MustCompile(`(?is)<(style|script)\b[^>]*>.*?</(style|script)\s*>`)
+	imageTags    = regexp.MustCompile(`(?is)<img\b[^>]*?\bsrc\s*=\s*["']?([^"'\s>]+)[^>]*>`)
+	soundTags    = regexp.MustCompile(`\[sound:([^\]]+)\]`)
+	lineBreaks   = regexp.MustCompile(`(?i)<\s*(br|hr|div|/p|/li|/tr|/h[1-6])\b[^>]*>`)
+	tags         = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// htmlToText turns a field into plain text, media are kept as references like "[image: cat.jpg]"
+func htmlToText(value string) string {
+	value = hiddenBlocks.ReplaceAllString(value, "")
+	value = imageTags.ReplaceAllString(value, "[image: $1]")
+	value = soundTags.ReplaceAllString(value, "[sound: $1]")
+	value = lineBreaks.ReplaceAllString(value, "\n")
+	value = tags.ReplaceAllString(value, "")
+	value = strings.ReplaceAll(html.UnescapeString(value), "\u00a0", " ")
+
+	lines := strings.Split(value, "\n")
+	text := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		// keep paragraphs apart but drop runs of empty lines
+		if line == "" && (len(text) == 0 || text[len(text)-1] == "") {
+			continue
+		}
+		text = append(text, line)
+	}
+	return strings.TrimSpace(strings.Join(text, "\n"))
+}
+
+// textToHTML turns plain text into a field
+func textToHTML(text string) string {
+	return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>")
+}
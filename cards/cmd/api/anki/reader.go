@@ -0,0 +1,446 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	_ "modernc.org/sqlite"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Check makes sure the data looks like a package this reader understands without reading the collection
+func Check(data []byte) error {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return ErrInvalidPackage
+	}
+	_, err = findCollection(archive)
+	return err
+}
+
+// Read parses a whole package
+func Read(data []byte) (*Package, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, ErrInvalidPackage
+	}
+	collection, err := findCollection(archive)
+	if err != nil {
+		return nil, err
+	}
+	// the SQLite driver only opens files
+	path, err := extract(collection)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(path)
+
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	pkg, err := readCollection(db)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range archive.File {
+		if file.Name == mediaFile {
+			pkg.Media = readMedia(file)
+		}
+	}
+	return pkg, nil
+}
+
+// findCollection prefers the collection of Anki 2.1, packages may also hold an older copy for old clients
+func findCollection(archive *zip.Reader) (*zip.File, error) {
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+	switch {
+	case files[collection21bFile] != nil:
+		// the legacy collection next to it only holds a note asking to update Anki
+		return nil, ErrUnsupportedCollection
+	case files[collection21File] != nil:
+		return files[collection21File], nil
+	case files[collectionFile] != nil:
+		return files[collectionFile], nil
+	default:
+		return nil, fmt.Errorf("%w: the collection is missing", ErrInvalidPackage)
+	}
+}
+
+func extract(file *zip.File) (string, error) {
+	if file.UncompressedSize64 > maxCollectionBytes {
+		return "", fmt.Errorf("%w: the collection is larger than %d bytes", ErrInvalidPackage, maxCollectionBytes)
+	}
+	src, err := file.Open()
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+	}
+	defer src.Close()
+
+	dst, err := os.CreateTemp("", "anki-*.anki2")
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+	written, err := io.Copy(dst, io.LimitReader(src, maxCollectionBytes+1))
+	if err == nil && written > maxCollectionBytes {
+		err = fmt.Errorf("%w: the collection is larger than %d bytes", ErrInvalidPackage, maxCollectionBytes)
+	}
+	if err != nil {
+		os.Remove(dst.Name())
+		return "", err
+	}
+	return dst.Name(), nil
+}
+
+// readMedia lists the names of the media map, newer packages encode it differently and are skipped
+func readMedia(file *zip.File) []string {
+	src, err := file.Open()
+	if err != nil {
+		return nil
+	}
+	defer src.Close()
+	media := map[string]string{}
+	if err := json.NewDecoder(src).Decode(&media); err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(media))
+	for _, name := range media {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type note struct {
+	guid   string
+	typeID int64
+	tags   []string
+	fields []string
+}
+
+// collectionReader holds what cards need from the rest of the collection
+type collectionReader struct {
+	created   time.Time
+	noteTypes map[int64]noteType
+	decks     map[int64]deck
+	notes     map[int64]note
+	history   map[int64]reviewHistory
+}
+
+// reviewHistory sums up the answers to a card
+type reviewHistory struct {
+	lastReview time.Time
+	// streak counts the answers since the last Again
+	streak int
+}
+
+func readCollection(db *sql.DB) (*Package, error) {
+	var (
+		crt           int64
+		rawNoteTypes  string
+		rawDecks      string
+		collectionErr = func(err error) error { return fmt.Errorf("%w: %v", ErrInvalidPackage, err) }
+	)
+	if err := db.QueryRow(`SELECT crt, models, decks FROM col`).Scan(&crt, &rawNoteTypes, &rawDecks); err != nil {
+		return nil, collectionErr(err)
+	}
+	c := collectionReader{
+		created:   time.Unix(crt, 0),
+		noteTypes: map[int64]noteType{},
+		decks:     map[int64]deck{},
+		notes:     map[int64]note{},
+		history:   map[int64]reviewHistory{},
+	}
+	noteTypes := map[string]noteType{}
+	if err := json.Unmarshal([]byte(rawNoteTypes), &noteTypes); err != nil {
+		return nil, collectionErr(err)
+	}
+	for _, noteType := range noteTypes {
+		sort.Slice(noteType.Fields, func(i, j int) bool { return noteType.Fields[i].Ord < noteType.Fields[j].Ord })
+		c.noteTypes[noteType.ID] = noteType
+	}
+	decks := map[string]deck{}
+	if err := json.Unmarshal([]byte(rawDecks), &decks); err != nil {
+		return nil, collectionErr(err)
+	}
+	for _, deck := range decks {
+		c.decks[deck.ID] = deck
+	}
+	if err := c.readNotes(db); err != nil {
+		return nil, collectionErr(err)
+	}
+	if err := c.readHistory(db); err != nil {
+		return nil, collectionErr(err)
+	}
+	pkg, err := c.readCards(db)
+	if err != nil {
+		return nil, collectionErr(err)
+	}
+	return pkg, nil
+}
+
+func (c *collectionReader) readNotes(db *sql.DB) error {
+	rows, err := db.Query(`SELECT id, guid, mid, tags, flds FROM notes`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			id           int64
+			n            note
+			tags, fields string
+		)
+		if err := rows.Scan(&id, &n.guid, &n.typeID, &tags, &fields); err != nil {
+			return err
+		}
+		n.tags = strings.Fields(tags)
+		n.fields = strings.Split(fields, fieldSeparator)
+		c.notes[id] = n
+	}
+	return rows.Err()
+}
+
+func (c *collectionReader) readHistory(db *sql.DB) error {
+	rows, err := db.Query(`SELECT id, cid, ease, type FROM revlog ORDER BY cid, id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, cardID, ease, kind int64
+		if err := rows.Scan(&id, &cardID, &ease, &kind); err != nil {
+			return err
+		}
+		if kind == revlogManual {
+			continue
+		}
+		history := c.history[cardID]
+		history.lastReview = time.UnixMilli(id)
+		if ease == easeAgain {
+			history.streak = 0
+		} else {
+			history.streak++
+		}
+		c.history[cardID] = history
+	}
+	return rows.Err()
+}
+
+func (c *collectionReader) readCards(db *sql.DB) (*Package, error) {
+	rows, err := db.Query(`SELECT id, nid, did, odid, ord, type, queue, due, odue, ivl, factor, reps, lapses, data
+		FROM cards ORDER BY nid, ord`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pkg := &Package{Cards: []Card{}, Skipped: []Skipped{}}
+	for position := 1; rows.Next(); position++ {
+		var (
+			r    cardRow
+			data string
+		)
+		err := rows.Scan(&r.id, &r.noteID, &r.deckID, &r.originalDeckID, &r.ord, &r.kind, &r.queue, &r.due,
+			&r.originalDue, &r.interval, &r.factor, &r.reps, &r.lapses, &data)
+		if err != nil {
+			return nil, err
+		}
+		json.Unmarshal([]byte(data), &r.fsrs)
+
+		card, err := c.card(r)
+		if err != nil {
+			pkg.Skipped = append(pkg.Skipped, Skipped{Position: position, Reason: err.Error()})
+			continue
+		}
+		card.Position = position
+		pkg.Cards = append(pkg.Cards, card)
+	}
+	return pkg, rows.Err()
+}
+
+type cardRow struct {
+	id             int64
+	noteID         int64
+	deckID         int64
+	originalDeckID int64
+	ord            int
+	kind           int
+	queue          int
+	due            int64
+	originalDue    int64
+	interval       int
+	factor         int
+	reps           int
+	lapses         int
+	fsrs           fsrsData
+}
+
+// card renders the sides of the card through the template of its note type
+func (c *collectionReader) card(r cardRow) (Card, error) {
+	n, ok := c.notes[r.noteID]
+	if !ok {
+		return Card{}, errors.New("the note of the card is missing")
+	}
+	noteType, ok := c.noteTypes[n.typeID]
+	if !ok {
+		return Card{}, errors.New("the note type of the card is missing")
+	}
+	deckID := r.deckID
+	if r.originalDeckID != 0 {
+		// the card is borrowed by a filtered deck
+		deckID = r.originalDeckID
+	}
+	deckName := c.decks[deckID].Name
+
+	rend := &renderer{
+		fields: make(map[string]string, len(noteType.Fields)),
+		special: map[string]string{
+			"Tags":    strings.Join(n.tags, " "),
+			"Type":    noteType.Name,
+			"Deck":    deckName,
+			"Subdeck": deckName[strings.LastIndex(deckName, "::")+1:],
+		},
+	}
+	var alternatives []string
+	for i, field := range noteType.Fields {
+		if i < len(n.fields) {
+			rend.fields[field.Name] = n.fields[i]
+		}
+		if strings.EqualFold(field.Name, "alternatives") {
+			alternatives = splitAlternatives(htmlToText(rend.fields[field.Name]))
+		}
+	}
+
+	var (
+		template cardTemplate
+		found    bool
+	)
+	if noteType.Type == noteTypeCloze {
+		// cloze note types have a single template shared by all deletions
+		if len(noteType.Templates) > 0 {
+			template, found = noteType.Templates[0], true
+		}
+		rend.cloze = r.ord + 1
+	} else {
+		for _, candidate := range noteType.Templates {
+			if candidate.Ord == r.ord {
+				template, found = candidate, true
+			}
+		}
+	}
+	if !found {
+		return Card{}, fmt.Errorf("note type %q has no template %d", noteType.Name, r.ord+1)
+	}
+	rend.special["Card"] = template.Name
+
+	question, err := rend.render(template.QuestionFormat)
+	if err != nil {
+		return Card{}, err
+	}
+	card := Card{
+		NoteGUID:     n.guid,
+		Front:        htmlToText(question),
+		Alternatives: alternatives,
+		Tags:         n.tags,
+		Deck:         deckName,
+		Schedule:     c.schedule(r),
+	}
+	if noteType.Type == noteTypeCloze {
+		var (
+			answers []string
+			hints   []string
+		)
+		for _, value := range rend.fields {
+			fieldAnswers, fieldHints := clozeAnswers(value, rend.cloze)
+			answers = append(answers, fieldAnswers...)
+			hints = append(hints, fieldHints...)
+		}
+		if len(answers) == 0 {
+			return Card{}, fmt.Errorf("note has no cloze deletion %d", rend.cloze)
+		}
+		card.Back = strings.Join(answers, ", ")
+		rend.hints = append(rend.hints, hints...)
+	} else {
+		answer, err := rend.render(template.AnswerFormat)
+		if err != nil {
+			return Card{}, err
+		}
+		card.Back = htmlToText(answerSide(answer))
+	}
+	card.Hint = strings.Join(rend.hints, "\n")
+	if card.Front == "" {
+		return Card{}, fmt.Errorf("template %q renders an empty question", template.Name)
+	}
+	return card, nil
+}
+
+// schedule converts the scheduling columns, due is a day number relative to the collection creation
+// for review cards and a unix timestamp for cards in learning
+func (c *collectionReader) schedule(r cardRow) *Schedule {
+	if r.kind == cardNew {
+		return nil
+	}
+	due := r.due
+	if r.originalDeckID != 0 && r.originalDue != 0 {
+		due = r.originalDue
+	}
+	schedule := &Schedule{
+		Lapses:       r.lapses,
+		IntervalDays: max(r.interval, 0),
+		Ease:         float64(r.factor) / 1000,
+		Stability:    r.fsrs.Stability,
+		Difficulty:   r.fsrs.Difficulty,
+	}
+	if due > unixTimestampCeiling {
+		schedule.Due = time.Unix(due, 0)
+	} else {
+		schedule.Due = c.created.Add(time.Duration(due) * secondsPerDay * time.Second)
+	}
+	if schedule.Ease == 0 {
+		schedule.Ease = defaultEase
+	}
+	if schedule.Stability == 0 {
+		schedule.Stability = estimateStability(schedule.IntervalDays)
+	}
+	if schedule.Difficulty == 0 {
+		schedule.Difficulty = estimateDifficulty(schedule.Ease)
+	}
+
+	if history, ok := c.history[r.id]; ok {
+		schedule.Repetitions = history.streak
+		schedule.LastReview = history.lastReview
+	} else {
+		// packages exported without the review log only tell how often the card was answered
+		schedule.Repetitions = max(r.reps-r.lapses, 0)
+		schedule.LastReview = schedule.Due.AddDate(0, 0, -schedule.IntervalDays)
+	}
+	if r.kind == cardRelearning {
+		schedule.Repetitions = 0
+	}
+	return schedule
+}
+
+func splitAlternatives(value string) []string {
+	parts := strings.Split(value, "|")
+	alternatives := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			alternatives = append(alternatives, part)
+		}
+	}
+	return alternatives
+}
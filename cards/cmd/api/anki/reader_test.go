@@ -0,0 +1,185 @@
+package anki
+
+//go:generate go run testdata/generate.go
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// day returns the date the collection fixtures count due days from
+func day(n int) time.Time {
+	return time.Unix(1704067200, 0).AddDate(0, 0, n)
+}
+
+func TestReadLegacyPackage(t *testing.T) {
+	data := readFixture(t, "legacy.apkg")
+	if err := Check(data); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	pkg, err := Read(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"cat.jpg", "chat.mp3"}; !reflect.DeepEqual(pkg.Media, want) {
+		t.Errorf("media %v, want %v", pkg.Media, want)
+	}
+	if len(pkg.Cards) != 5 {
+		t.Fatalf("read %d cards, want 5: %+v", len(pkg.Cards), pkg.Cards)
+	}
+
+	chat := pkg.Cards[0]
+	want := Card{
+		Position: 1,
+		NoteGUID: "guid-chat",
+		Front:    "chat [image: cat.jpg]",
+		Back:     "cat [sound: chat.mp3]",
+		Tags:     []string{"french", "animals"},
+		Deck:     "Languages::French",
+	}
+	if !reflect.DeepEqual(chat, want) {
+		t.Errorf("basic card\n got %+v\nwant %+v", chat, want)
+	}
+
+	maison := pkg.Cards[1]
+	if maison.Front != "la maison" || maison.Back != "the house" || maison.Hint != "feminine" ||
+		!reflect.DeepEqual(maison.Alternatives, []string{"home", "house"}) {
+		t.Errorf("card with a hint and alternatives %+v", maison)
+	}
+	checkSchedule(t, "review card", maison.Schedule, Schedule{
+		// good and good since the last again, the manual reschedule isn't an answer
+		Repetitions:  2,
+		Lapses:       1,
+		IntervalDays: 10,
+		Ease:         2.3,
+		Stability:    10,
+		Difficulty:   5 + 0.2*5/1.2,
+		Due:          day(30),
+		LastReview:   time.UnixMilli(1704800000000),
+	})
+
+	reverse := pkg.Cards[2]
+	if reverse.Front != "the house" || reverse.Back != "la maison" || reverse.Hint != "" {
+		t.Errorf("reversed card %+v", reverse)
+	}
+	if reverse.Deck != "Languages" {
+		t.Errorf("card borrowed by a filtered deck is in %q, want its home deck", reverse.Deck)
+	}
+	checkSchedule(t, "card without history", reverse.Schedule, Schedule{
+		Repetitions:  3,
+		IntervalDays: 20,
+		Ease:         2.5,
+		Stability:    20,
+		Difficulty:   5,
+		Due:          day(40),
+		LastReview:   day(20),
+	})
+
+	first, second := pkg.Cards[3], pkg.Cards[4]
+	if first.Front != "[...] is the capital of France" || first.Back != "Paris" || first.Hint != "" || first.Schedule != nil {
+		t.Errorf("first cloze %+v", first)
+	}
+	if second.Front != "Paris is the capital of [country]" || second.Back != "France" || second.Hint != "country" {
+		t.Errorf("second cloze %+v", second)
+	}
+	checkSchedule(t, "learning card", second.Schedule, Schedule{
+		Repetitions: 1,
+		Ease:        2.5,
+		Stability:   1,
+		Difficulty:  5,
+		Due:         time.Unix(1704153600, 0),
+		LastReview:  time.Unix(1704153600, 0),
+	})
+
+	wantSkipped := []Skipped{
+		{Position: 6, Reason: "note has no cloze deletion 3"},
+		{Position: 7, Reason: "the note of the card is missing"},
+	}
+	if !reflect.DeepEqual(pkg.Skipped, wantSkipped) {
+		t.Errorf("skipped %+v, want %+v", pkg.Skipped, wantSkipped)
+	}
+}
+
+func TestReadAnki21Package(t *testing.T) {
+	pkg, err := Read(readFixture(t, "anki21.apkg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the notice of collection.anki2 must not be read
+	if len(pkg.Cards) != 1 || pkg.Cards[0].Front != "Powerhouse of the cell" || pkg.Cards[0].Back != "Mitochondria" {
+		t.Fatalf("cards %+v, want the one of collection.anki21", pkg.Cards)
+	}
+	if pkg.Cards[0].Deck != "Biology" || !reflect.DeepEqual(pkg.Cards[0].Tags, []string{"biology"}) {
+		t.Errorf("card %+v", pkg.Cards[0])
+	}
+	checkSchedule(t, "FSRS card", pkg.Cards[0].Schedule, Schedule{
+		Repetitions:  1,
+		IntervalDays: 12,
+		Ease:         2.5,
+		Stability:    12.5,
+		Difficulty:   6.2,
+		Due:          day(20),
+		LastReview:   time.UnixMilli(1704500000000),
+	})
+	if len(pkg.Media) != 0 {
+		t.Errorf("media %v, want none", pkg.Media)
+	}
+}
+
+func TestReadRejectsUnsupportedPackages(t *testing.T) {
+	var withoutCollection bytes.Buffer
+	archive := zip.NewWriter(&withoutCollection)
+	if _, err := archive.Create("media"); err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"anki 2.1.50 collection", readFixture(t, "anki21b.apkg"), ErrUnsupportedCollection},
+		{"not a zip", []byte("front,back\nchat,cat\n"), ErrInvalidPackage},
+		{"zip without collection", withoutCollection.Bytes(), ErrInvalidPackage},
+	}
+	for _, tt := range tests {
+		if err := Check(tt.data); !errors.Is(err, tt.want) {
+			t.Errorf("Check %s: %v, want %v", tt.name, err, tt.want)
+		}
+		if _, err := Read(tt.data); !errors.Is(err, tt.want) {
+			t.Errorf("Read %s: %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func checkSchedule(t *testing.T, name string, got *Schedule, want Schedule) {
+	t.Helper()
+	if got == nil {
+		t.Errorf("%s has no schedule", name)
+		return
+	}
+	if got.Repetitions != want.Repetitions || got.Lapses != want.Lapses || got.IntervalDays != want.IntervalDays ||
+		math.Abs(got.Ease-want.Ease) > 1e-9 || math.Abs(got.Stability-want.Stability) > 1e-9 ||
+		math.Abs(got.Difficulty-want.Difficulty) > 1e-9 || !got.Due.Equal(want.Due) || !got.LastReview.Equal(want.LastReview) {
+		t.Errorf("%s schedule\n got %+v\nwant %+v", name, *got, want)
+	}
+}
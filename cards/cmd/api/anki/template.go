@@ -0,0 +1,150 @@
+package anki
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// clozes match deletions like {{c1::answer}} and {{c1::answer::hint}}
+var clozes = regexp.MustCompile(`(?s)\{\{c(\d+)::(.*?)(?:::(.*?))?\}\}`)
+
+// renderer fills card templates, it covers fields, conditional sections and the filters used for studying
+type renderer struct {
+	fields map[string]string
+	// special are the fields Anki provides next to the note fields, e.g. Tags and Deck
+	special map[string]string
+	// cloze is the 1-based number of the deletion asked by a cloze card, 0 for other note types
+	cloze int
+	// hints collects the fields shown with the hint filter, they become the hint of the card
+	hints []string
+}
+
+// render fills the template, it fails on unbalanced sections
+func (r *renderer) render(template string) (string, error) {
+	var (
+		out      strings.Builder
+		sections []string
+		hidden   int
+	)
+	for len(template) > 0 {
+		start := strings.Index(template, "{{")
+		if start < 0 {
+			start = len(template)
+		}
+		if hidden == 0 {
+			out.WriteString(template[:start])
+		}
+		template = template[start:]
+		if template == "" {
+			break
+		}
+		end := strings.Index(template, "}}")
+		if end < 0 {
+			return "", fmt.Errorf("template has an unclosed tag %q", template)
+		}
+		tag := strings.TrimSpace(template[2:end])
+		template = template[end+2:]
+
+		switch {
+		case strings.HasPrefix(tag, "#"), strings.HasPrefix(tag, "^"):
+			name := strings.TrimSpace(tag[1:])
+			shown := r.nonEmpty(name) == (tag[0] == '#')
+			sections = append(sections, name)
+			if hidden > 0 || !shown {
+				hidden++
+			}
+		case strings.HasPrefix(tag, "/"):
+			name := strings.TrimSpace(tag[1:])
+			if len(sections) == 0 || sections[len(sections)-1] != name {
+				return "", fmt.Errorf("template closes section %q that isn't open", name)
+			}
+			sections = sections[:len(sections)-1]
+			if hidden > 0 {
+				hidden--
+			}
+		case hidden == 0:
+			out.WriteString(r.replace(tag))
+		}
+	}
+	if len(sections) > 0 {
+		return "", fmt.Errorf("template doesn't close section %q", sections[len(sections)-1])
+	}
+	return out.String(), nil
+}
+
+// replace renders a field reference, filters are written before the field name and applied right to left
+func (r *renderer) replace(tag string) string {
+	parts := strings.Split(tag, ":")
+	name := strings.TrimSpace(parts[len(parts)-1])
+	filters := parts[:len(parts)-1]
+	value := r.value(name)
+	for i := len(filters) - 1; i >= 0; i-- {
+		switch filter := strings.TrimSpace(filters[i]); {
+		case filter == "hint":
+			if text := htmlToText(value); text != "" {
+				r.hints = append(r.hints, text)
+			}
+			return ""
+		case filter == "type", strings.HasPrefix(filter, "tts"):
+			// typing answers and speech are up to the quiz modes
+			return ""
+		case filter == "cloze":
+			value = renderCloze(value, r.cloze)
+		}
+	}
+	return value
+}
+
+func (r *renderer) value(name string) string {
+	if value, ok := r.fields[name]; ok {
+		return value
+	}
+	return r.special[name]
+}
+
+func (r *renderer) nonEmpty(name string) bool {
+	return htmlToText(r.value(name)) != ""
+}
+
+// renderCloze asks the active deletion and reveals the others
+func renderCloze(text string, active int) string {
+	return clozes.ReplaceAllStringFunc(text, func(match string) string {
+		groups := clozes.FindStringSubmatch(match)
+		if number, _ := strconv.Atoi(groups[1]); number != active {
+			return groups[2]
+		}
+		if groups[3] != "" {
+			return "[" + groups[3] + "]"
+		}
+		return "[...]"
+	})
+}
+
+// clozeAnswers returns the answers and hints of the active deletion, a deletion may appear several times
+func clozeAnswers(text string, active int) (answers []string, hints []string) {
+	for _, groups := range clozes.FindAllStringSubmatch(text, -1) {
+		if number, _ := strconv.Atoi(groups[1]); number != active {
+			continue
+		}
+		if answer := htmlToText(groups[2]); !slices.Contains(answers, answer) {
+			answers = append(answers, answer)
+		}
+		if hint := htmlToText(groups[3]); hint != "" && !slices.Contains(hints, hint) {
+			hints = append(hints, hint)
+		}
+	}
+	return answers, hints
+}
+
+// answerSide drops the repeated question of an answer template, Anki marks the boundary with <hr id=answer>
+var answerBoundary = regexp.MustCompile(`(?i)<hr\s+id\s*=\s*["']?answer["']?\s*/?>`)
+
+func answerSide(rendered string) string {
+	if location := answerBoundary.FindStringIndex(rendered); location != nil {
+		return rendered[location[1]:]
+	}
+	return rendered
+}
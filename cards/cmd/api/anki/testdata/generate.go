@@ -0,0 +1,263 @@
+//go:build ignore
+
+// generate builds the package fixtures the way Anki lays them out, independently of the package writer.
+// Run it from the anki directory with go generate.
+package main
+
+import (
+	"archive/zip"
+	"database/sql"
+	"encoding/json"
+	"log"
+	_ "modernc.org/sqlite"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// schema is the legacy collection schema of Anki 2.1
+const schema = `
+CREATE TABLE col (id integer primary key, crt integer not null, mod integer not null, scm integer not null,
+    ver integer not null, dty integer not null, usn integer not null, ls integer not null, conf text not null,
+    models text not null, decks text not null, dconf text not null, tags text not null);
+CREATE TABLE notes (id integer primary key, guid text not null, mid integer not null, mod integer not null,
+    usn integer not null, tags text not null, flds text not null, sfld integer not null, csum integer not null,
+    flags integer not null, data text not null);
+CREATE TABLE cards (id integer primary key, nid integer not null, did integer not null, ord integer not null,
+    mod integer not null, usn integer not null, type integer not null, queue integer not null, due integer not null,
+    ivl integer not null, factor integer not null, reps integer not null, lapses integer not null,
+    left integer not null, odue integer not null, odid integer not null, flags integer not null, data text not null);
+CREATE TABLE revlog (id integer primary key, cid integer not null, usn integer not null, ease integer not null,
+    ivl integer not null, lastIvl integer not null, factor integer not null, time integer not null,
+    type integer not null);
+CREATE TABLE graves (usn integer not null, oid integer not null, type integer not null);
+`
+
+// created is the creation day of the fixture collections, 2024-01-01
+const created = 1704067200
+
+var modified = time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+type noteType struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Type      int        `json:"type"`
+	Templates []template `json:"tmpls"`
+	Fields    []field    `json:"flds"`
+}
+
+type template struct {
+	Name string `json:"name"`
+	Ord  int    `json:"ord"`
+	Q    string `json:"qfmt"`
+	A    string `json:"afmt"`
+}
+
+type field struct {
+	Name string `json:"name"`
+	Ord  int    `json:"ord"`
+}
+
+type deck struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	Dynamic int    `json:"dyn"`
+}
+
+var (
+	basic = noteType{ID: 200, Name: "Basic", Fields: []field{{"Front", 0}, {"Back", 1}}, Templates: []template{
+		{Name: "Card 1", Ord: 0, Q: "{{Front}}", A: "{{FrontSide}}\n\n<hr id=answer>\n\n{{Back}}"},
+	}}
+	reversed = noteType{ID: 201, Name: "Basic (and reversed card)",
+		Fields: []field{{"Front", 0}, {"Back", 1}, {"Hint", 2}, {"Alternatives", 3}},
+		Templates: []template{
+			{Name: "Card 1", Ord: 0, Q: "{{Front}}{{#Hint}}<br>{{hint:Hint}}{{/Hint}}", A: "{{FrontSide}}<hr id=answer>{{Back}}"},
+			{Name: "Card 2", Ord: 1, Q: "{{Back}}", A: "{{FrontSide}}<hr id=answer>{{Front}}"},
+		}}
+	cloze = noteType{ID: 202, Name: "Cloze", Type: 1, Fields: []field{{"Text", 0}, {"Extra", 1}}, Templates: []template{
+		{Name: "Cloze", Ord: 0, Q: "{{cloze:Text}}", A: "{{cloze:Text}}<br>{{Extra}}"},
+	}}
+)
+
+func main() {
+	if err := legacy(); err != nil {
+		log.Fatal(err)
+	}
+	if err := anki21(); err != nil {
+		log.Fatal(err)
+	}
+	if err := anki21b(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// legacy is a package of Anki before 2.1.28: a collection.anki2 with subdecks, a filtered deck,
+// three note types, review history and two media files
+func legacy() error {
+	collection, err := buildCollection(
+		[]noteType{basic, reversed, cloze},
+		[]deck{{1, "Default", 0}, {100, "Languages", 0}, {101, "Languages::French", 0}, {102, "Cram", 1}},
+		[]string{
+			`INSERT INTO notes VALUES (1001, 'guid-chat', 200, 0, -1, ' french animals ',
+				'<b>chat</b>&nbsp;<img src="cat.jpg">' || char(31) || 'cat [sound:chat.mp3]', 'chat', 0, 0, '')`,
+			`INSERT INTO notes VALUES (1002, 'guid-maison', 201, 0, -1, ' french ',
+				'la maison' || char(31) || 'the house' || char(31) || 'feminine' || char(31) || 'home | house', 'la maison', 0, 0, '')`,
+			`INSERT INTO notes VALUES (1003, 'guid-paris', 202, 0, -1, '',
+				'{{c1::Paris}} is the capital of {{c2::France::country}}' || char(31) || 'Europe', '', 0, 0, '')`,
+			// new
+			`INSERT INTO cards VALUES (2001, 1001, 101, 0, 0, -1, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, '')`,
+			// review, due on day 30, with its history
+			`INSERT INTO cards VALUES (2002, 1002, 101, 0, 0, -1, 2, 2, 30, 10, 2300, 5, 1, 0, 0, 0, 0, '')`,
+			// review borrowed by the filtered deck, due on day 40 in its home deck, without history
+			`INSERT INTO cards VALUES (2003, 1002, 102, 1, 0, -1, 2, 2, 1, 20, 2500, 3, 0, 0, 40, 100, 0, '')`,
+			// new cloze
+			`INSERT INTO cards VALUES (2004, 1003, 100, 0, 0, -1, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, '')`,
+			// cloze in learning, due at a timestamp
+			`INSERT INTO cards VALUES (2005, 1003, 100, 1, 0, -1, 1, 1, 1704153600, 0, 0, 1, 0, 1001, 0, 0, 0, '')`,
+			// the third deletion was removed from the note
+			`INSERT INTO cards VALUES (2006, 1003, 100, 2, 0, -1, 0, 0, 3, 0, 0, 0, 0, 0, 0, 0, 0, '')`,
+			// the note was deleted
+			`INSERT INTO cards VALUES (2007, 9999, 100, 0, 0, -1, 0, 0, 4, 0, 0, 0, 0, 0, 0, 0, 0, '')`,
+			// good, again, good, good, then rescheduled by hand
+			`INSERT INTO revlog VALUES (1704200000000, 2002, -1, 3, 1, 0, 2500, 5000, 0)`,
+			`INSERT INTO revlog VALUES (1704300000000, 2002, -1, 1, 1, 1, 2300, 8000, 1)`,
+			`INSERT INTO revlog VALUES (1704400000000, 2002, -1, 3, 4, 1, 2300, 4000, 2)`,
+			`INSERT INTO revlog VALUES (1704800000000, 2002, -1, 3, 10, 4, 2300, 3000, 1)`,
+			`INSERT INTO revlog VALUES (1704900000000, 2002, -1, 0, 10, 10, 2300, 0, 4)`,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	media, err := json.Marshal(map[string]string{"0": "cat.jpg", "1": "chat.mp3"})
+	if err != nil {
+		return err
+	}
+	return writePackage("legacy.apkg", []entry{
+		{"collection.anki2", collection},
+		{"0", []byte("jpeg")},
+		{"1", []byte("mp3")},
+		{"media", media},
+	})
+}
+
+// anki21 is a package of Anki 2.1.28 to 2.1.49: the real collection in collection.anki21,
+// collection.anki2 only asks old clients to update
+func anki21() error {
+	collection, err := buildCollection(
+		[]noteType{basic},
+		[]deck{{1, "Default", 0}, {100, "Biology", 0}},
+		[]string{
+			`INSERT INTO notes VALUES (1001, 'guid-cell', 200, 0, -1, ' biology ',
+				'Powerhouse of the cell' || char(31) || 'Mitochondria', 'Powerhouse of the cell', 0, 0, '')`,
+			// review scheduled with FSRS
+			`INSERT INTO cards VALUES (2001, 1001, 100, 0, 0, -1, 2, 2, 20, 12, 2500, 2, 0, 0, 0, 0, 0, '{"s":12.5,"d":6.2}')`,
+			`INSERT INTO revlog VALUES (1704500000000, 2001, -1, 3, 12, 3, 2500, 6000, 1)`,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	return writePackage("anki21.apkg", []entry{
+		{"collection.anki2", updateNotice()},
+		{"collection.anki21", collection},
+		{"media", []byte("{}")},
+	})
+}
+
+// anki21b is a package of Anki 2.1.50+ without legacy support, its collection is zstd compressed
+func anki21b() error {
+	return writePackage("anki21b.apkg", []entry{
+		{"collection.anki2", updateNotice()},
+		{"collection.anki21b", []byte("\x28\xb5\x2f\xfdnot really zstd")},
+		{"media", []byte("\x0a\x00")},
+	})
+}
+
+func updateNotice() []byte {
+	collection, err := buildCollection([]noteType{basic}, []deck{{1, "Default", 0}}, []string{
+		`INSERT INTO notes VALUES (1, 'guid-update', 200, 0, -1, '',
+			'Please update to the latest Anki version, then import the .colpkg/.apkg file again.' || char(31) || '', '', 0, 0, '')`,
+		`INSERT INTO cards VALUES (1, 1, 1, 0, 0, -1, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, '')`,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return collection
+}
+
+func buildCollection(noteTypes []noteType, decks []deck, statements []string) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "fixture")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "collection.anki2")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	models := map[string]noteType{}
+	for _, noteType := range noteTypes {
+		models[jsonID(noteType.ID)] = noteType
+	}
+	deckMap := map[string]deck{}
+	for _, d := range decks {
+		deckMap[jsonID(d.ID)] = d
+	}
+	rawModels, err := json.Marshal(models)
+	if err != nil {
+		return nil, err
+	}
+	rawDecks, err := json.Marshal(deckMap)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(schema); err != nil {
+		return nil, err
+	}
+	_, err = db.Exec(`INSERT INTO col VALUES (1, ?, 0, 0, 11, 0, 0, 0, '{}', ?, ?, '{}', '{}')`,
+		created, string(rawModels), string(rawDecks))
+	if err != nil {
+		return nil, err
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			return nil, err
+		}
+	}
+	db.Close()
+	return os.ReadFile(path)
+}
+
+func jsonID(id int64) string {
+	encoded, _ := json.Marshal(id)
+	return string(encoded)
+}
+
+type entry struct {
+	name string
+	data []byte
+}
+
+// writePackage zips the entries with fixed times, so regenerating doesn't change unchanged fixtures
+func writePackage(name string, entries []entry) error {
+	file, err := os.Create(filepath.Join("testdata", name))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	archive := zip.NewWriter(file)
+	for _, e := range entries {
+		w, err := archive.CreateHeader(&zip.FileHeader{Name: e.name, Method: zip.Deflate, Modified: modified})
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(e.data); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
@@ -0,0 +1,226 @@
+package anki
+
+import (
+	"archive/zip"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// noteTypeID is fixed, so Anki recognises the note type of previous exports instead of adding a copy
+const noteTypeID = 1704067200000
+
+var exportNoteType = noteType{
+	ID:   noteTypeID,
+	Name: "Card Quizzler",
+	Templates: []cardTemplate{{
+		Name:           "Card 1",
+		QuestionFormat: "{{Front}}{{#Hint}}<br><br>{{hint:Hint}}{{/Hint}}",
+		AnswerFormat:   "{{FrontSide}}\n\n<hr id=answer>\n\n{{Back}}",
+	}},
+	Fields: []noteTypeField{
+		{Name: "Front", Ord: 0, Font: "Arial", Size: 20, Media: []any{}},
+		{Name: "Back", Ord: 1, Font: "Arial", Size: 20, Media: []any{}},
+		{Name: "Hint", Ord: 2, Font: "Arial", Size: 20, Media: []any{}},
+		{Name: "Alternatives", Ord: 3, Font: "Arial", Size: 20, Media: []any{}},
+	},
+	CSS:       ".card {\n font-family: arial;\n font-size: 20px;\n text-align: center;\n color: black;\n background-color: white;\n}\n",
+	LatexPre:  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage[utf8]{inputenc}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n",
+	LatexPost: "\\end{document}",
+	Req:       []any{[]any{0, "any", []int{0}}},
+	Tags:      []string{},
+	Vers:      []any{},
+}
+
+// Write builds a package with a single deck, now stamps the modification times
+func Write(w io.Writer, deckName string, notes []Note, now time.Time) error {
+	file, err := os.CreateTemp("", "anki-*.anki2")
+	if err != nil {
+		return err
+	}
+	path := file.Name()
+	file.Close()
+	defer os.Remove(path)
+
+	if err := writeCollection(path, deckName, notes, now); err != nil {
+		return err
+	}
+	collection, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer collection.Close()
+
+	archive := zip.NewWriter(w)
+	entry, err := archive.Create(collectionFile)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(entry, collection); err != nil {
+		return err
+	}
+	// cards only carry text, so there are no media files
+	entry, err = archive.Create(mediaFile)
+	if err != nil {
+		return err
+	}
+	if _, err := entry.Write([]byte("{}")); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+func writeCollection(path, deckName string, notes []Note, now time.Time) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(collectionSchema); err != nil {
+		return err
+	}
+
+	// ids are millisecond timestamps in Anki, the deck shares its id with the first note
+	baseID := now.UnixMilli()
+	created := collectionCreation(notes, now)
+	if err := writeCol(tx, deckName, baseID, created, now); err != nil {
+		return err
+	}
+	for i, n := range notes {
+		id := baseID + int64(i)
+		if err := writeNote(tx, id, n, now); err != nil {
+			return err
+		}
+		if err := writeCard(tx, id, baseID, i, n.Schedule, created, now); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// collectionCreation is the day the collection was started, due days of review cards count from it
+func collectionCreation(notes []Note, now time.Time) time.Time {
+	created := now
+	for _, n := range notes {
+		if n.Schedule != nil && n.Schedule.LastReview.Before(created) {
+			created = n.Schedule.LastReview
+		}
+	}
+	return created.UTC().Truncate(secondsPerDay * time.Second)
+}
+
+func writeCol(tx *sql.Tx, deckName string, deckID int64, created, now time.Time) error {
+	conf, err := json.Marshal(map[string]any{
+		"nextPos": 1, "estTimes": true, "activeDecks": []int64{deckID}, "sortType": "noteFld", "timeLim": 0,
+		"sortBackwards": false, "addToCur": true, "curDeck": deckID, "newBury": true, "newSpread": 0,
+		"dueCounts": true, "curModel": strconv.Itoa(noteTypeID), "collapseTime": 1200,
+	})
+	if err != nil {
+		return err
+	}
+	noteTypes := exportNoteType
+	noteTypes.Mod = now.Unix()
+	noteTypes.Usn = -1
+	noteTypes.DeckID = deckID
+	models, err := json.Marshal(map[string]noteType{strconv.Itoa(noteTypeID): noteTypes})
+	if err != nil {
+		return err
+	}
+	decks, err := json.Marshal(map[string]deck{
+		strconv.Itoa(defaultDeckID):   newDeck(defaultDeckID, "Default", now),
+		strconv.FormatInt(deckID, 10): newDeck(deckID, deckName, now),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO col (id, crt, mod, scm, ver, dty, usn, ls, conf, models, decks, dconf, tags)
+		VALUES (1, ?, ?, ?, ?, 0, 0, 0, ?, ?, ?, ?, '{}')`,
+		created.Unix(), now.UnixMilli(), now.UnixMilli(), collectionVersion, string(conf), string(models),
+		string(decks), defaultDeckConfig,
+	)
+	return err
+}
+
+func newDeck(id int64, name string, now time.Time) deck {
+	return deck{
+		ID:           id,
+		Name:         name,
+		Mod:          now.Unix(),
+		Usn:          -1,
+		ConfigID:     defaultDeckConfigID,
+		ExtendNew:    10,
+		ExtendReview: 50,
+	}
+}
+
+func writeNote(tx *sql.Tx, id int64, n Note, now time.Time) error {
+	fields := []string{
+		textToHTML(n.Front),
+		textToHTML(n.Back),
+		textToHTML(n.Hint),
+		textToHTML(strings.Join(n.Alternatives, " | ")),
+	}
+	tags := ""
+	if len(n.Tags) > 0 {
+		// Anki tags can't hold spaces, they pad the list with spaces to ease searching
+		tagNames := make([]string, len(n.Tags))
+		for i, tag := range n.Tags {
+			tagNames[i] = strings.Join(strings.Fields(tag), "_")
+		}
+		tags = " " + strings.Join(tagNames, " ") + " "
+	}
+	_, err := tx.Exec(`INSERT INTO notes (id, guid, mid, mod, usn, tags, flds, sfld, csum, flags, data)
+		VALUES (?, ?, ?, ?, -1, ?, ?, ?, ?, 0, '')`,
+		id, n.GUID, noteTypeID, now.Unix(), tags, strings.Join(fields, fieldSeparator), n.Front, checksum(n.Front),
+	)
+	return err
+}
+
+func writeCard(tx *sql.Tx, id, deckID int64, position int, schedule *Schedule, created, now time.Time) error {
+	var (
+		kind, queue                    = cardNew, queueNew
+		due                            = int64(position + 1)
+		interval, factor, reps, lapses int
+		data                           = ""
+	)
+	if schedule != nil {
+		kind, queue = cardReview, queueReview
+		due = int64(schedule.Due.Sub(created) / (secondsPerDay * time.Second))
+		interval = max(schedule.IntervalDays, 1)
+		factor = int(schedule.Ease * 1000)
+		if factor == 0 {
+			factor = defaultEase * 1000
+		}
+		reps = schedule.Repetitions + schedule.Lapses
+		lapses = schedule.Lapses
+		encoded, err := json.Marshal(fsrsData{Stability: schedule.Stability, Difficulty: schedule.Difficulty})
+		if err != nil {
+			return err
+		}
+		data = string(encoded)
+	}
+	_, err := tx.Exec(`INSERT INTO cards (id, nid, did, ord, mod, usn, type, queue, due, ivl, factor, reps, lapses,
+		left, odue, odid, flags, data)
+		VALUES (?, ?, ?, 0, ?, -1, ?, ?, ?, ?, ?, ?, ?, 0, 0, 0, 0, ?)`,
+		id, id, deckID, now.Unix(), kind, queue, due, interval, factor, reps, lapses, data,
+	)
+	return err
+}
+
+// checksum is the csum column, the first 8 hex digits of the SHA-1 of the sort field
+func checksum(sortField string) int64 {
+	sum := sha1.Sum([]byte(sortField))
+	value, _ := strconv.ParseInt(hex.EncodeToString(sum[:4]), 16, 64)
+	return value
+}
@@ -0,0 +1,100 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	now := time.Date(2024, time.March, 10, 15, 4, 5, 0, time.UTC)
+	lastReview := time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC)
+	notes := []Note{
+		{
+			GUID:         "card-1",
+			Front:        "What is <b>bold</b> & why?",
+			Back:         "Heavier text\non two lines",
+			Hint:         "HTML",
+			Alternatives: []string{"strong text", "emphasis"},
+			Tags:         []string{"markup", "web design"},
+		},
+		{
+			GUID:  "card-2",
+			Front: "Столица России",
+			Back:  "Москва",
+			Schedule: &Schedule{
+				Repetitions:  3,
+				Lapses:       1,
+				IntervalDays: 12,
+				Ease:         2.36,
+				Stability:    11.8,
+				Difficulty:   6.4,
+				Due:          lastReview.AddDate(0, 0, 12),
+				LastReview:   lastReview,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, "Parent::Exported deck", notes, now); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	if want := []string{"collection.anki2", "media"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("package holds %v, want %v", names, want)
+	}
+
+	pkg, err := Read(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkg.Cards) != 2 || len(pkg.Skipped) != 0 {
+		t.Fatalf("read %d cards and skipped %+v, want 2 and none", len(pkg.Cards), pkg.Skipped)
+	}
+
+	first := pkg.Cards[0]
+	want := Card{
+		Position:     1,
+		NoteGUID:     "card-1",
+		Front:        "What is <b>bold</b> & why?",
+		Back:         "Heavier text\non two lines",
+		Hint:         "HTML",
+		Alternatives: []string{"strong text", "emphasis"},
+		// Anki tags can't hold spaces
+		Tags: []string{"markup", "web_design"},
+		Deck: "Parent::Exported deck",
+	}
+	if !reflect.DeepEqual(first, want) {
+		t.Errorf("new card\n got %+v\nwant %+v", first, want)
+	}
+
+	second := pkg.Cards[1]
+	if second.NoteGUID != "card-2" || second.Front != "Столица России" || second.Back != "Москва" {
+		t.Errorf("studied card %+v", second)
+	}
+	// without a review log the streak is rebuilt from the counts, and the last review from the interval
+	checkSchedule(t, "studied card", second.Schedule, *notes[1].Schedule)
+}
+
+func TestWriteEmptyDeck(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, "Empty", nil, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := Read(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkg.Cards) != 0 {
+		t.Errorf("empty deck read back as %+v", pkg.Cards)
+	}
+}
@@ -0,0 +1,241 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Salladin95/rmqtools"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	AmqpExchange = "broker"
+
+	DeckCreatedKey  = "cards.deck.created"
+	DeckUpdatedKey  = "cards.deck.updated"
+	DeckDeletedKey  = "cards.deck.deleted"
+	CardCreatedKey  = "cards.card.created"
+	CardUpdatedKey  = "cards.card.updated"
+	CardDeletedKey  = "cards.card.deleted"
+	CardReviewedKey = "cards.card.reviewed"
+
+	QuizSessionCompletedKey   = "quiz.session.completed"
+	QuizChallengeCompletedKey = "quiz.challenge.completed"
+
+	// DeckInvitedKey is consumed by the mailer to send email invitations
+	DeckInvitedKey       = "cards.deck.invited"
+	DeckMemberAddedKey   = "cards.deck.member.added"
+	DeckMemberUpdatedKey = "cards.deck.member.updated"
+	DeckMemberRemovedKey = "cards.deck.member.removed"
+
+	DeckPublishedKey = "cards.deck.published"
+	DeckForkedKey    = "cards.deck.forked"
+
+	StreakMilestoneKey = "cards.streak.milestone"
+	// AchievementUnlockedKey is published for in-app notifications when a user earns a badge
+	AchievementUnlockedKey = "cards.achievement.unlocked"
+
+	// ProfileUpdatedKey is published by the auth service, the stats count study days in the timezone of the profile
+	ProfileUpdatedKey = "auth.profile.updated"
+
+	// JobQueuedKey is consumed by the cards service itself to process large imports and exports
+	JobQueuedKey = "cards.job.queued"
+)
+
+// DeckChanged is published when a deck is created or updated
+type DeckChanged struct {
+	DeckID      string   `json:"deckId"`
+	OwnerID     string   `json:"ownerId"`
+	ParentID    string   `json:"parentId,omitempty"`
+	FolderID    string   `json:"folderId,omitempty"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags,omitempty"`
+	UpdatedAt   int64    `json:"updatedAt"`
+}
+
+// DeckDeleted is published when a deck and all of its cards are removed
+type DeckDeleted struct {
+	DeckID  string `json:"deckId"`
+	OwnerID string `json:"ownerId"`
+}
+
+// CardChanged is published when a card is created or updated
+type CardChanged struct {
+	CardID       string   `json:"cardId"`
+	DeckID       string   `json:"deckId"`
+	OwnerID      string   `json:"ownerId"`
+	Front        string   `json:"front"`
+	Back         string   `json:"back"`
+	Hint         string   `json:"hint"`
+	Alternatives []string `json:"alternatives"`
+	Tags         []string `json:"tags"`
+	AnswerType   string   `json:"answerType"`
+	UpdatedAt    int64    `json:"updatedAt"`
+}
+
+type CardDeleted struct {
+	CardID  string `json:"cardId"`
+	DeckID  string `json:"deckId"`
+	OwnerID string `json:"ownerId"`
+}
+
+// DeckInvited is published when a deck is shared by email, it carries the token of the invitation link
+type DeckInvited struct {
+	InvitationID string `json:"invitationId"`
+	DeckID       string `json:"deckId"`
+	DeckTitle    string `json:"deckTitle"`
+	InviterID    string `json:"inviterId"`
+	InviterEmail string `json:"inviterEmail,omitempty"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+	Token        string `json:"token"`
+	ExpiresAt    int64  `json:"expiresAt"`
+}
+
+// DeckMemberChanged is published when a user joins a shared deck, gets another role or leaves it
+type DeckMemberChanged struct {
+	DeckID    string `json:"deckId"`
+	DeckTitle string `json:"deckTitle"`
+	OwnerID   string `json:"ownerId"`
+	UserID    string `json:"userId"`
+	// Role is empty when the member was removed
+	Role      string `json:"role,omitempty"`
+	ChangedBy string `json:"changedBy"`
+}
+
+// DeckPublished is published when a deck enters the catalog or its catalog details change
+type DeckPublished struct {
+	DeckID      string `json:"deckId"`
+	OwnerID     string `json:"ownerId"`
+	Title       string `json:"title"`
+	Category    string `json:"category"`
+	Language    string `json:"language"`
+	PublishedAt int64  `json:"publishedAt"`
+}
+
+// DeckForked is published when a user copies a published deck into their library
+type DeckForked struct {
+	DeckID          string `json:"deckId"`
+	OwnerID         string `json:"ownerId"`
+	UpstreamID      string `json:"upstreamId"`
+	UpstreamOwnerID string `json:"upstreamOwnerId"`
+	Cards           int    `json:"cards"`
+}
+
+// Publisher publishes cards events to the broker exchange
+type Publisher interface {
+	Publish(ctx context.Context, key string, payload any) error
+}
+
+type rabbitPublisher struct {
+	rabbit *amqp.Connection
+}
+
+func NewRabbitPublisher(rabbit *amqp.Connection) Publisher {
+	return &rabbitPublisher{rabbit: rabbit}
+}
+
+func (p *rabbitPublisher) Publish(ctx context.Context, key string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	emitter, err := rmqtools.NewEventEmitter(p.rabbit, AmqpExchange)
+	if err != nil {
+		return err
+	}
+	return emitter.Push(ctx, key, data)
+}
+
+// CardReviewed is published after every answer submitted for scheduling
+type CardReviewed struct {
+	ReviewID     string `json:"reviewId"`
+	UserID       string `json:"userId"`
+	DeckID       string `json:"deckId"`
+	CardID       string `json:"cardId"`
+	Grade        int    `json:"grade"`
+	New          bool   `json:"new"`
+	IntervalDays int    `json:"intervalDays"`
+	// AnswerMs is how long the user took to answer, 0 when it's not measured
+	AnswerMs   int   `json:"answerMs,omitempty"`
+	ReviewedAt int64 `json:"reviewedAt"`
+}
+
+// QuizSessionCompleted is published once per session, when it is finished or its last question is answered
+type QuizSessionCompleted struct {
+	SessionID     string           `json:"sessionId"`
+	UserID        string           `json:"userId"`
+	DeckID        string           `json:"deckId"`
+	Modes         []string         `json:"modes"`
+	Total         int              `json:"total"`
+	Answered      int              `json:"answered"`
+	Correct       int              `json:"correct"`
+	Skipped       int              `json:"skipped"`
+	Accuracy      float64          `json:"accuracy"`
+	ActiveSeconds int64            `json:"activeSeconds"`
+	StartedAt     int64            `json:"startedAt"`
+	CompletedAt   int64            `json:"completedAt"`
+	Results       []QuizCardResult `json:"results"`
+}
+
+// QuizChallengeCompleted is published next to QuizSessionCompleted when a timed challenge ends,
+// leaderboards rank users by its score
+type QuizChallengeCompleted struct {
+	SessionID string `json:"sessionId"`
+	UserID    string `json:"userId"`
+	DeckID    string `json:"deckId"`
+	// Modes of the session, scores of sessions with self-graded questions are kept out of leaderboards
+	Modes            []string `json:"modes"`
+	Score            int      `json:"score"`
+	Answered         int      `json:"answered"`
+	Correct          int      `json:"correct"`
+	MaxCombo         int      `json:"maxCombo"`
+	DurationMs       int64    `json:"durationMs"`
+	TimeLimitSeconds int      `json:"timeLimitSeconds"`
+	// PersonalBest is set when the score beats all previous scores of the user in the deck
+	PersonalBest bool  `json:"personalBest"`
+	CompletedAt  int64 `json:"completedAt"`
+}
+
+type QuizCardResult struct {
+	CardID  string `json:"cardId"`
+	Mode    string `json:"mode"`
+	Correct bool   `json:"correct"`
+	Skipped bool   `json:"skipped"`
+}
+
+// StreakMilestone is published when a streak reaches a milestone, e.g. 7 or 100 days
+type StreakMilestone struct {
+	UserID  string `json:"userId"`
+	Days    int    `json:"days"`
+	Longest int    `json:"longest"`
+	// Day is the study day the milestone was reached on as YYYY-MM-DD
+	Day string `json:"day"`
+}
+
+// AchievementUnlocked is published once per badge, when the user meets the conditions of the achievement
+type AchievementUnlocked struct {
+	UserID        string `json:"userId"`
+	AchievementID string `json:"achievementId"`
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	Icon          string `json:"icon"`
+	// Retroactive is set when the achievement was added after the user met it, clients may group these
+	Retroactive bool  `json:"retroactive"`
+	AwardedAt   int64 `json:"awardedAt"`
+}
+
+// ProfileUpdated is published by the auth service when a user signs up or changes the profile
+type ProfileUpdated struct {
+	UserID   string `json:"userId"`
+	Name     string `json:"name"`
+	Timezone string `json:"timezone"`
+	// UpdatedAt is a unix timestamp in milliseconds
+	UpdatedAt int64 `json:"updatedAt"`
+}
+
+// JobQueued asks a cards worker to process the job
+type JobQueued struct {
+	JobID string `json:"jobId"`
+	Kind  string `json:"kind"`
+}
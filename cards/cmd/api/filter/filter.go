@@ -0,0 +1,258 @@
+// Package filter parses the queries of filtered decks. A query is a list of terms separated by spaces,
+// every term must match:
+//
+//	tag:verbs          the card or its deck is tagged "verbs" or a tag nested in it, like "verbs::irregular"
+//	deck:"Spanish 1"   the card is in a deck of that title or in a deck nested in it
+//	folder:Languages   the card is in a deck filed in that folder or in a folder nested in it
+//	is:new             new | due | review | study, where study takes new and due cards
+//	lapses>=3          lapses and difficulty compare with = : > >= < <=, they only match studied cards
+//
+// tag, deck and folder terms are negated with a leading "-". Several deck and folder terms widen the
+// selection, a card must be in any of them.
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/repositories"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxLength bounds the query text
+	MaxLength = 1000
+	// maxTerms bounds the number of terms, each of them adds a condition to the repository query
+	maxTerms = 30
+)
+
+// Query is a parsed filtered deck query, tags are lower-case, deck titles and folder names are
+// matched ignoring case by the caller
+type Query struct {
+	Tags           []string
+	ExcludeTags    []string
+	Decks          []string
+	ExcludeDecks   []string
+	Folders        []string
+	ExcludeFolders []string
+	// Due is one of the repositories.Due statuses
+	Due         string
+	Comparisons []repositories.Comparison
+}
+
+// SyntaxError describes an invalid query, Offset is the byte offset of the term it is about
+type SyntaxError struct {
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("query: %s at offset %d", e.Msg, e.Offset)
+}
+
+// ErrTooLong is returned for queries longer than MaxLength
+var ErrTooLong = errors.New("query is too long")
+
+var dueStatuses = []string{repositories.DueNew, repositories.DueDue, repositories.DueReview, repositories.DueStudy}
+
+// operators are tried in order, the longer ones first
+var operators = []string{
+	repositories.OpGreaterOrEqual,
+	repositories.OpLessOrEqual,
+	repositories.OpGreater,
+	repositories.OpLess,
+	repositories.OpEqual,
+	":",
+}
+
+// Parse parses and validates a query, an empty query matches every card
+func Parse(text string) (Query, error) {
+	var query Query
+	if len(text) > MaxLength {
+		return query, ErrTooLong
+	}
+	terms, err := split(text)
+	if err != nil {
+		return query, err
+	}
+	if len(terms) > maxTerms {
+		return query, &SyntaxError{Offset: terms[maxTerms].offset, Msg: fmt.Sprintf("more than %d terms", maxTerms)}
+	}
+	for _, t := range terms {
+		if err := query.add(t); err != nil {
+			return Query{}, err
+		}
+	}
+	if query.Due == repositories.DueNew && len(query.Comparisons) > 0 {
+		return Query{}, &SyntaxError{Offset: 0, Msg: "is:new never matches lapses and difficulty, new cards have none"}
+	}
+	return query, nil
+}
+
+// term is a "key:value" or "field>value" term, value is unquoted
+type term struct {
+	offset  int
+	negated bool
+	key     string
+	op      string
+	value   string
+	// quoted values are never taken for operators
+	quoted bool
+}
+
+func (q *Query) add(t term) error {
+	fail := func(format string, args ...any) error {
+		return &SyntaxError{Offset: t.offset, Msg: fmt.Sprintf(format, args...)}
+	}
+	key := strings.ToLower(t.key)
+	if key == "" && t.value == "" {
+		return fail("empty term")
+	}
+	if t.value == "" {
+		return fail("%s has no value", key)
+	}
+
+	switch key {
+	case "tag", "deck", "folder", "is":
+		if t.op != ":" {
+			return fail("%s takes %s:value", key, key)
+		}
+	case repositories.FieldLapses, repositories.FieldDifficulty:
+		if t.negated {
+			return fail("%s can't be negated, use the opposite comparison", key)
+		}
+		value, err := strconv.ParseFloat(t.value, 64)
+		if err != nil || t.quoted {
+			return fail("%s compares to a number, got %q", key, t.value)
+		}
+		if key == repositories.FieldLapses && value != float64(int(value)) {
+			return fail("lapses compares to a whole number, got %q", t.value)
+		}
+		op := t.op
+		if op == ":" {
+			op = repositories.OpEqual
+		}
+		q.Comparisons = append(q.Comparisons, repositories.Comparison{Field: key, Op: op, Value: value})
+		return nil
+	case "":
+		return fail("%q is not a term, use tag:, deck:, folder:, is:, lapses or difficulty", t.value)
+	default:
+		return fail("unknown term %q", key)
+	}
+
+	switch key {
+	case "tag":
+		tag := strings.ToLower(strings.Join(strings.Fields(t.value), " "))
+		if t.negated {
+			q.ExcludeTags = append(q.ExcludeTags, tag)
+		} else {
+			q.Tags = append(q.Tags, tag)
+		}
+	case "deck":
+		if t.negated {
+			q.ExcludeDecks = append(q.ExcludeDecks, t.value)
+		} else {
+			q.Decks = append(q.Decks, t.value)
+		}
+	case "folder":
+		if t.negated {
+			q.ExcludeFolders = append(q.ExcludeFolders, t.value)
+		} else {
+			q.Folders = append(q.Folders, t.value)
+		}
+	case "is":
+		status := strings.ToLower(t.value)
+		switch {
+		case t.negated:
+			return fail("is: can't be negated")
+		case !slices.Contains(dueStatuses, status):
+			return fail("is: takes one of %s, got %q", strings.Join(dueStatuses, ", "), t.value)
+		case q.Due != "":
+			return fail("is: is given twice")
+		}
+		q.Due = status
+	}
+	return nil
+}
+
+// split breaks the text into terms, values may be quoted with double quotes and a backslash escapes a quote
+func split(text string) ([]term, error) {
+	var terms []term
+	i := 0
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if unicode.IsSpace(r) {
+			i += size
+			continue
+		}
+
+		t := term{offset: i}
+		if text[i] == '-' {
+			t.negated = true
+			i++
+		}
+		start := i
+		for i < len(text) && (text[i] == '_' || isLetter(text[i])) {
+			i++
+		}
+		t.key = text[start:i]
+		for _, op := range operators {
+			if t.key != "" && strings.HasPrefix(text[i:], op) {
+				t.op = op
+				i += len(op)
+				break
+			}
+		}
+		if t.op == "" {
+			// a bare word, reported by add
+			t.key = ""
+			i = start
+		}
+
+		if i < len(text) && text[i] == '"' {
+			value, end, err := unquote(text, i)
+			if err != nil {
+				return nil, err
+			}
+			t.value, t.quoted, i = value, true, end
+		} else {
+			end := i
+			for end < len(text) {
+				r, size := utf8.DecodeRuneInString(text[end:])
+				if unicode.IsSpace(r) {
+					break
+				}
+				end += size
+			}
+			t.value, i = text[i:end], end
+		}
+		terms = append(terms, t)
+	}
+	return terms, nil
+}
+
+// unquote reads the quoted value starting at text[start], it returns the offset after the closing quote
+func unquote(text string, start int) (string, int, error) {
+	var value strings.Builder
+	for i := start + 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			if i+1 < len(text) && (text[i+1] == '"' || text[i+1] == '\\') {
+				i++
+			}
+			value.WriteByte(text[i])
+		case '"':
+			return value.String(), i + 1, nil
+		default:
+			value.WriteByte(text[i])
+		}
+	}
+	return "", 0, &SyntaxError{Offset: start, Msg: "unterminated quote"}
+}
+
+func isLetter(b byte) bool {
+	return 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
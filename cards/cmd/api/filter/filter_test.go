@@ -0,0 +1,81 @@
+package filter
+
+import (
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/repositories"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text string
+		want Query
+	}{
+		{"", Query{}},
+		{"tag:Verbs -tag:languages::german", Query{Tags: []string{"verbs"}, ExcludeTags: []string{"languages::german"}}},
+		{`deck:"Spanish 1" deck:French -deck:"say \"hi\""`, Query{
+			Decks:        []string{"Spanish 1", "French"},
+			ExcludeDecks: []string{`say "hi"`},
+		}},
+		{"folder:Languages -folder:Old", Query{Folders: []string{"Languages"}, ExcludeFolders: []string{"Old"}}},
+		{"IS:Due  lapses>=3 difficulty<7.5 lapses:4", Query{
+			Due: repositories.DueDue,
+			Comparisons: []repositories.Comparison{
+				{Field: repositories.FieldLapses, Op: repositories.OpGreaterOrEqual, Value: 3},
+				{Field: repositories.FieldDifficulty, Op: repositories.OpLess, Value: 7.5},
+				{Field: repositories.FieldLapses, Op: repositories.OpEqual, Value: 4},
+			},
+		}},
+		{`tag:"past   tense"`, Query{Tags: []string{"past tense"}}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.text)
+		if err != nil {
+			t.Errorf("parse %q: %v", tt.text, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parse %q = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseRejectsInvalidQueries(t *testing.T) {
+	tests := []struct {
+		text   string
+		offset int
+		msg    string
+	}{
+		{"verbs", 0, "not a term"},
+		{"tag:a color:red", 6, `unknown term "color"`},
+		{"tag:", 0, "no value"},
+		{"tag:a -", 6, "empty term"},
+		{"tag>a", 0, "takes tag:value"},
+		{"is:new is:due", 7, "given twice"},
+		{"is:old", 0, "takes one of"},
+		{"-is:new", 0, "can't be negated"},
+		{"lapses>many", 0, "compares to a number"},
+		{"lapses>1.5", 0, "whole number"},
+		{`lapses:"3"`, 0, "compares to a number"},
+		{"-difficulty>3", 0, "can't be negated"},
+		{"is:new lapses>0", 0, "never matches"},
+		{`deck:"Spanish`, 5, "unterminated quote"},
+		{strings.Repeat("tag:a ", 31), 180, "more than 30 terms"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.text)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("parse %q returned %v, want a syntax error", tt.text, err)
+			continue
+		}
+		if syntaxErr.Offset != tt.offset || !strings.Contains(syntaxErr.Msg, tt.msg) {
+			t.Errorf("parse %q returned %q at %d, want %q at %d", tt.text, syntaxErr.Msg, syntaxErr.Offset, tt.msg, tt.offset)
+		}
+	}
+	if _, err := Parse(strings.Repeat("a", MaxLength+1)); !errors.Is(err, ErrTooLong) {
+		t.Errorf("a long query returned %v", err)
+	}
+}
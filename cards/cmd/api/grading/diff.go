@@ -0,0 +1,101 @@
+package grading
+
+const (
+	// DiffEqual marks text typed correctly
+	DiffEqual = "equal"
+	// DiffMissing marks expected text the answer lacks
+	DiffMissing = "missing"
+	// DiffExtra marks typed text that isn't expected
+	DiffExtra = "extra"
+
+	// maxDiffCells bounds the table of a diff, 1M cells of 8 bytes
+	maxDiffCells = 1 << 20
+)
+
+type DiffSegment struct {
+	Op   string
+	Text string
+}
+
+// Diff returns the edits turning the answer into the expected text, adjacent edits of the same kind are merged.
+// Past a few thousand characters the texts between their shared prefix and suffix are reported as replaced.
+func Diff(answer, expected string) []DiffSegment {
+	a, e := []rune(answer), []rune(expected)
+	prefix := 0
+	for prefix < len(a) && prefix < len(e) && a[prefix] == e[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(e)-prefix && a[len(a)-1-suffix] == e[len(e)-1-suffix] {
+		suffix++
+	}
+
+	var segments []DiffSegment
+	if prefix > 0 {
+		segments = append(segments, DiffSegment{Op: DiffEqual, Text: string(a[:prefix])})
+	}
+	s, t := a[prefix:len(a)-suffix], e[prefix:len(e)-suffix]
+	if (len(s)+1)*(len(t)+1) > maxDiffCells {
+		segments = append(segments, DiffSegment{Op: DiffExtra, Text: string(s)}, DiffSegment{Op: DiffMissing, Text: string(t)})
+	} else {
+		segments = append(segments, diffRunes(s, t)...)
+	}
+	if suffix > 0 {
+		segments = append(segments, DiffSegment{Op: DiffEqual, Text: string(a[len(a)-suffix:])})
+	}
+	return segments
+}
+
+// diffRunes diffs two texts with no shared prefix or suffix
+func diffRunes(s, t []rune) []DiffSegment {
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			if s[i-1] == t[j-1] {
+				d[i][j] = d[i-1][j-1]
+			} else {
+				d[i][j] = min(d[i-1][j], d[i][j-1]) + 1
+			}
+		}
+	}
+
+	// walk back from the end, collecting the edit of every rune in reverse
+	type edit struct {
+		op string
+		r  rune
+	}
+	edits := make([]edit, 0, max(len(s), len(t)))
+	i, j := len(s), len(t)
+	for i > 0 || j > 0 {
+		switch {
+		case i > 0 && j > 0 && s[i-1] == t[j-1] && d[i][j] == d[i-1][j-1]:
+			edits = append(edits, edit{DiffEqual, s[i-1]})
+			i--
+			j--
+		case j > 0 && (i == 0 || d[i][j-1] <= d[i-1][j]):
+			edits = append(edits, edit{DiffMissing, t[j-1]})
+			j--
+		default:
+			edits = append(edits, edit{DiffExtra, s[i-1]})
+			i--
+		}
+	}
+
+	var segments []DiffSegment
+	var text []rune
+	for k := len(edits) - 1; k >= 0; k-- {
+		text = append(text, edits[k].r)
+		if k == 0 || edits[k-1].op != edits[k].op {
+			segments = append(segments, DiffSegment{Op: edits[k].op, Text: string(text)})
+			text = text[:0]
+		}
+	}
+	return segments
+}
@@ -0,0 +1,74 @@
+package grading
+
+// Distance returns the Damerau-Levenshtein distance (optimal string alignment) between two strings,
+// swapping two adjacent characters counts as a single edit.
+// Distances above the limit aren't computed, limit+1 is returned for them.
+// Only three rows of the table are kept, so memory grows with the shorter string alone.
+func Distance(a, b string, limit int) int {
+	s, t := trimCommon([]rune(a), []rune(b))
+	if len(s) < len(t) {
+		s, t = t, s
+	}
+	// the length difference alone takes as many insertions or deletions
+	if len(s)-len(t) > limit {
+		return limit + 1
+	}
+	if len(t) == 0 {
+		return len(s)
+	}
+
+	// previous is the row before current, beforePrevious the one before it, for transpositions
+	beforePrevious := make([]int, len(t)+1)
+	previous := make([]int, len(t)+1)
+	current := make([]int, len(t)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		current[0] = i
+		rowMin := current[0]
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				current[j] = min(current[j], beforePrevious[j-2]+1)
+			}
+			rowMin = min(rowMin, current[j])
+		}
+		// distances never shrink from one row to the next
+		if rowMin > limit {
+			return limit + 1
+		}
+		beforePrevious, previous, current = previous, current, beforePrevious
+	}
+	return min(previous[len(t)], limit+1)
+}
+
+// trimCommon drops the prefix and suffix both strings share, they take no edits
+func trimCommon(s, t []rune) ([]rune, []rune) {
+	for len(s) > 0 && len(t) > 0 && s[0] == t[0] {
+		s, t = s[1:], t[1:]
+	}
+	for len(s) > 0 && len(t) > 0 && s[len(s)-1] == t[len(t)-1] {
+		s, t = s[:len(s)-1], t[:len(t)-1]
+	}
+	return s, t
+}
+
+// Tolerance returns how many typos are forgiven in an answer of the given length,
+// short answers must be exact since a single edit may turn them into another word
+func Tolerance(length int) int {
+	switch {
+	case length <= 3:
+		return 0
+	case length <= 7:
+		return 1
+	case length <= 12:
+		return 2
+	default:
+		return length / 6
+	}
+}
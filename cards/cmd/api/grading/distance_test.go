@@ -0,0 +1,170 @@
+package grading
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// referenceDistance is the textbook full table version of Distance
+func referenceDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(s)][len(t)]
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"photosynthesis", "photosynthesis", 0},
+		{"recieve", "receive", 1},
+		{"ca", "abc", 3},
+		{"привет", "првиет", 1},
+	}
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b, 100); got != tt.want {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := Distance(tt.b, tt.a, 100); got != tt.want {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestDistanceMatchesReference(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	word := func() string {
+		runes := make([]rune, rng.Intn(12))
+		for i := range runes {
+			runes[i] = rune('a' + rng.Intn(4))
+		}
+		return string(runes)
+	}
+	for i := 0; i < 5000; i++ {
+		a, b := word(), word()
+		want := referenceDistance(a, b)
+		for _, limit := range []int{0, 1, 2, 5, 20} {
+			got := Distance(a, b, limit)
+			if want <= limit && got != want || want > limit && got != limit+1 {
+				t.Fatalf("Distance(%q, %q, %d) = %d, reference distance is %d", a, b, limit, got, want)
+			}
+		}
+	}
+}
+
+func TestDistanceLongInput(t *testing.T) {
+	long := strings.Repeat("abcdefghij", 500)
+	if got := Distance(long, strings.Repeat("z", 5000), 3); got != 4 {
+		t.Errorf("Distance of unrelated long strings with limit 3 = %d, want 4", got)
+	}
+	if got := Distance(long, long[:4990], 20); got != 10 {
+		t.Errorf("Distance after dropping 10 characters = %d, want 10", got)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		answer, expected string
+		want             []DiffSegment
+	}{
+		{"paris", "paris", []DiffSegment{{DiffEqual, "paris"}}},
+		{"pars", "paris", []DiffSegment{{DiffEqual, "par"}, {DiffMissing, "i"}, {DiffEqual, "s"}}},
+		{"pariss", "paris", []DiffSegment{{DiffEqual, "paris"}, {DiffExtra, "s"}}},
+		{"london", "paris", []DiffSegment{{DiffExtra, "london"}, {DiffMissing, "paris"}}},
+		{"recieve", "receive", []DiffSegment{{DiffEqual, "rec"}, {DiffExtra, "i"}, {DiffEqual, "e"}, {DiffMissing, "i"}, {DiffEqual, "ve"}}},
+		{"", "paris", []DiffSegment{{DiffMissing, "paris"}}},
+	}
+	for _, tt := range tests {
+		got := Diff(tt.answer, tt.expected)
+		if !equalSegments(got, tt.want) {
+			t.Errorf("Diff(%q, %q) = %v, want %v", tt.answer, tt.expected, got, tt.want)
+		}
+		answer, expected := applyDiff(got)
+		if answer != tt.answer || expected != tt.expected {
+			t.Errorf("Diff(%q, %q) rebuilds %q and %q", tt.answer, tt.expected, answer, expected)
+		}
+	}
+}
+
+func TestDiffLongInput(t *testing.T) {
+	answer := "x" + strings.Repeat("a", 4000) + "y"
+	expected := "x" + strings.Repeat("b", 4000) + "y"
+	got := Diff(answer, expected)
+	want := []DiffSegment{
+		{DiffEqual, "x"},
+		{DiffExtra, strings.Repeat("a", 4000)},
+		{DiffMissing, strings.Repeat("b", 4000)},
+		{DiffEqual, "y"},
+	}
+	if !equalSegments(got, want) {
+		t.Errorf("Diff of long texts has %d segments, want the coarse 4", len(got))
+	}
+}
+
+func TestGradeRejectsOversizedAnswers(t *testing.T) {
+	result := Grade(strings.Repeat("paris ", 800), []string{"Paris"}, Options{})
+	if result.Verdict != VerdictWrong || result.Reason == "" || result.Diff != nil {
+		t.Errorf("Grade of an oversized answer = %+v, want a wrong verdict with a reason and no diff", result)
+	}
+}
+
+func TestGradePicksClosestAnswer(t *testing.T) {
+	result := Grade("colour", []string{"shade", "color", "colours"}, Options{})
+	if result.Verdict != VerdictAlmost || result.Matched != "color" || result.Distance != 1 {
+		t.Errorf("Grade = %+v, want almost matching color at distance 1", result)
+	}
+	result = Grade("hue", []string{"shade", "color"}, Options{})
+	if result.Verdict != VerdictWrong || result.Matched != "shade" || result.Distance != 3 {
+		t.Errorf("Grade = %+v, want wrong matching shade at distance 3", result)
+	}
+}
+
+func equalSegments(a, b []DiffSegment) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// applyDiff returns the answer and the expected text a diff was made of
+func applyDiff(segments []DiffSegment) (string, string) {
+	var answer, expected strings.Builder
+	for _, segment := range segments {
+		if segment.Op != DiffMissing {
+			answer.WriteString(segment.Text)
+		}
+		if segment.Op != DiffExtra {
+			expected.WriteString(segment.Text)
+		}
+	}
+	return answer.String(), expected.String()
+}
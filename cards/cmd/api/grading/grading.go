@@ -0,0 +1,128 @@
+// Package grading checks typed answers against the accepted ones, forgiving formatting and small typos.
+package grading
+
+import "unicode/utf8"
+
+const (
+	VerdictCorrect = "correct"
+	// VerdictAlmost means the answer is right apart from a few typos
+	VerdictAlmost = "almost"
+	VerdictWrong  = "wrong"
+)
+
+const (
+	// AnswerText answers are compared as text
+	AnswerText = "text"
+	// AnswerNumeric answers are numbers with optional units
+	AnswerNumeric = "numeric"
+)
+
+const (
+	// answers longer than maxLengthRatio times the longest accepted answer plus maxExtraLength
+	// are wrong without comparing them, which keeps oversized answers from tying up the grader
+	maxLengthRatio = 2
+	maxExtraLength = 20
+)
+
+type Options struct {
+	// Strict turns off typo tolerance, only formatting differences are forgiven
+	Strict bool
+	// AbsoluteTolerance and RelativeTolerance bound the error of numeric answers,
+	// the absolute one is expressed in the unit of the expected answer
+	AbsoluteTolerance float64
+	RelativeTolerance float64
+}
+
+// Grader grades an answer against the accepted ones
+type Grader interface {
+	Grade(answer string, accepted []string) Result
+}
+
+// New returns the grader for the answer type, unknown types are graded as text
+func New(answerType string, opts Options) Grader {
+	if answerType == AnswerNumeric {
+		return NumericGrader{opts: opts}
+	}
+	return TextGrader{opts: opts}
+}
+
+// Validate checks that the accepted answer can be graded as the answer type
+func Validate(answerType, accepted string) error {
+	if answerType == AnswerNumeric {
+		return ValidateNumeric(accepted)
+	}
+	return nil
+}
+
+type TextGrader struct {
+	opts Options
+}
+
+func (g TextGrader) Grade(answer string, accepted []string) Result {
+	return Grade(answer, accepted, g.opts)
+}
+
+type Result struct {
+	Verdict string
+	// Matched is the accepted answer closest to the typed one
+	Matched  string
+	Distance int
+	// Diff shows how to turn the normalized answer into the normalized Matched
+	Diff []DiffSegment
+	// Reason explains a verdict that isn't obvious from the answer, e.g. a missing unit
+	Reason string
+}
+
+// Correct reports whether the answer should be counted, answers with forgiven typos are
+func (r Result) Correct() bool {
+	return r.Verdict != VerdictWrong
+}
+
+// Grade compares the typed answer with each accepted answer as text and returns the best match
+func Grade(answer string, accepted []string, opts Options) Result {
+	normalizedAnswer := Normalize(answer)
+	answerLength := utf8.RuneCountInString(normalizedAnswer)
+
+	longest := 0
+	for _, candidate := range accepted {
+		longest = max(longest, utf8.RuneCountInString(Normalize(candidate)))
+	}
+	if answerLength > longest*maxLengthRatio+maxExtraLength {
+		return Result{Verdict: VerdictWrong, Reason: "answer is much longer than expected"}
+	}
+
+	best := Result{Verdict: VerdictWrong, Distance: -1}
+	var bestNormalized string
+	for _, candidate := range accepted {
+		normalized := Normalize(candidate)
+		if normalized == "" {
+			continue
+		}
+		// candidates no closer than the best one so far are given up early
+		limit := max(answerLength, utf8.RuneCountInString(normalized))
+		if best.Distance != -1 {
+			limit = best.Distance - 1
+		}
+		distance := Distance(normalizedAnswer, normalized, limit)
+		if distance <= limit {
+			best.Matched = candidate
+			best.Distance = distance
+			bestNormalized = normalized
+		}
+		if best.Distance == 0 {
+			break
+		}
+	}
+	if best.Distance == -1 {
+		return Result{Verdict: VerdictWrong}
+	}
+
+	switch {
+	case best.Distance == 0:
+		best.Verdict = VerdictCorrect
+	case !opts.Strict && best.Distance <= Tolerance(utf8.RuneCountInString(bestNormalized)):
+		best.Verdict = VerdictAlmost
+	}
+	best.Diff = Diff(normalizedAnswer, bestNormalized)
+	return best
+}
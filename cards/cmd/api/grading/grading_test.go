@@ -0,0 +1,97 @@
+package grading
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"", ""},
+		{"  Hello,   World! ", "hello world"},
+		{"tab\tand\nnewline", "tab and newline"},
+		{"Café", "cafe"},
+		{"naïve résumé", "naive resume"},
+		{"Ångström", "angstrom"},
+		{"rock-'n'-roll", "rock n roll"},
+		{"C++", "c"},
+		{"3.14", "3 14"},
+		{"?!", ""},
+		{"ПРИВЕТ", "привет"},
+		{"Straße", "straße"},
+		{"日本語", "日本語"},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.text); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTolerance(t *testing.T) {
+	tests := []struct {
+		length, want int
+	}{
+		{0, 0}, {3, 0}, {4, 1}, {7, 1}, {8, 2}, {12, 2}, {13, 2}, {18, 3}, {30, 5},
+	}
+	for _, tt := range tests {
+		if got := Tolerance(tt.length); got != tt.want {
+			t.Errorf("Tolerance(%d) = %d, want %d", tt.length, got, tt.want)
+		}
+	}
+}
+
+func TestGrade(t *testing.T) {
+	tests := []struct {
+		name     string
+		answer   string
+		accepted []string
+		opts     Options
+		verdict  string
+		matched  string
+		distance int
+	}{
+		{"formatting is forgiven", "Hello, World!", []string{"hello world"}, Options{}, VerdictCorrect, "hello world", 0},
+		{"accents are forgiven", "resume", []string{"résumé"}, Options{}, VerdictCorrect, "résumé", 0},
+		{"short answers are exact", "cot", []string{"cat"}, Options{}, VerdictWrong, "cat", 1},
+		{"one typo in a short word", "hous", []string{"house"}, Options{}, VerdictAlmost, "house", 1},
+		{"swapped letters are one typo", "huose", []string{"house"}, Options{}, VerdictAlmost, "house", 1},
+		{"two typos in a longer word", "elefant", []string{"elephant"}, Options{}, VerdictAlmost, "elephant", 2},
+		{"too many typos", "elefent", []string{"elephant"}, Options{}, VerdictWrong, "elephant", 3},
+		{"tolerance grows with the length", "the quick brwn fox jumsp ovr", []string{"the quick brown fox jumps over"}, Options{}, VerdictAlmost, "the quick brown fox jumps over", 3},
+		{"strict grading", "hous", []string{"house"}, Options{Strict: true}, VerdictWrong, "house", 1},
+		{"strict grading forgives formatting", "HOUSE!", []string{"house"}, Options{Strict: true}, VerdictCorrect, "house", 0},
+		{"alternative", "hi", []string{"hello", "hi"}, Options{}, VerdictCorrect, "hi", 0},
+		{"typo in an alternative", "greetngs", []string{"hello", "greetings"}, Options{}, VerdictAlmost, "greetings", 1},
+		{"matched keeps its formatting", "hello", []string{"Hello!"}, Options{}, VerdictCorrect, "Hello!", 0},
+		{"empty accepted answers are skipped", "hello", []string{"?!"}, Options{}, VerdictWrong, "", 0},
+	}
+	for _, tt := range tests {
+		result := Grade(tt.answer, tt.accepted, tt.opts)
+		if result.Verdict != tt.verdict || result.Matched != tt.matched || result.Distance != tt.distance {
+			t.Errorf("%s: got %s %q at %d, want %s %q at %d",
+				tt.name, result.Verdict, result.Matched, result.Distance, tt.verdict, tt.matched, tt.distance)
+		}
+		if result.Correct() != (tt.verdict != VerdictWrong) {
+			t.Errorf("%s: Correct() = %t for a %s verdict", tt.name, result.Correct(), result.Verdict)
+		}
+	}
+}
+
+func TestGradeDiff(t *testing.T) {
+	// the diff compares the normalized texts
+	result := Grade("Hous", []string{"House!"}, Options{})
+	want := []DiffSegment{{DiffEqual, "hous"}, {DiffMissing, "e"}}
+	if !equalSegments(result.Diff, want) {
+		t.Errorf("diff %v, want %v", result.Diff, want)
+	}
+
+	result = Grade("Paris", []string{"paris"}, Options{})
+	if want := []DiffSegment{{DiffEqual, "paris"}}; !equalSegments(result.Diff, want) {
+		t.Errorf("diff of a correct answer %v, want %v", result.Diff, want)
+	}
+
+	result = Grade("hello", []string{"?!"}, Options{})
+	if result.Diff != nil {
+		t.Errorf("diff %v without an accepted answer", result.Diff)
+	}
+}
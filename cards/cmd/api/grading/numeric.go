@@ -0,0 +1,61 @@
+package grading
+
+import "math"
+
+// defaultRelativeTolerance absorbs float rounding when a card doesn't define any tolerance
+const defaultRelativeTolerance = 1e-9
+
+// NumericGrader compares numbers with units after converting them to SI base units
+type NumericGrader struct {
+	opts Options
+}
+
+func (g NumericGrader) Grade(answer string, accepted []string) Result {
+	given, err := parseQuantity(answer)
+	if err != nil {
+		return Result{Verdict: VerdictWrong, Reason: "answer is not a number with a known unit"}
+	}
+
+	result := Result{Verdict: VerdictWrong}
+	for _, candidate := range accepted {
+		expected, err := parseQuantity(candidate)
+		if err != nil {
+			continue
+		}
+		switch {
+		case given.dimension == expected.dimension && g.within(given.value, expected):
+			return Result{Verdict: VerdictCorrect, Matched: candidate}
+		case !given.hasUnit && expected.hasUnit && g.sameNumber(given.value, candidate):
+			// the number is right in the expected unit, only the unit is missing
+			result = Result{Verdict: VerdictAlmost, Matched: candidate, Reason: "unit is missing"}
+		case result.Verdict == VerdictWrong && given.dimension != expected.dimension:
+			result = Result{Verdict: VerdictWrong, Matched: candidate, Reason: "units don't match"}
+		}
+	}
+	if result.Matched == "" && len(accepted) > 0 {
+		result.Matched = accepted[0]
+	}
+	return result
+}
+
+// within compares the value with the expected quantity, both in SI base units
+func (g NumericGrader) within(value float64, expected quantity) bool {
+	diff := math.Abs(value - expected.value)
+	if g.opts.AbsoluteTolerance == 0 && g.opts.RelativeTolerance == 0 {
+		return diff <= defaultRelativeTolerance*math.Abs(expected.value)
+	}
+	// the absolute tolerance is given in the unit of the expected answer
+	if g.opts.AbsoluteTolerance > 0 && diff <= g.opts.AbsoluteTolerance*expected.factor {
+		return true
+	}
+	return g.opts.RelativeTolerance > 0 && diff <= g.opts.RelativeTolerance*math.Abs(expected.value)
+}
+
+// sameNumber compares a unitless value with the number written in the expected answer
+func (g NumericGrader) sameNumber(value float64, expected string) bool {
+	number, _, err := parseNumber(expected)
+	if err != nil {
+		return false
+	}
+	return g.within(value, quantity{value: number, factor: 1})
+}
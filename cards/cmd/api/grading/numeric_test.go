@@ -0,0 +1,93 @@
+package grading
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		text      string
+		value     float64
+		dimension dimension
+	}{
+		{"1000", 1000, dimensionless},
+		{"1,000", 1000, dimensionless},
+		{"-12,345,678", -12345678, dimensionless},
+		{"1,000.5", 1000.5, dimensionless},
+		{"1,000 m", 1000, length},
+		{"3,14", 3.14, dimensionless},
+		{"1,0005", 1.0005, dimensionless},
+		{"0,5 kg", 0.5, mass},
+		{"1/3", 1.0 / 3, dimensionless},
+		{"-2/5", -0.4, dimensionless},
+		{"1 1/2 h", 5400, duration},
+		{"6.02e23 mol^-1", 6.02e23, dimension{0, 0, 0, 0, 0, -1, 0}},
+		{"1.5×10^3", 1500, dimensionless},
+		{"1.5*10^-3 s", 1.5e-3, duration},
+		{"1.5·10⁻³", 1.5e-3, dimensionless},
+		{"5 km", 5000, length},
+		{"1 dam", 10, length},
+		{"3 mA", 3e-3, current},
+		{"2 µs", 2e-6, duration},
+		{"1 kg", 1, mass},
+		{"36 km/h", 10, dimension{1, 0, -1, 0, 0, 0, 0}},
+		{"9.81 m/s^2", 9.81, dimension{1, 0, -2, 0, 0, 0, 0}},
+		{"2 kg·m²/s²", 2, dimension{2, 1, -2, 0, 0, 0, 0}},
+		{"50 %", 0.5, dimensionless},
+	}
+	for _, tt := range tests {
+		q, err := parseQuantity(tt.text)
+		if err != nil {
+			t.Errorf("parseQuantity(%q): %v", tt.text, err)
+			continue
+		}
+		if math.Abs(q.value-tt.value) > 1e-9*math.Abs(tt.value) || q.dimension != tt.dimension {
+			t.Errorf("parseQuantity(%q) = %g %v, want %g %v", tt.text, q.value, q.dimension, tt.value, tt.dimension)
+		}
+	}
+
+	for _, text := range []string{"", "abc", "1/0", "1,000,5", "12,345,67", "5 parsec", "3 m^x"} {
+		if _, err := parseQuantity(text); err == nil {
+			t.Errorf("parseQuantity(%q) succeeded, want an error", text)
+		}
+	}
+}
+
+func TestNumericGrader(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		answer   string
+		accepted []string
+		verdict  string
+		reason   string
+	}{
+		{"thousands separator in the answer", Options{}, "1,000", []string{"1000"}, VerdictCorrect, ""},
+		{"thousands separator in the accepted answer", Options{}, "1000", []string{"1,000"}, VerdictCorrect, ""},
+		{"thousands are not a decimal comma", Options{}, "1", []string{"1,000"}, VerdictWrong, ""},
+		{"decimal comma", Options{}, "3,5", []string{"3.5"}, VerdictCorrect, ""},
+		{"float rounding", Options{}, "0.30000000000000004", []string{"0.3"}, VerdictCorrect, ""},
+		{"fraction", Options{}, "1/4", []string{"0.25"}, VerdictCorrect, ""},
+		{"scientific notation", Options{}, "6.02×10^23", []string{"6.02e23"}, VerdictCorrect, ""},
+		{"converted unit", Options{}, "5000 m", []string{"5 km"}, VerdictCorrect, ""},
+		{"absolute tolerance", Options{AbsoluteTolerance: 0.01}, "9.8 m/s^2", []string{"9.81 m/s^2"}, VerdictCorrect, ""},
+		{"outside absolute tolerance", Options{AbsoluteTolerance: 0.01}, "9.7 m/s^2", []string{"9.81 m/s^2"}, VerdictWrong, ""},
+		{"absolute tolerance in the expected unit", Options{AbsoluteTolerance: 0.1}, "5050 m", []string{"5 km"}, VerdictCorrect, ""},
+		{"outside absolute tolerance in the expected unit", Options{AbsoluteTolerance: 0.1}, "5200 m", []string{"5 km"}, VerdictWrong, ""},
+		{"relative tolerance", Options{RelativeTolerance: 0.05}, "104", []string{"100"}, VerdictCorrect, ""},
+		{"outside relative tolerance", Options{RelativeTolerance: 0.05}, "106", []string{"100"}, VerdictWrong, ""},
+		{"either tolerance", Options{AbsoluteTolerance: 1, RelativeTolerance: 0.05}, "105", []string{"100"}, VerdictCorrect, ""},
+		{"missing unit", Options{}, "5", []string{"5 km"}, VerdictAlmost, "unit is missing"},
+		{"missing unit and wrong number", Options{}, "5000", []string{"5 km"}, VerdictWrong, "units don't match"},
+		{"wrong unit", Options{}, "5 kg", []string{"5 km"}, VerdictWrong, "units don't match"},
+		{"unknown unit", Options{}, "5 parsec", []string{"5 km"}, VerdictWrong, "answer is not a number with a known unit"},
+		{"any accepted answer", Options{}, "0.5", []string{"1/3", "1/2"}, VerdictCorrect, ""},
+	}
+	for _, tt := range tests {
+		result := New(AnswerNumeric, tt.opts).Grade(tt.answer, tt.accepted)
+		if result.Verdict != tt.verdict || result.Reason != tt.reason {
+			t.Errorf("%s: got %s %q, want %s %q", tt.name, result.Verdict, result.Reason, tt.verdict, tt.reason)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package grading
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// quantity is a value converted to SI base units
+type quantity struct {
+	value     float64
+	dimension dimension
+	// factor converts the unit the quantity was written in to SI base units
+	factor float64
+	// hasUnit tells "5" apart from "5 m"
+	hasUnit bool
+}
+
+var (
+	// fractionPattern matches "1/3", "-2/5" and mixed numbers like "1 1/2"
+	fractionPattern = regexp.MustCompile(`^([+-]?)(?:(\d+)\s+)?(\d+)\s*/\s*(\d+)`)
+	// powerOfTenPattern matches "1.5×10^3", "1.5*10^-3" and "1.5·10⁻³"
+	powerOfTenPattern = regexp.MustCompile(`^([+-]?(?:\d+\.?\d*|\.\d+))\s*[×x*·]\s*10\^?([+-]?\d+)`)
+	decimalPattern    = regexp.MustCompile(`^[+-]?(?:\d+\.?\d*|\.\d+)(?:[eE][+-]?\d+)?`)
+	// thousandsPattern matches a number with comma separated thousands, e.g. "1,000" or "-12,345,678"
+	thousandsPattern = regexp.MustCompile(`^[+-]?\d{1,3}(?:,\d{3})+`)
+)
+
+// parseQuantity parses a number followed by an optional unit, e.g. "9.81 m/s^2", "1/3" or "6.02e23 mol^-1"
+func parseQuantity(text string) (quantity, error) {
+	text = normalizeSeparators(strings.TrimSpace(superscripts.Replace(text)))
+	value, rest, err := parseNumber(text)
+	if err != nil {
+		return quantity{}, err
+	}
+	u, err := parseUnit(rest)
+	if err != nil {
+		return quantity{}, err
+	}
+	return quantity{value: value * u.factor, dimension: u.dimension, factor: u.factor, hasUnit: strings.TrimSpace(rest) != ""}, nil
+}
+
+// normalizeSeparators drops the commas between thousands, a comma followed by exactly three digits is one,
+// and otherwise accepts a decimal comma when there is no decimal point. Numbers mixing both, like "1,000,5",
+// are left for parseNumber to reject.
+func normalizeSeparators(text string) string {
+	if m := thousandsPattern.FindString(text); m != "" {
+		if rest := text[len(m):]; rest == "" || (rest[0] != ',' && (rest[0] < '0' || rest[0] > '9')) {
+			return strings.ReplaceAll(m, ",", "") + rest
+		}
+	}
+	if !strings.Contains(text, ".") {
+		return strings.Replace(text, ",", ".", 1)
+	}
+	return text
+}
+
+// parseNumber reads the number at the start of the text and returns the remainder
+func parseNumber(text string) (float64, string, error) {
+	if m := fractionPattern.FindStringSubmatch(text); m != nil {
+		whole, _ := strconv.ParseFloat(withDefault(m[2], "0"), 64)
+		numerator, _ := strconv.ParseFloat(m[3], 64)
+		denominator, _ := strconv.ParseFloat(m[4], 64)
+		if denominator == 0 {
+			return 0, "", fmt.Errorf("division by zero in %q", text)
+		}
+		value := whole + numerator/denominator
+		if m[1] == "-" {
+			value = -value
+		}
+		return value, text[len(m[0]):], nil
+	}
+	if m := powerOfTenPattern.FindStringSubmatch(text); m != nil {
+		mantissa, _ := strconv.ParseFloat(m[1], 64)
+		exponent, _ := strconv.Atoi(m[2])
+		return mantissa * math.Pow(10, float64(exponent)), text[len(m[0]):], nil
+	}
+	if m := decimalPattern.FindString(text); m != "" {
+		value, err := strconv.ParseFloat(m, 64)
+		if err != nil {
+			return 0, "", err
+		}
+		return value, text[len(m):], nil
+	}
+	return 0, "", fmt.Errorf("%q is not a number", text)
+}
+
+func withDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// ValidateNumeric checks that the text is a number with an optional known unit
+func ValidateNumeric(text string) error {
+	_, err := parseQuantity(text)
+	return err
+}
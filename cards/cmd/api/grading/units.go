@@ -0,0 +1,183 @@
+package grading
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// dimension holds exponents of the SI base units: m, kg, s, A, K, mol, cd
+type dimension [7]int
+
+// unit is a multiple of a combination of SI base units
+type unit struct {
+	factor    float64
+	dimension dimension
+}
+
+var (
+	dimensionless = dimension{}
+	length        = dimension{1, 0, 0, 0, 0, 0, 0}
+	mass          = dimension{0, 1, 0, 0, 0, 0, 0}
+	duration      = dimension{0, 0, 1, 0, 0, 0, 0}
+	current       = dimension{0, 0, 0, 1, 0, 0, 0}
+	temperature   = dimension{0, 0, 0, 0, 1, 0, 0}
+	amount        = dimension{0, 0, 0, 0, 0, 1, 0}
+	luminosity    = dimension{0, 0, 0, 0, 0, 0, 1}
+)
+
+// units lists the symbols that may carry an SI prefix
+var units = map[string]unit{
+	"m":   {1, length},
+	"g":   {1e-3, mass},
+	"s":   {1, duration},
+	"A":   {1, current},
+	"K":   {1, temperature},
+	"mol": {1, amount},
+	"cd":  {1, luminosity},
+	"Hz":  {1, dimension{0, 0, -1, 0, 0, 0, 0}},
+	"N":   {1, dimension{1, 1, -2, 0, 0, 0, 0}},
+	"Pa":  {1, dimension{-1, 1, -2, 0, 0, 0, 0}},
+	"J":   {1, dimension{2, 1, -2, 0, 0, 0, 0}},
+	"W":   {1, dimension{2, 1, -3, 0, 0, 0, 0}},
+	"C":   {1, dimension{0, 0, 1, 1, 0, 0, 0}},
+	"V":   {1, dimension{2, 1, -3, -1, 0, 0, 0}},
+	"Ω":   {1, dimension{2, 1, -3, -2, 0, 0, 0}},
+	"ohm": {1, dimension{2, 1, -3, -2, 0, 0, 0}},
+	"F":   {1, dimension{-2, -1, 4, 2, 0, 0, 0}},
+	"T":   {1, dimension{0, 1, -2, -1, 0, 0, 0}},
+	"L":   {1e-3, dimension{3, 0, 0, 0, 0, 0, 0}},
+	"l":   {1e-3, dimension{3, 0, 0, 0, 0, 0, 0}},
+	"eV":  {1.602176634e-19, dimension{2, 1, -2, 0, 0, 0, 0}},
+}
+
+// plainUnits can't take a prefix
+var plainUnits = map[string]unit{
+	"min": {60, duration},
+	"h":   {3600, duration},
+	"d":   {86400, duration},
+	"%":   {0.01, dimensionless},
+	"°":   {math.Pi / 180, dimensionless},
+	"deg": {math.Pi / 180, dimensionless},
+	"rad": {1, dimensionless},
+}
+
+// prefixes are tried in order, "da" must come before "d"
+var prefixes = []struct {
+	symbol string
+	factor float64
+}{
+	{"da", 1e1},
+	{"T", 1e12},
+	{"G", 1e9},
+	{"M", 1e6},
+	{"k", 1e3},
+	{"h", 1e2},
+	{"d", 1e-1},
+	{"c", 1e-2},
+	{"m", 1e-3},
+	{"µ", 1e-6},
+	{"μ", 1e-6},
+	{"u", 1e-6},
+	{"n", 1e-9},
+	{"p", 1e-12},
+}
+
+var superscripts = strings.NewReplacer("⁻", "-", "¹", "1", "²", "2", "³", "3", "⁴", "4")
+
+// parseUnit parses expressions like "m/s^2", "kg·m²/s²" or "km/h";
+// every symbol after a slash goes into the denominator
+func parseUnit(text string) (unit, error) {
+	result := unit{factor: 1}
+	text = strings.TrimSpace(superscripts.Replace(text))
+	if text == "" {
+		return result, nil
+	}
+
+	sign := 1
+	var term strings.Builder
+	flush := func() error {
+		if term.Len() == 0 {
+			return nil
+		}
+		u, err := parseUnitTerm(term.String())
+		if err != nil {
+			return err
+		}
+		result.factor *= math.Pow(u.factor, float64(sign))
+		for i := range result.dimension {
+			result.dimension[i] += sign * u.dimension[i]
+		}
+		term.Reset()
+		return nil
+	}
+	for _, r := range text {
+		switch r {
+		case '*', '·', '⋅', ' ':
+			if err := flush(); err != nil {
+				return unit{}, err
+			}
+		case '/':
+			if err := flush(); err != nil {
+				return unit{}, err
+			}
+			sign = -1
+		default:
+			term.WriteRune(r)
+		}
+	}
+	if err := flush(); err != nil {
+		return unit{}, err
+	}
+	return result, nil
+}
+
+// parseUnitTerm parses a single symbol with an optional exponent, e.g. "km", "s^2" or "m2"
+func parseUnitTerm(term string) (unit, error) {
+	symbol, exponent := term, 1
+	if i := strings.IndexByte(term, '^'); i >= 0 {
+		e, err := strconv.Atoi(term[i+1:])
+		if err != nil {
+			return unit{}, fmt.Errorf("invalid exponent in %q", term)
+		}
+		symbol, exponent = term[:i], e
+	} else if i := strings.IndexFunc(term, func(r rune) bool { return unicode.IsDigit(r) || r == '-' }); i > 0 {
+		e, err := strconv.Atoi(term[i:])
+		if err != nil {
+			return unit{}, fmt.Errorf("invalid exponent in %q", term)
+		}
+		symbol, exponent = term[:i], e
+	}
+
+	u, ok := lookupUnit(symbol)
+	if !ok {
+		return unit{}, fmt.Errorf("unknown unit %q", symbol)
+	}
+	result := unit{factor: math.Pow(u.factor, float64(exponent))}
+	for i := range u.dimension {
+		result.dimension[i] = u.dimension[i] * exponent
+	}
+	return result, nil
+}
+
+func lookupUnit(symbol string) (unit, bool) {
+	if u, ok := plainUnits[symbol]; ok {
+		return u, true
+	}
+	if u, ok := units[symbol]; ok {
+		return u, true
+	}
+	// kilograms are the base unit of mass, so "kg" is found as prefix "k" and unit "g"
+	for _, prefix := range prefixes {
+		rest, found := strings.CutPrefix(symbol, prefix.symbol)
+		if !found || rest == "" {
+			continue
+		}
+		if u, ok := units[rest]; ok {
+			return unit{factor: prefix.factor * u.factor, dimension: u.dimension}, true
+		}
+	}
+	return unit{}, false
+}
@@ -0,0 +1,67 @@
+// Package leaderboard ranks users by weekly scores kept in sorted sets, in memory or in Redis.
+//
+// Boards reset every week, weeks start on Monday at midnight UTC for every user so all of them compete over
+// the same days. Scores carry a tie breaker in their fraction: of two users with the same whole score the one
+// who reached it first ranks higher.
+package leaderboard
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// MetricChallenge ranks the best timed challenge score of the week
+	MetricChallenge = "challenge"
+	// MetricVolume ranks the number of cards reviewed in the week
+	MetricVolume = "volume"
+
+	// KeptWeeks is the number of past weeks whose boards can still be read
+	KeptWeeks = 4
+
+	week = 7 * 24 * time.Hour
+	// tieSpan is the number of seconds after tieEpoch the tie breaker tells apart, about 136 years
+	tieSpan = 1 << 32
+)
+
+var tieEpoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// WeekStart returns the Monday midnight UTC the week of t started at
+func WeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	year, month, day := t.Date()
+	// time.Sunday is 0, Monday starts the week
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(year, month, day-offset, 0, 0, 0, 0, time.UTC)
+}
+
+// ExpireAt returns when the board of the week starting at weekStart is dropped
+func ExpireAt(weekStart time.Time) time.Time {
+	return weekStart.Add((KeptWeeks + 1) * week)
+}
+
+// Key names the sorted set of a board, the global one when deckID is empty
+func Key(metric, deckID string, weekStart time.Time) string {
+	scope := "global"
+	if deckID != "" {
+		scope = "deck:" + deckID
+	}
+	return "leaderboard:" + metric + ":" + scope + ":" + weekStart.Format(time.DateOnly)
+}
+
+// TieBreaker returns the fraction added to a score reached at the time, earlier times get larger fractions.
+// Whole scores below 2^20 keep every second apart within the precision of a float64.
+func TieBreaker(at time.Time) float64 {
+	seconds := min(max(at.Unix()-tieEpoch.Unix(), 0), tieSpan-1)
+	return float64(tieSpan-seconds) / float64(tieSpan+1)
+}
+
+// Score returns the whole score with the tie breaker of the time it was reached
+func Score(whole int, at time.Time) float64 {
+	return float64(whole) + TieBreaker(at)
+}
+
+// Whole drops the tie breaker of a score
+func Whole(score float64) int {
+	return int(math.Floor(score))
+}
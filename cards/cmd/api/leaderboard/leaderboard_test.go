@@ -0,0 +1,94 @@
+package leaderboard
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWeekStart(t *testing.T) {
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC), "2024-03-04"},
+		{time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC), "2024-03-04"},
+		{time.Date(2024, time.March, 10, 23, 59, 0, 0, time.UTC), "2024-03-04"},
+		// Monday 1 am in Berlin is still Sunday in UTC
+		{time.Date(2024, time.March, 11, 0, 30, 0, 0, time.FixedZone("CET", 3600)), "2024-03-04"},
+	}
+	for _, tt := range tests {
+		if got := WeekStart(tt.at).Format(time.DateOnly); got != tt.want {
+			t.Errorf("%v: got %s, want %s", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestTieBreaker(t *testing.T) {
+	earlier := Score(1000, time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC))
+	later := Score(1000, time.Date(2024, time.March, 4, 9, 0, 1, 0, time.UTC))
+	if earlier <= later {
+		t.Errorf("got %f for the earlier score and %f for the later one, want the earlier one higher", earlier, later)
+	}
+	if Whole(earlier) != 1000 || Whole(later) != 1000 || Whole(Score(1001, time.Time{})) != 1001 {
+		t.Errorf("whole scores changed: %d, %d", Whole(earlier), Whole(later))
+	}
+}
+
+func TestMemorySortedSet(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+	set := NewMemorySortedSet(func() time.Time { return now })
+	expireAt := now.Add(time.Hour)
+
+	// a lower score keeps the best one
+	for _, score := range []float64{10.5, 30.5, 20.5} {
+		if err := set.SetMax(ctx, "best", "ann", score, expireAt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, score, err := set.Rank(ctx, "best", "ann"); err != nil || score != 30.5 {
+		t.Errorf("got %f, %v, want 30.5", score, err)
+	}
+
+	// a source is added once and the fraction follows the latest addition
+	additions := []struct {
+		member, source string
+		fraction       float64
+	}{{"ann", "r1", 0.9}, {"ann", "r1", 0.9}, {"bob", "r2", 0.8}, {"ann", "r3", 0.7}, {"bob", "r4", 0.6}, {"cid", "r5", 0.5}}
+	for _, addition := range additions {
+		if err := set.AddWhole(ctx, "volume", addition.member, addition.source, 1, addition.fraction, expireAt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	members, err := set.Range(ctx, "volume", 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Member{{"ann", 2.7}, {"bob", 2.6}, {"cid", 1.5}}
+	if len(members) != len(want) {
+		t.Fatalf("got %v, want %v", members, want)
+	}
+	for i := range want {
+		if members[i].ID != want[i].ID || Whole(members[i].Score) != Whole(want[i].Score) {
+			t.Errorf("position %d: got %v, want %v", i, members[i], want[i])
+		}
+	}
+	if rank, _, err := set.Rank(ctx, "volume", "cid"); err != nil || rank != 2 {
+		t.Errorf("got rank %d, %v, want 2", rank, err)
+	}
+	if page, _ := set.Range(ctx, "volume", 1, 1); len(page) != 1 || page[0].ID != "bob" {
+		t.Errorf("got page %v, want bob", page)
+	}
+	if scored, _ := set.Scores(ctx, "volume", []string{"cid", "dan"}); len(scored) != 1 || scored[0].ID != "cid" {
+		t.Errorf("got scores %v, want cid only", scored)
+	}
+
+	now = expireAt
+	if count, _ := set.Len(ctx, "volume"); count != 0 {
+		t.Errorf("got %d members after expiry, want 0", count)
+	}
+	if _, _, err := set.Rank(ctx, "best", "ann"); err != ErrNotRanked {
+		t.Errorf("got %v after expiry, want ErrNotRanked", err)
+	}
+}
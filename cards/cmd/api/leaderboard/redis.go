@@ -0,0 +1,124 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+// addWholeScript adds to the whole part of a score and replaces its fraction in one step, once per source.
+// KEYS: the set, its sources. ARGV: member, source, amount, fraction, expiry as a unix time or 0.
+var addWholeScript = redis.NewScript(`
+if redis.call('SADD', KEYS[2], ARGV[2]) == 0 then
+	return 0
+end
+local whole = 0
+local score = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if score then
+	whole = math.floor(tonumber(score))
+end
+redis.call('ZADD', KEYS[1], whole + tonumber(ARGV[3]) + tonumber(ARGV[4]), ARGV[1])
+if tonumber(ARGV[5]) > 0 then
+	redis.call('EXPIREAT', KEYS[1], ARGV[5])
+	redis.call('EXPIREAT', KEYS[2], ARGV[5])
+end
+return 1
+`)
+
+// redisSortedSet keeps the sets in Redis 6.2 or later, shared by every instance
+type redisSortedSet struct {
+	client redis.UniversalClient
+}
+
+func NewRedisSortedSet(client redis.UniversalClient) SortedSet {
+	return &redisSortedSet{client: client}
+}
+
+func (s *redisSortedSet) SetMax(ctx context.Context, key, member string, score float64, expireAt time.Time) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAddGT(ctx, setKey(key), redis.Z{Score: score, Member: member})
+		if !expireAt.IsZero() {
+			pipe.ExpireAt(ctx, setKey(key), expireAt)
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *redisSortedSet) AddWhole(ctx context.Context, key, member, sourceID string, amount int, fraction float64,
+	expireAt time.Time) error {
+	var expiry int64
+	if !expireAt.IsZero() {
+		expiry = expireAt.Unix()
+	}
+	return addWholeScript.Run(ctx, s.client, []string{setKey(key), sourcesKey(key)},
+		member, sourceID, amount, fraction, expiry,
+	).Err()
+}
+
+func (s *redisSortedSet) Rank(ctx context.Context, key, member string) (int, float64, error) {
+	var (
+		rank  *redis.IntCmd
+		score *redis.FloatCmd
+	)
+	_, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		rank = pipe.ZRevRank(ctx, setKey(key), member)
+		score = pipe.ZScore(ctx, setKey(key), member)
+		return nil
+	})
+	if errors.Is(err, redis.Nil) {
+		return 0, 0, ErrNotRanked
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(rank.Val()), score.Val(), nil
+}
+
+func (s *redisSortedSet) Range(ctx context.Context, key string, offset, limit int) ([]Member, error) {
+	if limit <= 0 {
+		return []Member{}, nil
+	}
+	entries, err := s.client.ZRevRangeWithScores(ctx, setKey(key), int64(offset), int64(offset+limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+	members := make([]Member, 0, len(entries))
+	for _, entry := range entries {
+		members = append(members, Member{ID: entry.Member.(string), Score: entry.Score})
+	}
+	return members, nil
+}
+
+func (s *redisSortedSet) Scores(ctx context.Context, key string, members []string) ([]Member, error) {
+	if len(members) == 0 {
+		return []Member{}, nil
+	}
+	scores, err := s.client.ZMScore(ctx, setKey(key), members...).Result()
+	if err != nil {
+		return nil, err
+	}
+	// missing members read as 0, ranked ones always carry a tie breaker above it
+	scored := []Member{}
+	for i, score := range scores {
+		if score > 0 {
+			scored = append(scored, Member{ID: members[i], Score: score})
+		}
+	}
+	return scored, nil
+}
+
+func (s *redisSortedSet) Len(ctx context.Context, key string) (int, error) {
+	count, err := s.client.ZCard(ctx, setKey(key)).Result()
+	return int(count), err
+}
+
+// setKey and sourcesKey share a hash tag, so a Redis cluster keeps a set and its sources on the same node
+func setKey(key string) string {
+	return "{" + key + "}"
+}
+
+func sourcesKey(key string) string {
+	return "{" + key + "}:sources"
+}
@@ -0,0 +1,170 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotRanked is returned for a member without a score
+var ErrNotRanked = errors.New("member is not ranked")
+
+type Member struct {
+	ID    string
+	Score float64
+}
+
+// SortedSet keeps members ordered by score, highest first, like a Redis sorted set. Members with equal
+// scores are ordered by id, the greater id first, as Redis does. Writes take the time the set expires at,
+// the set is dropped then, a zero time keeps it.
+type SortedSet interface {
+	// SetMax sets the score of the member unless it has a higher one
+	SetMax(ctx context.Context, key, member string, score float64, expireAt time.Time) error
+	// AddWhole adds the amount to the whole part of the member's score once per source, e.g. a review id,
+	// and replaces the fraction of the score, between 0 and 1, with the given one
+	AddWhole(ctx context.Context, key, member, sourceID string, amount int, fraction float64, expireAt time.Time) error
+	// Rank returns the position of the member counted from 0 and its score, or ErrNotRanked
+	Rank(ctx context.Context, key, member string) (int, float64, error)
+	// Range returns up to limit members from the offset on
+	Range(ctx context.Context, key string, offset, limit int) ([]Member, error)
+	// Scores returns the listed members that have a score, in no particular order
+	Scores(ctx context.Context, key string, members []string) ([]Member, error)
+	Len(ctx context.Context, key string) (int, error)
+}
+
+// Sort orders members like a sorted set does, highest score first
+func Sort(members []Member) {
+	slices.SortFunc(members, compareMembers)
+}
+
+type memorySet struct {
+	scores   map[string]float64
+	sources  map[string]struct{}
+	expireAt time.Time
+}
+
+// memorySortedSet keeps the sets of a single instance, every read of a ranking sorts the set.
+// It's meant for local development and tests, Redis shares the boards between instances.
+type memorySortedSet struct {
+	mu   sync.Mutex
+	sets map[string]*memorySet
+	now  func() time.Time
+}
+
+// NewMemorySortedSet returns sorted sets expiring by the clock
+func NewMemorySortedSet(now func() time.Time) SortedSet {
+	return &memorySortedSet{sets: make(map[string]*memorySet), now: now}
+}
+
+func (s *memorySortedSet) SetMax(ctx context.Context, key, member string, score float64, expireAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	set := s.write(key, expireAt)
+	if stored, ok := set.scores[member]; !ok || score > stored {
+		set.scores[member] = score
+	}
+	return nil
+}
+
+func (s *memorySortedSet) AddWhole(ctx context.Context, key, member, sourceID string, amount int, fraction float64,
+	expireAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	set := s.write(key, expireAt)
+	if _, ok := set.sources[sourceID]; ok {
+		return nil
+	}
+	set.sources[sourceID] = struct{}{}
+	set.scores[member] = math.Floor(set.scores[member]) + float64(amount) + fraction
+	return nil
+}
+
+func (s *memorySortedSet) Rank(ctx context.Context, key, member string) (int, float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	set := s.read(key)
+	score, ok := set.scores[member]
+	if !ok {
+		return 0, 0, ErrNotRanked
+	}
+	rank := 0
+	for id, other := range set.scores {
+		if compareMembers(Member{ID: id, Score: other}, Member{ID: member, Score: score}) < 0 {
+			rank++
+		}
+	}
+	return rank, score, nil
+}
+
+func (s *memorySortedSet) Range(ctx context.Context, key string, offset, limit int) ([]Member, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	set := s.read(key)
+	members := make([]Member, 0, len(set.scores))
+	for id, score := range set.scores {
+		members = append(members, Member{ID: id, Score: score})
+	}
+	Sort(members)
+	if offset >= len(members) {
+		return []Member{}, nil
+	}
+	return members[offset:min(offset+limit, len(members))], nil
+}
+
+func (s *memorySortedSet) Scores(ctx context.Context, key string, members []string) ([]Member, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	set := s.read(key)
+	scored := []Member{}
+	for _, id := range members {
+		if score, ok := set.scores[id]; ok {
+			scored = append(scored, Member{ID: id, Score: score})
+		}
+	}
+	return scored, nil
+}
+
+func (s *memorySortedSet) Len(ctx context.Context, key string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.read(key).scores), nil
+}
+
+// read returns the set, an empty one when it's missing or expired, the caller must hold the lock
+func (s *memorySortedSet) read(key string) *memorySet {
+	set, ok := s.sets[key]
+	if !ok {
+		return &memorySet{}
+	}
+	if !set.expireAt.IsZero() && !s.now().Before(set.expireAt) {
+		delete(s.sets, key)
+		return &memorySet{}
+	}
+	return set
+}
+
+// write returns the set to change, creating it when it's missing, the caller must hold the lock
+func (s *memorySortedSet) write(key string, expireAt time.Time) *memorySet {
+	set := s.read(key)
+	if set.scores == nil {
+		set = &memorySet{scores: make(map[string]float64), sources: make(map[string]struct{})}
+		s.sets[key] = set
+	}
+	set.expireAt = expireAt
+	return set
+}
+
+// compareMembers sorts members in rank order
+func compareMembers(a, b Member) int {
+	if a.Score != b.Score {
+		if a.Score > b.Score {
+			return -1
+		}
+		return 1
+	}
+	return -strings.Compare(a.ID, b.ID)
+}
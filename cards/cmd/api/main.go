@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/config"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/leaderboard"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/printout"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/repositories"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/scheduler"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/search"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/server"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"github.com/Salladin95/card-quizzler-microservices/consumer"
+	"github.com/Salladin95/rmqtools"
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// AmqpJobQueue holds imports and exports too large to process within a request
+const AmqpJobQueue = "cards-jobs-queue"
+
+// challengeSweepInterval is how often abandoned challenges are looked for
+const challengeSweepInterval = time.Minute
+
+type App struct {
+	rabbit *amqp091.Connection
+	repos  server.Repositories
+	config config.AppCfg
+}
+
+func main() {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	rabbitConn, err := rmqtools.ConnectToRabbit(cfg.AppCfg.RABBIT_URL)
+	if err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+	defer rabbitConn.Close()
+
+	repos, closeStorage, err := openRepositories(cfg.AppCfg)
+	if err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+	defer closeStorage()
+	leaderboards, closeLeaderboards, err := openLeaderboards(cfg.AppCfg)
+	if err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+	defer closeLeaderboards()
+	repos.Leaderboards = leaderboards
+
+	app := App{rabbit: rabbitConn, repos: repos, config: cfg.AppCfg}
+	algorithm, err := scheduler.New(app.config.SCHEDULER_ALGORITHM, scheduler.Options{
+		DesiredRetention: app.config.DESIRED_RETENTION,
+		MaximumInterval:  app.config.MAXIMUM_INTERVAL,
+	})
+	if err != nil {
+		log.Printf("failed to configure scheduler: %v", err)
+		os.Exit(1)
+	}
+	printer, err := printout.NewPrinter(app.config.PDF_FONT_FILE)
+	if err != nil {
+		log.Printf("failed to load PDF fonts: %v", err)
+		os.Exit(1)
+	}
+	index, indexCreated, err := openSearchIndex(app.config)
+	if err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+	defer index.Close()
+	cardsServer := server.NewCardsServer(app.config, app.repos, algorithm, printer, index, events.NewRabbitPublisher(app.rabbit))
+
+	jobConsumer, err := consumer.NewConsumer(rabbitConn, consumer.Options{
+		Exchange:    events.AmqpExchange,
+		Queue:       AmqpJobQueue,
+		Keys:        []string{events.JobQueuedKey},
+		MaxAttempts: app.config.JOB_MAX_ATTEMPTS,
+		RetryDelay:  app.config.JOB_RETRY_DELAY,
+	})
+	if err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	// the queue is declared before a rebuild, so changes made meanwhile are applied afterwards
+	searchConsumer, err := consumer.NewConsumer(rabbitConn, consumer.Options{
+		Exchange:    events.AmqpExchange,
+		Queue:       app.config.SEARCH_QUEUE,
+		Keys:        server.SearchEventKeys,
+		MaxAttempts: app.config.JOB_MAX_ATTEMPTS,
+		RetryDelay:  app.config.JOB_RETRY_DELAY,
+	})
+	if err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	statsConsumer, err := consumer.NewConsumer(rabbitConn, consumer.Options{
+		Exchange:    events.AmqpExchange,
+		Queue:       app.config.STATS_QUEUE,
+		Keys:        server.StatsEventKeys,
+		MaxAttempts: app.config.JOB_MAX_ATTEMPTS,
+		RetryDelay:  app.config.JOB_RETRY_DELAY,
+	})
+	if err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	achievementConsumer, err := consumer.NewConsumer(rabbitConn, consumer.Options{
+		Exchange:    events.AmqpExchange,
+		Queue:       app.config.ACHIEVEMENTS_QUEUE,
+		Keys:        server.AchievementEventKeys,
+		MaxAttempts: app.config.JOB_MAX_ATTEMPTS,
+		RetryDelay:  app.config.JOB_RETRY_DELAY,
+	})
+	if err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	leaderboardConsumer, err := consumer.NewConsumer(rabbitConn, consumer.Options{
+		Exchange:    events.AmqpExchange,
+		Queue:       app.config.LEADERBOARD_QUEUE,
+		Keys:        server.LeaderboardEventKeys,
+		MaxAttempts: app.config.JOB_MAX_ATTEMPTS,
+		RetryDelay:  app.config.JOB_RETRY_DELAY,
+	})
+	if err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if indexCreated {
+		decks, err := cardsServer.RebuildSearchIndex(ctx)
+		if err != nil {
+			log.Printf("failed to rebuild search index: %v", err)
+			os.Exit(1)
+		}
+		log.Printf("Search index rebuilt with %d decks", decks)
+	}
+
+	go app.gRPCListen(cardsServer)
+	go func() {
+		if err := jobConsumer.Listen(ctx, cardsServer.HandleJobEvent); err != nil {
+			log.Printf("job consumer stopped: %s\n", err.Error())
+			stop()
+		}
+	}()
+	go func() {
+		if err := searchConsumer.Listen(ctx, cardsServer.HandleSearchEvent); err != nil {
+			log.Printf("search consumer stopped: %s\n", err.Error())
+			stop()
+		}
+	}()
+	go func() {
+		if err := statsConsumer.Listen(ctx, cardsServer.HandleStatsEvent); err != nil {
+			log.Printf("stats consumer stopped: %s\n", err.Error())
+			stop()
+		}
+	}()
+	go func() {
+		if err := achievementConsumer.Listen(ctx, cardsServer.HandleAchievementEvent); err != nil {
+			log.Printf("achievement consumer stopped: %s\n", err.Error())
+			stop()
+		}
+	}()
+	go func() {
+		if err := leaderboardConsumer.Listen(ctx, cardsServer.HandleLeaderboardEvent); err != nil {
+			log.Printf("leaderboard consumer stopped: %s\n", err.Error())
+			stop()
+		}
+	}()
+	// achievements added since the last start are awarded to the users who already met them
+	go func() {
+		awarded, err := cardsServer.AwardAchievements(ctx)
+		if err != nil {
+			log.Printf("failed to award achievements: %s\n", err.Error())
+			return
+		}
+		log.Printf("Awarded %d achievements retroactively", awarded)
+	}()
+	go sweepChallenges(ctx, cardsServer)
+	<-ctx.Done()
+	log.Println("Received termination signal. Shutting down gracefully.")
+}
+
+// sweepChallenges periodically completes challenges abandoned by their users, so their scores are recorded
+func sweepChallenges(ctx context.Context, cardsServer *server.CardsServer) {
+	ticker := time.NewTicker(challengeSweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := cardsServer.ExpireChallenges(ctx); err != nil {
+				log.Printf("failed to expire challenges: %s\n", err.Error())
+			}
+		}
+	}
+}
+
+func (app *App) gRPCListen(cardsServer *server.CardsServer) {
+	port := app.config.GRPC_PORT
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", port))
+	if err != nil {
+		log.Fatalf("failed to listen tcp port - %s. Err - %s", port, err.Error())
+	}
+
+	// leave room for the rest of the message next to an uploaded or exported file
+	maxMessageSize := app.config.MAX_IMPORT_BYTES + 1<<20
+	gRPCServer := grpc.NewServer(grpc.MaxRecvMsgSize(maxMessageSize), grpc.MaxSendMsgSize(maxMessageSize))
+	cards.RegisterCardsServer(gRPCServer, cardsServer)
+
+	log.Printf("gRPC Server started on port %s", port)
+
+	if err := gRPCServer.Serve(listener); err != nil {
+		log.Fatalf("Failed to listen for gRPC: %v", err)
+	}
+}
+
+// openRepositories builds repositories for the configured storage and returns a function releasing it
+func openRepositories(cfg config.AppCfg) (server.Repositories, func(), error) {
+	if cfg.STORAGE == config.StorageMemory {
+		store := repositories.NewMemoryStore()
+		return server.Repositories{
+			Decks:           repositories.NewMemoryDeckRepository(store),
+			Cards:           repositories.NewMemoryCardRepository(store),
+			Reviews:         repositories.NewMemoryReviewRepository(store),
+			QuizSessions:    repositories.NewMemoryQuizSessionRepository(store),
+			ChallengeScores: repositories.NewMemoryChallengeScoreRepository(store),
+			Jobs:            repositories.NewMemoryJobRepository(store),
+			Folders:         repositories.NewMemoryFolderRepository(store),
+			FilteredDecks:   repositories.NewMemoryFilteredDeckRepository(store),
+			Sharing:         repositories.NewMemorySharingRepository(store),
+			Catalog:         repositories.NewMemoryCatalogRepository(store),
+			Forks:           repositories.NewMemoryForkRepository(store),
+			Stats:           repositories.NewMemoryStatsRepository(store),
+			Streaks:         repositories.NewMemoryStreakRepository(store),
+			Achievements:    repositories.NewMemoryAchievementRepository(store),
+			Follows:         repositories.NewMemoryFollowRepository(store),
+		}, func() {}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	db, err := repositories.OpenPostgres(ctx, cfg.DATABASE_URL)
+	if err != nil {
+		return server.Repositories{}, nil, fmt.Errorf("failed to open postgres: %w", err)
+	}
+	return server.Repositories{
+		Decks:           repositories.NewPostgresDeckRepository(db),
+		Cards:           repositories.NewPostgresCardRepository(db),
+		Reviews:         repositories.NewPostgresReviewRepository(db),
+		QuizSessions:    repositories.NewPostgresQuizSessionRepository(db),
+		ChallengeScores: repositories.NewPostgresChallengeScoreRepository(db),
+		Jobs:            repositories.NewPostgresJobRepository(db),
+		Folders:         repositories.NewPostgresFolderRepository(db),
+		FilteredDecks:   repositories.NewPostgresFilteredDeckRepository(db),
+		Sharing:         repositories.NewPostgresSharingRepository(db),
+		Catalog:         repositories.NewPostgresCatalogRepository(db),
+		Forks:           repositories.NewPostgresForkRepository(db),
+		Stats:           repositories.NewPostgresStatsRepository(db),
+		Streaks:         repositories.NewPostgresStreakRepository(db),
+		Achievements:    repositories.NewPostgresAchievementRepository(db),
+		Follows:         repositories.NewPostgresFollowRepository(db),
+	}, func() { db.Close() }, nil
+}
+
+// openLeaderboards connects to Redis when it keeps the leaderboards and returns a function closing the connection
+func openLeaderboards(cfg config.AppCfg) (leaderboard.SortedSet, func(), error) {
+	if cfg.LEADERBOARD_STORE == config.LeaderboardMemory {
+		return leaderboard.NewMemorySortedSet(time.Now), func() {}, nil
+	}
+	options, err := redis.ParseURL(cfg.REDIS_URL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("REDIS_URL: %w", err)
+	}
+	client := redis.NewClient(options)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	return leaderboard.NewRedisSortedSet(client), func() { client.Close() }, nil
+}
+
+// openSearchIndex opens the index on disk, with memory storage it's kept in memory like everything else
+func openSearchIndex(cfg config.AppCfg) (*search.Index, bool, error) {
+	path := cfg.SEARCH_INDEX_PATH
+	if cfg.STORAGE == config.StorageMemory {
+		path = ""
+	}
+	index, created, err := search.Open(path, cfg.SEARCH_LANGUAGES)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to open search index: %w", err)
+	}
+	return index, created, nil
+}
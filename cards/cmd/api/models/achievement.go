@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Badge is an achievement awarded to a user
+type Badge struct {
+	UserID        string
+	AchievementID string
+	AwardedAt     time.Time
+	// Retroactive is set when the achievement was added after the user met it
+	Retroactive bool
+}
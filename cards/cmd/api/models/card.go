@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+type Card struct {
+	ID     string
+	DeckID string
+	Front  string
+	Back   string
+	Hint   string
+	// Alternatives are other accepted answers for typed questions
+	Alternatives []string
+	// Tags are free-form labels for organizing cards
+	Tags []string
+	// AnswerType is "text" or "numeric", numeric answers may carry SI units
+	AnswerType string
+	// AbsoluteTolerance and RelativeTolerance bound the error of numeric answers
+	AbsoluteTolerance float64
+	RelativeTolerance float64
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
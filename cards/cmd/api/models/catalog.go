@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// CatalogEntry publishes a deck to the public catalog, anyone can browse it and fork it into their library
+type CatalogEntry struct {
+	DeckID   string
+	OwnerID  string
+	Category string
+	// Language is the BCP 47 tag of the language the deck teaches or is written in, e.g. "es" or "pt-BR"
+	Language string
+	// Forks counts the copies users made, popular decks are the most forked ones
+	Forks       int
+	PublishedAt time.Time
+	UpdatedAt   time.Time
+}
+
+// CatalogDeck is a published deck as it's listed in the catalog
+type CatalogDeck struct {
+	Entry     CatalogEntry
+	Deck      Deck
+	CardCount int
+}
+
+// Fork links a deck copied from the catalog to the deck it was copied from
+type Fork struct {
+	DeckID     string
+	UpstreamID string
+	ForkedAt   time.Time
+	// SyncedAt is the last time changes were pulled from the upstream deck
+	SyncedAt time.Time
+}
+
+// ForkCard links a card of a fork to the upstream card it was copied from
+type ForkCard struct {
+	CardID         string
+	DeckID         string
+	UpstreamCardID string
+	// UpstreamUpdatedAt is the version of the upstream card last pulled, a newer upstream card is a change
+	UpstreamUpdatedAt time.Time
+	// SyncedAt is when the card was last written from the upstream, the card updated after it has local edits
+	SyncedAt time.Time
+}
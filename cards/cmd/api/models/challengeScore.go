@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// ChallengeScore is the result of a completed timed challenge
+type ChallengeScore struct {
+	ID        string
+	UserID    string
+	DeckID    string
+	SessionID string
+	Score     int
+	Answered  int
+	Correct   int
+	MaxCombo  int
+	// Duration is the time actually played, at most the time limit
+	Duration    time.Duration
+	TimeLimit   time.Duration
+	CompletedAt time.Time
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+type Deck struct {
+	ID      string
+	OwnerID string
+	// ParentID nests the deck inside another deck of the owner, empty for top-level decks
+	ParentID string
+	// FolderID files the deck in a folder of the owner, empty for decks outside folders
+	FolderID    string
+	Title       string
+	Description string
+	// Tags are free-form labels, filtered decks match them like the tags of the deck cards
+	Tags      []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// FilteredDeck studies the owner's cards matching a saved query without moving them, see package filter
+type FilteredDeck struct {
+	ID      string
+	OwnerID string
+	Name    string
+	Query   string
+	// Limit bounds the cards studied at once, filtered decks don't follow the daily limits of decks
+	Limit     int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
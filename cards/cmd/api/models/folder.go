@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Folder organizes decks, folders nest inside each other
+type Folder struct {
+	ID      string
+	OwnerID string
+	// ParentID is empty for top-level folders
+	ParentID  string
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// Follow makes the followee's scores show up on the follower's leaderboards
+type Follow struct {
+	FollowerID string
+	FolloweeID string
+	CreatedAt  time.Time
+}
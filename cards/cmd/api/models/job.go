@@ -0,0 +1,102 @@
+package models
+
+import "time"
+
+const (
+	JobImportCards = "import-cards"
+	JobExportCards = "export-cards"
+
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+
+	// MaxJobRowErrors bounds the row errors kept in a report, the failed count still covers every row
+	MaxJobRowErrors = 100
+)
+
+// Job is a deck import or export, large ones, Anki imports and PDF exports are processed in the background
+type Job struct {
+	ID      string
+	UserID  string
+	DeckID  string
+	Kind    string
+	Status  string
+	Format  string
+	Options JobOptions
+	// Input is the uploaded file of an import
+	Input []byte
+	// Output is the file produced by an export
+	Output     []byte
+	OutputName string
+	OutputType string
+	Report     JobReport
+	// Error explains why the job failed
+	Error string
+	// Attempts counts the runs of the job, a run fails for good after the configured maximum
+	Attempts    int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	CompletedAt time.Time
+}
+
+type JobOptions struct {
+	Delimiter string            `json:"delimiter,omitempty"`
+	NoHeader  bool              `json:"noHeader,omitempty"`
+	Mapping   map[string]string `json:"mapping,omitempty"`
+	// DryRun validates an import without creating cards
+	DryRun  bool     `json:"dryRun,omitempty"`
+	Columns []string `json:"columns,omitempty"`
+	// TermSeparator and CardSeparator split a Quizlet export
+	TermSeparator string `json:"termSeparator,omitempty"`
+	CardSeparator string `json:"cardSeparator,omitempty"`
+	// Print lays out a PDF export
+	Print *PrintOptions `json:"print,omitempty"`
+}
+
+type PrintOptions struct {
+	Layout        string  `json:"layout,omitempty"`
+	Paper         string  `json:"paper,omitempty"`
+	CardsPerPage  int     `json:"cardsPerPage,omitempty"`
+	FontSize      float64 `json:"fontSize,omitempty"`
+	Questions     string  `json:"questions,omitempty"`
+	QuestionCount int     `json:"questionCount,omitempty"`
+	Seed          int64   `json:"seed,omitempty"`
+}
+
+type JobReport struct {
+	Rows int `json:"rows"`
+	// Valid rows passed validation, Imported ones were stored as cards
+	Valid    int           `json:"valid"`
+	Imported int           `json:"imported"`
+	Failed   int           `json:"failed"`
+	Errors   []JobRowError `json:"errors"`
+	// Warnings point out what the file holds but cards can't, e.g. media
+	Warnings []string `json:"warnings"`
+	// Decks are the decks created for the sections of a Markdown import
+	Decks []string `json:"decks,omitempty"`
+}
+
+type JobRowError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// Fail records a row that can't be imported
+func (r *JobReport) Fail(line int, message string) {
+	r.Failed++
+	if len(r.Errors) < MaxJobRowErrors {
+		r.Errors = append(r.Errors, JobRowError{Line: line, Message: message})
+	}
+}
+
+// Warn records a warning, like row errors only the first ones are kept
+func (r *JobReport) Warn(message string) {
+	if len(r.Warnings) < MaxJobRowErrors {
+		r.Warnings = append(r.Warnings, message)
+	}
+}
+
+func (j Job) Finished() bool {
+	return j.Status == JobSucceeded || j.Status == JobFailed
+}
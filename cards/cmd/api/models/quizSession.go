@@ -0,0 +1,82 @@
+package models
+
+import "time"
+
+const (
+	QuizStatusActive    = "active"
+	QuizStatusPaused    = "paused"
+	QuizStatusCompleted = "completed"
+	QuizStatusExpired   = "expired"
+
+	QuestionStatusPending  = "pending"
+	QuestionStatusAnswered = "answered"
+	QuestionStatusSkipped  = "skipped"
+)
+
+type QuizSession struct {
+	ID     string
+	UserID string
+	// DeckID is the deck quizzed, or the filtered deck the cards were picked by
+	DeckID string
+	Status string
+	Order  string
+	Modes  []string
+	// Seed makes shuffling and question building reproducible
+	Seed      int64
+	Questions []QuizQuestion
+	// Current is the index of the question the user has to answer next
+	Current int
+	// ActiveDuration is the time spent in the active state before ResumedAt
+	ActiveDuration time.Duration
+	ResumedAt      time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	ExpiresAt      time.Time
+	CompletedAt    time.Time
+	// Challenge is set for timed challenges only
+	Challenge *QuizChallenge
+	// Version is increased on every update to detect concurrent modifications
+	Version int
+}
+
+// QuizChallenge is the state of a timed challenge, answering as many questions as possible before the deadline
+type QuizChallenge struct {
+	TimeLimit time.Duration
+	Deadline  time.Time
+	Score     int
+	// Combo counts the correct answers in a row, MaxCombo is the longest streak of the session
+	Combo    int
+	MaxCombo int
+}
+
+type QuizQuestion struct {
+	CardID string
+	// GroupCardIDs lists all cards a question built from several cards covers, in the order of Terms
+	GroupCardIDs []string
+	Mode         string
+	Prompt       string
+	Hint         string
+	// Terms are matched against Options in matching questions
+	Terms   []string
+	Options []string
+	// Statement is the answer proposed by true/false questions
+	Statement string
+	// Expected is the correct answer, it is revealed once the question is answered or skipped
+	Expected string
+	// Accepted are other answers counted as correct, they are never sent to the client
+	Accepted []string
+	// AnswerType and the tolerances are copied from the card to grade typed answers
+	AnswerType        string
+	AbsoluteTolerance float64
+	RelativeTolerance float64
+	Status            string
+	Answer            string
+	Correct           bool
+	// Points scored by the answer in a challenge
+	Points int
+	// MissedCardIDs are the cards of a group answered wrong
+	MissedCardIDs []string
+	// AskedAt is when the question became the current one, pauses excluded
+	AskedAt    time.Time
+	AnsweredAt time.Time
+}
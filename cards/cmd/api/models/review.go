@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// ReviewState is the scheduling state of a card for one user
+type ReviewState struct {
+	UserID       string
+	CardID       string
+	DeckID       string
+	Algorithm    string
+	Repetitions  int
+	Lapses       int
+	IntervalDays int
+	Ease         float64
+	Stability    float64
+	Difficulty   float64
+	Due          time.Time
+	LastReview   time.Time
+}
+
+// ReviewLog records a single answer, it is used to enforce daily limits
+type ReviewLog struct {
+	ID           string
+	UserID       string
+	CardID       string
+	DeckID       string
+	Grade        int
+	IntervalDays int
+	// New is true when the review introduced the card to the user
+	New        bool
+	ReviewedAt time.Time
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Roles of the users a deck is shared with, each role can do what the ones before it can
+const (
+	// RoleViewer studies the deck and reads its cards
+	RoleViewer = "viewer"
+	// RoleEditor changes the deck and its cards as well
+	RoleEditor = "editor"
+	// RoleOwner shares the deck and deletes it as well, the creator of a deck is always an owner
+	RoleOwner = "owner"
+)
+
+// DeckMember grants a user a role on a deck created by someone else
+type DeckMember struct {
+	DeckID string
+	UserID string
+	Role   string
+	// InvitedBy is the user who created the accepted invitation
+	InvitedBy string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// DeckInvitation lets users join a deck with a role
+type DeckInvitation struct {
+	ID        string
+	DeckID    string
+	InviterID string
+	// Email is set for invitations sent to one person, they can be accepted once.
+	// Invitations without it are links anyone holding them can accept until they expire.
+	Email string
+	Role  string
+	// TokenHash is the SHA-256 of the token handed to the invitee, the token itself is never stored
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	// AcceptedBy and AcceptedAt are set once an email invitation is used
+	AcceptedBy string
+	AcceptedAt time.Time
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// StatsRollupPeriod is the length of a rollup. Days of every timezone, UTC+5:45 included,
+// are made of whole rollups, so daily stats are summed from them in any timezone.
+const StatsRollupPeriod = 15 * time.Minute
+
+// ReviewTotals sums the answers given for scheduling
+type ReviewTotals struct {
+	Reviews int
+	// NewCards counts the reviews introducing a card
+	NewCards int
+	// Recalled counts the reviews of studied cards not graded again
+	Recalled int
+	// Timed counts the reviews reporting how long the answer took, AnswerTime sums them
+	Timed      int
+	AnswerTime time.Duration
+}
+
+func (t *ReviewTotals) Add(other ReviewTotals) {
+	t.Reviews += other.Reviews
+	t.NewCards += other.NewCards
+	t.Recalled += other.Recalled
+	t.Timed += other.Timed
+	t.AnswerTime += other.AnswerTime
+}
+
+// StatsRollup sums the reviews of a user in a deck over the period starting at Start
+type StatsRollup struct {
+	UserID string
+	DeckID string
+	Start  time.Time
+	ReviewTotals
+}
+
+// DailyStats sums the reviews of a day, Date is its midnight in the requested timezone
+type DailyStats struct {
+	Date time.Time
+	ReviewTotals
+}
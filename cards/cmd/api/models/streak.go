@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+const (
+	GoalCards   = "cards"
+	GoalMinutes = "minutes"
+)
+
+// StudySettings decide when the study day of a user starts and what completes it
+type StudySettings struct {
+	UserID string
+	// Timezone is copied from the auth profile, TimezoneUpdatedAt orders the profile events
+	Timezone          string
+	TimezoneUpdatedAt time.Time
+	// RolloverHour is the local hour a new study day starts at, late night reviews count for the day before
+	RolloverHour int
+	// GoalKind is GoalCards or GoalMinutes, minutes are the summed answer times
+	GoalKind   string
+	GoalTarget int
+	UpdatedAt  time.Time
+}
+
+// Streak counts the consecutive study days the daily goal was reached on
+type Streak struct {
+	UserID  string
+	Current int
+	Longest int
+	// LastDay is the last study day the goal was reached on, its date at midnight UTC
+	LastDay time.Time
+	// Freezes are earned while the streak grows, each one covers a missed day
+	Freezes     int
+	FreezesUsed int
+	UpdatedAt   time.Time
+	Version     int
+}
@@ -0,0 +1,209 @@
+package printout
+
+import (
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font/sfnt"
+	"strings"
+	"time"
+)
+
+const (
+	goFamily       = "go"
+	fallbackFamily = "fallback"
+
+	// ptToMM converts font sizes in points to page units
+	ptToMM = 25.4 / 72
+	// lineSpacing is the height of a line relative to its font size
+	lineSpacing = 1.3
+	// minFitSize is the smallest font size long texts shrink to
+	minFitSize = 6
+	// maxRune bounds the runes the PDF writer keeps widths for
+	maxRune  = 0xFFFF
+	ellipsis = "..."
+)
+
+var (
+	black = [3]int{0, 0, 0}
+	gray  = [3]int{110, 110, 110}
+)
+
+// document is a PDF being written, its methods aren't safe for concurrent use
+type document struct {
+	pdf   *fpdf.Fpdf
+	fonts []font
+	opts  Options
+	// buf is reused by glyph lookups
+	buf sfnt.Buffer
+}
+
+// line is a line of text laid out in a font
+type line struct {
+	text   string
+	family string
+	style  string
+	size   float64
+	color  [3]int
+	// gap is the space above the line, it separates paragraphs
+	gap float64
+}
+
+func (l line) height() float64 {
+	return l.gap + l.size*ptToMM*lineSpacing
+}
+
+// block is a paragraph of a card, its scale sizes it relative to the font size of the card
+type block struct {
+	text  string
+	scale float64
+	bold  bool
+	color [3]int
+}
+
+func (p *Printer) newDocument(title string, opts Options, now time.Time) *document {
+	pdf := fpdf.New("P", "mm", paperSizes[opts.Paper], "")
+	pdf.SetTitle(title, true)
+	pdf.SetCreator("Card Quizzler", true)
+	pdf.SetCreationDate(now)
+	pdf.SetModificationDate(now)
+	for _, f := range p.fonts {
+		pdf.AddUTF8FontFromBytes(f.family, "", f.regular)
+		pdf.AddUTF8FontFromBytes(f.family, "B", f.bold)
+	}
+	return &document{pdf: pdf, fonts: p.fonts, opts: opts}
+}
+
+// setFont selects the first font able to show the whole text, or the last font when none can.
+// It returns the text with the runes the font lacks replaced by question marks, and the font family.
+func (d *document) setFont(text string, bold bool, size float64) (string, string) {
+	text = strings.Map(func(r rune) rune {
+		switch r {
+		case '\t':
+			return ' '
+		case '\r':
+			return -1
+		}
+		return r
+	}, text)
+
+	chosen := d.fonts[len(d.fonts)-1]
+	for _, f := range d.fonts {
+		if d.covers(f, text) {
+			chosen = f
+			break
+		}
+	}
+	d.pdf.SetFont(chosen.family, styleOf(bold), size)
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || d.hasGlyph(chosen, r) {
+			return r
+		}
+		return '?'
+	}, text), chosen.family
+}
+
+func (d *document) covers(f font, text string) bool {
+	for _, r := range text {
+		if r != '\n' && !d.hasGlyph(f, r) {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *document) hasGlyph(f font, r rune) bool {
+	if r > maxRune {
+		return false
+	}
+	index, err := f.glyphs.GlyphIndex(&d.buf, r)
+	return err == nil && index != 0
+}
+
+// layout wraps the blocks to the width at the font size
+func (d *document) layout(blocks []block, size, width float64) []line {
+	var lines []line
+	for _, b := range blocks {
+		if b.text == "" {
+			continue
+		}
+		blockSize := size * b.scale
+		text, family := d.setFont(b.text, b.bold, blockSize)
+		gap := 0.0
+		if len(lines) > 0 {
+			gap = blockSize * ptToMM * 0.6
+		}
+		for _, paragraph := range strings.Split(text, "\n") {
+			wrapped := d.pdf.SplitText(paragraph, width)
+			if len(wrapped) == 0 {
+				wrapped = []string{""}
+			}
+			for _, wrappedLine := range wrapped {
+				lines = append(lines, line{text: wrappedLine, family: family, style: styleOf(b.bold), size: blockSize, color: b.color, gap: gap})
+				gap = 0
+			}
+		}
+	}
+	return lines
+}
+
+// fit writes the blocks centred in the box, shrinking them until they fit.
+// Text still overflowing at the smallest size is cut short.
+func (d *document) fit(x, y, w, h float64, blocks []block) {
+	size := d.opts.FontSize
+	lines := d.layout(blocks, size, w)
+	for size > minFitSize && totalHeight(lines) > h {
+		size = max(size-1, minFitSize)
+		lines = d.layout(blocks, size, w)
+	}
+	if totalHeight(lines) > h {
+		height := 0.0
+		for i, l := range lines {
+			if height+l.height() > h {
+				lines = lines[:max(i, 1)]
+				lines[len(lines)-1].text = strings.TrimRight(lines[len(lines)-1].text, " ") + ellipsis
+				break
+			}
+			height += l.height()
+		}
+	}
+
+	top := y + max(h-totalHeight(lines), 0)/2
+	for _, l := range lines {
+		top += l.gap
+		d.write(x, top, w, l, "C")
+		top += l.height() - l.gap
+	}
+}
+
+// write puts the line at the position, align is L, C or R
+func (d *document) write(x, y, w float64, l line, align string) {
+	d.pdf.SetFont(l.family, l.style, l.size)
+	d.pdf.SetTextColor(l.color[0], l.color[1], l.color[2])
+	d.pdf.SetXY(x, y)
+	d.pdf.CellFormat(w, l.size*ptToMM*lineSpacing, l.text, "", 0, align, false, 0, "")
+}
+
+// empty prints a single page telling the deck has no cards
+func (d *document) empty(title string) {
+	d.pdf.AddPage()
+	left, top, right, _ := d.pdf.GetMargins()
+	width, _ := d.pdf.GetPageSize()
+	d.fit(left, top, width-left-right, 40, []block{
+		{text: title, scale: 1.3, bold: true, color: black},
+		{text: "The deck has no cards yet.", scale: 1, color: gray},
+	})
+}
+
+func totalHeight(lines []line) float64 {
+	height := 0.0
+	for _, l := range lines {
+		height += l.height()
+	}
+	return height
+}
+
+func styleOf(bold bool) string {
+	if bold {
+		return "B"
+	}
+	return ""
+}
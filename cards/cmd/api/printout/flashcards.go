@@ -0,0 +1,86 @@
+package printout
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	// sheetMargin keeps cards clear of the area printers can't reach
+	sheetMargin = 10
+	// cardPadding keeps text clear of the cut lines
+	cardPadding = 5
+)
+
+// flashcards prints every sheet twice, first the fronts and then the backs. Backs are mirrored,
+// so each lands behind its front when the sheets are printed double-sided, flipped on the long edge.
+func (d *document) flashcards(cards []Card) {
+	grid := grids[d.opts.CardsPerPage]
+	pageWidth, pageHeight := d.pdf.GetPageSize()
+	cardWidth := (pageWidth - 2*sheetMargin) / float64(grid.columns)
+	cardHeight := (pageHeight - 2*sheetMargin) / float64(grid.rows)
+	d.pdf.SetAutoPageBreak(false, 0)
+
+	for start := 0; start < len(cards); start += d.opts.CardsPerPage {
+		sheet := cards[start:min(start+d.opts.CardsPerPage, len(cards))]
+		for _, backs := range []bool{false, true} {
+			d.pdf.AddPage()
+			d.cutLines(grid.columns, grid.rows, cardWidth, cardHeight)
+			for i, card := range sheet {
+				column, row := i%grid.columns, i/grid.columns
+				if backs {
+					column = grid.columns - 1 - column
+				}
+				x := sheetMargin + float64(column)*cardWidth
+				y := sheetMargin + float64(row)*cardHeight
+				d.cardNumber(x, y, start+i+1)
+				d.fit(x+cardPadding, y+cardPadding, cardWidth-2*cardPadding, cardHeight-2*cardPadding, cardBlocks(card, backs))
+			}
+		}
+	}
+}
+
+func cardBlocks(card Card, back bool) []block {
+	if back {
+		alternatives := ""
+		if len(card.Alternatives) > 0 {
+			alternatives = "Also: " + strings.Join(card.Alternatives, ", ")
+		}
+		return []block{
+			{text: card.Back, scale: 1, color: black},
+			{text: alternatives, scale: 0.7, color: gray},
+		}
+	}
+	hint := ""
+	if card.Hint != "" {
+		hint = "Hint: " + card.Hint
+	}
+	return []block{
+		{text: card.Front, scale: 1, bold: true, color: black},
+		{text: hint, scale: 0.7, color: gray},
+	}
+}
+
+// cutLines draws the dashed grid the sheet is cut along
+func (d *document) cutLines(columns, rows int, cardWidth, cardHeight float64) {
+	d.pdf.SetDrawColor(gray[0], gray[1], gray[2])
+	d.pdf.SetLineWidth(0.2)
+	d.pdf.SetDashPattern([]float64{2, 2}, 0)
+	bottom := sheetMargin + float64(rows)*cardHeight
+	right := sheetMargin + float64(columns)*cardWidth
+	for column := 0; column <= columns; column++ {
+		x := sheetMargin + float64(column)*cardWidth
+		d.pdf.Line(x, sheetMargin, x, bottom)
+	}
+	for row := 0; row <= rows; row++ {
+		y := sheetMargin + float64(row)*cardHeight
+		d.pdf.Line(sheetMargin, y, right, y)
+	}
+	d.pdf.SetDashPattern([]float64{}, 0)
+}
+
+// cardNumber matches the sides of a card once the sheets are cut
+func (d *document) cardNumber(x, y float64, number int) {
+	text, family := d.setFont(strconv.Itoa(number), false, minFitSize)
+	d.write(x+1, y+1, 10, line{text: text, family: family, size: minFitSize, color: gray}, "L")
+}
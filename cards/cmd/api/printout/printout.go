@@ -0,0 +1,202 @@
+// Package printout renders decks as printable PDFs: double-sided flashcard sheets and practice tests.
+//
+// Text is set in the Go fonts, which cover Latin, Greek and Cyrillic scripts. An optional fallback
+// TrueType font, e.g. one for Chinese, Japanese or Korean, takes over the texts the Go fonts can't show.
+// Only the glyphs in use are embedded.
+package printout
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	FormatPDF   = "pdf"
+	ContentType = "application/pdf"
+
+	LayoutFlashcards = "flashcards"
+	LayoutTest       = "test"
+
+	PaperA4     = "a4"
+	PaperLetter = "letter"
+
+	QuestionsWritten = "written"
+	QuestionsChoice  = "choice"
+	QuestionsMixed   = "mixed"
+
+	DefaultCardsPerPage = 8
+	// DefaultFontSize suits flashcards, DefaultTestFontSize the denser practice tests
+	DefaultFontSize     = 14
+	DefaultTestFontSize = 11
+	MinFontSize         = 8
+	MaxFontSize         = 32
+)
+
+var ErrInvalidOptions = errors.New("invalid print options")
+
+// grids lays the supported numbers of cards per page out in columns and rows
+var grids = map[int]struct{ columns, rows int }{
+	1:  {1, 1},
+	2:  {1, 2},
+	4:  {2, 2},
+	6:  {2, 3},
+	8:  {2, 4},
+	10: {2, 5},
+	12: {3, 4},
+}
+
+var paperSizes = map[string]string{PaperA4: "A4", PaperLetter: "Letter"}
+
+type Options struct {
+	// Layout is flashcards or test
+	Layout string
+	// Paper is a4 or letter
+	Paper string
+	// CardsPerPage is 1, 2, 4, 6, 8, 10 or 12, it only applies to flashcards
+	CardsPerPage int
+	// FontSize is in points, long texts shrink to fit their card
+	FontSize float64
+	// Questions picks written or multiple choice questions for a test, mixed by default
+	Questions string
+	// QuestionCount limits a test to as many random cards, all of them by default
+	QuestionCount int
+	// Seed shuffles a test, the same seed prints the same test
+	Seed int64
+}
+
+// WithDefaults fills in the options left empty
+func (o Options) WithDefaults() Options {
+	if o.Layout == "" {
+		o.Layout = LayoutFlashcards
+	}
+	if o.Paper == "" {
+		o.Paper = PaperA4
+	}
+	if o.CardsPerPage == 0 {
+		o.CardsPerPage = DefaultCardsPerPage
+	}
+	if o.FontSize == 0 {
+		o.FontSize = DefaultFontSize
+		if o.Layout == LayoutTest {
+			o.FontSize = DefaultTestFontSize
+		}
+	}
+	if o.Questions == "" {
+		o.Questions = QuestionsMixed
+	}
+	return o
+}
+
+// Validate reports options that can't be printed, empty ones take their defaults
+func (o Options) Validate() error {
+	o = o.WithDefaults()
+	if o.Layout != LayoutFlashcards && o.Layout != LayoutTest {
+		return fmt.Errorf("%w: unknown layout %q", ErrInvalidOptions, o.Layout)
+	}
+	if _, ok := paperSizes[o.Paper]; !ok {
+		return fmt.Errorf("%w: unknown paper size %q", ErrInvalidOptions, o.Paper)
+	}
+	if _, ok := grids[o.CardsPerPage]; !ok {
+		return fmt.Errorf("%w: %d cards don't fit a page, use 1, 2, 4, 6, 8, 10 or 12", ErrInvalidOptions, o.CardsPerPage)
+	}
+	if o.FontSize < MinFontSize || o.FontSize > MaxFontSize {
+		return fmt.Errorf("%w: font size must be between %d and %d points", ErrInvalidOptions, MinFontSize, MaxFontSize)
+	}
+	if o.Questions != QuestionsWritten && o.Questions != QuestionsChoice && o.Questions != QuestionsMixed {
+		return fmt.Errorf("%w: unknown question type %q", ErrInvalidOptions, o.Questions)
+	}
+	if o.QuestionCount < 0 {
+		return fmt.Errorf("%w: question count can't be negative", ErrInvalidOptions)
+	}
+	return nil
+}
+
+// Card is the printed content of a card
+type Card struct {
+	Front        string
+	Back         string
+	Hint         string
+	Alternatives []string
+}
+
+// Printer renders PDFs, it is safe for concurrent use
+type Printer struct {
+	// fonts are tried in order for every text
+	fonts []font
+}
+
+type font struct {
+	family  string
+	regular []byte
+	bold    []byte
+	// glyphs tells which runes the font can show
+	glyphs *sfnt.Font
+}
+
+// NewPrinter loads the fallback font from the TrueType file at the path,
+// without a path texts the Go fonts can't show are printed with question marks
+func NewPrinter(fallbackPath string) (*Printer, error) {
+	goFont, err := newFont(goFamily, goregular.TTF, gobold.TTF)
+	if err != nil {
+		return nil, err
+	}
+	printer := &Printer{fonts: []font{goFont}}
+	if fallbackPath == "" {
+		return printer, nil
+	}
+
+	data, err := os.ReadFile(fallbackPath)
+	if err != nil {
+		return nil, err
+	}
+	// a single weight is enough for the fallback, bold texts use it as well
+	fallback, err := newFont(fallbackFamily, data, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fallbackPath, err)
+	}
+	printer.fonts = append(printer.fonts, fallback)
+	return printer, nil
+}
+
+func newFont(family string, regular, bold []byte) (font, error) {
+	// the PDF writer only embeds TrueType outlines
+	if bytes.HasPrefix(regular, []byte("OTTO")) || bytes.HasPrefix(regular, []byte("ttcf")) {
+		return font{}, errors.New("only TrueType fonts are supported, not OpenType CFF fonts or font collections")
+	}
+	glyphs, err := sfnt.Parse(regular)
+	if err != nil {
+		return font{}, err
+	}
+	// embedding fails on fonts the PDF writer can't read, better at startup than with the first export
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(family, "", regular)
+	if err := pdf.Error(); err != nil {
+		return font{}, err
+	}
+	return font{family: family, regular: regular, bold: bold, glyphs: glyphs}, nil
+}
+
+// Print writes the cards in the layout of the options as a PDF
+func (p *Printer) Print(w io.Writer, title string, cards []Card, opts Options, now time.Time) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	d := p.newDocument(title, opts.WithDefaults(), now)
+	switch {
+	case len(cards) == 0:
+		d.empty(title)
+	case d.opts.Layout == LayoutTest:
+		d.test(title, cards)
+	default:
+		d.flashcards(cards)
+	}
+	return d.pdf.Output(w)
+}
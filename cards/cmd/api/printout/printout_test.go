@@ -0,0 +1,147 @@
+package printout
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+var printedAt = time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC)
+
+func testCards(count int) []Card {
+	cards := make([]Card, 0, count)
+	for i := 1; i <= count; i++ {
+		cards = append(cards, Card{
+			Front:        fmt.Sprintf("front %d", i),
+			Back:         fmt.Sprintf("back %d", i),
+			Hint:         fmt.Sprintf("hint %d", i),
+			Alternatives: []string{fmt.Sprintf("alternative %d", i)},
+		})
+	}
+	return cards
+}
+
+// render renders the cards and checks the output is a complete PDF
+func render(t *testing.T, printer *Printer, cards []Card, opts Options) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	if err := printer.Print(&out, "Deck", cards, opts, printedAt); err != nil {
+		t.Fatalf("%+v: %v", opts, err)
+	}
+	pdf := bytes.TrimSpace(out.Bytes())
+	if !bytes.HasPrefix(pdf, []byte("%PDF-")) || !bytes.HasSuffix(pdf, []byte("%%EOF")) {
+		t.Errorf("%+v: output of %d bytes is not a PDF", opts, out.Len())
+	}
+	return out.Bytes()
+}
+
+// pageCount counts the page objects of a PDF
+func pageCount(pdf []byte) int {
+	return bytes.Count(pdf, []byte("/Type /Page\n"))
+}
+
+func newTestPrinter(t *testing.T) *Printer {
+	t.Helper()
+	printer, err := NewPrinter("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return printer
+}
+
+func TestPrintFlashcards(t *testing.T) {
+	printer := newTestPrinter(t)
+	for _, paper := range []string{PaperA4, PaperLetter} {
+		for perPage, grid := range grids {
+			opts := Options{Layout: LayoutFlashcards, Paper: paper, CardsPerPage: perPage}
+			pdf := render(t, printer, testCards(13), opts)
+			// every sheet has a page of fronts and a page of backs
+			sheets := (13 + grid.columns*grid.rows - 1) / (grid.columns * grid.rows)
+			if got := pageCount(pdf); got != 2*sheets {
+				t.Errorf("%+v: %d pages, want %d", opts, got, 2*sheets)
+			}
+		}
+	}
+}
+
+func TestPrintTest(t *testing.T) {
+	printer := newTestPrinter(t)
+	for _, questions := range []string{QuestionsWritten, QuestionsChoice, QuestionsMixed} {
+		for _, count := range []int{0, 3} {
+			opts := Options{Layout: LayoutTest, Questions: questions, QuestionCount: count, Seed: 7}
+			pdf := render(t, printer, testCards(10), opts)
+			if pageCount(pdf) < 2 {
+				t.Errorf("%+v: %d pages, want the test and its answer key", opts, pageCount(pdf))
+			}
+		}
+	}
+
+	// multiple choice falls back to written questions without enough distinct answers
+	render(t, printer, testCards(2), Options{Layout: LayoutTest, Questions: QuestionsChoice})
+}
+
+func TestPrintTestIsSeeded(t *testing.T) {
+	printer := newTestPrinter(t)
+	opts := Options{Layout: LayoutTest, Questions: QuestionsMixed, QuestionCount: 5, Seed: 42}
+	first := render(t, printer, testCards(20), opts)
+	if again := render(t, printer, testCards(20), opts); !bytes.Equal(first, again) {
+		t.Error("the same seed printed different tests")
+	}
+	opts.Seed = 43
+	if other := render(t, printer, testCards(20), opts); bytes.Equal(first, other) {
+		t.Error("another seed printed the same test")
+	}
+}
+
+func TestPrintEmptyDeck(t *testing.T) {
+	printer := newTestPrinter(t)
+	for _, layout := range []string{LayoutFlashcards, LayoutTest} {
+		if pdf := render(t, printer, nil, Options{Layout: layout}); pageCount(pdf) != 1 {
+			t.Errorf("%s: %d pages for an empty deck, want 1", layout, pageCount(pdf))
+		}
+	}
+}
+
+func TestPrintDifficultText(t *testing.T) {
+	printer := newTestPrinter(t)
+	cards := []Card{
+		{Front: strings.Repeat("a long sentence that wraps ", 200), Back: strings.Repeat("word ", 1000)},
+		{Front: strings.Repeat("unbreakable", 300), Back: strings.Repeat("x", 5000), Hint: strings.Repeat("hint ", 200)},
+		{Front: "Ελληνικά και кириллица", Back: "Größe, naïve, façade"},
+		{Front: "日本語と한국어", Back: "中文"},
+		{Front: "emoji 🎉 and ✓", Back: "tabs\tand\nnew\nlines"},
+		{Front: "\u202eright to left\u202c", Back: "zero\u200bwidth"},
+	}
+	for _, layout := range []string{LayoutFlashcards, LayoutTest} {
+		for _, size := range []float64{MinFontSize, MaxFontSize} {
+			render(t, printer, cards, Options{Layout: layout, FontSize: size, CardsPerPage: 12})
+		}
+	}
+}
+
+func TestInvalidOptions(t *testing.T) {
+	printer := newTestPrinter(t)
+	for _, opts := range []Options{
+		{Layout: "poster"},
+		{Paper: "a3"},
+		{CardsPerPage: 3},
+		{FontSize: MinFontSize - 1},
+		{FontSize: MaxFontSize + 1},
+		{Layout: LayoutTest, Questions: "essay"},
+		{Layout: LayoutTest, QuestionCount: -1},
+	} {
+		err := printer.Print(&bytes.Buffer{}, "Deck", testCards(1), opts, printedAt)
+		if !errors.Is(err, ErrInvalidOptions) {
+			t.Errorf("%+v: got %v, want ErrInvalidOptions", opts, err)
+		}
+	}
+}
+
+func TestMissingFallbackFont(t *testing.T) {
+	if _, err := NewPrinter("missing.ttf"); err == nil {
+		t.Error("printer loaded a missing font")
+	}
+}
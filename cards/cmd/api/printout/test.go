@@ -0,0 +1,179 @@
+package printout
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+const (
+	// pageMargin surrounds the text of a test
+	pageMargin = 20
+	// choiceCount is the number of options of a multiple choice question
+	choiceCount = 4
+	// answerLines are ruled below a written question
+	answerLines = 2
+	// answerLineSpacing is the distance between ruled lines
+	answerLineSpacing = 9
+	// choiceIndent sets the options of a question apart from it
+	choiceIndent = 8
+)
+
+type question struct {
+	card Card
+	// choices are the options of a multiple choice question, empty for a written one
+	choices []string
+	// answer indexes the right choice
+	answer int
+}
+
+// test prints a shuffled practice test, then its answer key on pages of its own
+func (d *document) test(title string, cards []Card) {
+	questions := d.questions(cards)
+	d.pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	d.pdf.SetAutoPageBreak(true, pageMargin)
+	d.pdf.AliasNbPages("")
+	d.pdf.SetFooterFunc(d.pageNumber)
+
+	d.pdf.AddPage()
+	d.paragraph(title, d.opts.FontSize*1.6, true, black, 0)
+	d.paragraph(fmt.Sprintf("Practice test, %d questions", len(questions)), d.opts.FontSize, false, gray, 0)
+	d.pdf.Ln(4)
+	d.paragraph("Name: ______________________________    Date: ______________", d.opts.FontSize, false, black, 0)
+	d.pdf.Ln(6)
+	for i, q := range questions {
+		d.question(i+1, q)
+	}
+
+	d.pdf.AddPage()
+	d.paragraph("Answer key: "+title, d.opts.FontSize*1.6, true, black, 0)
+	d.pdf.Ln(4)
+	for i, q := range questions {
+		d.paragraph(fmt.Sprintf("%d. %s", i+1, answer(q)), d.opts.FontSize, false, black, 0)
+	}
+}
+
+// questions picks and shuffles the cards, multiple choice needs enough distinct answers for the options
+func (d *document) questions(cards []Card) []question {
+	rng := rand.New(rand.NewSource(d.opts.Seed))
+	order := rng.Perm(len(cards))
+	if d.opts.QuestionCount > 0 && d.opts.QuestionCount < len(order) {
+		order = order[:d.opts.QuestionCount]
+	}
+
+	var backs []string
+	seen := make(map[string]bool)
+	for _, card := range cards {
+		if !seen[card.Back] {
+			seen[card.Back] = true
+			backs = append(backs, card.Back)
+		}
+	}
+
+	questions := make([]question, 0, len(order))
+	for _, i := range order {
+		q := question{card: cards[i]}
+		choice := d.opts.Questions == QuestionsChoice || d.opts.Questions == QuestionsMixed && rng.Intn(2) == 0
+		if choice && len(backs) >= choiceCount {
+			q.choices, q.answer = choices(rng, q.card.Back, backs)
+		}
+		questions = append(questions, q)
+	}
+	return questions
+}
+
+// choices mixes the answer with distractors drawn from the other answers of the deck
+func choices(rng *rand.Rand, answer string, backs []string) ([]string, int) {
+	distractors := make([]string, 0, len(backs)-1)
+	for _, back := range backs {
+		if back != answer {
+			distractors = append(distractors, back)
+		}
+	}
+	rng.Shuffle(len(distractors), func(i, j int) {
+		distractors[i], distractors[j] = distractors[j], distractors[i]
+	})
+
+	options := append([]string{answer}, distractors[:choiceCount-1]...)
+	rng.Shuffle(len(options), func(i, j int) {
+		options[i], options[j] = options[j], options[i]
+	})
+	for i, option := range options {
+		if option == answer {
+			return options, i
+		}
+	}
+	return options, 0
+}
+
+// question keeps a question on one page unless it is longer than a page
+func (d *document) question(number int, q question) {
+	prompt := fmt.Sprintf("%d. %s", number, q.card.Front)
+	height := d.measure(prompt, true, 0) + 3
+	if len(q.choices) == 0 {
+		height += answerLines * answerLineSpacing
+	}
+	for i, choice := range q.choices {
+		height += d.measure(choiceLabel(i)+choice, false, choiceIndent)
+	}
+	_, pageHeight := d.pdf.GetPageSize()
+	if d.pdf.GetY()+height > pageHeight-pageMargin {
+		d.pdf.AddPage()
+	}
+
+	d.paragraph(prompt, d.opts.FontSize, true, black, 0)
+	if len(q.choices) == 0 {
+		pageWidth, _ := d.pdf.GetPageSize()
+		d.pdf.SetDrawColor(gray[0], gray[1], gray[2])
+		d.pdf.SetLineWidth(0.2)
+		for i := 0; i < answerLines; i++ {
+			y := d.pdf.GetY() + answerLineSpacing
+			d.pdf.Line(pageMargin, y, pageWidth-pageMargin, y)
+			d.pdf.SetY(y)
+		}
+	}
+	for i, choice := range q.choices {
+		d.paragraph(choiceLabel(i)+choice, d.opts.FontSize, false, black, choiceIndent)
+	}
+	d.pdf.Ln(3)
+}
+
+// paragraph writes wrapped text at the left margin plus the indent, breaking pages as needed
+func (d *document) paragraph(text string, size float64, bold bool, color [3]int, indent float64) {
+	text, _ = d.setFont(text, bold, size)
+	d.pdf.SetTextColor(color[0], color[1], color[2])
+	pageWidth, _ := d.pdf.GetPageSize()
+	d.pdf.SetX(pageMargin + indent)
+	d.pdf.MultiCell(pageWidth-2*pageMargin-indent, size*ptToMM*lineSpacing, text, "", "L", false)
+}
+
+// measure returns the height of a paragraph at the font size of the options
+func (d *document) measure(text string, bold bool, indent float64) float64 {
+	text, _ = d.setFont(text, bold, d.opts.FontSize)
+	pageWidth, _ := d.pdf.GetPageSize()
+	lines := 0
+	for _, paragraph := range strings.Split(text, "\n") {
+		lines += max(len(d.pdf.SplitText(paragraph, pageWidth-2*pageMargin-indent)), 1)
+	}
+	return float64(lines) * d.opts.FontSize * ptToMM * lineSpacing
+}
+
+func (d *document) pageNumber() {
+	text, family := d.setFont(fmt.Sprintf("Page %d of {nb}", d.pdf.PageNo()), false, minFitSize+2)
+	pageWidth, pageHeight := d.pdf.GetPageSize()
+	d.write(pageMargin, pageHeight-pageMargin+5, pageWidth-2*pageMargin, line{text: text, family: family, size: minFitSize + 2, color: gray}, "C")
+}
+
+func answer(q question) string {
+	if len(q.choices) > 0 {
+		return choiceLabel(q.answer) + q.card.Back
+	}
+	if len(q.card.Alternatives) > 0 {
+		return fmt.Sprintf("%s (also: %s)", q.card.Back, strings.Join(q.card.Alternatives, ", "))
+	}
+	return q.card.Back
+}
+
+func choiceLabel(index int) string {
+	return string(rune('A'+index)) + ") "
+}
@@ -0,0 +1,75 @@
+package quiz
+
+import (
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"time"
+)
+
+const (
+	MinTimeLimit = 10 * time.Second
+	MaxTimeLimit = time.Hour
+
+	// ChallengeBasePoints are scored by a correct answer without a combo
+	ChallengeBasePoints = 100
+	// ComboStep correct answers in a row raise the multiplier by one, up to MaxMultiplier
+	ComboStep     = 5
+	MaxMultiplier = 4
+
+	// maxChallengeQuestions bounds the questions prepared for a challenge, nobody answers more than one per second
+	maxChallengeQuestions = 3600
+)
+
+// Multiplier returns the points multiplier for the number of correct answers in a row
+func Multiplier(combo int) int {
+	if combo < 1 {
+		return 1
+	}
+	return min(1+(combo-1)/ComboStep, MaxMultiplier)
+}
+
+// IsChallenge reports whether the session is a timed challenge
+func IsChallenge(session *models.QuizSession) bool {
+	return session.Challenge != nil
+}
+
+// challengeQuestionCount is how many questions a challenge prepares, the deck is repeated as often as needed
+func challengeQuestionCount(limit time.Duration) int {
+	return min(int(limit/time.Second), maxChallengeQuestions)
+}
+
+// timeUp reports whether an answer to the current question received at now is too late for the challenge.
+// Answers arriving within the latency allowance after the deadline still count, they were most likely
+// sent in time. The allowance only covers questions asked before the deadline, otherwise a quick client
+// could keep answering questions shown after the time is up.
+func (e *Engine) timeUp(session *models.QuizSession, now time.Time) bool {
+	if !IsChallenge(session) {
+		return false
+	}
+	deadline := session.Challenge.Deadline
+	if !now.After(deadline) {
+		return false
+	}
+	if session.Current < len(session.Questions) && !session.Questions[session.Current].AskedAt.Before(deadline) {
+		return true
+	}
+	return now.After(deadline.Add(e.latencyAllowance))
+}
+
+// ChallengeOverBefore returns the time before which challenges must have reached their deadline
+// to be over at now, whatever question they are on
+func (e *Engine) ChallengeOverBefore(now time.Time) time.Time {
+	return now.Add(-e.latencyAllowance)
+}
+
+// score updates the challenge score with the graded answer and returns the points it earned
+func score(challenge *models.QuizChallenge, correct bool) int {
+	if !correct {
+		challenge.Combo = 0
+		return 0
+	}
+	challenge.Combo++
+	challenge.MaxCombo = max(challenge.MaxCombo, challenge.Combo)
+	points := ChallengeBasePoints * Multiplier(challenge.Combo)
+	challenge.Score += points
+	return points
+}
@@ -0,0 +1,387 @@
+// Package quiz implements quiz sessions as server-side state machines.
+//
+// A session moves between the states
+//
+//	active <-> paused
+//	active, paused -> completed
+//	active, paused -> expired
+//
+// and every mutation extends its expiry. Timed challenges can't be paused and are completed
+// once their deadline passes. The engine only changes the session value, persisting it is up to the caller.
+package quiz
+
+import (
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"math/rand"
+	"time"
+)
+
+const (
+	OrderSequential = "sequential"
+	OrderRandom     = "random"
+)
+
+const (
+	DefaultChoiceCount = 4
+	MinChoiceCount     = 2
+	MaxChoiceCount     = 8
+)
+
+type Options struct {
+	// CardCount limits the number of cards studied, 0 takes the whole deck
+	CardCount int
+	Order     string
+	Modes     []string
+	// ChoiceCount is the number of options in choice questions, 0 uses DefaultChoiceCount
+	ChoiceCount int
+	// TimeLimit turns the session into a timed challenge, the selected cards are repeated until time is up
+	TimeLimit time.Duration
+}
+
+type Engine struct {
+	modes Modes
+	ttl   time.Duration
+	// latencyAllowance is how late after the deadline an answer to a challenge may arrive
+	latencyAllowance time.Duration
+}
+
+func NewEngine(modes Modes, ttl, latencyAllowance time.Duration) *Engine {
+	return &Engine{modes: modes, ttl: ttl, latencyAllowance: latencyAllowance}
+}
+
+// Start builds the questions of a new session, session.ID, UserID, DeckID and Seed must already be set
+func (e *Engine) Start(session *models.QuizSession, deck []models.Card, opts Options, now time.Time) error {
+	if opts.Order != OrderSequential && opts.Order != OrderRandom {
+		return ErrUnknownOrder
+	}
+	modes := make([]Mode, 0, len(opts.Modes))
+	for _, name := range opts.Modes {
+		mode, err := e.modes.Get(name)
+		if err != nil {
+			return err
+		}
+		if opts.TimeLimit > 0 && SelfGraded(name) {
+			return ErrSelfGradedChallenge
+		}
+		modes = append(modes, mode)
+	}
+	// scores of challenges must be graded by the server, typing fits any card as well
+	var fallback Mode = FlashcardMode{}
+	if opts.TimeLimit > 0 {
+		fallback = TypedMode{}
+	}
+
+	choiceCount := opts.ChoiceCount
+	if choiceCount == 0 {
+		choiceCount = DefaultChoiceCount
+	}
+	if choiceCount < MinChoiceCount || choiceCount > MaxChoiceCount {
+		return ErrInvalidChoiceCount
+	}
+	if opts.TimeLimit != 0 && (opts.TimeLimit < MinTimeLimit || opts.TimeLimit > MaxTimeLimit) {
+		return ErrInvalidTimeLimit
+	}
+
+	rng := rand.New(rand.NewSource(session.Seed))
+	bc := BuildContext{Deck: deck, Rng: rng, ChoiceCount: choiceCount}
+	selected := append([]models.Card(nil), deck...)
+	if opts.Order == OrderRandom {
+		rng.Shuffle(len(selected), func(i, j int) { selected[i], selected[j] = selected[j], selected[i] })
+	}
+
+	if opts.CardCount > 0 && opts.CardCount < len(selected) {
+		selected = selected[:opts.CardCount]
+	}
+
+	questions, err := buildRound(modes, fallback, selected, bc)
+	if err != nil {
+		return err
+	}
+	if len(questions) == 0 {
+		return ErrNoQuestions
+	}
+	if opts.TimeLimit > 0 {
+		count := challengeQuestionCount(opts.TimeLimit)
+		for len(questions) < count {
+			if opts.Order == OrderRandom {
+				rng.Shuffle(len(selected), func(i, j int) { selected[i], selected[j] = selected[j], selected[i] })
+			}
+			round, err := buildRound(modes, fallback, selected, bc)
+			if err != nil {
+				return err
+			}
+			questions = append(questions, round...)
+		}
+		questions = questions[:count]
+		session.Challenge = &models.QuizChallenge{TimeLimit: opts.TimeLimit, Deadline: now.Add(opts.TimeLimit)}
+	}
+	questions[0].AskedAt = now
+
+	session.Status = models.QuizStatusActive
+	session.Order = opts.Order
+	session.Modes = opts.Modes
+	session.Questions = questions
+	session.Current = 0
+	session.ActiveDuration = 0
+	session.ResumedAt = now
+	session.CreatedAt = now
+	e.touch(session, now)
+	return nil
+}
+
+// buildRound builds questions covering every selected card once
+func buildRound(modes []Mode, fallback Mode, selected []models.Card, bc BuildContext) ([]models.QuizQuestion, error) {
+	questions := make([]models.QuizQuestion, 0, len(selected))
+	covered := make(map[string]bool, len(selected))
+	for i, card := range selected {
+		if covered[card.ID] {
+			continue
+		}
+		bc.Pending = pendingCards(selected[i+1:], covered)
+		question, err := buildQuestion(modes, fallback, card, bc)
+		if err != nil {
+			return nil, err
+		}
+		covered[card.ID] = true
+		for _, id := range question.GroupCardIDs {
+			covered[id] = true
+		}
+		questions = append(questions, question)
+	}
+	return questions, nil
+}
+
+// buildQuestion picks a random mode for the card and falls back to the other selected modes
+// when the picked one can't use the card, and to the fallback mode when none of them can
+func buildQuestion(modes []Mode, fallback Mode, card models.Card, bc BuildContext) (models.QuizQuestion, error) {
+	first := bc.Rng.Intn(len(modes))
+	candidates := make([]Mode, 0, len(modes)+1)
+	for i := range modes {
+		candidates = append(candidates, modes[(first+i)%len(modes)])
+	}
+	candidates = append(candidates, fallback)
+
+	for _, mode := range candidates {
+		question, err := mode.Build(card, bc)
+		if errors.Is(err, ErrUnsupportedCard) {
+			continue
+		}
+		if err != nil {
+			return models.QuizQuestion{}, err
+		}
+		return models.QuizQuestion{
+			CardID:            card.ID,
+			GroupCardIDs:      question.Group,
+			Mode:              mode.Name(),
+			Prompt:            question.Prompt,
+			Hint:              question.Hint,
+			Terms:             question.Terms,
+			Options:           question.Options,
+			Statement:         question.Statement,
+			Expected:          question.Expected,
+			Accepted:          question.Accepted,
+			Status:            models.QuestionStatusPending,
+			AnswerType:        question.AnswerType,
+			AbsoluteTolerance: question.AbsoluteTolerance,
+			RelativeTolerance: question.RelativeTolerance,
+		}, nil
+	}
+	return models.QuizQuestion{}, ErrUnsupportedCard
+}
+
+func pendingCards(cards []models.Card, covered map[string]bool) []models.Card {
+	pending := make([]models.Card, 0, len(cards))
+	for _, card := range cards {
+		if !covered[card.ID] {
+			pending = append(pending, card)
+		}
+	}
+	return pending
+}
+
+// Expire moves a session whose expiry has passed into the expired state, or completes a challenge
+// whose time is up, and reports whether it did
+func (e *Engine) Expire(session *models.QuizSession, now time.Time) bool {
+	if !isOpen(session) {
+		return false
+	}
+	if e.timeUp(session, now) {
+		// the session ends at the deadline, or at the last answer accepted within the latency allowance
+		end := session.Challenge.Deadline
+		if session.UpdatedAt.After(end) {
+			end = session.UpdatedAt
+		}
+		e.complete(session, end)
+		return true
+	}
+	if !now.After(session.ExpiresAt) {
+		return false
+	}
+	if session.Status == models.QuizStatusActive {
+		// idle time before the expiry isn't study time, count up to the last activity only
+		session.ActiveDuration += session.UpdatedAt.Sub(session.ResumedAt)
+	}
+	session.Status = models.QuizStatusExpired
+	session.UpdatedAt = now
+	return true
+}
+
+// CurrentQuestion returns the question to answer next, ok is false once there is none left
+func (e *Engine) CurrentQuestion(session *models.QuizSession) (question models.QuizQuestion, ok bool) {
+	if session.Current >= len(session.Questions) {
+		return models.QuizQuestion{}, false
+	}
+	return session.Questions[session.Current], true
+}
+
+// Answer grades the answer to the current question and moves on to the next one
+func (e *Engine) Answer(session *models.QuizSession, index int, answer string, now time.Time) (Result, error) {
+	if err := e.checkCurrent(session, index); err != nil {
+		return Result{}, err
+	}
+	if e.timeUp(session, now) {
+		return Result{}, ErrTimeUp
+	}
+	question := &session.Questions[index]
+	mode, err := e.modes.Get(question.Mode)
+	if err != nil {
+		return Result{}, err
+	}
+	result, err := mode.Grade(*question, answer)
+	if err != nil {
+		return Result{}, err
+	}
+
+	question.Status = models.QuestionStatusAnswered
+	question.Answer = answer
+	question.Correct = result.Correct
+	question.MissedCardIDs = result.Missed
+	question.AnsweredAt = now
+	if IsChallenge(session) {
+		question.Points = score(session.Challenge, result.Correct)
+		result.Points = question.Points
+		result.Combo = session.Challenge.Combo
+	}
+	e.advance(session, now)
+	return result, nil
+}
+
+// Skip leaves the current question unanswered and moves on to the next one
+func (e *Engine) Skip(session *models.QuizSession, index int, now time.Time) error {
+	if err := e.checkCurrent(session, index); err != nil {
+		return err
+	}
+	if e.timeUp(session, now) {
+		return ErrTimeUp
+	}
+	question := &session.Questions[index]
+	question.Status = models.QuestionStatusSkipped
+	question.AnsweredAt = now
+	if IsChallenge(session) {
+		// skipping breaks the combo
+		session.Challenge.Combo = 0
+	}
+	e.advance(session, now)
+	return nil
+}
+
+func (e *Engine) Pause(session *models.QuizSession, now time.Time) error {
+	if err := checkOpen(session); err != nil {
+		return err
+	}
+	if session.Status == models.QuizStatusPaused {
+		return ErrSessionPaused
+	}
+	if IsChallenge(session) {
+		return ErrChallengePause
+	}
+	session.ActiveDuration += now.Sub(session.ResumedAt)
+	session.Status = models.QuizStatusPaused
+	e.touch(session, now)
+	return nil
+}
+
+func (e *Engine) Resume(session *models.QuizSession, now time.Time) error {
+	if err := checkOpen(session); err != nil {
+		return err
+	}
+	if session.Status != models.QuizStatusPaused {
+		return ErrSessionActive
+	}
+	// a paused session was last touched when it was paused, the pause doesn't count as answer time
+	if session.Current < len(session.Questions) {
+		question := &session.Questions[session.Current]
+		question.AskedAt = question.AskedAt.Add(now.Sub(session.UpdatedAt))
+	}
+	session.Status = models.QuizStatusActive
+	session.ResumedAt = now
+	e.touch(session, now)
+	return nil
+}
+
+// Finish completes the session, finishing a completed session again is a no-op reported by completed being false
+func (e *Engine) Finish(session *models.QuizSession, now time.Time) (completed bool, err error) {
+	if session.Status == models.QuizStatusCompleted {
+		return false, nil
+	}
+	if err := checkOpen(session); err != nil {
+		return false, err
+	}
+	e.complete(session, now)
+	return true, nil
+}
+
+func (e *Engine) checkCurrent(session *models.QuizSession, index int) error {
+	if err := checkOpen(session); err != nil {
+		return err
+	}
+	if session.Status == models.QuizStatusPaused {
+		return ErrSessionPaused
+	}
+	if index != session.Current {
+		return ErrQuestionMismatch
+	}
+	return nil
+}
+
+func (e *Engine) advance(session *models.QuizSession, now time.Time) {
+	for session.Current < len(session.Questions) &&
+		session.Questions[session.Current].Status != models.QuestionStatusPending {
+		session.Current++
+	}
+	if session.Current == len(session.Questions) {
+		e.complete(session, now)
+		return
+	}
+	session.Questions[session.Current].AskedAt = now
+	e.touch(session, now)
+}
+
+func (e *Engine) complete(session *models.QuizSession, now time.Time) {
+	if session.Status == models.QuizStatusActive {
+		session.ActiveDuration += now.Sub(session.ResumedAt)
+	}
+	session.Status = models.QuizStatusCompleted
+	session.CompletedAt = now
+	session.UpdatedAt = now
+}
+
+func (e *Engine) touch(session *models.QuizSession, now time.Time) {
+	session.UpdatedAt = now
+	session.ExpiresAt = now.Add(e.ttl)
+}
+
+func isOpen(session *models.QuizSession) bool {
+	return session.Status == models.QuizStatusActive || session.Status == models.QuizStatusPaused
+}
+
+func checkOpen(session *models.QuizSession) error {
+	switch session.Status {
+	case models.QuizStatusCompleted:
+		return ErrSessionCompleted
+	case models.QuizStatusExpired:
+		return ErrSessionExpired
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+package quiz
+
+import (
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"testing"
+	"time"
+)
+
+const testTTL = time.Hour
+
+var startedAt = time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC)
+
+func testEngine() *Engine {
+	return NewEngine(DefaultModes(), testTTL, 2*time.Second)
+}
+
+// startSession starts a sequential flashcard session on the first cards of the capitals
+func startSession(t *testing.T, e *Engine, cardCount int) *models.QuizSession {
+	t.Helper()
+	session := &models.QuizSession{ID: "session", UserID: "user", DeckID: "deck", Seed: 1}
+	opts := Options{Order: OrderSequential, Modes: []string{ModeFlashcard}}
+	if err := e.Start(session, capitals[:cardCount], opts, startedAt); err != nil {
+		t.Fatal(err)
+	}
+	return session
+}
+
+func at(d time.Duration) time.Time {
+	return startedAt.Add(d)
+}
+
+func TestSessionLifecycle(t *testing.T) {
+	e := testEngine()
+	session := startSession(t, e, 3)
+	if session.Status != models.QuizStatusActive || len(session.Questions) != 3 || session.Current != 0 {
+		t.Fatalf("started session %s with %d questions at %d", session.Status, len(session.Questions), session.Current)
+	}
+	for i, question := range session.Questions {
+		if question.CardID != capitals[i].ID || question.Status != models.QuestionStatusPending {
+			t.Errorf("question %d is %s for card %s", i, question.Status, question.CardID)
+		}
+	}
+	if !session.ExpiresAt.Equal(at(testTTL)) {
+		t.Errorf("expires at %v", session.ExpiresAt)
+	}
+
+	if result, err := e.Answer(session, 0, FlashcardCorrect, at(10*time.Second)); err != nil || !result.Correct {
+		t.Fatalf("answer returned %+v, %v", result, err)
+	}
+	if err := e.Pause(session, at(20*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if session.Status != models.QuizStatusPaused || session.ActiveDuration != 20*time.Second {
+		t.Errorf("paused session %s active for %v", session.Status, session.ActiveDuration)
+	}
+	if _, err := e.Answer(session, 1, FlashcardCorrect, at(30*time.Second)); !errors.Is(err, ErrSessionPaused) {
+		t.Errorf("answer while paused returned %v", err)
+	}
+	if err := e.Pause(session, at(30*time.Second)); !errors.Is(err, ErrSessionPaused) {
+		t.Errorf("second pause returned %v", err)
+	}
+
+	// the pause doesn't count as time spent on the question
+	asked := session.Questions[1].AskedAt
+	if err := e.Resume(session, at(80*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if shift := session.Questions[1].AskedAt.Sub(asked); shift != time.Minute {
+		t.Errorf("asked at moved by %v, want the minute of the pause", shift)
+	}
+	if err := e.Resume(session, at(80*time.Second)); !errors.Is(err, ErrSessionActive) {
+		t.Errorf("second resume returned %v", err)
+	}
+
+	if err := e.Skip(session, 1, at(90*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Answer(session, 2, FlashcardIncorrect, at(100*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if session.Status != models.QuizStatusCompleted || !session.CompletedAt.Equal(at(100*time.Second)) {
+		t.Fatalf("session %s at %v after the last answer", session.Status, session.CompletedAt)
+	}
+	if _, ok := e.CurrentQuestion(session); ok {
+		t.Error("completed session has a current question")
+	}
+
+	if _, err := e.Answer(session, 2, FlashcardCorrect, at(110*time.Second)); !errors.Is(err, ErrSessionCompleted) {
+		t.Errorf("answer after completion returned %v", err)
+	}
+	if err := e.Pause(session, at(110*time.Second)); !errors.Is(err, ErrSessionCompleted) {
+		t.Errorf("pause after completion returned %v", err)
+	}
+	if completed, err := e.Finish(session, at(110*time.Second)); completed || err != nil {
+		t.Errorf("finishing again returned %v, %v, want a no-op", completed, err)
+	}
+
+	want := Summary{
+		Total: 3, Answered: 2, Correct: 1, Incorrect: 1, Skipped: 1,
+		Accuracy: 0.5, ActiveDuration: 40 * time.Second,
+	}
+	if summary := Summarize(session); summary != want {
+		t.Errorf("summary %+v, want %+v", summary, want)
+	}
+}
+
+func TestAnswerOutOfOrder(t *testing.T) {
+	e := testEngine()
+	session := startSession(t, e, 3)
+
+	if _, err := e.Answer(session, 1, FlashcardCorrect, at(time.Second)); !errors.Is(err, ErrQuestionMismatch) {
+		t.Errorf("answer ahead returned %v", err)
+	}
+	if _, err := e.Answer(session, 0, FlashcardCorrect, at(2*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	// another device answering the same question again is too late
+	if _, err := e.Answer(session, 0, FlashcardCorrect, at(3*time.Second)); !errors.Is(err, ErrQuestionMismatch) {
+		t.Errorf("answer behind returned %v", err)
+	}
+	if err := e.Skip(session, 2, at(4*time.Second)); !errors.Is(err, ErrQuestionMismatch) {
+		t.Errorf("skip ahead returned %v", err)
+	}
+	if _, err := e.Answer(session, 1, "maybe", at(5*time.Second)); !errors.Is(err, ErrInvalidAnswer) {
+		t.Errorf("answer the mode can't grade returned %v", err)
+	}
+	if session.Current != 1 || session.Questions[1].Status != models.QuestionStatusPending {
+		t.Errorf("rejected answers moved the session to %d", session.Current)
+	}
+}
+
+func TestFinishEarly(t *testing.T) {
+	e := testEngine()
+	session := startSession(t, e, 3)
+	if _, err := e.Answer(session, 0, FlashcardCorrect, at(10*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Pause(session, at(15*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	// a paused session is finished without counting the pause
+	if completed, err := e.Finish(session, at(time.Minute)); !completed || err != nil {
+		t.Fatalf("finish returned %v, %v", completed, err)
+	}
+	want := Summary{Total: 3, Answered: 1, Correct: 1, Unanswered: 2, Accuracy: 1, ActiveDuration: 15 * time.Second}
+	if summary := Summarize(session); summary != want {
+		t.Errorf("summary %+v, want %+v", summary, want)
+	}
+}
+
+func TestSessionExpiry(t *testing.T) {
+	e := testEngine()
+	session := startSession(t, e, 3)
+	if _, err := e.Answer(session, 0, FlashcardCorrect, at(10*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	// every mutation extends the expiry
+	if e.Expire(session, at(testTTL)) {
+		t.Fatal("session expired within the ttl of the last answer")
+	}
+	if !e.Expire(session, at(testTTL+11*time.Second)) {
+		t.Fatal("session didn't expire")
+	}
+	if session.Status != models.QuizStatusExpired || session.ActiveDuration != 10*time.Second {
+		t.Errorf("expired session %s active for %v, want the time until the last answer", session.Status, session.ActiveDuration)
+	}
+	if e.Expire(session, at(2*testTTL)) {
+		t.Error("expired session expired again")
+	}
+
+	now := at(2 * testTTL)
+	if _, err := e.Answer(session, 1, FlashcardCorrect, now); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("answer returned %v", err)
+	}
+	if err := e.Resume(session, now); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("resume returned %v", err)
+	}
+	if _, err := e.Finish(session, now); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("finish returned %v", err)
+	}
+
+	// a paused session expires without adding the pause to the active time
+	paused := startSession(t, e, 3)
+	if err := e.Pause(paused, at(5*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if !e.Expire(paused, at(2*testTTL)) || paused.Status != models.QuizStatusExpired || paused.ActiveDuration != 5*time.Second {
+		t.Errorf("paused session %s active for %v", paused.Status, paused.ActiveDuration)
+	}
+}
+
+func TestStartOptions(t *testing.T) {
+	e := testEngine()
+	tests := []struct {
+		name string
+		deck []models.Card
+		opts Options
+		err  error
+	}{
+		{"unknown order", capitals, Options{Order: "alphabetical", Modes: []string{ModeFlashcard}}, ErrUnknownOrder},
+		{"unknown mode", capitals, Options{Order: OrderRandom, Modes: []string{"essay"}}, ErrUnknownMode},
+		{"too few choices", capitals, Options{Order: OrderRandom, Modes: []string{ModeFlashcard}, ChoiceCount: 1}, ErrInvalidChoiceCount},
+		{"too many choices", capitals, Options{Order: OrderRandom, Modes: []string{ModeFlashcard}, ChoiceCount: 9}, ErrInvalidChoiceCount},
+		{"short challenge", capitals, Options{Order: OrderRandom, Modes: []string{ModeTyped}, TimeLimit: 5 * time.Second}, ErrInvalidTimeLimit},
+		{"self-graded challenge", capitals, Options{Order: OrderRandom, Modes: []string{ModeFlashcard}, TimeLimit: time.Minute}, ErrSelfGradedChallenge},
+		{"empty deck", nil, Options{Order: OrderRandom, Modes: []string{ModeFlashcard}}, ErrNoQuestions},
+	}
+	for _, tt := range tests {
+		session := &models.QuizSession{Seed: 1}
+		if err := e.Start(session, tt.deck, tt.opts, startedAt); !errors.Is(err, tt.err) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.err)
+		}
+	}
+
+	session := &models.QuizSession{Seed: 1}
+	if err := e.Start(session, capitals, Options{Order: OrderRandom, Modes: []string{ModeFlashcard}, CardCount: 5}, startedAt); err != nil {
+		t.Fatal(err)
+	}
+	if len(session.Questions) != 5 {
+		t.Errorf("%d questions, want the 5 cards asked for", len(session.Questions))
+	}
+	// a single card can't be matched, it falls back to flashcards
+	session = &models.QuizSession{Seed: 1}
+	if err := e.Start(session, capitals[:1], Options{Order: OrderRandom, Modes: []string{ModeMatching}}, startedAt); err != nil {
+		t.Fatal(err)
+	}
+	if session.Questions[0].Mode != ModeFlashcard {
+		t.Errorf("unmatchable card asked as %s", session.Questions[0].Mode)
+	}
+}
+
+func TestChallengeSession(t *testing.T) {
+	e := testEngine()
+	session := &models.QuizSession{Seed: 1}
+	opts := Options{Order: OrderRandom, Modes: []string{ModeMatching}, TimeLimit: 10 * time.Second}
+	if err := e.Start(session, capitals[:1], opts, startedAt); err != nil {
+		t.Fatal(err)
+	}
+	// the deck is repeated to fill the time, an unmatchable card is typed instead of self-graded
+	if len(session.Questions) != 10 || session.Questions[0].Mode != ModeTyped {
+		t.Fatalf("%d questions in mode %s", len(session.Questions), session.Questions[0].Mode)
+	}
+	if !session.Challenge.Deadline.Equal(at(10 * time.Second)) {
+		t.Errorf("deadline %v", session.Challenge.Deadline)
+	}
+
+	for i := 0; i < 2; i++ {
+		result, err := e.Answer(session, i, "Paris", at(time.Duration(i+1)*time.Second))
+		if err != nil || !result.Correct || result.Points != ChallengeBasePoints || result.Combo != i+1 {
+			t.Fatalf("answer %d returned %+v, %v", i, result, err)
+		}
+	}
+	if err := e.Pause(session, at(3*time.Second)); !errors.Is(err, ErrChallengePause) {
+		t.Errorf("pause returned %v", err)
+	}
+	// skipping breaks the combo
+	if err := e.Skip(session, 2, at(4*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if session.Challenge.Combo != 0 || session.Challenge.MaxCombo != 2 {
+		t.Errorf("combo %d, max %d after a skip", session.Challenge.Combo, session.Challenge.MaxCombo)
+	}
+
+	// the question was asked before the deadline, but the answer is later than the allowance
+	if _, err := e.Answer(session, 3, "Paris", at(13*time.Second)); !errors.Is(err, ErrTimeUp) {
+		t.Errorf("late answer returned %v", err)
+	}
+	if !e.Expire(session, at(13*time.Second)) || session.Status != models.QuizStatusCompleted || !session.CompletedAt.Equal(at(10*time.Second)) {
+		t.Fatalf("challenge %s at %v, want it completed at the deadline", session.Status, session.CompletedAt)
+	}
+
+	// questions the challenge never got to aren't part of the summary
+	want := Summary{
+		Total: 4, Answered: 2, Correct: 2, Skipped: 1, Unanswered: 1, Accuracy: 1,
+		ActiveDuration: 10 * time.Second, Score: 2 * ChallengeBasePoints, MaxCombo: 2,
+	}
+	if summary := Summarize(session); summary != want {
+		t.Errorf("summary %+v, want %+v", summary, want)
+	}
+}
@@ -0,0 +1,23 @@
+package quiz
+
+import "errors"
+
+var (
+	ErrUnknownMode         = errors.New("unknown quiz mode")
+	ErrUnknownOrder        = errors.New("unknown card order")
+	ErrInvalidChoiceCount  = errors.New("number of choices must be between 2 and 8")
+	ErrNoQuestions         = errors.New("deck has no cards")
+	ErrUnsupportedCard     = errors.New("card is not suitable for the mode")
+	ErrInvalidAnswer       = errors.New("answer can't be graded by the question mode")
+	ErrSessionExpired      = errors.New("quiz session has expired")
+	ErrSessionCompleted    = errors.New("quiz session is completed")
+	ErrSessionPaused       = errors.New("quiz session is paused")
+	ErrSessionActive       = errors.New("quiz session is not paused")
+	ErrInvalidTimeLimit    = errors.New("time limit must be between 10 seconds and an hour")
+	ErrChallengePause      = errors.New("timed challenges can't be paused")
+	ErrSelfGradedChallenge = errors.New("timed challenges can't use self-graded flashcards")
+	ErrTimeUp              = errors.New("time of the challenge is up")
+	// ErrQuestionMismatch is returned when the answer targets a question other than the current one,
+	// usually because another device has already answered it
+	ErrQuestionMismatch = errors.New("question is not the current one")
+)
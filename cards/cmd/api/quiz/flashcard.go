@@ -0,0 +1,41 @@
+package quiz
+
+import (
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+)
+
+const (
+	ModeFlashcard = "flashcard"
+
+	FlashcardCorrect   = "correct"
+	FlashcardIncorrect = "incorrect"
+)
+
+// FlashcardMode shows the front of a card and lets the user judge whether they knew the back.
+// It can use any card, so the engine falls back to it when none of the selected modes fits a card.
+// The server can't check such answers, so timed challenges don't allow it.
+type FlashcardMode struct{}
+
+// SelfGraded reports whether answers to questions of the mode are judged by the user, not the server
+func SelfGraded(mode string) bool {
+	return mode == ModeFlashcard
+}
+
+func (FlashcardMode) Name() string {
+	return ModeFlashcard
+}
+
+func (FlashcardMode) Build(card models.Card, bc BuildContext) (Question, error) {
+	return Question{Prompt: card.Front, Hint: card.Hint, Expected: card.Back}, nil
+}
+
+func (FlashcardMode) Grade(question models.QuizQuestion, answer string) (Result, error) {
+	switch answer {
+	case FlashcardCorrect:
+		return Result{Correct: true}, nil
+	case FlashcardIncorrect:
+		return Result{Correct: false}, nil
+	default:
+		return Result{}, ErrInvalidAnswer
+	}
+}
@@ -0,0 +1,101 @@
+package quiz
+
+import (
+	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/grading"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"strconv"
+	"strings"
+)
+
+const ModeMatching = "matching"
+
+// MatchingMode shuffles the fronts and backs of up to ChoiceCount cards and asks to pair them.
+// Answers and the expected pairing list the index of the option chosen for every term, e.g. "2,0,1".
+type MatchingMode struct{}
+
+func (MatchingMode) Name() string {
+	return ModeMatching
+}
+
+func (MatchingMode) Build(card models.Card, bc BuildContext) (Question, error) {
+	group := []models.Card{card}
+	fronts := map[string]bool{grading.Normalize(card.Front): true}
+	backs := map[string]bool{grading.Normalize(card.Back): true}
+	for _, other := range bc.Pending {
+		if len(group) == bc.ChoiceCount {
+			break
+		}
+		// identical terms or definitions would make the pairing ambiguous
+		front, back := grading.Normalize(other.Front), grading.Normalize(other.Back)
+		if front == "" || back == "" || fronts[front] || backs[back] {
+			continue
+		}
+		fronts[front], backs[back] = true, true
+		group = append(group, other)
+	}
+	if len(group) < MinChoiceCount {
+		return Question{}, ErrUnsupportedCard
+	}
+
+	bc.Rng.Shuffle(len(group), func(i, j int) { group[i], group[j] = group[j], group[i] })
+	order := bc.Rng.Perm(len(group))
+	question := Question{
+		Group:   make([]string, len(group)),
+		Prompt:  "Match each term with its definition",
+		Terms:   make([]string, len(group)),
+		Options: make([]string, len(group)),
+	}
+	pairing := make([]string, len(group))
+	for i, member := range group {
+		question.Group[i] = member.ID
+		question.Terms[i] = member.Front
+		question.Options[order[i]] = member.Back
+		pairing[i] = strconv.Itoa(order[i])
+	}
+	question.Expected = strings.Join(pairing, ",")
+	return question, nil
+}
+
+func (MatchingMode) Grade(question models.QuizQuestion, answer string) (Result, error) {
+	chosen, err := parsePairing(answer, len(question.Terms))
+	if err != nil {
+		return Result{}, err
+	}
+	expected, err := parsePairing(question.Expected, len(question.Terms))
+	if err != nil {
+		return Result{}, err
+	}
+
+	var missed []string
+	for i := range expected {
+		if chosen[i] != expected[i] {
+			missed = append(missed, question.GroupCardIDs[i])
+		}
+	}
+	matched := len(expected) - len(missed)
+	return Result{
+		Correct:  len(missed) == 0,
+		Feedback: fmt.Sprintf("%d of %d pairs are right", matched, len(expected)),
+		Missed:   missed,
+	}, nil
+}
+
+// parsePairing reads a comma separated list of option indices, one per term, each option used once
+func parsePairing(text string, size int) ([]int, error) {
+	parts := strings.Split(text, ",")
+	if len(parts) != size {
+		return nil, ErrInvalidAnswer
+	}
+	pairing := make([]int, size)
+	used := make([]bool, size)
+	for i, part := range parts {
+		index, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || index < 0 || index >= size || used[index] {
+			return nil, ErrInvalidAnswer
+		}
+		used[index] = true
+		pairing[i] = index
+	}
+	return pairing, nil
+}
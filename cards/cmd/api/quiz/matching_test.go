@@ -0,0 +1,112 @@
+package quiz
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// buildMatching builds a matching question for the first card with the rest pending
+func buildMatching(t *testing.T, cards []models.Card, seed int64) models.QuizQuestion {
+	t.Helper()
+	bc := buildContext(capitals, seed)
+	bc.Pending = cards[1:]
+	question, err := MatchingMode{}.Build(cards[0], bc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return models.QuizQuestion{
+		CardID:       cards[0].ID,
+		GroupCardIDs: question.Group,
+		Mode:         ModeMatching,
+		Terms:        question.Terms,
+		Options:      question.Options,
+		Expected:     question.Expected,
+	}
+}
+
+func TestMatchingBuild(t *testing.T) {
+	byID := make(map[string]models.Card, len(capitals))
+	for _, c := range capitals {
+		byID[c.ID] = c
+	}
+	for seed := int64(0); seed < 20; seed++ {
+		question := buildMatching(t, capitals, seed)
+		if len(question.GroupCardIDs) != DefaultChoiceCount || len(question.Options) != DefaultChoiceCount {
+			t.Fatalf("seed %d grouped %v, want %d cards", seed, question.GroupCardIDs, DefaultChoiceCount)
+		}
+		// the expected pairing points every term at the back of its card
+		for i, index := range strings.Split(question.Expected, ",") {
+			option, _ := strconv.Atoi(index)
+			c := byID[question.GroupCardIDs[i]]
+			if question.Terms[i] != c.Front || question.Options[option] != c.Back {
+				t.Errorf("seed %d pairs %q with %q", seed, question.Terms[i], question.Options[option])
+			}
+		}
+	}
+}
+
+func TestMatchingSkipsDuplicatePairs(t *testing.T) {
+	cards := []models.Card{
+		card("1", "Capital of France", "Paris"),
+		// the same term or definition would make two pairings right
+		card("2", "capital of FRANCE!", "Lutetia"),
+		card("3", "City of light", "paris"),
+		card("4", "Capital of Italy", "Rome"),
+		card("5", "  ", "Nowhere"),
+		card("6", "Capital of Spain", "Madrid"),
+	}
+	question := buildMatching(t, cards, 1)
+	grouped := slices.Clone(question.GroupCardIDs)
+	slices.Sort(grouped)
+	if got := fmt.Sprint(grouped); got != "[1 4 6]" {
+		t.Errorf("grouped %s, want the cards with distinct terms and definitions", got)
+	}
+
+	// a card without a partner can't be matched
+	bc := buildContext(capitals, 1)
+	bc.Pending = []models.Card{card("2", "Capital of France", "Lutetia")}
+	if _, err := (MatchingMode{}).Build(cards[0], bc); !errors.Is(err, ErrUnsupportedCard) {
+		t.Errorf("Build without partners returned %v, want ErrUnsupportedCard", err)
+	}
+}
+
+func TestMatchingGrade(t *testing.T) {
+	question := models.QuizQuestion{
+		GroupCardIDs: []string{"a", "b", "c", "d"},
+		Terms:        []string{"ta", "tb", "tc", "td"},
+		Options:      []string{"oc", "oa", "od", "ob"},
+		Expected:     "1,3,0,2",
+	}
+	tests := []struct {
+		answer   string
+		correct  bool
+		missed   []string
+		feedback string
+	}{
+		{"1,3,0,2", true, nil, "4 of 4 pairs are right"},
+		{" 1, 3 ,0,2 ", true, nil, "4 of 4 pairs are right"},
+		{"3,1,0,2", false, []string{"a", "b"}, "2 of 4 pairs are right"},
+		{"0,1,2,3", false, []string{"a", "b", "c", "d"}, "0 of 4 pairs are right"},
+	}
+	for _, tt := range tests {
+		result, err := MatchingMode{}.Grade(question, tt.answer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Correct != tt.correct || fmt.Sprint(result.Missed) != fmt.Sprint(tt.missed) || result.Feedback != tt.feedback {
+			t.Errorf("Grade(%q) = %+v, want correct %v, missed %v, %q", tt.answer, result, tt.correct, tt.missed, tt.feedback)
+		}
+	}
+
+	// every term takes exactly one option
+	for _, answer := range []string{"", "1,3,0", "1,3,0,2,4", "1,1,0,2", "1,3,0,4", "1,3,0,-1", "a,b,c,d"} {
+		if _, err := (MatchingMode{}).Grade(question, answer); !errors.Is(err, ErrInvalidAnswer) {
+			t.Errorf("Grade(%q) returned %v, want ErrInvalidAnswer", answer, err)
+		}
+	}
+}
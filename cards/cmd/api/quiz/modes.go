@@ -0,0 +1,87 @@
+package quiz
+
+import (
+	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/grading"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"math/rand"
+)
+
+// Question is what a mode builds out of a card
+type Question struct {
+	// Group lists all cards covered by a question built from several cards, in the order of Terms
+	Group   []string
+	Prompt  string
+	Hint    string
+	Terms   []string
+	Options []string
+	// Statement is the answer proposed by true/false questions
+	Statement string
+	Expected  string
+	Accepted  []string
+	// AnswerType and the tolerances tell how typed answers are graded
+	AnswerType        string
+	AbsoluteTolerance float64
+	RelativeTolerance float64
+}
+
+// BuildContext is what modes may use besides the card itself
+type BuildContext struct {
+	// Deck holds all cards of the deck
+	Deck []models.Card
+	// Rng is seeded from the session, modes must take all randomness from it
+	Rng *rand.Rand
+	// ChoiceCount is the number of options for modes offering a choice, or of pairs for matching
+	ChoiceCount int
+	// Pending are the cards selected for the session that don't have a question yet.
+	// A mode may cover some of them with the question, listing them in Question.Group.
+	Pending []models.Card
+}
+
+type Result struct {
+	Correct bool
+	// Verdict refines Correct for modes grading typed answers, see the grading package
+	Verdict string
+	// Feedback is an optional explanation shown next to the result
+	Feedback string
+	// Diff shows typos in typed answers
+	Diff []grading.DiffSegment
+	// Missed are the cards of a group answered wrong
+	Missed []string
+	// Points and Combo are set by the engine for challenges
+	Points int
+	Combo  int
+}
+
+// Mode turns cards into questions and grades answers to them
+type Mode interface {
+	Name() string
+	// Build prepares a question for the card, it returns ErrUnsupportedCard when the mode can't use the card
+	Build(card models.Card, bc BuildContext) (Question, error)
+	// Grade checks the answer, it returns ErrInvalidAnswer when the answer can't be graded at all
+	Grade(question models.QuizQuestion, answer string) (Result, error)
+}
+
+// Modes is the registry of modes a session can use
+type Modes map[string]Mode
+
+func NewModes(modes ...Mode) Modes {
+	registry := make(Modes, len(modes))
+	for _, mode := range modes {
+		registry[mode.Name()] = mode
+	}
+	return registry
+}
+
+// DefaultModes returns all modes shipped with the service
+func DefaultModes() Modes {
+	return NewModes(FlashcardMode{}, MultipleChoiceMode{}, TypedMode{}, MatchingMode{}, TrueFalseMode{})
+}
+
+func (m Modes) Get(name string) (Mode, error) {
+	mode, ok := m[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownMode, name)
+	}
+	return mode, nil
+}
@@ -0,0 +1,100 @@
+package quiz
+
+import (
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/grading"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const ModeMultipleChoice = "multiple-choice"
+
+// MultipleChoiceMode asks to pick the back of a card among the backs of other cards of the deck
+type MultipleChoiceMode struct{}
+
+func (MultipleChoiceMode) Name() string {
+	return ModeMultipleChoice
+}
+
+func (MultipleChoiceMode) Build(card models.Card, bc BuildContext) (Question, error) {
+	distractors := pickDistractors(card, bc)
+	if len(distractors) == 0 {
+		// a deck without any usable distractor can't make a choice question
+		return Question{}, ErrUnsupportedCard
+	}
+
+	options := append(distractors, card.Back)
+	bc.Rng.Shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
+	return Question{Prompt: card.Front, Hint: card.Hint, Options: options, Expected: card.Back}, nil
+}
+
+func (MultipleChoiceMode) Grade(question models.QuizQuestion, answer string) (Result, error) {
+	for _, option := range question.Options {
+		if option == answer {
+			return Result{Correct: answer == question.Expected}, nil
+		}
+	}
+	return Result{}, ErrInvalidAnswer
+}
+
+type distractor struct {
+	text  string
+	score float64
+}
+
+// pickDistractors returns up to ChoiceCount-1 answers of other cards, the most similar to the correct one first.
+// Smaller decks simply produce fewer options.
+func pickDistractors(card models.Card, bc BuildContext) []string {
+	correct := grading.Normalize(card.Back)
+	seen := map[string]bool{correct: true}
+
+	candidates := make([]distractor, 0, len(bc.Deck))
+	for _, other := range bc.Deck {
+		if other.ID == card.ID {
+			continue
+		}
+		text := strings.TrimSpace(other.Back)
+		normalized := grading.Normalize(text)
+		if normalized == "" || seen[normalized] || disguises(correct, normalized) {
+			continue
+		}
+		seen[normalized] = true
+		candidates = append(candidates, distractor{text: text, score: similarity(card.Back, text)})
+	}
+
+	// shuffle before the stable sort, so equally similar candidates are picked at random
+	bc.Rng.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+
+	count := min(bc.ChoiceCount-1, len(candidates))
+	distractors := make([]string, 0, count)
+	for _, candidate := range candidates[:count] {
+		distractors = append(distractors, candidate.text)
+	}
+	return distractors
+}
+
+// disguises reports whether the candidate is the correct answer in other words,
+// e.g. "Paris" and "Paris, France"; both must be normalized
+func disguises(correct, candidate string) bool {
+	return containsWords(candidate, correct) || containsWords(correct, candidate)
+}
+
+// similarity scores how alike two answers look, from 0 to 1, so distractors can't be ruled out at a glance
+func similarity(correct, candidate string) float64 {
+	score := 0.0
+	if kindOf(correct) == kindOf(candidate) {
+		score += 0.5
+	}
+
+	correctLen := float64(utf8.RuneCountInString(correct))
+	candidateLen := float64(utf8.RuneCountInString(candidate))
+	score += 0.3 * (1 - math.Abs(correctLen-candidateLen)/math.Max(correctLen, candidateLen))
+
+	correctWords := float64(len(strings.Fields(correct)))
+	candidateWords := float64(len(strings.Fields(candidate)))
+	score += 0.2 * (1 - math.Abs(correctWords-candidateWords)/math.Max(correctWords, candidateWords))
+	return score
+}
@@ -0,0 +1,180 @@
+package quiz
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+func card(id, front, back string) models.Card {
+	return models.Card{ID: id, DeckID: "deck", Front: front, Back: back}
+}
+
+func buildContext(deck []models.Card, seed int64) BuildContext {
+	return BuildContext{Deck: deck, Rng: rand.New(rand.NewSource(seed)), ChoiceCount: DefaultChoiceCount}
+}
+
+var capitals = []models.Card{
+	card("1", "Capital of France", "Paris"),
+	card("2", "Capital of Germany", "Berlin"),
+	card("3", "Capital of Italy", "Rome"),
+	card("4", "Capital of Spain", "Madrid"),
+	card("5", "Capital of Japan", "Tokyo"),
+	card("6", "Capital of the USA", "Washington, D.C."),
+	card("7", "Year of the French revolution", "1789"),
+	card("8", "Capital of Portugal", "Lisbon"),
+}
+
+func TestMultipleChoiceBuild(t *testing.T) {
+	question, err := MultipleChoiceMode{}.Build(capitals[0], buildContext(capitals, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if question.Prompt != "Capital of France" || question.Expected != "Paris" {
+		t.Errorf("question %q expecting %q", question.Prompt, question.Expected)
+	}
+	if len(question.Options) != DefaultChoiceCount {
+		t.Fatalf("%d options %v, want %d", len(question.Options), question.Options, DefaultChoiceCount)
+	}
+	seen := make(map[string]bool)
+	for _, option := range question.Options {
+		if seen[option] {
+			t.Errorf("option %q offered twice in %v", option, question.Options)
+		}
+		seen[option] = true
+	}
+	if !seen["Paris"] {
+		t.Errorf("options %v lack the answer", question.Options)
+	}
+	// single words look most like the answer, the phrase and the year are ruled out at a glance
+	for _, unlikely := range []string{"Washington, D.C.", "1789"} {
+		if seen[unlikely] {
+			t.Errorf("options %v include the dissimilar %q", question.Options, unlikely)
+		}
+	}
+}
+
+func TestMultipleChoiceIsSeeded(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		first, err := MultipleChoiceMode{}.Build(capitals[1], buildContext(capitals, seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := MultipleChoiceMode{}.Build(capitals[1], buildContext(capitals, seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(first.Options) != fmt.Sprint(second.Options) {
+			t.Fatalf("seed %d built %v and then %v", seed, first.Options, second.Options)
+		}
+	}
+}
+
+func TestMultipleChoiceRanksDistractors(t *testing.T) {
+	// Tokyo is as long as Paris and always offered, Berlin, Madrid and Lisbon tie for the two other slots,
+	// Rome is one letter short and never makes it
+	offered := make(map[string]int)
+	for seed := int64(0); seed < 50; seed++ {
+		question, err := MultipleChoiceMode{}.Build(capitals[0], buildContext(capitals, seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, option := range question.Options {
+			offered[option]++
+		}
+	}
+	if offered["Tokyo"] != 50 || offered["Rome"] != 0 {
+		t.Errorf("Tokyo offered %d and Rome %d times in 50 questions, want 50 and 0", offered["Tokyo"], offered["Rome"])
+	}
+	for _, tied := range []string{"Berlin", "Madrid", "Lisbon"} {
+		if offered[tied] == 0 || offered[tied] == 50 {
+			t.Errorf("%q offered %d times in 50 questions, ties must be broken at random", tied, offered[tied])
+		}
+	}
+}
+
+func TestMultipleChoiceFiltersDistractors(t *testing.T) {
+	deck := []models.Card{
+		card("1", "Capital of France", "Paris"),
+		// the same answer, differently written
+		card("2", "City of light", " paris "),
+		card("3", "Largest French city", "PARIS!"),
+		// the answer in other words
+		card("4", "Seat of the French government", "Paris, France"),
+		// duplicates of each other
+		card("5", "Capital of Germany", "Berlin"),
+		card("6", "Largest German city", "berlin"),
+		// blank backs can't be options
+		card("7", "Unanswered", "   "),
+		card("8", "Capital of Italy", "Rome"),
+	}
+	for seed := int64(0); seed < 20; seed++ {
+		question, err := MultipleChoiceMode{}.Build(deck[0], buildContext(deck, seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := append([]string(nil), question.Options...)
+		sort.Strings(got)
+		if fmt.Sprint(got) != fmt.Sprint([]string{"Berlin", "Paris", "Rome"}) &&
+			fmt.Sprint(got) != fmt.Sprint([]string{"Paris", "Rome", "berlin"}) {
+			t.Fatalf("seed %d offered %q, want Paris with one Berlin and Rome", seed, question.Options)
+		}
+	}
+}
+
+func TestMultipleChoiceSmallDeck(t *testing.T) {
+	// fewer cards than options make fewer options
+	deck := capitals[:2]
+	question, err := MultipleChoiceMode{}.Build(deck[0], buildContext(deck, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(question.Options) != 2 {
+		t.Errorf("options %v, want the answer and the only other card", question.Options)
+	}
+
+	// without any usable distractor the card can't be asked as a choice
+	deck = []models.Card{card("1", "Capital of France", "Paris"), card("2", "City of light", "paris")}
+	_, err = MultipleChoiceMode{}.Build(deck[0], buildContext(deck, 1))
+	if !errors.Is(err, ErrUnsupportedCard) {
+		t.Errorf("Build without distractors returned %v, want ErrUnsupportedCard", err)
+	}
+}
+
+func TestMultipleChoiceSessionFallsBackToFlashcards(t *testing.T) {
+	engine := NewEngine(DefaultModes(), time.Hour, time.Second)
+	session := &models.QuizSession{ID: "session", UserID: "user", DeckID: "deck", Seed: 7}
+	deck := []models.Card{card("1", "Capital of France", "Paris")}
+	err := engine.Start(session, deck, Options{Order: OrderSequential, Modes: []string{ModeMultipleChoice}}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := session.Questions[0].Mode; mode != ModeFlashcard {
+		t.Errorf("single card deck asked as %q, want %q", mode, ModeFlashcard)
+	}
+}
+
+func TestMultipleChoiceGrade(t *testing.T) {
+	question := models.QuizQuestion{Options: []string{"Rome", "Paris", "Berlin"}, Expected: "Paris"}
+	tests := []struct {
+		answer  string
+		correct bool
+		err     error
+	}{
+		{"Paris", true, nil},
+		{"Rome", false, nil},
+		// only the offered options can be picked, not text resembling them
+		{"paris", false, ErrInvalidAnswer},
+		{"Madrid", false, ErrInvalidAnswer},
+	}
+	for _, tt := range tests {
+		result, err := MultipleChoiceMode{}.Grade(question, tt.answer)
+		if !errors.Is(err, tt.err) || err == nil && result.Correct != tt.correct {
+			t.Errorf("Grade(%q) = %+v, %v, want correct %v and error %v", tt.answer, result, err, tt.correct, tt.err)
+		}
+	}
+}
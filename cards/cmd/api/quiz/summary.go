@@ -0,0 +1,58 @@
+package quiz
+
+import (
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"time"
+)
+
+type Summary struct {
+	Total      int
+	Answered   int
+	Correct    int
+	Incorrect  int
+	Skipped    int
+	Unanswered int
+	// Accuracy is the share of correct answers among answered questions
+	Accuracy       float64
+	ActiveDuration time.Duration
+	// Score and MaxCombo are set for challenges
+	Score    int
+	MaxCombo int
+}
+
+func Summarize(session *models.QuizSession) Summary {
+	questions := AskedQuestions(session)
+	summary := Summary{Total: len(questions), ActiveDuration: session.ActiveDuration}
+	for _, question := range questions {
+		switch question.Status {
+		case models.QuestionStatusAnswered:
+			summary.Answered++
+			if question.Correct {
+				summary.Correct++
+			} else {
+				summary.Incorrect++
+			}
+		case models.QuestionStatusSkipped:
+			summary.Skipped++
+		default:
+			summary.Unanswered++
+		}
+	}
+	if session.Challenge != nil {
+		summary.Score = session.Challenge.Score
+		summary.MaxCombo = session.Challenge.MaxCombo
+	}
+	if summary.Answered > 0 {
+		summary.Accuracy = float64(summary.Correct) / float64(summary.Answered)
+	}
+	return summary
+}
+
+// AskedQuestions returns the questions of the session, leaving out the ones a challenge
+// prepared but never got to
+func AskedQuestions(session *models.QuizSession) []models.QuizQuestion {
+	if session.Challenge == nil {
+		return session.Questions
+	}
+	return session.Questions[:min(session.Current+1, len(session.Questions))]
+}
@@ -0,0 +1,34 @@
+package quiz
+
+import (
+	"strconv"
+	"strings"
+)
+
+// containsWords reports whether the words of needle appear in a row inside haystack,
+// both must be normalized
+func containsWords(haystack, needle string) bool {
+	if needle == "" {
+		return false
+	}
+	return strings.Contains(" "+haystack+" ", " "+needle+" ")
+}
+
+type answerKind int
+
+const (
+	kindWord answerKind = iota
+	kindPhrase
+	kindNumber
+)
+
+func kindOf(text string) answerKind {
+	trimmed := strings.TrimSpace(text)
+	if _, err := strconv.ParseFloat(strings.ReplaceAll(trimmed, ",", ""), 64); err == nil {
+		return kindNumber
+	}
+	if len(strings.Fields(trimmed)) > 1 {
+		return kindPhrase
+	}
+	return kindWord
+}
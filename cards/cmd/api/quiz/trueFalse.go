@@ -0,0 +1,53 @@
+package quiz
+
+import (
+	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+)
+
+const (
+	ModeTrueFalse = "true-false"
+
+	AnswerTrue  = "true"
+	AnswerFalse = "false"
+)
+
+// TrueFalseMode pairs the front of a card with either its own back or the back of another card
+// and asks whether the pair is right. The expected answer revealed afterwards is the card's back.
+type TrueFalseMode struct{}
+
+func (TrueFalseMode) Name() string {
+	return ModeTrueFalse
+}
+
+func (TrueFalseMode) Build(card models.Card, bc BuildContext) (Question, error) {
+	statement := card.Back
+	if bc.Rng.Intn(2) == 1 {
+		// a single, most similar distractor makes a false statement hard to spot
+		distractors := pickDistractors(card, BuildContext{Deck: bc.Deck, Rng: bc.Rng, ChoiceCount: 2})
+		if len(distractors) > 0 {
+			statement = distractors[0]
+		}
+	}
+	return Question{
+		Prompt:    card.Front,
+		Hint:      card.Hint,
+		Options:   []string{AnswerTrue, AnswerFalse},
+		Statement: statement,
+		Expected:  card.Back,
+	}, nil
+}
+
+func (TrueFalseMode) Grade(question models.QuizQuestion, answer string) (Result, error) {
+	if answer != AnswerTrue && answer != AnswerFalse {
+		return Result{}, ErrInvalidAnswer
+	}
+	truthful := question.Statement == question.Expected
+	if (answer == AnswerTrue) == truthful {
+		return Result{Correct: true}, nil
+	}
+	if truthful {
+		return Result{Correct: false, Feedback: "the statement is true"}, nil
+	}
+	return Result{Correct: false, Feedback: fmt.Sprintf("the statement is false, the answer is %q", question.Expected)}, nil
+}
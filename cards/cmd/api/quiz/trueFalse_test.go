@@ -0,0 +1,70 @@
+package quiz
+
+import (
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"testing"
+)
+
+func TestTrueFalseBuild(t *testing.T) {
+	statements := make(map[string]int)
+	for seed := int64(0); seed < 50; seed++ {
+		question, err := TrueFalseMode{}.Build(capitals[0], buildContext(capitals, seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if question.Prompt != "Capital of France" || question.Expected != "Paris" ||
+			len(question.Options) != 2 || question.Options[0] != AnswerTrue || question.Options[1] != AnswerFalse {
+			t.Fatalf("seed %d built %+v", seed, question)
+		}
+		statements[question.Statement]++
+	}
+	// false statements pair the card with the most similar answer of another card
+	if statements["Paris"] == 0 || statements["Tokyo"] == 0 || statements["Paris"]+statements["Tokyo"] != 50 {
+		t.Errorf("statements %v, want Paris and its closest distractor Tokyo", statements)
+	}
+
+	// without another answer in the deck the statement is always true
+	deck := []models.Card{card("1", "Capital of France", "Paris"), card("2", "City of light", "paris")}
+	for seed := int64(0); seed < 10; seed++ {
+		question, err := TrueFalseMode{}.Build(deck[0], buildContext(deck, seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if question.Statement != "Paris" {
+			t.Errorf("seed %d stated %q without any distractor", seed, question.Statement)
+		}
+	}
+}
+
+func TestTrueFalseGrade(t *testing.T) {
+	truthful := models.QuizQuestion{Statement: "Paris", Expected: "Paris"}
+	untruthful := models.QuizQuestion{Statement: "Tokyo", Expected: "Paris"}
+	tests := []struct {
+		name     string
+		question models.QuizQuestion
+		answer   string
+		correct  bool
+		feedback string
+	}{
+		{"true statement confirmed", truthful, AnswerTrue, true, ""},
+		{"true statement denied", truthful, AnswerFalse, false, "the statement is true"},
+		{"false statement denied", untruthful, AnswerFalse, true, ""},
+		{"false statement confirmed", untruthful, AnswerTrue, false, `the statement is false, the answer is "Paris"`},
+	}
+	for _, tt := range tests {
+		result, err := TrueFalseMode{}.Grade(tt.question, tt.answer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Correct != tt.correct || result.Feedback != tt.feedback {
+			t.Errorf("%s: got %+v, want correct %v and %q", tt.name, result, tt.correct, tt.feedback)
+		}
+	}
+
+	for _, answer := range []string{"", "yes", "True"} {
+		if _, err := (TrueFalseMode{}).Grade(truthful, answer); !errors.Is(err, ErrInvalidAnswer) {
+			t.Errorf("Grade(%q) returned %v, want ErrInvalidAnswer", answer, err)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package quiz
+
+import (
+	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/grading"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+)
+
+const ModeTyped = "typed"
+
+// TypedMode asks to type the back of a card, typos within the grading tolerance are forgiven
+type TypedMode struct{}
+
+func (TypedMode) Name() string {
+	return ModeTyped
+}
+
+func (TypedMode) Build(card models.Card, bc BuildContext) (Question, error) {
+	return Question{
+		Prompt:            card.Front,
+		Hint:              card.Hint,
+		Expected:          card.Back,
+		Accepted:          card.Alternatives,
+		AnswerType:        card.AnswerType,
+		AbsoluteTolerance: card.AbsoluteTolerance,
+		RelativeTolerance: card.RelativeTolerance,
+	}, nil
+}
+
+func (TypedMode) Grade(question models.QuizQuestion, answer string) (Result, error) {
+	grader := grading.New(question.AnswerType, grading.Options{
+		AbsoluteTolerance: question.AbsoluteTolerance,
+		RelativeTolerance: question.RelativeTolerance,
+	})
+	graded := grader.Grade(answer, append([]string{question.Expected}, question.Accepted...))
+	result := Result{Correct: graded.Correct(), Verdict: graded.Verdict, Feedback: graded.Reason, Diff: graded.Diff}
+	if graded.Verdict == grading.VerdictAlmost && graded.Reason == "" {
+		result.Feedback = fmt.Sprintf("almost, mind the spelling of %q", graded.Matched)
+	}
+	return result, nil
+}
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"slices"
+	"strings"
+)
+
+type AchievementRepository interface {
+	// Add adds the amount to a counter of the user once per source, e.g. a review id, so redelivered events count once
+	Add(ctx context.Context, userID, counter, sourceID string, amount int) error
+	// Raise sets a counter of the user to the value unless it's already larger
+	Raise(ctx context.Context, userID, counter string, value int) error
+	Counters(ctx context.Context, userID string) (map[string]int, error)
+	// ListUsers returns the ids of users with counters after afterID in id order, to visit all of them in batches
+	ListUsers(ctx context.Context, afterID string, limit int) ([]string, error)
+
+	// Award stores the badge unless the user already has it and reports whether it was stored
+	Award(ctx context.Context, badge models.Badge) (bool, error)
+	// Badges returns the badges of the user, oldest first
+	Badges(ctx context.Context, userID string) ([]models.Badge, error)
+}
+
+type counterKey struct {
+	userID  string
+	counter string
+}
+
+type counterSourceKey struct {
+	counterKey
+	sourceID string
+}
+
+type badgeKey struct {
+	userID        string
+	achievementID string
+}
+
+type memoryAchievementRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryAchievementRepository(store *MemoryStore) AchievementRepository {
+	return &memoryAchievementRepository{store: store}
+}
+
+func (r *memoryAchievementRepository) Add(ctx context.Context, userID, counter, sourceID string, amount int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	key := counterKey{userID: userID, counter: counter}
+	source := counterSourceKey{counterKey: key, sourceID: sourceID}
+	if _, ok := r.store.counterSources[source]; ok {
+		return nil
+	}
+	r.store.counterSources[source] = struct{}{}
+	r.store.counters[key] += amount
+	return nil
+}
+
+func (r *memoryAchievementRepository) Raise(ctx context.Context, userID, counter string, value int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	key := counterKey{userID: userID, counter: counter}
+	r.store.counters[key] = max(r.store.counters[key], value)
+	return nil
+}
+
+func (r *memoryAchievementRepository) Counters(ctx context.Context, userID string) (map[string]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	counters := make(map[string]int)
+	for key, value := range r.store.counters {
+		if key.userID == userID {
+			counters[key.counter] = value
+		}
+	}
+	return counters, nil
+}
+
+func (r *memoryAchievementRepository) ListUsers(ctx context.Context, afterID string, limit int) ([]string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var users []string
+	for key := range r.store.counters {
+		if key.userID > afterID && !slices.Contains(users, key.userID) {
+			users = append(users, key.userID)
+		}
+	}
+	slices.SortFunc(users, strings.Compare)
+	if len(users) > limit {
+		users = users[:limit]
+	}
+	return users, nil
+}
+
+func (r *memoryAchievementRepository) Award(ctx context.Context, badge models.Badge) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	key := badgeKey{userID: badge.UserID, achievementID: badge.AchievementID}
+	if _, ok := r.store.badges[key]; ok {
+		return false, nil
+	}
+	r.store.badges[key] = badge
+	return true, nil
+}
+
+func (r *memoryAchievementRepository) Badges(ctx context.Context, userID string) ([]models.Badge, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	badges := []models.Badge{}
+	for key, badge := range r.store.badges {
+		if key.userID == userID {
+			badges = append(badges, badge)
+		}
+	}
+	slices.SortFunc(badges, func(a, b models.Badge) int {
+		if c := a.AwardedAt.Compare(b.AwardedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.AchievementID, b.AchievementID)
+	})
+	return badges, nil
+}
@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Due statuses of a CardFilter
+const (
+	// DueAny matches every card
+	DueAny = ""
+	// DueNew matches cards the user has never studied
+	DueNew = "new"
+	// DueDue matches studied cards due at the moment of the filter
+	DueDue = "due"
+	// DueReview matches studied cards, due or not
+	DueReview = "review"
+	// DueStudy matches the cards to study now, new or due
+	DueStudy = "study"
+)
+
+// Review state fields a CardFilter compares
+const (
+	FieldLapses     = "lapses"
+	FieldDifficulty = "difficulty"
+)
+
+// TagSeparator nests tags, e.g. "languages::spanish"
+const TagSeparator = "::"
+
+// Comparison operators
+const (
+	OpEqual          = "="
+	OpGreater        = ">"
+	OpGreaterOrEqual = ">="
+	OpLess           = "<"
+	OpLessOrEqual    = "<="
+)
+
+// Comparison matches studied cards whose review state field compares to the value
+type Comparison struct {
+	Field string
+	Op    string
+	Value float64
+}
+
+// CardFilter selects cards across the decks of an owner, it's the compiled form of a filtered deck query
+type CardFilter struct {
+	OwnerID string
+	// UserID picks the review states the due status and the comparisons read
+	UserID string
+	// RestrictDecks limits the cards to DeckIDs, an empty list then matches nothing
+	RestrictDecks  bool
+	DeckIDs        []string
+	ExcludeDeckIDs []string
+	// Tags must all be on the card or its deck, ExcludeTags on neither. They are lower-case,
+	// a tag also matches the tags nested in it with "::", e.g. "verbs" matches "verbs::irregular".
+	Tags        []string
+	ExcludeTags []string
+	Due         string
+	// Now tells due cards apart
+	Now         time.Time
+	Comparisons []Comparison
+	// Limit bounds the result, 0 returns every match
+	Limit int
+}
+
+// FilteredCard is a card matching a CardFilter with the user's review state, State is empty for new cards
+type FilteredCard struct {
+	Card    models.Card
+	State   models.ReviewState
+	Studied bool
+}
+
+// matches evaluates the filter in memory, the postgres repository compiles the same conditions to SQL
+func (f CardFilter) matches(deck models.Deck, card models.Card, state models.ReviewState, studied bool) bool {
+	if deck.OwnerID != f.OwnerID {
+		return false
+	}
+	if f.RestrictDecks && !slices.Contains(f.DeckIDs, card.DeckID) {
+		return false
+	}
+	if slices.Contains(f.ExcludeDeckIDs, card.DeckID) {
+		return false
+	}
+	tags := append(append([]string{}, card.Tags...), deck.Tags...)
+	for _, tag := range f.Tags {
+		if !hasTag(tags, tag) {
+			return false
+		}
+	}
+	for _, tag := range f.ExcludeTags {
+		if hasTag(tags, tag) {
+			return false
+		}
+	}
+
+	due := studied && !state.Due.After(f.Now)
+	switch f.Due {
+	case DueNew:
+		if studied {
+			return false
+		}
+	case DueDue:
+		if !due {
+			return false
+		}
+	case DueReview:
+		if !studied {
+			return false
+		}
+	case DueStudy:
+		if studied && !due {
+			return false
+		}
+	}
+
+	for _, comparison := range f.Comparisons {
+		if !studied {
+			return false
+		}
+		var value float64
+		switch comparison.Field {
+		case FieldLapses:
+			value = float64(state.Lapses)
+		case FieldDifficulty:
+			value = state.Difficulty
+		}
+		if !compare(value, comparison.Op, comparison.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasTag reports whether a tag or one nested in it is among the tags, ignoring case
+func hasTag(tags []string, tag string) bool {
+	for _, candidate := range tags {
+		candidate = strings.ToLower(candidate)
+		if candidate == tag || strings.HasPrefix(candidate, tag+TagSeparator) {
+			return true
+		}
+	}
+	return false
+}
+
+func compare(value float64, op string, target float64) bool {
+	switch op {
+	case OpEqual:
+		return value == target
+	case OpGreater:
+		return value > target
+	case OpGreaterOrEqual:
+		return value >= target
+	case OpLess:
+		return value < target
+	case OpLessOrEqual:
+		return value <= target
+	default:
+		return false
+	}
+}
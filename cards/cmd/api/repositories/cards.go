@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"sort"
+)
+
+type CardRepository interface {
+	Create(ctx context.Context, card models.Card) error
+	Get(ctx context.Context, id string) (models.Card, error)
+	// ListByDeck returns cards of the deck in the order they were created
+	ListByDeck(ctx context.Context, deckID string) ([]models.Card, error)
+	// Filter returns the cards matching the filter, studied ones first by due date, then new ones in the order they were created
+	Filter(ctx context.Context, filter CardFilter) ([]FilteredCard, error)
+	Update(ctx context.Context, card models.Card) error
+	Delete(ctx context.Context, id string) error
+}
+
+type memoryCardRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryCardRepository creates a CardRepository on top of the in-memory store
+func NewMemoryCardRepository(store *MemoryStore) CardRepository {
+	return &memoryCardRepository{store: store}
+}
+
+func (r *memoryCardRepository) Create(ctx context.Context, card models.Card) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.decks[card.DeckID]; !ok {
+		return ErrNotFound
+	}
+	if _, ok := r.store.cards[card.ID]; ok {
+		return ErrAlreadyExists
+	}
+	r.store.cards[card.ID] = card
+	return nil
+}
+
+func (r *memoryCardRepository) Get(ctx context.Context, id string) (models.Card, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	card, ok := r.store.cards[id]
+	if !ok {
+		return models.Card{}, ErrNotFound
+	}
+	return card, nil
+}
+
+func (r *memoryCardRepository) ListByDeck(ctx context.Context, deckID string) ([]models.Card, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	cards := []models.Card{}
+	for _, card := range r.store.cards {
+		if card.DeckID == deckID {
+			cards = append(cards, card)
+		}
+	}
+	sort.Slice(cards, func(i, j int) bool {
+		if cards[i].CreatedAt.Equal(cards[j].CreatedAt) {
+			return cards[i].ID < cards[j].ID
+		}
+		return cards[i].CreatedAt.Before(cards[j].CreatedAt)
+	})
+	return cards, nil
+}
+
+func (r *memoryCardRepository) Filter(ctx context.Context, filter CardFilter) ([]FilteredCard, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	matches := []FilteredCard{}
+	for _, card := range r.store.cards {
+		state, studied := r.store.reviewStates[reviewKey{userID: filter.UserID, cardID: card.ID}]
+		if filter.matches(r.store.decks[card.DeckID], card, state, studied) {
+			matches = append(matches, FilteredCard{Card: card, State: state, Studied: studied})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Studied != b.Studied {
+			return a.Studied
+		}
+		if a.Studied && !a.State.Due.Equal(b.State.Due) {
+			return a.State.Due.Before(b.State.Due)
+		}
+		if !a.Card.CreatedAt.Equal(b.Card.CreatedAt) {
+			return a.Card.CreatedAt.Before(b.Card.CreatedAt)
+		}
+		return a.Card.ID < b.Card.ID
+	})
+	return paginate(matches, filter.Limit, 0), nil
+}
+
+func (r *memoryCardRepository) Update(ctx context.Context, card models.Card) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.cards[card.ID]; !ok {
+		return ErrNotFound
+	}
+	r.store.cards[card.ID] = card
+	return nil
+}
+
+func (r *memoryCardRepository) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.cards[id]; !ok {
+		return ErrNotFound
+	}
+	r.store.deleteCard(id)
+	return nil
+}
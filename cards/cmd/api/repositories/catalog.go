@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"sort"
+	"strings"
+)
+
+// Orders of the catalog
+const (
+	// CatalogSortPopular lists the most forked decks first
+	CatalogSortPopular = "popular"
+	// CatalogSortRecent lists the latest published decks first
+	CatalogSortRecent = "recent"
+	// CatalogSortTitle lists the decks by title
+	CatalogSortTitle = "title"
+)
+
+// CatalogQuery selects published decks, empty fields match every deck
+type CatalogQuery struct {
+	Category string
+	// Language matches the tag and the tags of its regions, e.g. "pt" matches "pt-br"
+	Language string
+	Sort     string
+	Limit    int
+	Offset   int
+}
+
+type CatalogRepository interface {
+	// Publish adds the deck to the catalog or changes its category and language, the fork count is kept
+	Publish(ctx context.Context, entry models.CatalogEntry) error
+	Get(ctx context.Context, deckID string) (models.CatalogEntry, error)
+	Unpublish(ctx context.Context, deckID string) error
+	// Browse returns a page of the published decks matching the query with their total count
+	Browse(ctx context.Context, query CatalogQuery) ([]models.CatalogDeck, int, error)
+	// AddFork counts a fork of the published deck
+	AddFork(ctx context.Context, deckID string) error
+}
+
+type memoryCatalogRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryCatalogRepository(store *MemoryStore) CatalogRepository {
+	return &memoryCatalogRepository{store: store}
+}
+
+func (r *memoryCatalogRepository) Publish(ctx context.Context, entry models.CatalogEntry) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.decks[entry.DeckID]; !ok {
+		return ErrNotFound
+	}
+	if stored, ok := r.store.catalog[entry.DeckID]; ok {
+		entry.Forks = stored.Forks
+		entry.PublishedAt = stored.PublishedAt
+	}
+	r.store.catalog[entry.DeckID] = entry
+	return nil
+}
+
+func (r *memoryCatalogRepository) Get(ctx context.Context, deckID string) (models.CatalogEntry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	entry, ok := r.store.catalog[deckID]
+	if !ok {
+		return models.CatalogEntry{}, ErrNotFound
+	}
+	return entry, nil
+}
+
+func (r *memoryCatalogRepository) Unpublish(ctx context.Context, deckID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.catalog[deckID]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.catalog, deckID)
+	return nil
+}
+
+func (r *memoryCatalogRepository) Browse(ctx context.Context, query CatalogQuery) ([]models.CatalogDeck, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	cardCounts := make(map[string]int)
+	for _, card := range r.store.cards {
+		cardCounts[card.DeckID]++
+	}
+	decks := []models.CatalogDeck{}
+	for _, entry := range r.store.catalog {
+		if query.Category != "" && entry.Category != query.Category {
+			continue
+		}
+		if query.Language != "" && entry.Language != query.Language && !strings.HasPrefix(entry.Language, query.Language+"-") {
+			continue
+		}
+		decks = append(decks, models.CatalogDeck{
+			Entry:     entry,
+			Deck:      r.store.decks[entry.DeckID],
+			CardCount: cardCounts[entry.DeckID],
+		})
+	}
+	sort.Slice(decks, func(i, j int) bool {
+		a, b := decks[i], decks[j]
+		switch query.Sort {
+		case CatalogSortTitle:
+			if a.Deck.Title != b.Deck.Title {
+				return a.Deck.Title < b.Deck.Title
+			}
+		case CatalogSortPopular:
+			if a.Entry.Forks != b.Entry.Forks {
+				return a.Entry.Forks > b.Entry.Forks
+			}
+		}
+		if !a.Entry.PublishedAt.Equal(b.Entry.PublishedAt) {
+			return a.Entry.PublishedAt.After(b.Entry.PublishedAt)
+		}
+		return a.Entry.DeckID < b.Entry.DeckID
+	})
+	return paginate(decks, query.Limit, query.Offset), len(decks), nil
+}
+
+func (r *memoryCatalogRepository) AddFork(ctx context.Context, deckID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	entry, ok := r.store.catalog[deckID]
+	if !ok {
+		return ErrNotFound
+	}
+	entry.Forks++
+	r.store.catalog[deckID] = entry
+	return nil
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"sort"
+)
+
+type ChallengeScoreRepository interface {
+	Create(ctx context.Context, score models.ChallengeScore) error
+	// Best returns the user's highest score in the deck, the earliest one on ties, or ErrNotFound
+	Best(ctx context.Context, userID, deckID string) (models.ChallengeScore, error)
+	// ListRecent returns the user's scores in the deck, the latest first
+	ListRecent(ctx context.Context, userID, deckID string, limit int) ([]models.ChallengeScore, error)
+}
+
+type memoryChallengeScoreRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryChallengeScoreRepository(store *MemoryStore) ChallengeScoreRepository {
+	return &memoryChallengeScoreRepository{store: store}
+}
+
+func (r *memoryChallengeScoreRepository) Create(ctx context.Context, score models.ChallengeScore) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	for _, stored := range r.store.challengeScores {
+		if stored.ID == score.ID || stored.SessionID == score.SessionID {
+			return ErrAlreadyExists
+		}
+	}
+	r.store.challengeScores = append(r.store.challengeScores, score)
+	return nil
+}
+
+func (r *memoryChallengeScoreRepository) Best(ctx context.Context, userID, deckID string) (models.ChallengeScore, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var (
+		best  models.ChallengeScore
+		found bool
+	)
+	for _, score := range r.store.challengeScores {
+		if score.UserID != userID || score.DeckID != deckID {
+			continue
+		}
+		if !found || score.Score > best.Score || score.Score == best.Score && score.CompletedAt.Before(best.CompletedAt) {
+			best, found = score, true
+		}
+	}
+	if !found {
+		return models.ChallengeScore{}, ErrNotFound
+	}
+	return best, nil
+}
+
+func (r *memoryChallengeScoreRepository) ListRecent(ctx context.Context, userID, deckID string, limit int) ([]models.ChallengeScore, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	scores := []models.ChallengeScore{}
+	for _, score := range r.store.challengeScores {
+		if score.UserID == userID && score.DeckID == deckID {
+			scores = append(scores, score)
+		}
+	}
+	sort.Slice(scores, func(i, j int) bool { return scores[i].CompletedAt.After(scores[j].CompletedAt) })
+	return paginate(scores, limit, 0), nil
+}
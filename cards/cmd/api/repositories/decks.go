@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"sort"
+)
+
+type DeckRepository interface {
+	Create(ctx context.Context, deck models.Deck) error
+	Get(ctx context.Context, id string) (models.Deck, error)
+	// ListByOwner returns a page of the owner's decks, most recently updated first, and the total count
+	ListByOwner(ctx context.Context, ownerID string, limit, offset int) ([]models.Deck, int, error)
+	// ListAll returns up to limit decks of every owner ordered by id, starting after afterID
+	ListAll(ctx context.Context, afterID string, limit int) ([]models.Deck, error)
+	Update(ctx context.Context, deck models.Deck) error
+	// Delete removes the deck together with its cards, members, invitations and catalog entry,
+	// decks nested in it move to the top level
+	Delete(ctx context.Context, id string) error
+}
+
+type memoryDeckRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryDeckRepository creates a DeckRepository on top of the in-memory store
+func NewMemoryDeckRepository(store *MemoryStore) DeckRepository {
+	return &memoryDeckRepository{store: store}
+}
+
+func (r *memoryDeckRepository) Create(ctx context.Context, deck models.Deck) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.decks[deck.ID]; ok {
+		return ErrAlreadyExists
+	}
+	r.store.decks[deck.ID] = deck
+	return nil
+}
+
+func (r *memoryDeckRepository) Get(ctx context.Context, id string) (models.Deck, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	deck, ok := r.store.decks[id]
+	if !ok {
+		return models.Deck{}, ErrNotFound
+	}
+	return deck, nil
+}
+
+func (r *memoryDeckRepository) ListByOwner(ctx context.Context, ownerID string, limit, offset int) ([]models.Deck, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var decks []models.Deck
+	for _, deck := range r.store.decks {
+		if deck.OwnerID == ownerID {
+			decks = append(decks, deck)
+		}
+	}
+	sort.Slice(decks, func(i, j int) bool {
+		if decks[i].UpdatedAt.Equal(decks[j].UpdatedAt) {
+			return decks[i].ID < decks[j].ID
+		}
+		return decks[i].UpdatedAt.After(decks[j].UpdatedAt)
+	})
+	return paginate(decks, limit, offset), len(decks), nil
+}
+
+func (r *memoryDeckRepository) ListAll(ctx context.Context, afterID string, limit int) ([]models.Deck, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	decks := []models.Deck{}
+	for _, deck := range r.store.decks {
+		if deck.ID > afterID {
+			decks = append(decks, deck)
+		}
+	}
+	sort.Slice(decks, func(i, j int) bool { return decks[i].ID < decks[j].ID })
+	return paginate(decks, limit, 0), nil
+}
+
+func (r *memoryDeckRepository) Update(ctx context.Context, deck models.Deck) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.decks[deck.ID]; !ok {
+		return ErrNotFound
+	}
+	r.store.decks[deck.ID] = deck
+	return nil
+}
+
+func (r *memoryDeckRepository) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.decks[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.decks, id)
+	for childID, child := range r.store.decks {
+		if child.ParentID == id {
+			child.ParentID = ""
+			r.store.decks[childID] = child
+		}
+	}
+	for cardID, card := range r.store.cards {
+		if card.DeckID == id {
+			r.store.deleteCard(cardID)
+		}
+	}
+	for key := range r.store.deckMembers {
+		if key.deckID == id {
+			delete(r.store.deckMembers, key)
+		}
+	}
+	for invitationID, invitation := range r.store.invitations {
+		if invitation.DeckID == id {
+			delete(r.store.invitations, invitationID)
+		}
+	}
+	// forks of the deck stay, they no longer have an upstream to pull from
+	delete(r.store.catalog, id)
+	delete(r.store.forks, id)
+	return nil
+}
+
+func paginate[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	end := len(items)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return items[offset:end]
+}
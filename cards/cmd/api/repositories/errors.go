@@ -0,0 +1,10 @@
+package repositories
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrConflict is returned when a record was changed since it had been read
+	ErrConflict = errors.New("conflict")
+)
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"sort"
+)
+
+type FilteredDeckRepository interface {
+	Create(ctx context.Context, deck models.FilteredDeck) error
+	Get(ctx context.Context, id string) (models.FilteredDeck, error)
+	// ListByOwner returns every filtered deck of the owner ordered by name
+	ListByOwner(ctx context.Context, ownerID string) ([]models.FilteredDeck, error)
+	Update(ctx context.Context, deck models.FilteredDeck) error
+	Delete(ctx context.Context, id string) error
+}
+
+type memoryFilteredDeckRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryFilteredDeckRepository(store *MemoryStore) FilteredDeckRepository {
+	return &memoryFilteredDeckRepository{store: store}
+}
+
+func (r *memoryFilteredDeckRepository) Create(ctx context.Context, deck models.FilteredDeck) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.filteredDecks[deck.ID]; ok {
+		return ErrAlreadyExists
+	}
+	r.store.filteredDecks[deck.ID] = deck
+	return nil
+}
+
+func (r *memoryFilteredDeckRepository) Get(ctx context.Context, id string) (models.FilteredDeck, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	deck, ok := r.store.filteredDecks[id]
+	if !ok {
+		return models.FilteredDeck{}, ErrNotFound
+	}
+	return deck, nil
+}
+
+func (r *memoryFilteredDeckRepository) ListByOwner(ctx context.Context, ownerID string) ([]models.FilteredDeck, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	decks := []models.FilteredDeck{}
+	for _, deck := range r.store.filteredDecks {
+		if deck.OwnerID == ownerID {
+			decks = append(decks, deck)
+		}
+	}
+	sort.Slice(decks, func(i, j int) bool {
+		if decks[i].Name == decks[j].Name {
+			return decks[i].ID < decks[j].ID
+		}
+		return decks[i].Name < decks[j].Name
+	})
+	return decks, nil
+}
+
+func (r *memoryFilteredDeckRepository) Update(ctx context.Context, deck models.FilteredDeck) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.filteredDecks[deck.ID]; !ok {
+		return ErrNotFound
+	}
+	r.store.filteredDecks[deck.ID] = deck
+	return nil
+}
+
+func (r *memoryFilteredDeckRepository) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.filteredDecks[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.filteredDecks, id)
+	return nil
+}
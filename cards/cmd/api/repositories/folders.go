@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"sort"
+)
+
+type FolderRepository interface {
+	Create(ctx context.Context, folder models.Folder) error
+	Get(ctx context.Context, id string) (models.Folder, error)
+	// ListByOwner returns every folder of the owner ordered by name, callers build the tree from the parent ids
+	ListByOwner(ctx context.Context, ownerID string) ([]models.Folder, error)
+	Update(ctx context.Context, folder models.Folder) error
+	// Delete removes the folder, the folders and decks filed in it move to its parent
+	Delete(ctx context.Context, id string) error
+}
+
+type memoryFolderRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryFolderRepository(store *MemoryStore) FolderRepository {
+	return &memoryFolderRepository{store: store}
+}
+
+func (r *memoryFolderRepository) Create(ctx context.Context, folder models.Folder) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.folders[folder.ID]; ok {
+		return ErrAlreadyExists
+	}
+	r.store.folders[folder.ID] = folder
+	return nil
+}
+
+func (r *memoryFolderRepository) Get(ctx context.Context, id string) (models.Folder, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	folder, ok := r.store.folders[id]
+	if !ok {
+		return models.Folder{}, ErrNotFound
+	}
+	return folder, nil
+}
+
+func (r *memoryFolderRepository) ListByOwner(ctx context.Context, ownerID string) ([]models.Folder, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	folders := []models.Folder{}
+	for _, folder := range r.store.folders {
+		if folder.OwnerID == ownerID {
+			folders = append(folders, folder)
+		}
+	}
+	sort.Slice(folders, func(i, j int) bool {
+		if folders[i].Name == folders[j].Name {
+			return folders[i].ID < folders[j].ID
+		}
+		return folders[i].Name < folders[j].Name
+	})
+	return folders, nil
+}
+
+func (r *memoryFolderRepository) Update(ctx context.Context, folder models.Folder) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.folders[folder.ID]; !ok {
+		return ErrNotFound
+	}
+	r.store.folders[folder.ID] = folder
+	return nil
+}
+
+func (r *memoryFolderRepository) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	folder, ok := r.store.folders[id]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(r.store.folders, id)
+	for childID, child := range r.store.folders {
+		if child.ParentID == id {
+			child.ParentID = folder.ParentID
+			r.store.folders[childID] = child
+		}
+	}
+	for deckID, deck := range r.store.decks {
+		if deck.FolderID == id {
+			deck.FolderID = folder.ParentID
+			r.store.decks[deckID] = deck
+		}
+	}
+	return nil
+}
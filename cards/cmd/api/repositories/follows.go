@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"slices"
+	"strings"
+)
+
+type FollowRepository interface {
+	// Follow returns ErrAlreadyExists when the user already follows the followee
+	Follow(ctx context.Context, follow models.Follow) error
+	// Unfollow returns ErrNotFound when the user does not follow the followee
+	Unfollow(ctx context.Context, followerID, followeeID string) error
+	// Following returns the ids of the users the user follows, ordered by id
+	Following(ctx context.Context, followerID string) ([]string, error)
+}
+
+type followKey struct {
+	followerID string
+	followeeID string
+}
+
+type memoryFollowRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryFollowRepository(store *MemoryStore) FollowRepository {
+	return &memoryFollowRepository{store: store}
+}
+
+func (r *memoryFollowRepository) Follow(ctx context.Context, follow models.Follow) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	key := followKey{followerID: follow.FollowerID, followeeID: follow.FolloweeID}
+	if _, ok := r.store.follows[key]; ok {
+		return ErrAlreadyExists
+	}
+	r.store.follows[key] = follow
+	return nil
+}
+
+func (r *memoryFollowRepository) Unfollow(ctx context.Context, followerID, followeeID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	key := followKey{followerID: followerID, followeeID: followeeID}
+	if _, ok := r.store.follows[key]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.follows, key)
+	return nil
+}
+
+func (r *memoryFollowRepository) Following(ctx context.Context, followerID string) ([]string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	following := []string{}
+	for key := range r.store.follows {
+		if key.followerID == followerID {
+			following = append(following, key.followeeID)
+		}
+	}
+	slices.SortFunc(following, strings.Compare)
+	return following, nil
+}
@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+)
+
+type ForkRepository interface {
+	Create(ctx context.Context, fork models.Fork) error
+	Get(ctx context.Context, deckID string) (models.Fork, error)
+	// MarkSynced records that the fork pulled the changes of its upstream
+	MarkSynced(ctx context.Context, fork models.Fork) error
+	// SaveCard links a card of the fork to its upstream card or updates the link
+	SaveCard(ctx context.Context, link models.ForkCard) error
+	// ListCards returns the links of the fork cards, cards added by the user have none
+	ListCards(ctx context.Context, deckID string) ([]models.ForkCard, error)
+}
+
+type memoryForkRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryForkRepository(store *MemoryStore) ForkRepository {
+	return &memoryForkRepository{store: store}
+}
+
+func (r *memoryForkRepository) Create(ctx context.Context, fork models.Fork) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.decks[fork.DeckID]; !ok {
+		return ErrNotFound
+	}
+	if _, ok := r.store.forks[fork.DeckID]; ok {
+		return ErrAlreadyExists
+	}
+	r.store.forks[fork.DeckID] = fork
+	return nil
+}
+
+func (r *memoryForkRepository) Get(ctx context.Context, deckID string) (models.Fork, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	fork, ok := r.store.forks[deckID]
+	if !ok {
+		return models.Fork{}, ErrNotFound
+	}
+	return fork, nil
+}
+
+func (r *memoryForkRepository) MarkSynced(ctx context.Context, fork models.Fork) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	stored, ok := r.store.forks[fork.DeckID]
+	if !ok {
+		return ErrNotFound
+	}
+	stored.SyncedAt = fork.SyncedAt
+	r.store.forks[fork.DeckID] = stored
+	return nil
+}
+
+func (r *memoryForkRepository) SaveCard(ctx context.Context, link models.ForkCard) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	card, ok := r.store.cards[link.CardID]
+	if !ok || card.DeckID != link.DeckID {
+		return ErrNotFound
+	}
+	r.store.forkCards[link.CardID] = link
+	return nil
+}
+
+func (r *memoryForkRepository) ListCards(ctx context.Context, deckID string) ([]models.ForkCard, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	links := []models.ForkCard{}
+	for _, link := range r.store.forkCards {
+		if link.DeckID == deckID {
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
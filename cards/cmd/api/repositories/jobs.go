@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+)
+
+type JobRepository interface {
+	Create(ctx context.Context, job models.Job) error
+	Get(ctx context.Context, id string) (models.Job, error)
+	Update(ctx context.Context, job models.Job) error
+}
+
+type memoryJobRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryJobRepository(store *MemoryStore) JobRepository {
+	return &memoryJobRepository{store: store}
+}
+
+func (r *memoryJobRepository) Create(ctx context.Context, job models.Job) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.jobs[job.ID]; ok {
+		return ErrAlreadyExists
+	}
+	r.store.jobs[job.ID] = copyJob(job)
+	return nil
+}
+
+func (r *memoryJobRepository) Get(ctx context.Context, id string) (models.Job, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	job, ok := r.store.jobs[id]
+	if !ok {
+		return models.Job{}, ErrNotFound
+	}
+	return copyJob(job), nil
+}
+
+func (r *memoryJobRepository) Update(ctx context.Context, job models.Job) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.jobs[job.ID]; !ok {
+		return ErrNotFound
+	}
+	r.store.jobs[job.ID] = copyJob(job)
+	return nil
+}
+
+// copyJob detaches the report so callers can't change the stored job in place, files are never modified
+func copyJob(job models.Job) models.Job {
+	job.Report.Errors = append([]models.JobRowError(nil), job.Report.Errors...)
+	job.Options.Columns = append([]string(nil), job.Options.Columns...)
+	mapping := make(map[string]string, len(job.Options.Mapping))
+	for header, field := range job.Options.Mapping {
+		mapping[header] = field
+	}
+	job.Options.Mapping = mapping
+	return job
+}
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"sync"
+)
+
+type reviewKey struct {
+	userID string
+	cardID string
+}
+
+// MemoryStore is shared by the in-memory repositories, so deleting a deck can drop its cards
+type MemoryStore struct {
+	mu           sync.RWMutex
+	decks        map[string]models.Deck
+	cards        map[string]models.Card
+	reviewStates map[reviewKey]models.ReviewState
+	reviewLogs   []models.ReviewLog
+	quizSessions map[string]models.QuizSession
+	// challengeScores outlive their decks, like quiz sessions
+	challengeScores []models.ChallengeScore
+	jobs            map[string]models.Job
+	folders         map[string]models.Folder
+	filteredDecks   map[string]models.FilteredDeck
+	deckMembers     map[memberKey]models.DeckMember
+	invitations     map[string]models.DeckInvitation
+	catalog         map[string]models.CatalogEntry
+	forks           map[string]models.Fork
+	forkCards       map[string]models.ForkCard
+	// stats outlive decks and cards, they keep the history of the user
+	statsRollups map[rollupKey]models.StatsRollup
+	statsReviews map[string]struct{}
+	// studySettings and streaks are kept by user id
+	studySettings map[string]models.StudySettings
+	streaks       map[string]models.Streak
+	// achievement counters and badges outlive decks like the stats
+	counters       map[counterKey]int
+	counterSources map[counterSourceKey]struct{}
+	badges         map[badgeKey]models.Badge
+	follows        map[followKey]models.Follow
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		decks:          make(map[string]models.Deck),
+		cards:          make(map[string]models.Card),
+		reviewStates:   make(map[reviewKey]models.ReviewState),
+		quizSessions:   make(map[string]models.QuizSession),
+		jobs:           make(map[string]models.Job),
+		folders:        make(map[string]models.Folder),
+		filteredDecks:  make(map[string]models.FilteredDeck),
+		deckMembers:    make(map[memberKey]models.DeckMember),
+		invitations:    make(map[string]models.DeckInvitation),
+		catalog:        make(map[string]models.CatalogEntry),
+		forks:          make(map[string]models.Fork),
+		forkCards:      make(map[string]models.ForkCard),
+		statsRollups:   make(map[rollupKey]models.StatsRollup),
+		statsReviews:   make(map[string]struct{}),
+		studySettings:  make(map[string]models.StudySettings),
+		streaks:        make(map[string]models.Streak),
+		counters:       make(map[counterKey]int),
+		counterSources: make(map[counterSourceKey]struct{}),
+		badges:         make(map[badgeKey]models.Badge),
+		follows:        make(map[followKey]models.Follow),
+	}
+}
+
+// deleteCard drops the card with its review history, the caller must hold the lock
+func (s *MemoryStore) deleteCard(id string) {
+	delete(s.cards, id)
+	delete(s.forkCards, id)
+	for key := range s.reviewStates {
+		if key.cardID == id {
+			delete(s.reviewStates, key)
+		}
+	}
+	logs := s.reviewLogs[:0]
+	for _, log := range s.reviewLogs {
+		if log.CardID != id {
+			logs = append(logs, log)
+		}
+	}
+	s.reviewLogs = logs
+}
@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"github.com/lib/pq"
+)
+
+//go:embed schema.sql
+var schema string
+
+// uniqueViolation is the postgres error code raised on duplicate keys
+const uniqueViolation = "23505"
+
+// foreignKeyViolation is the postgres error code raised when a referenced row is missing
+const foreignKeyViolation = "23503"
+
+// OpenPostgres connects to the database and makes sure the schema exists
+func OpenPostgres(ctx context.Context, url string) (*sql.DB, error) {
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// mapPostgresError translates driver errors into repository errors
+func mapPostgresError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case uniqueViolation:
+			return ErrAlreadyExists
+		case foreignKeyViolation:
+			return ErrNotFound
+		}
+	}
+	return err
+}
+
+// textArray encodes a TEXT[] value, the driver would store a nil slice as NULL
+func textArray(values []string) any {
+	if values == nil {
+		values = []string{}
+	}
+	return pq.Array(values)
+}
+
+// nullString stores an empty string as NULL, for optional references
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// expectAffected returns ErrNotFound when a statement did not touch any row
+func expectAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+)
+
+type postgresAchievementRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresAchievementRepository(db *sql.DB) AchievementRepository {
+	return &postgresAchievementRepository{db: db}
+}
+
+func (r *postgresAchievementRepository) Add(ctx context.Context, userID, counter, sourceID string, amount int) error {
+	// the counter is only changed when the source is inserted, both in one statement
+	_, err := r.db.ExecContext(ctx,
+		`WITH counted AS (
+			INSERT INTO achievement_sources (user_id, counter, source_id, counted_at) VALUES ($1, $2, $3, now())
+			ON CONFLICT (user_id, counter, source_id) DO NOTHING RETURNING user_id
+		)
+		INSERT INTO achievement_counters (user_id, counter, value)
+		SELECT $1, $2, $4 FROM counted
+		ON CONFLICT (user_id, counter) DO UPDATE SET value = achievement_counters.value + EXCLUDED.value`,
+		userID, counter, sourceID, amount,
+	)
+	return mapPostgresError(err)
+}
+
+func (r *postgresAchievementRepository) Raise(ctx context.Context, userID, counter string, value int) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO achievement_counters (user_id, counter, value) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, counter) DO UPDATE SET value = greatest(achievement_counters.value, EXCLUDED.value)`,
+		userID, counter, value,
+	)
+	return mapPostgresError(err)
+}
+
+func (r *postgresAchievementRepository) Counters(ctx context.Context, userID string) (map[string]int, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT counter, value FROM achievement_counters WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counters := make(map[string]int)
+	for rows.Next() {
+		var (
+			counter string
+			value   int
+		)
+		if err := rows.Scan(&counter, &value); err != nil {
+			return nil, err
+		}
+		counters[counter] = value
+	}
+	return counters, rows.Err()
+}
+
+func (r *postgresAchievementRepository) ListUsers(ctx context.Context, afterID string, limit int) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT DISTINCT user_id FROM achievement_counters WHERE user_id > $1 ORDER BY user_id LIMIT $2`,
+		afterID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		users = append(users, userID)
+	}
+	return users, rows.Err()
+}
+
+func (r *postgresAchievementRepository) Award(ctx context.Context, badge models.Badge) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO badges (user_id, achievement_id, awarded_at, retroactive) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, achievement_id) DO NOTHING`,
+		badge.UserID, badge.AchievementID, badge.AwardedAt, badge.Retroactive,
+	)
+	if err != nil {
+		return false, mapPostgresError(err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (r *postgresAchievementRepository) Badges(ctx context.Context, userID string) ([]models.Badge, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT user_id, achievement_id, awarded_at, retroactive FROM badges
+		WHERE user_id = $1 ORDER BY awarded_at, achievement_id`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	badges := []models.Badge{}
+	for rows.Next() {
+		var badge models.Badge
+		if err := rows.Scan(&badge.UserID, &badge.AchievementID, &badge.AwardedAt, &badge.Retroactive); err != nil {
+			return nil, err
+		}
+		badges = append(badges, badge)
+	}
+	return badges, rows.Err()
+}
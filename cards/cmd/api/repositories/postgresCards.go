@@ -0,0 +1,199 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"github.com/lib/pq"
+	"strconv"
+	"strings"
+)
+
+type postgresCardRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresCardRepository(db *sql.DB) CardRepository {
+	return &postgresCardRepository{db: db}
+}
+
+func (r *postgresCardRepository) Create(ctx context.Context, card models.Card) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO cards (id, deck_id, front, back, hint, alternatives, tags, answer_type, absolute_tolerance, relative_tolerance, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		card.ID, card.DeckID, card.Front, card.Back, card.Hint, textArray(card.Alternatives), textArray(card.Tags),
+		card.AnswerType, card.AbsoluteTolerance, card.RelativeTolerance, card.CreatedAt, card.UpdatedAt,
+	)
+	return mapPostgresError(err)
+}
+
+func (r *postgresCardRepository) Get(ctx context.Context, id string) (models.Card, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT id, deck_id, front, back, hint, alternatives, tags, answer_type, absolute_tolerance, relative_tolerance, created_at, updated_at FROM cards WHERE id = $1`, id,
+	)
+	card, err := scanCard(row)
+	if err != nil {
+		return models.Card{}, mapPostgresError(err)
+	}
+	return card, nil
+}
+
+func (r *postgresCardRepository) ListByDeck(ctx context.Context, deckID string) ([]models.Card, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, deck_id, front, back, hint, alternatives, tags, answer_type, absolute_tolerance, relative_tolerance, created_at, updated_at FROM cards
+		WHERE deck_id = $1 ORDER BY created_at, id`, deckID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cards := []models.Card{}
+	for rows.Next() {
+		card, err := scanCard(rows)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, rows.Err()
+}
+
+func (r *postgresCardRepository) Filter(ctx context.Context, filter CardFilter) ([]FilteredCard, error) {
+	where, args := compileCardFilter(filter)
+	// LIMIT NULL means no limit in postgres
+	var limitArg any
+	if filter.Limit > 0 {
+		limitArg = filter.Limit
+	}
+	args = append(args, limitArg)
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT c.id, c.deck_id, c.front, c.back, c.hint, c.alternatives, c.tags, c.answer_type,
+		c.absolute_tolerance, c.relative_tolerance, c.created_at, c.updated_at,
+		rs.card_id IS NOT NULL, coalesce(rs.algorithm, ''), coalesce(rs.repetitions, 0), coalesce(rs.lapses, 0),
+		coalesce(rs.interval_days, 0), coalesce(rs.ease, 0), coalesce(rs.stability, 0), coalesce(rs.difficulty, 0),
+		coalesce(rs.due_at, c.created_at), coalesce(rs.last_review, c.created_at)
+		FROM cards c
+		JOIN decks d ON d.id = c.deck_id
+		LEFT JOIN review_states rs ON rs.card_id = c.id AND rs.user_id = $1
+		WHERE `+where+`
+		ORDER BY rs.due_at NULLS LAST, c.created_at, c.id
+		LIMIT $`+strconv.Itoa(len(args)),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches := []FilteredCard{}
+	for rows.Next() {
+		var match FilteredCard
+		card, state := &match.Card, &match.State
+		err := rows.Scan(
+			&card.ID, &card.DeckID, &card.Front, &card.Back, &card.Hint, pq.Array(&card.Alternatives), pq.Array(&card.Tags),
+			&card.AnswerType, &card.AbsoluteTolerance, &card.RelativeTolerance, &card.CreatedAt, &card.UpdatedAt,
+			&match.Studied, &state.Algorithm, &state.Repetitions, &state.Lapses,
+			&state.IntervalDays, &state.Ease, &state.Stability, &state.Difficulty, &state.Due, &state.LastReview,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if match.Studied {
+			state.UserID, state.CardID, state.DeckID = filter.UserID, card.ID, card.DeckID
+		} else {
+			match.State = models.ReviewState{}
+		}
+		matches = append(matches, match)
+	}
+	return matches, rows.Err()
+}
+
+// compileCardFilter turns the filter into the conditions of a query over cards c, their decks d
+// and the review states rs of the user, $1 is the user id
+func compileCardFilter(filter CardFilter) (string, []any) {
+	args := []any{filter.UserID}
+	arg := func(value any) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	conditions := []string{"d.owner_id = " + arg(filter.OwnerID)}
+	if filter.RestrictDecks {
+		conditions = append(conditions, "c.deck_id = ANY("+arg(textArray(filter.DeckIDs))+")")
+	}
+	if len(filter.ExcludeDeckIDs) > 0 {
+		conditions = append(conditions, "NOT c.deck_id = ANY("+arg(textArray(filter.ExcludeDeckIDs))+")")
+	}
+	tagged := func(tag string) string {
+		return "EXISTS (SELECT 1 FROM unnest(c.tags || d.tags) AS t(tag) WHERE lower(t.tag) = " + arg(tag) +
+			" OR lower(t.tag) LIKE " + arg(escapeLike(tag)+TagSeparator+"%") + ")"
+	}
+	for _, tag := range filter.Tags {
+		conditions = append(conditions, tagged(tag))
+	}
+	for _, tag := range filter.ExcludeTags {
+		conditions = append(conditions, "NOT "+tagged(tag))
+	}
+
+	switch filter.Due {
+	case DueNew:
+		conditions = append(conditions, "rs.card_id IS NULL")
+	case DueDue:
+		conditions = append(conditions, "rs.due_at <= "+arg(filter.Now))
+	case DueReview:
+		conditions = append(conditions, "rs.card_id IS NOT NULL")
+	case DueStudy:
+		conditions = append(conditions, "(rs.card_id IS NULL OR rs.due_at <= "+arg(filter.Now)+")")
+	}
+
+	// the columns are picked from a fixed set, never from the filter
+	columns := map[string]string{FieldLapses: "rs.lapses", FieldDifficulty: "rs.difficulty"}
+	operators := map[string]string{OpEqual: "=", OpGreater: ">", OpGreaterOrEqual: ">=", OpLess: "<", OpLessOrEqual: "<="}
+	for _, comparison := range filter.Comparisons {
+		column, ok := columns[comparison.Field]
+		operator, known := operators[comparison.Op]
+		if !ok || !known {
+			conditions = append(conditions, "false")
+			continue
+		}
+		// comparing NULL of a new card is never true, like the memory filter
+		conditions = append(conditions, column+" "+operator+" "+arg(comparison.Value))
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+func (r *postgresCardRepository) Update(ctx context.Context, card models.Card) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE cards SET front = $2, back = $3, hint = $4, alternatives = $5, tags = $6,
+		answer_type = $7, absolute_tolerance = $8, relative_tolerance = $9, updated_at = $10 WHERE id = $1`,
+		card.ID, card.Front, card.Back, card.Hint, textArray(card.Alternatives), textArray(card.Tags),
+		card.AnswerType, card.AbsoluteTolerance, card.RelativeTolerance, card.UpdatedAt,
+	)
+	if err != nil {
+		return mapPostgresError(err)
+	}
+	return expectAffected(result)
+}
+
+func (r *postgresCardRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM cards WHERE id = $1`, id)
+	if err != nil {
+		return mapPostgresError(err)
+	}
+	return expectAffected(result)
+}
+
+func scanCard(row scanner) (models.Card, error) {
+	var card models.Card
+	err := row.Scan(
+		&card.ID, &card.DeckID, &card.Front, &card.Back, &card.Hint, pq.Array(&card.Alternatives), pq.Array(&card.Tags),
+		&card.AnswerType, &card.AbsoluteTolerance, &card.RelativeTolerance, &card.CreatedAt, &card.UpdatedAt,
+	)
+	return card, err
+}
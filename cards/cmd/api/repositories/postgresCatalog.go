@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"github.com/lib/pq"
+)
+
+const catalogColumns = `deck_id, owner_id, category, language, forks, published_at, updated_at`
+
+// catalogOrders are the ORDER BY clauses of the catalog sorts, the latest published decks come first among equals
+var catalogOrders = map[string]string{
+	CatalogSortPopular: `c.forks DESC, c.published_at DESC, c.deck_id`,
+	CatalogSortRecent:  `c.published_at DESC, c.deck_id`,
+	CatalogSortTitle:   `d.title, c.published_at DESC, c.deck_id`,
+}
+
+type postgresCatalogRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresCatalogRepository(db *sql.DB) CatalogRepository {
+	return &postgresCatalogRepository{db: db}
+}
+
+func (r *postgresCatalogRepository) Publish(ctx context.Context, entry models.CatalogEntry) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO catalog_decks (`+catalogColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (deck_id) DO UPDATE SET category = excluded.category, language = excluded.language,
+		updated_at = excluded.updated_at`,
+		entry.DeckID, entry.OwnerID, entry.Category, entry.Language, entry.Forks, entry.PublishedAt, entry.UpdatedAt,
+	)
+	return mapPostgresError(err)
+}
+
+func (r *postgresCatalogRepository) Get(ctx context.Context, deckID string) (models.CatalogEntry, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+catalogColumns+` FROM catalog_decks WHERE deck_id = $1`, deckID)
+	var entry models.CatalogEntry
+	err := row.Scan(
+		&entry.DeckID, &entry.OwnerID, &entry.Category, &entry.Language, &entry.Forks, &entry.PublishedAt, &entry.UpdatedAt,
+	)
+	if err != nil {
+		return models.CatalogEntry{}, mapPostgresError(err)
+	}
+	return entry, nil
+}
+
+func (r *postgresCatalogRepository) Unpublish(ctx context.Context, deckID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM catalog_decks WHERE deck_id = $1`, deckID)
+	if err != nil {
+		return mapPostgresError(err)
+	}
+	return expectAffected(result)
+}
+
+func (r *postgresCatalogRepository) Browse(ctx context.Context, query CatalogQuery) ([]models.CatalogDeck, int, error) {
+	order, ok := catalogOrders[query.Sort]
+	if !ok {
+		order = catalogOrders[CatalogSortRecent]
+	}
+	// LIMIT NULL means no limit in postgres
+	var limitArg any
+	if query.Limit > 0 {
+		limitArg = query.Limit
+	}
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT c.deck_id, c.owner_id, c.category, c.language, c.forks, c.published_at, c.updated_at,
+		d.id, d.owner_id, coalesce(d.parent_id, ''), coalesce(d.folder_id, ''), d.title, d.description, d.tags,
+		d.created_at, d.updated_at,
+		(SELECT count(*) FROM cards WHERE cards.deck_id = d.id), count(*) OVER ()
+		FROM catalog_decks c JOIN decks d ON d.id = c.deck_id
+		WHERE ($1 = '' OR c.category = $1) AND ($2 = '' OR c.language = $2 OR c.language LIKE $3)
+		ORDER BY `+order+` LIMIT $4 OFFSET $5`,
+		query.Category, query.Language, escapeLike(query.Language)+"-%", limitArg, query.Offset,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	decks := []models.CatalogDeck{}
+	total := 0
+	for rows.Next() {
+		var item models.CatalogDeck
+		entry, deck := &item.Entry, &item.Deck
+		err := rows.Scan(
+			&entry.DeckID, &entry.OwnerID, &entry.Category, &entry.Language, &entry.Forks, &entry.PublishedAt, &entry.UpdatedAt,
+			&deck.ID, &deck.OwnerID, &deck.ParentID, &deck.FolderID, &deck.Title, &deck.Description, pq.Array(&deck.Tags),
+			&deck.CreatedAt, &deck.UpdatedAt,
+			&item.CardCount, &total,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		decks = append(decks, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	// a page past the end has no rows to carry the total
+	if len(decks) == 0 && query.Offset > 0 {
+		err := r.db.QueryRowContext(ctx,
+			`SELECT count(*) FROM catalog_decks c
+			WHERE ($1 = '' OR c.category = $1) AND ($2 = '' OR c.language = $2 OR c.language LIKE $3)`,
+			query.Category, query.Language, escapeLike(query.Language)+"-%",
+		).Scan(&total)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	return decks, total, nil
+}
+
+func (r *postgresCatalogRepository) AddFork(ctx context.Context, deckID string) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE catalog_decks SET forks = forks + 1 WHERE deck_id = $1`, deckID)
+	if err != nil {
+		return mapPostgresError(err)
+	}
+	return expectAffected(result)
+}
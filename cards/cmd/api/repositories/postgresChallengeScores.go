@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"time"
+)
+
+const challengeScoreColumns = `id, user_id, deck_id, session_id, score, answered, correct, max_combo,
+	duration, time_limit, completed_at`
+
+type postgresChallengeScoreRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresChallengeScoreRepository(db *sql.DB) ChallengeScoreRepository {
+	return &postgresChallengeScoreRepository{db: db}
+}
+
+func (r *postgresChallengeScoreRepository) Create(ctx context.Context, score models.ChallengeScore) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO challenge_scores (`+challengeScoreColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		score.ID, score.UserID, score.DeckID, score.SessionID, score.Score, score.Answered, score.Correct,
+		score.MaxCombo, int64(score.Duration), int64(score.TimeLimit), score.CompletedAt,
+	)
+	return mapPostgresError(err)
+}
+
+func (r *postgresChallengeScoreRepository) Best(ctx context.Context, userID, deckID string) (models.ChallengeScore, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+challengeScoreColumns+` FROM challenge_scores
+		WHERE user_id = $1 AND deck_id = $2 ORDER BY score DESC, completed_at LIMIT 1`, userID, deckID,
+	)
+	score, err := scanChallengeScore(row)
+	if err != nil {
+		return models.ChallengeScore{}, mapPostgresError(err)
+	}
+	return score, nil
+}
+
+func (r *postgresChallengeScoreRepository) ListRecent(ctx context.Context, userID, deckID string, limit int) ([]models.ChallengeScore, error) {
+	var limitArg any
+	if limit > 0 {
+		limitArg = limit
+	}
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+challengeScoreColumns+` FROM challenge_scores
+		WHERE user_id = $1 AND deck_id = $2 ORDER BY completed_at DESC LIMIT $3`, userID, deckID, limitArg,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	scores := []models.ChallengeScore{}
+	for rows.Next() {
+		score, err := scanChallengeScore(rows)
+		if err != nil {
+			return nil, err
+		}
+		scores = append(scores, score)
+	}
+	return scores, rows.Err()
+}
+
+func scanChallengeScore(row scanner) (models.ChallengeScore, error) {
+	var (
+		score     models.ChallengeScore
+		duration  int64
+		timeLimit int64
+	)
+	err := row.Scan(
+		&score.ID, &score.UserID, &score.DeckID, &score.SessionID, &score.Score, &score.Answered, &score.Correct,
+		&score.MaxCombo, &duration, &timeLimit, &score.CompletedAt,
+	)
+	score.Duration = time.Duration(duration)
+	score.TimeLimit = time.Duration(timeLimit)
+	return score, err
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"github.com/lib/pq"
+)
+
+// deckColumns are read by scanDeck
+const deckColumns = `id, owner_id, coalesce(parent_id, ''), coalesce(folder_id, ''), title, description, tags, created_at, updated_at`
+
+type postgresDeckRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresDeckRepository(db *sql.DB) DeckRepository {
+	return &postgresDeckRepository{db: db}
+}
+
+func (r *postgresDeckRepository) Create(ctx context.Context, deck models.Deck) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO decks (id, owner_id, parent_id, folder_id, title, description, tags, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		deck.ID, deck.OwnerID, nullString(deck.ParentID), nullString(deck.FolderID), deck.Title, deck.Description,
+		textArray(deck.Tags), deck.CreatedAt, deck.UpdatedAt,
+	)
+	return mapPostgresError(err)
+}
+
+func (r *postgresDeckRepository) Get(ctx context.Context, id string) (models.Deck, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+deckColumns+` FROM decks WHERE id = $1`, id,
+	)
+	deck, err := scanDeck(row)
+	if err != nil {
+		return models.Deck{}, mapPostgresError(err)
+	}
+	return deck, nil
+}
+
+func (r *postgresDeckRepository) ListByOwner(ctx context.Context, ownerID string, limit, offset int) ([]models.Deck, int, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, `SELECT count(*) FROM decks WHERE owner_id = $1`, ownerID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// LIMIT NULL means no limit in postgres
+	var limitArg any
+	if limit > 0 {
+		limitArg = limit
+	}
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+deckColumns+` FROM decks
+		WHERE owner_id = $1 ORDER BY updated_at DESC, id LIMIT $2 OFFSET $3`,
+		ownerID, limitArg, offset,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	decks := []models.Deck{}
+	for rows.Next() {
+		deck, err := scanDeck(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		decks = append(decks, deck)
+	}
+	return decks, total, rows.Err()
+}
+
+func (r *postgresDeckRepository) ListAll(ctx context.Context, afterID string, limit int) ([]models.Deck, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+deckColumns+` FROM decks
+		WHERE id > $1 ORDER BY id LIMIT $2`,
+		afterID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	decks := []models.Deck{}
+	for rows.Next() {
+		deck, err := scanDeck(rows)
+		if err != nil {
+			return nil, err
+		}
+		decks = append(decks, deck)
+	}
+	return decks, rows.Err()
+}
+
+func (r *postgresDeckRepository) Update(ctx context.Context, deck models.Deck) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE decks SET folder_id = $2, title = $3, description = $4, tags = $5, updated_at = $6 WHERE id = $1`,
+		deck.ID, nullString(deck.FolderID), deck.Title, deck.Description, textArray(deck.Tags), deck.UpdatedAt,
+	)
+	if err != nil {
+		return mapPostgresError(err)
+	}
+	return expectAffected(result)
+}
+
+func (r *postgresDeckRepository) Delete(ctx context.Context, id string) error {
+	// cards are removed by the ON DELETE CASCADE constraint
+	result, err := r.db.ExecContext(ctx, `DELETE FROM decks WHERE id = $1`, id)
+	if err != nil {
+		return mapPostgresError(err)
+	}
+	return expectAffected(result)
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanDeck(row scanner) (models.Deck, error) {
+	var deck models.Deck
+	err := row.Scan(
+		&deck.ID, &deck.OwnerID, &deck.ParentID, &deck.FolderID, &deck.Title, &deck.Description, pq.Array(&deck.Tags),
+		&deck.CreatedAt, &deck.UpdatedAt,
+	)
+	return deck, err
+}
@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+)
+
+const filteredDeckColumns = `id, owner_id, name, query, card_limit, created_at, updated_at`
+
+type postgresFilteredDeckRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresFilteredDeckRepository(db *sql.DB) FilteredDeckRepository {
+	return &postgresFilteredDeckRepository{db: db}
+}
+
+func (r *postgresFilteredDeckRepository) Create(ctx context.Context, deck models.FilteredDeck) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO filtered_decks (`+filteredDeckColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		deck.ID, deck.OwnerID, deck.Name, deck.Query, deck.Limit, deck.CreatedAt, deck.UpdatedAt,
+	)
+	return mapPostgresError(err)
+}
+
+func (r *postgresFilteredDeckRepository) Get(ctx context.Context, id string) (models.FilteredDeck, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+filteredDeckColumns+` FROM filtered_decks WHERE id = $1`, id)
+	deck, err := scanFilteredDeck(row)
+	if err != nil {
+		return models.FilteredDeck{}, mapPostgresError(err)
+	}
+	return deck, nil
+}
+
+func (r *postgresFilteredDeckRepository) ListByOwner(ctx context.Context, ownerID string) ([]models.FilteredDeck, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+filteredDeckColumns+` FROM filtered_decks WHERE owner_id = $1 ORDER BY name, id`, ownerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	decks := []models.FilteredDeck{}
+	for rows.Next() {
+		deck, err := scanFilteredDeck(rows)
+		if err != nil {
+			return nil, err
+		}
+		decks = append(decks, deck)
+	}
+	return decks, rows.Err()
+}
+
+func (r *postgresFilteredDeckRepository) Update(ctx context.Context, deck models.FilteredDeck) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE filtered_decks SET name = $2, query = $3, card_limit = $4, updated_at = $5 WHERE id = $1`,
+		deck.ID, deck.Name, deck.Query, deck.Limit, deck.UpdatedAt,
+	)
+	if err != nil {
+		return mapPostgresError(err)
+	}
+	return expectAffected(result)
+}
+
+func (r *postgresFilteredDeckRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM filtered_decks WHERE id = $1`, id)
+	if err != nil {
+		return mapPostgresError(err)
+	}
+	return expectAffected(result)
+}
+
+func scanFilteredDeck(row scanner) (models.FilteredDeck, error) {
+	var deck models.FilteredDeck
+	err := row.Scan(&deck.ID, &deck.OwnerID, &deck.Name, &deck.Query, &deck.Limit, &deck.CreatedAt, &deck.UpdatedAt)
+	return deck, err
+}
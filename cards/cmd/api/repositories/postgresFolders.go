@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+)
+
+const folderColumns = `id, owner_id, coalesce(parent_id, ''), name, created_at, updated_at`
+
+type postgresFolderRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresFolderRepository(db *sql.DB) FolderRepository {
+	return &postgresFolderRepository{db: db}
+}
+
+func (r *postgresFolderRepository) Create(ctx context.Context, folder models.Folder) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO folders (id, owner_id, parent_id, name, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)`,
+		folder.ID, folder.OwnerID, nullString(folder.ParentID), folder.Name, folder.CreatedAt, folder.UpdatedAt,
+	)
+	return mapPostgresError(err)
+}
+
+func (r *postgresFolderRepository) Get(ctx context.Context, id string) (models.Folder, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+folderColumns+` FROM folders WHERE id = $1`, id)
+	folder, err := scanFolder(row)
+	if err != nil {
+		return models.Folder{}, mapPostgresError(err)
+	}
+	return folder, nil
+}
+
+func (r *postgresFolderRepository) ListByOwner(ctx context.Context, ownerID string) ([]models.Folder, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+folderColumns+` FROM folders WHERE owner_id = $1 ORDER BY name, id`, ownerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	folders := []models.Folder{}
+	for rows.Next() {
+		folder, err := scanFolder(rows)
+		if err != nil {
+			return nil, err
+		}
+		folders = append(folders, folder)
+	}
+	return folders, rows.Err()
+}
+
+func (r *postgresFolderRepository) Update(ctx context.Context, folder models.Folder) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE folders SET parent_id = $2, name = $3, updated_at = $4 WHERE id = $1`,
+		folder.ID, nullString(folder.ParentID), folder.Name, folder.UpdatedAt,
+	)
+	if err != nil {
+		return mapPostgresError(err)
+	}
+	return expectAffected(result)
+}
+
+func (r *postgresFolderRepository) Delete(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var parentID sql.NullString
+	err = tx.QueryRowContext(ctx, `SELECT parent_id FROM folders WHERE id = $1 FOR UPDATE`, id).Scan(&parentID)
+	if err != nil {
+		return mapPostgresError(err)
+	}
+	// ON DELETE SET NULL would move the content to the top level instead of the parent
+	if _, err := tx.ExecContext(ctx, `UPDATE folders SET parent_id = $2 WHERE parent_id = $1`, id, parentID); err != nil {
+		return mapPostgresError(err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE decks SET folder_id = $2 WHERE folder_id = $1`, id, parentID); err != nil {
+		return mapPostgresError(err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM folders WHERE id = $1`, id); err != nil {
+		return mapPostgresError(err)
+	}
+	return tx.Commit()
+}
+
+func scanFolder(row scanner) (models.Folder, error) {
+	var folder models.Folder
+	err := row.Scan(&folder.ID, &folder.OwnerID, &folder.ParentID, &folder.Name, &folder.CreatedAt, &folder.UpdatedAt)
+	return folder, err
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+)
+
+type postgresFollowRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresFollowRepository(db *sql.DB) FollowRepository {
+	return &postgresFollowRepository{db: db}
+}
+
+func (r *postgresFollowRepository) Follow(ctx context.Context, follow models.Follow) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO follows (follower_id, followee_id, created_at) VALUES ($1, $2, $3)`,
+		follow.FollowerID, follow.FolloweeID, follow.CreatedAt,
+	)
+	return mapPostgresError(err)
+}
+
+func (r *postgresFollowRepository) Unfollow(ctx context.Context, followerID, followeeID string) error {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2`, followerID, followeeID,
+	)
+	if err != nil {
+		return mapPostgresError(err)
+	}
+	return expectAffected(result)
+}
+
+func (r *postgresFollowRepository) Following(ctx context.Context, followerID string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT followee_id FROM follows WHERE follower_id = $1 ORDER BY followee_id`, followerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	following := []string{}
+	for rows.Next() {
+		var followeeID string
+		if err := rows.Scan(&followeeID); err != nil {
+			return nil, err
+		}
+		following = append(following, followeeID)
+	}
+	return following, rows.Err()
+}
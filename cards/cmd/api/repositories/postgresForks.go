@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+)
+
+type postgresForkRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresForkRepository(db *sql.DB) ForkRepository {
+	return &postgresForkRepository{db: db}
+}
+
+func (r *postgresForkRepository) Create(ctx context.Context, fork models.Fork) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO forks (deck_id, upstream_id, forked_at, synced_at) VALUES ($1, $2, $3, $4)`,
+		fork.DeckID, fork.UpstreamID, fork.ForkedAt, fork.SyncedAt,
+	)
+	return mapPostgresError(err)
+}
+
+func (r *postgresForkRepository) Get(ctx context.Context, deckID string) (models.Fork, error) {
+	var fork models.Fork
+	err := r.db.QueryRowContext(ctx,
+		`SELECT deck_id, upstream_id, forked_at, synced_at FROM forks WHERE deck_id = $1`, deckID,
+	).Scan(&fork.DeckID, &fork.UpstreamID, &fork.ForkedAt, &fork.SyncedAt)
+	if err != nil {
+		return models.Fork{}, mapPostgresError(err)
+	}
+	return fork, nil
+}
+
+func (r *postgresForkRepository) MarkSynced(ctx context.Context, fork models.Fork) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE forks SET synced_at = $2 WHERE deck_id = $1`, fork.DeckID, fork.SyncedAt)
+	if err != nil {
+		return mapPostgresError(err)
+	}
+	return expectAffected(result)
+}
+
+func (r *postgresForkRepository) SaveCard(ctx context.Context, link models.ForkCard) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO fork_cards (card_id, deck_id, upstream_card_id, upstream_updated_at, synced_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (card_id) DO UPDATE SET upstream_card_id = excluded.upstream_card_id,
+		upstream_updated_at = excluded.upstream_updated_at, synced_at = excluded.synced_at`,
+		link.CardID, link.DeckID, link.UpstreamCardID, link.UpstreamUpdatedAt, link.SyncedAt,
+	)
+	return mapPostgresError(err)
+}
+
+func (r *postgresForkRepository) ListCards(ctx context.Context, deckID string) ([]models.ForkCard, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT card_id, deck_id, upstream_card_id, upstream_updated_at, synced_at FROM fork_cards WHERE deck_id = $1`, deckID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := []models.ForkCard{}
+	for rows.Next() {
+		var link models.ForkCard
+		if err := rows.Scan(&link.CardID, &link.DeckID, &link.UpstreamCardID, &link.UpstreamUpdatedAt, &link.SyncedAt); err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	return links, rows.Err()
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+)
+
+const jobColumns = `id, user_id, deck_id, kind, status, format, options, input, output, output_name, output_type,
+	report, error, attempts, created_at, updated_at, completed_at`
+
+type postgresJobRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresJobRepository(db *sql.DB) JobRepository {
+	return &postgresJobRepository{db: db}
+}
+
+func (r *postgresJobRepository) Create(ctx context.Context, job models.Job) error {
+	options, report, err := marshalJob(job)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx,
+		`INSERT INTO jobs (`+jobColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`,
+		job.ID, job.UserID, job.DeckID, job.Kind, job.Status, job.Format, options, job.Input, job.Output,
+		job.OutputName, job.OutputType, report, job.Error, job.Attempts, job.CreatedAt, job.UpdatedAt,
+		nullTime(job.CompletedAt),
+	)
+	return mapPostgresError(err)
+}
+
+func (r *postgresJobRepository) Get(ctx context.Context, id string) (models.Job, error) {
+	var (
+		job         models.Job
+		options     []byte
+		report      []byte
+		completedAt sql.NullTime
+	)
+	err := r.db.QueryRowContext(ctx, `SELECT `+jobColumns+` FROM jobs WHERE id = $1`, id).Scan(
+		&job.ID, &job.UserID, &job.DeckID, &job.Kind, &job.Status, &job.Format, &options, &job.Input, &job.Output,
+		&job.OutputName, &job.OutputType, &report, &job.Error, &job.Attempts, &job.CreatedAt, &job.UpdatedAt,
+		&completedAt,
+	)
+	if err != nil {
+		return models.Job{}, mapPostgresError(err)
+	}
+	if err := json.Unmarshal(options, &job.Options); err != nil {
+		return models.Job{}, err
+	}
+	if err := json.Unmarshal(report, &job.Report); err != nil {
+		return models.Job{}, err
+	}
+	job.CompletedAt = completedAt.Time
+	return job, nil
+}
+
+// Update stores the progress of a job, its input never changes
+func (r *postgresJobRepository) Update(ctx context.Context, job models.Job) error {
+	_, report, err := marshalJob(job)
+	if err != nil {
+		return err
+	}
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE jobs SET status = $2, output = $3, output_name = $4, output_type = $5, report = $6, error = $7,
+			attempts = $8, updated_at = $9, completed_at = $10
+		WHERE id = $1`,
+		job.ID, job.Status, job.Output, job.OutputName, job.OutputType, report, job.Error, job.Attempts,
+		job.UpdatedAt, nullTime(job.CompletedAt),
+	)
+	if err != nil {
+		return mapPostgresError(err)
+	}
+	return expectAffected(result)
+}
+
+func marshalJob(job models.Job) (options, report []byte, err error) {
+	options, err = json.Marshal(job.Options)
+	if err != nil {
+		return nil, nil, err
+	}
+	report, err = json.Marshal(job.Report)
+	if err != nil {
+		return nil, nil, err
+	}
+	return options, report, nil
+}
@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"time"
+)
+
+const quizSessionColumns = `id, user_id, deck_id, status, card_order, modes, seed, questions, current,
+	active_duration, resumed_at, created_at, updated_at, expires_at, completed_at, challenge, version`
+
+// challenge_deadline copies the deadline out of the challenge JSON, so abandoned challenges can be found
+const quizSessionInsertColumns = quizSessionColumns + `, challenge_deadline`
+
+type postgresQuizSessionRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresQuizSessionRepository(db *sql.DB) QuizSessionRepository {
+	return &postgresQuizSessionRepository{db: db}
+}
+
+func (r *postgresQuizSessionRepository) Create(ctx context.Context, session models.QuizSession) error {
+	modes, questions, challenge, err := marshalQuizSession(session)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx,
+		`INSERT INTO quiz_sessions (`+quizSessionInsertColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`,
+		session.ID, session.UserID, session.DeckID, session.Status, session.Order, modes, session.Seed, questions,
+		session.Current, int64(session.ActiveDuration), session.ResumedAt, session.CreatedAt, session.UpdatedAt,
+		session.ExpiresAt, nullTime(session.CompletedAt), nullJSON(challenge), session.Version,
+		nullTime(challengeDeadline(session)),
+	)
+	return mapPostgresError(err)
+}
+
+func (r *postgresQuizSessionRepository) Get(ctx context.Context, id string) (models.QuizSession, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+quizSessionColumns+` FROM quiz_sessions WHERE id = $1`, id)
+	session, err := scanQuizSession(row)
+	if err != nil {
+		return models.QuizSession{}, mapPostgresError(err)
+	}
+	return session, nil
+}
+
+func (r *postgresQuizSessionRepository) ListOpenChallenges(ctx context.Context, userID string, deadlineBefore time.Time, limit int) ([]models.QuizSession, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+quizSessionColumns+` FROM quiz_sessions
+		WHERE status IN ('active', 'paused') AND challenge_deadline < $1 AND ($2 = '' OR user_id = $2)
+		ORDER BY challenge_deadline LIMIT $3`,
+		deadlineBefore, userID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []models.QuizSession{}
+	for rows.Next() {
+		session, err := scanQuizSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+func scanQuizSession(row scanner) (models.QuizSession, error) {
+	var (
+		session        models.QuizSession
+		modes          []byte
+		questions      []byte
+		activeDuration int64
+		completedAt    sql.NullTime
+		challenge      []byte
+	)
+	err := row.Scan(
+		&session.ID, &session.UserID, &session.DeckID, &session.Status, &session.Order, &modes, &session.Seed,
+		&questions, &session.Current, &activeDuration, &session.ResumedAt, &session.CreatedAt, &session.UpdatedAt,
+		&session.ExpiresAt, &completedAt, &challenge, &session.Version,
+	)
+	if err != nil {
+		return models.QuizSession{}, err
+	}
+	if err := json.Unmarshal(modes, &session.Modes); err != nil {
+		return models.QuizSession{}, err
+	}
+	if err := json.Unmarshal(questions, &session.Questions); err != nil {
+		return models.QuizSession{}, err
+	}
+	if challenge != nil {
+		if err := json.Unmarshal(challenge, &session.Challenge); err != nil {
+			return models.QuizSession{}, err
+		}
+	}
+	session.ActiveDuration = time.Duration(activeDuration)
+	session.CompletedAt = completedAt.Time
+	return session, nil
+}
+
+func (r *postgresQuizSessionRepository) Update(ctx context.Context, session *models.QuizSession) error {
+	modes, questions, challenge, err := marshalQuizSession(*session)
+	if err != nil {
+		return err
+	}
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE quiz_sessions SET status = $3, card_order = $4, modes = $5, questions = $6, current = $7,
+			active_duration = $8, resumed_at = $9, updated_at = $10, expires_at = $11, completed_at = $12,
+			challenge = $13, version = version + 1
+		WHERE id = $1 AND version = $2`,
+		session.ID, session.Version, session.Status, session.Order, modes, questions, session.Current,
+		int64(session.ActiveDuration), session.ResumedAt, session.UpdatedAt, session.ExpiresAt,
+		nullTime(session.CompletedAt), nullJSON(challenge),
+	)
+	if err != nil {
+		return mapPostgresError(err)
+	}
+	if err := expectAffected(result); err != nil {
+		// tell a missing session apart from one changed by another request
+		if _, getErr := r.Get(ctx, session.ID); getErr == nil {
+			return ErrConflict
+		}
+		return err
+	}
+	session.Version++
+	return nil
+}
+
+// marshalQuizSession encodes the JSONB columns, challenge stays nil for regular sessions
+func marshalQuizSession(session models.QuizSession) (modes, questions, challenge []byte, err error) {
+	modes, err = json.Marshal(session.Modes)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	questions, err = json.Marshal(session.Questions)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if session.Challenge != nil {
+		challenge, err = json.Marshal(session.Challenge)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return modes, questions, challenge, nil
+}
+
+func challengeDeadline(session models.QuizSession) time.Time {
+	if session.Challenge == nil {
+		return time.Time{}
+	}
+	return session.Challenge.Deadline
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// nullJSON stores missing JSON as NULL, the driver would send an empty value for a nil slice
+func nullJSON(data []byte) any {
+	if data == nil {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"time"
+)
+
+const reviewStateColumns = `user_id, card_id, deck_id, algorithm, repetitions, lapses, interval_days,
+	ease, stability, difficulty, due_at, last_review`
+
+type postgresReviewRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresReviewRepository(db *sql.DB) ReviewRepository {
+	return &postgresReviewRepository{db: db}
+}
+
+func (r *postgresReviewRepository) GetState(ctx context.Context, userID, cardID string) (models.ReviewState, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+reviewStateColumns+` FROM review_states WHERE user_id = $1 AND card_id = $2`, userID, cardID,
+	)
+	state, err := scanReviewState(row)
+	if err != nil {
+		return models.ReviewState{}, mapPostgresError(err)
+	}
+	return state, nil
+}
+
+func (r *postgresReviewRepository) ListStates(ctx context.Context, userID, deckID string) (map[string]models.ReviewState, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+reviewStateColumns+` FROM review_states WHERE user_id = $1 AND deck_id = $2`, userID, deckID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	states := make(map[string]models.ReviewState)
+	for rows.Next() {
+		state, err := scanReviewState(rows)
+		if err != nil {
+			return nil, err
+		}
+		states[state.CardID] = state
+	}
+	return states, rows.Err()
+}
+
+func (r *postgresReviewRepository) ListDue(ctx context.Context, userID, deckID string, before time.Time, limit int) ([]models.ReviewState, error) {
+	var limitArg any
+	if limit > 0 {
+		limitArg = limit
+	}
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+reviewStateColumns+` FROM review_states
+		WHERE user_id = $1 AND deck_id = $2 AND due_at <= $3 ORDER BY due_at, card_id LIMIT $4`,
+		userID, deckID, before, limitArg,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	states := []models.ReviewState{}
+	for rows.Next() {
+		state, err := scanReviewState(rows)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, state)
+	}
+	return states, rows.Err()
+}
+
+func (r *postgresReviewRepository) Save(ctx context.Context, state models.ReviewState, log models.ReviewLog) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := saveReviewState(ctx, tx, state); err != nil {
+		return mapPostgresError(err)
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO review_logs (id, user_id, card_id, deck_id, grade, interval_days, new, reviewed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		log.ID, log.UserID, log.CardID, log.DeckID, log.Grade, log.IntervalDays, log.New, log.ReviewedAt,
+	)
+	if err != nil {
+		return mapPostgresError(err)
+	}
+	return tx.Commit()
+}
+
+func (r *postgresReviewRepository) SaveState(ctx context.Context, state models.ReviewState) error {
+	return mapPostgresError(saveReviewState(ctx, r.db, state))
+}
+
+// execer is implemented by both *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func saveReviewState(ctx context.Context, db execer, state models.ReviewState) error {
+	_, err := db.ExecContext(ctx,
+		`INSERT INTO review_states (`+reviewStateColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (user_id, card_id) DO UPDATE SET
+			deck_id = EXCLUDED.deck_id, algorithm = EXCLUDED.algorithm, repetitions = EXCLUDED.repetitions,
+			lapses = EXCLUDED.lapses, interval_days = EXCLUDED.interval_days, ease = EXCLUDED.ease,
+			stability = EXCLUDED.stability, difficulty = EXCLUDED.difficulty, due_at = EXCLUDED.due_at,
+			last_review = EXCLUDED.last_review`,
+		state.UserID, state.CardID, state.DeckID, state.Algorithm, state.Repetitions, state.Lapses, state.IntervalDays,
+		state.Ease, state.Stability, state.Difficulty, state.Due, state.LastReview,
+	)
+	return err
+}
+
+func (r *postgresReviewRepository) CountSince(ctx context.Context, userID, deckID string, since time.Time) (int, int, error) {
+	var newCards, reviews int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT count(*) FILTER (WHERE new), count(*) FILTER (WHERE NOT new) FROM review_logs
+		WHERE user_id = $1 AND deck_id = $2 AND reviewed_at >= $3`,
+		userID, deckID, since,
+	).Scan(&newCards, &reviews)
+	return newCards, reviews, err
+}
+
+func scanReviewState(row scanner) (models.ReviewState, error) {
+	var state models.ReviewState
+	err := row.Scan(
+		&state.UserID, &state.CardID, &state.DeckID, &state.Algorithm, &state.Repetitions, &state.Lapses,
+		&state.IntervalDays, &state.Ease, &state.Stability, &state.Difficulty, &state.Due, &state.LastReview,
+	)
+	return state, err
+}
@@ -0,0 +1,186 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+)
+
+const (
+	memberColumns     = `deck_id, user_id, role, invited_by, created_at, updated_at`
+	invitationColumns = `id, deck_id, inviter_id, email, role, token_hash, expires_at, created_at,
+		coalesce(accepted_by, ''), accepted_at`
+)
+
+type postgresSharingRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresSharingRepository(db *sql.DB) SharingRepository {
+	return &postgresSharingRepository{db: db}
+}
+
+func (r *postgresSharingRepository) SaveMember(ctx context.Context, member models.DeckMember) error {
+	return mapPostgresError(saveMember(ctx, r.db, member))
+}
+
+func saveMember(ctx context.Context, db execer, member models.DeckMember) error {
+	_, err := db.ExecContext(ctx,
+		`INSERT INTO deck_members (`+memberColumns+`) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (deck_id, user_id) DO UPDATE SET
+			role = EXCLUDED.role, invited_by = EXCLUDED.invited_by, updated_at = EXCLUDED.updated_at`,
+		member.DeckID, member.UserID, member.Role, member.InvitedBy, member.CreatedAt, member.UpdatedAt,
+	)
+	return err
+}
+
+func (r *postgresSharingRepository) GetMember(ctx context.Context, deckID, userID string) (models.DeckMember, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+memberColumns+` FROM deck_members WHERE deck_id = $1 AND user_id = $2`, deckID, userID,
+	)
+	member, err := scanMember(row)
+	if err != nil {
+		return models.DeckMember{}, mapPostgresError(err)
+	}
+	return member, nil
+}
+
+func (r *postgresSharingRepository) ListMembers(ctx context.Context, deckID string) ([]models.DeckMember, error) {
+	return r.listMembers(ctx,
+		`SELECT `+memberColumns+` FROM deck_members WHERE deck_id = $1 ORDER BY created_at, user_id`, deckID,
+	)
+}
+
+func (r *postgresSharingRepository) ListMemberships(ctx context.Context, userID string) ([]models.DeckMember, error) {
+	return r.listMembers(ctx,
+		`SELECT `+memberColumns+` FROM deck_members WHERE user_id = $1 ORDER BY created_at DESC, deck_id`, userID,
+	)
+}
+
+func (r *postgresSharingRepository) listMembers(ctx context.Context, query string, args ...any) ([]models.DeckMember, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []models.DeckMember{}
+	for rows.Next() {
+		member, err := scanMember(rows)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
+
+func (r *postgresSharingRepository) DeleteMember(ctx context.Context, deckID, userID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM deck_members WHERE deck_id = $1 AND user_id = $2`, deckID, userID)
+	if err != nil {
+		return mapPostgresError(err)
+	}
+	return expectAffected(result)
+}
+
+func (r *postgresSharingRepository) CreateInvitation(ctx context.Context, invitation models.DeckInvitation) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO deck_invitations (id, deck_id, inviter_id, email, role, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		invitation.ID, invitation.DeckID, invitation.InviterID, invitation.Email, invitation.Role, invitation.TokenHash,
+		invitation.ExpiresAt, invitation.CreatedAt,
+	)
+	return mapPostgresError(err)
+}
+
+func (r *postgresSharingRepository) GetInvitation(ctx context.Context, id string) (models.DeckInvitation, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+invitationColumns+` FROM deck_invitations WHERE id = $1`, id)
+	invitation, err := scanInvitation(row)
+	if err != nil {
+		return models.DeckInvitation{}, mapPostgresError(err)
+	}
+	return invitation, nil
+}
+
+func (r *postgresSharingRepository) GetInvitationByToken(ctx context.Context, tokenHash string) (models.DeckInvitation, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+invitationColumns+` FROM deck_invitations WHERE token_hash = $1`, tokenHash)
+	invitation, err := scanInvitation(row)
+	if err != nil {
+		return models.DeckInvitation{}, mapPostgresError(err)
+	}
+	return invitation, nil
+}
+
+func (r *postgresSharingRepository) ListInvitations(ctx context.Context, deckID string) ([]models.DeckInvitation, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+invitationColumns+` FROM deck_invitations
+		WHERE deck_id = $1 AND accepted_by IS NULL ORDER BY created_at DESC, id`,
+		deckID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := []models.DeckInvitation{}
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, invitation)
+	}
+	return invitations, rows.Err()
+}
+
+func (r *postgresSharingRepository) DeleteInvitation(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM deck_invitations WHERE id = $1`, id)
+	if err != nil {
+		return mapPostgresError(err)
+	}
+	return expectAffected(result)
+}
+
+func (r *postgresSharingRepository) AcceptInvitation(ctx context.Context, invitation models.DeckInvitation, member models.DeckMember) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if invitation.Email != "" {
+		result, err := tx.ExecContext(ctx,
+			`UPDATE deck_invitations SET accepted_by = $2, accepted_at = $3 WHERE id = $1 AND accepted_by IS NULL`,
+			invitation.ID, invitation.AcceptedBy, invitation.AcceptedAt,
+		)
+		if err != nil {
+			return mapPostgresError(err)
+		}
+		if err := expectAffected(result); err != nil {
+			return ErrConflict
+		}
+	}
+	if err := saveMember(ctx, tx, member); err != nil {
+		return mapPostgresError(err)
+	}
+	return tx.Commit()
+}
+
+func scanMember(row scanner) (models.DeckMember, error) {
+	var member models.DeckMember
+	err := row.Scan(&member.DeckID, &member.UserID, &member.Role, &member.InvitedBy, &member.CreatedAt, &member.UpdatedAt)
+	return member, err
+}
+
+func scanInvitation(row scanner) (models.DeckInvitation, error) {
+	var (
+		invitation models.DeckInvitation
+		acceptedAt sql.NullTime
+	)
+	err := row.Scan(
+		&invitation.ID, &invitation.DeckID, &invitation.InviterID, &invitation.Email, &invitation.Role, &invitation.TokenHash,
+		&invitation.ExpiresAt, &invitation.CreatedAt, &invitation.AcceptedBy, &acceptedAt,
+	)
+	invitation.AcceptedAt = acceptedAt.Time
+	return invitation, err
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"time"
+)
+
+type postgresStatsRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresStatsRepository(db *sql.DB) StatsRepository {
+	return &postgresStatsRepository{db: db}
+}
+
+func (r *postgresStatsRepository) Record(ctx context.Context, reviewID string, rollup models.StatsRollup) error {
+	// the rollup is only changed when the review id is inserted, both in one statement
+	_, err := r.db.ExecContext(ctx,
+		`WITH recorded AS (
+			INSERT INTO stats_reviews (review_id, recorded_at) VALUES ($1, now())
+			ON CONFLICT (review_id) DO NOTHING RETURNING review_id
+		)
+		INSERT INTO stats_rollups (user_id, deck_id, start, reviews, new_cards, recalled, timed, answer_time)
+		SELECT $2, $3, $4, $5, $6, $7, $8, $9 FROM recorded
+		ON CONFLICT (user_id, deck_id, start) DO UPDATE SET
+			reviews = stats_rollups.reviews + EXCLUDED.reviews,
+			new_cards = stats_rollups.new_cards + EXCLUDED.new_cards,
+			recalled = stats_rollups.recalled + EXCLUDED.recalled,
+			timed = stats_rollups.timed + EXCLUDED.timed,
+			answer_time = stats_rollups.answer_time + EXCLUDED.answer_time`,
+		reviewID, rollup.UserID, rollup.DeckID, rollup.Start, rollup.Reviews, rollup.NewCards, rollup.Recalled,
+		rollup.Timed, int64(rollup.AnswerTime),
+	)
+	return mapPostgresError(err)
+}
+
+func (r *postgresStatsRepository) Daily(ctx context.Context, query StatsQuery) ([]models.DailyStats, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT to_char(start AT TIME ZONE $5, 'YYYY-MM-DD') AS day,
+			sum(reviews), sum(new_cards), sum(recalled), sum(timed), sum(answer_time)
+		FROM stats_rollups
+		WHERE user_id = $1 AND ($2 = '' OR deck_id = $2) AND start >= $3 AND start < $4
+		GROUP BY day ORDER BY day`,
+		query.UserID, query.DeckID, query.From, query.To, query.Location.String(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	daily := []models.DailyStats{}
+	for rows.Next() {
+		var (
+			day        models.DailyStats
+			date       string
+			answerTime int64
+		)
+		if err := rows.Scan(&date, &day.Reviews, &day.NewCards, &day.Recalled, &day.Timed, &answerTime); err != nil {
+			return nil, err
+		}
+		day.Date, err = time.ParseInLocation(time.DateOnly, date, query.Location)
+		if err != nil {
+			return nil, err
+		}
+		day.AnswerTime = time.Duration(answerTime)
+		daily = append(daily, day)
+	}
+	return daily, rows.Err()
+}
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+)
+
+const studySettingsColumns = `user_id, timezone, timezone_updated_at, rollover_hour, goal_kind, goal_target, updated_at`
+
+const streakColumns = `user_id, current, longest, last_day, freezes, freezes_used, updated_at, version`
+
+type postgresStreakRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresStreakRepository(db *sql.DB) StreakRepository {
+	return &postgresStreakRepository{db: db}
+}
+
+func (r *postgresStreakRepository) GetSettings(ctx context.Context, userID string) (models.StudySettings, error) {
+	var settings models.StudySettings
+	err := r.db.QueryRowContext(ctx,
+		`SELECT `+studySettingsColumns+` FROM study_settings WHERE user_id = $1`, userID,
+	).Scan(&settings.UserID, &settings.Timezone, &settings.TimezoneUpdatedAt, &settings.RolloverHour,
+		&settings.GoalKind, &settings.GoalTarget, &settings.UpdatedAt)
+	if err != nil {
+		return models.StudySettings{}, mapPostgresError(err)
+	}
+	return settings, nil
+}
+
+func (r *postgresStreakRepository) SaveSettings(ctx context.Context, settings models.StudySettings) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO study_settings (`+studySettingsColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id) DO UPDATE SET rollover_hour = EXCLUDED.rollover_hour, goal_kind = EXCLUDED.goal_kind,
+			goal_target = EXCLUDED.goal_target, updated_at = EXCLUDED.updated_at`,
+		settings.UserID, settings.Timezone, settings.TimezoneUpdatedAt, settings.RolloverHour, settings.GoalKind,
+		settings.GoalTarget, settings.UpdatedAt,
+	)
+	return mapPostgresError(err)
+}
+
+func (r *postgresStreakRepository) SaveTimezone(ctx context.Context, settings models.StudySettings) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO study_settings (`+studySettingsColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id) DO UPDATE SET timezone = EXCLUDED.timezone, timezone_updated_at = EXCLUDED.timezone_updated_at
+		WHERE study_settings.timezone_updated_at < EXCLUDED.timezone_updated_at`,
+		settings.UserID, settings.Timezone, settings.TimezoneUpdatedAt, settings.RolloverHour, settings.GoalKind,
+		settings.GoalTarget, settings.UpdatedAt,
+	)
+	return mapPostgresError(err)
+}
+
+func (r *postgresStreakRepository) GetStreak(ctx context.Context, userID string) (models.Streak, error) {
+	var streak models.Streak
+	err := r.db.QueryRowContext(ctx, `SELECT `+streakColumns+` FROM streaks WHERE user_id = $1`, userID).Scan(
+		&streak.UserID, &streak.Current, &streak.Longest, &streak.LastDay, &streak.Freezes, &streak.FreezesUsed,
+		&streak.UpdatedAt, &streak.Version,
+	)
+	if err != nil {
+		return models.Streak{}, mapPostgresError(err)
+	}
+	streak.LastDay = streak.LastDay.UTC()
+	return streak, nil
+}
+
+func (r *postgresStreakRepository) SaveStreak(ctx context.Context, streak *models.Streak) error {
+	// a new streak is inserted, a stored one is only updated when nobody changed it in the meantime
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO streaks (`+streakColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8 + 1)
+		ON CONFLICT (user_id) DO UPDATE SET current = EXCLUDED.current, longest = EXCLUDED.longest,
+			last_day = EXCLUDED.last_day, freezes = EXCLUDED.freezes, freezes_used = EXCLUDED.freezes_used,
+			updated_at = EXCLUDED.updated_at, version = streaks.version + 1
+		WHERE streaks.version = $8`,
+		streak.UserID, streak.Current, streak.Longest, streak.LastDay, streak.Freezes, streak.FreezesUsed,
+		streak.UpdatedAt, streak.Version,
+	)
+	if err != nil {
+		return mapPostgresError(err)
+	}
+	if err := expectAffected(result); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return ErrConflict
+		}
+		return err
+	}
+	streak.Version++
+	return nil
+}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"sort"
+	"time"
+)
+
+type QuizSessionRepository interface {
+	Create(ctx context.Context, session models.QuizSession) error
+	Get(ctx context.Context, id string) (models.QuizSession, error)
+	// Update stores the session if its version still matches the stored one and increases the version,
+	// otherwise it returns ErrConflict
+	Update(ctx context.Context, session *models.QuizSession) error
+	// ListOpenChallenges returns up to limit active or paused challenges whose deadline passed before the given time,
+	// oldest deadline first. An empty userID lists the challenges of every user.
+	ListOpenChallenges(ctx context.Context, userID string, deadlineBefore time.Time, limit int) ([]models.QuizSession, error)
+}
+
+type memoryQuizSessionRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryQuizSessionRepository(store *MemoryStore) QuizSessionRepository {
+	return &memoryQuizSessionRepository{store: store}
+}
+
+func (r *memoryQuizSessionRepository) Create(ctx context.Context, session models.QuizSession) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.quizSessions[session.ID]; ok {
+		return ErrAlreadyExists
+	}
+	r.store.quizSessions[session.ID] = copyQuizSession(session)
+	return nil
+}
+
+func (r *memoryQuizSessionRepository) Get(ctx context.Context, id string) (models.QuizSession, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	session, ok := r.store.quizSessions[id]
+	if !ok {
+		return models.QuizSession{}, ErrNotFound
+	}
+	return copyQuizSession(session), nil
+}
+
+func (r *memoryQuizSessionRepository) Update(ctx context.Context, session *models.QuizSession) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	stored, ok := r.store.quizSessions[session.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	if stored.Version != session.Version {
+		return ErrConflict
+	}
+	session.Version++
+	r.store.quizSessions[session.ID] = copyQuizSession(*session)
+	return nil
+}
+
+func (r *memoryQuizSessionRepository) ListOpenChallenges(ctx context.Context, userID string, deadlineBefore time.Time, limit int) ([]models.QuizSession, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	sessions := []models.QuizSession{}
+	for _, session := range r.store.quizSessions {
+		if session.Challenge == nil || !session.Challenge.Deadline.Before(deadlineBefore) ||
+			(session.Status != models.QuizStatusActive && session.Status != models.QuizStatusPaused) ||
+			(userID != "" && session.UserID != userID) {
+			continue
+		}
+		sessions = append(sessions, copyQuizSession(session))
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].Challenge.Deadline.Before(sessions[j].Challenge.Deadline)
+	})
+	if len(sessions) > limit {
+		sessions = sessions[:limit]
+	}
+	return sessions, nil
+}
+
+// copyQuizSession detaches the questions so callers can't change the stored session in place
+func copyQuizSession(session models.QuizSession) models.QuizSession {
+	session.Modes = append([]string(nil), session.Modes...)
+	questions := make([]models.QuizQuestion, len(session.Questions))
+	for i, question := range session.Questions {
+		question.GroupCardIDs = append([]string(nil), question.GroupCardIDs...)
+		question.Terms = append([]string(nil), question.Terms...)
+		question.Options = append([]string(nil), question.Options...)
+		question.Accepted = append([]string(nil), question.Accepted...)
+		question.MissedCardIDs = append([]string(nil), question.MissedCardIDs...)
+		questions[i] = question
+	}
+	session.Questions = questions
+	if session.Challenge != nil {
+		challenge := *session.Challenge
+		session.Challenge = &challenge
+	}
+	return session
+}
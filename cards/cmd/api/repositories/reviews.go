@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"sort"
+	"time"
+)
+
+type ReviewRepository interface {
+	GetState(ctx context.Context, userID, cardID string) (models.ReviewState, error)
+	// ListStates returns the user's review states of the deck cards keyed by card id
+	ListStates(ctx context.Context, userID, deckID string) (map[string]models.ReviewState, error)
+	// ListDue returns states due at or before the moment, the most overdue first
+	ListDue(ctx context.Context, userID, deckID string, before time.Time, limit int) ([]models.ReviewState, error)
+	// Save stores the new state together with the log of the review that produced it
+	Save(ctx context.Context, state models.ReviewState, log models.ReviewLog) error
+	// SaveState stores a state without a review, e.g. one carried over from another app
+	SaveState(ctx context.Context, state models.ReviewState) error
+	// CountSince counts the user's reviews in the deck since the moment, new cards and reviews separately
+	CountSince(ctx context.Context, userID, deckID string, since time.Time) (newCards int, reviews int, err error)
+}
+
+type memoryReviewRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryReviewRepository(store *MemoryStore) ReviewRepository {
+	return &memoryReviewRepository{store: store}
+}
+
+func (r *memoryReviewRepository) GetState(ctx context.Context, userID, cardID string) (models.ReviewState, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	state, ok := r.store.reviewStates[reviewKey{userID: userID, cardID: cardID}]
+	if !ok {
+		return models.ReviewState{}, ErrNotFound
+	}
+	return state, nil
+}
+
+func (r *memoryReviewRepository) ListStates(ctx context.Context, userID, deckID string) (map[string]models.ReviewState, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	states := make(map[string]models.ReviewState)
+	for key, state := range r.store.reviewStates {
+		if key.userID == userID && state.DeckID == deckID {
+			states[key.cardID] = state
+		}
+	}
+	return states, nil
+}
+
+func (r *memoryReviewRepository) ListDue(ctx context.Context, userID, deckID string, before time.Time, limit int) ([]models.ReviewState, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	states := []models.ReviewState{}
+	for key, state := range r.store.reviewStates {
+		if key.userID == userID && state.DeckID == deckID && !state.Due.After(before) {
+			states = append(states, state)
+		}
+	}
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].Due.Equal(states[j].Due) {
+			return states[i].CardID < states[j].CardID
+		}
+		return states[i].Due.Before(states[j].Due)
+	})
+	return paginate(states, limit, 0), nil
+}
+
+func (r *memoryReviewRepository) Save(ctx context.Context, state models.ReviewState, log models.ReviewLog) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.cards[state.CardID]; !ok {
+		return ErrNotFound
+	}
+	r.store.reviewStates[reviewKey{userID: state.UserID, cardID: state.CardID}] = state
+	r.store.reviewLogs = append(r.store.reviewLogs, log)
+	return nil
+}
+
+func (r *memoryReviewRepository) SaveState(ctx context.Context, state models.ReviewState) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.cards[state.CardID]; !ok {
+		return ErrNotFound
+	}
+	r.store.reviewStates[reviewKey{userID: state.UserID, cardID: state.CardID}] = state
+	return nil
+}
+
+func (r *memoryReviewRepository) CountSince(ctx context.Context, userID, deckID string, since time.Time) (int, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var newCards, reviews int
+	for _, log := range r.store.reviewLogs {
+		if log.UserID != userID || log.DeckID != deckID || log.ReviewedAt.Before(since) {
+			continue
+		}
+		if log.New {
+			newCards++
+		} else {
+			reviews++
+		}
+	}
+	return newCards, reviews, nil
+}
@@ -0,0 +1,296 @@
+CREATE TABLE IF NOT EXISTS decks (
+    id          TEXT PRIMARY KEY,
+    owner_id    TEXT        NOT NULL,
+    title       TEXT        NOT NULL,
+    description TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL
+);
+
+-- deleting a parent moves its child decks to the top level
+ALTER TABLE decks ADD COLUMN IF NOT EXISTS parent_id TEXT REFERENCES decks (id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS folders (
+    id         TEXT PRIMARY KEY,
+    owner_id   TEXT        NOT NULL,
+    -- deleting a folder moves its content to the parent, the repository does it before the delete
+    parent_id  TEXT REFERENCES folders (id) ON DELETE SET NULL,
+    name       TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS folders_owner_id_idx ON folders (owner_id, name);
+
+ALTER TABLE decks ADD COLUMN IF NOT EXISTS folder_id TEXT REFERENCES folders (id) ON DELETE SET NULL;
+ALTER TABLE decks ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS decks_owner_id_updated_at_idx ON decks (owner_id, updated_at DESC);
+CREATE INDEX IF NOT EXISTS decks_parent_id_idx ON decks (parent_id);
+
+CREATE TABLE IF NOT EXISTS cards (
+    id           TEXT PRIMARY KEY,
+    deck_id      TEXT        NOT NULL REFERENCES decks (id) ON DELETE CASCADE,
+    front        TEXT        NOT NULL,
+    back         TEXT        NOT NULL,
+    hint         TEXT        NOT NULL DEFAULT '',
+    alternatives TEXT[]      NOT NULL DEFAULT '{}',
+    tags         TEXT[]      NOT NULL DEFAULT '{}',
+    answer_type  TEXT        NOT NULL DEFAULT 'text',
+    absolute_tolerance DOUBLE PRECISION NOT NULL DEFAULT 0,
+    relative_tolerance DOUBLE PRECISION NOT NULL DEFAULT 0,
+    created_at   TIMESTAMPTZ NOT NULL,
+    updated_at   TIMESTAMPTZ NOT NULL
+);
+
+-- columns added after the first release, CREATE TABLE IF NOT EXISTS leaves existing tables as they are
+ALTER TABLE cards ADD COLUMN IF NOT EXISTS alternatives TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE cards ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE cards ADD COLUMN IF NOT EXISTS answer_type TEXT NOT NULL DEFAULT 'text';
+ALTER TABLE cards ADD COLUMN IF NOT EXISTS absolute_tolerance DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE cards ADD COLUMN IF NOT EXISTS relative_tolerance DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS cards_deck_id_created_at_idx ON cards (deck_id, created_at);
+
+CREATE TABLE IF NOT EXISTS review_states (
+    user_id       TEXT             NOT NULL,
+    card_id       TEXT             NOT NULL REFERENCES cards (id) ON DELETE CASCADE,
+    deck_id       TEXT             NOT NULL,
+    algorithm     TEXT             NOT NULL,
+    repetitions   INTEGER          NOT NULL,
+    lapses        INTEGER          NOT NULL,
+    interval_days INTEGER          NOT NULL,
+    ease          DOUBLE PRECISION NOT NULL,
+    stability     DOUBLE PRECISION NOT NULL,
+    difficulty    DOUBLE PRECISION NOT NULL,
+    due_at        TIMESTAMPTZ      NOT NULL,
+    last_review   TIMESTAMPTZ      NOT NULL,
+    PRIMARY KEY (user_id, card_id)
+);
+
+CREATE INDEX IF NOT EXISTS review_states_user_id_deck_id_due_at_idx ON review_states (user_id, deck_id, due_at);
+
+CREATE TABLE IF NOT EXISTS deck_members (
+    deck_id    TEXT        NOT NULL REFERENCES decks (id) ON DELETE CASCADE,
+    user_id    TEXT        NOT NULL,
+    role       TEXT        NOT NULL,
+    invited_by TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (deck_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS deck_members_user_id_idx ON deck_members (user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS deck_invitations (
+    id          TEXT PRIMARY KEY,
+    deck_id     TEXT        NOT NULL REFERENCES decks (id) ON DELETE CASCADE,
+    inviter_id  TEXT        NOT NULL,
+    email       TEXT        NOT NULL DEFAULT '',
+    role        TEXT        NOT NULL,
+    token_hash  TEXT        NOT NULL UNIQUE,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL,
+    accepted_by TEXT,
+    accepted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS deck_invitations_deck_id_idx ON deck_invitations (deck_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS catalog_decks (
+    deck_id      TEXT PRIMARY KEY REFERENCES decks (id) ON DELETE CASCADE,
+    owner_id     TEXT        NOT NULL,
+    category     TEXT        NOT NULL,
+    language     TEXT        NOT NULL,
+    forks        INTEGER     NOT NULL DEFAULT 0,
+    published_at TIMESTAMPTZ NOT NULL,
+    updated_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS catalog_decks_category_language_idx ON catalog_decks (category, language);
+CREATE INDEX IF NOT EXISTS catalog_decks_forks_idx ON catalog_decks (forks DESC, published_at DESC);
+
+-- the upstream is not a foreign key, forks outlive the decks they were copied from
+CREATE TABLE IF NOT EXISTS forks (
+    deck_id     TEXT PRIMARY KEY REFERENCES decks (id) ON DELETE CASCADE,
+    upstream_id TEXT        NOT NULL,
+    forked_at   TIMESTAMPTZ NOT NULL,
+    synced_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS fork_cards (
+    card_id             TEXT PRIMARY KEY REFERENCES cards (id) ON DELETE CASCADE,
+    deck_id             TEXT        NOT NULL,
+    upstream_card_id    TEXT        NOT NULL,
+    upstream_updated_at TIMESTAMPTZ NOT NULL,
+    synced_at           TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS fork_cards_deck_id_idx ON fork_cards (deck_id);
+
+CREATE TABLE IF NOT EXISTS filtered_decks (
+    id          TEXT PRIMARY KEY,
+    owner_id    TEXT        NOT NULL,
+    name        TEXT        NOT NULL,
+    query       TEXT        NOT NULL,
+    card_limit  INTEGER     NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS filtered_decks_owner_id_idx ON filtered_decks (owner_id, name);
+
+CREATE TABLE IF NOT EXISTS review_logs (
+    id            TEXT PRIMARY KEY,
+    user_id       TEXT        NOT NULL,
+    card_id       TEXT        NOT NULL REFERENCES cards (id) ON DELETE CASCADE,
+    deck_id       TEXT        NOT NULL,
+    grade         INTEGER     NOT NULL,
+    interval_days INTEGER     NOT NULL,
+    new           BOOLEAN     NOT NULL,
+    reviewed_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS review_logs_user_id_deck_id_reviewed_at_idx ON review_logs (user_id, deck_id, reviewed_at);
+
+-- rollups are built from review events and don't reference decks, the history outlives them
+CREATE TABLE IF NOT EXISTS stats_rollups (
+    user_id     TEXT        NOT NULL,
+    deck_id     TEXT        NOT NULL,
+    start       TIMESTAMPTZ NOT NULL,
+    reviews     INTEGER     NOT NULL,
+    new_cards   INTEGER     NOT NULL,
+    recalled    INTEGER     NOT NULL,
+    timed       INTEGER     NOT NULL,
+    answer_time BIGINT      NOT NULL,
+    PRIMARY KEY (user_id, deck_id, start)
+);
+
+CREATE INDEX IF NOT EXISTS stats_rollups_user_id_start_idx ON stats_rollups (user_id, start);
+
+-- reviews already added to the rollups, redelivered events are skipped
+CREATE TABLE IF NOT EXISTS stats_reviews (
+    review_id   TEXT PRIMARY KEY,
+    recorded_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS study_settings (
+    user_id             TEXT PRIMARY KEY,
+    timezone            TEXT        NOT NULL,
+    timezone_updated_at TIMESTAMPTZ NOT NULL,
+    rollover_hour       INTEGER     NOT NULL,
+    goal_kind           TEXT        NOT NULL,
+    goal_target         INTEGER     NOT NULL,
+    updated_at          TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS streaks (
+    user_id      TEXT PRIMARY KEY,
+    current      INTEGER     NOT NULL,
+    longest      INTEGER     NOT NULL,
+    last_day     TIMESTAMPTZ NOT NULL,
+    freezes      INTEGER     NOT NULL,
+    freezes_used INTEGER     NOT NULL,
+    updated_at   TIMESTAMPTZ NOT NULL,
+    version      INTEGER     NOT NULL
+);
+
+-- counters are kept whether an achievement uses them or not, so achievements added later are awarded retroactively
+CREATE TABLE IF NOT EXISTS achievement_counters (
+    user_id TEXT    NOT NULL,
+    counter TEXT    NOT NULL,
+    value   INTEGER NOT NULL,
+    PRIMARY KEY (user_id, counter)
+);
+
+-- sources already added to a counter, redelivered events are skipped
+CREATE TABLE IF NOT EXISTS achievement_sources (
+    user_id    TEXT        NOT NULL,
+    counter    TEXT        NOT NULL,
+    source_id  TEXT        NOT NULL,
+    counted_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, counter, source_id)
+);
+
+CREATE TABLE IF NOT EXISTS badges (
+    user_id        TEXT        NOT NULL,
+    achievement_id TEXT        NOT NULL,
+    awarded_at     TIMESTAMPTZ NOT NULL,
+    retroactive    BOOLEAN     NOT NULL,
+    PRIMARY KEY (user_id, achievement_id)
+);
+
+-- users whose scores show up on the follower's leaderboards
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id TEXT        NOT NULL,
+    followee_id TEXT        NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (follower_id, followee_id)
+);
+
+CREATE TABLE IF NOT EXISTS quiz_sessions (
+    id              TEXT PRIMARY KEY,
+    user_id         TEXT        NOT NULL,
+    deck_id         TEXT        NOT NULL,
+    status          TEXT        NOT NULL,
+    card_order      TEXT        NOT NULL,
+    modes           JSONB       NOT NULL,
+    seed            BIGINT      NOT NULL,
+    questions       JSONB       NOT NULL,
+    current         INTEGER     NOT NULL,
+    active_duration BIGINT      NOT NULL,
+    resumed_at      TIMESTAMPTZ NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL,
+    updated_at      TIMESTAMPTZ NOT NULL,
+    expires_at      TIMESTAMPTZ NOT NULL,
+    completed_at    TIMESTAMPTZ,
+    challenge       JSONB,
+    version         INTEGER     NOT NULL
+);
+
+ALTER TABLE quiz_sessions ADD COLUMN IF NOT EXISTS challenge JSONB;
+ALTER TABLE quiz_sessions ADD COLUMN IF NOT EXISTS challenge_deadline TIMESTAMPTZ;
+UPDATE quiz_sessions SET challenge_deadline = (challenge ->> 'Deadline')::timestamptz
+WHERE challenge IS NOT NULL AND challenge_deadline IS NULL;
+
+CREATE INDEX IF NOT EXISTS quiz_sessions_user_id_idx ON quiz_sessions (user_id);
+-- finds challenges abandoned before their deadline, their scores are recorded by a sweeper
+CREATE INDEX IF NOT EXISTS quiz_sessions_open_challenges_idx ON quiz_sessions (challenge_deadline)
+    WHERE status IN ('active', 'paused') AND challenge_deadline IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS challenge_scores (
+    id           TEXT PRIMARY KEY,
+    user_id      TEXT        NOT NULL,
+    deck_id      TEXT        NOT NULL,
+    session_id   TEXT        NOT NULL UNIQUE,
+    score        INTEGER     NOT NULL,
+    answered     INTEGER     NOT NULL,
+    correct      INTEGER     NOT NULL,
+    max_combo    INTEGER     NOT NULL,
+    duration     BIGINT      NOT NULL,
+    time_limit   BIGINT      NOT NULL,
+    completed_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS challenge_scores_user_id_deck_id_score_idx ON challenge_scores (user_id, deck_id, score DESC);
+
+-- jobs don't reference decks, deleting the deck fails a pending job instead of dropping it
+CREATE TABLE IF NOT EXISTS jobs (
+    id           TEXT PRIMARY KEY,
+    user_id      TEXT        NOT NULL,
+    deck_id      TEXT        NOT NULL,
+    kind         TEXT        NOT NULL,
+    status       TEXT        NOT NULL,
+    format       TEXT        NOT NULL,
+    options      JSONB       NOT NULL,
+    input        BYTEA,
+    output       BYTEA,
+    output_name  TEXT        NOT NULL DEFAULT '',
+    output_type  TEXT        NOT NULL DEFAULT '',
+    report       JSONB       NOT NULL,
+    error        TEXT        NOT NULL DEFAULT '',
+    attempts     INTEGER     NOT NULL DEFAULT 0,
+    created_at   TIMESTAMPTZ NOT NULL,
+    updated_at   TIMESTAMPTZ NOT NULL,
+    completed_at TIMESTAMPTZ
+);
@@ -0,0 +1,200 @@
+package repositories
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"sort"
+)
+
+type SharingRepository interface {
+	// SaveMember adds the member or changes its role
+	SaveMember(ctx context.Context, member models.DeckMember) error
+	GetMember(ctx context.Context, deckID, userID string) (models.DeckMember, error)
+	// ListMembers returns the members of the deck in the order they joined
+	ListMembers(ctx context.Context, deckID string) ([]models.DeckMember, error)
+	// ListMemberships returns the decks shared with the user, the latest first
+	ListMemberships(ctx context.Context, userID string) ([]models.DeckMember, error)
+	DeleteMember(ctx context.Context, deckID, userID string) error
+
+	CreateInvitation(ctx context.Context, invitation models.DeckInvitation) error
+	GetInvitation(ctx context.Context, id string) (models.DeckInvitation, error)
+	GetInvitationByToken(ctx context.Context, tokenHash string) (models.DeckInvitation, error)
+	// ListInvitations returns the invitations of the deck not accepted yet, the latest first
+	ListInvitations(ctx context.Context, deckID string) ([]models.DeckInvitation, error)
+	DeleteInvitation(ctx context.Context, id string) error
+	// AcceptInvitation saves the member and, for email invitations, marks the invitation as accepted.
+	// ErrConflict is returned when an email invitation was accepted in the meantime.
+	AcceptInvitation(ctx context.Context, invitation models.DeckInvitation, member models.DeckMember) error
+}
+
+type memberKey struct {
+	deckID string
+	userID string
+}
+
+type memorySharingRepository struct {
+	store *MemoryStore
+}
+
+func NewMemorySharingRepository(store *MemoryStore) SharingRepository {
+	return &memorySharingRepository{store: store}
+}
+
+func (r *memorySharingRepository) SaveMember(ctx context.Context, member models.DeckMember) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return r.saveMember(member)
+}
+
+// saveMember stores the member, the caller must hold the lock
+func (r *memorySharingRepository) saveMember(member models.DeckMember) error {
+	if _, ok := r.store.decks[member.DeckID]; !ok {
+		return ErrNotFound
+	}
+	key := memberKey{deckID: member.DeckID, userID: member.UserID}
+	if stored, ok := r.store.deckMembers[key]; ok {
+		member.CreatedAt = stored.CreatedAt
+	}
+	r.store.deckMembers[key] = member
+	return nil
+}
+
+func (r *memorySharingRepository) GetMember(ctx context.Context, deckID, userID string) (models.DeckMember, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	member, ok := r.store.deckMembers[memberKey{deckID: deckID, userID: userID}]
+	if !ok {
+		return models.DeckMember{}, ErrNotFound
+	}
+	return member, nil
+}
+
+func (r *memorySharingRepository) ListMembers(ctx context.Context, deckID string) ([]models.DeckMember, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	members := []models.DeckMember{}
+	for _, member := range r.store.deckMembers {
+		if member.DeckID == deckID {
+			members = append(members, member)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].CreatedAt.Equal(members[j].CreatedAt) {
+			return members[i].UserID < members[j].UserID
+		}
+		return members[i].CreatedAt.Before(members[j].CreatedAt)
+	})
+	return members, nil
+}
+
+func (r *memorySharingRepository) ListMemberships(ctx context.Context, userID string) ([]models.DeckMember, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	members := []models.DeckMember{}
+	for _, member := range r.store.deckMembers {
+		if member.UserID == userID {
+			members = append(members, member)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].CreatedAt.Equal(members[j].CreatedAt) {
+			return members[i].DeckID < members[j].DeckID
+		}
+		return members[i].CreatedAt.After(members[j].CreatedAt)
+	})
+	return members, nil
+}
+
+func (r *memorySharingRepository) DeleteMember(ctx context.Context, deckID, userID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	key := memberKey{deckID: deckID, userID: userID}
+	if _, ok := r.store.deckMembers[key]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.deckMembers, key)
+	return nil
+}
+
+func (r *memorySharingRepository) CreateInvitation(ctx context.Context, invitation models.DeckInvitation) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.decks[invitation.DeckID]; !ok {
+		return ErrNotFound
+	}
+	for _, stored := range r.store.invitations {
+		if stored.ID == invitation.ID || stored.TokenHash == invitation.TokenHash {
+			return ErrAlreadyExists
+		}
+	}
+	r.store.invitations[invitation.ID] = invitation
+	return nil
+}
+
+func (r *memorySharingRepository) GetInvitation(ctx context.Context, id string) (models.DeckInvitation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	invitation, ok := r.store.invitations[id]
+	if !ok {
+		return models.DeckInvitation{}, ErrNotFound
+	}
+	return invitation, nil
+}
+
+func (r *memorySharingRepository) GetInvitationByToken(ctx context.Context, tokenHash string) (models.DeckInvitation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, invitation := range r.store.invitations {
+		if invitation.TokenHash == tokenHash {
+			return invitation, nil
+		}
+	}
+	return models.DeckInvitation{}, ErrNotFound
+}
+
+func (r *memorySharingRepository) ListInvitations(ctx context.Context, deckID string) ([]models.DeckInvitation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	invitations := []models.DeckInvitation{}
+	for _, invitation := range r.store.invitations {
+		if invitation.DeckID == deckID && invitation.AcceptedBy == "" {
+			invitations = append(invitations, invitation)
+		}
+	}
+	sort.Slice(invitations, func(i, j int) bool {
+		if invitations[i].CreatedAt.Equal(invitations[j].CreatedAt) {
+			return invitations[i].ID < invitations[j].ID
+		}
+		return invitations[i].CreatedAt.After(invitations[j].CreatedAt)
+	})
+	return invitations, nil
+}
+
+func (r *memorySharingRepository) DeleteInvitation(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.invitations[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.invitations, id)
+	return nil
+}
+
+func (r *memorySharingRepository) AcceptInvitation(ctx context.Context, invitation models.DeckInvitation, member models.DeckMember) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	stored, ok := r.store.invitations[invitation.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	if invitation.Email != "" && stored.AcceptedBy != "" {
+		return ErrConflict
+	}
+	if err := r.saveMember(member); err != nil {
+		return err
+	}
+	if invitation.Email != "" {
+		r.store.invitations[invitation.ID] = invitation
+	}
+	return nil
+}
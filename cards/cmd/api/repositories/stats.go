@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"sort"
+	"time"
+)
+
+// StatsQuery selects the rollups of a user, of every deck when DeckID is empty
+type StatsQuery struct {
+	UserID string
+	DeckID string
+	// From and To bound the rollups by their start, To is exclusive
+	From time.Time
+	To   time.Time
+	// Location splits the rollups into days
+	Location *time.Location
+}
+
+type StatsRepository interface {
+	// Record adds the rollup to the stored one of the same user, deck and start.
+	// A review is recorded once, a redelivered event with the same review id changes nothing.
+	Record(ctx context.Context, reviewID string, rollup models.StatsRollup) error
+	// Daily sums the selected rollups by day, days without reviews are left out
+	Daily(ctx context.Context, query StatsQuery) ([]models.DailyStats, error)
+}
+
+type rollupKey struct {
+	userID string
+	deckID string
+	start  time.Time
+}
+
+type memoryStatsRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryStatsRepository(store *MemoryStore) StatsRepository {
+	return &memoryStatsRepository{store: store}
+}
+
+func (r *memoryStatsRepository) Record(ctx context.Context, reviewID string, rollup models.StatsRollup) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.statsReviews[reviewID]; ok {
+		return nil
+	}
+	r.store.statsReviews[reviewID] = struct{}{}
+	key := rollupKey{userID: rollup.UserID, deckID: rollup.DeckID, start: rollup.Start.UTC()}
+	stored, ok := r.store.statsRollups[key]
+	if !ok {
+		stored = models.StatsRollup{UserID: rollup.UserID, DeckID: rollup.DeckID, Start: key.start}
+	}
+	stored.Add(rollup.ReviewTotals)
+	r.store.statsRollups[key] = stored
+	return nil
+}
+
+func (r *memoryStatsRepository) Daily(ctx context.Context, query StatsQuery) ([]models.DailyStats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	days := make(map[time.Time]*models.DailyStats)
+	for key, rollup := range r.store.statsRollups {
+		if key.userID != query.UserID || (query.DeckID != "" && key.deckID != query.DeckID) ||
+			key.start.Before(query.From) || !key.start.Before(query.To) {
+			continue
+		}
+		local := key.start.In(query.Location)
+		date := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, query.Location)
+		day, ok := days[date]
+		if !ok {
+			day = &models.DailyStats{Date: date}
+			days[date] = day
+		}
+		day.Add(rollup.ReviewTotals)
+	}
+
+	daily := make([]models.DailyStats, 0, len(days))
+	for _, day := range days {
+		daily = append(daily, *day)
+	}
+	sort.Slice(daily, func(i, j int) bool { return daily[i].Date.Before(daily[j].Date) })
+	return daily, nil
+}
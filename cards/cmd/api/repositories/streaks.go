@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+)
+
+type StreakRepository interface {
+	GetSettings(ctx context.Context, userID string) (models.StudySettings, error)
+	// SaveSettings stores the goal and the rollover hour, the timezone is only stored with a new user
+	SaveSettings(ctx context.Context, settings models.StudySettings) error
+	// SaveTimezone stores the timezone unless a newer one is stored, the rest is only stored with a new user
+	SaveTimezone(ctx context.Context, settings models.StudySettings) error
+
+	GetStreak(ctx context.Context, userID string) (models.Streak, error)
+	// SaveStreak stores the streak if its version still matches the stored one, 0 for a new streak,
+	// and increases the version, otherwise it returns ErrConflict
+	SaveStreak(ctx context.Context, streak *models.Streak) error
+}
+
+type memoryStreakRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryStreakRepository(store *MemoryStore) StreakRepository {
+	return &memoryStreakRepository{store: store}
+}
+
+func (r *memoryStreakRepository) GetSettings(ctx context.Context, userID string) (models.StudySettings, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	settings, ok := r.store.studySettings[userID]
+	if !ok {
+		return models.StudySettings{}, ErrNotFound
+	}
+	return settings, nil
+}
+
+func (r *memoryStreakRepository) SaveSettings(ctx context.Context, settings models.StudySettings) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if stored, ok := r.store.studySettings[settings.UserID]; ok {
+		settings.Timezone = stored.Timezone
+		settings.TimezoneUpdatedAt = stored.TimezoneUpdatedAt
+	}
+	r.store.studySettings[settings.UserID] = settings
+	return nil
+}
+
+func (r *memoryStreakRepository) SaveTimezone(ctx context.Context, settings models.StudySettings) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	stored, ok := r.store.studySettings[settings.UserID]
+	if !ok {
+		r.store.studySettings[settings.UserID] = settings
+		return nil
+	}
+	if stored.TimezoneUpdatedAt.Before(settings.TimezoneUpdatedAt) {
+		stored.Timezone = settings.Timezone
+		stored.TimezoneUpdatedAt = settings.TimezoneUpdatedAt
+		r.store.studySettings[settings.UserID] = stored
+	}
+	return nil
+}
+
+func (r *memoryStreakRepository) GetStreak(ctx context.Context, userID string) (models.Streak, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	streak, ok := r.store.streaks[userID]
+	if !ok {
+		return models.Streak{}, ErrNotFound
+	}
+	return streak, nil
+}
+
+func (r *memoryStreakRepository) SaveStreak(ctx context.Context, streak *models.Streak) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if r.store.streaks[streak.UserID].Version != streak.Version {
+		return ErrConflict
+	}
+	streak.Version++
+	r.store.streaks[streak.UserID] = *streak
+	return nil
+}
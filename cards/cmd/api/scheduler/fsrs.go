@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultFSRSWeights are the FSRS-4.5 default parameters
+var DefaultFSRSWeights = [17]float64{
+	0.4872, 1.4003, 3.7145, 13.8206, 5.1618, 1.2298, 0.8975, 0.031,
+	1.6474, 0.1367, 1.0461, 2.1072, 0.0793, 0.3246, 1.587, 0.2272, 2.8755,
+}
+
+const (
+	fsrsDecay = -0.5
+	// fsrsFactor makes the retrievability 0.9 when elapsed days equal the stability
+	fsrsFactor = 19.0 / 81.0
+
+	fsrsMinDifficulty = 1.0
+	fsrsMaxDifficulty = 10.0
+	fsrsMinStability  = 0.1
+)
+
+// FSRS implements the Free Spaced Repetition Scheduler, version 4.5, with day-based intervals
+type FSRS struct {
+	opts Options
+	w    [17]float64
+}
+
+func NewFSRS(opts Options, weights [17]float64) *FSRS {
+	return &FSRS{opts: opts, w: weights}
+}
+
+func (a *FSRS) Name() string {
+	return AlgorithmFSRS
+}
+
+func (a *FSRS) Schedule(state State, grade Grade, now time.Time) State {
+	next := state
+
+	// a card first scheduled by another algorithm has no memory model yet
+	if state.IsNew() || state.Stability == 0 {
+		next.Difficulty = a.initDifficulty(grade)
+		next.Stability = a.initStability(grade)
+	} else {
+		retrievability := a.retrievability(elapsedDays(state.LastReview, now), state.Stability)
+		next.Difficulty = a.nextDifficulty(state.Difficulty, grade)
+		if grade == Again {
+			next.Stability = a.nextForgetStability(state.Difficulty, state.Stability, retrievability)
+		} else {
+			next.Stability = a.nextRecallStability(state.Difficulty, state.Stability, retrievability, grade)
+		}
+	}
+
+	if grade == Again {
+		next.Repetitions = 0
+		if !state.IsNew() {
+			next.Lapses++
+		}
+	} else {
+		next.Repetitions++
+	}
+
+	next.IntervalDays = a.nextInterval(next.Stability)
+	next.LastReview = now
+	next.Due = dueAfter(now, next.IntervalDays)
+	return next
+}
+
+func (a *FSRS) initStability(grade Grade) float64 {
+	return math.Max(a.w[grade-1], fsrsMinStability)
+}
+
+func (a *FSRS) initDifficulty(grade Grade) float64 {
+	return clampDifficulty(a.w[4] - a.w[5]*float64(grade-3))
+}
+
+func (a *FSRS) retrievability(elapsedDays int, stability float64) float64 {
+	return math.Pow(1+fsrsFactor*float64(elapsedDays)/stability, fsrsDecay)
+}
+
+func (a *FSRS) nextInterval(stability float64) int {
+	interval := stability / fsrsFactor * (math.Pow(a.opts.DesiredRetention, 1/fsrsDecay) - 1)
+	return clampInterval(int(math.Round(interval)), a.opts.MaximumInterval)
+}
+
+func (a *FSRS) nextDifficulty(difficulty float64, grade Grade) float64 {
+	next := difficulty - a.w[6]*float64(grade-3)
+	// mean reversion towards the initial difficulty keeps cards from getting stuck at the bounds
+	return clampDifficulty(a.w[7]*a.w[4] + (1-a.w[7])*next)
+}
+
+func (a *FSRS) nextRecallStability(difficulty, stability, retrievability float64, grade Grade) float64 {
+	hardPenalty := 1.0
+	if grade == Hard {
+		hardPenalty = a.w[15]
+	}
+	easyBonus := 1.0
+	if grade == Easy {
+		easyBonus = a.w[16]
+	}
+	return stability * (1 + math.Exp(a.w[8])*
+		(11-difficulty)*
+		math.Pow(stability, -a.w[9])*
+		(math.Exp((1-retrievability)*a.w[10])-1)*
+		hardPenalty*
+		easyBonus)
+}
+
+func (a *FSRS) nextForgetStability(difficulty, stability, retrievability float64) float64 {
+	next := a.w[11] *
+		math.Pow(difficulty, -a.w[12]) *
+		(math.Pow(stability+1, a.w[13]) - 1) *
+		math.Exp((1-retrievability)*a.w[14])
+	// forgetting can never make a memory more stable than it was
+	return math.Max(math.Min(next, stability), fsrsMinStability)
+}
+
+func clampDifficulty(difficulty float64) float64 {
+	return math.Min(math.Max(difficulty, fsrsMinDifficulty), fsrsMaxDifficulty)
+}
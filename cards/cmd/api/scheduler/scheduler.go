@@ -0,0 +1,102 @@
+// Package scheduler decides when a card should be reviewed again.
+// Algorithms are pure functions of the previous state, the grade and the review time,
+// so the same history always produces the same schedule.
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	AlgorithmSM2  = "sm2"
+	AlgorithmFSRS = "fsrs"
+)
+
+// Grade is the answer quality reported by the user
+type Grade int
+
+const (
+	Again Grade = iota + 1
+	Hard
+	Good
+	Easy
+)
+
+func (g Grade) Valid() bool {
+	return g >= Again && g <= Easy
+}
+
+// State is the per-card review state shared by all algorithms,
+// each algorithm only reads and updates the fields it needs
+type State struct {
+	// Repetitions is the number of successful reviews in a row
+	Repetitions int
+	Lapses      int
+	// IntervalDays is the gap between LastReview and Due
+	IntervalDays int
+	// Ease is the SM-2 easiness factor
+	Ease float64
+	// Stability and Difficulty are the FSRS memory model parameters
+	Stability  float64
+	Difficulty float64
+	Due        time.Time
+	LastReview time.Time
+}
+
+// IsNew reports whether the card has never been reviewed
+func (s State) IsNew() bool {
+	return s.LastReview.IsZero()
+}
+
+// Algorithm computes the state after a review
+type Algorithm interface {
+	Name() string
+	Schedule(state State, grade Grade, now time.Time) State
+}
+
+type Options struct {
+	// DesiredRetention is the probability of recall FSRS aims for when a card becomes due
+	DesiredRetention float64
+	// MaximumInterval caps intervals, in days
+	MaximumInterval int
+}
+
+func DefaultOptions() Options {
+	return Options{DesiredRetention: 0.9, MaximumInterval: 36500}
+}
+
+// New returns the algorithm registered under the name
+func New(name string, opts Options) (Algorithm, error) {
+	switch name {
+	case AlgorithmSM2:
+		return NewSM2(opts), nil
+	case AlgorithmFSRS:
+		return NewFSRS(opts, DefaultFSRSWeights), nil
+	default:
+		return nil, fmt.Errorf("unknown scheduling algorithm %q", name)
+	}
+}
+
+// dueAfter returns the moment the card is due again after the interval
+func dueAfter(now time.Time, intervalDays int) time.Time {
+	return now.AddDate(0, 0, intervalDays)
+}
+
+// elapsedDays returns the number of whole days passed since the last review
+func elapsedDays(last, now time.Time) int {
+	if last.IsZero() || !now.After(last) {
+		return 0
+	}
+	return int(now.Sub(last) / (24 * time.Hour))
+}
+
+func clampInterval(days, maximum int) int {
+	if days < 1 {
+		return 1
+	}
+	if maximum > 0 && days > maximum {
+		return maximum
+	}
+	return days
+}
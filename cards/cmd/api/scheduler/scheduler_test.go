@@ -0,0 +1,191 @@
+package scheduler
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// step is a review made on the day the card is due, and the state it must produce
+type step struct {
+	grade        Grade
+	intervalDays int
+	repetitions  int
+	lapses       int
+	// ease is checked for SM-2, stability and difficulty for FSRS
+	ease       float64
+	stability  float64
+	difficulty float64
+}
+
+var start = time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC)
+
+// replay reviews a new card with the grades of the steps, each review on the day the previous one made it due
+func replay(t *testing.T, algorithm Algorithm, steps []step, check func(t *testing.T, i int, got State, want step)) {
+	t.Helper()
+	var state State
+	now := start
+	for i, want := range steps {
+		state = algorithm.Schedule(state, want.grade, now)
+		if state.IntervalDays != want.intervalDays {
+			t.Errorf("review %d: interval %d days, want %d", i+1, state.IntervalDays, want.intervalDays)
+		}
+		if state.Repetitions != want.repetitions || state.Lapses != want.lapses {
+			t.Errorf("review %d: %d repetitions and %d lapses, want %d and %d",
+				i+1, state.Repetitions, state.Lapses, want.repetitions, want.lapses)
+		}
+		if !state.LastReview.Equal(now) || !state.Due.Equal(now.AddDate(0, 0, want.intervalDays)) {
+			t.Errorf("review %d: reviewed %v and due %v, want %v and %d days later", i+1, state.LastReview, state.Due, now, want.intervalDays)
+		}
+		check(t, i, state, want)
+		now = state.Due
+	}
+}
+
+func TestSM2(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "good keeps the ease",
+			steps: []step{
+				{grade: Good, intervalDays: 1, repetitions: 1, ease: 2.5},
+				{grade: Good, intervalDays: 6, repetitions: 2, ease: 2.5},
+				{grade: Good, intervalDays: 15, repetitions: 3, ease: 2.5},
+				{grade: Good, intervalDays: 38, repetitions: 4, ease: 2.5},
+			},
+		},
+		{
+			name: "easy raises the ease",
+			steps: []step{
+				{grade: Easy, intervalDays: 1, repetitions: 1, ease: 2.6},
+				{grade: Easy, intervalDays: 6, repetitions: 2, ease: 2.7},
+				{grade: Easy, intervalDays: 16, repetitions: 3, ease: 2.8},
+				{grade: Easy, intervalDays: 45, repetitions: 4, ease: 2.9},
+			},
+		},
+		{
+			name: "hard lowers the ease down to its minimum",
+			steps: []step{
+				{grade: Hard, intervalDays: 1, repetitions: 1, ease: 2.36},
+				{grade: Hard, intervalDays: 6, repetitions: 2, ease: 2.22},
+				{grade: Hard, intervalDays: 13, repetitions: 3, ease: 2.08},
+				{grade: Hard, intervalDays: 27, repetitions: 4, ease: 1.94},
+				{grade: Hard, intervalDays: 52, repetitions: 5, ease: 1.8},
+				{grade: Hard, intervalDays: 94, repetitions: 6, ease: 1.66},
+				{grade: Hard, intervalDays: 156, repetitions: 7, ease: 1.52},
+				{grade: Hard, intervalDays: 237, repetitions: 8, ease: 1.38},
+				{grade: Hard, intervalDays: 327, repetitions: 9, ease: 1.3},
+			},
+		},
+		{
+			name: "a lapse restarts the repetitions and keeps the ease",
+			steps: []step{
+				{grade: Again, intervalDays: 1, repetitions: 0, ease: 2.5},
+				{grade: Good, intervalDays: 1, repetitions: 1, ease: 2.5},
+				{grade: Good, intervalDays: 6, repetitions: 2, ease: 2.5},
+				{grade: Again, intervalDays: 1, repetitions: 0, lapses: 1, ease: 2.5},
+				{grade: Good, intervalDays: 1, repetitions: 1, lapses: 1, ease: 2.5},
+				{grade: Easy, intervalDays: 6, repetitions: 2, lapses: 1, ease: 2.6},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replay(t, NewSM2(DefaultOptions()), tt.steps, func(t *testing.T, i int, got State, want step) {
+				if got.Ease != want.ease {
+					t.Errorf("review %d: ease %v, want %v", i+1, got.Ease, want.ease)
+				}
+			})
+		})
+	}
+}
+
+// The FSRS expectations were computed with the FSRS-4.5 formulas and default weights at 90% retention
+func TestFSRS(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "good",
+			steps: []step{
+				{grade: Good, intervalDays: 4, repetitions: 1, stability: 3.7145, difficulty: 5.1618},
+				{grade: Good, intervalDays: 15, repetitions: 2, stability: 14.8081, difficulty: 5.1618},
+				{grade: Good, intervalDays: 49, repetitions: 3, stability: 49.4616, difficulty: 5.1618},
+				{grade: Good, intervalDays: 146, repetitions: 4, stability: 145.6706, difficulty: 5.1618},
+			},
+		},
+		{
+			name: "lapse",
+			steps: []step{
+				{grade: Good, intervalDays: 4, repetitions: 1, stability: 3.7145, difficulty: 5.1618},
+				{grade: Good, intervalDays: 15, repetitions: 2, stability: 14.8081, difficulty: 5.1618},
+				{grade: Again, intervalDays: 3, repetitions: 0, lapses: 1, stability: 3.1493, difficulty: 6.9012},
+				{grade: Good, intervalDays: 9, repetitions: 1, lapses: 1, stability: 9.1982, difficulty: 6.8472},
+			},
+		},
+		{
+			name: "easy and hard",
+			steps: []step{
+				{grade: Easy, intervalDays: 14, repetitions: 1, stability: 13.8206, difficulty: 3.932},
+				{grade: Hard, intervalDays: 23, repetitions: 2, stability: 22.8012, difficulty: 4.8398},
+				{grade: Easy, intervalDays: 175, repetitions: 3, stability: 174.8456, difficulty: 3.9801},
+			},
+		},
+		{
+			name: "failed first review",
+			steps: []step{
+				{grade: Again, intervalDays: 1, repetitions: 0, stability: 0.4872, difficulty: 7.6214},
+				{grade: Good, intervalDays: 2, repetitions: 1, stability: 2.4225, difficulty: 7.5452},
+				{grade: Good, intervalDays: 6, repetitions: 2, stability: 5.9911, difficulty: 7.4713},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replay(t, NewFSRS(DefaultOptions(), DefaultFSRSWeights), tt.steps, func(t *testing.T, i int, got State, want step) {
+				if math.Abs(got.Stability-want.stability) > 1e-4 || math.Abs(got.Difficulty-want.difficulty) > 1e-4 {
+					t.Errorf("review %d: stability %.4f and difficulty %.4f, want %.4f and %.4f",
+						i+1, got.Stability, got.Difficulty, want.stability, want.difficulty)
+				}
+			})
+		})
+	}
+}
+
+func TestFSRSDesiredRetention(t *testing.T) {
+	// at 80% retention the interval after the first good review grows from 4 to 9 days
+	fsrs := NewFSRS(Options{DesiredRetention: 0.8, MaximumInterval: 36500}, DefaultFSRSWeights)
+	if got := fsrs.Schedule(State{}, Good, start).IntervalDays; got != 9 {
+		t.Errorf("interval at 80%% retention = %d days, want 9", got)
+	}
+}
+
+func TestMaximumInterval(t *testing.T) {
+	opts := Options{DesiredRetention: 0.9, MaximumInterval: 10}
+	for _, algorithm := range []Algorithm{NewSM2(opts), NewFSRS(opts, DefaultFSRSWeights)} {
+		var state State
+		now := start
+		for i := 0; i < 6; i++ {
+			state = algorithm.Schedule(state, Easy, now)
+			now = state.Due
+		}
+		if state.IntervalDays != 10 {
+			t.Errorf("%s: interval %d days, want the maximum of 10", algorithm.Name(), state.IntervalDays)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, name := range []string{AlgorithmSM2, AlgorithmFSRS} {
+		algorithm, err := New(name, DefaultOptions())
+		if err != nil || algorithm.Name() != name {
+			t.Errorf("New(%q) = %v, %v", name, algorithm, err)
+		}
+	}
+	if _, err := New("leitner", DefaultOptions()); err == nil {
+		t.Error("New accepted an unknown algorithm")
+	}
+}
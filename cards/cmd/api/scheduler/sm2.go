@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	sm2InitialEase = 2.5
+	sm2MinimumEase = 1.3
+)
+
+// SM2 implements the SuperMemo 2 algorithm.
+// Grades map onto SM-2 qualities: again 1, hard 3, good 4, easy 5.
+type SM2 struct {
+	opts Options
+}
+
+func NewSM2(opts Options) *SM2 {
+	return &SM2{opts: opts}
+}
+
+func (a *SM2) Name() string {
+	return AlgorithmSM2
+}
+
+func (a *SM2) Schedule(state State, grade Grade, now time.Time) State {
+	next := state
+	if next.Ease == 0 {
+		next.Ease = sm2InitialEase
+	}
+
+	quality := sm2Quality(grade)
+	if quality < 3 {
+		// a failed recall restarts the repetitions but keeps the ease
+		next.Repetitions = 0
+		next.IntervalDays = 1
+		if !state.IsNew() {
+			next.Lapses++
+		}
+	} else {
+		switch next.Repetitions {
+		case 0:
+			next.IntervalDays = 1
+		case 1:
+			next.IntervalDays = 6
+		default:
+			next.IntervalDays = int(math.Round(float64(next.IntervalDays) * next.Ease))
+		}
+		next.Repetitions++
+		next.Ease = sm2NextEase(next.Ease, quality)
+	}
+
+	next.IntervalDays = clampInterval(next.IntervalDays, a.opts.MaximumInterval)
+	next.LastReview = now
+	next.Due = dueAfter(now, next.IntervalDays)
+	return next
+}
+
+func sm2Quality(grade Grade) int {
+	switch grade {
+	case Again:
+		return 1
+	case Hard:
+		return 3
+	case Good:
+		return 4
+	default:
+		return 5
+	}
+}
+
+func sm2NextEase(ease float64, quality int) float64 {
+	q := float64(5 - quality)
+	ease += 0.1 - q*(0.08+q*0.02)
+	// keep two decimals so the stored value doesn't accumulate float noise
+	ease = math.Round(ease*100) / 100
+	return math.Max(ease, sm2MinimumEase)
+}
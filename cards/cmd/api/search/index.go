@@ -0,0 +1,446 @@
+// Package search keeps an embedded full-text index of decks and cards.
+//
+// Text fields are indexed once as plain lower-cased words and once per configured language with stemming,
+// so "running" finds "runs" while exact matches score higher for matching twice.
+// The index is fed from deck and card events and only ever answers queries of the owner.
+// Members of a shared deck don't find it, documents carry the owner alone and memberships are not indexed.
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search/highlight/format/html"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Kinds of indexed documents
+const (
+	KindDeck = "deck"
+	KindCard = "card"
+)
+
+// mappingKey stores the mapping version and the languages of an index, see Open
+const mappingKey = "mapping"
+
+// highlightStart opens a match in the snippets of the html highlighter
+const highlightStart = "<mark>"
+
+// deleteBatchSize bounds the cards removed in one batch when their deck is deleted
+const deleteBatchSize = 500
+
+type Deck struct {
+	ID          string
+	OwnerID     string
+	Title       string
+	Description string
+	Tags        []string
+	// UpdatedAt is a unix timestamp, older versions never replace newer ones
+	UpdatedAt int64
+}
+
+type Card struct {
+	ID        string
+	DeckID    string
+	OwnerID   string
+	Front     string
+	Back      string
+	Tags      []string
+	UpdatedAt int64
+}
+
+type Index struct {
+	index     bleve.Index
+	languages []string
+	// mu serializes writes, so comparing versions and indexing a document happen atomically
+	mu sync.Mutex
+}
+
+// Open opens the index at path, or creates it when it doesn't exist yet or was built for other languages.
+// An empty path keeps the index in memory. created reports a new, empty index, the caller fills it.
+func Open(path string, languages []string) (index *Index, created bool, err error) {
+	if err := checkLanguages(languages); err != nil {
+		return nil, false, err
+	}
+	indexMapping, err := newMapping(languages)
+	if err != nil {
+		return nil, false, err
+	}
+	version := mappingVersion + ":" + strings.Join(languages, ",")
+
+	if path == "" {
+		memIndex, err := bleve.NewMemOnly(indexMapping)
+		if err != nil {
+			return nil, false, err
+		}
+		return &Index{index: memIndex, languages: languages}, true, nil
+	}
+
+	existing, err := bleve.Open(path)
+	switch {
+	case err == nil:
+		stored, err := existing.GetInternal([]byte(mappingKey))
+		if err == nil && string(stored) == version {
+			return &Index{index: existing, languages: languages}, false, nil
+		}
+		existing.Close()
+		// built by another version or for other languages, it's rebuilt from scratch
+		if err := os.RemoveAll(path); err != nil {
+			return nil, false, err
+		}
+	case !errors.Is(err, bleve.ErrorIndexPathDoesNotExist):
+		return nil, false, fmt.Errorf("failed to open search index: %w", err)
+	}
+
+	newIndex, err := bleve.New(path, indexMapping)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create search index: %w", err)
+	}
+	if err := newIndex.SetInternal([]byte(mappingKey), []byte(version)); err != nil {
+		newIndex.Close()
+		return nil, false, err
+	}
+	return &Index{index: newIndex, languages: languages}, true, nil
+}
+
+func (i *Index) Close() error {
+	return i.index.Close()
+}
+
+func deckDocID(id string) string {
+	return KindDeck + ":" + id
+}
+
+func cardDocID(id string) string {
+	return KindCard + ":" + id
+}
+
+// IndexDeck adds the deck or replaces an older version of it
+func (i *Index) IndexDeck(deck Deck) error {
+	return i.put(deckDocID(deck.ID), deck.UpdatedAt, map[string]any{
+		fieldKind:        KindDeck,
+		fieldOwnerID:     deck.OwnerID,
+		fieldDeckID:      deck.ID,
+		fieldTitle:       deck.Title,
+		fieldDescription: deck.Description,
+		fieldTags:        deck.Tags,
+		fieldUpdatedAt:   float64(deck.UpdatedAt),
+	})
+}
+
+// IndexCard adds the card or replaces an older version of it
+func (i *Index) IndexCard(card Card) error {
+	return i.put(cardDocID(card.ID), card.UpdatedAt, map[string]any{
+		fieldKind:      KindCard,
+		fieldOwnerID:   card.OwnerID,
+		fieldDeckID:    card.DeckID,
+		fieldFront:     card.Front,
+		fieldBack:      card.Back,
+		fieldTags:      card.Tags,
+		fieldUpdatedAt: float64(card.UpdatedAt),
+	})
+}
+
+// put indexes the document unless the index holds a newer version, events may arrive out of order when retried
+func (i *Index) put(id string, updatedAt int64, document map[string]any) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	indexed, err := i.updatedAt(id)
+	if err != nil {
+		return err
+	}
+	if indexed > updatedAt {
+		return nil
+	}
+	return i.index.Index(id, document)
+}
+
+// updatedAt returns the version of an indexed document, -1 when it isn't indexed
+func (i *Index) updatedAt(id string) (int64, error) {
+	req := bleve.NewSearchRequest(bleve.NewDocIDQuery([]string{id}))
+	req.Fields = []string{fieldUpdatedAt}
+	res, err := i.index.Search(req)
+	if err != nil {
+		return 0, err
+	}
+	if len(res.Hits) == 0 {
+		return -1, nil
+	}
+	updatedAt, _ := res.Hits[0].Fields[fieldUpdatedAt].(float64)
+	return int64(updatedAt), nil
+}
+
+func (i *Index) DeleteCard(id string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.index.Delete(cardDocID(id))
+}
+
+// DeleteDeck removes the deck along with its cards
+func (i *Index) DeleteDeck(id string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	deckCards := bleve.NewConjunctionQuery(termQuery(fieldDeckID, id), termQuery(fieldKind, KindCard))
+	for {
+		req := bleve.NewSearchRequestOptions(deckCards, deleteBatchSize, 0, false)
+		res, err := i.index.Search(req)
+		if err != nil {
+			return err
+		}
+		if len(res.Hits) == 0 {
+			break
+		}
+		batch := i.index.NewBatch()
+		for _, hit := range res.Hits {
+			batch.Delete(hit.ID)
+		}
+		if err := i.index.Batch(batch); err != nil {
+			return err
+		}
+	}
+	return i.index.Delete(deckDocID(id))
+}
+
+// Count returns the number of indexed decks and cards
+func (i *Index) Count() (uint64, error) {
+	return i.index.DocCount()
+}
+
+type Query struct {
+	OwnerID string
+	Text    string
+	// Kind limits the hits to decks or cards, both are searched when empty
+	Kind string
+	// DeckID limits the hits to the deck and its cards
+	DeckID string
+	Limit  int
+	Offset int
+}
+
+type Hit struct {
+	Kind        string
+	ID          string
+	DeckID      string
+	Score       float64
+	Title       string
+	Description string
+	Front       string
+	Back        string
+	Tags        []string
+	// Highlights maps fields to a snippet of their text around the matches, HTML-escaped with the matches in <mark>
+	Highlights map[string]string
+}
+
+type Result struct {
+	Hits  []Hit
+	Total int
+}
+
+// Search returns the owner's decks and cards matching any word of the text, best matches first
+func (i *Index) Search(ctx context.Context, q Query) (Result, error) {
+	var text []query.Query
+	for _, field := range textFields {
+		text = append(text, matchQuery(field.name, plainAnalyzer, q.Text, field.boost))
+		for _, language := range i.languages {
+			text = append(text, matchQuery(languageField(field.name, language), analyzers[language], q.Text, field.boost))
+		}
+	}
+	req := bleve.NewSearchRequestOptions(i.ownerQuery(q.OwnerID, q.Kind, q.DeckID, bleve.NewDisjunctionQuery(text...)),
+		q.Limit, q.Offset, false)
+	req.Fields = storedFields
+	req.Highlight = bleve.NewHighlightWithStyle(html.Name)
+	for _, field := range textFields {
+		req.Highlight.AddField(field.name)
+		for _, language := range i.languages {
+			req.Highlight.AddField(languageField(field.name, language))
+		}
+	}
+
+	res, err := i.index.SearchInContext(ctx, req)
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{Hits: make([]Hit, 0, len(res.Hits)), Total: int(res.Total)}
+	for _, match := range res.Hits {
+		kind, id, _ := strings.Cut(match.ID, ":")
+		hit := Hit{
+			Kind:        kind,
+			ID:          id,
+			DeckID:      stringField(match.Fields[fieldDeckID]),
+			Score:       match.Score,
+			Title:       stringField(match.Fields[fieldTitle]),
+			Description: stringField(match.Fields[fieldDescription]),
+			Front:       stringField(match.Fields[fieldFront]),
+			Back:        stringField(match.Fields[fieldBack]),
+			Tags:        stringsField(match.Fields[fieldTags]),
+			Highlights:  make(map[string]string),
+		}
+		for _, field := range textFields {
+			if snippet := i.snippet(match.Fragments, field.name); snippet != "" {
+				hit.Highlights[field.name] = snippet
+			}
+		}
+		result.Hits = append(result.Hits, hit)
+	}
+	return result, nil
+}
+
+// snippet picks the fragment of the plain field, or of the first language that matched.
+// Fields without matches come back from bleve as their leading text, they get no snippet.
+func (i *Index) snippet(fragments map[string][]string, field string) string {
+	names := []string{field}
+	for _, language := range i.languages {
+		names = append(names, languageField(field, language))
+	}
+	for _, name := range names {
+		for _, fragment := range fragments[name] {
+			if strings.Contains(fragment, highlightStart) {
+				return fragment
+			}
+		}
+	}
+	return ""
+}
+
+// Autocomplete completes the last word of the text with the owner's deck titles, card fronts and tags.
+// Earlier words must match exactly, suggestions come best match first without duplicates.
+func (i *Index) Autocomplete(ctx context.Context, ownerID, text string, limit int) ([]string, error) {
+	words, err := i.words(text)
+	if err != nil || len(words) == 0 {
+		return []string{}, err
+	}
+	prefix := words[len(words)-1]
+
+	conditions := []query.Query{fieldsQuery(func(field string) query.Query {
+		prefixQuery := bleve.NewPrefixQuery(prefix)
+		prefixQuery.SetField(field)
+		return prefixQuery
+	})}
+	for _, word := range words[:len(words)-1] {
+		conditions = append(conditions, fieldsQuery(func(field string) query.Query {
+			return termQuery(field, word)
+		}))
+	}
+	// more hits than suggestions, several cards often share a front
+	req := bleve.NewSearchRequestOptions(i.ownerQuery(ownerID, "", "", bleve.NewConjunctionQuery(conditions...)),
+		limit*3, 0, false)
+	req.Fields = []string{fieldTitle, fieldFront, fieldTags}
+	req.IncludeLocations = true
+	res, err := i.index.SearchInContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]string, 0, limit)
+	seen := make(map[string]bool)
+	for _, hit := range res.Hits {
+		matched := make(map[string]bool, len(hit.Locations))
+		for field := range hit.Locations {
+			matched[field] = true
+		}
+		suggestion := suggestionOf(hit.Fields, matched, prefix)
+		key := strings.ToLower(suggestion)
+		if suggestion == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		suggestions = append(suggestions, suggestion)
+		if len(suggestions) == limit {
+			break
+		}
+	}
+	return suggestions, nil
+}
+
+// suggestionOf returns the title or front of a hit, or its tag when only a tag starts with the prefix
+func suggestionOf(fields map[string]any, matched map[string]bool, prefix string) string {
+	for _, field := range []string{fieldTitle, fieldFront} {
+		if matched[field] {
+			return stringField(fields[field])
+		}
+	}
+	for _, tag := range stringsField(fields[fieldTags]) {
+		for _, word := range strings.Fields(strings.ToLower(tag)) {
+			if strings.HasPrefix(word, prefix) {
+				return tag
+			}
+		}
+	}
+	return ""
+}
+
+// words splits the text the way the plain fields are indexed
+func (i *Index) words(text string) ([]string, error) {
+	analyzer := i.index.Mapping().AnalyzerNamed(plainAnalyzer)
+	if analyzer == nil {
+		return nil, fmt.Errorf("analyzer %s is not registered", plainAnalyzer)
+	}
+	tokens := analyzer.Analyze([]byte(text))
+	words := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		words = append(words, string(token.Term))
+	}
+	return words, nil
+}
+
+// ownerQuery limits a query to the owner's documents, optionally of one kind and deck.
+// Decks shared with the user are left out, see the package doc.
+func (i *Index) ownerQuery(ownerID, kind, deckID string, text query.Query) query.Query {
+	conditions := []query.Query{termQuery(fieldOwnerID, ownerID), text}
+	if kind != "" {
+		conditions = append(conditions, termQuery(fieldKind, kind))
+	}
+	if deckID != "" {
+		conditions = append(conditions, termQuery(fieldDeckID, deckID))
+	}
+	return bleve.NewConjunctionQuery(conditions...)
+}
+
+// fieldsQuery matches when the query built for any of the suggest fields does
+func fieldsQuery(build func(field string) query.Query) query.Query {
+	queries := make([]query.Query, 0, len(suggestFields))
+	for _, field := range suggestFields {
+		queries = append(queries, build(field))
+	}
+	return bleve.NewDisjunctionQuery(queries...)
+}
+
+func termQuery(field, term string) query.Query {
+	termQuery := bleve.NewTermQuery(term)
+	termQuery.SetField(field)
+	return termQuery
+}
+
+// matchQuery analyzes the text like the field, bleve would pick the analyzer of the property for stemmed copies
+func matchQuery(field, analyzer, text string, boost float64) query.Query {
+	matchQuery := bleve.NewMatchQuery(text)
+	matchQuery.SetField(field)
+	matchQuery.Analyzer = analyzer
+	matchQuery.SetBoost(boost)
+	return matchQuery
+}
+
+func stringField(value any) string {
+	text, _ := value.(string)
+	return text
+}
+
+// stringsField reads a stored array, bleve returns a single value as a plain string
+func stringsField(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, stringField(item))
+		}
+		return values
+	default:
+		return []string{}
+	}
+}
@@ -0,0 +1,260 @@
+package search
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func openTestIndex(t *testing.T, languages ...string) *Index {
+	t.Helper()
+	index, created, err := Open("", languages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created {
+		t.Error("an in-memory index is reported as existing")
+	}
+	t.Cleanup(func() { index.Close() })
+	return index
+}
+
+func seed(t *testing.T, index *Index, decks []Deck, cards []Card) {
+	t.Helper()
+	for _, deck := range decks {
+		if err := index.IndexDeck(deck); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, card := range cards {
+		if err := index.IndexCard(card); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func search(t *testing.T, index *Index, q Query) Result {
+	t.Helper()
+	if q.Limit == 0 {
+		q.Limit = 10
+	}
+	result, err := index.Search(context.Background(), q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func hitIDs(result Result) []string {
+	ids := make([]string, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		ids = append(ids, hit.Kind+":"+hit.ID)
+	}
+	return ids
+}
+
+func TestSearchStemsConfiguredLanguages(t *testing.T) {
+	index := openTestIndex(t, "en", "de")
+	seed(t, index, nil, []Card{
+		{ID: "run", DeckID: "d", OwnerID: "ann", Front: "He runs every morning", Back: "Er läuft jeden Morgen", UpdatedAt: 1},
+		{ID: "house", DeckID: "d", OwnerID: "ann", Front: "the houses", Back: "die Häuser", UpdatedAt: 1},
+	})
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"running", []string{"card:run"}},
+		{"house", []string{"card:house"}},
+		// the German stemmer folds the umlaut plural to the singular
+		{"Haus", []string{"card:house"}},
+		{"nothing", []string{}},
+	}
+	for _, tt := range tests {
+		if got := hitIDs(search(t, index, Query{OwnerID: "ann", Text: tt.text})); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("search %q found %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSearchRanksTitlesAndExactMatchesFirst(t *testing.T) {
+	index := openTestIndex(t, "en")
+	seed(t, index,
+		[]Deck{{ID: "verbs", OwnerID: "ann", Title: "Irregular verbs", UpdatedAt: 1}},
+		[]Card{
+			{ID: "stemmed", DeckID: "other", OwnerID: "ann", Front: "verbal", Back: "spoken verbally", UpdatedAt: 1},
+			{ID: "back", DeckID: "other", OwnerID: "ann", Front: "go", Back: "an irregular verb", UpdatedAt: 1},
+		})
+	got := hitIDs(search(t, index, Query{OwnerID: "ann", Text: "irregular verbs"}))
+	if len(got) < 2 || got[0] != "deck:verbs" || got[1] != "card:back" {
+		t.Errorf("hits %v, want the deck title before the card back", got)
+	}
+}
+
+func TestSearchIsLimitedToOwnerKindAndDeck(t *testing.T) {
+	index := openTestIndex(t, "en")
+	seed(t, index,
+		[]Deck{
+			{ID: "ann-deck", OwnerID: "ann", Title: "Capitals", UpdatedAt: 1},
+			{ID: "bob-deck", OwnerID: "bob", Title: "Capitals", UpdatedAt: 1},
+		},
+		[]Card{
+			{ID: "ann-card", DeckID: "ann-deck", OwnerID: "ann", Front: "Capital of France", Back: "Paris", UpdatedAt: 1},
+			{ID: "ann-other", DeckID: "ann-other-deck", OwnerID: "ann", Front: "Capital of Spain", Back: "Madrid", UpdatedAt: 1},
+			{ID: "bob-card", DeckID: "bob-deck", OwnerID: "bob", Front: "Capital of Italy", Back: "Rome", UpdatedAt: 1},
+		})
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"owner", Query{OwnerID: "ann", Text: "capital"}, []string{"card:ann-card", "card:ann-other", "deck:ann-deck"}},
+		{"kind", Query{OwnerID: "ann", Text: "capital", Kind: KindDeck}, []string{"deck:ann-deck"}},
+		{"deck", Query{OwnerID: "ann", Text: "capital", DeckID: "ann-deck", Kind: KindCard}, []string{"card:ann-card"}},
+		{"other owner", Query{OwnerID: "carl", Text: "capital"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hitIDs(search(t, index, tt.query))
+			if len(got) != len(tt.want) {
+				t.Fatalf("hits %v, want %v", got, tt.want)
+			}
+			for _, id := range tt.want {
+				if !strings.Contains(strings.Join(got, " "), id) {
+					t.Errorf("hits %v, want %s among them", got, id)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchHighlightsEscapedSnippets(t *testing.T) {
+	index := openTestIndex(t, "en")
+	seed(t, index, nil, []Card{
+		{ID: "tag", DeckID: "d", OwnerID: "ann", Front: "<b>bold</b> claims", Back: "nothing", Tags: []string{"html"}, UpdatedAt: 1},
+	})
+	result := search(t, index, Query{OwnerID: "ann", Text: "claim"})
+	if len(result.Hits) != 1 {
+		t.Fatalf("hits %v", hitIDs(result))
+	}
+	hit := result.Hits[0]
+	if want := "&lt;b&gt;bold&lt;/b&gt; <mark>claims</mark>"; hit.Highlights[fieldFront] != want {
+		t.Errorf("front snippet %q, want %q", hit.Highlights[fieldFront], want)
+	}
+	if _, ok := hit.Highlights[fieldBack]; ok {
+		t.Errorf("snippet of a field without matches: %v", hit.Highlights)
+	}
+	if hit.Front != "<b>bold</b> claims" || !reflect.DeepEqual(hit.Tags, []string{"html"}) {
+		t.Errorf("stored fields %+v", hit)
+	}
+}
+
+func TestAutocomplete(t *testing.T) {
+	index := openTestIndex(t, "en")
+	seed(t, index,
+		[]Deck{{ID: "verbs", OwnerID: "ann", Title: "Irregular verbs", UpdatedAt: 1}},
+		[]Card{
+			{ID: "1", DeckID: "verbs", OwnerID: "ann", Front: "Irish whiskey", Back: "", UpdatedAt: 1},
+			{ID: "2", DeckID: "verbs", OwnerID: "ann", Front: "Irish whiskey", Back: "", UpdatedAt: 1},
+			{ID: "3", DeckID: "verbs", OwnerID: "ann", Front: "go", Back: "went", Tags: []string{"irony"}, UpdatedAt: 1},
+			{ID: "4", DeckID: "bob", OwnerID: "bob", Front: "Iris", Back: "", UpdatedAt: 1},
+		})
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"ir", []string{"Irish whiskey", "Irregular verbs", "irony"}},
+		{"irregular ve", []string{"Irregular verbs"}},
+		{"WHISK", []string{"Irish whiskey"}},
+		{"  ", []string{}},
+	}
+	for _, tt := range tests {
+		got, err := index.Autocomplete(context.Background(), "ann", tt.text, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("autocomplete %q returned %v, want %v", tt.text, got, tt.want)
+			continue
+		}
+		for _, suggestion := range tt.want {
+			if !strings.Contains(strings.Join(got, "|"), suggestion) {
+				t.Errorf("autocomplete %q returned %v, want %q among them", tt.text, got, suggestion)
+			}
+		}
+	}
+}
+
+func TestStaleUpdatesAreIgnored(t *testing.T) {
+	index := openTestIndex(t, "en")
+	seed(t, index, nil, []Card{
+		{ID: "1", DeckID: "d", OwnerID: "ann", Front: "newer", UpdatedAt: 20},
+		// a retried event of an earlier version
+		{ID: "1", DeckID: "d", OwnerID: "ann", Front: "older", UpdatedAt: 10},
+	})
+	if got := hitIDs(search(t, index, Query{OwnerID: "ann", Text: "older"})); len(got) != 0 {
+		t.Errorf("a stale version replaced the card: %v", got)
+	}
+	if got := hitIDs(search(t, index, Query{OwnerID: "ann", Text: "newer"})); len(got) != 1 {
+		t.Errorf("the newer version is gone: %v", got)
+	}
+}
+
+func TestDeleteDeckRemovesItsCards(t *testing.T) {
+	index := openTestIndex(t, "en")
+	seed(t, index,
+		[]Deck{{ID: "d", OwnerID: "ann", Title: "Planets", UpdatedAt: 1}},
+		[]Card{
+			{ID: "1", DeckID: "d", OwnerID: "ann", Front: "planet Mars", UpdatedAt: 1},
+			{ID: "2", DeckID: "other", OwnerID: "ann", Front: "planet Venus", UpdatedAt: 1},
+		})
+	if err := index.DeleteDeck("d"); err != nil {
+		t.Fatal(err)
+	}
+	if got := hitIDs(search(t, index, Query{OwnerID: "ann", Text: "planet"})); !reflect.DeepEqual(got, []string{"card:2"}) {
+		t.Errorf("hits after deleting the deck %v", got)
+	}
+	if err := index.DeleteCard("2"); err != nil {
+		t.Fatal(err)
+	}
+	if count, err := index.Count(); err != nil || count != 0 {
+		t.Errorf("%d documents left, err %v", count, err)
+	}
+}
+
+func TestOpenRebuildsIndexOfOtherLanguages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search.bleve")
+	open := func(languages ...string) (*Index, bool) {
+		index, created, err := Open(path, languages)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return index, created
+	}
+
+	index, created := open("en")
+	if !created {
+		t.Error("a new index is reported as existing")
+	}
+	seed(t, index, []Deck{{ID: "d", OwnerID: "ann", Title: "Planets", UpdatedAt: 1}}, nil)
+	index.Close()
+
+	index, created = open("en")
+	if count, _ := index.Count(); created || count != 1 {
+		t.Errorf("reopened index created %v with %d documents", created, count)
+	}
+	index.Close()
+
+	index, created = open("en", "de")
+	defer index.Close()
+	if count, _ := index.Count(); !created || count != 0 {
+		t.Errorf("index of other languages reopened, created %v with %d documents", created, count)
+	}
+}
+
+func TestOpenRejectsUnknownLanguages(t *testing.T) {
+	if _, _, err := Open("", []string{"en", "xx"}); err == nil || !strings.Contains(err.Error(), `"xx"`) {
+		t.Errorf("open returned %v, want an unsupported language", err)
+	}
+}
@@ -0,0 +1,146 @@
+package search
+
+import (
+	"fmt"
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/v2/analysis/lang/cjk"
+	"github.com/blevesearch/bleve/v2/analysis/lang/de"
+	"github.com/blevesearch/bleve/v2/analysis/lang/en"
+	"github.com/blevesearch/bleve/v2/analysis/lang/es"
+	"github.com/blevesearch/bleve/v2/analysis/lang/fr"
+	"github.com/blevesearch/bleve/v2/analysis/lang/it"
+	"github.com/blevesearch/bleve/v2/analysis/lang/nl"
+	"github.com/blevesearch/bleve/v2/analysis/lang/pt"
+	"github.com/blevesearch/bleve/v2/analysis/lang/ru"
+	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/v2/analysis/token/unicodenorm"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/unicode"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"sort"
+	"strings"
+)
+
+// mappingVersion changes whenever the mapping does, an index built with another mapping is rebuilt
+const mappingVersion = "1"
+
+// plainAnalyzer splits text into lower-cased words without stemming or stop words,
+// it matches exact words and the prefixes typed into autocomplete
+const plainAnalyzer = "plain"
+
+// nfkcFilter folds compatibility characters like full-width letters before lower-casing
+const nfkcFilter = "nfkc"
+
+// analyzers maps the supported languages to the bleve analyzers stemming them
+var analyzers = map[string]string{
+	"cjk": cjk.AnalyzerName,
+	"de":  de.AnalyzerName,
+	"en":  en.AnalyzerName,
+	"es":  es.AnalyzerName,
+	"fr":  fr.AnalyzerName,
+	"it":  it.AnalyzerName,
+	"nl":  nl.AnalyzerName,
+	"pt":  pt.AnalyzerName,
+	"ru":  ru.AnalyzerName,
+}
+
+// Document fields, text fields are additionally indexed as "<field>_<language>" for each language
+const (
+	fieldKind        = "kind"
+	fieldOwnerID     = "owner_id"
+	fieldDeckID      = "deck_id"
+	fieldTitle       = "title"
+	fieldDescription = "description"
+	fieldFront       = "front"
+	fieldBack        = "back"
+	fieldTags        = "tags"
+	fieldUpdatedAt   = "updated_at"
+)
+
+// textFields are searched, the boost ranks matches in titles and fronts above the rest
+var textFields = []struct {
+	name  string
+	boost float64
+}{
+	{fieldTitle, 3},
+	{fieldFront, 2},
+	{fieldTags, 1.5},
+	{fieldDescription, 1},
+	{fieldBack, 1},
+}
+
+// suggestFields complete prefixes in autocomplete
+var suggestFields = []string{fieldTitle, fieldFront, fieldTags}
+
+// storedFields come back with every hit
+var storedFields = []string{fieldKind, fieldDeckID, fieldTitle, fieldDescription, fieldFront, fieldBack, fieldTags}
+
+func checkLanguages(languages []string) error {
+	for _, language := range languages {
+		if _, ok := analyzers[language]; !ok {
+			supported := make([]string, 0, len(analyzers))
+			for name := range analyzers {
+				supported = append(supported, name)
+			}
+			sort.Strings(supported)
+			return fmt.Errorf("unsupported search language %q, supported are %s", language, strings.Join(supported, ", "))
+		}
+	}
+	return nil
+}
+
+// languageField names the stemmed copy of a text field
+func languageField(field, language string) string {
+	return field + "_" + language
+}
+
+func newMapping(languages []string) (mapping.IndexMapping, error) {
+	indexMapping := bleve.NewIndexMapping()
+	err := indexMapping.AddCustomTokenFilter(nfkcFilter, map[string]any{"type": unicodenorm.Name, "form": unicodenorm.NFKC})
+	if err != nil {
+		return nil, err
+	}
+	err = indexMapping.AddCustomAnalyzer(plainAnalyzer, map[string]any{
+		"type":          custom.Name,
+		"tokenizer":     unicode.Name,
+		"token_filters": []any{nfkcFilter, lowercase.Name},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	document := bleve.NewDocumentStaticMapping()
+	for _, field := range []string{fieldKind, fieldOwnerID, fieldDeckID} {
+		keywordField := bleve.NewKeywordFieldMapping()
+		keywordField.Analyzer = keyword.Name
+		keywordField.IncludeInAll = false
+		document.AddFieldMappingsAt(field, keywordField)
+	}
+	updatedAt := bleve.NewNumericFieldMapping()
+	updatedAt.IncludeInAll = false
+	document.AddFieldMappingsAt(fieldUpdatedAt, updatedAt)
+
+	for _, field := range textFields {
+		// every copy is stored with term vectors, highlighting needs the text and the positions of its matches
+		fieldMappings := []*mapping.FieldMapping{textField(field.name, plainAnalyzer)}
+		for _, language := range languages {
+			fieldMappings = append(fieldMappings, textField(languageField(field.name, language), analyzers[language]))
+		}
+		document.AddFieldMappingsAt(field.name, fieldMappings...)
+	}
+
+	indexMapping.DefaultMapping = document
+	indexMapping.DefaultAnalyzer = plainAnalyzer
+	return indexMapping, nil
+}
+
+func textField(name, analyzer string) *mapping.FieldMapping {
+	field := bleve.NewTextFieldMapping()
+	field.Name = name
+	field.Analyzer = analyzer
+	field.Store = true
+	field.IncludeTermVectors = true
+	field.IncludeInAll = false
+	return field
+}
@@ -0,0 +1,222 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/achievements"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/quiz"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"github.com/Salladin95/card-quizzler-microservices/consumer"
+	"slices"
+)
+
+const (
+	// perfectSessionQuestions is the shortest quiz session counted as perfect when every answer is correct
+	perfectSessionQuestions = 10
+	// awardBatchSize is the number of users read at once while awarding achievements retroactively
+	awardBatchSize = 100
+)
+
+// AchievementEventKeys are the events feeding the achievement counters
+var AchievementEventKeys = []string{
+	events.CardReviewedKey,
+	events.QuizSessionCompletedKey,
+	events.StreakMilestoneKey,
+	events.DeckPublishedKey,
+}
+
+// counterUpdate is an event's change of an achievement counter, a sum counter adds the value once per source
+type counterUpdate struct {
+	counter  string
+	sourceID string
+	value    int
+}
+
+// GetAchievements returns every achievement with the user's progress, unlocked ones with their badges
+func (cs *CardsServer) GetAchievements(ctx context.Context, req *cards.AchievementsRequest) (*cards.AchievementsResponse, error) {
+	dto := userDto{UserID: req.GetPayload().GetUserId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	counters, err := cs.badges.Counters(ctx, dto.UserID)
+	if err != nil {
+		return nil, operationFailure("get achievement counters", err)
+	}
+	badges, err := cs.badges.Badges(ctx, dto.UserID)
+	if err != nil {
+		return nil, operationFailure("get badges", err)
+	}
+	awarded := make(map[string]models.Badge, len(badges))
+	for _, badge := range badges {
+		awarded[badge.AchievementID] = badge
+	}
+
+	res := &cards.AchievementsResponse{Achievements: make([]*cards.Achievement, 0, len(cs.rules))}
+	for _, rule := range cs.rules {
+		achievement := &cards.Achievement{
+			Id:          rule.ID,
+			Title:       rule.Title,
+			Description: rule.Description,
+			Icon:        rule.Icon,
+			Conditions:  make([]*cards.AchievementCondition, 0, len(rule.Conditions)),
+		}
+		for _, condition := range rule.Conditions {
+			achievement.Conditions = append(achievement.Conditions, &cards.AchievementCondition{
+				Counter: condition.Counter,
+				Value:   int32(counters[condition.Counter]),
+				Target:  int32(condition.AtLeast),
+			})
+		}
+		if badge, ok := awarded[rule.ID]; ok {
+			achievement.Unlocked = true
+			achievement.UnlockedAt = badge.AwardedAt.Unix()
+			achievement.Retroactive = badge.Retroactive
+			res.Unlocked++
+		}
+		res.Achievements = append(res.Achievements, achievement)
+	}
+	return res, nil
+}
+
+// HandleAchievementEvent updates the achievement counters of the user and awards the achievements they meet,
+// returned errors make the consumer retry it
+func (cs *CardsServer) HandleAchievementEvent(ctx context.Context, key string, payload []byte) error {
+	userID, updates, err := counterUpdates(key, payload)
+	if err != nil {
+		return consumer.Permanent(err)
+	}
+	for _, update := range updates {
+		if achievements.Maximum(update.counter) {
+			err = cs.badges.Raise(ctx, userID, update.counter, update.value)
+		} else {
+			err = cs.badges.Add(ctx, userID, update.counter, update.sourceID, update.value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	// awarding runs for redelivered events too, it completes an earlier attempt that failed after counting
+	_, err = cs.awardAchievements(ctx, userID, false)
+	return err
+}
+
+// AwardAchievements awards every user the achievements they meet but don't have yet, it's run at startup
+// so achievements added since the last release reach the users who met them earlier
+func (cs *CardsServer) AwardAchievements(ctx context.Context) (int, error) {
+	awarded := 0
+	afterID := ""
+	for {
+		users, err := cs.badges.ListUsers(ctx, afterID, awardBatchSize)
+		if err != nil {
+			return awarded, err
+		}
+		if len(users) == 0 {
+			return awarded, nil
+		}
+		for _, userID := range users {
+			count, err := cs.awardAchievements(ctx, userID, true)
+			if err != nil {
+				return awarded, err
+			}
+			awarded += count
+		}
+		afterID = users[len(users)-1]
+	}
+}
+
+// awardAchievements stores the badges of the achievements the user meets and announces the new ones,
+// several instances may award the same badge at once, only the one storing it announces it
+func (cs *CardsServer) awardAchievements(ctx context.Context, userID string, retroactive bool) (int, error) {
+	counters, err := cs.badges.Counters(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	awarded := 0
+	for _, rule := range cs.rules {
+		if !rule.Met(counters) {
+			continue
+		}
+		badge := models.Badge{UserID: userID, AchievementID: rule.ID, AwardedAt: cs.now(), Retroactive: retroactive}
+		stored, err := cs.badges.Award(ctx, badge)
+		if err != nil {
+			return awarded, err
+		}
+		if !stored {
+			continue
+		}
+		awarded++
+		cs.publish(ctx, events.AchievementUnlockedKey, events.AchievementUnlocked{
+			UserID:        userID,
+			AchievementID: rule.ID,
+			Title:         rule.Title,
+			Description:   rule.Description,
+			Icon:          rule.Icon,
+			Retroactive:   retroactive,
+			AwardedAt:     badge.AwardedAt.Unix(),
+		})
+	}
+	return awarded, nil
+}
+
+// selfGraded reports whether the user judged any answer of the session, such a session can't be perfect
+func selfGraded(event events.QuizSessionCompleted) bool {
+	if slices.ContainsFunc(event.Modes, quiz.SelfGraded) {
+		return true
+	}
+	// modes that can't use a card fall back to flashcards
+	return slices.ContainsFunc(event.Results, func(result events.QuizCardResult) bool {
+		return quiz.SelfGraded(result.Mode)
+	})
+}
+
+// counterUpdates reads the user and the counter changes of an event
+func counterUpdates(key string, payload []byte) (string, []counterUpdate, error) {
+	switch key {
+	case events.CardReviewedKey:
+		var event events.CardReviewed
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return "", nil, err
+		}
+		if event.ReviewID == "" {
+			return "", nil, errors.New("review event without a review id")
+		}
+		updates := []counterUpdate{{counter: achievements.CounterReviews, sourceID: event.ReviewID, value: 1}}
+		if event.New {
+			updates = append(updates, counterUpdate{counter: achievements.CounterNewCards, sourceID: event.ReviewID, value: 1})
+		}
+		return event.UserID, updates, nil
+	case events.QuizSessionCompletedKey:
+		var event events.QuizSessionCompleted
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return "", nil, err
+		}
+		// a session finished before answering anything isn't counted
+		if event.Answered == 0 {
+			return event.UserID, nil, nil
+		}
+		updates := []counterUpdate{{counter: achievements.CounterSessions, sourceID: event.SessionID, value: 1}}
+		if event.Total >= perfectSessionQuestions && event.Correct == event.Total && !selfGraded(event) {
+			updates = append(updates, counterUpdate{counter: achievements.CounterPerfectSessions, sourceID: event.SessionID, value: 1})
+		}
+		return event.UserID, updates, nil
+	case events.StreakMilestoneKey:
+		var event events.StreakMilestone
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return "", nil, err
+		}
+		return event.UserID, []counterUpdate{{counter: achievements.CounterLongestStreak, value: event.Days}}, nil
+	case events.DeckPublishedKey:
+		var event events.DeckPublished
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return "", nil, err
+		}
+		return event.OwnerID, []counterUpdate{{counter: achievements.CounterPublishedDecks, sourceID: event.DeckID, value: 1}}, nil
+	default:
+		return "", nil, fmt.Errorf("unexpected event %s", key)
+	}
+}
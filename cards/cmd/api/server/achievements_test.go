@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/achievements"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/quiz"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"testing"
+)
+
+const testAchievements = `[
+	{"id": "first-review", "title": "First Steps", "conditions": [{"counter": "reviews", "atLeast": 1}]},
+	{"id": "reviews-3", "title": "Three Cards", "conditions": [{"counter": "reviews", "atLeast": 3}]},
+	{"id": "publisher", "title": "Publisher", "conditions": [{"counter": "published_decks", "atLeast": 1}]},
+	{"id": "flawless", "title": "Flawless", "conditions": [{"counter": "perfect_sessions", "atLeast": 1}]}
+]`
+
+// handleAchievementEvent delivers an event to the achievement consumer twice, like a redelivering queue
+func (ts *testServer) handleAchievementEvent(t *testing.T, key string, event any) {
+	t.Helper()
+	payload, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := ts.HandleAchievementEvent(context.Background(), key, payload); err != nil {
+			t.Fatalf("handle %s: %v", key, err)
+		}
+	}
+}
+
+// unlocked returns the published achievement events and drops them
+func (ts *testServer) unlocked() []events.AchievementUnlocked {
+	ts.publisher.mu.Lock()
+	defer ts.publisher.mu.Unlock()
+	var unlocked []events.AchievementUnlocked
+	for _, event := range ts.publisher.events {
+		if event.key == events.AchievementUnlockedKey {
+			unlocked = append(unlocked, event.payload.(events.AchievementUnlocked))
+		}
+	}
+	ts.publisher.events = nil
+	return unlocked
+}
+
+func TestAchievements(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	ts.rules = achievements.MustParse([]byte(testAchievements))
+	_, deckCards := ts.seedDeck(t, "spanish", 3)
+
+	timedReview(t, ts, "spanish", deckCards[0].ID, 3, 0)
+	timedReview(t, ts, "spanish", deckCards[1].ID, 3, 0)
+	ts.deliverEvents(t, AchievementEventKeys, ts.HandleAchievementEvent)
+	unlocked := ts.unlocked()
+	if len(unlocked) != 1 || unlocked[0].AchievementID != "first-review" || unlocked[0].Retroactive {
+		t.Fatalf("after two reviews: got %+v, want first-review once", unlocked)
+	}
+
+	// republishing a deck counts it once
+	published := events.DeckPublished{DeckID: "spanish", OwnerID: testUserID}
+	ts.handleAchievementEvent(t, events.DeckPublishedKey, published)
+	ts.handleAchievementEvent(t, events.DeckPublishedKey, published)
+	// a perfect session needs at least 10 questions
+	ts.handleAchievementEvent(t, events.QuizSessionCompletedKey, events.QuizSessionCompleted{
+		SessionID: "short", UserID: testUserID, Total: 5, Answered: 5, Correct: 5,
+	})
+	unlocked = ts.unlocked()
+	if len(unlocked) != 1 || unlocked[0].AchievementID != "publisher" {
+		t.Fatalf("after publishing: got %+v, want publisher once", unlocked)
+	}
+	// the user judged the flashcards and a card of the other session fell back to them
+	ts.handleAchievementEvent(t, events.QuizSessionCompletedKey, events.QuizSessionCompleted{
+		SessionID: "flashcards", UserID: testUserID, Modes: []string{quiz.ModeFlashcard}, Total: 10, Answered: 10, Correct: 10,
+	})
+	ts.handleAchievementEvent(t, events.QuizSessionCompletedKey, events.QuizSessionCompleted{
+		SessionID: "fallback", UserID: testUserID, Modes: []string{quiz.ModeMatching}, Total: 10, Answered: 10, Correct: 10,
+		Results: []events.QuizCardResult{{CardID: "card-1", Mode: quiz.ModeFlashcard, Correct: true}},
+	})
+	if unlocked = ts.unlocked(); len(unlocked) != 0 {
+		t.Fatalf("after self-graded sessions: got %+v, want nothing", unlocked)
+	}
+	ts.handleAchievementEvent(t, events.QuizSessionCompletedKey, events.QuizSessionCompleted{
+		SessionID: "long", UserID: testUserID, Modes: []string{quiz.ModeTyped}, Total: 10, Answered: 10, Correct: 10,
+	})
+	if unlocked = ts.unlocked(); len(unlocked) != 1 || unlocked[0].AchievementID != "flawless" {
+		t.Fatalf("after a perfect session: got %+v, want flawless once", unlocked)
+	}
+
+	// a lower milestone reported late keeps the longest streak
+	ts.handleAchievementEvent(t, events.StreakMilestoneKey, events.StreakMilestone{UserID: testUserID, Days: 7})
+	ts.handleAchievementEvent(t, events.StreakMilestoneKey, events.StreakMilestone{UserID: testUserID, Days: 3})
+
+	// an achievement added later is awarded to users who already met it, once
+	ts.rules = append(ts.rules, achievements.Achievement{
+		ID:         "streak-7",
+		Title:      "Week Warrior",
+		Conditions: []achievements.Condition{{Counter: achievements.CounterLongestStreak, AtLeast: 7}},
+	})
+	for i, want := range []int{1, 0} {
+		awarded, err := ts.AwardAchievements(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if awarded != want {
+			t.Errorf("sweep %d: got %d awarded, want %d", i+1, awarded, want)
+		}
+	}
+	if unlocked = ts.unlocked(); len(unlocked) != 1 || unlocked[0].AchievementID != "streak-7" || !unlocked[0].Retroactive {
+		t.Fatalf("after the sweep: got %+v, want streak-7 retroactively", unlocked)
+	}
+
+	res, err := ts.GetAchievements(ctx, &cards.AchievementsRequest{Payload: &cards.AchievementsPayload{UserId: testUserID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Unlocked != 4 || len(res.Achievements) != 5 {
+		t.Fatalf("got %d of %d unlocked, want 4 of 5", res.Unlocked, len(res.Achievements))
+	}
+	locked := res.Achievements[1]
+	if locked.Id != "reviews-3" || locked.Unlocked || locked.Conditions[0].Value != 2 || locked.Conditions[0].Target != 3 {
+		t.Errorf("got %+v, want reviews-3 locked at 2 of 3", locked)
+	}
+	if streak := res.Achievements[4]; !streak.Unlocked || !streak.Retroactive || streak.Conditions[0].Value != 7 {
+		t.Errorf("got %+v, want streak-7 unlocked retroactively at 7", streak)
+	}
+}
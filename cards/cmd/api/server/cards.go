@@ -0,0 +1,215 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/grading"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/repositories"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+func (cs *CardsServer) CreateCard(ctx context.Context, req *cards.CreateCardRequest) (*cards.CardResponse, error) {
+	payload := req.GetPayload()
+	dto := createCardDto{
+		UserID:            payload.GetUserId(),
+		DeckID:            payload.GetDeckId(),
+		Front:             payload.GetFront(),
+		Back:              payload.GetBack(),
+		Hint:              payload.GetHint(),
+		Alternatives:      payload.GetAlternatives(),
+		Tags:              normalizeTags(payload.GetTags()),
+		AnswerType:        payload.GetAnswerType(),
+		AbsoluteTolerance: payload.GetAbsoluteTolerance(),
+		RelativeTolerance: payload.GetRelativeTolerance(),
+	}
+	if dto.AnswerType == "" {
+		dto.AnswerType = grading.AnswerText
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+	if err := validateAnswers(dto.AnswerType, dto.Back, dto.Alternatives); err != nil {
+		return nil, err
+	}
+
+	deck, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	card := models.Card{
+		ID:                uuid.NewString(),
+		DeckID:            deck.ID,
+		Front:             dto.Front,
+		Back:              dto.Back,
+		Hint:              dto.Hint,
+		Alternatives:      dto.Alternatives,
+		Tags:              dto.Tags,
+		AnswerType:        dto.AnswerType,
+		AbsoluteTolerance: dto.AbsoluteTolerance,
+		RelativeTolerance: dto.RelativeTolerance,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if err := cs.cards.Create(ctx, card); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			// the deck was deleted in the meantime
+			return nil, errDeckNotFound
+		}
+		return nil, operationFailure("create card", err)
+	}
+	cs.publish(ctx, events.CardCreatedKey, cardChanged(deck, card))
+
+	return &cards.CardResponse{Card: toProtoCard(card)}, nil
+}
+
+func (cs *CardsServer) GetCard(ctx context.Context, req *cards.GetCardRequest) (*cards.CardResponse, error) {
+	payload := req.GetPayload()
+	dto := cardDto{UserID: payload.GetUserId(), DeckID: payload.GetDeckId(), CardID: payload.GetCardId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	if _, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleViewer); err != nil {
+		return nil, err
+	}
+	card, err := cs.getDeckCard(ctx, dto.DeckID, dto.CardID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cards.CardResponse{Card: toProtoCard(card)}, nil
+}
+
+func (cs *CardsServer) ListCards(ctx context.Context, req *cards.ListCardsRequest) (*cards.ListCardsResponse, error) {
+	payload := req.GetPayload()
+	dto := deckDto{UserID: payload.GetUserId(), DeckID: payload.GetDeckId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	if _, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleViewer); err != nil {
+		return nil, err
+	}
+	deckCards, err := cs.cards.ListByDeck(ctx, dto.DeckID)
+	if err != nil {
+		return nil, operationFailure("list cards", err)
+	}
+
+	res := &cards.ListCardsResponse{Cards: make([]*cards.Card, 0, len(deckCards))}
+	for _, card := range deckCards {
+		res.Cards = append(res.Cards, toProtoCard(card))
+	}
+	return res, nil
+}
+
+func (cs *CardsServer) UpdateCard(ctx context.Context, req *cards.UpdateCardRequest) (*cards.CardResponse, error) {
+	payload := req.GetPayload()
+	dto := updateCardDto{
+		UserID:            payload.GetUserId(),
+		DeckID:            payload.GetDeckId(),
+		CardID:            payload.GetCardId(),
+		Front:             payload.GetFront(),
+		Back:              payload.GetBack(),
+		Hint:              payload.GetHint(),
+		Alternatives:      payload.GetAlternatives(),
+		Tags:              normalizeTags(payload.GetTags()),
+		AnswerType:        payload.GetAnswerType(),
+		AbsoluteTolerance: payload.GetAbsoluteTolerance(),
+		RelativeTolerance: payload.GetRelativeTolerance(),
+	}
+	if dto.AnswerType == "" {
+		dto.AnswerType = grading.AnswerText
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+	if err := validateAnswers(dto.AnswerType, dto.Back, dto.Alternatives); err != nil {
+		return nil, err
+	}
+
+	deck, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	card, err := cs.getDeckCard(ctx, deck.ID, dto.CardID)
+	if err != nil {
+		return nil, err
+	}
+	card.Front = dto.Front
+	card.Back = dto.Back
+	card.Hint = dto.Hint
+	card.Alternatives = dto.Alternatives
+	card.Tags = dto.Tags
+	card.AnswerType = dto.AnswerType
+	card.AbsoluteTolerance = dto.AbsoluteTolerance
+	card.RelativeTolerance = dto.RelativeTolerance
+	card.UpdatedAt = time.Now()
+	if err := cs.cards.Update(ctx, card); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, errCardNotFound
+		}
+		return nil, operationFailure("update card", err)
+	}
+	cs.publish(ctx, events.CardUpdatedKey, cardChanged(deck, card))
+
+	return &cards.CardResponse{Card: toProtoCard(card)}, nil
+}
+
+func (cs *CardsServer) DeleteCard(ctx context.Context, req *cards.DeleteCardRequest) (*cards.DeleteResponse, error) {
+	payload := req.GetPayload()
+	dto := cardDto{UserID: payload.GetUserId(), DeckID: payload.GetDeckId(), CardID: payload.GetCardId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	deck, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	card, err := cs.getDeckCard(ctx, deck.ID, dto.CardID)
+	if err != nil {
+		return nil, err
+	}
+	if err := cs.cards.Delete(ctx, card.ID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, errCardNotFound
+		}
+		return nil, operationFailure("delete card", err)
+	}
+	cs.publish(ctx, events.CardDeletedKey, events.CardDeleted{CardID: card.ID, DeckID: deck.ID, OwnerID: deck.OwnerID})
+
+	return &cards.DeleteResponse{Message: "card is deleted"}, nil
+}
+
+func cardChanged(deck models.Deck, card models.Card) events.CardChanged {
+	return events.CardChanged{
+		CardID:       card.ID,
+		DeckID:       deck.ID,
+		OwnerID:      deck.OwnerID,
+		Front:        card.Front,
+		Back:         card.Back,
+		Hint:         card.Hint,
+		Alternatives: card.Alternatives,
+		Tags:         card.Tags,
+		AnswerType:   card.AnswerType,
+		UpdatedAt:    card.UpdatedAt.Unix(),
+	}
+}
+
+// validateAnswers checks that the back and the alternatives can be graded as the answer type
+func validateAnswers(answerType, back string, alternatives []string) error {
+	for _, answer := range append([]string{back}, alternatives...) {
+		if err := grading.Validate(answerType, answer); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid %s answer: %v", answerType, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/achievements"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/config"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/leaderboard"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/printout"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/quiz"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/repositories"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/scheduler"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/search"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"github.com/go-playground/validator/v10"
+	"time"
+)
+
+// defaultPageSize is used when a list request does not set a limit
+const defaultPageSize = 20
+
+type CardsServer struct {
+	cards.UnimplementedCardsServer
+	config    config.AppCfg
+	decks     repositories.DeckRepository
+	cards     repositories.CardRepository
+	reviews   repositories.ReviewRepository
+	sessions  repositories.QuizSessionRepository
+	scores    repositories.ChallengeScoreRepository
+	jobs      repositories.JobRepository
+	folders   repositories.FolderRepository
+	filtered  repositories.FilteredDeckRepository
+	sharing   repositories.SharingRepository
+	catalog   repositories.CatalogRepository
+	forks     repositories.ForkRepository
+	stats     repositories.StatsRepository
+	streaks   repositories.StreakRepository
+	badges    repositories.AchievementRepository
+	follows   repositories.FollowRepository
+	boards    leaderboard.SortedSet
+	scheduler scheduler.Algorithm
+	quiz      *quiz.Engine
+	printer   *printout.Printer
+	search    *search.Index
+	publisher events.Publisher
+	validate  *validator.Validate
+	// rules are the achievements badges are awarded by
+	rules []achievements.Achievement
+	// now is the clock used for scheduling
+	now func() time.Time
+}
+
+type Repositories struct {
+	Decks   repositories.DeckRepository
+	Cards   repositories.CardRepository
+	Reviews repositories.ReviewRepository
+	// QuizSessions keeps quiz sessions so they can be resumed from another device
+	QuizSessions repositories.QuizSessionRepository
+	// ChallengeScores records the results of timed challenges
+	ChallengeScores repositories.ChallengeScoreRepository
+	// Jobs tracks imports and exports
+	Jobs repositories.JobRepository
+	// Folders organize decks
+	Folders repositories.FolderRepository
+	// FilteredDecks are saved queries studied across decks
+	FilteredDecks repositories.FilteredDeckRepository
+	// Sharing keeps the members of shared decks and the invitations to join them
+	Sharing repositories.SharingRepository
+	// Catalog lists the published decks, Forks links the decks copied from it to their upstream
+	Catalog repositories.CatalogRepository
+	Forks   repositories.ForkRepository
+	// Stats keeps the daily rollups of the reviews
+	Stats repositories.StatsRepository
+	// Streaks keeps the daily goals and the streaks of reaching them
+	Streaks repositories.StreakRepository
+	// Achievements keeps the counters achievements are awarded by and the badges
+	Achievements repositories.AchievementRepository
+	// Follows keeps the users each user follows, they make up the following leaderboards
+	Follows repositories.FollowRepository
+	// Leaderboards keeps the weekly rankings, in memory or in Redis, whatever the storage of the rest
+	Leaderboards leaderboard.SortedSet
+}
+
+func NewCardsServer(cfg config.AppCfg, repos Repositories, algorithm scheduler.Algorithm, printer *printout.Printer,
+	index *search.Index, publisher events.Publisher) *CardsServer {
+	return &CardsServer{
+		config:    cfg,
+		decks:     repos.Decks,
+		cards:     repos.Cards,
+		reviews:   repos.Reviews,
+		sessions:  repos.QuizSessions,
+		scores:    repos.ChallengeScores,
+		jobs:      repos.Jobs,
+		folders:   repos.Folders,
+		filtered:  repos.FilteredDecks,
+		sharing:   repos.Sharing,
+		catalog:   repos.Catalog,
+		forks:     repos.Forks,
+		stats:     repos.Stats,
+		streaks:   repos.Streaks,
+		badges:    repos.Achievements,
+		follows:   repos.Follows,
+		boards:    repos.Leaderboards,
+		scheduler: algorithm,
+		quiz:      quiz.NewEngine(quiz.DefaultModes(), cfg.QUIZ_SESSION_TTL, cfg.CHALLENGE_LATENCY_ALLOWANCE),
+		printer:   printer,
+		search:    index,
+		publisher: publisher,
+		validate:  validator.New(),
+		rules:     achievements.Builtin(),
+		now:       time.Now,
+	}
+}
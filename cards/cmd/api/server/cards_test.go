@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/grading"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"reflect"
+	"testing"
+)
+
+func TestCardLifecycle(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	ts.seedDeck(t, "deck", 1)
+
+	created, err := ts.CreateCard(ctx, &cards.CreateCardRequest{Payload: &cards.CreateCardPayload{
+		UserId: testUserID, DeckId: "deck", Front: "hola", Back: "hello", Alternatives: []string{"hi"}, Tags: []string{"Greetings"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	card := created.GetCard()
+	if card.GetDeckId() != "deck" || card.GetAnswerType() != grading.AnswerText || !reflect.DeepEqual(card.GetTags(), []string{"greetings"}) {
+		t.Errorf("created card %+v", card)
+	}
+
+	list, err := ts.ListCards(ctx, &cards.ListCardsRequest{Payload: &cards.ListCardsPayload{UserId: testUserID, DeckId: "deck"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.GetCards()) != 2 || list.GetCards()[1].GetId() != card.GetId() {
+		t.Errorf("listed %v, want the seeded card and the new one", list.GetCards())
+	}
+
+	updated, err := ts.UpdateCard(ctx, &cards.UpdateCardRequest{Payload: &cards.UpdateCardPayload{
+		UserId: testUserID, DeckId: "deck", CardId: card.GetId(), Front: "dos", Back: "2", AnswerType: grading.AnswerNumeric,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.GetCard().GetBack() != "2" || updated.GetCard().GetAnswerType() != grading.AnswerNumeric || len(updated.GetCard().GetAlternatives()) != 0 {
+		t.Errorf("updated card %+v", updated.GetCard())
+	}
+	got, err := ts.GetCard(ctx, &cards.GetCardRequest{Payload: &cards.GetCardPayload{UserId: testUserID, DeckId: "deck", CardId: card.GetId()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetCard().GetFront() != "dos" {
+		t.Errorf("got card %+v", got.GetCard())
+	}
+
+	if _, err := ts.DeleteCard(ctx, &cards.DeleteCardRequest{Payload: &cards.DeleteCardPayload{
+		UserId: testUserID, DeckId: "deck", CardId: card.GetId(),
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = ts.GetCard(ctx, &cards.GetCardRequest{Payload: &cards.GetCardPayload{UserId: testUserID, DeckId: "deck", CardId: card.GetId()}})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("deleted card returned %v, want NotFound", err)
+	}
+
+	want := []string{events.CardCreatedKey, events.CardUpdatedKey, events.CardDeletedKey}
+	if keys := ts.publisher.keys(); !reflect.DeepEqual(keys, want) {
+		t.Errorf("published %v, want %v", keys, want)
+	}
+}
+
+func TestCardValidation(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	ts.seedDeck(t, "deck", 0)
+	for _, payload := range []*cards.CreateCardPayload{
+		{UserId: testUserID, DeckId: "deck", Back: "no front"},
+		{UserId: testUserID, DeckId: "deck", Front: "no back"},
+		{UserId: testUserID, DeckId: "deck", Front: "front", Back: "back", AnswerType: "essay"},
+		{UserId: testUserID, DeckId: "deck", Front: "front", Back: "many", AnswerType: grading.AnswerNumeric},
+		{UserId: testUserID, DeckId: "deck", Front: "front", Back: "1", Alternatives: []string{"one"}, AnswerType: grading.AnswerNumeric},
+		{UserId: testUserID, DeckId: "deck", Front: "front", Back: "1", AnswerType: grading.AnswerNumeric, RelativeTolerance: 2},
+	} {
+		_, err := ts.CreateCard(ctx, &cards.CreateCardRequest{Payload: payload})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%+v: got %v, want InvalidArgument", payload, err)
+		}
+	}
+}
+
+func TestCardOwnership(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	_, deckCards := ts.seedDeck(t, "deck", 1)
+	_, otherCards := ts.seedDeck(t, "other", 1)
+	cardID := deckCards[0].ID
+
+	tests := []struct {
+		name   string
+		userID string
+		deckID string
+		cardID string
+		code   codes.Code
+	}{
+		{"another user's deck", strangerID, "deck", cardID, codes.PermissionDenied},
+		{"missing deck", testUserID, "missing", cardID, codes.NotFound},
+		{"missing card", testUserID, "deck", "missing", codes.NotFound},
+		// a card is reached through its own deck only
+		{"card of another deck", testUserID, "deck", otherCards[0].ID, codes.NotFound},
+	}
+	for _, tt := range tests {
+		_, err := ts.GetCard(ctx, &cards.GetCardRequest{Payload: &cards.GetCardPayload{UserId: tt.userID, DeckId: tt.deckID, CardId: tt.cardID}})
+		if status.Code(err) != tt.code {
+			t.Errorf("%s: get returned %v, want %s", tt.name, err, tt.code)
+		}
+		_, err = ts.UpdateCard(ctx, &cards.UpdateCardRequest{Payload: &cards.UpdateCardPayload{
+			UserId: tt.userID, DeckId: tt.deckID, CardId: tt.cardID, Front: "taken", Back: "over",
+		}})
+		if status.Code(err) != tt.code {
+			t.Errorf("%s: update returned %v, want %s", tt.name, err, tt.code)
+		}
+		_, err = ts.DeleteCard(ctx, &cards.DeleteCardRequest{Payload: &cards.DeleteCardPayload{UserId: tt.userID, DeckId: tt.deckID, CardId: tt.cardID}})
+		if status.Code(err) != tt.code {
+			t.Errorf("%s: delete returned %v, want %s", tt.name, err, tt.code)
+		}
+	}
+
+	_, err := ts.CreateCard(ctx, &cards.CreateCardRequest{Payload: &cards.CreateCardPayload{
+		UserId: strangerID, DeckId: "deck", Front: "planted", Back: "card",
+	}})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("stranger creating a card returned %v, want PermissionDenied", err)
+	}
+	_, err = ts.ListCards(ctx, &cards.ListCardsRequest{Payload: &cards.ListCardsPayload{UserId: strangerID, DeckId: "deck"}})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("stranger listing the cards returned %v, want PermissionDenied", err)
+	}
+
+	for _, card := range append(deckCards, otherCards...) {
+		stored, err := ts.cards.Get(ctx, card.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(stored, card) {
+			t.Errorf("card %s was changed to %+v", card.ID, stored)
+		}
+	}
+	if keys := ts.publisher.keys(); len(keys) != 0 {
+		t.Errorf("published %v for rejected changes", keys)
+	}
+}
@@ -0,0 +1,501 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/repositories"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Statuses of the upstream changes a fork can pull
+const (
+	// ChangeNew is a card added to the upstream deck
+	ChangeNew = "new"
+	// ChangeChanged is an upstream card changed since it was pulled
+	ChangeChanged = "changed"
+	// ChangeConflict is an upstream card changed since it was pulled while the user edited its copy
+	ChangeConflict = "conflict"
+	// ChangeDeleted is a card deleted from the upstream deck, its copy stays until the change is pulled
+	ChangeDeleted = "deleted"
+)
+
+// upstreamChange is a difference between a fork and its upstream deck, Local is empty for new cards
+// and Upstream for deleted ones
+type upstreamChange struct {
+	Status   string
+	Upstream models.Card
+	Local    models.Card
+	Link     models.ForkCard
+	Studied  bool
+}
+
+// PublishDeck adds the user's deck to the catalog, publishing it again changes its category and language
+func (cs *CardsServer) PublishDeck(ctx context.Context, req *cards.PublishDeckRequest) (*cards.CatalogDeckResponse, error) {
+	payload := req.GetPayload()
+	dto := publishDeckDto{
+		UserID:   payload.GetUserId(),
+		DeckID:   payload.GetDeckId(),
+		Category: payload.GetCategory(),
+		Language: payload.GetLanguage(),
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	// decks are published under the name of their creator, members of a shared deck can't publish it
+	deck, err := cs.getOwnedDeck(ctx, dto.UserID, dto.DeckID)
+	if err != nil {
+		return nil, err
+	}
+	now := cs.now()
+	entry := models.CatalogEntry{
+		DeckID:      deck.ID,
+		OwnerID:     deck.OwnerID,
+		Category:    dto.Category,
+		Language:    strings.ToLower(dto.Language),
+		PublishedAt: now,
+		UpdatedAt:   now,
+	}
+	if err := cs.catalog.Publish(ctx, entry); err != nil {
+		return nil, operationFailure("publish deck", err)
+	}
+	catalogDeck, err := cs.getCatalogDeck(ctx, deck.ID)
+	if err != nil {
+		return nil, err
+	}
+	cs.publish(ctx, events.DeckPublishedKey, events.DeckPublished{
+		DeckID:      deck.ID,
+		OwnerID:     deck.OwnerID,
+		Title:       deck.Title,
+		Category:    catalogDeck.Entry.Category,
+		Language:    catalogDeck.Entry.Language,
+		PublishedAt: catalogDeck.Entry.PublishedAt.Unix(),
+	})
+
+	return &cards.CatalogDeckResponse{CatalogDeck: toProtoCatalogDeck(catalogDeck)}, nil
+}
+
+// UnpublishDeck takes the deck out of the catalog, existing forks keep their cards but can't pull changes anymore
+func (cs *CardsServer) UnpublishDeck(ctx context.Context, req *cards.CatalogDeckRequest) (*cards.DeleteResponse, error) {
+	payload := req.GetPayload()
+	dto := deckDto{UserID: payload.GetUserId(), DeckID: payload.GetDeckId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	deck, err := cs.getOwnedDeck(ctx, dto.UserID, dto.DeckID)
+	if err != nil {
+		return nil, err
+	}
+	if err := cs.catalog.Unpublish(ctx, deck.ID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, errNotPublished
+		}
+		return nil, operationFailure("unpublish deck", err)
+	}
+	return &cards.DeleteResponse{Message: "deck is unpublished"}, nil
+}
+
+func (cs *CardsServer) BrowseCatalog(ctx context.Context, req *cards.BrowseCatalogRequest) (*cards.BrowseCatalogResponse, error) {
+	payload := req.GetPayload()
+	dto := browseCatalogDto{
+		UserID:   payload.GetUserId(),
+		Category: payload.GetCategory(),
+		Language: payload.GetLanguage(),
+		Sort:     payload.GetSort(),
+		Limit:    int(payload.GetLimit()),
+		Offset:   int(payload.GetOffset()),
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+	if dto.Limit == 0 {
+		dto.Limit = defaultPageSize
+	}
+	if dto.Sort == "" {
+		dto.Sort = repositories.CatalogSortRecent
+	}
+
+	decks, total, err := cs.catalog.Browse(ctx, repositories.CatalogQuery{
+		Category: dto.Category,
+		Language: strings.ToLower(dto.Language),
+		Sort:     dto.Sort,
+		Limit:    dto.Limit,
+		Offset:   dto.Offset,
+	})
+	if err != nil {
+		return nil, operationFailure("browse catalog", err)
+	}
+	res := &cards.BrowseCatalogResponse{Decks: make([]*cards.CatalogDeck, 0, len(decks)), Total: int32(total)}
+	for _, deck := range decks {
+		res.Decks = append(res.Decks, toProtoCatalogDeck(deck))
+	}
+	return res, nil
+}
+
+// GetCatalogDeck returns a published deck with its cards, any user can read them
+func (cs *CardsServer) GetCatalogDeck(ctx context.Context, req *cards.CatalogDeckRequest) (*cards.GetCatalogDeckResponse, error) {
+	payload := req.GetPayload()
+	dto := deckDto{UserID: payload.GetUserId(), DeckID: payload.GetDeckId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	catalogDeck, err := cs.getCatalogDeck(ctx, dto.DeckID)
+	if err != nil {
+		return nil, err
+	}
+	deckCards, err := cs.cards.ListByDeck(ctx, dto.DeckID)
+	if err != nil {
+		return nil, operationFailure("list cards", err)
+	}
+	res := &cards.GetCatalogDeckResponse{
+		CatalogDeck: toProtoCatalogDeck(catalogDeck),
+		Cards:       make([]*cards.Card, 0, len(deckCards)),
+	}
+	for _, card := range deckCards {
+		res.Cards = append(res.Cards, toProtoCard(card))
+	}
+	return res, nil
+}
+
+// ForkDeck copies a published deck into the user's library, the copy tracks the published deck as its upstream
+func (cs *CardsServer) ForkDeck(ctx context.Context, req *cards.ForkDeckRequest) (*cards.DeckResponse, error) {
+	payload := req.GetPayload()
+	dto := forkDeckDto{UserID: payload.GetUserId(), DeckID: payload.GetDeckId(), FolderID: payload.GetFolderId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+	if dto.FolderID != "" {
+		if _, err := cs.getOwnedFolder(ctx, dto.UserID, dto.FolderID); err != nil {
+			return nil, err
+		}
+	}
+
+	upstream, err := cs.getCatalogDeck(ctx, dto.DeckID)
+	if err != nil {
+		return nil, err
+	}
+	upstreamCards, err := cs.cards.ListByDeck(ctx, upstream.Deck.ID)
+	if err != nil {
+		return nil, operationFailure("list cards", err)
+	}
+
+	now := cs.now()
+	deck := models.Deck{
+		ID:          uuid.NewString(),
+		OwnerID:     dto.UserID,
+		FolderID:    dto.FolderID,
+		Title:       upstream.Deck.Title,
+		Description: upstream.Deck.Description,
+		Tags:        upstream.Deck.Tags,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := cs.decks.Create(ctx, deck); err != nil {
+		return nil, operationFailure("create deck", err)
+	}
+	fork := models.Fork{DeckID: deck.ID, UpstreamID: upstream.Deck.ID, ForkedAt: now, SyncedAt: now}
+	if err := cs.forks.Create(ctx, fork); err != nil {
+		return nil, operationFailure("create fork", err)
+	}
+	cs.publish(ctx, events.DeckCreatedKey, deckChanged(deck))
+	for i, upstreamCard := range upstreamCards {
+		// spacing creation times by order keeps the cards in the order of the upstream deck
+		if _, err := cs.pullNewCard(ctx, deck, upstreamCard, now.Add(time.Duration(i)*time.Microsecond)); err != nil {
+			return nil, err
+		}
+	}
+	if err := cs.catalog.AddFork(ctx, upstream.Deck.ID); err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return nil, operationFailure("count fork", err)
+	}
+	cs.publish(ctx, events.DeckForkedKey, events.DeckForked{
+		DeckID:          deck.ID,
+		OwnerID:         deck.OwnerID,
+		UpstreamID:      upstream.Deck.ID,
+		UpstreamOwnerID: upstream.Deck.OwnerID,
+		Cards:           len(upstreamCards),
+	})
+
+	return &cards.DeckResponse{Deck: toProtoDeck(deck)}, nil
+}
+
+// GetUpstreamChanges previews the changes of the upstream deck per card, nothing is changed until they are merged
+func (cs *CardsServer) GetUpstreamChanges(ctx context.Context, req *cards.CatalogDeckRequest) (*cards.UpstreamChangesResponse, error) {
+	payload := req.GetPayload()
+	dto := deckDto{UserID: payload.GetUserId(), DeckID: payload.GetDeckId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	deck, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	fork, changes, err := cs.upstreamChanges(ctx, dto.UserID, deck)
+	if err != nil {
+		return nil, err
+	}
+	res := &cards.UpstreamChangesResponse{
+		UpstreamId: fork.UpstreamID,
+		Changes:    make([]*cards.UpstreamChange, 0, len(changes)),
+		SyncedAt:   fork.SyncedAt.Unix(),
+	}
+	for _, change := range changes {
+		res.Changes = append(res.Changes, toProtoUpstreamChange(change))
+	}
+	return res, nil
+}
+
+// MergeUpstream applies the chosen upstream changes. Changed cards are updated in place,
+// so the review history of the user stays with them.
+func (cs *CardsServer) MergeUpstream(ctx context.Context, req *cards.MergeUpstreamRequest) (*cards.MergeUpstreamResponse, error) {
+	payload := req.GetPayload()
+	dto := mergeUpstreamDto{UserID: payload.GetUserId(), DeckID: payload.GetDeckId(), UpstreamCardIDs: payload.GetUpstreamCardIds()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	deck, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	fork, changes, err := cs.upstreamChanges(ctx, dto.UserID, deck)
+	if err != nil {
+		return nil, err
+	}
+	byUpstreamID := make(map[string]upstreamChange, len(changes))
+	for _, change := range changes {
+		byUpstreamID[change.Link.UpstreamCardID] = change
+	}
+	selected := make([]upstreamChange, 0, len(dto.UpstreamCardIDs))
+	for _, id := range dto.UpstreamCardIDs {
+		change, ok := byUpstreamID[id]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "upstream card %s has no change to merge", id)
+		}
+		selected = append(selected, change)
+	}
+
+	now := cs.now()
+	res := &cards.MergeUpstreamResponse{}
+	for i, change := range selected {
+		switch change.Status {
+		case ChangeNew:
+			if _, err := cs.pullNewCard(ctx, deck, change.Upstream, now.Add(time.Duration(i)*time.Microsecond)); err != nil {
+				return nil, err
+			}
+			res.Added++
+		case ChangeChanged, ChangeConflict:
+			if err := cs.pullCardChange(ctx, deck, change, now); err != nil {
+				return nil, err
+			}
+			res.Updated++
+		case ChangeDeleted:
+			if err := cs.cards.Delete(ctx, change.Local.ID); err != nil && !errors.Is(err, repositories.ErrNotFound) {
+				return nil, operationFailure("delete card", err)
+			}
+			cs.publish(ctx, events.CardDeletedKey, events.CardDeleted{CardID: change.Local.ID, DeckID: deck.ID, OwnerID: deck.OwnerID})
+			res.Removed++
+		}
+	}
+	fork.SyncedAt = now
+	if err := cs.forks.MarkSynced(ctx, fork); err != nil {
+		return nil, operationFailure("mark fork synced", err)
+	}
+	return res, nil
+}
+
+// upstreamChanges compares the fork with its upstream deck, in the order of the upstream cards with deleted cards last
+func (cs *CardsServer) upstreamChanges(ctx context.Context, userID string, deck models.Deck) (models.Fork, []upstreamChange, error) {
+	fork, err := cs.forks.Get(ctx, deck.ID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return models.Fork{}, nil, errNotForked
+		}
+		return models.Fork{}, nil, operationFailure("get fork", err)
+	}
+	if _, err := cs.catalog.Get(ctx, fork.UpstreamID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return models.Fork{}, nil, errUpstreamGone
+		}
+		return models.Fork{}, nil, operationFailure("get catalog entry", err)
+	}
+	upstreamCards, err := cs.cards.ListByDeck(ctx, fork.UpstreamID)
+	if err != nil {
+		return models.Fork{}, nil, operationFailure("list cards", err)
+	}
+	forkCards, err := cs.cards.ListByDeck(ctx, deck.ID)
+	if err != nil {
+		return models.Fork{}, nil, operationFailure("list cards", err)
+	}
+	links, err := cs.forks.ListCards(ctx, deck.ID)
+	if err != nil {
+		return models.Fork{}, nil, operationFailure("list fork cards", err)
+	}
+	states, err := cs.reviews.ListStates(ctx, userID, deck.ID)
+	if err != nil {
+		return models.Fork{}, nil, operationFailure("list review states", err)
+	}
+
+	localCards := make(map[string]models.Card, len(forkCards))
+	for _, card := range forkCards {
+		localCards[card.ID] = card
+	}
+	linksByUpstream := make(map[string]models.ForkCard, len(links))
+	for _, link := range links {
+		if _, ok := localCards[link.CardID]; ok {
+			linksByUpstream[link.UpstreamCardID] = link
+		}
+	}
+
+	changes := []upstreamChange{}
+	for _, upstreamCard := range upstreamCards {
+		link, ok := linksByUpstream[upstreamCard.ID]
+		if !ok {
+			changes = append(changes, upstreamChange{
+				Status:   ChangeNew,
+				Upstream: upstreamCard,
+				Link:     models.ForkCard{DeckID: deck.ID, UpstreamCardID: upstreamCard.ID},
+			})
+			continue
+		}
+		delete(linksByUpstream, upstreamCard.ID)
+		if !upstreamCard.UpdatedAt.After(link.UpstreamUpdatedAt) {
+			continue
+		}
+		local := localCards[link.CardID]
+		change := upstreamChange{Status: ChangeChanged, Upstream: upstreamCard, Local: local, Link: link}
+		if local.UpdatedAt.After(link.SyncedAt) {
+			change.Status = ChangeConflict
+		}
+		_, change.Studied = states[local.ID]
+		changes = append(changes, change)
+	}
+	// links left are the cards deleted from the upstream deck
+	deleted := make([]upstreamChange, 0, len(linksByUpstream))
+	for _, link := range linksByUpstream {
+		local := localCards[link.CardID]
+		_, studied := states[local.ID]
+		deleted = append(deleted, upstreamChange{Status: ChangeDeleted, Local: local, Link: link, Studied: studied})
+	}
+	sortByCreation(deleted)
+	return fork, append(changes, deleted...), nil
+}
+
+// pullNewCard copies an upstream card into the fork
+func (cs *CardsServer) pullNewCard(ctx context.Context, deck models.Deck, upstreamCard models.Card, createdAt time.Time) (models.Card, error) {
+	card := upstreamCard
+	card.ID = uuid.NewString()
+	card.DeckID = deck.ID
+	card.CreatedAt = createdAt
+	card.UpdatedAt = createdAt
+	if err := cs.cards.Create(ctx, card); err != nil {
+		return models.Card{}, operationFailure("create card", err)
+	}
+	link := models.ForkCard{
+		CardID:            card.ID,
+		DeckID:            deck.ID,
+		UpstreamCardID:    upstreamCard.ID,
+		UpstreamUpdatedAt: upstreamCard.UpdatedAt,
+		SyncedAt:          card.UpdatedAt,
+	}
+	if err := cs.forks.SaveCard(ctx, link); err != nil {
+		return models.Card{}, operationFailure("link fork card", err)
+	}
+	cs.publish(ctx, events.CardCreatedKey, cardChanged(deck, card))
+	return card, nil
+}
+
+// pullCardChange overwrites the content of the fork card with the upstream one, its id and review state stay
+func (cs *CardsServer) pullCardChange(ctx context.Context, deck models.Deck, change upstreamChange, now time.Time) error {
+	card := change.Local
+	card.Front = change.Upstream.Front
+	card.Back = change.Upstream.Back
+	card.Hint = change.Upstream.Hint
+	card.Alternatives = change.Upstream.Alternatives
+	card.Tags = change.Upstream.Tags
+	card.AnswerType = change.Upstream.AnswerType
+	card.AbsoluteTolerance = change.Upstream.AbsoluteTolerance
+	card.RelativeTolerance = change.Upstream.RelativeTolerance
+	card.UpdatedAt = now
+	if err := cs.cards.Update(ctx, card); err != nil {
+		return operationFailure("update card", err)
+	}
+	link := change.Link
+	link.UpstreamUpdatedAt = change.Upstream.UpdatedAt
+	link.SyncedAt = card.UpdatedAt
+	if err := cs.forks.SaveCard(ctx, link); err != nil {
+		return operationFailure("link fork card", err)
+	}
+	cs.publish(ctx, events.CardUpdatedKey, cardChanged(deck, card))
+	return nil
+}
+
+// getCatalogDeck loads a published deck with its catalog details
+func (cs *CardsServer) getCatalogDeck(ctx context.Context, deckID string) (models.CatalogDeck, error) {
+	entry, err := cs.catalog.Get(ctx, deckID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return models.CatalogDeck{}, errNotPublished
+		}
+		return models.CatalogDeck{}, operationFailure("get catalog entry", err)
+	}
+	deck, err := cs.decks.Get(ctx, deckID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return models.CatalogDeck{}, errNotPublished
+		}
+		return models.CatalogDeck{}, operationFailure("get deck", err)
+	}
+	deckCards, err := cs.cards.ListByDeck(ctx, deckID)
+	if err != nil {
+		return models.CatalogDeck{}, operationFailure("list cards", err)
+	}
+	return models.CatalogDeck{Entry: entry, Deck: deck, CardCount: len(deckCards)}, nil
+}
+
+func sortByCreation(changes []upstreamChange) {
+	slices.SortFunc(changes, func(a, b upstreamChange) int {
+		if c := a.Local.CreatedAt.Compare(b.Local.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Local.ID, b.Local.ID)
+	})
+}
+
+func toProtoCatalogDeck(deck models.CatalogDeck) *cards.CatalogDeck {
+	return &cards.CatalogDeck{
+		Deck:        toProtoDeck(deck.Deck),
+		Category:    deck.Entry.Category,
+		Language:    deck.Entry.Language,
+		Forks:       int32(deck.Entry.Forks),
+		CardCount:   int32(deck.CardCount),
+		PublishedAt: deck.Entry.PublishedAt.Unix(),
+		UpdatedAt:   deck.Entry.UpdatedAt.Unix(),
+	}
+}
+
+func toProtoUpstreamChange(change upstreamChange) *cards.UpstreamChange {
+	res := &cards.UpstreamChange{
+		Status:         change.Status,
+		UpstreamCardId: change.Link.UpstreamCardID,
+		CardId:         change.Local.ID,
+		Studied:        change.Studied,
+	}
+	if change.Status != ChangeDeleted {
+		res.Upstream = toProtoCard(change.Upstream)
+	}
+	if change.Status != ChangeNew {
+		res.Local = toProtoCard(change.Local)
+	}
+	return res
+}
@@ -0,0 +1,216 @@
+package server
+
+import (
+	"context"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"slices"
+	"testing"
+	"time"
+)
+
+const forkerID = "user-2"
+
+func publishDeck(t *testing.T, ts *testServer, deckID, category, language string) {
+	t.Helper()
+	if _, err := ts.PublishDeck(context.Background(), &cards.PublishDeckRequest{Payload: &cards.PublishDeckPayload{
+		UserId: testUserID, DeckId: deckID, Category: category, Language: language,
+	}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func forkDeck(t *testing.T, ts *testServer, deckID string) string {
+	t.Helper()
+	res, err := ts.ForkDeck(context.Background(), &cards.ForkDeckRequest{Payload: &cards.ForkDeckPayload{UserId: forkerID, DeckId: deckID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.GetDeck().GetId()
+}
+
+func TestBrowseCatalog(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	for _, id := range []string{"spanish", "brazilian", "physics"} {
+		ts.seedDeck(t, id, 1)
+	}
+	publishDeck(t, ts, "spanish", "languages", "es")
+	ts.clock.Advance(time.Minute)
+	publishDeck(t, ts, "brazilian", "languages", "pt-BR")
+	ts.clock.Advance(time.Minute)
+	publishDeck(t, ts, "physics", "science", "en")
+	forkDeck(t, ts, "spanish")
+
+	browse := func(payload *cards.BrowseCatalogPayload) []string {
+		t.Helper()
+		payload.UserId = forkerID
+		res, err := ts.BrowseCatalog(ctx, &cards.BrowseCatalogRequest{Payload: payload})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := make([]string, 0, len(res.GetDecks()))
+		for _, deck := range res.GetDecks() {
+			ids = append(ids, deck.GetDeck().GetId())
+		}
+		return ids
+	}
+	tests := []struct {
+		payload *cards.BrowseCatalogPayload
+		want    []string
+	}{
+		{&cards.BrowseCatalogPayload{}, []string{"physics", "brazilian", "spanish"}},
+		{&cards.BrowseCatalogPayload{Sort: "popular"}, []string{"spanish", "physics", "brazilian"}},
+		{&cards.BrowseCatalogPayload{Category: "languages", Sort: "title"}, []string{"brazilian", "spanish"}},
+		{&cards.BrowseCatalogPayload{Language: "pt"}, []string{"brazilian"}},
+		{&cards.BrowseCatalogPayload{Language: "pt-br"}, []string{"brazilian"}},
+	}
+	for _, tt := range tests {
+		if got := browse(tt.payload); !slices.Equal(got, tt.want) {
+			t.Errorf("browse %+v found %v, want %v", tt.payload, got, tt.want)
+		}
+	}
+
+	// members of a shared deck can't publish it, only its creator
+	shareDeck(t, ts, "physics", editorID, "owner")
+	_, err := ts.PublishDeck(ctx, &cards.PublishDeckRequest{Payload: &cards.PublishDeckPayload{
+		UserId: editorID, DeckId: "physics", Category: "science", Language: "en",
+	}})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("member publishing the deck returned %v", err)
+	}
+	if _, err := ts.UnpublishDeck(ctx, &cards.CatalogDeckRequest{Payload: &cards.CatalogDeckPayload{
+		UserId: testUserID, DeckId: "physics",
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = ts.GetCatalogDeck(ctx, &cards.CatalogDeckRequest{Payload: &cards.CatalogDeckPayload{UserId: forkerID, DeckId: "physics"}})
+	if err != errNotPublished {
+		t.Errorf("reading an unpublished deck returned %v", err)
+	}
+}
+
+func TestMergeUpstream(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	_, upstreamCards := ts.seedDeck(t, "upstream", 3)
+	publishDeck(t, ts, "upstream", "languages", "es")
+	forkID := forkDeck(t, ts, "upstream")
+
+	forked, err := ts.ListCards(ctx, &cards.ListCardsRequest{Payload: &cards.ListCardsPayload{UserId: forkerID, DeckId: forkID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fronts []string
+	for _, card := range forked.GetCards() {
+		fronts = append(fronts, card.GetFront())
+	}
+	if !slices.Equal(fronts, []string{"front 1", "front 2", "front 3"}) {
+		t.Fatalf("fork holds %v", fronts)
+	}
+	local := forked.GetCards()
+	if _, err := ts.SubmitReview(ctx, &cards.SubmitReviewRequest{Payload: &cards.SubmitReviewPayload{
+		UserId: forkerID, DeckId: forkID, CardId: local[0].GetId(), Grade: 3,
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the upstream changes the first two cards, deletes the third and adds one, the user edits the second
+	updateCard := func(userID, deckID, cardID, front string) {
+		t.Helper()
+		if _, err := ts.UpdateCard(ctx, &cards.UpdateCardRequest{Payload: &cards.UpdateCardPayload{
+			UserId: userID, DeckId: deckID, CardId: cardID, Front: front, Back: "back",
+		}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	updateCard(testUserID, "upstream", upstreamCards[0].ID, "upstream 1")
+	updateCard(testUserID, "upstream", upstreamCards[1].ID, "upstream 2")
+	updateCard(forkerID, forkID, local[1].GetId(), "mine 2")
+	if _, err := ts.DeleteCard(ctx, &cards.DeleteCardRequest{Payload: &cards.DeleteCardPayload{
+		UserId: testUserID, DeckId: "upstream", CardId: upstreamCards[2].ID,
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	added, err := ts.CreateCard(ctx, &cards.CreateCardRequest{Payload: &cards.CreateCardPayload{
+		UserId: testUserID, DeckId: "upstream", Front: "front 4", Back: "back 4",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	preview := func() map[string]*cards.UpstreamChange {
+		t.Helper()
+		res, err := ts.GetUpstreamChanges(ctx, &cards.CatalogDeckRequest{Payload: &cards.CatalogDeckPayload{UserId: forkerID, DeckId: forkID}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		changes := make(map[string]*cards.UpstreamChange)
+		for _, change := range res.GetChanges() {
+			changes[change.GetUpstreamCardId()] = change
+		}
+		return changes
+	}
+	changes := preview()
+	want := map[string]string{
+		upstreamCards[0].ID:     ChangeChanged,
+		upstreamCards[1].ID:     ChangeConflict,
+		upstreamCards[2].ID:     ChangeDeleted,
+		added.GetCard().GetId(): ChangeNew,
+	}
+	if len(changes) != len(want) {
+		t.Errorf("preview holds %d changes, want %d", len(changes), len(want))
+	}
+	for id, wantStatus := range want {
+		if changes[id].GetStatus() != wantStatus {
+			t.Errorf("change of %s is %q, want %q", id, changes[id].GetStatus(), wantStatus)
+		}
+	}
+	if !changes[upstreamCards[0].ID].GetStudied() {
+		t.Error("studied card isn't marked")
+	}
+
+	res, err := ts.MergeUpstream(ctx, &cards.MergeUpstreamRequest{Payload: &cards.MergeUpstreamPayload{
+		UserId: forkerID, DeckId: forkID, UpstreamCardIds: []string{upstreamCards[0].ID, added.GetCard().GetId()},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.GetAdded() != 1 || res.GetUpdated() != 1 || res.GetRemoved() != 0 {
+		t.Errorf("merge %v", res)
+	}
+	// the changed card keeps its id and review history
+	merged, err := ts.cards.Get(ctx, local[0].GetId())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.Front != "upstream 1" {
+		t.Errorf("merged card front %q", merged.Front)
+	}
+	if _, err := ts.reviews.GetState(ctx, forkerID, merged.ID); err != nil {
+		t.Errorf("review state of the merged card: %v", err)
+	}
+
+	changes = preview()
+	if len(changes) != 2 || changes[upstreamCards[1].ID].GetStatus() != ChangeConflict ||
+		changes[upstreamCards[2].ID].GetStatus() != ChangeDeleted {
+		t.Errorf("changes left after the merge: %v", changes)
+	}
+	_, err = ts.MergeUpstream(ctx, &cards.MergeUpstreamRequest{Payload: &cards.MergeUpstreamPayload{
+		UserId: forkerID, DeckId: forkID, UpstreamCardIds: []string{upstreamCards[0].ID},
+	}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("merging a merged change returned %v", err)
+	}
+
+	if _, err := ts.UnpublishDeck(ctx, &cards.CatalogDeckRequest{Payload: &cards.CatalogDeckPayload{
+		UserId: testUserID, DeckId: "upstream",
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = ts.GetUpstreamChanges(ctx, &cards.CatalogDeckRequest{Payload: &cards.CatalogDeckPayload{UserId: forkerID, DeckId: forkID}})
+	if err != errUpstreamGone {
+		t.Errorf("previewing an unpublished upstream returned %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/quiz"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/repositories"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"github.com/google/uuid"
+	"log"
+	"time"
+)
+
+func (cs *CardsServer) GetChallengeRecords(ctx context.Context, req *cards.GetChallengeRecordsRequest) (*cards.ChallengeRecordsResponse, error) {
+	payload := req.GetPayload()
+	dto := challengeRecordsDto{UserID: payload.GetUserId(), DeckID: payload.GetDeckId(), Limit: int(payload.GetLimit())}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+	if dto.Limit == 0 {
+		dto.Limit = defaultPageSize
+	}
+
+	if _, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleViewer); err != nil {
+		return nil, err
+	}
+	// challenges abandoned before the sweeper got to them count already
+	if _, err := cs.expireChallenges(ctx, dto.UserID); err != nil {
+		return nil, operationFailure("expire challenges", err)
+	}
+	res := &cards.ChallengeRecordsResponse{}
+	best, err := cs.scores.Best(ctx, dto.UserID, dto.DeckID)
+	switch {
+	case err == nil:
+		res.Best = toProtoChallengeScore(best)
+	case !errors.Is(err, repositories.ErrNotFound):
+		return nil, operationFailure("get personal best", err)
+	}
+	recent, err := cs.scores.ListRecent(ctx, dto.UserID, dto.DeckID, dto.Limit)
+	if err != nil {
+		return nil, operationFailure("list challenge scores", err)
+	}
+	res.Recent = make([]*cards.ChallengeScore, 0, len(recent))
+	for _, score := range recent {
+		res.Recent = append(res.Recent, toProtoChallengeScore(score))
+	}
+	return res, nil
+}
+
+// expireChallengesBatch bounds the sessions loaded at once when expiring challenges
+const expireChallengesBatch = 100
+
+// ExpireChallenges completes every challenge whose time is up and records its score, it returns how many
+// were completed. Challenges are otherwise only completed when their session is loaded, which never
+// happens once the user leaves mid-challenge.
+func (cs *CardsServer) ExpireChallenges(ctx context.Context) (int, error) {
+	return cs.expireChallenges(ctx, "")
+}
+
+// expireChallenges completes the challenges of the user whose time is up, an empty userID stands for every user
+func (cs *CardsServer) expireChallenges(ctx context.Context, userID string) (int, error) {
+	expired := 0
+	for {
+		now := cs.now()
+		sessions, err := cs.sessions.ListOpenChallenges(ctx, userID, cs.quiz.ChallengeOverBefore(now), expireChallengesBatch)
+		if err != nil {
+			return expired, err
+		}
+		progress := false
+		for i := range sessions {
+			if !cs.quiz.Expire(&sessions[i], now) {
+				continue
+			}
+			progress = true
+			if err := cs.saveQuizSession(ctx, &sessions[i]); err != nil {
+				// a concurrent request completed the session first, it recorded the score
+				if errors.Is(err, errQuizSessionConflict) || errors.Is(err, errQuizSessionNotFound) {
+					continue
+				}
+				return expired, err
+			}
+			expired++
+		}
+		// sessions left open by a conflict would come back in the next batch again
+		if len(sessions) < expireChallengesBatch || !progress {
+			return expired, nil
+		}
+	}
+}
+
+// recordChallenge stores the score of a completed challenge and announces it.
+// The session is already completed at this point, so failures are only logged.
+func (cs *CardsServer) recordChallenge(ctx context.Context, session *models.QuizSession) {
+	summary := quiz.Summarize(session)
+	score := models.ChallengeScore{
+		ID:          uuid.NewString(),
+		UserID:      session.UserID,
+		DeckID:      session.DeckID,
+		SessionID:   session.ID,
+		Score:       summary.Score,
+		Answered:    summary.Answered,
+		Correct:     summary.Correct,
+		MaxCombo:    summary.MaxCombo,
+		Duration:    min(summary.ActiveDuration, session.Challenge.TimeLimit),
+		TimeLimit:   session.Challenge.TimeLimit,
+		CompletedAt: session.CompletedAt,
+	}
+
+	personalBest := score.Score > 0
+	best, err := cs.scores.Best(ctx, score.UserID, score.DeckID)
+	switch {
+	case err == nil:
+		personalBest = score.Score > best.Score
+	case !errors.Is(err, repositories.ErrNotFound):
+		log.Printf("failed to get personal best of %s: %s\n", score.UserID, err.Error())
+		personalBest = false
+	}
+	if err := cs.scores.Create(ctx, score); err != nil {
+		log.Printf("failed to record challenge %s: %s\n", session.ID, err.Error())
+		return
+	}
+
+	cs.publish(ctx, events.QuizChallengeCompletedKey, events.QuizChallengeCompleted{
+		SessionID:        score.SessionID,
+		UserID:           score.UserID,
+		DeckID:           score.DeckID,
+		Modes:            session.Modes,
+		Score:            score.Score,
+		Answered:         score.Answered,
+		Correct:          score.Correct,
+		MaxCombo:         score.MaxCombo,
+		DurationMs:       score.Duration.Milliseconds(),
+		TimeLimitSeconds: int(score.TimeLimit / time.Second),
+		PersonalBest:     personalBest,
+		CompletedAt:      score.CompletedAt.Unix(),
+	})
+}
+
+func toProtoChallengeScore(score models.ChallengeScore) *cards.ChallengeScore {
+	return &cards.ChallengeScore{
+		SessionId:        score.SessionID,
+		DeckId:           score.DeckID,
+		Score:            int32(score.Score),
+		Answered:         int32(score.Answered),
+		Correct:          int32(score.Correct),
+		MaxCombo:         int32(score.MaxCombo),
+		DurationMs:       score.Duration.Milliseconds(),
+		TimeLimitSeconds: int32(score.TimeLimit / time.Second),
+		CompletedAt:      score.CompletedAt.Unix(),
+	}
+}
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/quiz"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func startChallenge(t *testing.T, ts *testServer, deckID string, seconds int32) string {
+	t.Helper()
+	res, err := ts.StartQuiz(context.Background(), &cards.StartQuizRequest{Payload: &cards.StartQuizPayload{
+		UserId: testUserID, DeckId: deckID, TimeLimitSeconds: seconds, Seed: 1, Modes: []string{quiz.ModeTyped},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.GetSession().GetId()
+}
+
+// answer types the right answer to the question
+func answer(ts *testServer, sessionID string, index int32) (*cards.AnswerResponse, error) {
+	session, err := ts.sessions.Get(context.Background(), sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return ts.SubmitAnswer(context.Background(), &cards.SubmitAnswerRequest{Payload: &cards.SubmitAnswerPayload{
+		UserId: testUserID, SessionId: sessionID, QuestionIndex: index, Answer: session.Questions[index].Expected,
+	}})
+}
+
+func challengeRecords(t *testing.T, ts *testServer, deckID string) *cards.ChallengeRecordsResponse {
+	t.Helper()
+	res, err := ts.GetChallengeRecords(context.Background(), &cards.GetChallengeRecordsRequest{
+		Payload: &cards.GetChallengeRecordsPayload{UserId: testUserID, DeckId: deckID},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestChallengesRejectSelfGradedFlashcards(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ts.seedDeck(t, "deck", 3)
+	_, err := ts.StartQuiz(context.Background(), &cards.StartQuizRequest{Payload: &cards.StartQuizPayload{
+		UserId: testUserID, DeckId: "deck", TimeLimitSeconds: 60, Modes: []string{quiz.ModeTyped, quiz.ModeFlashcard},
+	}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("flashcard challenge returned %v, want InvalidArgument", err)
+	}
+
+	// without modes a challenge is typed, and cards no selected mode fits are typed as well
+	for _, modes := range [][]string{nil, {quiz.ModeMultipleChoice}} {
+		res, err := ts.StartQuiz(context.Background(), &cards.StartQuizRequest{Payload: &cards.StartQuizPayload{
+			UserId: testUserID, DeckId: "deck", TimeLimitSeconds: 60, Modes: modes, Seed: 1,
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		session, err := ts.sessions.Get(context.Background(), res.GetSession().GetId())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, question := range session.Questions {
+			if quiz.SelfGraded(question.Mode) {
+				t.Fatalf("challenge with modes %v asks a %s question", modes, question.Mode)
+			}
+		}
+	}
+}
+
+func TestChallengeLatencyAllowanceCoversQuestionsAskedInTime(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ts.seedDeck(t, "deck", 3)
+	sessionID := startChallenge(t, ts, "deck", 10)
+
+	// the answer to a question asked before the deadline arrives within the allowance
+	ts.clock.Advance(11 * time.Second)
+	if _, err := answer(ts, sessionID, 0); err != nil {
+		t.Fatalf("answer within the latency allowance: %v", err)
+	}
+	// the next question was shown after the deadline, the allowance doesn't cover it
+	ts.clock.Advance(100 * time.Millisecond)
+	if _, err := answer(ts, sessionID, 1); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("answer to a question asked after the deadline returned %v, want the session to be over", err)
+	}
+
+	session, err := ts.sessions.Get(context.Background(), sessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.Status != models.QuizStatusCompleted || session.Challenge.Score != quiz.ChallengeBasePoints {
+		t.Errorf("session %s with score %d, want it completed with one answer", session.Status, session.Challenge.Score)
+	}
+	if best := challengeRecords(t, ts, "deck").GetBest(); best.GetScore() != quiz.ChallengeBasePoints || best.GetAnswered() != 1 {
+		t.Errorf("best score %+v", best)
+	}
+}
+
+func TestAbandonedChallengesAreRecorded(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ts.seedDeck(t, "deck", 3)
+	ts.seedDeck(t, "other", 3)
+	abandoned := startChallenge(t, ts, "deck", 10)
+	if _, err := answer(ts, abandoned, 0); err != nil {
+		t.Fatal(err)
+	}
+	running := startChallenge(t, ts, "other", 60)
+
+	// within the allowance the last answer could still be on its way
+	ts.clock.Advance(11 * time.Second)
+	if expired, err := ts.ExpireChallenges(context.Background()); err != nil || expired != 0 {
+		t.Fatalf("expired %d challenges before the allowance ran out, %v", expired, err)
+	}
+
+	ts.clock.Advance(2 * time.Second)
+	if expired, err := ts.ExpireChallenges(context.Background()); err != nil || expired != 1 {
+		t.Fatalf("expired %d challenges, %v, want the abandoned one", expired, err)
+	}
+	if expired, err := ts.ExpireChallenges(context.Background()); err != nil || expired != 0 {
+		t.Errorf("second sweep expired %d challenges, %v", expired, err)
+	}
+
+	session, err := ts.sessions.Get(context.Background(), abandoned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.Status != models.QuizStatusCompleted || !session.CompletedAt.Equal(session.Challenge.Deadline) {
+		t.Errorf("abandoned session %s at %v, want it completed at the deadline", session.Status, session.CompletedAt)
+	}
+	if session, _ := ts.sessions.Get(context.Background(), running); session.Status != models.QuizStatusActive {
+		t.Errorf("running challenge is %s", session.Status)
+	}
+	recent := challengeRecords(t, ts, "deck").GetRecent()
+	if len(recent) != 1 || recent[0].GetScore() != quiz.ChallengeBasePoints || recent[0].GetDurationMs() != 10000 {
+		t.Errorf("recent scores %+v", recent)
+	}
+	completed := 0
+	for _, key := range ts.publisher.keys() {
+		if key == events.QuizChallengeCompletedKey {
+			completed++
+		}
+	}
+	if completed != 1 {
+		t.Errorf("published %d challenge completions, want 1", completed)
+	}
+}
+
+func TestChallengeRecordsExpireAbandonedChallenges(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ts.seedDeck(t, "deck", 3)
+	startChallenge(t, ts, "deck", 10)
+
+	// nobody swept yet, reading the records completes the challenge
+	ts.clock.Advance(time.Minute)
+	recent := challengeRecords(t, ts, "deck").GetRecent()
+	if len(recent) != 1 || recent[0].GetScore() != 0 {
+		t.Errorf("recent scores %+v, want the abandoned challenge", recent)
+	}
+}
@@ -0,0 +1,156 @@
+package server
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"github.com/google/uuid"
+	"time"
+)
+
+func (cs *CardsServer) CreateDeck(ctx context.Context, req *cards.CreateDeckRequest) (*cards.DeckResponse, error) {
+	payload := req.GetPayload()
+	dto := createDeckDto{
+		UserID:      payload.GetUserId(),
+		Title:       payload.GetTitle(),
+		Description: payload.GetDescription(),
+		Tags:        payload.GetTags(),
+		ParentID:    payload.GetParentId(),
+		FolderID:    payload.GetFolderId(),
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+	if dto.ParentID != "" {
+		if _, err := cs.getOwnedDeck(ctx, dto.UserID, dto.ParentID); err != nil {
+			return nil, err
+		}
+	}
+	if dto.FolderID != "" {
+		if _, err := cs.getOwnedFolder(ctx, dto.UserID, dto.FolderID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	deck := models.Deck{
+		ID:          uuid.NewString(),
+		OwnerID:     dto.UserID,
+		ParentID:    dto.ParentID,
+		FolderID:    dto.FolderID,
+		Title:       dto.Title,
+		Description: dto.Description,
+		Tags:        normalizeTags(dto.Tags),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := cs.decks.Create(ctx, deck); err != nil {
+		return nil, operationFailure("create deck", err)
+	}
+	cs.publish(ctx, events.DeckCreatedKey, deckChanged(deck))
+
+	return &cards.DeckResponse{Deck: toProtoDeck(deck)}, nil
+}
+
+func (cs *CardsServer) GetDeck(ctx context.Context, req *cards.GetDeckRequest) (*cards.DeckResponse, error) {
+	payload := req.GetPayload()
+	dto := deckDto{UserID: payload.GetUserId(), DeckID: payload.GetDeckId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	deck, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cards.DeckResponse{Deck: toProtoDeck(deck)}, nil
+}
+
+func (cs *CardsServer) ListDecks(ctx context.Context, req *cards.ListDecksRequest) (*cards.ListDecksResponse, error) {
+	payload := req.GetPayload()
+	dto := listDecksDto{
+		UserID: payload.GetUserId(),
+		Limit:  int(payload.GetLimit()),
+		Offset: int(payload.GetOffset()),
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+	if dto.Limit == 0 {
+		dto.Limit = defaultPageSize
+	}
+
+	decks, total, err := cs.decks.ListByOwner(ctx, dto.UserID, dto.Limit, dto.Offset)
+	if err != nil {
+		return nil, operationFailure("list decks", err)
+	}
+
+	res := &cards.ListDecksResponse{Decks: make([]*cards.Deck, 0, len(decks)), Total: int32(total)}
+	for _, deck := range decks {
+		res.Decks = append(res.Decks, toProtoDeck(deck))
+	}
+	return res, nil
+}
+
+func (cs *CardsServer) UpdateDeck(ctx context.Context, req *cards.UpdateDeckRequest) (*cards.DeckResponse, error) {
+	payload := req.GetPayload()
+	dto := updateDeckDto{
+		UserID:      payload.GetUserId(),
+		DeckID:      payload.GetDeckId(),
+		Title:       payload.GetTitle(),
+		Description: payload.GetDescription(),
+		Tags:        payload.GetTags(),
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	deck, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	deck.Title = dto.Title
+	deck.Description = dto.Description
+	deck.Tags = normalizeTags(dto.Tags)
+	deck.UpdatedAt = time.Now()
+	if err := cs.decks.Update(ctx, deck); err != nil {
+		return nil, operationFailure("update deck", err)
+	}
+	cs.publish(ctx, events.DeckUpdatedKey, deckChanged(deck))
+
+	return &cards.DeckResponse{Deck: toProtoDeck(deck)}, nil
+}
+
+func (cs *CardsServer) DeleteDeck(ctx context.Context, req *cards.DeleteDeckRequest) (*cards.DeleteResponse, error) {
+	payload := req.GetPayload()
+	dto := deckDto{UserID: payload.GetUserId(), DeckID: payload.GetDeckId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	deck, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	if err := cs.decks.Delete(ctx, deck.ID); err != nil {
+		return nil, operationFailure("delete deck", err)
+	}
+	cs.publish(ctx, events.DeckDeletedKey, events.DeckDeleted{DeckID: deck.ID, OwnerID: deck.OwnerID})
+
+	return &cards.DeleteResponse{Message: "deck is deleted"}, nil
+}
+
+func deckChanged(deck models.Deck) events.DeckChanged {
+	return events.DeckChanged{
+		DeckID:      deck.ID,
+		OwnerID:     deck.OwnerID,
+		ParentID:    deck.ParentID,
+		FolderID:    deck.FolderID,
+		Title:       deck.Title,
+		Description: deck.Description,
+		Tags:        deck.Tags,
+		UpdatedAt:   deck.UpdatedAt.Unix(),
+	}
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// strangerID owns nothing and has no deck shared with them
+const strangerID = "user-9"
+
+func TestDeckLifecycle(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	created, err := ts.CreateDeck(ctx, &cards.CreateDeckRequest{Payload: &cards.CreateDeckPayload{
+		UserId: testUserID, Title: "Spanish", Description: "verbs", Tags: []string{" Verbs ", "verbs", "A1"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deck := created.GetDeck()
+	if deck.GetOwnerId() != testUserID || !reflect.DeepEqual(deck.GetTags(), []string{"verbs", "a1"}) {
+		t.Errorf("created deck %+v", deck)
+	}
+
+	got, err := ts.GetDeck(ctx, &cards.GetDeckRequest{Payload: &cards.GetDeckPayload{UserId: testUserID, DeckId: deck.GetId()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetDeck().GetTitle() != "Spanish" {
+		t.Errorf("got deck %+v", got.GetDeck())
+	}
+
+	updated, err := ts.UpdateDeck(ctx, &cards.UpdateDeckRequest{Payload: &cards.UpdateDeckPayload{
+		UserId: testUserID, DeckId: deck.GetId(), Title: "Spanish verbs",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.GetDeck().GetTitle() != "Spanish verbs" || updated.GetDeck().GetDescription() != "" || len(updated.GetDeck().GetTags()) != 0 {
+		t.Errorf("updated deck %+v", updated.GetDeck())
+	}
+
+	if _, err := ts.DeleteDeck(ctx, &cards.DeleteDeckRequest{Payload: &cards.DeleteDeckPayload{UserId: testUserID, DeckId: deck.GetId()}}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = ts.GetDeck(ctx, &cards.GetDeckRequest{Payload: &cards.GetDeckPayload{UserId: testUserID, DeckId: deck.GetId()}})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("deleted deck returned %v, want NotFound", err)
+	}
+
+	want := []string{events.DeckCreatedKey, events.DeckUpdatedKey, events.DeckDeletedKey}
+	if keys := ts.publisher.keys(); !reflect.DeepEqual(keys, want) {
+		t.Errorf("published %v, want %v", keys, want)
+	}
+}
+
+func TestListDecks(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	for _, id := range []string{"a", "b", "c"} {
+		ts.seedDeck(t, id, 0)
+	}
+
+	page, err := ts.ListDecks(ctx, &cards.ListDecksRequest{Payload: &cards.ListDecksPayload{UserId: testUserID, Limit: 2, Offset: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.GetTotal() != 3 || len(page.GetDecks()) != 2 {
+		t.Errorf("page of %d decks out of %d, want 2 out of 3", len(page.GetDecks()), page.GetTotal())
+	}
+
+	// other users' decks are not listed
+	others, err := ts.ListDecks(ctx, &cards.ListDecksRequest{Payload: &cards.ListDecksPayload{UserId: strangerID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if others.GetTotal() != 0 || len(others.GetDecks()) != 0 {
+		t.Errorf("stranger lists %v", others.GetDecks())
+	}
+
+	_, err = ts.ListDecks(ctx, &cards.ListDecksRequest{Payload: &cards.ListDecksPayload{UserId: testUserID, Limit: 101}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("limit over 100 returned %v, want InvalidArgument", err)
+	}
+}
+
+func TestDeckValidation(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	for _, payload := range []*cards.CreateDeckPayload{
+		{Title: "no user"},
+		{UserId: testUserID},
+		{UserId: testUserID, Title: strings.Repeat("a", 201)},
+		{UserId: testUserID, Title: "empty tag", Tags: []string{""}},
+	} {
+		_, err := ts.CreateDeck(ctx, &cards.CreateDeckRequest{Payload: payload})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%+v: got %v, want InvalidArgument", payload, err)
+		}
+	}
+	if keys := ts.publisher.keys(); len(keys) != 0 {
+		t.Errorf("published %v for invalid decks", keys)
+	}
+}
+
+func TestDeckOwnership(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	ts.seedDeck(t, "deck", 0)
+
+	tests := []struct {
+		name   string
+		userID string
+		deckID string
+		code   codes.Code
+	}{
+		{"another user's deck", strangerID, "deck", codes.PermissionDenied},
+		{"missing deck", testUserID, "missing", codes.NotFound},
+		{"missing deck of nobody", strangerID, "missing", codes.NotFound},
+	}
+	for _, tt := range tests {
+		_, err := ts.GetDeck(ctx, &cards.GetDeckRequest{Payload: &cards.GetDeckPayload{UserId: tt.userID, DeckId: tt.deckID}})
+		if status.Code(err) != tt.code {
+			t.Errorf("%s: get returned %v, want %s", tt.name, err, tt.code)
+		}
+		_, err = ts.UpdateDeck(ctx, &cards.UpdateDeckRequest{Payload: &cards.UpdateDeckPayload{
+			UserId: tt.userID, DeckId: tt.deckID, Title: "taken over",
+		}})
+		if status.Code(err) != tt.code {
+			t.Errorf("%s: update returned %v, want %s", tt.name, err, tt.code)
+		}
+		_, err = ts.DeleteDeck(ctx, &cards.DeleteDeckRequest{Payload: &cards.DeleteDeckPayload{UserId: tt.userID, DeckId: tt.deckID}})
+		if status.Code(err) != tt.code {
+			t.Errorf("%s: delete returned %v, want %s", tt.name, err, tt.code)
+		}
+	}
+
+	deck, err := ts.decks.Get(ctx, "deck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deck.Title != "Deck deck" {
+		t.Errorf("deck of the owner was changed to %q", deck.Title)
+	}
+	if keys := ts.publisher.keys(); len(keys) != 0 {
+		t.Errorf("published %v for rejected changes", keys)
+	}
+}
@@ -0,0 +1,313 @@
+package server
+
+type createDeckDto struct {
+	UserID      string   `validate:"required"`
+	Title       string   `validate:"required,max=200"`
+	Description string   `validate:"max=2000"`
+	Tags        []string `validate:"max=20,dive,required,max=50"`
+	ParentID    string
+	FolderID    string
+}
+
+type deckDto struct {
+	UserID string `validate:"required"`
+	DeckID string `validate:"required"`
+}
+
+type listDecksDto struct {
+	UserID string `validate:"required"`
+	Limit  int    `validate:"min=0,max=100"`
+	Offset int    `validate:"min=0"`
+}
+
+type updateDeckDto struct {
+	UserID      string   `validate:"required"`
+	DeckID      string   `validate:"required"`
+	Title       string   `validate:"required,max=200"`
+	Description string   `validate:"max=2000"`
+	Tags        []string `validate:"max=20,dive,required,max=50"`
+}
+
+type userDto struct {
+	UserID string `validate:"required"`
+}
+
+type moveDeckDto struct {
+	UserID   string `validate:"required"`
+	DeckID   string `validate:"required"`
+	FolderID string
+}
+
+type createFolderDto struct {
+	UserID   string `validate:"required"`
+	Name     string `validate:"required,max=100"`
+	ParentID string
+}
+
+type folderDto struct {
+	UserID   string `validate:"required"`
+	FolderID string `validate:"required"`
+}
+
+type updateFolderDto struct {
+	UserID   string `validate:"required"`
+	FolderID string `validate:"required"`
+	Name     string `validate:"required,max=100"`
+	ParentID string
+}
+
+type filteredDeckDto struct {
+	UserID         string `validate:"required"`
+	FilteredDeckID string `validate:"required"`
+}
+
+type saveFilteredDeckDto struct {
+	UserID string `validate:"required"`
+	// FilteredDeckID is empty when the deck is created
+	FilteredDeckID string
+	Name           string `validate:"required,max=200"`
+	Query          string `validate:"max=1000"`
+	Limit          int    `validate:"min=0,max=1000"`
+}
+
+type dueCardsDto struct {
+	UserID         string `validate:"required"`
+	DeckID         string `validate:"required_without=FilteredDeckID,excluded_with=FilteredDeckID"`
+	FilteredDeckID string
+}
+
+type createCardDto struct {
+	UserID            string   `validate:"required"`
+	DeckID            string   `validate:"required"`
+	Front             string   `validate:"required,max=5000"`
+	Back              string   `validate:"required,max=5000"`
+	Hint              string   `validate:"max=1000"`
+	Alternatives      []string `validate:"max=20,dive,required,max=500"`
+	Tags              []string `validate:"max=20,dive,required,max=50"`
+	AnswerType        string   `validate:"oneof=text numeric"`
+	AbsoluteTolerance float64  `validate:"min=0"`
+	RelativeTolerance float64  `validate:"min=0,max=1"`
+}
+
+type cardDto struct {
+	UserID string `validate:"required"`
+	DeckID string `validate:"required"`
+	CardID string `validate:"required"`
+}
+
+type updateCardDto struct {
+	UserID            string   `validate:"required"`
+	DeckID            string   `validate:"required"`
+	CardID            string   `validate:"required"`
+	Front             string   `validate:"required,max=5000"`
+	Back              string   `validate:"required,max=5000"`
+	Hint              string   `validate:"max=1000"`
+	Alternatives      []string `validate:"max=20,dive,required,max=500"`
+	Tags              []string `validate:"max=20,dive,required,max=50"`
+	AnswerType        string   `validate:"oneof=text numeric"`
+	AbsoluteTolerance float64  `validate:"min=0"`
+	RelativeTolerance float64  `validate:"min=0,max=1"`
+}
+
+type submitReviewDto struct {
+	UserID string `validate:"required"`
+	DeckID string `validate:"required"`
+	CardID string `validate:"required"`
+	Grade  int    `validate:"min=1,max=4"`
+	// AnswerMs is at most a day, longer answers are a client bug
+	AnswerMs int `validate:"min=0,max=86400000"`
+}
+
+type startQuizDto struct {
+	UserID         string `validate:"required"`
+	DeckID         string `validate:"required_without=FilteredDeckID,excluded_with=FilteredDeckID"`
+	FilteredDeckID string
+	CardCount      int      `validate:"min=0,max=200"`
+	Order          string   `validate:"oneof=sequential random"`
+	Modes          []string `validate:"min=1,max=10,dive,required"`
+	// ChoiceCount is checked by the quiz engine, which owns the bounds
+	ChoiceCount int
+	Seed        int64
+	// TimeLimitSeconds bounds are checked by the quiz engine as well
+	TimeLimitSeconds int `validate:"min=0,max=3600"`
+}
+
+type quizSessionDto struct {
+	UserID    string `validate:"required"`
+	SessionID string `validate:"required"`
+}
+
+type submitAnswerDto struct {
+	UserID        string `validate:"required"`
+	SessionID     string `validate:"required"`
+	QuestionIndex int    `validate:"min=0"`
+	Answer        string `validate:"max=5000"`
+}
+
+type gradeAnswerDto struct {
+	UserID string `validate:"required"`
+	DeckID string `validate:"required"`
+	CardID string `validate:"required"`
+	Answer string `validate:"max=5000"`
+}
+
+type challengeRecordsDto struct {
+	UserID string `validate:"required"`
+	DeckID string `validate:"required"`
+	Limit  int    `validate:"min=0,max=100"`
+}
+
+type skipQuestionDto struct {
+	UserID        string `validate:"required"`
+	SessionID     string `validate:"required"`
+	QuestionIndex int    `validate:"min=0"`
+}
+
+type importCardsDto struct {
+	UserID    string            `validate:"required"`
+	DeckID    string            `validate:"required"`
+	Format    string            `validate:"oneof=csv tsv apkg quizlet markdown"`
+	Delimiter string            `validate:"max=1"`
+	Mapping   map[string]string `validate:"max=20"`
+	Content   []byte            `validate:"required"`
+
+	TermSeparator string `validate:"max=20"`
+	CardSeparator string `validate:"max=20"`
+}
+
+type previewImportDto struct {
+	UserID        string `validate:"required"`
+	Format        string `validate:"oneof=quizlet markdown"`
+	Content       string `validate:"required"`
+	TermSeparator string `validate:"max=20"`
+	CardSeparator string `validate:"max=20"`
+}
+
+type exportCardsDto struct {
+	UserID    string   `validate:"required"`
+	DeckID    string   `validate:"required"`
+	Format    string   `validate:"oneof=csv tsv apkg pdf"`
+	Delimiter string   `validate:"max=1"`
+	Columns   []string `validate:"max=5,dive,required"`
+}
+
+type jobDto struct {
+	UserID string `validate:"required"`
+	JobID  string `validate:"required"`
+}
+
+type searchDto struct {
+	UserID string `validate:"required"`
+	Query  string `validate:"required,max=200"`
+	Kind   string `validate:"omitempty,oneof=deck card"`
+	DeckID string
+	Limit  int `validate:"min=0,max=100"`
+	Offset int `validate:"min=0,max=1000"`
+}
+
+type autocompleteDto struct {
+	UserID string `validate:"required"`
+	Prefix string `validate:"required,max=100"`
+	Limit  int    `validate:"min=0,max=20"`
+}
+
+type createInvitationDto struct {
+	UserID         string `validate:"required"`
+	DeckID         string `validate:"required"`
+	Role           string `validate:"oneof=viewer editor owner"`
+	Email          string `validate:"omitempty,email,max=254"`
+	InviterEmail   string `validate:"omitempty,email"`
+	ExpiresInHours int    `validate:"min=0,max=720"`
+}
+
+type invitationDto struct {
+	UserID       string `validate:"required"`
+	DeckID       string `validate:"required"`
+	InvitationID string `validate:"required"`
+}
+
+type acceptInvitationDto struct {
+	UserID string `validate:"required"`
+	Email  string `validate:"omitempty,email"`
+	Token  string `validate:"required"`
+}
+
+type memberDto struct {
+	UserID   string `validate:"required"`
+	DeckID   string `validate:"required"`
+	MemberID string `validate:"required"`
+}
+
+type updateMemberDto struct {
+	UserID   string `validate:"required"`
+	DeckID   string `validate:"required"`
+	MemberID string `validate:"required"`
+	Role     string `validate:"oneof=viewer editor owner"`
+}
+
+type publishDeckDto struct {
+	UserID   string `validate:"required"`
+	DeckID   string `validate:"required"`
+	Category string `validate:"oneof=languages science mathematics history geography programming medicine arts exams other"`
+	Language string `validate:"required,bcp47_language_tag"`
+}
+
+type browseCatalogDto struct {
+	UserID   string `validate:"required"`
+	Category string `validate:"omitempty,oneof=languages science mathematics history geography programming medicine arts exams other"`
+	Language string `validate:"omitempty,bcp47_language_tag"`
+	Sort     string `validate:"omitempty,oneof=popular recent title"`
+	Limit    int    `validate:"min=0,max=100"`
+	Offset   int    `validate:"min=0"`
+}
+
+type forkDeckDto struct {
+	UserID   string `validate:"required"`
+	DeckID   string `validate:"required"`
+	FolderID string
+}
+
+type mergeUpstreamDto struct {
+	UserID          string   `validate:"required"`
+	DeckID          string   `validate:"required"`
+	UpstreamCardIDs []string `validate:"min=1,max=1000,dive,required"`
+}
+
+type studyStatsDto struct {
+	UserID   string `validate:"required"`
+	DeckID   string
+	From     string `validate:"omitempty,datetime=2006-01-02"`
+	To       string `validate:"omitempty,datetime=2006-01-02"`
+	Timezone string `validate:"omitempty,timezone"`
+}
+
+type reviewForecastDto struct {
+	UserID   string `validate:"required"`
+	DeckID   string
+	Days     int    `validate:"min=0,max=365"`
+	Timezone string `validate:"omitempty,timezone"`
+}
+
+type updateStudySettingsDto struct {
+	UserID       string `validate:"required"`
+	RolloverHour int    `validate:"min=0,max=23"`
+	GoalKind     string `validate:"oneof=cards minutes"`
+	GoalTarget   int    `validate:"min=1,max=10000"`
+}
+
+type followDto struct {
+	UserID     string `validate:"required"`
+	FolloweeID string `validate:"required,nefield=UserID"`
+}
+
+type leaderboardDto struct {
+	UserID string `validate:"required"`
+	Metric string `validate:"oneof=challenge volume"`
+	Scope  string `validate:"oneof=global deck following"`
+	DeckID string `validate:"required_if=Scope deck"`
+	// WeeksAgo reaches back as far as the boards are kept, leaderboard.KeptWeeks
+	WeeksAgo int `validate:"min=0,max=4"`
+	Offset   int `validate:"min=0"`
+	Limit    int `validate:"min=0,max=100"`
+	AroundMe bool
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/quiz"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log"
+	"strings"
+)
+
+var (
+	errDeckNotFound = status.Error(codes.NotFound, "deck is not found")
+	errCardNotFound = status.Error(codes.NotFound, "card is not found")
+	errNotDeckOwner = status.Error(codes.PermissionDenied, "deck belongs to another user")
+
+	errQuizSessionNotFound = status.Error(codes.NotFound, "quiz session is not found")
+	errNotQuizSessionOwner = status.Error(codes.PermissionDenied, "quiz session belongs to another user")
+	errQuizSessionConflict = status.Error(codes.Aborted, "quiz session was changed by another request, reload it")
+
+	errFolderNotFound = status.Error(codes.NotFound, "folder is not found")
+	errNotFolderOwner = status.Error(codes.PermissionDenied, "folder belongs to another user")
+	errFolderCycle    = status.Error(codes.InvalidArgument, "folder can't be moved into itself or a folder nested in it")
+
+	errFilteredDeckNotFound = status.Error(codes.NotFound, "filtered deck is not found")
+	errNotFilteredDeckOwner = status.Error(codes.PermissionDenied, "filtered deck belongs to another user")
+
+	errInvitationNotFound = status.Error(codes.NotFound, "invitation is not found")
+	errInvitationExpired  = status.Error(codes.FailedPrecondition, "invitation has expired")
+	errInvitationUsed     = status.Error(codes.FailedPrecondition, "invitation has already been accepted")
+	errInvitationEmail    = status.Error(codes.PermissionDenied, "invitation was sent to another email")
+	errMemberNotFound     = status.Error(codes.NotFound, "deck member is not found")
+	errDeckCreator        = status.Error(codes.FailedPrecondition, "creator of the deck is always its owner")
+
+	errNotPublished = status.Error(codes.NotFound, "deck is not published in the catalog")
+	errNotForked    = status.Error(codes.FailedPrecondition, "deck is not a fork of a published deck")
+	errUpstreamGone = status.Error(codes.FailedPrecondition, "upstream deck is no longer published")
+
+	errStatsRange = status.Error(codes.InvalidArgument, "stats range must end after it starts and span at most two years")
+
+	errAlreadyFollowing = status.Error(codes.AlreadyExists, "user is already followed")
+	errNotFollowing     = status.Error(codes.NotFound, "user is not followed")
+	errFollowingLimit   = status.Errorf(codes.FailedPrecondition, "at most %d users can be followed", maxFollowing)
+
+	errJobNotFound    = status.Error(codes.NotFound, "job is not found")
+	errNotJobOwner    = status.Error(codes.PermissionDenied, "job belongs to another user")
+	errNoJobResult    = status.Error(codes.FailedPrecondition, "only exports have a file to download")
+	errJobNotFinished = status.Error(codes.FailedPrecondition, "job is not finished yet")
+)
+
+// roleFailure is returned to members of a deck whose role does not allow the operation
+func roleFailure(role, required string) error {
+	return status.Errorf(codes.PermissionDenied, "deck is shared with the user as %s, %s is required", role, required)
+}
+
+// validationFailure converts validator errors into an InvalidArgument status
+func validationFailure(err error) error {
+	return status.Error(codes.InvalidArgument, validationMessage(err))
+}
+
+// validationMessage lists the failed fields of validator errors
+func validationMessage(err error) string {
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err.Error()
+	}
+	messages := make([]string, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		messages = append(messages, fmt.Sprintf("%s: failed on '%s'", strings.ToLower(fieldErr.Field()), fieldErr.Tag()))
+	}
+	return strings.Join(messages, "; ")
+}
+
+// operationFailure logs the underlying error and hides it from the caller
+func operationFailure(operation string, err error) error {
+	log.Printf("failed to %s: %s\n", operation, err.Error())
+	return status.Errorf(codes.Internal, "failed to %s", operation)
+}
+
+// quizFailure converts quiz engine errors into statuses
+func quizFailure(operation string, err error) error {
+	switch {
+	case errors.Is(err, quiz.ErrUnknownMode),
+		errors.Is(err, quiz.ErrUnknownOrder),
+		errors.Is(err, quiz.ErrInvalidChoiceCount),
+		errors.Is(err, quiz.ErrInvalidTimeLimit),
+		errors.Is(err, quiz.ErrSelfGradedChallenge),
+		errors.Is(err, quiz.ErrInvalidAnswer):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, quiz.ErrQuestionMismatch):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, quiz.ErrNoQuestions),
+		errors.Is(err, quiz.ErrSessionExpired),
+		errors.Is(err, quiz.ErrSessionCompleted),
+		errors.Is(err, quiz.ErrSessionPaused),
+		errors.Is(err, quiz.ErrSessionActive),
+		errors.Is(err, quiz.ErrChallengePause),
+		errors.Is(err, quiz.ErrTimeUp):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return operationFailure(operation, err)
+	}
+}
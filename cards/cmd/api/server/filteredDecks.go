@@ -0,0 +1,288 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/filter"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/repositories"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+	"time"
+)
+
+// defaultFilteredDeckLimit is used when a filtered deck does not set a limit
+const defaultFilteredDeckLimit = 100
+
+func (cs *CardsServer) CreateFilteredDeck(ctx context.Context, req *cards.CreateFilteredDeckRequest) (*cards.FilteredDeckResponse, error) {
+	payload := req.GetPayload()
+	dto := saveFilteredDeckDto{
+		UserID: payload.GetUserId(),
+		Name:   strings.TrimSpace(payload.GetName()),
+		Query:  strings.TrimSpace(payload.GetQuery()),
+		Limit:  int(payload.GetLimit()),
+	}
+	if err := cs.validateFilteredDeck(ctx, &dto); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	deck := models.FilteredDeck{
+		ID:        uuid.NewString(),
+		OwnerID:   dto.UserID,
+		Name:      dto.Name,
+		Query:     dto.Query,
+		Limit:     dto.Limit,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := cs.filtered.Create(ctx, deck); err != nil {
+		return nil, operationFailure("create filtered deck", err)
+	}
+	return &cards.FilteredDeckResponse{FilteredDeck: toProtoFilteredDeck(deck)}, nil
+}
+
+func (cs *CardsServer) GetFilteredDeck(ctx context.Context, req *cards.FilteredDeckRequest) (*cards.FilteredDeckResponse, error) {
+	payload := req.GetPayload()
+	dto := filteredDeckDto{UserID: payload.GetUserId(), FilteredDeckID: payload.GetFilteredDeckId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	deck, err := cs.getOwnedFilteredDeck(ctx, dto.UserID, dto.FilteredDeckID)
+	if err != nil {
+		return nil, err
+	}
+	return &cards.FilteredDeckResponse{FilteredDeck: toProtoFilteredDeck(deck)}, nil
+}
+
+func (cs *CardsServer) ListFilteredDecks(ctx context.Context, req *cards.ListFilteredDecksRequest) (*cards.ListFilteredDecksResponse, error) {
+	dto := userDto{UserID: req.GetPayload().GetUserId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	decks, err := cs.filtered.ListByOwner(ctx, dto.UserID)
+	if err != nil {
+		return nil, operationFailure("list filtered decks", err)
+	}
+	res := &cards.ListFilteredDecksResponse{FilteredDecks: make([]*cards.FilteredDeck, 0, len(decks))}
+	for _, deck := range decks {
+		res.FilteredDecks = append(res.FilteredDecks, toProtoFilteredDeck(deck))
+	}
+	return res, nil
+}
+
+func (cs *CardsServer) UpdateFilteredDeck(ctx context.Context, req *cards.UpdateFilteredDeckRequest) (*cards.FilteredDeckResponse, error) {
+	payload := req.GetPayload()
+	dto := saveFilteredDeckDto{
+		UserID:         payload.GetUserId(),
+		FilteredDeckID: payload.GetFilteredDeckId(),
+		Name:           strings.TrimSpace(payload.GetName()),
+		Query:          strings.TrimSpace(payload.GetQuery()),
+		Limit:          int(payload.GetLimit()),
+	}
+	if err := cs.validate.Struct(filteredDeckDto{UserID: dto.UserID, FilteredDeckID: dto.FilteredDeckID}); err != nil {
+		return nil, validationFailure(err)
+	}
+	deck, err := cs.getOwnedFilteredDeck(ctx, dto.UserID, dto.FilteredDeckID)
+	if err != nil {
+		return nil, err
+	}
+	if err := cs.validateFilteredDeck(ctx, &dto); err != nil {
+		return nil, err
+	}
+
+	deck.Name = dto.Name
+	deck.Query = dto.Query
+	deck.Limit = dto.Limit
+	deck.UpdatedAt = time.Now()
+	if err := cs.filtered.Update(ctx, deck); err != nil {
+		return nil, operationFailure("update filtered deck", err)
+	}
+	return &cards.FilteredDeckResponse{FilteredDeck: toProtoFilteredDeck(deck)}, nil
+}
+
+// DeleteFilteredDeck removes the saved query, the cards it matched stay in their decks
+func (cs *CardsServer) DeleteFilteredDeck(ctx context.Context, req *cards.FilteredDeckRequest) (*cards.DeleteResponse, error) {
+	payload := req.GetPayload()
+	dto := filteredDeckDto{UserID: payload.GetUserId(), FilteredDeckID: payload.GetFilteredDeckId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	deck, err := cs.getOwnedFilteredDeck(ctx, dto.UserID, dto.FilteredDeckID)
+	if err != nil {
+		return nil, err
+	}
+	if err := cs.filtered.Delete(ctx, deck.ID); err != nil {
+		return nil, operationFailure("delete filtered deck", err)
+	}
+	return &cards.DeleteResponse{Message: "filtered deck is deleted"}, nil
+}
+
+// validateFilteredDeck checks the fields and the query, whose decks and folders must exist, and applies the defaults
+func (cs *CardsServer) validateFilteredDeck(ctx context.Context, dto *saveFilteredDeckDto) error {
+	if err := cs.validate.Struct(dto); err != nil {
+		return validationFailure(err)
+	}
+	if dto.Limit == 0 {
+		dto.Limit = defaultFilteredDeckLimit
+	}
+	query, err := filter.Parse(dto.Query)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	_, err = cs.compileFilter(ctx, dto.UserID, query, true)
+	return err
+}
+
+// filteredCards returns the cards of the filtered deck, due is the status used when the query has no is: term
+func (cs *CardsServer) filteredCards(ctx context.Context, deck models.FilteredDeck, due string) ([]repositories.FilteredCard, error) {
+	query, err := filter.Parse(deck.Query)
+	if err != nil {
+		// queries are validated when they are saved
+		return nil, operationFailure("parse filtered deck query", err)
+	}
+	cardFilter, err := cs.compileFilter(ctx, deck.OwnerID, query, false)
+	if err != nil {
+		return nil, err
+	}
+	if cardFilter.Due == repositories.DueAny {
+		cardFilter.Due = due
+	}
+	cardFilter.Limit = deck.Limit
+
+	matches, err := cs.cards.Filter(ctx, cardFilter)
+	if err != nil {
+		return nil, operationFailure("filter cards", err)
+	}
+	return matches, nil
+}
+
+// compileFilter resolves the deck titles and folder names of the query to deck ids. Decks nested in a
+// matching deck and decks filed in a matching folder or its subfolders are included. In strict mode a
+// name without a match is an error, otherwise it matches no deck, as it does after a rename.
+func (cs *CardsServer) compileFilter(ctx context.Context, ownerID string, query filter.Query, strict bool) (repositories.CardFilter, error) {
+	cardFilter := repositories.CardFilter{
+		OwnerID:     ownerID,
+		UserID:      ownerID,
+		Tags:        query.Tags,
+		ExcludeTags: query.ExcludeTags,
+		Due:         query.Due,
+		Now:         cs.now(),
+		Comparisons: query.Comparisons,
+	}
+	if len(query.Decks)+len(query.ExcludeDecks)+len(query.Folders)+len(query.ExcludeFolders) == 0 {
+		return cardFilter, nil
+	}
+
+	decks, _, err := cs.decks.ListByOwner(ctx, ownerID, 0, 0)
+	if err != nil {
+		return cardFilter, operationFailure("list decks", err)
+	}
+	folders, err := cs.folders.ListByOwner(ctx, ownerID)
+	if err != nil {
+		return cardFilter, operationFailure("list folders", err)
+	}
+	childDecks := make(map[string][]string)
+	folderDecks := make(map[string][]string)
+	for _, deck := range decks {
+		childDecks[deck.ParentID] = append(childDecks[deck.ParentID], deck.ID)
+		if deck.FolderID != "" {
+			folderDecks[deck.FolderID] = append(folderDecks[deck.FolderID], deck.ID)
+		}
+	}
+	childFolders := make(map[string][]string)
+	for _, folder := range folders {
+		childFolders[folder.ParentID] = append(childFolders[folder.ParentID], folder.ID)
+	}
+
+	resolve := func(deckTitles, folderNames []string) ([]string, error) {
+		var roots []string
+		for _, title := range deckTitles {
+			found := false
+			for _, deck := range decks {
+				if strings.EqualFold(deck.Title, title) {
+					roots, found = append(roots, deck.ID), true
+				}
+			}
+			if !found && strict {
+				return nil, status.Errorf(codes.InvalidArgument, "query: no deck is titled %q", title)
+			}
+		}
+		for _, name := range folderNames {
+			var folderRoots []string
+			for _, folder := range folders {
+				if strings.EqualFold(folder.Name, name) {
+					folderRoots = append(folderRoots, folder.ID)
+				}
+			}
+			if len(folderRoots) == 0 && strict {
+				return nil, status.Errorf(codes.InvalidArgument, "query: no folder is named %q", name)
+			}
+			for _, folderID := range descendants(folderRoots, childFolders) {
+				roots = append(roots, folderDecks[folderID]...)
+			}
+		}
+		return descendants(roots, childDecks), nil
+	}
+
+	if len(query.Decks)+len(query.Folders) > 0 {
+		cardFilter.RestrictDecks = true
+		if cardFilter.DeckIDs, err = resolve(query.Decks, query.Folders); err != nil {
+			return cardFilter, err
+		}
+	}
+	if cardFilter.ExcludeDeckIDs, err = resolve(query.ExcludeDecks, query.ExcludeFolders); err != nil {
+		return cardFilter, err
+	}
+	return cardFilter, nil
+}
+
+// descendants returns the roots and every id nested in them, each once
+func descendants(roots []string, children map[string][]string) []string {
+	seen := make(map[string]bool, len(roots))
+	ids := []string{}
+	for len(roots) > 0 {
+		id := roots[0]
+		roots = roots[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+		roots = append(roots, children[id]...)
+	}
+	return ids
+}
+
+// getOwnedFilteredDeck loads the filtered deck and makes sure it belongs to the user
+func (cs *CardsServer) getOwnedFilteredDeck(ctx context.Context, userID, id string) (models.FilteredDeck, error) {
+	deck, err := cs.filtered.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return models.FilteredDeck{}, errFilteredDeckNotFound
+		}
+		return models.FilteredDeck{}, operationFailure("get filtered deck", err)
+	}
+	if deck.OwnerID != userID {
+		return models.FilteredDeck{}, errNotFilteredDeckOwner
+	}
+	return deck, nil
+}
+
+func toProtoFilteredDeck(deck models.FilteredDeck) *cards.FilteredDeck {
+	return &cards.FilteredDeck{
+		Id:        deck.ID,
+		OwnerId:   deck.OwnerID,
+		Name:      deck.Name,
+		Query:     deck.Query,
+		Limit:     int32(deck.Limit),
+		CreatedAt: deck.CreatedAt.Unix(),
+		UpdatedAt: deck.UpdatedAt.Unix(),
+	}
+}
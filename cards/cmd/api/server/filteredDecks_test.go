@@ -0,0 +1,160 @@
+package server
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+// seedLibrary files decks in folders:
+//
+//	Languages/        german (tagged "hard")
+//	Languages/Romance spanish, with the deck verbs nested in it
+//	                  math, outside folders
+func (ts *testServer) seedLibrary(t *testing.T) {
+	t.Helper()
+	ctx := context.Background()
+	now := ts.clock.Now()
+	for _, folder := range []models.Folder{
+		{ID: "languages", OwnerID: testUserID, Name: "Languages", CreatedAt: now, UpdatedAt: now},
+		{ID: "romance", OwnerID: testUserID, ParentID: "languages", Name: "Romance", CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := ts.folders.Create(ctx, folder); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, seeded := range []models.Deck{
+		{ID: "german", FolderID: "languages", Title: "German", Tags: []string{"hard"}},
+		{ID: "spanish", FolderID: "romance", Title: "Spanish"},
+		{ID: "verbs", ParentID: "spanish", Title: "Verbs"},
+		{ID: "math", Title: "Math"},
+	} {
+		deck, _ := ts.seedDeck(t, seeded.ID, 2)
+		deck.ParentID, deck.FolderID, deck.Title, deck.Tags = seeded.ParentID, seeded.FolderID, seeded.Title, seeded.Tags
+		if err := ts.decks.Update(ctx, deck); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	card, err := ts.cards.Get(ctx, "spanish-card-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	card.Tags = []string{"Verbs::Irregular"}
+	if err := ts.cards.Update(ctx, card); err != nil {
+		t.Fatal(err)
+	}
+	// a leech due yesterday and a card due next week
+	for _, state := range []models.ReviewState{
+		{UserID: testUserID, CardID: "math-card-1", DeckID: "math", Lapses: 3, Difficulty: 8, Due: now.Add(-24 * time.Hour)},
+		{UserID: testUserID, CardID: "math-card-2", DeckID: "math", Lapses: 0, Difficulty: 3, Due: now.Add(7 * 24 * time.Hour)},
+	} {
+		if err := ts.reviews.SaveState(ctx, state); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func createFilteredDeck(t *testing.T, ts *testServer, query string, limit int32) (*cards.FilteredDeck, error) {
+	t.Helper()
+	res, err := ts.CreateFilteredDeck(context.Background(), &cards.CreateFilteredDeckRequest{Payload: &cards.CreateFilteredDeckPayload{
+		UserId: testUserID, Name: "filtered", Query: query, Limit: limit,
+	}})
+	return res.GetFilteredDeck(), err
+}
+
+func TestFilteredDeckDueCards(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ts.seedLibrary(t)
+	tests := []struct {
+		query string
+		limit int32
+		want  []string
+	}{
+		// new cards come in the order they were added, the seeded decks got theirs at the same times
+		{"folder:languages", 0, []string{"german-card-1", "spanish-card-1", "verbs-card-1", "german-card-2", "spanish-card-2", "verbs-card-2"}},
+		{"folder:Romance -deck:verbs", 0, []string{"spanish-card-1", "spanish-card-2"}},
+		// due cards come before new ones, decks nested in a matching deck are included
+		{"deck:Spanish deck:Math", 3, []string{"math-card-1", "spanish-card-1", "verbs-card-1"}},
+		{"tag:verbs", 0, []string{"spanish-card-1"}},
+		{"tag:hard", 0, []string{"german-card-1", "german-card-2"}},
+		{"lapses>=2 difficulty>7", 0, []string{"math-card-1"}},
+		// the card due next week is studied ahead only when asked for
+		{"deck:math", 0, []string{"math-card-1"}},
+		{"deck:math is:review", 0, []string{"math-card-1", "math-card-2"}},
+		{"deck:math is:new", 0, []string{}},
+	}
+	for _, tt := range tests {
+		deck, err := createFilteredDeck(t, ts, tt.query, tt.limit)
+		if err != nil {
+			t.Fatalf("create %q: %v", tt.query, err)
+		}
+		res, err := ts.GetDueCards(context.Background(), &cards.GetDueCardsRequest{
+			Payload: &cards.GetDueCardsPayload{UserId: testUserID, FilteredDeckId: deck.GetId()},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := cardIDs(res.GetCards()); !equalStrings(got, tt.want) {
+			t.Errorf("%q studies %v, want %v", tt.query, got, tt.want)
+		}
+		for _, card := range res.GetCards() {
+			if card.GetIsNew() == (card.GetState() != nil) {
+				t.Errorf("%q: card %s is new %v with state %v", tt.query, card.GetCard().GetId(), card.GetIsNew(), card.GetState())
+			}
+		}
+	}
+}
+
+func TestFilteredDeckQuiz(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ts.seedLibrary(t)
+	deck, err := createFilteredDeck(t, ts, "tag:hard", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := ts.StartQuiz(context.Background(), &cards.StartQuizRequest{Payload: &cards.StartQuizPayload{
+		UserId: testUserID, FilteredDeckId: deck.GetId(),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session := res.GetSession(); session.GetDeckId() != deck.GetId() || session.GetTotalQuestions() != 2 {
+		t.Errorf("session of deck %s with %d questions, want the filtered deck with 2", session.GetDeckId(), session.GetTotalQuestions())
+	}
+
+	_, err = ts.StartQuiz(context.Background(), &cards.StartQuizRequest{Payload: &cards.StartQuizPayload{
+		UserId: testUserID, DeckId: "math", FilteredDeckId: deck.GetId(),
+	}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("quiz of a deck and a filtered deck returned %v, want InvalidArgument", err)
+	}
+}
+
+func TestFilteredDeckValidatesQueries(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ts.seedLibrary(t)
+	for _, query := range []string{"tag:", "deck:Nope", "-folder:Nope", "is:new lapses>1", "spanish"} {
+		if _, err := createFilteredDeck(t, ts, query, 0); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("query %q returned %v, want InvalidArgument", query, err)
+		}
+	}
+
+	deck, err := createFilteredDeck(t, ts, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deck.GetLimit() != defaultFilteredDeckLimit {
+		t.Errorf("limit %d, want the default", deck.GetLimit())
+	}
+	_, err = ts.GetFilteredDeck(context.Background(), &cards.FilteredDeckRequest{
+		Payload: &cards.FilteredDeckPayload{UserId: "user-2", FilteredDeckId: deck.GetId()},
+	})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("another user got the filtered deck: %v", err)
+	}
+}
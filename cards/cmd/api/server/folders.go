@@ -0,0 +1,178 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/repositories"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+func (cs *CardsServer) CreateFolder(ctx context.Context, req *cards.CreateFolderRequest) (*cards.FolderResponse, error) {
+	payload := req.GetPayload()
+	dto := createFolderDto{
+		UserID:   payload.GetUserId(),
+		Name:     strings.TrimSpace(payload.GetName()),
+		ParentID: payload.GetParentId(),
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+	if dto.ParentID != "" {
+		if _, err := cs.getOwnedFolder(ctx, dto.UserID, dto.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	folder := models.Folder{
+		ID:        uuid.NewString(),
+		OwnerID:   dto.UserID,
+		ParentID:  dto.ParentID,
+		Name:      dto.Name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := cs.folders.Create(ctx, folder); err != nil {
+		return nil, operationFailure("create folder", err)
+	}
+	return &cards.FolderResponse{Folder: toProtoFolder(folder)}, nil
+}
+
+func (cs *CardsServer) ListFolders(ctx context.Context, req *cards.ListFoldersRequest) (*cards.ListFoldersResponse, error) {
+	dto := userDto{UserID: req.GetPayload().GetUserId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	folders, err := cs.folders.ListByOwner(ctx, dto.UserID)
+	if err != nil {
+		return nil, operationFailure("list folders", err)
+	}
+	res := &cards.ListFoldersResponse{Folders: make([]*cards.Folder, 0, len(folders))}
+	for _, folder := range folders {
+		res.Folders = append(res.Folders, toProtoFolder(folder))
+	}
+	return res, nil
+}
+
+// UpdateFolder renames the folder and moves it under another one
+func (cs *CardsServer) UpdateFolder(ctx context.Context, req *cards.UpdateFolderRequest) (*cards.FolderResponse, error) {
+	payload := req.GetPayload()
+	dto := updateFolderDto{
+		UserID:   payload.GetUserId(),
+		FolderID: payload.GetFolderId(),
+		Name:     strings.TrimSpace(payload.GetName()),
+		ParentID: payload.GetParentId(),
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	folder, err := cs.getOwnedFolder(ctx, dto.UserID, dto.FolderID)
+	if err != nil {
+		return nil, err
+	}
+	if dto.ParentID != folder.ParentID && dto.ParentID != "" {
+		if _, err := cs.getOwnedFolder(ctx, dto.UserID, dto.ParentID); err != nil {
+			return nil, err
+		}
+		folders, err := cs.folders.ListByOwner(ctx, dto.UserID)
+		if err != nil {
+			return nil, operationFailure("list folders", err)
+		}
+		parents := make(map[string]string, len(folders))
+		for _, other := range folders {
+			parents[other.ID] = other.ParentID
+		}
+		for id := dto.ParentID; id != ""; id = parents[id] {
+			if id == folder.ID {
+				return nil, errFolderCycle
+			}
+		}
+	}
+
+	folder.Name = dto.Name
+	folder.ParentID = dto.ParentID
+	folder.UpdatedAt = time.Now()
+	if err := cs.folders.Update(ctx, folder); err != nil {
+		return nil, operationFailure("update folder", err)
+	}
+	return &cards.FolderResponse{Folder: toProtoFolder(folder)}, nil
+}
+
+// DeleteFolder removes the folder, its folders and decks move to its parent
+func (cs *CardsServer) DeleteFolder(ctx context.Context, req *cards.DeleteFolderRequest) (*cards.DeleteResponse, error) {
+	payload := req.GetPayload()
+	dto := folderDto{UserID: payload.GetUserId(), FolderID: payload.GetFolderId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	folder, err := cs.getOwnedFolder(ctx, dto.UserID, dto.FolderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := cs.folders.Delete(ctx, folder.ID); err != nil {
+		return nil, operationFailure("delete folder", err)
+	}
+	return &cards.DeleteResponse{Message: "folder is deleted"}, nil
+}
+
+// MoveDeck files the deck in a folder or takes it out of its folder
+func (cs *CardsServer) MoveDeck(ctx context.Context, req *cards.MoveDeckRequest) (*cards.DeckResponse, error) {
+	payload := req.GetPayload()
+	dto := moveDeckDto{UserID: payload.GetUserId(), DeckID: payload.GetDeckId(), FolderID: payload.GetFolderId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	deck, err := cs.getOwnedDeck(ctx, dto.UserID, dto.DeckID)
+	if err != nil {
+		return nil, err
+	}
+	if dto.FolderID != "" {
+		if _, err := cs.getOwnedFolder(ctx, dto.UserID, dto.FolderID); err != nil {
+			return nil, err
+		}
+	}
+
+	deck.FolderID = dto.FolderID
+	deck.UpdatedAt = time.Now()
+	if err := cs.decks.Update(ctx, deck); err != nil {
+		return nil, operationFailure("move deck", err)
+	}
+	cs.publish(ctx, events.DeckUpdatedKey, deckChanged(deck))
+
+	return &cards.DeckResponse{Deck: toProtoDeck(deck)}, nil
+}
+
+// getOwnedFolder loads the folder and makes sure it belongs to the user
+func (cs *CardsServer) getOwnedFolder(ctx context.Context, userID, folderID string) (models.Folder, error) {
+	folder, err := cs.folders.Get(ctx, folderID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return models.Folder{}, errFolderNotFound
+		}
+		return models.Folder{}, operationFailure("get folder", err)
+	}
+	if folder.OwnerID != userID {
+		return models.Folder{}, errNotFolderOwner
+	}
+	return folder, nil
+}
+
+func toProtoFolder(folder models.Folder) *cards.Folder {
+	return &cards.Folder{
+		Id:        folder.ID,
+		OwnerId:   folder.OwnerID,
+		ParentId:  folder.ParentID,
+		Name:      folder.Name,
+		CreatedAt: folder.CreatedAt.Unix(),
+		UpdatedAt: folder.UpdatedAt.Unix(),
+	}
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func createFolder(t *testing.T, ts *testServer, name, parentID string) *cards.Folder {
+	t.Helper()
+	res, err := ts.CreateFolder(context.Background(), &cards.CreateFolderRequest{Payload: &cards.CreateFolderPayload{
+		UserId: testUserID, Name: name, ParentId: parentID,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.GetFolder()
+}
+
+func TestFolders(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	languages := createFolder(t, ts, "Languages", "")
+	romance := createFolder(t, ts, "Romance", languages.GetId())
+	ts.seedDeck(t, "spanish", 1)
+
+	moved, err := ts.MoveDeck(ctx, &cards.MoveDeckRequest{Payload: &cards.MoveDeckPayload{
+		UserId: testUserID, DeckId: "spanish", FolderId: romance.GetId(),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.GetDeck().GetFolderId() != romance.GetId() {
+		t.Errorf("deck filed in %q", moved.GetDeck().GetFolderId())
+	}
+
+	// a folder can't move into a folder nested in it
+	_, err = ts.UpdateFolder(ctx, &cards.UpdateFolderRequest{Payload: &cards.UpdateFolderPayload{
+		UserId: testUserID, FolderId: languages.GetId(), Name: "Languages", ParentId: romance.GetId(),
+	}})
+	if err != errFolderCycle {
+		t.Errorf("moving a folder into its child returned %v", err)
+	}
+
+	// deleting a folder moves its content to the parent
+	if _, err := ts.DeleteFolder(ctx, &cards.DeleteFolderRequest{Payload: &cards.DeleteFolderPayload{
+		UserId: testUserID, FolderId: romance.GetId(),
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	deck, err := ts.decks.Get(ctx, "spanish")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deck.FolderID != languages.GetId() {
+		t.Errorf("deck of the deleted folder is filed in %q, want the parent", deck.FolderID)
+	}
+	folders, err := ts.ListFolders(ctx, &cards.ListFoldersRequest{Payload: &cards.ListFoldersPayload{UserId: testUserID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(folders.GetFolders()) != 1 || folders.GetFolders()[0].GetId() != languages.GetId() {
+		t.Errorf("folders left %v", folders.GetFolders())
+	}
+}
+
+func TestFoldersOfOtherUsers(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	folder := createFolder(t, ts, "Mine", "")
+	ts.seedDeck(t, "deck", 0)
+
+	_, err := ts.CreateFolder(ctx, &cards.CreateFolderRequest{Payload: &cards.CreateFolderPayload{
+		UserId: "user-2", Name: "Theirs", ParentId: folder.GetId(),
+	}})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("nesting in a folder of another user returned %v", err)
+	}
+	_, err = ts.CreateDeck(ctx, &cards.CreateDeckRequest{Payload: &cards.CreateDeckPayload{
+		UserId: "user-2", Title: "Theirs", FolderId: folder.GetId(),
+	}})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("filing a deck in a folder of another user returned %v", err)
+	}
+	_, err = ts.MoveDeck(ctx, &cards.MoveDeckRequest{Payload: &cards.MoveDeckPayload{
+		UserId: testUserID, DeckId: "deck", FolderId: "missing",
+	}})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("moving a deck to a missing folder returned %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/grading"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+)
+
+func (cs *CardsServer) GradeAnswer(ctx context.Context, req *cards.GradeAnswerRequest) (*cards.GradeAnswerResponse, error) {
+	payload := req.GetPayload()
+	dto := gradeAnswerDto{
+		UserID: payload.GetUserId(),
+		DeckID: payload.GetDeckId(),
+		CardID: payload.GetCardId(),
+		Answer: payload.GetAnswer(),
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	deck, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	card, err := cs.getDeckCard(ctx, deck.ID, dto.CardID)
+	if err != nil {
+		return nil, err
+	}
+
+	grader := grading.New(card.AnswerType, grading.Options{
+		Strict:            payload.GetStrict(),
+		AbsoluteTolerance: card.AbsoluteTolerance,
+		RelativeTolerance: card.RelativeTolerance,
+	})
+	result := grader.Grade(dto.Answer, append([]string{card.Back}, card.Alternatives...))
+	return &cards.GradeAnswerResponse{
+		Verdict:  result.Verdict,
+		Correct:  result.Correct(),
+		Matched:  result.Matched,
+		Distance: int32(result.Distance),
+		Diff:     toProtoDiff(result.Diff),
+		Reason:   result.Reason,
+	}, nil
+}
+
+func toProtoDiff(diff []grading.DiffSegment) []*cards.DiffSegment {
+	segments := make([]*cards.DiffSegment, 0, len(diff))
+	for _, segment := range diff {
+		segments = append(segments, &cards.DiffSegment{Op: segment.Op, Text: segment.Text})
+	}
+	return segments
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/grading"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestGradeAnswer(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	ts.seedDeck(t, "deck", 0)
+	now := ts.clock.Now()
+	for _, card := range []models.Card{
+		{ID: "word", DeckID: "deck", Front: "to get", Back: "receive", Alternatives: []string{"obtain"}, AnswerType: grading.AnswerText},
+		{ID: "speed", DeckID: "deck", Front: "speed", Back: "36 km/h", AnswerType: grading.AnswerNumeric, RelativeTolerance: 0.01},
+	} {
+		card.CreatedAt, card.UpdatedAt = now, now
+		if err := ts.cards.Create(ctx, card); err != nil {
+			t.Fatal(err)
+		}
+	}
+	grade := func(cardID, answer string, strict bool) *cards.GradeAnswerResponse {
+		t.Helper()
+		res, err := ts.GradeAnswer(ctx, &cards.GradeAnswerRequest{Payload: &cards.GradeAnswerPayload{
+			UserId: testUserID, DeckId: "deck", CardId: cardID, Answer: answer, Strict: strict,
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	res := grade("word", "Recieve", false)
+	want := []string{"equal:rec", "extra:i", "equal:e", "missing:i", "equal:ve"}
+	if res.GetVerdict() != grading.VerdictAlmost || !res.GetCorrect() || res.GetMatched() != "receive" || !equalProtoDiff(res.GetDiff(), want) {
+		t.Errorf("typo graded %+v, want almost with diff %v", res, want)
+	}
+	if res := grade("word", "Recieve", true); res.GetVerdict() != grading.VerdictWrong || res.GetCorrect() || len(res.GetDiff()) == 0 {
+		t.Errorf("strict typo graded %+v, want wrong with a diff", res)
+	}
+	if res := grade("word", "obtain", false); res.GetVerdict() != grading.VerdictCorrect || res.GetMatched() != "obtain" {
+		t.Errorf("alternative graded %+v, want correct", res)
+	}
+	if res := grade("speed", "10 m/s", false); res.GetVerdict() != grading.VerdictCorrect {
+		t.Errorf("converted speed graded %+v, want correct", res)
+	}
+	if res := grade("speed", "36", false); res.GetVerdict() != grading.VerdictAlmost || res.GetReason() == "" {
+		t.Errorf("speed without a unit graded %+v, want almost with a reason", res)
+	}
+
+	_, err := ts.GradeAnswer(ctx, &cards.GradeAnswerRequest{Payload: &cards.GradeAnswerPayload{
+		UserId: strangerID, DeckId: "deck", CardId: "word", Answer: "receive",
+	}})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("stranger grading returned %v, want PermissionDenied", err)
+	}
+}
+
+// equalProtoDiff compares a diff with segments written as "op:text"
+func equalProtoDiff(diff []*cards.DiffSegment, want []string) bool {
+	if len(diff) != len(want) {
+		return false
+	}
+	for i, segment := range diff {
+		if segment.GetOp()+":"+segment.GetText() != want[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,682 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/anki"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/grading"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/printout"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/repositories"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/spreadsheet"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/textimport"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"github.com/Salladin95/card-quizzler-microservices/consumer"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"hash/fnv"
+	"io"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// importNamespace derives card ids from the job and the line, so a retried import doesn't duplicate cards
+var importNamespace = uuid.MustParse("6f1c3a52-8f0e-4b8e-9d57-2a4f0c1e7b93")
+
+// jobFailure is an error the user can act on, the job fails at once with its message instead of being retried
+type jobFailure struct {
+	message string
+}
+
+func (e jobFailure) Error() string {
+	return e.message
+}
+
+var errJobDeckGone = jobFailure{message: "deck is not found"}
+
+func (cs *CardsServer) ImportCards(ctx context.Context, req *cards.ImportCardsRequest) (*cards.JobResponse, error) {
+	payload := req.GetPayload()
+	dto := importCardsDto{
+		UserID:    payload.GetUserId(),
+		DeckID:    payload.GetDeckId(),
+		Format:    payload.GetFormat(),
+		Delimiter: payload.GetDelimiter(),
+		Mapping:   payload.GetMapping(),
+		Content:   payload.GetContent(),
+
+		TermSeparator: payload.GetTermSeparator(),
+		CardSeparator: payload.GetCardSeparator(),
+	}
+	if dto.Format == "" {
+		dto.Format = spreadsheet.FormatCSV
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+	if len(dto.Content) > cs.config.MAX_IMPORT_BYTES {
+		return nil, status.Errorf(codes.InvalidArgument, "file is larger than %d bytes", cs.config.MAX_IMPORT_BYTES)
+	}
+	options := models.JobOptions{
+		Delimiter: dto.Delimiter,
+		NoHeader:  payload.GetNoHeader(),
+		Mapping:   dto.Mapping,
+		DryRun:    payload.GetDryRun(),
+
+		TermSeparator: dto.TermSeparator,
+		CardSeparator: dto.CardSeparator,
+	}
+	// a broken package or a wrong mapping is reported before the job is queued
+	if err := checkImport(dto.Format, dto.Content, options); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	deck, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	job := cs.newJob(dto.UserID, deck.ID, models.JobImportCards, dto.Format, options)
+	job.Input = dto.Content
+	// packages are always imported in the background, reading the collection takes a while
+	inline := dto.Format != anki.FormatApkg && len(dto.Content) <= cs.config.IMPORT_SYNC_BYTES
+	return cs.startJob(ctx, job, inline)
+}
+
+func (cs *CardsServer) ExportCards(ctx context.Context, req *cards.ExportCardsRequest) (*cards.JobResponse, error) {
+	payload := req.GetPayload()
+	dto := exportCardsDto{
+		UserID:    payload.GetUserId(),
+		DeckID:    payload.GetDeckId(),
+		Format:    payload.GetFormat(),
+		Delimiter: payload.GetDelimiter(),
+		Columns:   payload.GetColumns(),
+	}
+	if dto.Format == "" {
+		dto.Format = spreadsheet.FormatCSV
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+	options := models.JobOptions{Delimiter: dto.Delimiter, NoHeader: payload.GetNoHeader(), Columns: dto.Columns}
+	if dto.Format == printout.FormatPDF {
+		options.Print = fromProtoPrintOptions(payload.GetPrint())
+	}
+	if err := checkExport(dto.Format, options); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	deck, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	deckCards, err := cs.cards.ListByDeck(ctx, deck.ID)
+	if err != nil {
+		return nil, operationFailure("list cards", err)
+	}
+	job := cs.newJob(dto.UserID, deck.ID, models.JobExportCards, dto.Format, options)
+	// laying out pages takes a while, PDFs are always printed in the background
+	inline := dto.Format != printout.FormatPDF && len(deckCards) <= cs.config.EXPORT_SYNC_CARDS
+	return cs.startJob(ctx, job, inline)
+}
+
+func (cs *CardsServer) GetJob(ctx context.Context, req *cards.JobRequest) (*cards.JobResponse, error) {
+	job, err := cs.getOwnedJob(ctx, req.GetPayload())
+	if err != nil {
+		return nil, err
+	}
+	return &cards.JobResponse{Job: toProtoJob(job)}, nil
+}
+
+// GetJobResult returns the file of a finished export
+func (cs *CardsServer) GetJobResult(ctx context.Context, req *cards.JobRequest) (*cards.JobResultResponse, error) {
+	job, err := cs.getOwnedJob(ctx, req.GetPayload())
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case job.Kind != models.JobExportCards:
+		return nil, errNoJobResult
+	case !job.Finished():
+		return nil, errJobNotFinished
+	case job.Status == models.JobFailed:
+		return nil, status.Errorf(codes.FailedPrecondition, "job failed: %s", job.Error)
+	}
+	return &cards.JobResultResponse{Filename: job.OutputName, ContentType: job.OutputType, Content: job.Output}, nil
+}
+
+// HandleJobEvent processes a queued job, returned errors make the consumer retry it
+func (cs *CardsServer) HandleJobEvent(ctx context.Context, key string, payload []byte) error {
+	if key != events.JobQueuedKey {
+		return consumer.Permanent(fmt.Errorf("unexpected event %s", key))
+	}
+	var event events.JobQueued
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return consumer.Permanent(err)
+	}
+	job, err := cs.jobs.Get(ctx, event.JobID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return consumer.Permanent(err)
+		}
+		return err
+	}
+	if job.Finished() {
+		// a redelivered message
+		return nil
+	}
+	return cs.runJob(ctx, &job, true)
+}
+
+func (cs *CardsServer) newJob(userID, deckID, kind, format string, options models.JobOptions) models.Job {
+	now := cs.now()
+	return models.Job{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		DeckID:    deckID,
+		Kind:      kind,
+		Status:    models.JobQueued,
+		Format:    format,
+		Options:   options,
+		Report:    models.JobReport{Errors: []models.JobRowError{}, Warnings: []string{}},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// startJob stores the job and either runs it right away or queues it for a worker
+func (cs *CardsServer) startJob(ctx context.Context, job models.Job, inline bool) (*cards.JobResponse, error) {
+	if err := cs.jobs.Create(ctx, job); err != nil {
+		return nil, operationFailure("create job", err)
+	}
+	if inline {
+		if err := cs.runJob(ctx, &job, false); err != nil {
+			return nil, operationFailure("run "+job.Kind+" job", err)
+		}
+		return &cards.JobResponse{Job: toProtoJob(job)}, nil
+	}
+
+	if err := cs.publisher.Publish(ctx, events.JobQueuedKey, events.JobQueued{JobID: job.ID, Kind: job.Kind}); err != nil {
+		return nil, cs.abandonJob(ctx, job, err)
+	}
+	return &cards.JobResponse{Job: toProtoJob(job)}, nil
+}
+
+// abandonJob fails a job that couldn't be queued, nobody would ever pick it up
+func (cs *CardsServer) abandonJob(ctx context.Context, job models.Job, err error) error {
+	job.Status = models.JobFailed
+	job.Error = "failed to queue job"
+	job.UpdatedAt = cs.now()
+	job.CompletedAt = job.UpdatedAt
+	if updateErr := cs.jobs.Update(ctx, job); updateErr != nil {
+		log.Printf("failed to fail job %s: %s\n", job.ID, updateErr.Error())
+	}
+	return operationFailure("queue job", err)
+}
+
+// runJob processes the job and stores the outcome. With retry set an unexpected error puts the job
+// back in the queue and is returned until the attempts run out, then the job fails like an inline one.
+func (cs *CardsServer) runJob(ctx context.Context, job *models.Job, retry bool) error {
+	job.Status = models.JobRunning
+	job.Attempts++
+	job.UpdatedAt = cs.now()
+	if err := cs.jobs.Update(ctx, *job); err != nil {
+		return err
+	}
+
+	var err error
+	switch job.Kind {
+	case models.JobImportCards:
+		err = cs.importCards(ctx, job)
+	case models.JobExportCards:
+		err = cs.exportCards(ctx, job)
+	default:
+		err = jobFailure{message: fmt.Sprintf("unknown job kind %q", job.Kind)}
+	}
+
+	job.UpdatedAt = cs.now()
+	var failure jobFailure
+	switch {
+	case err == nil:
+		job.Status = models.JobSucceeded
+		job.CompletedAt = job.UpdatedAt
+	case errors.As(err, &failure):
+		job.Status = models.JobFailed
+		job.Error = failure.message
+		job.CompletedAt = job.UpdatedAt
+		err = nil
+	case retry && job.Attempts < cs.config.JOB_MAX_ATTEMPTS:
+		job.Status = models.JobQueued
+	default:
+		job.Status = models.JobFailed
+		job.Error = "failed to process job"
+		job.CompletedAt = job.UpdatedAt
+	}
+	if updateErr := cs.jobs.Update(ctx, *job); updateErr != nil {
+		return updateErr
+	}
+	return err
+}
+
+// importCards creates a card per valid row, package card or text card, or only validates them on a dry run
+func (cs *CardsServer) importCards(ctx context.Context, job *models.Job) error {
+	deck, err := cs.jobDeck(ctx, job)
+	if err != nil {
+		return err
+	}
+	report := models.JobReport{Errors: []models.JobRowError{}, Warnings: []string{}}
+	switch job.Format {
+	case anki.FormatApkg:
+		err = cs.importPackage(ctx, job, deck, &report)
+	case textimport.FormatQuizlet, textimport.FormatMarkdown:
+		err = cs.importText(ctx, job, deck, &report)
+	default:
+		err = cs.importSpreadsheet(ctx, job, deck, &report)
+	}
+	if err != nil {
+		return err
+	}
+	job.Report = report
+	return nil
+}
+
+func (cs *CardsServer) importSpreadsheet(ctx context.Context, job *models.Job, deck models.Deck, report *models.JobReport) error {
+	reader, err := spreadsheet.NewReader(bytes.NewReader(job.Input), spreadsheetOptions(job.Format, job.Options))
+	if err != nil {
+		return jobFailure{message: err.Error()}
+	}
+	for {
+		row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		var rowErr *spreadsheet.RowError
+		if errors.As(err, &rowErr) {
+			report.Rows++
+			report.Fail(rowErr.Line, rowErr.Err.Error())
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		report.Rows++
+		dto := createCardDto{
+			Front:        row.Front,
+			Back:         row.Back,
+			Hint:         row.Hint,
+			Alternatives: row.Alternatives,
+			Tags:         normalizeTags(row.Tags),
+		}
+		if err := cs.importCard(ctx, job, deck, report, row.Line, row.Line, dto, nil); err != nil {
+			return err
+		}
+	}
+}
+
+// importPackage imports the cards of an Anki package with their scheduling, positions take the place of lines
+func (cs *CardsServer) importPackage(ctx context.Context, job *models.Job, deck models.Deck, report *models.JobReport) error {
+	pkg, err := anki.Read(job.Input)
+	if err != nil {
+		if errors.Is(err, anki.ErrInvalidPackage) || errors.Is(err, anki.ErrUnsupportedCollection) {
+			return jobFailure{message: err.Error()}
+		}
+		return err
+	}
+	for _, skipped := range pkg.Skipped {
+		report.Rows++
+		report.Fail(skipped.Position, skipped.Reason)
+	}
+	// subdecks would be lost in a single deck, their names become tags
+	decks := make(map[string]bool)
+	for _, card := range pkg.Cards {
+		decks[card.Deck] = true
+	}
+	for _, card := range pkg.Cards {
+		report.Rows++
+		tags := card.Tags
+		if len(decks) > 1 {
+			tags = append(tags, subdeckTag(card.Deck))
+		}
+		dto := createCardDto{
+			Front:        card.Front,
+			Back:         card.Back,
+			Hint:         card.Hint,
+			Alternatives: card.Alternatives,
+			Tags:         normalizeTags(tags),
+		}
+		if err := cs.importCard(ctx, job, deck, report, card.Position, card.Position, dto, card.Schedule); err != nil {
+			return err
+		}
+	}
+	if len(pkg.Media) > 0 {
+		report.Warn(fmt.Sprintf("%d media files were not imported, cards mention them by name", len(pkg.Media)))
+	}
+	return nil
+}
+
+// importCard validates a card read at the line of the file and stores it unless the job is a dry run.
+// The order of the card within the file derives its id, it differs from the line when a line holds several cards.
+// The schedule carries the review state over from another app.
+func (cs *CardsServer) importCard(ctx context.Context, job *models.Job, deck models.Deck, report *models.JobReport,
+	line, order int, dto createCardDto, schedule *anki.Schedule) error {
+	dto.UserID = job.UserID
+	dto.DeckID = deck.ID
+	dto.AnswerType = grading.AnswerText
+	if err := cs.validate.Struct(dto); err != nil {
+		report.Fail(line, validationMessage(err))
+		return nil
+	}
+	report.Valid++
+	if job.Options.DryRun {
+		return nil
+	}
+
+	// spacing creation times by order keeps the cards in the order of the file
+	createdAt := job.CreatedAt.Add(time.Duration(order) * time.Microsecond)
+	card := models.Card{
+		ID:           uuid.NewSHA1(importNamespace, []byte(fmt.Sprintf("%s:%d", job.ID, order))).String(),
+		DeckID:       deck.ID,
+		Front:        dto.Front,
+		Back:         dto.Back,
+		Hint:         dto.Hint,
+		Alternatives: dto.Alternatives,
+		Tags:         dto.Tags,
+		AnswerType:   dto.AnswerType,
+		CreatedAt:    createdAt,
+		UpdatedAt:    createdAt,
+	}
+	switch err := cs.cards.Create(ctx, card); {
+	case err == nil:
+		cs.publish(ctx, events.CardCreatedKey, cardChanged(deck, card))
+	case errors.Is(err, repositories.ErrAlreadyExists):
+		// created by a previous attempt
+	case errors.Is(err, repositories.ErrNotFound):
+		return errJobDeckGone
+	default:
+		return err
+	}
+	if schedule != nil {
+		if err := cs.reviews.SaveState(ctx, cs.importedState(job, card, schedule)); err != nil {
+			if errors.Is(err, repositories.ErrNotFound) {
+				return errJobDeckGone
+			}
+			return err
+		}
+	}
+	report.Imported++
+	return nil
+}
+
+func (cs *CardsServer) importedState(job *models.Job, card models.Card, schedule *anki.Schedule) models.ReviewState {
+	return models.ReviewState{
+		UserID:       job.UserID,
+		CardID:       card.ID,
+		DeckID:       card.DeckID,
+		Algorithm:    cs.scheduler.Name(),
+		Repetitions:  schedule.Repetitions,
+		Lapses:       schedule.Lapses,
+		IntervalDays: schedule.IntervalDays,
+		Ease:         schedule.Ease,
+		Stability:    schedule.Stability,
+		Difficulty:   schedule.Difficulty,
+		Due:          schedule.Due,
+		LastReview:   schedule.LastReview,
+	}
+}
+
+func (cs *CardsServer) exportCards(ctx context.Context, job *models.Job) error {
+	deck, err := cs.jobDeck(ctx, job)
+	if err != nil {
+		return err
+	}
+	deckCards, err := cs.cards.ListByDeck(ctx, deck.ID)
+	if err != nil {
+		return err
+	}
+
+	var output bytes.Buffer
+	job.OutputName = exportFileName(deck.Title, job.Format)
+	switch job.Format {
+	case anki.FormatApkg:
+		err = cs.exportPackage(ctx, job, deck, deckCards, &output)
+		job.OutputType = anki.ContentType
+	case printout.FormatPDF:
+		err = cs.exportPDF(job, deck, deckCards, &output)
+		job.OutputType = printout.ContentType
+		if printOptions(job).Layout == printout.LayoutTest {
+			job.OutputName = exportFileName(deck.Title+" test", job.Format)
+		}
+	default:
+		err = exportSpreadsheet(job, deckCards, &output)
+		job.OutputType = spreadsheet.ContentType(job.Format)
+	}
+	if err != nil {
+		return err
+	}
+	job.Output = output.Bytes()
+	job.Report = models.JobReport{Rows: len(deckCards), Valid: len(deckCards), Errors: []models.JobRowError{}, Warnings: []string{}}
+	return nil
+}
+
+func exportSpreadsheet(job *models.Job, deckCards []models.Card, output io.Writer) error {
+	writer, err := spreadsheet.NewWriter(output, spreadsheetOptions(job.Format, job.Options), job.Options.Columns)
+	if err != nil {
+		return jobFailure{message: err.Error()}
+	}
+	for _, card := range deckCards {
+		if err := writer.Write(card); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+// exportPDF prints flashcard sheets or a practice test
+func (cs *CardsServer) exportPDF(job *models.Job, deck models.Deck, deckCards []models.Card, output io.Writer) error {
+	printed := make([]printout.Card, 0, len(deckCards))
+	for _, card := range deckCards {
+		printed = append(printed, printout.Card{
+			Front:        card.Front,
+			Back:         card.Back,
+			Hint:         card.Hint,
+			Alternatives: card.Alternatives,
+		})
+	}
+	err := cs.printer.Print(output, deck.Title, printed, printOptions(job), cs.now())
+	if errors.Is(err, printout.ErrInvalidOptions) {
+		return jobFailure{message: err.Error()}
+	}
+	return err
+}
+
+// exportPackage writes an Anki package, the user's review states become the scheduling of the cards
+func (cs *CardsServer) exportPackage(ctx context.Context, job *models.Job, deck models.Deck, deckCards []models.Card, output io.Writer) error {
+	states, err := cs.reviews.ListStates(ctx, job.UserID, deck.ID)
+	if err != nil {
+		return err
+	}
+	notes := make([]anki.Note, 0, len(deckCards))
+	for _, card := range deckCards {
+		note := anki.Note{
+			GUID:         card.ID,
+			Front:        card.Front,
+			Back:         card.Back,
+			Hint:         card.Hint,
+			Alternatives: card.Alternatives,
+			Tags:         card.Tags,
+		}
+		if state, ok := states[card.ID]; ok {
+			note.Schedule = &anki.Schedule{
+				Repetitions:  state.Repetitions,
+				Lapses:       state.Lapses,
+				IntervalDays: state.IntervalDays,
+				Ease:         state.Ease,
+				Stability:    state.Stability,
+				Difficulty:   state.Difficulty,
+				Due:          state.Due,
+				LastReview:   state.LastReview,
+			}
+		}
+		notes = append(notes, note)
+	}
+	return anki.Write(output, deck.Title, notes, cs.now())
+}
+
+// jobDeck loads the deck of a running job, the job fails if the deck was deleted or unshared in the meantime
+func (cs *CardsServer) jobDeck(ctx context.Context, job *models.Job) (models.Deck, error) {
+	// importing changes the cards like editing them does, any member can export
+	role := models.RoleViewer
+	if job.Kind == models.JobImportCards {
+		role = models.RoleEditor
+	}
+	deck, _, err := cs.getDeckAs(ctx, job.UserID, job.DeckID, role)
+	if err != nil {
+		if code := status.Code(err); code == codes.NotFound || code == codes.PermissionDenied {
+			return models.Deck{}, errJobDeckGone
+		}
+		return models.Deck{}, err
+	}
+	return deck, nil
+}
+
+// getOwnedJob loads the job and makes sure it belongs to the user
+func (cs *CardsServer) getOwnedJob(ctx context.Context, payload *cards.JobPayload) (models.Job, error) {
+	dto := jobDto{UserID: payload.GetUserId(), JobID: payload.GetJobId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return models.Job{}, validationFailure(err)
+	}
+	job, err := cs.jobs.Get(ctx, dto.JobID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return models.Job{}, errJobNotFound
+		}
+		return models.Job{}, operationFailure("get job", err)
+	}
+	if job.UserID != dto.UserID {
+		return models.Job{}, errNotJobOwner
+	}
+	return job, nil
+}
+
+// subdeckTag names a tag after the last part of an Anki deck name, e.g. "Spanish::Verbs" becomes "verbs"
+func subdeckTag(deckName string) string {
+	parts := strings.Split(deckName, "::")
+	tag := []rune(strings.TrimSpace(parts[len(parts)-1]))
+	if len(tag) > 50 {
+		tag = tag[:50]
+	}
+	return string(tag)
+}
+
+// checkExport reports options the export can't be written with before the job is queued
+func checkExport(format string, options models.JobOptions) error {
+	switch format {
+	case anki.FormatApkg:
+		return nil
+	case printout.FormatPDF:
+		return printOptions(&models.Job{Options: options}).Validate()
+	}
+	_, err := spreadsheet.NewWriter(io.Discard, spreadsheetOptions(format, options), options.Columns)
+	return err
+}
+
+// printOptions reads the layout of a PDF export. A test printed without a seed is shuffled
+// by the job id, so it changes with every export but not when a job is retried.
+func printOptions(job *models.Job) printout.Options {
+	var options models.PrintOptions
+	if job.Options.Print != nil {
+		options = *job.Options.Print
+	}
+	seed := options.Seed
+	if seed == 0 {
+		hash := fnv.New64a()
+		hash.Write([]byte(job.ID))
+		seed = int64(hash.Sum64())
+	}
+	return printout.Options{
+		Layout:        options.Layout,
+		Paper:         options.Paper,
+		CardsPerPage:  options.CardsPerPage,
+		FontSize:      options.FontSize,
+		Questions:     options.Questions,
+		QuestionCount: options.QuestionCount,
+		Seed:          seed,
+	}
+}
+
+func fromProtoPrintOptions(options *cards.PrintOptions) *models.PrintOptions {
+	return &models.PrintOptions{
+		Layout:        options.GetLayout(),
+		Paper:         options.GetPaper(),
+		CardsPerPage:  int(options.GetCardsPerPage()),
+		FontSize:      options.GetFontSize(),
+		Questions:     options.GetQuestions(),
+		QuestionCount: int(options.GetQuestionCount()),
+		Seed:          options.GetSeed(),
+	}
+}
+
+// checkImport reads as little of the file as needed to tell whether it can be imported
+func checkImport(format string, content []byte, options models.JobOptions) error {
+	switch format {
+	case anki.FormatApkg:
+		return anki.Check(content)
+	case textimport.FormatQuizlet, textimport.FormatMarkdown:
+		return textOptions(format, options).Validate()
+	}
+	_, err := spreadsheet.NewReader(bytes.NewReader(content), spreadsheetOptions(format, options))
+	return err
+}
+
+func spreadsheetOptions(format string, options models.JobOptions) spreadsheet.Options {
+	delimiter, _ := utf8.DecodeRuneInString(options.Delimiter)
+	if options.Delimiter == "" {
+		delimiter = 0
+	}
+	return spreadsheet.Options{
+		Format:    format,
+		Delimiter: delimiter,
+		NoHeader:  options.NoHeader,
+		Mapping:   options.Mapping,
+	}
+}
+
+func toProtoJob(job models.Job) *cards.Job {
+	report := &cards.JobReport{
+		Rows:     int32(job.Report.Rows),
+		Valid:    int32(job.Report.Valid),
+		Imported: int32(job.Report.Imported),
+		Failed:   int32(job.Report.Failed),
+		Errors:   make([]*cards.JobRowError, 0, len(job.Report.Errors)),
+		Warnings: job.Report.Warnings,
+		DeckIds:  job.Report.Decks,
+	}
+	for _, rowErr := range job.Report.Errors {
+		report.Errors = append(report.Errors, &cards.JobRowError{Line: int32(rowErr.Line), Message: rowErr.Message})
+	}
+	res := &cards.Job{
+		Id:        job.ID,
+		DeckId:    job.DeckID,
+		Kind:      job.Kind,
+		Status:    job.Status,
+		Format:    job.Format,
+		DryRun:    job.Options.DryRun,
+		Report:    report,
+		Error:     job.Error,
+		CreatedAt: job.CreatedAt.Unix(),
+		UpdatedAt: job.UpdatedAt.Unix(),
+	}
+	if job.Status == models.JobSucceeded {
+		res.OutputName = job.OutputName
+	}
+	if !job.CompletedAt.IsZero() {
+		res.CompletedAt = job.CompletedAt.Unix()
+	}
+	return res
+}
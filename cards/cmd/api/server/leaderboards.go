@@ -0,0 +1,237 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/leaderboard"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/quiz"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/repositories"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"github.com/Salladin95/card-quizzler-microservices/consumer"
+	"slices"
+	"time"
+)
+
+const (
+	scopeGlobal    = "global"
+	scopeDeck      = "deck"
+	scopeFollowing = "following"
+	// maxFollowing bounds the users read to rank the following scope
+	maxFollowing = 1000
+)
+
+// LeaderboardEventKeys are the events feeding the leaderboards
+var LeaderboardEventKeys = []string{events.CardReviewedKey, events.QuizChallengeCompletedKey}
+
+// FollowUser adds a user to the following leaderboards of the user
+func (cs *CardsServer) FollowUser(ctx context.Context, req *cards.FollowRequest) (*cards.FollowingResponse, error) {
+	dto := followDto{UserID: req.GetPayload().GetUserId(), FolloweeID: req.GetPayload().GetFolloweeId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	following, err := cs.follows.Following(ctx, dto.UserID)
+	if err != nil {
+		return nil, operationFailure("list followed users", err)
+	}
+	if len(following) >= maxFollowing {
+		return nil, errFollowingLimit
+	}
+	err = cs.follows.Follow(ctx, models.Follow{FollowerID: dto.UserID, FolloweeID: dto.FolloweeID, CreatedAt: cs.now()})
+	if err != nil {
+		if errors.Is(err, repositories.ErrAlreadyExists) {
+			return nil, errAlreadyFollowing
+		}
+		return nil, operationFailure("follow user", err)
+	}
+	return cs.listFollowing(ctx, dto.UserID)
+}
+
+// UnfollowUser removes a user from the following leaderboards of the user
+func (cs *CardsServer) UnfollowUser(ctx context.Context, req *cards.FollowRequest) (*cards.FollowingResponse, error) {
+	dto := followDto{UserID: req.GetPayload().GetUserId(), FolloweeID: req.GetPayload().GetFolloweeId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	if err := cs.follows.Unfollow(ctx, dto.UserID, dto.FolloweeID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, errNotFollowing
+		}
+		return nil, operationFailure("unfollow user", err)
+	}
+	return cs.listFollowing(ctx, dto.UserID)
+}
+
+// ListFollowing returns the ids of the users the user follows
+func (cs *CardsServer) ListFollowing(ctx context.Context, req *cards.FollowingRequest) (*cards.FollowingResponse, error) {
+	dto := userDto{UserID: req.GetPayload().GetUserId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+	return cs.listFollowing(ctx, dto.UserID)
+}
+
+// GetLeaderboard returns a page of a weekly leaderboard with the user's own entry
+func (cs *CardsServer) GetLeaderboard(ctx context.Context, req *cards.LeaderboardRequest) (*cards.LeaderboardResponse, error) {
+	payload := req.GetPayload()
+	dto := leaderboardDto{
+		UserID:   payload.GetUserId(),
+		Metric:   payload.GetMetric(),
+		Scope:    payload.GetScope(),
+		DeckID:   payload.GetDeckId(),
+		WeeksAgo: int(payload.GetWeeksAgo()),
+		Offset:   int(payload.GetOffset()),
+		Limit:    int(payload.GetLimit()),
+		AroundMe: payload.GetAroundMe(),
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+	if dto.Limit == 0 {
+		dto.Limit = defaultPageSize
+	}
+	deckID := ""
+	if dto.Scope == scopeDeck {
+		if _, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleViewer); err != nil {
+			return nil, err
+		}
+		deckID = dto.DeckID
+	}
+
+	currentWeek := leaderboard.WeekStart(cs.now())
+	week := currentWeek.AddDate(0, 0, -7*dto.WeeksAgo)
+	key := leaderboard.Key(dto.Metric, deckID, week)
+	board := cs.boards
+	if dto.Scope == scopeFollowing {
+		var err error
+		if board, err = cs.followingBoard(ctx, dto.UserID, key); err != nil {
+			return nil, err
+		}
+	}
+
+	res := &cards.LeaderboardResponse{
+		Metric:    dto.Metric,
+		Scope:     dto.Scope,
+		DeckId:    deckID,
+		WeekStart: week.Format(time.DateOnly),
+		ResetsAt:  currentWeek.AddDate(0, 0, 7).Unix(),
+	}
+	total, err := board.Len(ctx, key)
+	if err != nil {
+		return nil, operationFailure("read leaderboard", err)
+	}
+	res.Total = int32(total)
+	rank, score, err := board.Rank(ctx, key, dto.UserID)
+	switch {
+	case err == nil:
+		res.Me = &cards.LeaderboardEntry{Rank: int32(rank + 1), UserId: dto.UserID, Score: int32(leaderboard.Whole(score))}
+		if dto.AroundMe {
+			dto.Offset = max(rank-dto.Limit/2, 0)
+		}
+	case errors.Is(err, leaderboard.ErrNotRanked):
+		if dto.AroundMe {
+			dto.Offset = 0
+		}
+	default:
+		return nil, operationFailure("read leaderboard", err)
+	}
+
+	members, err := board.Range(ctx, key, dto.Offset, dto.Limit)
+	if err != nil {
+		return nil, operationFailure("read leaderboard", err)
+	}
+	res.Offset = int32(dto.Offset)
+	res.Entries = make([]*cards.LeaderboardEntry, 0, len(members))
+	for i, member := range members {
+		res.Entries = append(res.Entries, &cards.LeaderboardEntry{
+			Rank:   int32(dto.Offset + i + 1),
+			UserId: member.ID,
+			Score:  int32(leaderboard.Whole(member.Score)),
+		})
+	}
+	return res, nil
+}
+
+// HandleLeaderboardEvent adds a review or a challenge score to the global and the deck leaderboards of its week,
+// returned errors make the consumer retry it
+func (cs *CardsServer) HandleLeaderboardEvent(ctx context.Context, key string, payload []byte) error {
+	switch key {
+	case events.CardReviewedKey:
+		var event events.CardReviewed
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return consumer.Permanent(err)
+		}
+		if event.ReviewID == "" {
+			return consumer.Permanent(errors.New("review event without a review id"))
+		}
+		reviewedAt := time.Unix(event.ReviewedAt, 0)
+		return cs.updateBoards(leaderboard.MetricVolume, event.DeckID, reviewedAt, func(key string, expireAt time.Time) error {
+			return cs.boards.AddWhole(ctx, key, event.UserID, event.ReviewID, 1, leaderboard.TieBreaker(reviewedAt), expireAt)
+		})
+	case events.QuizChallengeCompletedKey:
+		var event events.QuizChallengeCompleted
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return consumer.Permanent(err)
+		}
+		// the client could set the score of answers it grades itself, events without modes predate them
+		if len(event.Modes) == 0 || slices.ContainsFunc(event.Modes, quiz.SelfGraded) {
+			return nil
+		}
+		completedAt := time.Unix(event.CompletedAt, 0)
+		score := leaderboard.Score(event.Score, completedAt)
+		return cs.updateBoards(leaderboard.MetricChallenge, event.DeckID, completedAt, func(key string, expireAt time.Time) error {
+			return cs.boards.SetMax(ctx, key, event.UserID, score, expireAt)
+		})
+	default:
+		return consumer.Permanent(fmt.Errorf("unexpected event %s", key))
+	}
+}
+
+// updateBoards applies an update to the global and the deck board of the metric in the week of the time,
+// events arriving after the boards of their week were dropped are skipped
+func (cs *CardsServer) updateBoards(metric, deckID string, at time.Time,
+	update func(key string, expireAt time.Time) error) error {
+	week := leaderboard.WeekStart(at)
+	expireAt := leaderboard.ExpireAt(week)
+	if !cs.now().Before(expireAt) {
+		return nil
+	}
+	for _, boardDeckID := range []string{"", deckID} {
+		if err := update(leaderboard.Key(metric, boardDeckID, week), expireAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// followingBoard ranks the user and the users they follow by their scores on the global board of the key
+func (cs *CardsServer) followingBoard(ctx context.Context, userID, key string) (leaderboard.SortedSet, error) {
+	following, err := cs.follows.Following(ctx, userID)
+	if err != nil {
+		return nil, operationFailure("list followed users", err)
+	}
+	scores, err := cs.boards.Scores(ctx, key, append(following, userID))
+	if err != nil {
+		return nil, operationFailure("read leaderboard", err)
+	}
+	board := leaderboard.NewMemorySortedSet(cs.now)
+	for _, member := range scores {
+		if err := board.SetMax(ctx, key, member.ID, member.Score, time.Time{}); err != nil {
+			return nil, operationFailure("read leaderboard", err)
+		}
+	}
+	return board, nil
+}
+
+func (cs *CardsServer) listFollowing(ctx context.Context, userID string) (*cards.FollowingResponse, error) {
+	following, err := cs.follows.Following(ctx, userID)
+	if err != nil {
+		return nil, operationFailure("list followed users", err)
+	}
+	return &cards.FollowingResponse{UserIds: following}, nil
+}
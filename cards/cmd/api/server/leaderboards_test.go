@@ -0,0 +1,184 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/quiz"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+// handleLeaderboardEvent delivers an event to the leaderboard consumer twice, like a redelivering queue
+func (ts *testServer) handleLeaderboardEvent(t *testing.T, key string, event any) {
+	t.Helper()
+	payload, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := ts.HandleLeaderboardEvent(context.Background(), key, payload); err != nil {
+			t.Fatalf("handle %s: %v", key, err)
+		}
+	}
+}
+
+// reviewed delivers the reviews of a user in the deck, a minute apart from the given time
+func (ts *testServer) reviewed(t *testing.T, userID, deckID string, count int, at time.Time) {
+	t.Helper()
+	for i := 0; i < count; i++ {
+		ts.handleLeaderboardEvent(t, events.CardReviewedKey, events.CardReviewed{
+			ReviewID:   fmt.Sprintf("%s-%s-%d", userID, at.Format(time.RFC3339), i),
+			UserID:     userID,
+			DeckID:     deckID,
+			ReviewedAt: at.Add(time.Duration(i) * time.Minute).Unix(),
+		})
+	}
+}
+
+func (ts *testServer) leaderboard(t *testing.T, payload *cards.LeaderboardPayload) *cards.LeaderboardResponse {
+	t.Helper()
+	payload.UserId = testUserID
+	res, err := ts.GetLeaderboard(context.Background(), &cards.LeaderboardRequest{Payload: payload})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+// ranking lists the entries as "user:score" in rank order
+func ranking(res *cards.LeaderboardResponse) []string {
+	entries := make([]string, 0, len(res.Entries))
+	for _, entry := range res.Entries {
+		entries = append(entries, fmt.Sprintf("%d.%s:%d", entry.Rank, entry.UserId, entry.Score))
+	}
+	return entries
+}
+
+func assertRanking(t *testing.T, step string, res *cards.LeaderboardResponse, want ...string) {
+	t.Helper()
+	got := ranking(res)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("%s: got %v, want %v", step, got, want)
+	}
+}
+
+func TestVolumeLeaderboard(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	ts.seedDeck(t, "spanish", 0)
+	now := ts.clock.Now()
+
+	// user-3 reaches 2 reviews after user-1 and ranks below on the tie
+	ts.reviewed(t, "user-2", "spanish", 3, now.Add(-2*time.Hour))
+	ts.reviewed(t, testUserID, "spanish", 2, now.Add(-time.Hour))
+	ts.reviewed(t, "user-3", "french", 2, now.Add(-30*time.Minute))
+	// reviews older than the kept weeks are dropped
+	ts.reviewed(t, "user-4", "spanish", 5, now.AddDate(0, 0, -42))
+
+	res := ts.leaderboard(t, &cards.LeaderboardPayload{Metric: "volume", Scope: "global"})
+	assertRanking(t, "global", res, "1.user-2:3", "2.user-1:2", "3.user-3:2")
+	if res.WeekStart != "2024-02-26" || res.Total != 3 || res.Me.GetRank() != 2 {
+		t.Errorf("global: got week %s, total %d, rank %d, want 2024-02-26, 3 and 2", res.WeekStart, res.Total, res.Me.GetRank())
+	}
+	if resetsAt := time.Unix(res.ResetsAt, 0).UTC(); !resetsAt.Equal(time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("global: got reset at %v, want Monday March 4th", resetsAt)
+	}
+	assertRanking(t, "deck", ts.leaderboard(t, &cards.LeaderboardPayload{Metric: "volume", Scope: "deck", DeckId: "spanish"}),
+		"1.user-2:3", "2.user-1:2")
+	assertRanking(t, "page", ts.leaderboard(t, &cards.LeaderboardPayload{Metric: "volume", Scope: "global", Offset: 1, Limit: 1}),
+		"2.user-1:2")
+	assertRanking(t, "around me", ts.leaderboard(t, &cards.LeaderboardPayload{Metric: "volume", Scope: "global", Limit: 1, AroundMe: true}),
+		"2.user-1:2")
+
+	// following ranks the user among the followed users only
+	if _, err := ts.FollowUser(ctx, &cards.FollowRequest{Payload: &cards.FollowPayload{UserId: testUserID, FolloweeId: "user-3"}}); err != nil {
+		t.Fatal(err)
+	}
+	assertRanking(t, "following", ts.leaderboard(t, &cards.LeaderboardPayload{Metric: "volume", Scope: "following"}),
+		"1.user-1:2", "2.user-3:2")
+
+	// the board resets on Monday, the last week stays readable
+	ts.clock.Advance(3 * 24 * time.Hour)
+	res = ts.leaderboard(t, &cards.LeaderboardPayload{Metric: "volume", Scope: "global"})
+	if res.WeekStart != "2024-03-04" || res.Total != 0 || res.Me != nil {
+		t.Errorf("new week: got week %s, total %d, me %v, want an empty board of 2024-03-04", res.WeekStart, res.Total, res.Me)
+	}
+	assertRanking(t, "last week", ts.leaderboard(t, &cards.LeaderboardPayload{Metric: "volume", Scope: "global", WeeksAgo: 1}),
+		"1.user-2:3", "2.user-1:2", "3.user-3:2")
+}
+
+func TestChallengeLeaderboard(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ts.seedDeck(t, "spanish", 0)
+	now := ts.clock.Now()
+	challenge := func(sessionID, userID string, score int, at time.Time, modes ...string) {
+		t.Helper()
+		if len(modes) == 0 {
+			modes = []string{quiz.ModeTyped}
+		}
+		ts.handleLeaderboardEvent(t, events.QuizChallengeCompletedKey, events.QuizChallengeCompleted{
+			SessionID: sessionID, UserID: userID, DeckID: "spanish", Modes: modes, Score: score, CompletedAt: at.Unix(),
+		})
+	}
+
+	// the best score of the week counts, equal scores rank the earlier one first
+	challenge("s1", testUserID, 1200, now.Add(-3*time.Hour))
+	challenge("s2", testUserID, 900, now.Add(-2*time.Hour))
+	challenge("s3", "user-2", 1200, now.Add(-time.Hour))
+	challenge("s4", "user-3", 1500, now)
+	// self-graded answers could score anything
+	challenge("s5", "user-4", 9000, now, quiz.ModeMultipleChoice, quiz.ModeFlashcard)
+	assertRanking(t, "challenge", ts.leaderboard(t, &cards.LeaderboardPayload{Metric: "challenge", Scope: "deck", DeckId: "spanish"}),
+		"1.user-3:1500", "2.user-1:1200", "3.user-2:1200")
+}
+
+func TestLeaderboardAccess(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	now := ts.clock.Now()
+	if err := ts.decks.Create(ctx, models.Deck{ID: "private", OwnerID: "user-2", Title: "Private", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		payload *cards.LeaderboardPayload
+		code    codes.Code
+	}{
+		{"deck of another user", &cards.LeaderboardPayload{Metric: "volume", Scope: "deck", DeckId: "private"}, codes.PermissionDenied},
+		{"deck scope without a deck", &cards.LeaderboardPayload{Metric: "volume", Scope: "deck"}, codes.InvalidArgument},
+		{"unknown metric", &cards.LeaderboardPayload{Metric: "accuracy", Scope: "global"}, codes.InvalidArgument},
+		{"dropped week", &cards.LeaderboardPayload{Metric: "volume", Scope: "global", WeeksAgo: 5}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		tt.payload.UserId = testUserID
+		_, err := ts.GetLeaderboard(ctx, &cards.LeaderboardRequest{Payload: tt.payload})
+		if status.Code(err) != tt.code {
+			t.Errorf("%s: got %v, want %s", tt.name, err, tt.code)
+		}
+	}
+
+	follow := func(followeeID string) error {
+		_, err := ts.FollowUser(ctx, &cards.FollowRequest{Payload: &cards.FollowPayload{UserId: testUserID, FolloweeId: followeeID}})
+		return err
+	}
+	if err := follow(testUserID); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("following oneself: got %v, want InvalidArgument", err)
+	}
+	if err := follow("user-2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := follow("user-2"); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("following twice: got %v, want AlreadyExists", err)
+	}
+	res, err := ts.UnfollowUser(ctx, &cards.FollowRequest{Payload: &cards.FollowPayload{UserId: testUserID, FolloweeId: "user-2"}})
+	if err != nil || len(res.UserIds) != 0 {
+		t.Errorf("unfollow: got %v, %v, want nobody followed", res, err)
+	}
+}
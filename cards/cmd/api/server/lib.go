@@ -0,0 +1,148 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/repositories"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"log"
+	"strings"
+	"unicode"
+)
+
+// getOwnedDeck loads the deck and makes sure it belongs to the user
+func (cs *CardsServer) getOwnedDeck(ctx context.Context, userID, deckID string) (models.Deck, error) {
+	deck, err := cs.decks.Get(ctx, deckID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return models.Deck{}, errDeckNotFound
+		}
+		return models.Deck{}, operationFailure("get deck", err)
+	}
+	if deck.OwnerID != userID {
+		return models.Deck{}, errNotDeckOwner
+	}
+	return deck, nil
+}
+
+// roleRanks orders the roles, a role can do whatever the lower ones can
+var roleRanks = map[string]int{models.RoleViewer: 1, models.RoleEditor: 2, models.RoleOwner: 3}
+
+// getDeckAs loads the deck and makes sure the user has at least the role on it, it returns the user's role.
+// The creator of the deck is an owner, other users get their role by accepting an invitation.
+func (cs *CardsServer) getDeckAs(ctx context.Context, userID, deckID, role string) (models.Deck, string, error) {
+	deck, err := cs.decks.Get(ctx, deckID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return models.Deck{}, "", errDeckNotFound
+		}
+		return models.Deck{}, "", operationFailure("get deck", err)
+	}
+	userRole, err := cs.deckRole(ctx, deck, userID)
+	if err != nil {
+		return models.Deck{}, "", err
+	}
+	if roleRanks[userRole] < roleRanks[role] {
+		return models.Deck{}, "", roleFailure(userRole, role)
+	}
+	return deck, userRole, nil
+}
+
+// deckRole returns the role of the user on the deck, errNotDeckOwner when it is not shared with the user
+func (cs *CardsServer) deckRole(ctx context.Context, deck models.Deck, userID string) (string, error) {
+	if deck.OwnerID == userID {
+		return models.RoleOwner, nil
+	}
+	member, err := cs.sharing.GetMember(ctx, deck.ID, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return "", errNotDeckOwner
+		}
+		return "", operationFailure("get deck member", err)
+	}
+	return member.Role, nil
+}
+
+// getDeckCard loads the card and makes sure it is part of the deck
+func (cs *CardsServer) getDeckCard(ctx context.Context, deckID, cardID string) (models.Card, error) {
+	card, err := cs.cards.Get(ctx, cardID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return models.Card{}, errCardNotFound
+		}
+		return models.Card{}, operationFailure("get card", err)
+	}
+	if card.DeckID != deckID {
+		return models.Card{}, errCardNotFound
+	}
+	return card, nil
+}
+
+// publish emits an event; the change is already stored, so a failure is only logged
+func (cs *CardsServer) publish(ctx context.Context, key string, payload any) {
+	if err := cs.publisher.Publish(ctx, key, payload); err != nil {
+		log.Printf("failed to publish %s event: %s\n", key, err.Error())
+	}
+}
+
+func toProtoDeck(deck models.Deck) *cards.Deck {
+	return &cards.Deck{
+		Id:          deck.ID,
+		OwnerId:     deck.OwnerID,
+		ParentId:    deck.ParentID,
+		FolderId:    deck.FolderID,
+		Title:       deck.Title,
+		Description: deck.Description,
+		Tags:        deck.Tags,
+		CreatedAt:   deck.CreatedAt.Unix(),
+		UpdatedAt:   deck.UpdatedAt.Unix(),
+	}
+}
+
+// normalizeTags trims and lowercases tags and drops empty and repeated ones
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+func toProtoCard(card models.Card) *cards.Card {
+	return &cards.Card{
+		Id:                card.ID,
+		DeckId:            card.DeckID,
+		Front:             card.Front,
+		Back:              card.Back,
+		Hint:              card.Hint,
+		CreatedAt:         card.CreatedAt.Unix(),
+		UpdatedAt:         card.UpdatedAt.Unix(),
+		Alternatives:      card.Alternatives,
+		Tags:              card.Tags,
+		AnswerType:        card.AnswerType,
+		AbsoluteTolerance: card.AbsoluteTolerance,
+		RelativeTolerance: card.RelativeTolerance,
+	}
+}
+
+// exportFileName turns a deck title into a file name safe for downloads
+func exportFileName(title, format string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '-'
+	}, title)
+	name = strings.Join(strings.FieldsFunc(name, func(r rune) bool { return r == '-' }), "-")
+	if name == "" {
+		name = "deck"
+	}
+	return name + "." + format
+}
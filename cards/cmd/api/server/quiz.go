@@ -0,0 +1,426 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/quiz"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/repositories"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"github.com/google/uuid"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+func (cs *CardsServer) StartQuiz(ctx context.Context, req *cards.StartQuizRequest) (*cards.QuizSessionResponse, error) {
+	payload := req.GetPayload()
+	dto := startQuizDto{
+		UserID:           payload.GetUserId(),
+		DeckID:           payload.GetDeckId(),
+		FilteredDeckID:   payload.GetFilteredDeckId(),
+		CardCount:        int(payload.GetCardCount()),
+		Order:            payload.GetOrder(),
+		Modes:            payload.GetModes(),
+		ChoiceCount:      int(payload.GetChoiceCount()),
+		Seed:             payload.GetSeed(),
+		TimeLimitSeconds: int(payload.GetTimeLimitSeconds()),
+	}
+	if dto.Order == "" {
+		dto.Order = quiz.OrderSequential
+	}
+	if len(dto.Modes) == 0 && dto.TimeLimitSeconds > 0 {
+		// challenges are graded by the server, flashcards are judged by the user
+		dto.Modes = []string{quiz.ModeTyped}
+	} else if len(dto.Modes) == 0 {
+		dto.Modes = []string{quiz.ModeFlashcard}
+	}
+	if dto.Seed == 0 {
+		dto.Seed = rand.Int63()
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	deckID, deckCards, err := cs.quizCards(ctx, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	session := models.QuizSession{
+		ID:     uuid.NewString(),
+		UserID: dto.UserID,
+		DeckID: deckID,
+		Seed:   dto.Seed,
+	}
+	opts := quiz.Options{
+		CardCount:   dto.CardCount,
+		Order:       dto.Order,
+		Modes:       dto.Modes,
+		ChoiceCount: dto.ChoiceCount,
+		TimeLimit:   time.Duration(dto.TimeLimitSeconds) * time.Second,
+	}
+	if err := cs.quiz.Start(&session, deckCards, opts, cs.now()); err != nil {
+		return nil, quizFailure("start quiz", err)
+	}
+	if err := cs.sessions.Create(ctx, session); err != nil {
+		return nil, operationFailure("create quiz session", err)
+	}
+
+	return cs.quizSessionResponse(&session), nil
+}
+
+// quizCards returns the cards of the deck or the filtered deck the quiz is started on, with the id of either
+func (cs *CardsServer) quizCards(ctx context.Context, dto startQuizDto) (string, []models.Card, error) {
+	if dto.FilteredDeckID != "" {
+		deck, err := cs.getOwnedFilteredDeck(ctx, dto.UserID, dto.FilteredDeckID)
+		if err != nil {
+			return "", nil, err
+		}
+		matches, err := cs.filteredCards(ctx, deck, repositories.DueAny)
+		if err != nil {
+			return "", nil, err
+		}
+		filteredCards := make([]models.Card, 0, len(matches))
+		for _, match := range matches {
+			filteredCards = append(filteredCards, match.Card)
+		}
+		return deck.ID, filteredCards, nil
+	}
+
+	deck, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleViewer)
+	if err != nil {
+		return "", nil, err
+	}
+	deckCards, err := cs.cards.ListByDeck(ctx, deck.ID)
+	if err != nil {
+		return "", nil, operationFailure("list cards", err)
+	}
+	return deck.ID, deckCards, nil
+}
+
+func (cs *CardsServer) GetQuizSession(ctx context.Context, req *cards.QuizSessionRequest) (*cards.QuizSessionResponse, error) {
+	session, err := cs.loadQuizSession(ctx, req.GetPayload())
+	if err != nil {
+		return nil, err
+	}
+	return cs.quizSessionResponse(&session), nil
+}
+
+func (cs *CardsServer) GetNextQuestion(ctx context.Context, req *cards.QuizSessionRequest) (*cards.QuizSessionResponse, error) {
+	session, err := cs.loadQuizSession(ctx, req.GetPayload())
+	if err != nil {
+		return nil, err
+	}
+	switch session.Status {
+	case models.QuizStatusPaused:
+		return nil, quizFailure("get next question", quiz.ErrSessionPaused)
+	case models.QuizStatusCompleted:
+		return nil, quizFailure("get next question", quiz.ErrSessionCompleted)
+	case models.QuizStatusExpired:
+		return nil, quizFailure("get next question", quiz.ErrSessionExpired)
+	}
+	return cs.quizSessionResponse(&session), nil
+}
+
+func (cs *CardsServer) SubmitAnswer(ctx context.Context, req *cards.SubmitAnswerRequest) (*cards.AnswerResponse, error) {
+	payload := req.GetPayload()
+	dto := submitAnswerDto{
+		UserID:        payload.GetUserId(),
+		SessionID:     payload.GetSessionId(),
+		QuestionIndex: int(payload.GetQuestionIndex()),
+		Answer:        payload.GetAnswer(),
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	session, err := cs.loadQuizSession(ctx, &cards.QuizSessionPayload{UserId: dto.UserID, SessionId: dto.SessionID})
+	if err != nil {
+		return nil, err
+	}
+	result, err := cs.quiz.Answer(&session, dto.QuestionIndex, dto.Answer, cs.now())
+	if err != nil {
+		return nil, quizFailure("submit answer", err)
+	}
+	if err := cs.saveQuizSession(ctx, &session); err != nil {
+		return nil, err
+	}
+
+	answered := session.Questions[dto.QuestionIndex]
+	res := cs.answerResponse(&session, answered)
+	res.Correct = result.Correct
+	res.Verdict = result.Verdict
+	res.Feedback = result.Feedback
+	res.Diff = toProtoDiff(result.Diff)
+	res.Points = int32(result.Points)
+	res.Combo = int32(result.Combo)
+	return res, nil
+}
+
+func (cs *CardsServer) SkipQuestion(ctx context.Context, req *cards.SkipQuestionRequest) (*cards.AnswerResponse, error) {
+	payload := req.GetPayload()
+	dto := skipQuestionDto{
+		UserID:        payload.GetUserId(),
+		SessionID:     payload.GetSessionId(),
+		QuestionIndex: int(payload.GetQuestionIndex()),
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	session, err := cs.loadQuizSession(ctx, &cards.QuizSessionPayload{UserId: dto.UserID, SessionId: dto.SessionID})
+	if err != nil {
+		return nil, err
+	}
+	if err := cs.quiz.Skip(&session, dto.QuestionIndex, cs.now()); err != nil {
+		return nil, quizFailure("skip question", err)
+	}
+	if err := cs.saveQuizSession(ctx, &session); err != nil {
+		return nil, err
+	}
+
+	return cs.answerResponse(&session, session.Questions[dto.QuestionIndex]), nil
+}
+
+func (cs *CardsServer) PauseQuiz(ctx context.Context, req *cards.QuizSessionRequest) (*cards.QuizSessionResponse, error) {
+	session, err := cs.loadQuizSession(ctx, req.GetPayload())
+	if err != nil {
+		return nil, err
+	}
+	if err := cs.quiz.Pause(&session, cs.now()); err != nil {
+		return nil, quizFailure("pause quiz", err)
+	}
+	if err := cs.saveQuizSession(ctx, &session); err != nil {
+		return nil, err
+	}
+	return cs.quizSessionResponse(&session), nil
+}
+
+func (cs *CardsServer) ResumeQuiz(ctx context.Context, req *cards.QuizSessionRequest) (*cards.QuizSessionResponse, error) {
+	session, err := cs.loadQuizSession(ctx, req.GetPayload())
+	if err != nil {
+		return nil, err
+	}
+	if err := cs.quiz.Resume(&session, cs.now()); err != nil {
+		return nil, quizFailure("resume quiz", err)
+	}
+	if err := cs.saveQuizSession(ctx, &session); err != nil {
+		return nil, err
+	}
+	return cs.quizSessionResponse(&session), nil
+}
+
+func (cs *CardsServer) FinishQuiz(ctx context.Context, req *cards.QuizSessionRequest) (*cards.QuizSummaryResponse, error) {
+	session, err := cs.loadQuizSession(ctx, req.GetPayload())
+	if err != nil {
+		return nil, err
+	}
+	completed, err := cs.quiz.Finish(&session, cs.now())
+	if err != nil {
+		return nil, quizFailure("finish quiz", err)
+	}
+	if completed {
+		if err := cs.saveQuizSession(ctx, &session); err != nil {
+			return nil, err
+		}
+	}
+	return &cards.QuizSummaryResponse{Summary: toProtoQuizSummary(&session)}, nil
+}
+
+// loadQuizSession loads the user's session and expires it if it has been idle for too long
+func (cs *CardsServer) loadQuizSession(ctx context.Context, payload *cards.QuizSessionPayload) (models.QuizSession, error) {
+	dto := quizSessionDto{UserID: payload.GetUserId(), SessionID: payload.GetSessionId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return models.QuizSession{}, validationFailure(err)
+	}
+
+	session, err := cs.sessions.Get(ctx, dto.SessionID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return models.QuizSession{}, errQuizSessionNotFound
+		}
+		return models.QuizSession{}, operationFailure("get quiz session", err)
+	}
+	if session.UserID != dto.UserID {
+		return models.QuizSession{}, errNotQuizSessionOwner
+	}
+
+	if cs.quiz.Expire(&session, cs.now()) {
+		if err := cs.saveQuizSession(ctx, &session); err != nil {
+			return models.QuizSession{}, err
+		}
+	}
+	return session, nil
+}
+
+// saveQuizSession persists the session and announces its completion
+func (cs *CardsServer) saveQuizSession(ctx context.Context, session *models.QuizSession) error {
+	wasCompleted := session.Status == models.QuizStatusCompleted
+	if err := cs.sessions.Update(ctx, session); err != nil {
+		if errors.Is(err, repositories.ErrConflict) {
+			return errQuizSessionConflict
+		}
+		if errors.Is(err, repositories.ErrNotFound) {
+			return errQuizSessionNotFound
+		}
+		return operationFailure("save quiz session", err)
+	}
+	if wasCompleted {
+		cs.publish(ctx, events.QuizSessionCompletedKey, quizSessionCompleted(session))
+		if quiz.IsChallenge(session) {
+			cs.recordChallenge(ctx, session)
+		}
+	}
+	return nil
+}
+
+func (cs *CardsServer) quizSessionResponse(session *models.QuizSession) *cards.QuizSessionResponse {
+	res := &cards.QuizSessionResponse{Session: toProtoQuizSession(session, cs.now())}
+	if question, ok := cs.quiz.CurrentQuestion(session); ok && session.Status == models.QuizStatusActive {
+		res.Question = toProtoQuizQuestion(session.Current, question, false)
+	}
+	return res
+}
+
+func (cs *CardsServer) answerResponse(session *models.QuizSession, answered models.QuizQuestion) *cards.AnswerResponse {
+	res := &cards.AnswerResponse{
+		Correct:  answered.Correct,
+		Expected: answered.Expected,
+		Session:  toProtoQuizSession(session, cs.now()),
+	}
+	if question, ok := cs.quiz.CurrentQuestion(session); ok && session.Status == models.QuizStatusActive {
+		res.NextQuestion = toProtoQuizQuestion(session.Current, question, false)
+	}
+	return res
+}
+
+func quizSessionCompleted(session *models.QuizSession) events.QuizSessionCompleted {
+	summary := quiz.Summarize(session)
+	results := make([]events.QuizCardResult, 0, len(session.Questions))
+	for _, question := range session.Questions {
+		if question.Status == models.QuestionStatusPending {
+			continue
+		}
+		if len(question.GroupCardIDs) == 0 {
+			results = append(results, events.QuizCardResult{
+				CardID:  question.CardID,
+				Mode:    question.Mode,
+				Correct: question.Correct,
+				Skipped: question.Status == models.QuestionStatusSkipped,
+			})
+			continue
+		}
+		// every card of a group gets its own result
+		for _, cardID := range question.GroupCardIDs {
+			results = append(results, events.QuizCardResult{
+				CardID:  cardID,
+				Mode:    question.Mode,
+				Correct: question.Status == models.QuestionStatusAnswered && !slices.Contains(question.MissedCardIDs, cardID),
+				Skipped: question.Status == models.QuestionStatusSkipped,
+			})
+		}
+	}
+	return events.QuizSessionCompleted{
+		SessionID:     session.ID,
+		UserID:        session.UserID,
+		DeckID:        session.DeckID,
+		Modes:         session.Modes,
+		Total:         summary.Total,
+		Answered:      summary.Answered,
+		Correct:       summary.Correct,
+		Skipped:       summary.Skipped,
+		Accuracy:      summary.Accuracy,
+		ActiveSeconds: int64(summary.ActiveDuration / time.Second),
+		StartedAt:     session.CreatedAt.Unix(),
+		CompletedAt:   session.CompletedAt.Unix(),
+		Results:       results,
+	}
+}
+
+func toProtoQuizSession(session *models.QuizSession, now time.Time) *cards.QuizSession {
+	summary := quiz.Summarize(session)
+	res := &cards.QuizSession{
+		Id:             session.ID,
+		DeckId:         session.DeckID,
+		Status:         session.Status,
+		Order:          session.Order,
+		Modes:          session.Modes,
+		TotalQuestions: int32(summary.Total),
+		CurrentIndex:   int32(session.Current),
+		Answered:       int32(summary.Answered),
+		Correct:        int32(summary.Correct),
+		Skipped:        int32(summary.Skipped),
+		CreatedAt:      session.CreatedAt.Unix(),
+		UpdatedAt:      session.UpdatedAt.Unix(),
+		ExpiresAt:      session.ExpiresAt.Unix(),
+	}
+	if !session.CompletedAt.IsZero() {
+		res.CompletedAt = session.CompletedAt.Unix()
+	}
+	if challenge := session.Challenge; challenge != nil {
+		res.Challenge = &cards.QuizChallenge{
+			TimeLimitSeconds: int32(challenge.TimeLimit / time.Second),
+			Score:            int32(challenge.Score),
+			Combo:            int32(challenge.Combo),
+			Multiplier:       int32(quiz.Multiplier(challenge.Combo + 1)),
+			MaxCombo:         int32(challenge.MaxCombo),
+		}
+		if session.Status == models.QuizStatusActive {
+			res.Challenge.RemainingMs = max(challenge.Deadline.Sub(now).Milliseconds(), 0)
+		}
+	}
+	return res
+}
+
+// toProtoQuizQuestion converts the question, the expected answer is only included once it may be revealed
+func toProtoQuizQuestion(index int, question models.QuizQuestion, reveal bool) *cards.QuizQuestion {
+	res := &cards.QuizQuestion{
+		Index:      int32(index),
+		CardId:     question.CardID,
+		Mode:       question.Mode,
+		Prompt:     question.Prompt,
+		Hint:       question.Hint,
+		Options:    question.Options,
+		Status:     question.Status,
+		Answer:     question.Answer,
+		Correct:    question.Correct,
+		AnswerType: question.AnswerType,
+		Terms:      question.Terms,
+		Statement:  question.Statement,
+		Points:     int32(question.Points),
+	}
+	if question.Status != models.QuestionStatusPending && !question.AskedAt.IsZero() {
+		res.TimeSpentMs = question.AnsweredAt.Sub(question.AskedAt).Milliseconds()
+	}
+	if reveal || question.Status != models.QuestionStatusPending {
+		res.Expected = question.Expected
+	}
+	return res
+}
+
+func toProtoQuizSummary(session *models.QuizSession) *cards.QuizSummary {
+	summary := quiz.Summarize(session)
+	res := &cards.QuizSummary{
+		SessionId:     session.ID,
+		DeckId:        session.DeckID,
+		Total:         int32(summary.Total),
+		Answered:      int32(summary.Answered),
+		Correct:       int32(summary.Correct),
+		Incorrect:     int32(summary.Incorrect),
+		Skipped:       int32(summary.Skipped),
+		Unanswered:    int32(summary.Unanswered),
+		Accuracy:      summary.Accuracy,
+		ActiveSeconds: int64(summary.ActiveDuration / time.Second),
+		CompletedAt:   session.CompletedAt.Unix(),
+		Questions:     make([]*cards.QuizQuestion, 0, summary.Total),
+		Score:         int32(summary.Score),
+		MaxCombo:      int32(summary.MaxCombo),
+	}
+	for i, question := range quiz.AskedQuestions(session) {
+		// the quiz is over, so every expected answer can be shown
+		res.Questions = append(res.Questions, toProtoQuizQuestion(i, question, true))
+	}
+	return res
+}
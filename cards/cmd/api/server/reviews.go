@@ -0,0 +1,219 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/repositories"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/scheduler"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"github.com/google/uuid"
+)
+
+func (cs *CardsServer) SubmitReview(ctx context.Context, req *cards.SubmitReviewRequest) (*cards.ReviewResponse, error) {
+	payload := req.GetPayload()
+	dto := submitReviewDto{
+		UserID:   payload.GetUserId(),
+		DeckID:   payload.GetDeckId(),
+		CardID:   payload.GetCardId(),
+		Grade:    int(payload.GetGrade()),
+		AnswerMs: int(payload.GetAnswerMs()),
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	deck, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	card, err := cs.getDeckCard(ctx, deck.ID, dto.CardID)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := cs.reviews.GetState(ctx, dto.UserID, card.ID)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return nil, operationFailure("get review state", err)
+	}
+	isNew := errors.Is(err, repositories.ErrNotFound)
+
+	now := cs.now()
+	next := cs.scheduler.Schedule(toSchedulerState(state), scheduler.Grade(dto.Grade), now)
+	state = fromSchedulerState(next)
+	state.UserID = dto.UserID
+	state.CardID = card.ID
+	state.DeckID = deck.ID
+	state.Algorithm = cs.scheduler.Name()
+
+	log := models.ReviewLog{
+		ID:           uuid.NewString(),
+		UserID:       dto.UserID,
+		CardID:       card.ID,
+		DeckID:       deck.ID,
+		Grade:        dto.Grade,
+		IntervalDays: state.IntervalDays,
+		New:          isNew,
+		ReviewedAt:   now,
+	}
+	if err := cs.reviews.Save(ctx, state, log); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, errCardNotFound
+		}
+		return nil, operationFailure("save review", err)
+	}
+	cs.publish(ctx, events.CardReviewedKey, events.CardReviewed{
+		ReviewID:     log.ID,
+		UserID:       dto.UserID,
+		DeckID:       deck.ID,
+		CardID:       card.ID,
+		Grade:        dto.Grade,
+		New:          isNew,
+		IntervalDays: state.IntervalDays,
+		AnswerMs:     dto.AnswerMs,
+		ReviewedAt:   now.Unix(),
+	})
+
+	return &cards.ReviewResponse{State: toProtoReviewState(state)}, nil
+}
+
+func (cs *CardsServer) GetDueCards(ctx context.Context, req *cards.GetDueCardsRequest) (*cards.GetDueCardsResponse, error) {
+	payload := req.GetPayload()
+	dto := dueCardsDto{UserID: payload.GetUserId(), DeckID: payload.GetDeckId(), FilteredDeckID: payload.GetFilteredDeckId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+	if dto.FilteredDeckID != "" {
+		return cs.getFilteredDueCards(ctx, dto.UserID, dto.FilteredDeckID)
+	}
+
+	deck, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := cs.studySettings(ctx, dto.UserID)
+	if err != nil {
+		return nil, err
+	}
+	// daily limits roll over with the study day, at the same moment as goals and streaks
+	now := cs.now()
+	newToday, reviewsToday, err := cs.reviews.CountSince(ctx, dto.UserID, deck.ID, studyDayOf(now, settings).start)
+	if err != nil {
+		return nil, operationFailure("count reviews", err)
+	}
+	newRemaining := max(cs.config.NEW_CARDS_PER_DAY-newToday, 0)
+	reviewsRemaining := max(cs.config.REVIEWS_PER_DAY-reviewsToday, 0)
+
+	deckCards, err := cs.cards.ListByDeck(ctx, deck.ID)
+	if err != nil {
+		return nil, operationFailure("list cards", err)
+	}
+	cardsByID := make(map[string]models.Card, len(deckCards))
+	for _, card := range deckCards {
+		cardsByID[card.ID] = card
+	}
+
+	res := &cards.GetDueCardsResponse{
+		NewRemaining:     int32(newRemaining),
+		ReviewsRemaining: int32(reviewsRemaining),
+	}
+
+	if reviewsRemaining > 0 {
+		due, err := cs.reviews.ListDue(ctx, dto.UserID, deck.ID, now, reviewsRemaining)
+		if err != nil {
+			return nil, operationFailure("list due cards", err)
+		}
+		for _, state := range due {
+			card, ok := cardsByID[state.CardID]
+			if !ok {
+				continue
+			}
+			res.Cards = append(res.Cards, &cards.DueCard{Card: toProtoCard(card), State: toProtoReviewState(state)})
+		}
+	}
+
+	if newRemaining > 0 {
+		states, err := cs.reviews.ListStates(ctx, dto.UserID, deck.ID)
+		if err != nil {
+			return nil, operationFailure("list review states", err)
+		}
+		// new cards are introduced in the order they were added to the deck
+		for _, card := range deckCards {
+			if newRemaining == 0 {
+				break
+			}
+			if _, studied := states[card.ID]; studied {
+				continue
+			}
+			res.Cards = append(res.Cards, &cards.DueCard{Card: toProtoCard(card), IsNew: true})
+			newRemaining--
+		}
+	}
+
+	return res, nil
+}
+
+// getFilteredDueCards returns the due and new cards matching a filtered deck, up to its limit
+func (cs *CardsServer) getFilteredDueCards(ctx context.Context, userID, filteredDeckID string) (*cards.GetDueCardsResponse, error) {
+	deck, err := cs.getOwnedFilteredDeck(ctx, userID, filteredDeckID)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := cs.filteredCards(ctx, deck, repositories.DueStudy)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &cards.GetDueCardsResponse{Cards: make([]*cards.DueCard, 0, len(matches))}
+	for _, match := range matches {
+		dueCard := &cards.DueCard{Card: toProtoCard(match.Card), IsNew: !match.Studied}
+		if match.Studied {
+			dueCard.State = toProtoReviewState(match.State)
+		}
+		res.Cards = append(res.Cards, dueCard)
+	}
+	return res, nil
+}
+
+func toSchedulerState(state models.ReviewState) scheduler.State {
+	return scheduler.State{
+		Repetitions:  state.Repetitions,
+		Lapses:       state.Lapses,
+		IntervalDays: state.IntervalDays,
+		Ease:         state.Ease,
+		Stability:    state.Stability,
+		Difficulty:   state.Difficulty,
+		Due:          state.Due,
+		LastReview:   state.LastReview,
+	}
+}
+
+func fromSchedulerState(state scheduler.State) models.ReviewState {
+	return models.ReviewState{
+		Repetitions:  state.Repetitions,
+		Lapses:       state.Lapses,
+		IntervalDays: state.IntervalDays,
+		Ease:         state.Ease,
+		Stability:    state.Stability,
+		Difficulty:   state.Difficulty,
+		Due:          state.Due,
+		LastReview:   state.LastReview,
+	}
+}
+
+func toProtoReviewState(state models.ReviewState) *cards.ReviewState {
+	return &cards.ReviewState{
+		CardId:         state.CardID,
+		Algorithm:      state.Algorithm,
+		Repetitions:    int32(state.Repetitions),
+		Lapses:         int32(state.Lapses),
+		IntervalDays:   int32(state.IntervalDays),
+		Ease:           state.Ease,
+		Stability:      state.Stability,
+		Difficulty:     state.Difficulty,
+		DueAt:          state.Due.Unix(),
+		LastReviewedAt: state.LastReview.Unix(),
+	}
+}
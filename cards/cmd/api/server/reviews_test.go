@@ -0,0 +1,153 @@
+package server
+
+import (
+	"context"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func dueCards(t *testing.T, ts *testServer, deckID string) *cards.GetDueCardsResponse {
+	t.Helper()
+	res, err := ts.GetDueCards(context.Background(), &cards.GetDueCardsRequest{
+		Payload: &cards.GetDueCardsPayload{UserId: testUserID, DeckId: deckID},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func review(t *testing.T, ts *testServer, deckID, cardID string, grade int32) *cards.ReviewState {
+	t.Helper()
+	res, err := ts.SubmitReview(context.Background(), &cards.SubmitReviewRequest{
+		Payload: &cards.SubmitReviewPayload{UserId: testUserID, DeckId: deckID, CardId: cardID, Grade: grade},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.GetState()
+}
+
+func cardIDs(due []*cards.DueCard) []string {
+	ids := make([]string, 0, len(due))
+	for _, card := range due {
+		ids = append(ids, card.GetCard().GetId())
+	}
+	return ids
+}
+
+func TestDailyLimits(t *testing.T) {
+	cfg := testConfig()
+	cfg.NEW_CARDS_PER_DAY = 3
+	cfg.REVIEWS_PER_DAY = 2
+	ts := newTestServer(t, cfg)
+	deck, deckCards := ts.seedDeck(t, "deck-1", 5)
+
+	// new cards come in the order they were added, up to the daily limit
+	res := dueCards(t, ts, deck.ID)
+	if got, want := cardIDs(res.GetCards()), []string{deckCards[0].ID, deckCards[1].ID, deckCards[2].ID}; !equalStrings(got, want) {
+		t.Fatalf("first day queue %v, want %v", got, want)
+	}
+	if res.GetNewRemaining() != 3 || res.GetReviewsRemaining() != 2 {
+		t.Fatalf("first day remaining %d new and %d reviews, want 3 and 2", res.GetNewRemaining(), res.GetReviewsRemaining())
+	}
+	for _, card := range res.GetCards() {
+		review(t, ts, deck.ID, card.GetCard().GetId(), int32(3))
+	}
+
+	// studying the new cards uses up the new card limit for the rest of the day
+	res = dueCards(t, ts, deck.ID)
+	if len(res.GetCards()) != 0 || res.GetNewRemaining() != 0 {
+		t.Fatalf("after studying, queue %v with %d new remaining, want nothing", cardIDs(res.GetCards()), res.GetNewRemaining())
+	}
+
+	// next day the three reviews are due, but only two fit the review limit, and new cards come back
+	ts.clock.Advance(24 * time.Hour)
+	res = dueCards(t, ts, deck.ID)
+	var reviews, fresh []string
+	for _, card := range res.GetCards() {
+		if card.GetIsNew() {
+			fresh = append(fresh, card.GetCard().GetId())
+		} else {
+			reviews = append(reviews, card.GetCard().GetId())
+		}
+	}
+	if len(reviews) != 2 || !equalStrings(fresh, []string{deckCards[3].ID, deckCards[4].ID}) {
+		t.Fatalf("second day %v due and %v new, want 2 due and cards 4 and 5", reviews, fresh)
+	}
+	if res.GetNewRemaining() != 3 || res.GetReviewsRemaining() != 2 {
+		t.Fatalf("second day remaining %d new and %d reviews, want 3 and 2", res.GetNewRemaining(), res.GetReviewsRemaining())
+	}
+
+	review(t, ts, deck.ID, reviews[0], int32(3))
+	res = dueCards(t, ts, deck.ID)
+	if res.GetReviewsRemaining() != 1 {
+		t.Fatalf("after one review %d reviews remaining, want 1", res.GetReviewsRemaining())
+	}
+}
+
+func TestDailyLimitsResetAtRollover(t *testing.T) {
+	cfg := testConfig()
+	cfg.NEW_CARDS_PER_DAY = 1
+	ts := newTestServer(t, cfg)
+	ts.clock.now = time.Date(2024, time.March, 1, 23, 30, 0, 0, time.UTC)
+	deck, deckCards := ts.seedDeck(t, "deck-1", 2)
+
+	review(t, ts, deck.ID, deckCards[0].ID, int32(3))
+	// late night reviews count for the evening until the default rollover at 4 am
+	ts.clock.Advance(time.Hour)
+	if res := dueCards(t, ts, deck.ID); res.GetNewRemaining() != 0 {
+		t.Fatalf("%d new cards remaining after midnight, want 0", res.GetNewRemaining())
+	}
+	ts.clock.Advance(4 * time.Hour)
+	if res := dueCards(t, ts, deck.ID); res.GetNewRemaining() != 1 || !equalStrings(cardIDs(res.GetCards()), []string{deckCards[1].ID}) {
+		t.Fatalf("after the rollover %d new remaining and queue %v, want 1 and the second card", res.GetNewRemaining(), cardIDs(res.GetCards()))
+	}
+}
+
+func TestDailyLimitsFollowTimezone(t *testing.T) {
+	cfg := testConfig()
+	cfg.NEW_CARDS_PER_DAY = 1
+	ts := newTestServer(t, cfg)
+	// 3:30 am in New York, before the rollover
+	ts.clock.now = time.Date(2024, time.March, 1, 8, 30, 0, 0, time.UTC)
+	deck, deckCards := ts.seedDeck(t, "deck-1", 2)
+	if err := ts.updateProfile(t, "America/New_York", ts.clock.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	review(t, ts, deck.ID, deckCards[0].ID, int32(3))
+	if res := dueCards(t, ts, deck.ID); res.GetNewRemaining() != 0 {
+		t.Fatalf("%d new cards remaining before the rollover in New York, want 0", res.GetNewRemaining())
+	}
+	ts.clock.Advance(time.Hour)
+	if res := dueCards(t, ts, deck.ID); res.GetNewRemaining() != 1 {
+		t.Fatalf("%d new cards remaining after the rollover in New York, want 1", res.GetNewRemaining())
+	}
+}
+
+func TestSubmitReviewRejectsInvalidGrade(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	deck, deckCards := ts.seedDeck(t, "deck-1", 1)
+	_, err := ts.SubmitReview(context.Background(), &cards.SubmitReviewRequest{
+		Payload: &cards.SubmitReviewPayload{UserId: testUserID, DeckId: deck.ID, CardId: deckCards[0].ID, Grade: 5},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("grade 5 returned %v, want InvalidArgument", err)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
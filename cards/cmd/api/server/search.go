@@ -0,0 +1,202 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/search"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"github.com/Salladin95/card-quizzler-microservices/consumer"
+)
+
+const (
+	// defaultSuggestions is used when an autocomplete request does not set a limit
+	defaultSuggestions = 8
+	// rebuildBatchSize is the number of decks read at once while rebuilding the search index
+	rebuildBatchSize = 100
+)
+
+// SearchEventKeys are the events keeping the search index up to date
+var SearchEventKeys = []string{
+	events.DeckCreatedKey,
+	events.DeckUpdatedKey,
+	events.DeckDeletedKey,
+	events.CardCreatedKey,
+	events.CardUpdatedKey,
+	events.CardDeletedKey,
+}
+
+// Search finds the user's decks and cards by their text, decks shared with the user are not searched
+func (cs *CardsServer) Search(ctx context.Context, req *cards.SearchRequest) (*cards.SearchResponse, error) {
+	payload := req.GetPayload()
+	dto := searchDto{
+		UserID: payload.GetUserId(),
+		Query:  payload.GetQuery(),
+		Kind:   payload.GetKind(),
+		DeckID: payload.GetDeckId(),
+		Limit:  int(payload.GetLimit()),
+		Offset: int(payload.GetOffset()),
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+	if dto.Limit == 0 {
+		dto.Limit = defaultPageSize
+	}
+
+	result, err := cs.search.Search(ctx, search.Query{
+		OwnerID: dto.UserID,
+		Text:    dto.Query,
+		Kind:    dto.Kind,
+		DeckID:  dto.DeckID,
+		Limit:   dto.Limit,
+		Offset:  dto.Offset,
+	})
+	if err != nil {
+		return nil, operationFailure("search", err)
+	}
+
+	res := &cards.SearchResponse{Hits: make([]*cards.SearchHit, 0, len(result.Hits)), Total: int32(result.Total)}
+	for _, hit := range result.Hits {
+		res.Hits = append(res.Hits, &cards.SearchHit{
+			Kind:        hit.Kind,
+			Id:          hit.ID,
+			DeckId:      hit.DeckID,
+			Score:       hit.Score,
+			Title:       hit.Title,
+			Description: hit.Description,
+			Front:       hit.Front,
+			Back:        hit.Back,
+			Tags:        hit.Tags,
+			Highlights:  hit.Highlights,
+		})
+	}
+	return res, nil
+}
+
+// Autocomplete completes the last word typed into the search box
+func (cs *CardsServer) Autocomplete(ctx context.Context, req *cards.AutocompleteRequest) (*cards.AutocompleteResponse, error) {
+	payload := req.GetPayload()
+	dto := autocompleteDto{
+		UserID: payload.GetUserId(),
+		Prefix: payload.GetPrefix(),
+		Limit:  int(payload.GetLimit()),
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+	if dto.Limit == 0 {
+		dto.Limit = defaultSuggestions
+	}
+
+	suggestions, err := cs.search.Autocomplete(ctx, dto.UserID, dto.Prefix, dto.Limit)
+	if err != nil {
+		return nil, operationFailure("autocomplete", err)
+	}
+	return &cards.AutocompleteResponse{Suggestions: suggestions}, nil
+}
+
+// HandleSearchEvent applies a deck or card event to the search index, returned errors make the consumer retry it
+func (cs *CardsServer) HandleSearchEvent(ctx context.Context, key string, payload []byte) error {
+	switch key {
+	case events.DeckCreatedKey, events.DeckUpdatedKey:
+		var event events.DeckChanged
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return consumer.Permanent(err)
+		}
+		return cs.search.IndexDeck(search.Deck{
+			ID:          event.DeckID,
+			OwnerID:     event.OwnerID,
+			Title:       event.Title,
+			Description: event.Description,
+			Tags:        event.Tags,
+			UpdatedAt:   event.UpdatedAt,
+		})
+	case events.DeckDeletedKey:
+		var event events.DeckDeleted
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return consumer.Permanent(err)
+		}
+		return cs.search.DeleteDeck(event.DeckID)
+	case events.CardCreatedKey, events.CardUpdatedKey:
+		var event events.CardChanged
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return consumer.Permanent(err)
+		}
+		return cs.search.IndexCard(search.Card{
+			ID:        event.CardID,
+			DeckID:    event.DeckID,
+			OwnerID:   event.OwnerID,
+			Front:     event.Front,
+			Back:      event.Back,
+			Tags:      event.Tags,
+			UpdatedAt: event.UpdatedAt,
+		})
+	case events.CardDeletedKey:
+		var event events.CardDeleted
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return consumer.Permanent(err)
+		}
+		return cs.search.DeleteCard(event.CardID)
+	default:
+		return consumer.Permanent(fmt.Errorf("unexpected event %s", key))
+	}
+}
+
+// RebuildSearchIndex indexes every deck and card, it fills a new index.
+// Events published meanwhile wait in the queue and are applied afterwards, older versions are skipped.
+func (cs *CardsServer) RebuildSearchIndex(ctx context.Context) (int, error) {
+	indexed := 0
+	afterID := ""
+	for {
+		decks, err := cs.decks.ListAll(ctx, afterID, rebuildBatchSize)
+		if err != nil {
+			return indexed, err
+		}
+		if len(decks) == 0 {
+			return indexed, nil
+		}
+		for _, deck := range decks {
+			if err := cs.indexDeck(ctx, deck); err != nil {
+				return indexed, err
+			}
+			indexed++
+		}
+		afterID = decks[len(decks)-1].ID
+	}
+}
+
+func (cs *CardsServer) indexDeck(ctx context.Context, deck models.Deck) error {
+	err := cs.search.IndexDeck(search.Deck{
+		ID:          deck.ID,
+		OwnerID:     deck.OwnerID,
+		Title:       deck.Title,
+		Description: deck.Description,
+		Tags:        deck.Tags,
+		UpdatedAt:   deck.UpdatedAt.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	deckCards, err := cs.cards.ListByDeck(ctx, deck.ID)
+	if err != nil {
+		return err
+	}
+	for _, card := range deckCards {
+		err := cs.search.IndexCard(search.Card{
+			ID:        card.ID,
+			DeckID:    deck.ID,
+			OwnerID:   deck.OwnerID,
+			Front:     card.Front,
+			Back:      card.Back,
+			Tags:      card.Tags,
+			UpdatedAt: card.UpdatedAt.Unix(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"github.com/Salladin95/card-quizzler-microservices/consumer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"slices"
+	"testing"
+)
+
+// deliverSearchEvents hands the published deck and card events to the search consumer, like the queue would
+func (ts *testServer) deliverSearchEvents(t *testing.T) {
+	t.Helper()
+	ts.publisher.mu.Lock()
+	published := ts.publisher.events
+	ts.publisher.events = nil
+	ts.publisher.mu.Unlock()
+	for _, event := range published {
+		if !slices.Contains(SearchEventKeys, event.key) {
+			continue
+		}
+		payload, err := json.Marshal(event.payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ts.HandleSearchEvent(context.Background(), event.key, payload); err != nil {
+			t.Fatalf("handle %s: %v", event.key, err)
+		}
+	}
+}
+
+func searchIDs(t *testing.T, ts *testServer, query string) []string {
+	t.Helper()
+	res, err := ts.Search(context.Background(), &cards.SearchRequest{Payload: &cards.SearchPayload{UserId: testUserID, Query: query}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]string, 0, len(res.GetHits()))
+	for _, hit := range res.GetHits() {
+		ids = append(ids, hit.GetKind()+":"+hit.GetId())
+	}
+	return ids
+}
+
+func TestSearchFollowsDeckAndCardEvents(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	deck, err := ts.CreateDeck(ctx, &cards.CreateDeckRequest{Payload: &cards.CreateDeckPayload{
+		UserId: testUserID, Title: "Astronomy", Description: "Planets and moons",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deckID := deck.GetDeck().GetId()
+	card, err := ts.CreateCard(ctx, &cards.CreateCardRequest{Payload: &cards.CreateCardPayload{
+		UserId: testUserID, DeckId: deckID, Front: "Largest planet", Back: "Jupiter", Tags: []string{"solar-system"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cardID := card.GetCard().GetId()
+	ts.deliverSearchEvents(t)
+
+	if got := searchIDs(t, ts, "planet"); !slices.Equal(got, []string{"deck:" + deckID, "card:" + cardID}) &&
+		!slices.Equal(got, []string{"card:" + cardID, "deck:" + deckID}) {
+		t.Errorf("search found %v, want the deck and the card", got)
+	}
+
+	suggestions, err := ts.Autocomplete(ctx, &cards.AutocompleteRequest{Payload: &cards.AutocompletePayload{UserId: testUserID, Prefix: "Astro"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(suggestions.GetSuggestions(), []string{"Astronomy"}) {
+		t.Errorf("suggestions %v", suggestions.GetSuggestions())
+	}
+
+	if _, err := ts.DeleteDeck(ctx, &cards.DeleteDeckRequest{Payload: &cards.DeleteDeckPayload{UserId: testUserID, DeckId: deckID}}); err != nil {
+		t.Fatal(err)
+	}
+	ts.deliverSearchEvents(t)
+	if got := searchIDs(t, ts, "planet"); len(got) != 0 {
+		t.Errorf("search found %v after deleting the deck", got)
+	}
+}
+
+func TestRebuildSearchIndex(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	// seeded straight into the repositories, no events reach the index
+	ts.seedDeck(t, "a", 2)
+	ts.seedDeck(t, "b", 1)
+	if got := searchIDs(t, ts, "front"); len(got) != 0 {
+		t.Fatalf("search found %v before the rebuild", got)
+	}
+
+	decks, err := ts.RebuildSearchIndex(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decks != 2 {
+		t.Errorf("rebuilt %d decks, want 2", decks)
+	}
+	if got := searchIDs(t, ts, "front"); len(got) != 3 {
+		t.Errorf("search found %v after the rebuild, want the 3 cards", got)
+	}
+}
+
+func TestSearchRejectsInvalidRequests(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	tests := []*cards.SearchPayload{
+		{UserId: testUserID},
+		{UserId: testUserID, Query: "x", Kind: "folder"},
+		{UserId: testUserID, Query: "x", Limit: 1000},
+	}
+	for _, payload := range tests {
+		if _, err := ts.Search(context.Background(), &cards.SearchRequest{Payload: payload}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("search %+v returned %v, want InvalidArgument", payload, err)
+		}
+	}
+}
+
+func TestHandleSearchEventRejectsMalformedEvents(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	for key, payload := range map[string]string{"cards.card.created": "{", "cards.card.reviewed": "{}"} {
+		if err := ts.HandleSearchEvent(context.Background(), key, []byte(payload)); !consumer.IsPermanent(err) {
+			t.Errorf("%s %s returned %v, want a permanent error", key, payload, err)
+		}
+	}
+	if count, err := ts.search.Count(); err != nil || count != 0 {
+		t.Errorf("%d documents indexed, err %v", count, err)
+	}
+}
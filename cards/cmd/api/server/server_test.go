@@ -0,0 +1,169 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/config"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/leaderboard"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/printout"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/repositories"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/scheduler"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/search"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testUserID = "user-1"
+
+// recordingPublisher keeps the published events instead of sending them
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []publishedEvent
+}
+
+type publishedEvent struct {
+	key     string
+	payload any
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, key string, payload any) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, publishedEvent{key: key, payload: payload})
+	return nil
+}
+
+// keys returns the keys of the published events in order
+func (p *recordingPublisher) keys() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	keys := make([]string, 0, len(p.events))
+	for _, event := range p.events {
+		keys = append(keys, event.key)
+	}
+	return keys
+}
+
+// testClock is a settable clock for the server
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+type testServer struct {
+	*CardsServer
+	store     *repositories.MemoryStore
+	publisher *recordingPublisher
+	clock     *testClock
+}
+
+func testConfig() config.AppCfg {
+	return config.AppCfg{
+		STORAGE:                     config.StorageMemory,
+		SCHEDULER_ALGORITHM:         scheduler.AlgorithmSM2,
+		DESIRED_RETENTION:           0.9,
+		MAXIMUM_INTERVAL:            36500,
+		NEW_CARDS_PER_DAY:           20,
+		REVIEWS_PER_DAY:             200,
+		QUIZ_SESSION_TTL:            24 * time.Hour,
+		CHALLENGE_LATENCY_ALLOWANCE: 2 * time.Second,
+		MAX_IMPORT_BYTES:            1 << 20,
+		IMPORT_SYNC_BYTES:           1 << 20,
+		EXPORT_SYNC_CARDS:           1000,
+		JOB_MAX_ATTEMPTS:            3,
+		JOB_RETRY_DELAY:             time.Second,
+		SEARCH_LANGUAGES:            []string{"en"},
+		INVITATION_TTL:              7 * 24 * time.Hour,
+		STREAK_FREEZE_DAYS:          7,
+		MAX_STREAK_FREEZES:          2,
+	}
+}
+
+// newTestServer returns a server on memory repositories with its clock set to a fixed morning
+func newTestServer(t *testing.T, cfg config.AppCfg) *testServer {
+	t.Helper()
+	algorithm, err := scheduler.New(cfg.SCHEDULER_ALGORITHM, scheduler.Options{
+		DesiredRetention: cfg.DESIRED_RETENTION,
+		MaximumInterval:  cfg.MAXIMUM_INTERVAL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	printer, err := printout.NewPrinter("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	index, _, err := search.Open("", cfg.SEARCH_LANGUAGES)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { index.Close() })
+	store := repositories.NewMemoryStore()
+	publisher := &recordingPublisher{}
+	clock := &testClock{now: time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC)}
+	cs := NewCardsServer(cfg, Repositories{
+		Decks:           repositories.NewMemoryDeckRepository(store),
+		Cards:           repositories.NewMemoryCardRepository(store),
+		Reviews:         repositories.NewMemoryReviewRepository(store),
+		QuizSessions:    repositories.NewMemoryQuizSessionRepository(store),
+		ChallengeScores: repositories.NewMemoryChallengeScoreRepository(store),
+		Jobs:            repositories.NewMemoryJobRepository(store),
+		Folders:         repositories.NewMemoryFolderRepository(store),
+		FilteredDecks:   repositories.NewMemoryFilteredDeckRepository(store),
+		Sharing:         repositories.NewMemorySharingRepository(store),
+		Catalog:         repositories.NewMemoryCatalogRepository(store),
+		Forks:           repositories.NewMemoryForkRepository(store),
+		Stats:           repositories.NewMemoryStatsRepository(store),
+		Streaks:         repositories.NewMemoryStreakRepository(store),
+		Achievements:    repositories.NewMemoryAchievementRepository(store),
+		Follows:         repositories.NewMemoryFollowRepository(store),
+		Leaderboards:    leaderboard.NewMemorySortedSet(clock.Now),
+	}, algorithm, printer, index, publisher)
+	cs.now = clock.Now
+	return &testServer{CardsServer: cs, store: store, publisher: publisher, clock: clock}
+}
+
+// seedDeck stores a deck of the test user with numbered cards, added a minute apart
+func (ts *testServer) seedDeck(t *testing.T, id string, cardCount int) (models.Deck, []models.Card) {
+	t.Helper()
+	ctx := context.Background()
+	now := ts.clock.Now()
+	deck := models.Deck{ID: id, OwnerID: testUserID, Title: "Deck " + id, CreatedAt: now, UpdatedAt: now}
+	if err := ts.decks.Create(ctx, deck); err != nil {
+		t.Fatal(err)
+	}
+	deckCards := make([]models.Card, 0, cardCount)
+	for i := 1; i <= cardCount; i++ {
+		created := now.Add(time.Duration(i) * time.Minute)
+		card := models.Card{
+			ID:           fmt.Sprintf("%s-card-%d", id, i),
+			DeckID:       id,
+			Front:        fmt.Sprintf("front %d", i),
+			Back:         fmt.Sprintf("back %d", i),
+			Alternatives: []string{},
+			Tags:         []string{},
+			AnswerType:   "text",
+			CreatedAt:    created,
+			UpdatedAt:    created,
+		}
+		if err := ts.cards.Create(ctx, card); err != nil {
+			t.Fatal(err)
+		}
+		deckCards = append(deckCards, card)
+	}
+	return deck, deckCards
+}
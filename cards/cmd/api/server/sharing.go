@@ -0,0 +1,380 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/repositories"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+// CreateInvitation invites a user to the deck by email, or creates a link anyone holding it can join with
+func (cs *CardsServer) CreateInvitation(ctx context.Context, req *cards.CreateInvitationRequest) (*cards.InvitationResponse, error) {
+	payload := req.GetPayload()
+	dto := createInvitationDto{
+		UserID:         payload.GetUserId(),
+		DeckID:         payload.GetDeckId(),
+		Role:           payload.GetRole(),
+		Email:          strings.ToLower(strings.TrimSpace(payload.GetEmail())),
+		InviterEmail:   payload.GetInviterEmail(),
+		ExpiresInHours: int(payload.GetExpiresInHours()),
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	deck, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	token, err := generateInvitationToken()
+	if err != nil {
+		return nil, operationFailure("generate invitation token", err)
+	}
+	ttl := cs.config.INVITATION_TTL
+	if dto.ExpiresInHours > 0 {
+		ttl = time.Duration(dto.ExpiresInHours) * time.Hour
+	}
+	now := cs.now()
+	invitation := models.DeckInvitation{
+		ID:        uuid.NewString(),
+		DeckID:    deck.ID,
+		InviterID: dto.UserID,
+		Email:     dto.Email,
+		Role:      dto.Role,
+		TokenHash: hashInvitationToken(token),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+	if err := cs.sharing.CreateInvitation(ctx, invitation); err != nil {
+		return nil, operationFailure("create invitation", err)
+	}
+
+	if invitation.Email == "" {
+		return &cards.InvitationResponse{Invitation: toProtoInvitation(invitation), Token: token}, nil
+	}
+	cs.publish(ctx, events.DeckInvitedKey, events.DeckInvited{
+		InvitationID: invitation.ID,
+		DeckID:       deck.ID,
+		DeckTitle:    deck.Title,
+		InviterID:    dto.UserID,
+		InviterEmail: dto.InviterEmail,
+		Email:        invitation.Email,
+		Role:         invitation.Role,
+		Token:        token,
+		ExpiresAt:    invitation.ExpiresAt.Unix(),
+	})
+	return &cards.InvitationResponse{Invitation: toProtoInvitation(invitation)}, nil
+}
+
+// ListInvitations returns the invitations of the deck nobody accepted yet, expired ones included
+func (cs *CardsServer) ListInvitations(ctx context.Context, req *cards.DeckSharingRequest) (*cards.ListInvitationsResponse, error) {
+	payload := req.GetPayload()
+	dto := deckDto{UserID: payload.GetUserId(), DeckID: payload.GetDeckId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	deck, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	invitations, err := cs.sharing.ListInvitations(ctx, deck.ID)
+	if err != nil {
+		return nil, operationFailure("list invitations", err)
+	}
+	res := &cards.ListInvitationsResponse{Invitations: make([]*cards.DeckInvitation, 0, len(invitations))}
+	for _, invitation := range invitations {
+		res.Invitations = append(res.Invitations, toProtoInvitation(invitation))
+	}
+	return res, nil
+}
+
+// RevokeInvitation deletes the invitation, members who already joined with it stay
+func (cs *CardsServer) RevokeInvitation(ctx context.Context, req *cards.RevokeInvitationRequest) (*cards.DeleteResponse, error) {
+	payload := req.GetPayload()
+	dto := invitationDto{UserID: payload.GetUserId(), DeckID: payload.GetDeckId(), InvitationID: payload.GetInvitationId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	deck, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	invitation, err := cs.sharing.GetInvitation(ctx, dto.InvitationID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, errInvitationNotFound
+		}
+		return nil, operationFailure("get invitation", err)
+	}
+	if invitation.DeckID != deck.ID {
+		return nil, errInvitationNotFound
+	}
+	if err := cs.sharing.DeleteInvitation(ctx, invitation.ID); err != nil {
+		return nil, operationFailure("delete invitation", err)
+	}
+	return &cards.DeleteResponse{Message: "invitation is revoked"}, nil
+}
+
+// AcceptInvitation makes the user a member of the deck. Accepting never lowers the role the user already has.
+func (cs *CardsServer) AcceptInvitation(ctx context.Context, req *cards.AcceptInvitationRequest) (*cards.SharedDeckResponse, error) {
+	payload := req.GetPayload()
+	dto := acceptInvitationDto{UserID: payload.GetUserId(), Email: payload.GetEmail(), Token: payload.GetToken()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	invitation, err := cs.sharing.GetInvitationByToken(ctx, hashInvitationToken(dto.Token))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, errInvitationNotFound
+		}
+		return nil, operationFailure("get invitation", err)
+	}
+	now := cs.now()
+	if invitation.Email != "" {
+		if invitation.AcceptedBy != "" {
+			return nil, errInvitationUsed
+		}
+		if !strings.EqualFold(invitation.Email, dto.Email) {
+			return nil, errInvitationEmail
+		}
+	}
+	if !now.Before(invitation.ExpiresAt) {
+		return nil, errInvitationExpired
+	}
+	deck, err := cs.decks.Get(ctx, invitation.DeckID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, errInvitationNotFound
+		}
+		return nil, operationFailure("get deck", err)
+	}
+	if deck.OwnerID == dto.UserID {
+		return &cards.SharedDeckResponse{SharedDeck: toProtoSharedDeck(deck, models.RoleOwner)}, nil
+	}
+
+	member, err := cs.sharing.GetMember(ctx, deck.ID, dto.UserID)
+	joined := errors.Is(err, repositories.ErrNotFound)
+	if err != nil && !joined {
+		return nil, operationFailure("get deck member", err)
+	}
+	if joined {
+		member = models.DeckMember{DeckID: deck.ID, UserID: dto.UserID, CreatedAt: now}
+	}
+	promoted := roleRanks[invitation.Role] > roleRanks[member.Role]
+	if promoted {
+		member.Role = invitation.Role
+		member.InvitedBy = invitation.InviterID
+		member.UpdatedAt = now
+	}
+	if invitation.Email != "" {
+		invitation.AcceptedBy = dto.UserID
+		invitation.AcceptedAt = now
+	}
+	if err := cs.sharing.AcceptInvitation(ctx, invitation, member); err != nil {
+		if errors.Is(err, repositories.ErrConflict) {
+			return nil, errInvitationUsed
+		}
+		return nil, operationFailure("accept invitation", err)
+	}
+
+	if joined {
+		cs.publish(ctx, events.DeckMemberAddedKey, memberChanged(deck, member, invitation.InviterID))
+	} else if promoted {
+		cs.publish(ctx, events.DeckMemberUpdatedKey, memberChanged(deck, member, invitation.InviterID))
+	}
+	return &cards.SharedDeckResponse{SharedDeck: toProtoSharedDeck(deck, member.Role)}, nil
+}
+
+// ListMembers returns the users the deck is shared with, every member can see the others
+func (cs *CardsServer) ListMembers(ctx context.Context, req *cards.DeckSharingRequest) (*cards.ListMembersResponse, error) {
+	payload := req.GetPayload()
+	dto := deckDto{UserID: payload.GetUserId(), DeckID: payload.GetDeckId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	deck, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	members, err := cs.sharing.ListMembers(ctx, deck.ID)
+	if err != nil {
+		return nil, operationFailure("list deck members", err)
+	}
+	res := &cards.ListMembersResponse{OwnerId: deck.OwnerID, Members: make([]*cards.DeckMember, 0, len(members))}
+	for _, member := range members {
+		res.Members = append(res.Members, toProtoMember(member))
+	}
+	return res, nil
+}
+
+// UpdateMember gives a member another role
+func (cs *CardsServer) UpdateMember(ctx context.Context, req *cards.UpdateMemberRequest) (*cards.MemberResponse, error) {
+	payload := req.GetPayload()
+	dto := updateMemberDto{
+		UserID:   payload.GetUserId(),
+		DeckID:   payload.GetDeckId(),
+		MemberID: payload.GetMemberId(),
+		Role:     payload.GetRole(),
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	deck, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, models.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	member, err := cs.getMember(ctx, deck, dto.MemberID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Role == dto.Role {
+		return &cards.MemberResponse{Member: toProtoMember(member)}, nil
+	}
+	member.Role = dto.Role
+	member.UpdatedAt = cs.now()
+	if err := cs.sharing.SaveMember(ctx, member); err != nil {
+		return nil, operationFailure("update deck member", err)
+	}
+	cs.publish(ctx, events.DeckMemberUpdatedKey, memberChanged(deck, member, dto.UserID))
+
+	return &cards.MemberResponse{Member: toProtoMember(member)}, nil
+}
+
+// RemoveMember takes the deck away from a member, members can remove themselves to leave the deck
+func (cs *CardsServer) RemoveMember(ctx context.Context, req *cards.RemoveMemberRequest) (*cards.DeleteResponse, error) {
+	payload := req.GetPayload()
+	dto := memberDto{UserID: payload.GetUserId(), DeckID: payload.GetDeckId(), MemberID: payload.GetMemberId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	role := models.RoleOwner
+	if dto.MemberID == dto.UserID {
+		role = models.RoleViewer
+	}
+	deck, _, err := cs.getDeckAs(ctx, dto.UserID, dto.DeckID, role)
+	if err != nil {
+		return nil, err
+	}
+	member, err := cs.getMember(ctx, deck, dto.MemberID)
+	if err != nil {
+		return nil, err
+	}
+	if err := cs.sharing.DeleteMember(ctx, deck.ID, member.UserID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, errMemberNotFound
+		}
+		return nil, operationFailure("delete deck member", err)
+	}
+	member.Role = ""
+	cs.publish(ctx, events.DeckMemberRemovedKey, memberChanged(deck, member, dto.UserID))
+
+	return &cards.DeleteResponse{Message: "deck member is removed"}, nil
+}
+
+// ListSharedDecks returns the decks other users shared with the user, the latest joined first
+func (cs *CardsServer) ListSharedDecks(ctx context.Context, req *cards.ListSharedDecksRequest) (*cards.ListSharedDecksResponse, error) {
+	dto := userDto{UserID: req.GetPayload().GetUserId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	memberships, err := cs.sharing.ListMemberships(ctx, dto.UserID)
+	if err != nil {
+		return nil, operationFailure("list memberships", err)
+	}
+	res := &cards.ListSharedDecksResponse{SharedDecks: make([]*cards.SharedDeck, 0, len(memberships))}
+	for _, membership := range memberships {
+		deck, err := cs.decks.Get(ctx, membership.DeckID)
+		if err != nil {
+			// the deck was deleted after the memberships were read
+			if errors.Is(err, repositories.ErrNotFound) {
+				continue
+			}
+			return nil, operationFailure("get deck", err)
+		}
+		res.SharedDecks = append(res.SharedDecks, toProtoSharedDeck(deck, membership.Role))
+	}
+	return res, nil
+}
+
+// getMember loads a member of the deck, the creator of the deck is not a member and can't be changed
+func (cs *CardsServer) getMember(ctx context.Context, deck models.Deck, userID string) (models.DeckMember, error) {
+	if userID == deck.OwnerID {
+		return models.DeckMember{}, errDeckCreator
+	}
+	member, err := cs.sharing.GetMember(ctx, deck.ID, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return models.DeckMember{}, errMemberNotFound
+		}
+		return models.DeckMember{}, operationFailure("get deck member", err)
+	}
+	return member, nil
+}
+
+// generateInvitationToken returns a random url-safe token, only its hash is stored
+func generateInvitationToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func memberChanged(deck models.Deck, member models.DeckMember, changedBy string) events.DeckMemberChanged {
+	return events.DeckMemberChanged{
+		DeckID:    deck.ID,
+		DeckTitle: deck.Title,
+		OwnerID:   deck.OwnerID,
+		UserID:    member.UserID,
+		Role:      member.Role,
+		ChangedBy: changedBy,
+	}
+}
+
+func toProtoMember(member models.DeckMember) *cards.DeckMember {
+	return &cards.DeckMember{
+		DeckId:    member.DeckID,
+		UserId:    member.UserID,
+		Role:      member.Role,
+		InvitedBy: member.InvitedBy,
+		CreatedAt: member.CreatedAt.Unix(),
+		UpdatedAt: member.UpdatedAt.Unix(),
+	}
+}
+
+func toProtoInvitation(invitation models.DeckInvitation) *cards.DeckInvitation {
+	return &cards.DeckInvitation{
+		Id:        invitation.ID,
+		DeckId:    invitation.DeckID,
+		InviterId: invitation.InviterID,
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		ExpiresAt: invitation.ExpiresAt.Unix(),
+		CreatedAt: invitation.CreatedAt.Unix(),
+	}
+}
+
+func toProtoSharedDeck(deck models.Deck, role string) *cards.SharedDeck {
+	return &cards.SharedDeck{Deck: toProtoDeck(deck), Role: role}
+}
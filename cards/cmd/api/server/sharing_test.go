@@ -0,0 +1,268 @@
+package server
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"slices"
+	"testing"
+	"time"
+)
+
+const (
+	editorID = "user-2"
+	viewerID = "user-3"
+)
+
+// shareDeck invites a user with a link and accepts it on their behalf
+func shareDeck(t *testing.T, ts *testServer, deckID, userID, role string) {
+	t.Helper()
+	ctx := context.Background()
+	invitation, err := ts.CreateInvitation(ctx, &cards.CreateInvitationRequest{Payload: &cards.CreateInvitationPayload{
+		UserId: testUserID, DeckId: deckID, Role: role,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.AcceptInvitation(ctx, &cards.AcceptInvitationRequest{Payload: &cards.AcceptInvitationPayload{
+		UserId: userID, Token: invitation.GetToken(),
+	}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSharedDeckRoles(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	_, deckCards := ts.seedDeck(t, "shared", 2)
+	shareDeck(t, ts, "shared", editorID, "editor")
+	shareDeck(t, ts, "shared", viewerID, "viewer")
+
+	// viewers study the deck but can't change it
+	if _, err := ts.ListCards(ctx, &cards.ListCardsRequest{Payload: &cards.ListCardsPayload{UserId: viewerID, DeckId: "shared"}}); err != nil {
+		t.Errorf("viewer can't list the cards: %v", err)
+	}
+	_, err := ts.UpdateCard(ctx, &cards.UpdateCardRequest{Payload: &cards.UpdateCardPayload{
+		UserId: viewerID, DeckId: "shared", CardId: deckCards[0].ID, Front: "changed", Back: "back",
+	}})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("viewer updating a card returned %v, want PermissionDenied", err)
+	}
+
+	// editors change the cards, owners alone share and delete the deck
+	if _, err := ts.UpdateCard(ctx, &cards.UpdateCardRequest{Payload: &cards.UpdateCardPayload{
+		UserId: editorID, DeckId: "shared", CardId: deckCards[0].ID, Front: "changed", Back: "back",
+	}}); err != nil {
+		t.Errorf("editor can't update a card: %v", err)
+	}
+	_, err = ts.CreateInvitation(ctx, &cards.CreateInvitationRequest{Payload: &cards.CreateInvitationPayload{
+		UserId: editorID, DeckId: "shared", Role: "viewer",
+	}})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("editor inviting returned %v, want PermissionDenied", err)
+	}
+	_, err = ts.DeleteDeck(ctx, &cards.DeleteDeckRequest{Payload: &cards.DeleteDeckPayload{UserId: editorID, DeckId: "shared"}})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("editor deleting the deck returned %v, want PermissionDenied", err)
+	}
+
+	// other users don't see the deck at all
+	_, err = ts.GetDeck(ctx, &cards.GetDeckRequest{Payload: &cards.GetDeckPayload{UserId: "user-4", DeckId: "shared"}})
+	if err != errNotDeckOwner {
+		t.Errorf("stranger reading the deck returned %v", err)
+	}
+
+	shared, err := ts.ListSharedDecks(ctx, &cards.ListSharedDecksRequest{Payload: &cards.ListSharedDecksPayload{UserId: editorID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shared.GetSharedDecks()) != 1 || shared.GetSharedDecks()[0].GetRole() != "editor" {
+		t.Errorf("shared decks %v", shared.GetSharedDecks())
+	}
+}
+
+func TestEmailInvitation(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	ts.seedDeck(t, "shared", 1)
+	res, err := ts.CreateInvitation(ctx, &cards.CreateInvitationRequest{Payload: &cards.CreateInvitationPayload{
+		UserId: testUserID, DeckId: "shared", Role: "editor", Email: " Bob@Example.com", InviterEmail: "ann@example.com",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.GetToken() != "" {
+		t.Error("the token of an email invitation is returned to the inviter")
+	}
+	if res.GetInvitation().GetExpiresAt() != ts.clock.Now().Add(7*24*time.Hour).Unix() {
+		t.Errorf("invitation expires at %d", res.GetInvitation().GetExpiresAt())
+	}
+	invited, ok := ts.publisher.events[len(ts.publisher.events)-1].payload.(events.DeckInvited)
+	if !ok || invited.Email != "bob@example.com" || invited.DeckTitle != "Deck shared" || invited.Token == "" {
+		t.Fatalf("published %+v", ts.publisher.events[len(ts.publisher.events)-1])
+	}
+
+	accept := func(userID, email string) error {
+		_, err := ts.AcceptInvitation(ctx, &cards.AcceptInvitationRequest{Payload: &cards.AcceptInvitationPayload{
+			UserId: userID, Email: email, Token: invited.Token,
+		}})
+		return err
+	}
+	if err := accept(viewerID, "eve@example.com"); err != errInvitationEmail {
+		t.Errorf("accepting with another email returned %v", err)
+	}
+	if err := accept(editorID, "bob@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := accept(editorID, "bob@example.com"); err != errInvitationUsed {
+		t.Errorf("accepting twice returned %v", err)
+	}
+	if keys := ts.publisher.keys(); keys[len(keys)-1] != events.DeckMemberAddedKey {
+		t.Errorf("published %v", keys)
+	}
+
+	members, err := ts.ListMembers(ctx, &cards.DeckSharingRequest{Payload: &cards.DeckSharingPayload{UserId: editorID, DeckId: "shared"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if members.GetOwnerId() != testUserID || len(members.GetMembers()) != 1 || members.GetMembers()[0].GetRole() != "editor" {
+		t.Errorf("members %v", members)
+	}
+	invitations, err := ts.ListInvitations(ctx, &cards.DeckSharingRequest{Payload: &cards.DeckSharingPayload{UserId: testUserID, DeckId: "shared"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(invitations.GetInvitations()) != 0 {
+		t.Errorf("accepted invitation is still listed: %v", invitations.GetInvitations())
+	}
+}
+
+func TestLinkInvitation(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	ts.seedDeck(t, "shared", 1)
+	res, err := ts.CreateInvitation(ctx, &cards.CreateInvitationRequest{Payload: &cards.CreateInvitationPayload{
+		UserId: testUserID, DeckId: "shared", Role: "viewer", ExpiresInHours: 1,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	accept := func(userID string) (*cards.SharedDeckResponse, error) {
+		return ts.AcceptInvitation(ctx, &cards.AcceptInvitationRequest{Payload: &cards.AcceptInvitationPayload{
+			UserId: userID, Token: res.GetToken(),
+		}})
+	}
+
+	// links are used by anyone, a member keeps a higher role
+	shareDeck(t, ts, "shared", editorID, "editor")
+	for _, userID := range []string{viewerID, editorID} {
+		if _, err := accept(userID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	member, err := ts.sharing.GetMember(ctx, "shared", editorID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if member.Role != "editor" {
+		t.Errorf("accepting a viewer link made the editor a %s", member.Role)
+	}
+
+	ts.clock.Advance(time.Hour)
+	if _, err := accept("user-4"); err != errInvitationExpired {
+		t.Errorf("accepting an expired link returned %v", err)
+	}
+	if _, err := ts.AcceptInvitation(ctx, &cards.AcceptInvitationRequest{Payload: &cards.AcceptInvitationPayload{
+		UserId: "user-4", Token: "unknown",
+	}}); err != errInvitationNotFound {
+		t.Errorf("accepting an unknown token returned %v", err)
+	}
+}
+
+func TestManageMembers(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	ts.seedDeck(t, "shared", 1)
+	shareDeck(t, ts, "shared", editorID, "editor")
+	shareDeck(t, ts, "shared", viewerID, "viewer")
+
+	updated, err := ts.UpdateMember(ctx, &cards.UpdateMemberRequest{Payload: &cards.UpdateMemberPayload{
+		UserId: testUserID, DeckId: "shared", MemberId: viewerID, Role: "owner",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.GetMember().GetRole() != "owner" {
+		t.Errorf("member is a %s", updated.GetMember().GetRole())
+	}
+	// a co-owner manages the other members but not the creator
+	_, err = ts.UpdateMember(ctx, &cards.UpdateMemberRequest{Payload: &cards.UpdateMemberPayload{
+		UserId: viewerID, DeckId: "shared", MemberId: testUserID, Role: "viewer",
+	}})
+	if err != errDeckCreator {
+		t.Errorf("demoting the creator returned %v", err)
+	}
+	if _, err := ts.RemoveMember(ctx, &cards.RemoveMemberRequest{Payload: &cards.RemoveMemberPayload{
+		UserId: viewerID, DeckId: "shared", MemberId: editorID,
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	// members leave on their own
+	if _, err := ts.RemoveMember(ctx, &cards.RemoveMemberRequest{Payload: &cards.RemoveMemberPayload{
+		UserId: viewerID, DeckId: "shared", MemberId: viewerID,
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{events.DeckMemberUpdatedKey, events.DeckMemberRemovedKey, events.DeckMemberRemovedKey}
+	if keys := ts.publisher.keys(); !slices.Equal(keys[len(keys)-3:], want) {
+		t.Errorf("published %v", keys)
+	}
+	_, err = ts.GetDeck(ctx, &cards.GetDeckRequest{Payload: &cards.GetDeckPayload{UserId: editorID, DeckId: "shared"}})
+	if err != errNotDeckOwner {
+		t.Errorf("removed member reading the deck returned %v", err)
+	}
+}
+
+func TestSharedDeckImports(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	ts.seedDeck(t, "shared", 0)
+	shareDeck(t, ts, "shared", editorID, "editor")
+	shareDeck(t, ts, "shared", viewerID, "viewer")
+	importAs := func(userID string) (*cards.JobResponse, error) {
+		return ts.ImportCards(ctx, &cards.ImportCardsRequest{Payload: &cards.ImportCardsPayload{
+			UserId: userID, DeckId: "shared", Format: "markdown", Content: []byte("- hola :: hello\n# Verbs\n- ir :: to go\n"),
+		}})
+	}
+
+	if _, err := importAs(viewerID); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("viewer importing returned %v, want PermissionDenied", err)
+	}
+
+	// sections stay in the shared deck, editors don't create decks in the owner's tree
+	res, err := importAs(editorID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := res.GetJob().GetReport()
+	if report.GetImported() != 2 || len(report.GetDeckIds()) != 0 || len(report.GetWarnings()) != 1 {
+		t.Errorf("report %+v, want 2 cards in the shared deck and a warning", report)
+	}
+	deckCards, err := ts.cards.ListByDeck(ctx, "shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deckCards) != 2 {
+		t.Errorf("%d cards in the shared deck, want 2", len(deckCards))
+	}
+	decks, _, err := ts.decks.ListByOwner(ctx, testUserID, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decks) != 1 {
+		t.Errorf("%d decks of the owner, want the shared deck alone", len(decks))
+	}
+}
@@ -0,0 +1,380 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/repositories"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/scheduler"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"github.com/Salladin95/card-quizzler-microservices/consumer"
+	"time"
+)
+
+const (
+	// matureInterval is the interval in days from which a card counts as mature
+	matureInterval = 21
+	// defaultStatsDays, defaultHeatmapDays and defaultForecastDays are used when a request does not set the range
+	defaultStatsDays    = 30
+	defaultHeatmapDays  = 365
+	defaultForecastDays = 30
+	// maxStatsDays bounds the range of a stats request
+	maxStatsDays = 731
+	// maxAnswerTime caps a single answer, an app left open doesn't skew the average
+	maxAnswerTime = time.Minute
+)
+
+// StatsEventKeys are the events building the stats rollups and streaks
+var StatsEventKeys = []string{events.CardReviewedKey, events.ProfileUpdatedKey}
+
+// statsRange is the days a stats request covers, from midnight of the first to midnight after the last
+type statsRange struct {
+	from     time.Time
+	end      time.Time
+	location *time.Location
+}
+
+// GetStudyStats sums the reviews of the user by day, in a deck or in all of them
+func (cs *CardsServer) GetStudyStats(ctx context.Context, req *cards.StudyStatsRequest) (*cards.StudyStatsResponse, error) {
+	dto := toStudyStatsDto(req.GetPayload())
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+	timezone, err := cs.userTimezone(ctx, dto.UserID, dto.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	days, err := cs.parseStatsRange(dto.From, dto.To, timezone, defaultStatsDays)
+	if err != nil {
+		return nil, err
+	}
+	decks, err := cs.statsDecks(ctx, dto.UserID, dto.DeckID)
+	if err != nil {
+		return nil, err
+	}
+
+	daily, err := cs.stats.Daily(ctx, days.query(dto.UserID, dto.DeckID))
+	if err != nil {
+		return nil, operationFailure("get daily stats", err)
+	}
+	res := &cards.StudyStatsResponse{
+		From:      days.from.Format(time.DateOnly),
+		To:        days.last().Format(time.DateOnly),
+		Timezone:  days.location.String(),
+		Days:      make([]*cards.DailyStats, 0, len(daily)),
+		StudyDays: int32(len(daily)),
+	}
+	var totals models.ReviewTotals
+	for _, day := range daily {
+		totals.Add(day.ReviewTotals)
+		res.Days = append(res.Days, &cards.DailyStats{
+			Date:            day.Date.Format(time.DateOnly),
+			Reviews:         int32(day.Reviews),
+			NewCards:        int32(day.NewCards),
+			Retention:       retention(day.ReviewTotals),
+			AverageAnswerMs: averageAnswerMs(day.ReviewTotals),
+		})
+	}
+	res.Reviews = int32(totals.Reviews)
+	res.NewCards = int32(totals.NewCards)
+	res.Retention = retention(totals)
+	res.AverageAnswerMs = averageAnswerMs(totals)
+
+	res.Cards, err = cs.countCards(ctx, dto.UserID, decks)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetReviewHeatmap returns the number of reviews of every day of the range for a calendar heatmap
+func (cs *CardsServer) GetReviewHeatmap(ctx context.Context, req *cards.StudyStatsRequest) (*cards.ReviewHeatmapResponse, error) {
+	dto := toStudyStatsDto(req.GetPayload())
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+	timezone, err := cs.userTimezone(ctx, dto.UserID, dto.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	days, err := cs.parseStatsRange(dto.From, dto.To, timezone, defaultHeatmapDays)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := cs.statsDecks(ctx, dto.UserID, dto.DeckID); err != nil {
+		return nil, err
+	}
+
+	daily, err := cs.stats.Daily(ctx, days.query(dto.UserID, dto.DeckID))
+	if err != nil {
+		return nil, operationFailure("get daily stats", err)
+	}
+	reviews := make(map[string]int, len(daily))
+	maxReviews := 0
+	for _, day := range daily {
+		reviews[day.Date.Format(time.DateOnly)] = day.Reviews
+		maxReviews = max(maxReviews, day.Reviews)
+	}
+
+	res := &cards.ReviewHeatmapResponse{
+		From:       days.from.Format(time.DateOnly),
+		To:         days.last().Format(time.DateOnly),
+		Timezone:   days.location.String(),
+		MaxReviews: int32(maxReviews),
+		StudyDays:  int32(len(daily)),
+	}
+	for date := days.from; date.Before(days.end); date = date.AddDate(0, 0, 1) {
+		day := &cards.ReviewHeatmapDay{Date: date.Format(time.DateOnly), Reviews: int32(reviews[date.Format(time.DateOnly)])}
+		if day.Reviews > 0 {
+			// quarters of the busiest day, rounded up so any review shows
+			day.Level = (4*day.Reviews + int32(maxReviews) - 1) / int32(maxReviews)
+		}
+		res.Days = append(res.Days, day)
+	}
+	return res, nil
+}
+
+// GetReviewForecast counts the cards falling due in the coming days
+func (cs *CardsServer) GetReviewForecast(ctx context.Context, req *cards.ReviewForecastRequest) (*cards.ReviewForecastResponse, error) {
+	payload := req.GetPayload()
+	dto := reviewForecastDto{
+		UserID:   payload.GetUserId(),
+		DeckID:   payload.GetDeckId(),
+		Days:     int(payload.GetDays()),
+		Timezone: payload.GetTimezone(),
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+	if dto.Days == 0 {
+		dto.Days = defaultForecastDays
+	}
+	timezone, err := cs.userTimezone(ctx, dto.UserID, dto.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, validationFailure(err)
+	}
+	decks, err := cs.statsDecks(ctx, dto.UserID, dto.DeckID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := midnight(cs.now(), location)
+	res := &cards.ReviewForecastResponse{Timezone: location.String(), Days: make([]*cards.ForecastDay, 0, dto.Days)}
+	dayIndex := make(map[string]int, dto.Days)
+	for date := today; len(res.Days) < dto.Days; date = date.AddDate(0, 0, 1) {
+		dayIndex[date.Format(time.DateOnly)] = len(res.Days)
+		res.Days = append(res.Days, &cards.ForecastDay{Date: date.Format(time.DateOnly)})
+	}
+	for _, deck := range decks {
+		states, err := cs.reviews.ListStates(ctx, dto.UserID, deck.ID)
+		if err != nil {
+			return nil, operationFailure("list review states", err)
+		}
+		for _, state := range states {
+			if state.Due.Before(today) {
+				res.Overdue++
+				continue
+			}
+			if i, ok := dayIndex[state.Due.In(location).Format(time.DateOnly)]; ok {
+				res.Days[i].Due++
+			}
+		}
+	}
+	return res, nil
+}
+
+// HandleStatsEvent adds a review to the stats rollups and streaks or keeps the timezone of a profile,
+// returned errors make the consumer retry it
+func (cs *CardsServer) HandleStatsEvent(ctx context.Context, key string, payload []byte) error {
+	switch key {
+	case events.CardReviewedKey:
+		var event events.CardReviewed
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return consumer.Permanent(err)
+		}
+		return cs.recordReview(ctx, event)
+	case events.ProfileUpdatedKey:
+		var event events.ProfileUpdated
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return consumer.Permanent(err)
+		}
+		if _, err := time.LoadLocation(event.Timezone); event.Timezone == "" || err != nil {
+			return consumer.Permanent(fmt.Errorf("profile of %s with an invalid timezone %q", event.UserID, event.Timezone))
+		}
+		return cs.saveTimezone(ctx, event)
+	default:
+		return consumer.Permanent(fmt.Errorf("unexpected event %s", key))
+	}
+}
+
+// recordReview adds the review to its rollup and extends the streak when the review completes the daily goal
+func (cs *CardsServer) recordReview(ctx context.Context, event events.CardReviewed) error {
+	if event.ReviewID == "" {
+		return consumer.Permanent(errors.New("review event without a review id"))
+	}
+
+	rollup := models.StatsRollup{
+		UserID:       event.UserID,
+		DeckID:       event.DeckID,
+		Start:        time.Unix(event.ReviewedAt, 0).UTC().Truncate(models.StatsRollupPeriod),
+		ReviewTotals: models.ReviewTotals{Reviews: 1},
+	}
+	if event.New {
+		rollup.NewCards = 1
+	} else if scheduler.Grade(event.Grade) != scheduler.Again {
+		rollup.Recalled = 1
+	}
+	if event.AnswerMs > 0 {
+		rollup.Timed = 1
+		rollup.AnswerTime = min(time.Duration(event.AnswerMs)*time.Millisecond, maxAnswerTime)
+	}
+	if err := cs.stats.Record(ctx, event.ReviewID, rollup); err != nil {
+		return err
+	}
+	// the streak is updated after recording, a redelivered event finds the day already counted
+	return cs.updateStreak(ctx, event.UserID, time.Unix(event.ReviewedAt, 0))
+}
+
+// userTimezone returns the requested timezone or the timezone of the user's profile when none is requested
+func (cs *CardsServer) userTimezone(ctx context.Context, userID, timezone string) (string, error) {
+	if timezone != "" {
+		return timezone, nil
+	}
+	settings, err := cs.studySettings(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return settings.Timezone, nil
+}
+
+// parseStatsRange resolves the dates of a request in its timezone, the range ends today when it's not set
+func (cs *CardsServer) parseStatsRange(from, to, timezone string, defaultDays int) (statsRange, error) {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return statsRange{}, validationFailure(err)
+	}
+	last := midnight(cs.now(), location)
+	if to != "" {
+		if last, err = time.ParseInLocation(time.DateOnly, to, location); err != nil {
+			return statsRange{}, validationFailure(err)
+		}
+	}
+	first := last.AddDate(0, 0, 1-defaultDays)
+	if from != "" {
+		if first, err = time.ParseInLocation(time.DateOnly, from, location); err != nil {
+			return statsRange{}, validationFailure(err)
+		}
+	}
+	end := last.AddDate(0, 0, 1)
+	if !first.Before(end) || end.After(first.AddDate(0, 0, maxStatsDays)) {
+		return statsRange{}, errStatsRange
+	}
+	return statsRange{from: first, end: end, location: location}, nil
+}
+
+// last returns midnight of the last day of the range
+func (r statsRange) last() time.Time {
+	return r.end.AddDate(0, 0, -1)
+}
+
+func (r statsRange) query(userID, deckID string) repositories.StatsQuery {
+	return repositories.StatsQuery{UserID: userID, DeckID: deckID, From: r.from, To: r.end, Location: r.location}
+}
+
+// statsDecks returns the deck the stats are asked for, or every deck the user owns or is a member of
+func (cs *CardsServer) statsDecks(ctx context.Context, userID, deckID string) ([]models.Deck, error) {
+	if deckID != "" {
+		deck, _, err := cs.getDeckAs(ctx, userID, deckID, models.RoleViewer)
+		if err != nil {
+			return nil, err
+		}
+		return []models.Deck{deck}, nil
+	}
+
+	decks, _, err := cs.decks.ListByOwner(ctx, userID, 0, 0)
+	if err != nil {
+		return nil, operationFailure("list decks", err)
+	}
+	memberships, err := cs.sharing.ListMemberships(ctx, userID)
+	if err != nil {
+		return nil, operationFailure("list memberships", err)
+	}
+	for _, membership := range memberships {
+		deck, err := cs.decks.Get(ctx, membership.DeckID)
+		if err != nil {
+			// the deck was deleted after the memberships were read
+			if errors.Is(err, repositories.ErrNotFound) {
+				continue
+			}
+			return nil, operationFailure("get deck", err)
+		}
+		decks = append(decks, deck)
+	}
+	return decks, nil
+}
+
+// countCards splits the cards of the decks into new, young and mature ones for the user
+func (cs *CardsServer) countCards(ctx context.Context, userID string, decks []models.Deck) (*cards.CardCounts, error) {
+	counts := &cards.CardCounts{}
+	for _, deck := range decks {
+		deckCards, err := cs.cards.ListByDeck(ctx, deck.ID)
+		if err != nil {
+			return nil, operationFailure("list cards", err)
+		}
+		states, err := cs.reviews.ListStates(ctx, userID, deck.ID)
+		if err != nil {
+			return nil, operationFailure("list review states", err)
+		}
+		for _, card := range deckCards {
+			state, studied := states[card.ID]
+			switch {
+			case !studied:
+				counts.New++
+			case state.IntervalDays >= matureInterval:
+				counts.Mature++
+			default:
+				counts.Young++
+			}
+		}
+	}
+	return counts, nil
+}
+
+// midnight returns the start of the day of the moment in the location
+func midnight(t time.Time, location *time.Location) time.Time {
+	local := t.In(location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+}
+
+// retention is the share of the reviews of studied cards that weren't graded again
+func retention(totals models.ReviewTotals) float64 {
+	studied := totals.Reviews - totals.NewCards
+	if studied == 0 {
+		return 0
+	}
+	return float64(totals.Recalled) / float64(studied)
+}
+
+func averageAnswerMs(totals models.ReviewTotals) int32 {
+	if totals.Timed == 0 {
+		return 0
+	}
+	return int32(totals.AnswerTime.Milliseconds() / int64(totals.Timed))
+}
+
+func toStudyStatsDto(payload *cards.StudyStatsPayload) studyStatsDto {
+	return studyStatsDto{
+		UserID:   payload.GetUserId(),
+		DeckID:   payload.GetDeckId(),
+		From:     payload.GetFrom(),
+		To:       payload.GetTo(),
+		Timezone: payload.GetTimezone(),
+	}
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"slices"
+	"testing"
+	"time"
+)
+
+// deliverStatsEvents hands the published review events to the stats consumer, twice like a redelivering queue
+func (ts *testServer) deliverStatsEvents(t *testing.T) {
+	t.Helper()
+	ts.deliverEvents(t, StatsEventKeys, ts.HandleStatsEvent)
+}
+
+// deliverEvents hands the published events with the keys to a consumer handler, twice like a redelivering queue,
+// and drops all published events
+func (ts *testServer) deliverEvents(t *testing.T, keys []string, handle func(context.Context, string, []byte) error) {
+	t.Helper()
+	ts.publisher.mu.Lock()
+	published := ts.publisher.events
+	ts.publisher.events = nil
+	ts.publisher.mu.Unlock()
+	for _, event := range append(published, published...) {
+		if !slices.Contains(keys, event.key) {
+			continue
+		}
+		payload, err := json.Marshal(event.payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := handle(context.Background(), event.key, payload); err != nil {
+			t.Fatalf("handle %s: %v", event.key, err)
+		}
+	}
+}
+
+func timedReview(t *testing.T, ts *testServer, deckID, cardID string, grade, answerMs int32) {
+	t.Helper()
+	if _, err := ts.SubmitReview(context.Background(), &cards.SubmitReviewRequest{Payload: &cards.SubmitReviewPayload{
+		UserId: testUserID, DeckId: deckID, CardId: cardID, Grade: grade, AnswerMs: answerMs,
+	}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStudyStats(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	_, deckCards := ts.seedDeck(t, "spanish", 3)
+
+	// one review in the morning of March 1st, three after midnight UTC, still the evening of March 1st in New York
+	timedReview(t, ts, "spanish", deckCards[0].ID, 3, 4000)
+	ts.clock.Advance(15*time.Hour + 30*time.Minute)
+	timedReview(t, ts, "spanish", deckCards[1].ID, 4, 70000)
+	timedReview(t, ts, "spanish", deckCards[0].ID, 1, 0)
+	timedReview(t, ts, "spanish", deckCards[0].ID, 3, 2000)
+	ts.deliverStatsEvents(t)
+
+	stats := func(timezone string) *cards.StudyStatsResponse {
+		t.Helper()
+		res, err := ts.GetStudyStats(ctx, &cards.StudyStatsRequest{Payload: &cards.StudyStatsPayload{
+			UserId: testUserID, DeckId: "spanish", From: "2024-03-01", To: "2024-03-02", Timezone: timezone,
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	tests := []struct {
+		timezone string
+		want     []*cards.DailyStats
+	}{
+		// answers longer than a minute count as a minute
+		{"", []*cards.DailyStats{
+			{Date: "2024-03-01", Reviews: 1, NewCards: 1, AverageAnswerMs: 4000},
+			{Date: "2024-03-02", Reviews: 3, NewCards: 1, Retention: 0.5, AverageAnswerMs: 31000},
+		}},
+		{"America/New_York", []*cards.DailyStats{
+			{Date: "2024-03-01", Reviews: 4, NewCards: 2, Retention: 0.5, AverageAnswerMs: 22000},
+		}},
+	}
+	for _, tt := range tests {
+		res := stats(tt.timezone)
+		if len(res.GetDays()) != len(tt.want) {
+			t.Errorf("stats in %q have days %v", tt.timezone, res.GetDays())
+			continue
+		}
+		for i, day := range res.GetDays() {
+			want := tt.want[i]
+			if day.GetDate() != want.GetDate() || day.GetReviews() != want.GetReviews() || day.GetNewCards() != want.GetNewCards() ||
+				day.GetRetention() != want.GetRetention() || day.GetAverageAnswerMs() != want.GetAverageAnswerMs() {
+				t.Errorf("stats in %q of %s are %v, want %v", tt.timezone, want.GetDate(), day, want)
+			}
+		}
+	}
+
+	res := stats("")
+	if res.GetReviews() != 4 || res.GetRetention() != 0.5 || res.GetStudyDays() != 2 {
+		t.Errorf("totals %v", res)
+	}
+	if cardCounts := res.GetCards(); cardCounts.GetNew() != 1 || cardCounts.GetYoung() != 2 || cardCounts.GetMature() != 0 {
+		t.Errorf("card counts %v", cardCounts)
+	}
+
+	heatmap, err := ts.GetReviewHeatmap(ctx, &cards.StudyStatsRequest{Payload: &cards.StudyStatsPayload{
+		UserId: testUserID, From: "2024-03-01", To: "2024-03-03",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var levels []int32
+	for _, day := range heatmap.GetDays() {
+		levels = append(levels, day.GetLevel())
+	}
+	if !slices.Equal(levels, []int32{2, 4, 0}) || heatmap.GetMaxReviews() != 3 {
+		t.Errorf("heatmap %v", heatmap)
+	}
+
+	_, err = ts.GetStudyStats(ctx, &cards.StudyStatsRequest{Payload: &cards.StudyStatsPayload{
+		UserId: testUserID, From: "2024-03-02", To: "2024-03-01",
+	}})
+	if err != errStatsRange {
+		t.Errorf("reversed range returned %v", err)
+	}
+}
+
+func TestReviewForecast(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	ctx := context.Background()
+	_, deckCards := ts.seedDeck(t, "spanish", 3)
+	states := []models.ReviewState{
+		{CardID: deckCards[0].ID, IntervalDays: 30, Due: time.Date(2024, time.March, 4, 3, 0, 0, 0, time.UTC)},
+		{CardID: deckCards[1].ID, IntervalDays: 2, Due: time.Date(2024, time.February, 28, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, state := range states {
+		state.UserID = testUserID
+		state.DeckID = "spanish"
+		if err := ts.reviews.SaveState(ctx, state); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// due at 22:00 on March 3rd in New York
+	res, err := ts.GetReviewForecast(ctx, &cards.ReviewForecastRequest{Payload: &cards.ReviewForecastPayload{
+		UserId: testUserID, Days: 3, Timezone: "America/New_York",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var due []int32
+	for _, day := range res.GetDays() {
+		due = append(due, day.GetDue())
+	}
+	if res.GetOverdue() != 1 || !slices.Equal(due, []int32{0, 0, 1}) || res.GetDays()[0].GetDate() != "2024-03-01" {
+		t.Errorf("forecast %v", res)
+	}
+}
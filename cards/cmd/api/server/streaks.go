@@ -0,0 +1,257 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/repositories"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"slices"
+	"time"
+)
+
+const (
+	// defaultRolloverHour starts study days at 4 am, so late night reviews count for the evening
+	defaultRolloverHour = 4
+	defaultGoalTarget   = 20
+	defaultTimezone     = "UTC"
+	// yearlyMilestone repeats after the listed milestones
+	yearlyMilestone = 365
+)
+
+// streakMilestones are the streak lengths announced with an event
+var streakMilestones = []int{3, 7, 14, 30, 50, 100, 200}
+
+// GetStreak returns the streak of the user with the progress towards today's goal
+func (cs *CardsServer) GetStreak(ctx context.Context, req *cards.StreakRequest) (*cards.StreakResponse, error) {
+	dto := userDto{UserID: req.GetPayload().GetUserId()}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	settings, err := cs.studySettings(ctx, dto.UserID)
+	if err != nil {
+		return nil, err
+	}
+	day := studyDayOf(cs.now(), settings)
+	progress, err := cs.goalProgress(ctx, dto.UserID, day)
+	if err != nil {
+		return nil, err
+	}
+	streak, err := cs.streaks.GetStreak(ctx, dto.UserID)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return nil, operationFailure("get streak", err)
+	}
+
+	res := &cards.StreakResponse{
+		Streak: &cards.Streak{
+			Current:     int32(streak.Current),
+			Longest:     int32(streak.Longest),
+			Freezes:     int32(streak.Freezes),
+			FreezesUsed: int32(streak.FreezesUsed),
+		},
+		Today: &cards.GoalProgress{
+			Date:    day.date.Format(time.DateOnly),
+			Cards:   int32(progress.Reviews),
+			Minutes: progress.AnswerTime.Minutes(),
+			Reached: goalReached(progress, settings),
+		},
+		Settings: toProtoStudySettings(settings),
+	}
+	if streak.Current > 0 {
+		res.Streak.LastDay = streak.LastDay.Format(time.DateOnly)
+		if missed := daysBetween(streak.LastDay, day.date) - 1; missed > streak.Freezes {
+			res.Streak.Current = 0
+		} else if missed >= 0 && !res.Today.Reached {
+			// missing today too would need one more freeze than the user has
+			res.Streak.AtRisk = missed+1 > streak.Freezes
+		}
+	}
+	return res, nil
+}
+
+// UpdateStudySettings changes the daily goal and the hour study days start at, the timezone comes from the profile
+func (cs *CardsServer) UpdateStudySettings(ctx context.Context, req *cards.UpdateStudySettingsRequest) (*cards.StudySettingsResponse, error) {
+	payload := req.GetPayload()
+	dto := updateStudySettingsDto{
+		UserID:       payload.GetUserId(),
+		RolloverHour: int(payload.GetRolloverHour()),
+		GoalKind:     payload.GetGoalKind(),
+		GoalTarget:   int(payload.GetGoalTarget()),
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+
+	settings, err := cs.studySettings(ctx, dto.UserID)
+	if err != nil {
+		return nil, err
+	}
+	settings.RolloverHour = dto.RolloverHour
+	settings.GoalKind = dto.GoalKind
+	settings.GoalTarget = dto.GoalTarget
+	settings.UpdatedAt = cs.now()
+	if err := cs.streaks.SaveSettings(ctx, settings); err != nil {
+		return nil, operationFailure("save study settings", err)
+	}
+	return &cards.StudySettingsResponse{Settings: toProtoStudySettings(settings)}, nil
+}
+
+// studyDay is a day of a user, it starts at the rollover hour in their timezone
+type studyDay struct {
+	// date is the calendar date of the day at midnight UTC, so days are compared and counted as UTC dates
+	date     time.Time
+	start    time.Time
+	end      time.Time
+	location *time.Location
+}
+
+// studyDayOf returns the study day of the moment, before the rollover hour it's the day before
+func studyDayOf(t time.Time, settings models.StudySettings) studyDay {
+	location, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	local := t.In(location)
+	year, month, dayOfMonth := local.Date()
+	if local.Hour() < settings.RolloverHour {
+		dayOfMonth--
+	}
+	return studyDay{
+		date:     time.Date(year, month, dayOfMonth, 0, 0, 0, 0, time.UTC),
+		start:    time.Date(year, month, dayOfMonth, settings.RolloverHour, 0, 0, 0, location),
+		end:      time.Date(year, month, dayOfMonth+1, settings.RolloverHour, 0, 0, 0, location),
+		location: location,
+	}
+}
+
+// updateStreak extends the streak of the user when a review completes the goal of its study day.
+// Days already counted are skipped, so redelivered and late events change nothing.
+func (cs *CardsServer) updateStreak(ctx context.Context, userID string, reviewedAt time.Time) error {
+	settings, err := cs.studySettings(ctx, userID)
+	if err != nil {
+		return err
+	}
+	day := studyDayOf(reviewedAt, settings)
+	streak, err := cs.streaks.GetStreak(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, repositories.ErrNotFound) {
+			return err
+		}
+		streak = models.Streak{UserID: userID}
+	}
+	if !streak.LastDay.Before(day.date) {
+		return nil
+	}
+	progress, err := cs.goalProgress(ctx, userID, day)
+	if err != nil {
+		return err
+	}
+	if !goalReached(progress, settings) {
+		return nil
+	}
+
+	missed := daysBetween(streak.LastDay, day.date) - 1
+	if streak.Current > 0 && missed <= streak.Freezes {
+		streak.Freezes -= missed
+		streak.FreezesUsed += missed
+		streak.Current++
+	} else {
+		streak.Current = 1
+	}
+	streak.Longest = max(streak.Longest, streak.Current)
+	streak.LastDay = day.date
+	if streak.Current%cs.config.STREAK_FREEZE_DAYS == 0 && streak.Freezes < cs.config.MAX_STREAK_FREEZES {
+		streak.Freezes++
+	}
+	streak.UpdatedAt = cs.now()
+	// a conflict with another instance makes the consumer retry the event
+	if err := cs.streaks.SaveStreak(ctx, &streak); err != nil {
+		return err
+	}
+
+	if isStreakMilestone(streak.Current) {
+		cs.publish(ctx, events.StreakMilestoneKey, events.StreakMilestone{
+			UserID:  userID,
+			Days:    streak.Current,
+			Longest: streak.Longest,
+			Day:     day.date.Format(time.DateOnly),
+		})
+	}
+	return nil
+}
+
+// saveTimezone copies the timezone of an auth profile into the study settings
+func (cs *CardsServer) saveTimezone(ctx context.Context, event events.ProfileUpdated) error {
+	settings := defaultStudySettings(event.UserID)
+	settings.Timezone = event.Timezone
+	settings.TimezoneUpdatedAt = time.UnixMilli(event.UpdatedAt)
+	settings.UpdatedAt = cs.now()
+	return cs.streaks.SaveTimezone(ctx, settings)
+}
+
+// studySettings returns the settings of the user, the defaults until they change any
+func (cs *CardsServer) studySettings(ctx context.Context, userID string) (models.StudySettings, error) {
+	settings, err := cs.streaks.GetSettings(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return defaultStudySettings(userID), nil
+		}
+		return models.StudySettings{}, operationFailure("get study settings", err)
+	}
+	return settings, nil
+}
+
+// goalProgress sums the reviews of the study day from the stats rollups
+func (cs *CardsServer) goalProgress(ctx context.Context, userID string, day studyDay) (models.ReviewTotals, error) {
+	daily, err := cs.stats.Daily(ctx, repositories.StatsQuery{
+		UserID:   userID,
+		From:     day.start,
+		To:       day.end,
+		Location: day.location,
+	})
+	if err != nil {
+		return models.ReviewTotals{}, operationFailure("get daily stats", err)
+	}
+	var totals models.ReviewTotals
+	for _, stats := range daily {
+		totals.Add(stats.ReviewTotals)
+	}
+	return totals, nil
+}
+
+func goalReached(progress models.ReviewTotals, settings models.StudySettings) bool {
+	if settings.GoalKind == models.GoalMinutes {
+		return progress.AnswerTime >= time.Duration(settings.GoalTarget)*time.Minute
+	}
+	return progress.Reviews >= settings.GoalTarget
+}
+
+func isStreakMilestone(days int) bool {
+	return slices.Contains(streakMilestones, days) || (days > 0 && days%yearlyMilestone == 0)
+}
+
+// daysBetween counts the days between two dates at midnight UTC
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+func defaultStudySettings(userID string) models.StudySettings {
+	return models.StudySettings{
+		UserID:       userID,
+		Timezone:     defaultTimezone,
+		RolloverHour: defaultRolloverHour,
+		GoalKind:     models.GoalCards,
+		GoalTarget:   defaultGoalTarget,
+	}
+}
+
+func toProtoStudySettings(settings models.StudySettings) *cards.StudySettings {
+	return &cards.StudySettings{
+		Timezone:     settings.Timezone,
+		RolloverHour: int32(settings.RolloverHour),
+		GoalKind:     settings.GoalKind,
+		GoalTarget:   int32(settings.GoalTarget),
+	}
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"github.com/Salladin95/card-quizzler-microservices/consumer"
+	"testing"
+	"time"
+)
+
+// updateProfile delivers a profile event of the auth service to the stats consumer
+func (ts *testServer) updateProfile(t *testing.T, timezone string, updatedAt time.Time) error {
+	t.Helper()
+	payload, err := json.Marshal(events.ProfileUpdated{UserID: testUserID, Timezone: timezone, UpdatedAt: updatedAt.UnixMilli()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ts.HandleStatsEvent(context.Background(), events.ProfileUpdatedKey, payload)
+}
+
+func TestStreak(t *testing.T) {
+	cfg := testConfig()
+	cfg.STREAK_FREEZE_DAYS = 2
+	ts := newTestServer(t, cfg)
+	ctx := context.Background()
+	_, deckCards := ts.seedDeck(t, "spanish", 10)
+
+	if err := ts.updateProfile(t, "America/New_York", ts.clock.Now()); err != nil {
+		t.Fatal(err)
+	}
+	// an older profile event arriving late keeps the newer timezone
+	if err := ts.updateProfile(t, "Europe/Berlin", ts.clock.Now().Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.updateProfile(t, "Mars/Olympus", ts.clock.Now()); !consumer.IsPermanent(err) {
+		t.Fatalf("invalid timezone: got %v, want a permanent error", err)
+	}
+	if _, err := ts.UpdateStudySettings(ctx, &cards.UpdateStudySettingsRequest{Payload: &cards.UpdateStudySettingsPayload{
+		UserId: testUserID, RolloverHour: 4, GoalKind: "cards", GoalTarget: 2,
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	next := 0
+	review := func(count int) {
+		t.Helper()
+		for i := 0; i < count; i++ {
+			timedReview(t, ts, "spanish", deckCards[next%len(deckCards)].ID, 3, 5000)
+			next++
+		}
+		ts.deliverStatsEvents(t)
+	}
+	streak := func() *cards.StreakResponse {
+		t.Helper()
+		res, err := ts.GetStreak(ctx, &cards.StreakRequest{Payload: &cards.StreakPayload{UserId: testUserID}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	check := func(step string, res *cards.StreakResponse, today string, current, freezes int32, atRisk bool) {
+		t.Helper()
+		if res.Today.Date != today || res.Streak.Current != current || res.Streak.Freezes != freezes || res.Streak.AtRisk != atRisk {
+			t.Errorf("%s: got today %s, current %d, freezes %d, at risk %v, want %s, %d, %d, %v", step,
+				res.Today.Date, res.Streak.Current, res.Streak.Freezes, res.Streak.AtRisk, today, current, freezes, atRisk)
+		}
+	}
+
+	// 9 am UTC is 4 am in New York, the first hour of March 1st
+	res := streak()
+	if res.Settings.Timezone != "America/New_York" || res.Today.Reached {
+		t.Fatalf("got timezone %s, reached %v, want America/New_York before reaching the goal", res.Settings.Timezone, res.Today.Reached)
+	}
+	review(2)
+	check("goal reached", streak(), "2024-03-01", 1, 0, false)
+
+	// midnight in New York is still March 1st before the rollover hour
+	ts.clock.Advance(20 * time.Hour)
+	review(1)
+	res = streak()
+	check("before rollover", res, "2024-03-01", 1, 0, false)
+	if res.Today.Cards != 3 {
+		t.Errorf("before rollover: got %d cards today, want 3", res.Today.Cards)
+	}
+
+	// the second day earns a freeze
+	ts.clock.Advance(5 * time.Hour)
+	check("new day", streak(), "2024-03-02", 1, 0, true)
+	review(2)
+	check("second day", streak(), "2024-03-02", 2, 1, false)
+
+	// the freeze covers March 3rd
+	ts.clock.Advance(24 * time.Hour)
+	check("freeze left", streak(), "2024-03-03", 2, 1, false)
+	ts.clock.Advance(24 * time.Hour)
+	check("freeze needed", streak(), "2024-03-04", 2, 1, true)
+	review(2)
+	res = streak()
+	check("frozen day", res, "2024-03-04", 3, 0, false)
+	if res.Streak.FreezesUsed != 1 || res.Streak.Longest != 3 {
+		t.Errorf("frozen day: got %d freezes used, longest %d, want 1 and 3", res.Streak.FreezesUsed, res.Streak.Longest)
+	}
+
+	// the milestone is announced once although every event was delivered twice
+	var milestones []events.StreakMilestone
+	for _, event := range ts.publisher.events {
+		if event.key == events.StreakMilestoneKey {
+			milestones = append(milestones, event.payload.(events.StreakMilestone))
+		}
+	}
+	if len(milestones) != 1 || milestones[0].Days != 3 || milestones[0].Day != "2024-03-04" {
+		t.Errorf("got milestones %+v, want 3 days on 2024-03-04", milestones)
+	}
+
+	// missing two days without freezes breaks the streak
+	ts.clock.Advance(48 * time.Hour)
+	res = streak()
+	check("broken", res, "2024-03-06", 0, 0, false)
+	if res.Streak.Longest != 3 {
+		t.Errorf("broken: got longest %d, want 3", res.Streak.Longest)
+	}
+}
+
+func TestStudySettingsValidation(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	tests := []*cards.UpdateStudySettingsPayload{
+		{UserId: testUserID, RolloverHour: 24, GoalKind: "cards", GoalTarget: 10},
+		{UserId: testUserID, RolloverHour: 4, GoalKind: "hours", GoalTarget: 10},
+		{UserId: testUserID, RolloverHour: 4, GoalKind: "minutes", GoalTarget: 0},
+	}
+	for _, payload := range tests {
+		if _, err := ts.UpdateStudySettings(context.Background(), &cards.UpdateStudySettingsRequest{Payload: payload}); err == nil {
+			t.Errorf("%+v: got no error", payload)
+		}
+	}
+}
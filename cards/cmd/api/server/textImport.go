@@ -0,0 +1,202 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/events"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/grading"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/repositories"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/textimport"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+)
+
+const (
+	// previewLimit bounds the cards and warnings of a preview, the counts still cover the whole text
+	previewLimit = 200
+	// sectionSeparator joins the headings of a section in messages about it
+	sectionSeparator = " / "
+)
+
+// PreviewImport parses pasted text and validates its cards without storing anything
+func (cs *CardsServer) PreviewImport(ctx context.Context, req *cards.PreviewImportRequest) (*cards.PreviewImportResponse, error) {
+	payload := req.GetPayload()
+	dto := previewImportDto{
+		UserID:        payload.GetUserId(),
+		Format:        payload.GetFormat(),
+		Content:       payload.GetContent(),
+		TermSeparator: payload.GetTermSeparator(),
+		CardSeparator: payload.GetCardSeparator(),
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return nil, validationFailure(err)
+	}
+	if len(dto.Content) > cs.config.MAX_IMPORT_BYTES {
+		return nil, status.Errorf(codes.InvalidArgument, "text is longer than %d bytes", cs.config.MAX_IMPORT_BYTES)
+	}
+	result, err := textimport.Parse(dto.Content, textimport.Options{
+		Format:        dto.Format,
+		TermSeparator: dto.TermSeparator,
+		CardSeparator: dto.CardSeparator,
+	})
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	res := &cards.PreviewImportResponse{
+		Cards:    make([]*cards.PreviewCard, 0, min(len(result.Cards), previewLimit)),
+		Total:    int32(len(result.Cards)),
+		Warnings: make([]*cards.ImportWarning, 0, min(len(result.Warnings), previewLimit)),
+	}
+	for _, card := range result.Cards {
+		preview := &cards.PreviewCard{
+			Line:         int32(card.Line),
+			Front:        card.Front,
+			Back:         card.Back,
+			Alternatives: card.Alternatives,
+			Section:      card.Section,
+		}
+		// the deck is picked when importing
+		if err := cs.validate.StructExcept(textCardDto(dto.UserID, card), "DeckID"); err != nil {
+			preview.Error = validationMessage(err)
+		} else {
+			res.Valid++
+		}
+		if len(res.Cards) < previewLimit {
+			res.Cards = append(res.Cards, preview)
+		}
+	}
+	for _, warning := range result.Warnings[:min(len(result.Warnings), previewLimit)] {
+		res.Warnings = append(res.Warnings, &cards.ImportWarning{Line: int32(warning.Line), Message: warning.Message})
+	}
+	return res, nil
+}
+
+// importText creates the cards of a Quizlet export or a Markdown list, Markdown sections get decks of their own
+// in decks the user owns
+func (cs *CardsServer) importText(ctx context.Context, job *models.Job, deck models.Deck, report *models.JobReport) error {
+	result, err := textimport.Parse(string(job.Input), textOptions(job.Format, job.Options))
+	if err != nil {
+		return jobFailure{message: err.Error()}
+	}
+	for _, warning := range result.Warnings {
+		report.Warn(fmt.Sprintf("line %d: %s", warning.Line, warning.Message))
+	}
+
+	sections := make(map[string]sectionDeck)
+	// only the owner creates decks in the deck, editors of a shared deck get every card in the deck itself
+	nested := deck.OwnerID == job.UserID
+	flattened := false
+	for i, card := range result.Cards {
+		report.Rows++
+		target := deck
+		if len(card.Section) > 0 && !nested && !flattened {
+			report.Warn("sections become decks in your own decks only, their cards were imported into the shared deck")
+			flattened = true
+		}
+		if len(card.Section) > 0 && nested {
+			section, err := cs.findSectionDeck(ctx, job, deck, card.Section, sections, report)
+			if err != nil {
+				return err
+			}
+			if section.invalid != "" {
+				report.Fail(card.Line, section.invalid)
+				continue
+			}
+			target = section.deck
+		}
+		if err := cs.importCard(ctx, job, target, report, card.Line, i+1, textCardDto(job.UserID, card), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sectionDeck is the deck of a Markdown section, or why the section can't have one
+type sectionDeck struct {
+	deck    models.Deck
+	invalid string
+}
+
+// findSectionDeck returns the deck of a Markdown section, nested in the decks of the enclosing sections.
+// Decks of the section and its ancestors are created on first use and cached by their headings.
+func (cs *CardsServer) findSectionDeck(ctx context.Context, job *models.Job, root models.Deck, section []string,
+	sections map[string]sectionDeck, report *models.JobReport) (sectionDeck, error) {
+	key := strings.Join(section, "\n")
+	if cached, ok := sections[key]; ok {
+		return cached, nil
+	}
+
+	parent := sectionDeck{deck: root}
+	if len(section) > 1 {
+		var err error
+		if parent, err = cs.findSectionDeck(ctx, job, root, section[:len(section)-1], sections, report); err != nil {
+			return sectionDeck{}, err
+		}
+	}
+	result := parent
+	if parent.invalid == "" {
+		var err error
+		if result, err = cs.createSectionDeck(ctx, job, parent.deck, section, report); err != nil {
+			return sectionDeck{}, err
+		}
+	}
+	sections[key] = result
+	return result, nil
+}
+
+// createSectionDeck creates the deck of a Markdown section inside the deck of the enclosing section,
+// titled after its heading. Its id derives from the job, so a retried import finds the deck of the previous attempt.
+func (cs *CardsServer) createSectionDeck(ctx context.Context, job *models.Job, parent models.Deck, section []string,
+	report *models.JobReport) (sectionDeck, error) {
+	dto := createDeckDto{
+		UserID:   job.UserID,
+		Title:    section[len(section)-1],
+		ParentID: parent.ID,
+	}
+	if err := cs.validate.Struct(dto); err != nil {
+		return sectionDeck{invalid: fmt.Sprintf("section %q: %s", strings.Join(section, sectionSeparator), validationMessage(err))}, nil
+	}
+
+	deck := models.Deck{
+		ID:        uuid.NewSHA1(importNamespace, []byte(job.ID+":"+strings.Join(section, "\n"))).String(),
+		OwnerID:   job.UserID,
+		ParentID:  dto.ParentID,
+		Title:     dto.Title,
+		CreatedAt: job.CreatedAt,
+		UpdatedAt: job.CreatedAt,
+	}
+	if job.Options.DryRun {
+		return sectionDeck{deck: deck}, nil
+	}
+	switch err := cs.decks.Create(ctx, deck); {
+	case err == nil:
+		cs.publish(ctx, events.DeckCreatedKey, deckChanged(deck))
+	case errors.Is(err, repositories.ErrAlreadyExists):
+		// created by a previous attempt
+	default:
+		return sectionDeck{}, err
+	}
+	report.Decks = append(report.Decks, deck.ID)
+	return sectionDeck{deck: deck}, nil
+}
+
+func textCardDto(userID string, card textimport.Card) createCardDto {
+	return createCardDto{
+		UserID:       userID,
+		Front:        card.Front,
+		Back:         card.Back,
+		Alternatives: card.Alternatives,
+		Tags:         []string{},
+		AnswerType:   grading.AnswerText,
+	}
+}
+
+func textOptions(format string, options models.JobOptions) textimport.Options {
+	return textimport.Options{Format: format, TermSeparator: options.TermSeparator, CardSeparator: options.CardSeparator}
+}
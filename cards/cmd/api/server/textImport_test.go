@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	cards "github.com/Salladin95/card-quizzler-microservices/cards-service/proto"
+	"testing"
+)
+
+func TestMarkdownSectionsBecomeChildDecks(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	root, _ := ts.seedDeck(t, "deck", 0)
+	content := "- loose :: card\n# Verbs\n## Irregular\n- go :: went\n## Regular\n- walk :: walked\n# Nouns\n- cat :: Katze\n"
+	res, err := ts.ImportCards(context.Background(), &cards.ImportCardsRequest{Payload: &cards.ImportCardsPayload{
+		UserId: testUserID, DeckId: root.ID, Format: "markdown", Content: []byte(content),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := res.GetJob().GetReport()
+	if report.GetImported() != 4 || len(report.GetDeckIds()) != 4 {
+		t.Fatalf("report %+v, want 4 cards in 4 section decks", report)
+	}
+
+	decks, _, err := ts.decks.ListByOwner(context.Background(), testUserID, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byTitle := make(map[string]string)
+	parents := make(map[string]string)
+	for _, deck := range decks {
+		byTitle[deck.Title] = deck.ID
+		parents[deck.ID] = deck.ParentID
+	}
+	want := map[string]string{"Verbs": root.ID, "Irregular": byTitle["Verbs"], "Regular": byTitle["Verbs"], "Nouns": root.ID}
+	if len(decks) != len(want)+1 {
+		t.Errorf("%d decks, want %d", len(decks), len(want)+1)
+	}
+	for title, parentID := range want {
+		id, ok := byTitle[title]
+		if !ok {
+			t.Errorf("no deck titled %q", title)
+			continue
+		}
+		if parents[id] != parentID {
+			t.Errorf("deck %q nested in %q, want %q", title, parents[id], parentID)
+		}
+	}
+	for title, count := range map[string]int{"Verbs": 0, "Irregular": 1, "Regular": 1, "Nouns": 1, root.Title: 1} {
+		deckCards, err := ts.cards.ListByDeck(context.Background(), byTitle[title])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(deckCards) != count {
+			t.Errorf("deck %q has %d cards, want %d", title, len(deckCards), count)
+		}
+	}
+
+	// deleting a parent moves its children to the top level
+	if err := ts.decks.Delete(context.Background(), byTitle["Verbs"]); err != nil {
+		t.Fatal(err)
+	}
+	irregular, err := ts.decks.Get(context.Background(), byTitle["Irregular"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if irregular.ParentID != "" {
+		t.Errorf("child of a deleted deck nested in %q", irregular.ParentID)
+	}
+}
+
+func TestCreateDeckRejectsForeignParent(t *testing.T) {
+	ts := newTestServer(t, testConfig())
+	parent, _ := ts.seedDeck(t, "deck", 0)
+	if _, err := ts.CreateDeck(context.Background(), &cards.CreateDeckRequest{Payload: &cards.CreateDeckPayload{
+		UserId: "someone-else", Title: "Child", ParentId: parent.ID,
+	}}); err == nil {
+		t.Error("created a deck inside a deck of another user")
+	}
+	res, err := ts.CreateDeck(context.Background(), &cards.CreateDeckRequest{Payload: &cards.CreateDeckPayload{
+		UserId: testUserID, Title: "Child", ParentId: parent.ID,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.GetDeck().GetParentId() != parent.ID {
+		t.Errorf("deck nested in %q, want %q", res.GetDeck().GetParentId(), parent.ID)
+	}
+}
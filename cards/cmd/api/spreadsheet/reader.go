@@ -0,0 +1,133 @@
+package spreadsheet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// Row is a card read from a line of the file
+type Row struct {
+	Line         int
+	Front        string
+	Back         string
+	Hint         string
+	Tags         []string
+	Alternatives []string
+}
+
+// RowError reports a line that can't be read, reading may go on with the next line
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err.Error())
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Reader streams cards out of a file
+type Reader struct {
+	csv *csv.Reader
+	// columns maps fields to column indices
+	columns map[string]int
+}
+
+// NewReader reads the header, it fails when the file lacks a front or back column
+func NewReader(r io.Reader, opts Options) (*Reader, error) {
+	delimiter, err := opts.delimiter()
+	if err != nil {
+		return nil, err
+	}
+	for header, field := range opts.Mapping {
+		if !isField(field) {
+			return nil, fmt.Errorf("%w: header %q maps to unknown field %q", ErrInvalidOptions, header, field)
+		}
+	}
+
+	buffered := bufio.NewReader(r)
+	if prefix, err := buffered.Peek(len(utf8BOM)); err == nil && bytes.Equal(prefix, utf8BOM) {
+		buffered.Discard(len(utf8BOM))
+	}
+	csvReader := csv.NewReader(buffered)
+	csvReader.Comma = delimiter
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = delimiter != '\t'
+
+	reader := &Reader{csv: csvReader, columns: make(map[string]int)}
+	if opts.NoHeader {
+		for i, field := range Fields {
+			reader.columns[field] = i
+		}
+		return reader, nil
+	}
+
+	header, err := csvReader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("file is empty")
+		}
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	mapping := make(map[string]string, len(opts.Mapping))
+	for name, field := range opts.Mapping {
+		mapping[normalizeHeader(name)] = field
+	}
+	for i, name := range header {
+		field, ok := mapping[normalizeHeader(name)]
+		if !ok {
+			field, ok = headerAliases[normalizeHeader(name)]
+		}
+		if _, taken := reader.columns[field]; ok && !taken {
+			reader.columns[field] = i
+		}
+	}
+	for _, field := range []string{FieldFront, FieldBack} {
+		if _, ok := reader.columns[field]; !ok {
+			return nil, fmt.Errorf("no column holds the %s of cards, map one of the headers %q", field, header)
+		}
+	}
+	return reader, nil
+}
+
+// Next returns the next card, io.EOF once the file is over, or a *RowError for a malformed line
+func (r *Reader) Next() (Row, error) {
+	record, err := r.csv.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return Row{}, &RowError{Line: parseErr.StartLine, Err: parseErr.Err}
+		}
+		return Row{}, err
+	}
+	line, _ := r.csv.FieldPos(0)
+	cell := func(field string) string {
+		index, ok := r.columns[field]
+		if !ok || index >= len(record) {
+			return ""
+		}
+		return unquoteFormula(strings.TrimSpace(record[index]))
+	}
+	return Row{
+		Line:         line,
+		Front:        cell(FieldFront),
+		Back:         cell(FieldBack),
+		Hint:         cell(FieldHint),
+		Tags:         splitTags(cell(FieldTags)),
+		Alternatives: splitAlternatives(cell(FieldAlternatives)),
+	}, nil
+}
+
+func normalizeHeader(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
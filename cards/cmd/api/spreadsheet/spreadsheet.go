@@ -0,0 +1,184 @@
+// Package spreadsheet reads and writes cards as delimiter separated values, e.g. CSV and TSV files.
+//
+// A file has one card per line. Tags are separated by commas or semicolons inside their cell,
+// alternative answers by vertical bars. A backslash escapes a separator or another backslash,
+// so values holding separators survive an export and import.
+//
+// Cells starting with =, +, - or @ are written with a leading apostrophe, spreadsheet apps would
+// run them as formulas otherwise. The reader drops the apostrophe again.
+package spreadsheet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	FormatCSV = "csv"
+	FormatTSV = "tsv"
+
+	FieldFront        = "front"
+	FieldBack         = "back"
+	FieldHint         = "hint"
+	FieldTags         = "tags"
+	FieldAlternatives = "alternatives"
+
+	alternativesSeparator = "|"
+	tagSeparators         = ",;"
+
+	escapeChar = '\\'
+	// formulaQuote makes spreadsheet apps treat a cell as text
+	formulaQuote = '\''
+)
+
+// Fields are the card fields a column can hold, also the column order of files without a header
+var Fields = []string{FieldFront, FieldBack, FieldHint, FieldTags, FieldAlternatives}
+
+// headerAliases recognise the headers commonly used by spreadsheets and other apps
+var headerAliases = map[string]string{
+	"front":        FieldFront,
+	"term":         FieldFront,
+	"question":     FieldFront,
+	"word":         FieldFront,
+	"back":         FieldBack,
+	"definition":   FieldBack,
+	"answer":       FieldBack,
+	"meaning":      FieldBack,
+	"hint":         FieldHint,
+	"hints":        FieldHint,
+	"tag":          FieldTags,
+	"tags":         FieldTags,
+	"alternatives": FieldAlternatives,
+	"synonyms":     FieldAlternatives,
+}
+
+var ErrInvalidOptions = errors.New("invalid spreadsheet options")
+
+type Options struct {
+	// Format is csv or tsv, it picks the default delimiter
+	Format string
+	// Delimiter overrides the delimiter of the format
+	Delimiter rune
+	// NoHeader treats the first line as a card, columns then follow the order of Fields
+	NoHeader bool
+	// Mapping maps header names to fields, headers missing from it are recognised by their common names
+	Mapping map[string]string
+}
+
+func (o Options) delimiter() (rune, error) {
+	delimiter := o.Delimiter
+	if delimiter == 0 {
+		switch o.Format {
+		case FormatCSV, "":
+			delimiter = ','
+		case FormatTSV:
+			delimiter = '\t'
+		default:
+			return 0, fmt.Errorf("%w: unknown format %q", ErrInvalidOptions, o.Format)
+		}
+	}
+	if delimiter == '"' || delimiter == '\r' || delimiter == '\n' || delimiter == '�' {
+		return 0, fmt.Errorf("%w: %q can't be a delimiter", ErrInvalidOptions, delimiter)
+	}
+	return delimiter, nil
+}
+
+func isField(name string) bool {
+	for _, field := range Fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+func splitTags(cell string) []string {
+	return splitCell(cell, tagSeparators)
+}
+
+func splitAlternatives(cell string) []string {
+	return splitCell(cell, alternativesSeparator)
+}
+
+func joinTags(tags []string) string {
+	return joinCell(tags, tagSeparators, ", ")
+}
+
+func joinAlternatives(alternatives []string) string {
+	return joinCell(alternatives, alternativesSeparator, " "+alternativesSeparator+" ")
+}
+
+// splitCell splits the cell at any of the separators, a backslash escapes a separator or a backslash.
+// Other backslashes are kept, cells written by other apps rarely mean them as escapes.
+func splitCell(cell, separators string) []string {
+	values := []string{}
+	var current strings.Builder
+	flush := func() {
+		if value := strings.TrimSpace(current.String()); value != "" {
+			values = append(values, value)
+		}
+		current.Reset()
+	}
+	runes := []rune(cell)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == escapeChar && i+1 < len(runes) && (runes[i+1] == escapeChar || strings.ContainsRune(separators, runes[i+1])) {
+			current.WriteRune(runes[i+1])
+			i++
+			continue
+		}
+		if strings.ContainsRune(separators, r) {
+			flush()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	flush()
+	return values
+}
+
+// joinCell is the reverse of splitCell
+func joinCell(values []string, separators, glue string) string {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		var b strings.Builder
+		for _, r := range value {
+			if r == escapeChar || strings.ContainsRune(separators, r) {
+				b.WriteRune(escapeChar)
+			}
+			b.WriteRune(r)
+		}
+		escaped[i] = b.String()
+	}
+	return strings.Join(escaped, glue)
+}
+
+func startsFormula(value string) bool {
+	return value != "" && strings.ContainsRune("=+-@", rune(value[0]))
+}
+
+// quoteFormula prefixes cells spreadsheet apps would evaluate. Cells starting with the quote
+// itself are prefixed too, so unquoteFormula can tell them apart.
+func quoteFormula(value string) string {
+	if startsFormula(value) || (value != "" && value[0] == formulaQuote) {
+		return string(formulaQuote) + value
+	}
+	return value
+}
+
+// unquoteFormula drops the quote added by quoteFormula, a quote before anything else is part of the value
+func unquoteFormula(value string) string {
+	if len(value) > 1 && value[0] == formulaQuote && (startsFormula(value[1:]) || value[1] == formulaQuote) {
+		return value[1:]
+	}
+	return value
+}
+
+// ContentType is the media type of files in the format
+func ContentType(format string) string {
+	if format == FormatTSV {
+		return "text/tab-separated-values; charset=utf-8"
+	}
+	return "text/csv; charset=utf-8"
+}
@@ -0,0 +1,113 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"errors"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func readAll(t *testing.T, data string, opts Options) []Row {
+	t.Helper()
+	reader, err := NewReader(strings.NewReader(data), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rows []Row
+	for {
+		row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return rows
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows = append(rows, row)
+	}
+}
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	cards := []models.Card{
+		{
+			Front:        "=HYPERLINK(\"http://evil.example.com\")",
+			Back:         "-5",
+			Hint:         "@mention",
+			Tags:         []string{"math, algebra", "a;b", `back\slash`, "+plus"},
+			Alternatives: []string{"minus five", "x | y", `trailing\`},
+		},
+		{
+			Front: "'quoted",
+			Back:  "'=still text",
+			Hint:  "'til dawn",
+			Tags:  []string{"plain"},
+		},
+		{Front: "Столица России", Back: "Москва"},
+	}
+	for _, format := range []string{FormatCSV, FormatTSV} {
+		var buf bytes.Buffer
+		writer, err := NewWriter(&buf, Options{Format: format}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, card := range cards {
+			if err := writer.Write(card); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := writer.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		rows := readAll(t, buf.String(), Options{Format: format})
+		if len(rows) != len(cards) {
+			t.Fatalf("%s: read %d rows, want %d", format, len(rows), len(cards))
+		}
+		for i, row := range rows {
+			card := cards[i]
+			want := Row{Line: i + 2, Front: card.Front, Back: card.Back, Hint: card.Hint, Tags: card.Tags, Alternatives: card.Alternatives}
+			if want.Tags == nil {
+				want.Tags = []string{}
+			}
+			if want.Alternatives == nil {
+				want.Alternatives = []string{}
+			}
+			if !reflect.DeepEqual(row, want) {
+				t.Errorf("%s: row %d\n got %+v\nwant %+v", format, i, row, want)
+			}
+		}
+	}
+}
+
+func TestWriteQuotesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, Options{NoHeader: true}, []string{FieldFront, FieldBack, FieldHint, FieldTags})
+	if err != nil {
+		t.Fatal(err)
+	}
+	card := models.Card{Front: "=1+2", Back: "+7", Hint: "@SUM(A1)", Tags: []string{"-x", "y"}}
+	if err := writer.Write(card); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "'=1+2,'+7,'@SUM(A1),\"'-x, y\"\n"; buf.String() != want {
+		t.Errorf("written %q, want %q", buf.String(), want)
+	}
+}
+
+func TestReadKeepsForeignBackslashes(t *testing.T) {
+	rows := readAll(t, "front,back,tags,alternatives\n"+`Path,x,"C:\dir, a\;b","C:\dir | a\|b"`+"\n", Options{})
+	if len(rows) != 1 {
+		t.Fatalf("read %d rows", len(rows))
+	}
+	if want := []string{`C:\dir`, "a;b"}; !reflect.DeepEqual(rows[0].Tags, want) {
+		t.Errorf("tags %q, want %q", rows[0].Tags, want)
+	}
+	if want := []string{`C:\dir`, "a|b"}; !reflect.DeepEqual(rows[0].Alternatives, want) {
+		t.Errorf("alternatives %q, want %q", rows[0].Alternatives, want)
+	}
+}
@@ -0,0 +1,66 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/cards-service/cmd/api/models"
+	"io"
+)
+
+// Writer streams cards into a file
+type Writer struct {
+	csv     *csv.Writer
+	columns []string
+}
+
+// NewWriter writes the header unless opts.NoHeader is set, no columns means all Fields
+func NewWriter(w io.Writer, opts Options, columns []string) (*Writer, error) {
+	delimiter, err := opts.delimiter()
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		columns = Fields
+	}
+	for _, column := range columns {
+		if !isField(column) {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidOptions, column)
+		}
+	}
+
+	csvWriter := csv.NewWriter(w)
+	csvWriter.Comma = delimiter
+	writer := &Writer{csv: csvWriter, columns: columns}
+	if !opts.NoHeader {
+		if err := csvWriter.Write(columns); err != nil {
+			return nil, err
+		}
+	}
+	return writer, nil
+}
+
+func (w *Writer) Write(card models.Card) error {
+	record := make([]string, len(w.columns))
+	for i, column := range w.columns {
+		switch column {
+		case FieldFront:
+			record[i] = card.Front
+		case FieldBack:
+			record[i] = card.Back
+		case FieldHint:
+			record[i] = card.Hint
+		case FieldTags:
+			record[i] = joinTags(card.Tags)
+		case FieldAlternatives:
+			record[i] = joinAlternatives(card.Alternatives)
+		}
+		record[i] = quoteFormula(record[i])
+	}
+	return w.csv.Write(record)
+}
+
+// Flush writes buffered lines and reports any error of previous writes
+func (w *Writer) Flush() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
@@ -0,0 +1,155 @@
+package textimport
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*?)(?:\s+#+)?$`)
+	// listMarker is the bullet or number starting a list item
+	listMarker = regexp.MustCompile(`^(?:[-*+]|\d{1,9}[.)])\s+`)
+)
+
+// termSeparator splits "term :: definition" lines. The spaced form wins, and a line with only the bare
+// form is split when no ": definition" follows it, so terms like "std::vector" survive in definition lists.
+const termSeparator = "::"
+
+type heading struct {
+	level int
+	title string
+}
+
+// markdownParser keeps the state of the lines read so far
+type markdownParser struct {
+	result   Result
+	headings []heading
+	// term is a line waiting for the definitions that turn it into a card
+	term *Card
+	// card indexes the last card of a definition list, further definitions become its alternatives
+	card int
+	// continued is set while indented lines extend the last definition
+	continued bool
+}
+
+func parseMarkdown(text string) Result {
+	p := markdownParser{result: Result{Cards: []Card{}, Warnings: []Warning{}}, card: -1}
+	fence := ""
+	for i, line := range strings.Split(text, "\n") {
+		number := i + 1
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+
+		switch heading := headingPattern.FindStringSubmatch(trimmed); {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			p.flush()
+			fence = trimmed[:3]
+			p.result.warn(number, "code blocks are not cards")
+		case trimmed == "":
+			// a blank line may separate a term from its definitions, not a definition from its lines
+			p.continued = false
+		case heading != nil:
+			p.flush()
+			p.heading(len(heading[1]), heading[2])
+		case trimmed == ":" || strings.HasPrefix(trimmed, ": ") || strings.HasPrefix(trimmed, ":\t"):
+			p.definition(number, trimmed[1:])
+		case p.continued && line != trimmed && !listMarker.MatchString(trimmed):
+			p.extend(trimmed)
+		default:
+			p.flush()
+			item := listMarker.ReplaceAllString(trimmed, "")
+			if term, definition, ok := strings.Cut(item, " "+termSeparator+" "); ok {
+				p.add(number, term, definition)
+			} else {
+				p.term = &Card{Line: number, Front: item}
+			}
+		}
+	}
+	p.flush()
+	return p.result
+}
+
+// flush splits a term no definition followed on the bare separator, or gives up on it
+func (p *markdownParser) flush() {
+	if p.term != nil {
+		if term, definition, ok := strings.Cut(p.term.Front, termSeparator); ok {
+			p.add(p.term.Line, term, definition)
+		} else {
+			p.result.warn(p.term.Line, `the line is not a card, write "term :: definition" or put ": definition" below it`)
+		}
+		p.term = nil
+	}
+	p.card = -1
+	p.continued = false
+}
+
+func (p *markdownParser) heading(level int, title string) {
+	for len(p.headings) > 0 && p.headings[len(p.headings)-1].level >= level {
+		p.headings = p.headings[:len(p.headings)-1]
+	}
+	if title = plainText(title); title != "" {
+		p.headings = append(p.headings, heading{level: level, title: title})
+	}
+}
+
+func (p *markdownParser) definition(line int, definition string) {
+	definition = plainText(definition)
+	switch {
+	case definition == "":
+		p.result.warn(line, "the definition is empty")
+		return
+	case p.term != nil:
+		term := p.term
+		p.term = nil
+		if p.add(term.Line, term.Front, definition) {
+			p.card = len(p.result.Cards) - 1
+		}
+	case p.card >= 0:
+		card := &p.result.Cards[p.card]
+		card.Alternatives = append(card.Alternatives, definition)
+	default:
+		p.result.warn(line, "the definition has no term above it")
+		return
+	}
+	p.continued = p.card >= 0
+}
+
+// extend appends an indented line to the last definition
+func (p *markdownParser) extend(text string) {
+	card := &p.result.Cards[p.card]
+	if n := len(card.Alternatives); n > 0 {
+		card.Alternatives[n-1] += "\n" + text
+	} else {
+		card.Back += "\n" + text
+	}
+}
+
+func (p *markdownParser) add(line int, term, definition string) bool {
+	term, definition = plainText(term), plainText(definition)
+	if term == "" || definition == "" {
+		p.result.warn(line, "the term or the definition is empty")
+		return false
+	}
+	section := make([]string, 0, len(p.headings))
+	for _, heading := range p.headings {
+		section = append(section, heading.title)
+	}
+	p.result.Cards = append(p.result.Cards, Card{Line: line, Front: term, Back: definition, Section: section})
+	return true
+}
+
+// plainText drops the emphasis wrapping a whole term, e.g. **term**
+func plainText(text string) string {
+	text = strings.TrimSpace(text)
+	for _, marker := range []string{"**", "__", "*", "_", "`"} {
+		if len(text) > 2*len(marker) && strings.HasPrefix(text, marker) && strings.HasSuffix(text, marker) {
+			text = strings.TrimSpace(text[len(marker) : len(text)-len(marker)])
+		}
+	}
+	return text
+}
@@ -0,0 +1,126 @@
+// Package textimport turns pasted text into cards: Quizlet exports and Markdown lists.
+//
+// A Quizlet export separates the term from the definition and the cards from each other with
+// separators picked when exporting, tab and new line by default. Markdown cards are either
+// "term :: definition" lines or definition lists, nested headings group them into sections.
+package textimport
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	FormatQuizlet  = "quizlet"
+	FormatMarkdown = "markdown"
+
+	// DefaultTermSeparator and DefaultCardSeparator are the defaults of the Quizlet export dialog
+	DefaultTermSeparator = "\t"
+	DefaultCardSeparator = "\n"
+)
+
+var ErrInvalidOptions = errors.New("invalid text import options")
+
+type Options struct {
+	// Format is quizlet or markdown
+	Format string
+	// TermSeparator and CardSeparator are the custom separators of a Quizlet export, Markdown ignores them
+	TermSeparator string
+	CardSeparator string
+}
+
+// Card is a card read from the text
+type Card struct {
+	// Line is where the card starts
+	Line         int
+	Front        string
+	Back         string
+	Alternatives []string
+	// Section is the path of the Markdown headings above the card, empty outside of any heading
+	Section []string
+}
+
+// Warning points out text that was skipped
+type Warning struct {
+	Line    int
+	Message string
+}
+
+type Result struct {
+	Cards    []Card
+	Warnings []Warning
+}
+
+func (r *Result) warn(line int, format string, args ...any) {
+	r.Warnings = append(r.Warnings, Warning{Line: line, Message: fmt.Sprintf(format, args...)})
+}
+
+// Validate reports options that can't be used to read any text
+func (o Options) Validate() error {
+	switch o.Format {
+	case FormatMarkdown:
+		return nil
+	case FormatQuizlet:
+		term, card := o.separators()
+		if strings.Contains(term, card) || strings.Contains(card, term) {
+			return fmt.Errorf("%w: the term and card separators overlap", ErrInvalidOptions)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown format %q", ErrInvalidOptions, o.Format)
+	}
+}
+
+func (o Options) separators() (term, card string) {
+	term, card = o.TermSeparator, o.CardSeparator
+	if term == "" {
+		term = DefaultTermSeparator
+	}
+	if card == "" {
+		card = DefaultCardSeparator
+	}
+	return term, card
+}
+
+// Parse reads every card of the text, lines that aren't cards become warnings
+func Parse(text string, opts Options) (Result, error) {
+	if err := opts.Validate(); err != nil {
+		return Result{}, err
+	}
+	text = strings.TrimPrefix(text, "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if opts.Format == FormatQuizlet {
+		term, card := opts.separators()
+		return parseQuizlet(text, term, card), nil
+	}
+	return parseMarkdown(text), nil
+}
+
+// parseQuizlet splits the text on the card separator, definitions may span lines unless
+// cards are separated by new lines
+func parseQuizlet(text, termSeparator, cardSeparator string) Result {
+	result := Result{Cards: []Card{}, Warnings: []Warning{}}
+	line := 1
+	for _, chunk := range strings.Split(text, cardSeparator) {
+		trimmed := strings.TrimLeft(chunk, " \t\n")
+		start := line + strings.Count(chunk[:len(chunk)-len(trimmed)], "\n")
+		line += strings.Count(chunk, "\n") + strings.Count(cardSeparator, "\n")
+
+		if strings.TrimSpace(trimmed) == "" {
+			continue
+		}
+		term, definition, ok := strings.Cut(trimmed, termSeparator)
+		if !ok {
+			result.warn(start, "no term separator, the text is not a card")
+			continue
+		}
+		term, definition = strings.TrimSpace(term), strings.TrimSpace(definition)
+		if term == "" || definition == "" {
+			result.warn(start, "the term or the definition is empty")
+			continue
+		}
+		result.Cards = append(result.Cards, Card{Line: start, Front: term, Back: definition, Section: []string{}})
+	}
+	return result
+}
@@ -0,0 +1,151 @@
+package textimport
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		cards    []Card
+		warnings []Warning
+	}{
+		{
+			name: "term lines",
+			text: "- hola :: hello\n* **adiós** :: goodbye\n1. gracias::thanks\n",
+			cards: []Card{
+				{Line: 1, Front: "hola", Back: "hello", Section: []string{}},
+				{Line: 2, Front: "adiós", Back: "goodbye", Section: []string{}},
+				{Line: 3, Front: "gracias", Back: "thanks", Section: []string{}},
+			},
+		},
+		{
+			name: "spaced separator wins",
+			text: "std::vector :: a dynamic array\n",
+			cards: []Card{
+				{Line: 1, Front: "std::vector", Back: "a dynamic array", Section: []string{}},
+			},
+		},
+		{
+			name: "bare separator in a defined term",
+			text: "std::vector\n: a dynamic array\n",
+			cards: []Card{
+				{Line: 1, Front: "std::vector", Back: "a dynamic array", Section: []string{}},
+			},
+		},
+		{
+			name: "definition list",
+			text: "perro\n\n: dog\n: hound\n  a hunting dog\n\ngato\n: cat\n",
+			cards: []Card{
+				{Line: 1, Front: "perro", Back: "dog", Alternatives: []string{"hound\na hunting dog"}, Section: []string{}},
+				{Line: 7, Front: "gato", Back: "cat", Section: []string{}},
+			},
+		},
+		{
+			name: "sections",
+			text: "# Spanish\n## Animals\nperro :: dog\n## Food #\npan :: bread\n# French\nchat :: cat\n",
+			cards: []Card{
+				{Line: 3, Front: "perro", Back: "dog", Section: []string{"Spanish", "Animals"}},
+				{Line: 5, Front: "pan", Back: "bread", Section: []string{"Spanish", "Food"}},
+				{Line: 7, Front: "chat", Back: "cat", Section: []string{"French"}},
+			},
+		},
+		{
+			name:  "skipped lines",
+			text:  "just a line\n: \n```\nhola :: hello\n```\nvacío ::\n",
+			cards: []Card{},
+			warnings: []Warning{
+				{Line: 2, Message: "the definition is empty"},
+				{Line: 1, Message: `the line is not a card, write "term :: definition" or put ": definition" below it`},
+				{Line: 3, Message: "code blocks are not cards"},
+				{Line: 6, Message: "the term or the definition is empty"},
+			},
+		},
+		{
+			name:  "definition without a term",
+			text:  ": orphan\n",
+			cards: []Card{},
+			warnings: []Warning{
+				{Line: 1, Message: "the definition has no term above it"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		result, err := Parse(tt.text, Options{Format: FormatMarkdown})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.warnings == nil {
+			tt.warnings = []Warning{}
+		}
+		if !reflect.DeepEqual(result.Cards, tt.cards) {
+			t.Errorf("%s: cards\n got %+v\nwant %+v", tt.name, result.Cards, tt.cards)
+		}
+		if !reflect.DeepEqual(result.Warnings, tt.warnings) {
+			t.Errorf("%s: warnings\n got %+v\nwant %+v", tt.name, result.Warnings, tt.warnings)
+		}
+	}
+}
+
+func TestParseQuizlet(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		opts     Options
+		cards    []Card
+		warnings []Warning
+	}{
+		{
+			name: "default separators",
+			text: "\ufeffhola\thello\r\n\r\nadiós\tgoodbye\nno separator\nempty definition\t \n",
+			cards: []Card{
+				{Line: 1, Front: "hola", Back: "hello", Section: []string{}},
+				{Line: 3, Front: "adiós", Back: "goodbye", Section: []string{}},
+			},
+			warnings: []Warning{
+				{Line: 4, Message: "no term separator, the text is not a card"},
+				{Line: 5, Message: "the term or the definition is empty"},
+			},
+		},
+		{
+			name: "custom separators keep multiline definitions",
+			text: "hola - hello\nhi;\n\nadiós - goodbye;",
+			opts: Options{TermSeparator: " - ", CardSeparator: ";"},
+			cards: []Card{
+				{Line: 1, Front: "hola", Back: "hello\nhi", Section: []string{}},
+				{Line: 4, Front: "adiós", Back: "goodbye", Section: []string{}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.opts.Format = FormatQuizlet
+		result, err := Parse(tt.text, tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.warnings == nil {
+			tt.warnings = []Warning{}
+		}
+		if !reflect.DeepEqual(result.Cards, tt.cards) {
+			t.Errorf("%s: cards\n got %+v\nwant %+v", tt.name, result.Cards, tt.cards)
+		}
+		if !reflect.DeepEqual(result.Warnings, tt.warnings) {
+			t.Errorf("%s: warnings\n got %+v\nwant %+v", tt.name, result.Warnings, tt.warnings)
+		}
+	}
+}
+
+func TestInvalidOptions(t *testing.T) {
+	for _, opts := range []Options{
+		{Format: "anki"},
+		{Format: FormatQuizlet, TermSeparator: "\n"},
+		{Format: FormatQuizlet, TermSeparator: ",", CardSeparator: ",,"},
+	} {
+		if _, err := Parse("hola\thello", opts); !errors.Is(err, ErrInvalidOptions) {
+			t.Errorf("%+v: got %v, want ErrInvalidOptions", opts, err)
+		}
+	}
+}
@@ -0,0 +1,2373 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v4.25.2
+// source: cards.proto
+
+package cards
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// CardsClient is the client API for Cards service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CardsClient interface {
+	CreateDeck(ctx context.Context, in *CreateDeckRequest, opts ...grpc.CallOption) (*DeckResponse, error)
+	GetDeck(ctx context.Context, in *GetDeckRequest, opts ...grpc.CallOption) (*DeckResponse, error)
+	ListDecks(ctx context.Context, in *ListDecksRequest, opts ...grpc.CallOption) (*ListDecksResponse, error)
+	UpdateDeck(ctx context.Context, in *UpdateDeckRequest, opts ...grpc.CallOption) (*DeckResponse, error)
+	DeleteDeck(ctx context.Context, in *DeleteDeckRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	CreateCard(ctx context.Context, in *CreateCardRequest, opts ...grpc.CallOption) (*CardResponse, error)
+	GetCard(ctx context.Context, in *GetCardRequest, opts ...grpc.CallOption) (*CardResponse, error)
+	ListCards(ctx context.Context, in *ListCardsRequest, opts ...grpc.CallOption) (*ListCardsResponse, error)
+	UpdateCard(ctx context.Context, in *UpdateCardRequest, opts ...grpc.CallOption) (*CardResponse, error)
+	DeleteCard(ctx context.Context, in *DeleteCardRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	SubmitReview(ctx context.Context, in *SubmitReviewRequest, opts ...grpc.CallOption) (*ReviewResponse, error)
+	GetDueCards(ctx context.Context, in *GetDueCardsRequest, opts ...grpc.CallOption) (*GetDueCardsResponse, error)
+	StartQuiz(ctx context.Context, in *StartQuizRequest, opts ...grpc.CallOption) (*QuizSessionResponse, error)
+	GetQuizSession(ctx context.Context, in *QuizSessionRequest, opts ...grpc.CallOption) (*QuizSessionResponse, error)
+	GetNextQuestion(ctx context.Context, in *QuizSessionRequest, opts ...grpc.CallOption) (*QuizSessionResponse, error)
+	SubmitAnswer(ctx context.Context, in *SubmitAnswerRequest, opts ...grpc.CallOption) (*AnswerResponse, error)
+	SkipQuestion(ctx context.Context, in *SkipQuestionRequest, opts ...grpc.CallOption) (*AnswerResponse, error)
+	PauseQuiz(ctx context.Context, in *QuizSessionRequest, opts ...grpc.CallOption) (*QuizSessionResponse, error)
+	ResumeQuiz(ctx context.Context, in *QuizSessionRequest, opts ...grpc.CallOption) (*QuizSessionResponse, error)
+	FinishQuiz(ctx context.Context, in *QuizSessionRequest, opts ...grpc.CallOption) (*QuizSummaryResponse, error)
+	GradeAnswer(ctx context.Context, in *GradeAnswerRequest, opts ...grpc.CallOption) (*GradeAnswerResponse, error)
+	GetChallengeRecords(ctx context.Context, in *GetChallengeRecordsRequest, opts ...grpc.CallOption) (*ChallengeRecordsResponse, error)
+	ImportCards(ctx context.Context, in *ImportCardsRequest, opts ...grpc.CallOption) (*JobResponse, error)
+	PreviewImport(ctx context.Context, in *PreviewImportRequest, opts ...grpc.CallOption) (*PreviewImportResponse, error)
+	ExportCards(ctx context.Context, in *ExportCardsRequest, opts ...grpc.CallOption) (*JobResponse, error)
+	GetJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobResponse, error)
+	GetJobResult(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobResultResponse, error)
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	Autocomplete(ctx context.Context, in *AutocompleteRequest, opts ...grpc.CallOption) (*AutocompleteResponse, error)
+	CreateFolder(ctx context.Context, in *CreateFolderRequest, opts ...grpc.CallOption) (*FolderResponse, error)
+	ListFolders(ctx context.Context, in *ListFoldersRequest, opts ...grpc.CallOption) (*ListFoldersResponse, error)
+	UpdateFolder(ctx context.Context, in *UpdateFolderRequest, opts ...grpc.CallOption) (*FolderResponse, error)
+	DeleteFolder(ctx context.Context, in *DeleteFolderRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	MoveDeck(ctx context.Context, in *MoveDeckRequest, opts ...grpc.CallOption) (*DeckResponse, error)
+	CreateFilteredDeck(ctx context.Context, in *CreateFilteredDeckRequest, opts ...grpc.CallOption) (*FilteredDeckResponse, error)
+	GetFilteredDeck(ctx context.Context, in *FilteredDeckRequest, opts ...grpc.CallOption) (*FilteredDeckResponse, error)
+	ListFilteredDecks(ctx context.Context, in *ListFilteredDecksRequest, opts ...grpc.CallOption) (*ListFilteredDecksResponse, error)
+	UpdateFilteredDeck(ctx context.Context, in *UpdateFilteredDeckRequest, opts ...grpc.CallOption) (*FilteredDeckResponse, error)
+	DeleteFilteredDeck(ctx context.Context, in *FilteredDeckRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	CreateInvitation(ctx context.Context, in *CreateInvitationRequest, opts ...grpc.CallOption) (*InvitationResponse, error)
+	ListInvitations(ctx context.Context, in *DeckSharingRequest, opts ...grpc.CallOption) (*ListInvitationsResponse, error)
+	RevokeInvitation(ctx context.Context, in *RevokeInvitationRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	AcceptInvitation(ctx context.Context, in *AcceptInvitationRequest, opts ...grpc.CallOption) (*SharedDeckResponse, error)
+	ListMembers(ctx context.Context, in *DeckSharingRequest, opts ...grpc.CallOption) (*ListMembersResponse, error)
+	UpdateMember(ctx context.Context, in *UpdateMemberRequest, opts ...grpc.CallOption) (*MemberResponse, error)
+	RemoveMember(ctx context.Context, in *RemoveMemberRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	ListSharedDecks(ctx context.Context, in *ListSharedDecksRequest, opts ...grpc.CallOption) (*ListSharedDecksResponse, error)
+	PublishDeck(ctx context.Context, in *PublishDeckRequest, opts ...grpc.CallOption) (*CatalogDeckResponse, error)
+	UnpublishDeck(ctx context.Context, in *CatalogDeckRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	BrowseCatalog(ctx context.Context, in *BrowseCatalogRequest, opts ...grpc.CallOption) (*BrowseCatalogResponse, error)
+	GetCatalogDeck(ctx context.Context, in *CatalogDeckRequest, opts ...grpc.CallOption) (*GetCatalogDeckResponse, error)
+	ForkDeck(ctx context.Context, in *ForkDeckRequest, opts ...grpc.CallOption) (*DeckResponse, error)
+	GetUpstreamChanges(ctx context.Context, in *CatalogDeckRequest, opts ...grpc.CallOption) (*UpstreamChangesResponse, error)
+	MergeUpstream(ctx context.Context, in *MergeUpstreamRequest, opts ...grpc.CallOption) (*MergeUpstreamResponse, error)
+	GetStudyStats(ctx context.Context, in *StudyStatsRequest, opts ...grpc.CallOption) (*StudyStatsResponse, error)
+	GetReviewHeatmap(ctx context.Context, in *StudyStatsRequest, opts ...grpc.CallOption) (*ReviewHeatmapResponse, error)
+	GetReviewForecast(ctx context.Context, in *ReviewForecastRequest, opts ...grpc.CallOption) (*ReviewForecastResponse, error)
+	GetStreak(ctx context.Context, in *StreakRequest, opts ...grpc.CallOption) (*StreakResponse, error)
+	UpdateStudySettings(ctx context.Context, in *UpdateStudySettingsRequest, opts ...grpc.CallOption) (*StudySettingsResponse, error)
+	GetAchievements(ctx context.Context, in *AchievementsRequest, opts ...grpc.CallOption) (*AchievementsResponse, error)
+	FollowUser(ctx context.Context, in *FollowRequest, opts ...grpc.CallOption) (*FollowingResponse, error)
+	UnfollowUser(ctx context.Context, in *FollowRequest, opts ...grpc.CallOption) (*FollowingResponse, error)
+	ListFollowing(ctx context.Context, in *FollowingRequest, opts ...grpc.CallOption) (*FollowingResponse, error)
+	GetLeaderboard(ctx context.Context, in *LeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardResponse, error)
+}
+
+type cardsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCardsClient(cc grpc.ClientConnInterface) CardsClient {
+	return &cardsClient{cc}
+}
+
+func (c *cardsClient) CreateDeck(ctx context.Context, in *CreateDeckRequest, opts ...grpc.CallOption) (*DeckResponse, error) {
+	out := new(DeckResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/CreateDeck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) GetDeck(ctx context.Context, in *GetDeckRequest, opts ...grpc.CallOption) (*DeckResponse, error) {
+	out := new(DeckResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/GetDeck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) ListDecks(ctx context.Context, in *ListDecksRequest, opts ...grpc.CallOption) (*ListDecksResponse, error) {
+	out := new(ListDecksResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/ListDecks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) UpdateDeck(ctx context.Context, in *UpdateDeckRequest, opts ...grpc.CallOption) (*DeckResponse, error) {
+	out := new(DeckResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/UpdateDeck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) DeleteDeck(ctx context.Context, in *DeleteDeckRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/DeleteDeck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) CreateCard(ctx context.Context, in *CreateCardRequest, opts ...grpc.CallOption) (*CardResponse, error) {
+	out := new(CardResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/CreateCard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) GetCard(ctx context.Context, in *GetCardRequest, opts ...grpc.CallOption) (*CardResponse, error) {
+	out := new(CardResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/GetCard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) ListCards(ctx context.Context, in *ListCardsRequest, opts ...grpc.CallOption) (*ListCardsResponse, error) {
+	out := new(ListCardsResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/ListCards", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) UpdateCard(ctx context.Context, in *UpdateCardRequest, opts ...grpc.CallOption) (*CardResponse, error) {
+	out := new(CardResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/UpdateCard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) DeleteCard(ctx context.Context, in *DeleteCardRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/DeleteCard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) SubmitReview(ctx context.Context, in *SubmitReviewRequest, opts ...grpc.CallOption) (*ReviewResponse, error) {
+	out := new(ReviewResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/SubmitReview", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) GetDueCards(ctx context.Context, in *GetDueCardsRequest, opts ...grpc.CallOption) (*GetDueCardsResponse, error) {
+	out := new(GetDueCardsResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/GetDueCards", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) StartQuiz(ctx context.Context, in *StartQuizRequest, opts ...grpc.CallOption) (*QuizSessionResponse, error) {
+	out := new(QuizSessionResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/StartQuiz", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) GetQuizSession(ctx context.Context, in *QuizSessionRequest, opts ...grpc.CallOption) (*QuizSessionResponse, error) {
+	out := new(QuizSessionResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/GetQuizSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) GetNextQuestion(ctx context.Context, in *QuizSessionRequest, opts ...grpc.CallOption) (*QuizSessionResponse, error) {
+	out := new(QuizSessionResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/GetNextQuestion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) SubmitAnswer(ctx context.Context, in *SubmitAnswerRequest, opts ...grpc.CallOption) (*AnswerResponse, error) {
+	out := new(AnswerResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/SubmitAnswer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) SkipQuestion(ctx context.Context, in *SkipQuestionRequest, opts ...grpc.CallOption) (*AnswerResponse, error) {
+	out := new(AnswerResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/SkipQuestion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) PauseQuiz(ctx context.Context, in *QuizSessionRequest, opts ...grpc.CallOption) (*QuizSessionResponse, error) {
+	out := new(QuizSessionResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/PauseQuiz", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) ResumeQuiz(ctx context.Context, in *QuizSessionRequest, opts ...grpc.CallOption) (*QuizSessionResponse, error) {
+	out := new(QuizSessionResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/ResumeQuiz", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) FinishQuiz(ctx context.Context, in *QuizSessionRequest, opts ...grpc.CallOption) (*QuizSummaryResponse, error) {
+	out := new(QuizSummaryResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/FinishQuiz", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) GradeAnswer(ctx context.Context, in *GradeAnswerRequest, opts ...grpc.CallOption) (*GradeAnswerResponse, error) {
+	out := new(GradeAnswerResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/GradeAnswer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) GetChallengeRecords(ctx context.Context, in *GetChallengeRecordsRequest, opts ...grpc.CallOption) (*ChallengeRecordsResponse, error) {
+	out := new(ChallengeRecordsResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/GetChallengeRecords", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) ImportCards(ctx context.Context, in *ImportCardsRequest, opts ...grpc.CallOption) (*JobResponse, error) {
+	out := new(JobResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/ImportCards", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) PreviewImport(ctx context.Context, in *PreviewImportRequest, opts ...grpc.CallOption) (*PreviewImportResponse, error) {
+	out := new(PreviewImportResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/PreviewImport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) ExportCards(ctx context.Context, in *ExportCardsRequest, opts ...grpc.CallOption) (*JobResponse, error) {
+	out := new(JobResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/ExportCards", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) GetJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobResponse, error) {
+	out := new(JobResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/GetJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) GetJobResult(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobResultResponse, error) {
+	out := new(JobResultResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/GetJobResult", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/Search", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) Autocomplete(ctx context.Context, in *AutocompleteRequest, opts ...grpc.CallOption) (*AutocompleteResponse, error) {
+	out := new(AutocompleteResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/Autocomplete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) CreateFolder(ctx context.Context, in *CreateFolderRequest, opts ...grpc.CallOption) (*FolderResponse, error) {
+	out := new(FolderResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/CreateFolder", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) ListFolders(ctx context.Context, in *ListFoldersRequest, opts ...grpc.CallOption) (*ListFoldersResponse, error) {
+	out := new(ListFoldersResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/ListFolders", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) UpdateFolder(ctx context.Context, in *UpdateFolderRequest, opts ...grpc.CallOption) (*FolderResponse, error) {
+	out := new(FolderResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/UpdateFolder", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) DeleteFolder(ctx context.Context, in *DeleteFolderRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/DeleteFolder", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) MoveDeck(ctx context.Context, in *MoveDeckRequest, opts ...grpc.CallOption) (*DeckResponse, error) {
+	out := new(DeckResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/MoveDeck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) CreateFilteredDeck(ctx context.Context, in *CreateFilteredDeckRequest, opts ...grpc.CallOption) (*FilteredDeckResponse, error) {
+	out := new(FilteredDeckResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/CreateFilteredDeck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) GetFilteredDeck(ctx context.Context, in *FilteredDeckRequest, opts ...grpc.CallOption) (*FilteredDeckResponse, error) {
+	out := new(FilteredDeckResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/GetFilteredDeck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) ListFilteredDecks(ctx context.Context, in *ListFilteredDecksRequest, opts ...grpc.CallOption) (*ListFilteredDecksResponse, error) {
+	out := new(ListFilteredDecksResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/ListFilteredDecks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) UpdateFilteredDeck(ctx context.Context, in *UpdateFilteredDeckRequest, opts ...grpc.CallOption) (*FilteredDeckResponse, error) {
+	out := new(FilteredDeckResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/UpdateFilteredDeck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) DeleteFilteredDeck(ctx context.Context, in *FilteredDeckRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/DeleteFilteredDeck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) CreateInvitation(ctx context.Context, in *CreateInvitationRequest, opts ...grpc.CallOption) (*InvitationResponse, error) {
+	out := new(InvitationResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/CreateInvitation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) ListInvitations(ctx context.Context, in *DeckSharingRequest, opts ...grpc.CallOption) (*ListInvitationsResponse, error) {
+	out := new(ListInvitationsResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/ListInvitations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) RevokeInvitation(ctx context.Context, in *RevokeInvitationRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/RevokeInvitation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) AcceptInvitation(ctx context.Context, in *AcceptInvitationRequest, opts ...grpc.CallOption) (*SharedDeckResponse, error) {
+	out := new(SharedDeckResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/AcceptInvitation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) ListMembers(ctx context.Context, in *DeckSharingRequest, opts ...grpc.CallOption) (*ListMembersResponse, error) {
+	out := new(ListMembersResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/ListMembers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) UpdateMember(ctx context.Context, in *UpdateMemberRequest, opts ...grpc.CallOption) (*MemberResponse, error) {
+	out := new(MemberResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/UpdateMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) RemoveMember(ctx context.Context, in *RemoveMemberRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/RemoveMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) ListSharedDecks(ctx context.Context, in *ListSharedDecksRequest, opts ...grpc.CallOption) (*ListSharedDecksResponse, error) {
+	out := new(ListSharedDecksResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/ListSharedDecks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) PublishDeck(ctx context.Context, in *PublishDeckRequest, opts ...grpc.CallOption) (*CatalogDeckResponse, error) {
+	out := new(CatalogDeckResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/PublishDeck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) UnpublishDeck(ctx context.Context, in *CatalogDeckRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/UnpublishDeck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) BrowseCatalog(ctx context.Context, in *BrowseCatalogRequest, opts ...grpc.CallOption) (*BrowseCatalogResponse, error) {
+	out := new(BrowseCatalogResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/BrowseCatalog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) GetCatalogDeck(ctx context.Context, in *CatalogDeckRequest, opts ...grpc.CallOption) (*GetCatalogDeckResponse, error) {
+	out := new(GetCatalogDeckResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/GetCatalogDeck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) ForkDeck(ctx context.Context, in *ForkDeckRequest, opts ...grpc.CallOption) (*DeckResponse, error) {
+	out := new(DeckResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/ForkDeck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) GetUpstreamChanges(ctx context.Context, in *CatalogDeckRequest, opts ...grpc.CallOption) (*UpstreamChangesResponse, error) {
+	out := new(UpstreamChangesResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/GetUpstreamChanges", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) MergeUpstream(ctx context.Context, in *MergeUpstreamRequest, opts ...grpc.CallOption) (*MergeUpstreamResponse, error) {
+	out := new(MergeUpstreamResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/MergeUpstream", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) GetStudyStats(ctx context.Context, in *StudyStatsRequest, opts ...grpc.CallOption) (*StudyStatsResponse, error) {
+	out := new(StudyStatsResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/GetStudyStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) GetReviewHeatmap(ctx context.Context, in *StudyStatsRequest, opts ...grpc.CallOption) (*ReviewHeatmapResponse, error) {
+	out := new(ReviewHeatmapResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/GetReviewHeatmap", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) GetReviewForecast(ctx context.Context, in *ReviewForecastRequest, opts ...grpc.CallOption) (*ReviewForecastResponse, error) {
+	out := new(ReviewForecastResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/GetReviewForecast", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) GetStreak(ctx context.Context, in *StreakRequest, opts ...grpc.CallOption) (*StreakResponse, error) {
+	out := new(StreakResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/GetStreak", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) UpdateStudySettings(ctx context.Context, in *UpdateStudySettingsRequest, opts ...grpc.CallOption) (*StudySettingsResponse, error) {
+	out := new(StudySettingsResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/UpdateStudySettings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) GetAchievements(ctx context.Context, in *AchievementsRequest, opts ...grpc.CallOption) (*AchievementsResponse, error) {
+	out := new(AchievementsResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/GetAchievements", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) FollowUser(ctx context.Context, in *FollowRequest, opts ...grpc.CallOption) (*FollowingResponse, error) {
+	out := new(FollowingResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/FollowUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) UnfollowUser(ctx context.Context, in *FollowRequest, opts ...grpc.CallOption) (*FollowingResponse, error) {
+	out := new(FollowingResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/UnfollowUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) ListFollowing(ctx context.Context, in *FollowingRequest, opts ...grpc.CallOption) (*FollowingResponse, error) {
+	out := new(FollowingResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/ListFollowing", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardsClient) GetLeaderboard(ctx context.Context, in *LeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardResponse, error) {
+	out := new(LeaderboardResponse)
+	err := c.cc.Invoke(ctx, "/cards.Cards/GetLeaderboard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CardsServer is the server API for Cards service.
+// All implementations must embed UnimplementedCardsServer
+// for forward compatibility
+type CardsServer interface {
+	CreateDeck(context.Context, *CreateDeckRequest) (*DeckResponse, error)
+	GetDeck(context.Context, *GetDeckRequest) (*DeckResponse, error)
+	ListDecks(context.Context, *ListDecksRequest) (*ListDecksResponse, error)
+	UpdateDeck(context.Context, *UpdateDeckRequest) (*DeckResponse, error)
+	DeleteDeck(context.Context, *DeleteDeckRequest) (*DeleteResponse, error)
+	CreateCard(context.Context, *CreateCardRequest) (*CardResponse, error)
+	GetCard(context.Context, *GetCardRequest) (*CardResponse, error)
+	ListCards(context.Context, *ListCardsRequest) (*ListCardsResponse, error)
+	UpdateCard(context.Context, *UpdateCardRequest) (*CardResponse, error)
+	DeleteCard(context.Context, *DeleteCardRequest) (*DeleteResponse, error)
+	SubmitReview(context.Context, *SubmitReviewRequest) (*ReviewResponse, error)
+	GetDueCards(context.Context, *GetDueCardsRequest) (*GetDueCardsResponse, error)
+	StartQuiz(context.Context, *StartQuizRequest) (*QuizSessionResponse, error)
+	GetQuizSession(context.Context, *QuizSessionRequest) (*QuizSessionResponse, error)
+	GetNextQuestion(context.Context, *QuizSessionRequest) (*QuizSessionResponse, error)
+	SubmitAnswer(context.Context, *SubmitAnswerRequest) (*AnswerResponse, error)
+	SkipQuestion(context.Context, *SkipQuestionRequest) (*AnswerResponse, error)
+	PauseQuiz(context.Context, *QuizSessionRequest) (*QuizSessionResponse, error)
+	ResumeQuiz(context.Context, *QuizSessionRequest) (*QuizSessionResponse, error)
+	FinishQuiz(context.Context, *QuizSessionRequest) (*QuizSummaryResponse, error)
+	GradeAnswer(context.Context, *GradeAnswerRequest) (*GradeAnswerResponse, error)
+	GetChallengeRecords(context.Context, *GetChallengeRecordsRequest) (*ChallengeRecordsResponse, error)
+	ImportCards(context.Context, *ImportCardsRequest) (*JobResponse, error)
+	PreviewImport(context.Context, *PreviewImportRequest) (*PreviewImportResponse, error)
+	ExportCards(context.Context, *ExportCardsRequest) (*JobResponse, error)
+	GetJob(context.Context, *JobRequest) (*JobResponse, error)
+	GetJobResult(context.Context, *JobRequest) (*JobResultResponse, error)
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	Autocomplete(context.Context, *AutocompleteRequest) (*AutocompleteResponse, error)
+	CreateFolder(context.Context, *CreateFolderRequest) (*FolderResponse, error)
+	ListFolders(context.Context, *ListFoldersRequest) (*ListFoldersResponse, error)
+	UpdateFolder(context.Context, *UpdateFolderRequest) (*FolderResponse, error)
+	DeleteFolder(context.Context, *DeleteFolderRequest) (*DeleteResponse, error)
+	MoveDeck(context.Context, *MoveDeckRequest) (*DeckResponse, error)
+	CreateFilteredDeck(context.Context, *CreateFilteredDeckRequest) (*FilteredDeckResponse, error)
+	GetFilteredDeck(context.Context, *FilteredDeckRequest) (*FilteredDeckResponse, error)
+	ListFilteredDecks(context.Context, *ListFilteredDecksRequest) (*ListFilteredDecksResponse, error)
+	UpdateFilteredDeck(context.Context, *UpdateFilteredDeckRequest) (*FilteredDeckResponse, error)
+	DeleteFilteredDeck(context.Context, *FilteredDeckRequest) (*DeleteResponse, error)
+	CreateInvitation(context.Context, *CreateInvitationRequest) (*InvitationResponse, error)
+	ListInvitations(context.Context, *DeckSharingRequest) (*ListInvitationsResponse, error)
+	RevokeInvitation(context.Context, *RevokeInvitationRequest) (*DeleteResponse, error)
+	AcceptInvitation(context.Context, *AcceptInvitationRequest) (*SharedDeckResponse, error)
+	ListMembers(context.Context, *DeckSharingRequest) (*ListMembersResponse, error)
+	UpdateMember(context.Context, *UpdateMemberRequest) (*MemberResponse, error)
+	RemoveMember(context.Context, *RemoveMemberRequest) (*DeleteResponse, error)
+	ListSharedDecks(context.Context, *ListSharedDecksRequest) (*ListSharedDecksResponse, error)
+	PublishDeck(context.Context, *PublishDeckRequest) (*CatalogDeckResponse, error)
+	UnpublishDeck(context.Context, *CatalogDeckRequest) (*DeleteResponse, error)
+	BrowseCatalog(context.Context, *BrowseCatalogRequest) (*BrowseCatalogResponse, error)
+	GetCatalogDeck(context.Context, *CatalogDeckRequest) (*GetCatalogDeckResponse, error)
+	ForkDeck(context.Context, *ForkDeckRequest) (*DeckResponse, error)
+	GetUpstreamChanges(context.Context, *CatalogDeckRequest) (*UpstreamChangesResponse, error)
+	MergeUpstream(context.Context, *MergeUpstreamRequest) (*MergeUpstreamResponse, error)
+	GetStudyStats(context.Context, *StudyStatsRequest) (*StudyStatsResponse, error)
+	GetReviewHeatmap(context.Context, *StudyStatsRequest) (*ReviewHeatmapResponse, error)
+	GetReviewForecast(context.Context, *ReviewForecastRequest) (*ReviewForecastResponse, error)
+	GetStreak(context.Context, *StreakRequest) (*StreakResponse, error)
+	UpdateStudySettings(context.Context, *UpdateStudySettingsRequest) (*StudySettingsResponse, error)
+	GetAchievements(context.Context, *AchievementsRequest) (*AchievementsResponse, error)
+	FollowUser(context.Context, *FollowRequest) (*FollowingResponse, error)
+	UnfollowUser(context.Context, *FollowRequest) (*FollowingResponse, error)
+	ListFollowing(context.Context, *FollowingRequest) (*FollowingResponse, error)
+	GetLeaderboard(context.Context, *LeaderboardRequest) (*LeaderboardResponse, error)
+	mustEmbedUnimplementedCardsServer()
+}
+
+// UnimplementedCardsServer must be embedded to have forward compatible implementations.
+type UnimplementedCardsServer struct {
+}
+
+func (UnimplementedCardsServer) CreateDeck(context.Context, *CreateDeckRequest) (*DeckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDeck not implemented")
+}
+func (UnimplementedCardsServer) GetDeck(context.Context, *GetDeckRequest) (*DeckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeck not implemented")
+}
+func (UnimplementedCardsServer) ListDecks(context.Context, *ListDecksRequest) (*ListDecksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDecks not implemented")
+}
+func (UnimplementedCardsServer) UpdateDeck(context.Context, *UpdateDeckRequest) (*DeckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDeck not implemented")
+}
+func (UnimplementedCardsServer) DeleteDeck(context.Context, *DeleteDeckRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDeck not implemented")
+}
+func (UnimplementedCardsServer) CreateCard(context.Context, *CreateCardRequest) (*CardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCard not implemented")
+}
+func (UnimplementedCardsServer) GetCard(context.Context, *GetCardRequest) (*CardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCard not implemented")
+}
+func (UnimplementedCardsServer) ListCards(context.Context, *ListCardsRequest) (*ListCardsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCards not implemented")
+}
+func (UnimplementedCardsServer) UpdateCard(context.Context, *UpdateCardRequest) (*CardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCard not implemented")
+}
+func (UnimplementedCardsServer) DeleteCard(context.Context, *DeleteCardRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCard not implemented")
+}
+func (UnimplementedCardsServer) SubmitReview(context.Context, *SubmitReviewRequest) (*ReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitReview not implemented")
+}
+func (UnimplementedCardsServer) GetDueCards(context.Context, *GetDueCardsRequest) (*GetDueCardsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDueCards not implemented")
+}
+func (UnimplementedCardsServer) StartQuiz(context.Context, *StartQuizRequest) (*QuizSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartQuiz not implemented")
+}
+func (UnimplementedCardsServer) GetQuizSession(context.Context, *QuizSessionRequest) (*QuizSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQuizSession not implemented")
+}
+func (UnimplementedCardsServer) GetNextQuestion(context.Context, *QuizSessionRequest) (*QuizSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNextQuestion not implemented")
+}
+func (UnimplementedCardsServer) SubmitAnswer(context.Context, *SubmitAnswerRequest) (*AnswerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitAnswer not implemented")
+}
+func (UnimplementedCardsServer) SkipQuestion(context.Context, *SkipQuestionRequest) (*AnswerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SkipQuestion not implemented")
+}
+func (UnimplementedCardsServer) PauseQuiz(context.Context, *QuizSessionRequest) (*QuizSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseQuiz not implemented")
+}
+func (UnimplementedCardsServer) ResumeQuiz(context.Context, *QuizSessionRequest) (*QuizSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeQuiz not implemented")
+}
+func (UnimplementedCardsServer) FinishQuiz(context.Context, *QuizSessionRequest) (*QuizSummaryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FinishQuiz not implemented")
+}
+func (UnimplementedCardsServer) GradeAnswer(context.Context, *GradeAnswerRequest) (*GradeAnswerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GradeAnswer not implemented")
+}
+func (UnimplementedCardsServer) GetChallengeRecords(context.Context, *GetChallengeRecordsRequest) (*ChallengeRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChallengeRecords not implemented")
+}
+func (UnimplementedCardsServer) ImportCards(context.Context, *ImportCardsRequest) (*JobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportCards not implemented")
+}
+func (UnimplementedCardsServer) PreviewImport(context.Context, *PreviewImportRequest) (*PreviewImportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PreviewImport not implemented")
+}
+func (UnimplementedCardsServer) ExportCards(context.Context, *ExportCardsRequest) (*JobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportCards not implemented")
+}
+func (UnimplementedCardsServer) GetJob(context.Context, *JobRequest) (*JobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJob not implemented")
+}
+func (UnimplementedCardsServer) GetJobResult(context.Context, *JobRequest) (*JobResultResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobResult not implemented")
+}
+func (UnimplementedCardsServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedCardsServer) Autocomplete(context.Context, *AutocompleteRequest) (*AutocompleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Autocomplete not implemented")
+}
+func (UnimplementedCardsServer) CreateFolder(context.Context, *CreateFolderRequest) (*FolderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFolder not implemented")
+}
+func (UnimplementedCardsServer) ListFolders(context.Context, *ListFoldersRequest) (*ListFoldersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFolders not implemented")
+}
+func (UnimplementedCardsServer) UpdateFolder(context.Context, *UpdateFolderRequest) (*FolderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateFolder not implemented")
+}
+func (UnimplementedCardsServer) DeleteFolder(context.Context, *DeleteFolderRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFolder not implemented")
+}
+func (UnimplementedCardsServer) MoveDeck(context.Context, *MoveDeckRequest) (*DeckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveDeck not implemented")
+}
+func (UnimplementedCardsServer) CreateFilteredDeck(context.Context, *CreateFilteredDeckRequest) (*FilteredDeckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFilteredDeck not implemented")
+}
+func (UnimplementedCardsServer) GetFilteredDeck(context.Context, *FilteredDeckRequest) (*FilteredDeckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFilteredDeck not implemented")
+}
+func (UnimplementedCardsServer) ListFilteredDecks(context.Context, *ListFilteredDecksRequest) (*ListFilteredDecksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFilteredDecks not implemented")
+}
+func (UnimplementedCardsServer) UpdateFilteredDeck(context.Context, *UpdateFilteredDeckRequest) (*FilteredDeckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateFilteredDeck not implemented")
+}
+func (UnimplementedCardsServer) DeleteFilteredDeck(context.Context, *FilteredDeckRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFilteredDeck not implemented")
+}
+func (UnimplementedCardsServer) CreateInvitation(context.Context, *CreateInvitationRequest) (*InvitationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateInvitation not implemented")
+}
+func (UnimplementedCardsServer) ListInvitations(context.Context, *DeckSharingRequest) (*ListInvitationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInvitations not implemented")
+}
+func (UnimplementedCardsServer) RevokeInvitation(context.Context, *RevokeInvitationRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeInvitation not implemented")
+}
+func (UnimplementedCardsServer) AcceptInvitation(context.Context, *AcceptInvitationRequest) (*SharedDeckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptInvitation not implemented")
+}
+func (UnimplementedCardsServer) ListMembers(context.Context, *DeckSharingRequest) (*ListMembersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMembers not implemented")
+}
+func (UnimplementedCardsServer) UpdateMember(context.Context, *UpdateMemberRequest) (*MemberResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateMember not implemented")
+}
+func (UnimplementedCardsServer) RemoveMember(context.Context, *RemoveMemberRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveMember not implemented")
+}
+func (UnimplementedCardsServer) ListSharedDecks(context.Context, *ListSharedDecksRequest) (*ListSharedDecksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSharedDecks not implemented")
+}
+func (UnimplementedCardsServer) PublishDeck(context.Context, *PublishDeckRequest) (*CatalogDeckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishDeck not implemented")
+}
+func (UnimplementedCardsServer) UnpublishDeck(context.Context, *CatalogDeckRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnpublishDeck not implemented")
+}
+func (UnimplementedCardsServer) BrowseCatalog(context.Context, *BrowseCatalogRequest) (*BrowseCatalogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BrowseCatalog not implemented")
+}
+func (UnimplementedCardsServer) GetCatalogDeck(context.Context, *CatalogDeckRequest) (*GetCatalogDeckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCatalogDeck not implemented")
+}
+func (UnimplementedCardsServer) ForkDeck(context.Context, *ForkDeckRequest) (*DeckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForkDeck not implemented")
+}
+func (UnimplementedCardsServer) GetUpstreamChanges(context.Context, *CatalogDeckRequest) (*UpstreamChangesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUpstreamChanges not implemented")
+}
+func (UnimplementedCardsServer) MergeUpstream(context.Context, *MergeUpstreamRequest) (*MergeUpstreamResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergeUpstream not implemented")
+}
+func (UnimplementedCardsServer) GetStudyStats(context.Context, *StudyStatsRequest) (*StudyStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStudyStats not implemented")
+}
+func (UnimplementedCardsServer) GetReviewHeatmap(context.Context, *StudyStatsRequest) (*ReviewHeatmapResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReviewHeatmap not implemented")
+}
+func (UnimplementedCardsServer) GetReviewForecast(context.Context, *ReviewForecastRequest) (*ReviewForecastResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReviewForecast not implemented")
+}
+func (UnimplementedCardsServer) GetStreak(context.Context, *StreakRequest) (*StreakResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStreak not implemented")
+}
+func (UnimplementedCardsServer) UpdateStudySettings(context.Context, *UpdateStudySettingsRequest) (*StudySettingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateStudySettings not implemented")
+}
+func (UnimplementedCardsServer) GetAchievements(context.Context, *AchievementsRequest) (*AchievementsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAchievements not implemented")
+}
+func (UnimplementedCardsServer) FollowUser(context.Context, *FollowRequest) (*FollowingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FollowUser not implemented")
+}
+func (UnimplementedCardsServer) UnfollowUser(context.Context, *FollowRequest) (*FollowingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnfollowUser not implemented")
+}
+func (UnimplementedCardsServer) ListFollowing(context.Context, *FollowingRequest) (*FollowingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFollowing not implemented")
+}
+func (UnimplementedCardsServer) GetLeaderboard(context.Context, *LeaderboardRequest) (*LeaderboardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLeaderboard not implemented")
+}
+func (UnimplementedCardsServer) mustEmbedUnimplementedCardsServer() {}
+
+// UnsafeCardsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CardsServer will
+// result in compilation errors.
+type UnsafeCardsServer interface {
+	mustEmbedUnimplementedCardsServer()
+}
+
+func RegisterCardsServer(s grpc.ServiceRegistrar, srv CardsServer) {
+	s.RegisterService(&Cards_ServiceDesc, srv)
+}
+
+func _Cards_CreateDeck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDeckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).CreateDeck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/CreateDeck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).CreateDeck(ctx, req.(*CreateDeckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_GetDeck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).GetDeck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/GetDeck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).GetDeck(ctx, req.(*GetDeckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_ListDecks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDecksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).ListDecks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/ListDecks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).ListDecks(ctx, req.(*ListDecksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_UpdateDeck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDeckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).UpdateDeck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/UpdateDeck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).UpdateDeck(ctx, req.(*UpdateDeckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_DeleteDeck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDeckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).DeleteDeck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/DeleteDeck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).DeleteDeck(ctx, req.(*DeleteDeckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_CreateCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).CreateCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/CreateCard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).CreateCard(ctx, req.(*CreateCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_GetCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).GetCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/GetCard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).GetCard(ctx, req.(*GetCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_ListCards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCardsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).ListCards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/ListCards",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).ListCards(ctx, req.(*ListCardsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_UpdateCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).UpdateCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/UpdateCard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).UpdateCard(ctx, req.(*UpdateCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_DeleteCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).DeleteCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/DeleteCard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).DeleteCard(ctx, req.(*DeleteCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_SubmitReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).SubmitReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/SubmitReview",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).SubmitReview(ctx, req.(*SubmitReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_GetDueCards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDueCardsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).GetDueCards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/GetDueCards",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).GetDueCards(ctx, req.(*GetDueCardsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_StartQuiz_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartQuizRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).StartQuiz(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/StartQuiz",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).StartQuiz(ctx, req.(*StartQuizRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_GetQuizSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuizSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).GetQuizSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/GetQuizSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).GetQuizSession(ctx, req.(*QuizSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_GetNextQuestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuizSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).GetNextQuestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/GetNextQuestion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).GetNextQuestion(ctx, req.(*QuizSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_SubmitAnswer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitAnswerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).SubmitAnswer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/SubmitAnswer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).SubmitAnswer(ctx, req.(*SubmitAnswerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_SkipQuestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SkipQuestionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).SkipQuestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/SkipQuestion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).SkipQuestion(ctx, req.(*SkipQuestionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_PauseQuiz_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuizSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).PauseQuiz(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/PauseQuiz",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).PauseQuiz(ctx, req.(*QuizSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_ResumeQuiz_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuizSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).ResumeQuiz(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/ResumeQuiz",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).ResumeQuiz(ctx, req.(*QuizSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_FinishQuiz_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuizSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).FinishQuiz(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/FinishQuiz",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).FinishQuiz(ctx, req.(*QuizSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_GradeAnswer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GradeAnswerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).GradeAnswer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/GradeAnswer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).GradeAnswer(ctx, req.(*GradeAnswerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_GetChallengeRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChallengeRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).GetChallengeRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/GetChallengeRecords",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).GetChallengeRecords(ctx, req.(*GetChallengeRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_ImportCards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportCardsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).ImportCards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/ImportCards",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).ImportCards(ctx, req.(*ImportCardsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_PreviewImport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreviewImportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).PreviewImport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/PreviewImport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).PreviewImport(ctx, req.(*PreviewImportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_ExportCards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportCardsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).ExportCards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/ExportCards",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).ExportCards(ctx, req.(*ExportCardsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_GetJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).GetJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/GetJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).GetJob(ctx, req.(*JobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_GetJobResult_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).GetJobResult(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/GetJobResult",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).GetJobResult(ctx, req.(*JobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/Search",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_Autocomplete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AutocompleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).Autocomplete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/Autocomplete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).Autocomplete(ctx, req.(*AutocompleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_CreateFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).CreateFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/CreateFolder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).CreateFolder(ctx, req.(*CreateFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_ListFolders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFoldersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).ListFolders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/ListFolders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).ListFolders(ctx, req.(*ListFoldersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_UpdateFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).UpdateFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/UpdateFolder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).UpdateFolder(ctx, req.(*UpdateFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_DeleteFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).DeleteFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/DeleteFolder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).DeleteFolder(ctx, req.(*DeleteFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_MoveDeck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveDeckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).MoveDeck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/MoveDeck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).MoveDeck(ctx, req.(*MoveDeckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_CreateFilteredDeck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateFilteredDeckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).CreateFilteredDeck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/CreateFilteredDeck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).CreateFilteredDeck(ctx, req.(*CreateFilteredDeckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_GetFilteredDeck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FilteredDeckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).GetFilteredDeck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/GetFilteredDeck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).GetFilteredDeck(ctx, req.(*FilteredDeckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_ListFilteredDecks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFilteredDecksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).ListFilteredDecks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/ListFilteredDecks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).ListFilteredDecks(ctx, req.(*ListFilteredDecksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_UpdateFilteredDeck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFilteredDeckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).UpdateFilteredDeck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/UpdateFilteredDeck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).UpdateFilteredDeck(ctx, req.(*UpdateFilteredDeckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_DeleteFilteredDeck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FilteredDeckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).DeleteFilteredDeck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/DeleteFilteredDeck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).DeleteFilteredDeck(ctx, req.(*FilteredDeckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_CreateInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateInvitationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).CreateInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/CreateInvitation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).CreateInvitation(ctx, req.(*CreateInvitationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_ListInvitations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeckSharingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).ListInvitations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/ListInvitations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).ListInvitations(ctx, req.(*DeckSharingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_RevokeInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeInvitationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).RevokeInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/RevokeInvitation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).RevokeInvitation(ctx, req.(*RevokeInvitationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_AcceptInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcceptInvitationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).AcceptInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/AcceptInvitation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).AcceptInvitation(ctx, req.(*AcceptInvitationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_ListMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeckSharingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).ListMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/ListMembers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).ListMembers(ctx, req.(*DeckSharingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_UpdateMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).UpdateMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/UpdateMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).UpdateMember(ctx, req.(*UpdateMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_RemoveMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).RemoveMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/RemoveMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).RemoveMember(ctx, req.(*RemoveMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_ListSharedDecks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSharedDecksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).ListSharedDecks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/ListSharedDecks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).ListSharedDecks(ctx, req.(*ListSharedDecksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_PublishDeck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishDeckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).PublishDeck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/PublishDeck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).PublishDeck(ctx, req.(*PublishDeckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_UnpublishDeck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CatalogDeckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).UnpublishDeck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/UnpublishDeck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).UnpublishDeck(ctx, req.(*CatalogDeckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_BrowseCatalog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BrowseCatalogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).BrowseCatalog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/BrowseCatalog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).BrowseCatalog(ctx, req.(*BrowseCatalogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_GetCatalogDeck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CatalogDeckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).GetCatalogDeck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/GetCatalogDeck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).GetCatalogDeck(ctx, req.(*CatalogDeckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_ForkDeck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForkDeckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).ForkDeck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/ForkDeck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).ForkDeck(ctx, req.(*ForkDeckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_GetUpstreamChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CatalogDeckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).GetUpstreamChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/GetUpstreamChanges",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).GetUpstreamChanges(ctx, req.(*CatalogDeckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_MergeUpstream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeUpstreamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).MergeUpstream(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/MergeUpstream",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).MergeUpstream(ctx, req.(*MergeUpstreamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_GetStudyStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StudyStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).GetStudyStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/GetStudyStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).GetStudyStats(ctx, req.(*StudyStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_GetReviewHeatmap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StudyStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).GetReviewHeatmap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/GetReviewHeatmap",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).GetReviewHeatmap(ctx, req.(*StudyStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_GetReviewForecast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewForecastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).GetReviewForecast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/GetReviewForecast",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).GetReviewForecast(ctx, req.(*ReviewForecastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_GetStreak_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StreakRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).GetStreak(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/GetStreak",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).GetStreak(ctx, req.(*StreakRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_UpdateStudySettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateStudySettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).UpdateStudySettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/UpdateStudySettings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).UpdateStudySettings(ctx, req.(*UpdateStudySettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_GetAchievements_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AchievementsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).GetAchievements(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/GetAchievements",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).GetAchievements(ctx, req.(*AchievementsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_FollowUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FollowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).FollowUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/FollowUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).FollowUser(ctx, req.(*FollowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_UnfollowUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FollowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).UnfollowUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/UnfollowUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).UnfollowUser(ctx, req.(*FollowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_ListFollowing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FollowingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).ListFollowing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/ListFollowing",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).ListFollowing(ctx, req.(*FollowingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cards_GetLeaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaderboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardsServer).GetLeaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cards.Cards/GetLeaderboard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardsServer).GetLeaderboard(ctx, req.(*LeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Cards_ServiceDesc is the grpc.ServiceDesc for Cards service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Cards_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cards.Cards",
+	HandlerType: (*CardsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateDeck",
+			Handler:    _Cards_CreateDeck_Handler,
+		},
+		{
+			MethodName: "GetDeck",
+			Handler:    _Cards_GetDeck_Handler,
+		},
+		{
+			MethodName: "ListDecks",
+			Handler:    _Cards_ListDecks_Handler,
+		},
+		{
+			MethodName: "UpdateDeck",
+			Handler:    _Cards_UpdateDeck_Handler,
+		},
+		{
+			MethodName: "DeleteDeck",
+			Handler:    _Cards_DeleteDeck_Handler,
+		},
+		{
+			MethodName: "CreateCard",
+			Handler:    _Cards_CreateCard_Handler,
+		},
+		{
+			MethodName: "GetCard",
+			Handler:    _Cards_GetCard_Handler,
+		},
+		{
+			MethodName: "ListCards",
+			Handler:    _Cards_ListCards_Handler,
+		},
+		{
+			MethodName: "UpdateCard",
+			Handler:    _Cards_UpdateCard_Handler,
+		},
+		{
+			MethodName: "DeleteCard",
+			Handler:    _Cards_DeleteCard_Handler,
+		},
+		{
+			MethodName: "SubmitReview",
+			Handler:    _Cards_SubmitReview_Handler,
+		},
+		{
+			MethodName: "GetDueCards",
+			Handler:    _Cards_GetDueCards_Handler,
+		},
+		{
+			MethodName: "StartQuiz",
+			Handler:    _Cards_StartQuiz_Handler,
+		},
+		{
+			MethodName: "GetQuizSession",
+			Handler:    _Cards_GetQuizSession_Handler,
+		},
+		{
+			MethodName: "GetNextQuestion",
+			Handler:    _Cards_GetNextQuestion_Handler,
+		},
+		{
+			MethodName: "SubmitAnswer",
+			Handler:    _Cards_SubmitAnswer_Handler,
+		},
+		{
+			MethodName: "SkipQuestion",
+			Handler:    _Cards_SkipQuestion_Handler,
+		},
+		{
+			MethodName: "PauseQuiz",
+			Handler:    _Cards_PauseQuiz_Handler,
+		},
+		{
+			MethodName: "ResumeQuiz",
+			Handler:    _Cards_ResumeQuiz_Handler,
+		},
+		{
+			MethodName: "FinishQuiz",
+			Handler:    _Cards_FinishQuiz_Handler,
+		},
+		{
+			MethodName: "GradeAnswer",
+			Handler:    _Cards_GradeAnswer_Handler,
+		},
+		{
+			MethodName: "GetChallengeRecords",
+			Handler:    _Cards_GetChallengeRecords_Handler,
+		},
+		{
+			MethodName: "ImportCards",
+			Handler:    _Cards_ImportCards_Handler,
+		},
+		{
+			MethodName: "PreviewImport",
+			Handler:    _Cards_PreviewImport_Handler,
+		},
+		{
+			MethodName: "ExportCards",
+			Handler:    _Cards_ExportCards_Handler,
+		},
+		{
+			MethodName: "GetJob",
+			Handler:    _Cards_GetJob_Handler,
+		},
+		{
+			MethodName: "GetJobResult",
+			Handler:    _Cards_GetJobResult_Handler,
+		},
+		{
+			MethodName: "Search",
+			Handler:    _Cards_Search_Handler,
+		},
+		{
+			MethodName: "Autocomplete",
+			Handler:    _Cards_Autocomplete_Handler,
+		},
+		{
+			MethodName: "CreateFolder",
+			Handler:    _Cards_CreateFolder_Handler,
+		},
+		{
+			MethodName: "ListFolders",
+			Handler:    _Cards_ListFolders_Handler,
+		},
+		{
+			MethodName: "UpdateFolder",
+			Handler:    _Cards_UpdateFolder_Handler,
+		},
+		{
+			MethodName: "DeleteFolder",
+			Handler:    _Cards_DeleteFolder_Handler,
+		},
+		{
+			MethodName: "MoveDeck",
+			Handler:    _Cards_MoveDeck_Handler,
+		},
+		{
+			MethodName: "CreateFilteredDeck",
+			Handler:    _Cards_CreateFilteredDeck_Handler,
+		},
+		{
+			MethodName: "GetFilteredDeck",
+			Handler:    _Cards_GetFilteredDeck_Handler,
+		},
+		{
+			MethodName: "ListFilteredDecks",
+			Handler:    _Cards_ListFilteredDecks_Handler,
+		},
+		{
+			MethodName: "UpdateFilteredDeck",
+			Handler:    _Cards_UpdateFilteredDeck_Handler,
+		},
+		{
+			MethodName: "DeleteFilteredDeck",
+			Handler:    _Cards_DeleteFilteredDeck_Handler,
+		},
+		{
+			MethodName: "CreateInvitation",
+			Handler:    _Cards_CreateInvitation_Handler,
+		},
+		{
+			MethodName: "ListInvitations",
+			Handler:    _Cards_ListInvitations_Handler,
+		},
+		{
+			MethodName: "RevokeInvitation",
+			Handler:    _Cards_RevokeInvitation_Handler,
+		},
+		{
+			MethodName: "AcceptInvitation",
+			Handler:    _Cards_AcceptInvitation_Handler,
+		},
+		{
+			MethodName: "ListMembers",
+			Handler:    _Cards_ListMembers_Handler,
+		},
+		{
+			MethodName: "UpdateMember",
+			Handler:    _Cards_UpdateMember_Handler,
+		},
+		{
+			MethodName: "RemoveMember",
+			Handler:    _Cards_RemoveMember_Handler,
+		},
+		{
+			MethodName: "ListSharedDecks",
+			Handler:    _Cards_ListSharedDecks_Handler,
+		},
+		{
+			MethodName: "PublishDeck",
+			Handler:    _Cards_PublishDeck_Handler,
+		},
+		{
+			MethodName: "UnpublishDeck",
+			Handler:    _Cards_UnpublishDeck_Handler,
+		},
+		{
+			MethodName: "BrowseCatalog",
+			Handler:    _Cards_BrowseCatalog_Handler,
+		},
+		{
+			MethodName: "GetCatalogDeck",
+			Handler:    _Cards_GetCatalogDeck_Handler,
+		},
+		{
+			MethodName: "ForkDeck",
+			Handler:    _Cards_ForkDeck_Handler,
+		},
+		{
+			MethodName: "GetUpstreamChanges",
+			Handler:    _Cards_GetUpstreamChanges_Handler,
+		},
+		{
+			MethodName: "MergeUpstream",
+			Handler:    _Cards_MergeUpstream_Handler,
+		},
+		{
+			MethodName: "GetStudyStats",
+			Handler:    _Cards_GetStudyStats_Handler,
+		},
+		{
+			MethodName: "GetReviewHeatmap",
+			Handler:    _Cards_GetReviewHeatmap_Handler,
+		},
+		{
+			MethodName: "GetReviewForecast",
+			Handler:    _Cards_GetReviewForecast_Handler,
+		},
+		{
+			MethodName: "GetStreak",
+			Handler:    _Cards_GetStreak_Handler,
+		},
+		{
+			MethodName: "UpdateStudySettings",
+			Handler:    _Cards_UpdateStudySettings_Handler,
+		},
+		{
+			MethodName: "GetAchievements",
+			Handler:    _Cards_GetAchievements_Handler,
+		},
+		{
+			MethodName: "FollowUser",
+			Handler:    _Cards_FollowUser_Handler,
+		},
+		{
+			MethodName: "UnfollowUser",
+			Handler:    _Cards_UnfollowUser_Handler,
+		},
+		{
+			MethodName: "ListFollowing",
+			Handler:    _Cards_ListFollowing_Handler,
+		},
+		{
+			MethodName: "GetLeaderboard",
+			Handler:    _Cards_GetLeaderboard_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "cards.proto",
+}
@@ -0,0 +1,213 @@
+// Package consumer reads events from a RabbitMQ queue with retries and a dead-letter queue.
+//
+// It is a module of its own, shared by the mailer and cards services.
+package consumer
+
+import (
+	"context"
+	"errors"
+	"github.com/Salladin95/rmqtools"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"log"
+	"time"
+)
+
+const (
+	// retryCountHeader counts failed deliveries of a message
+	retryCountHeader = "x-retry-count"
+	// routingKeyHeader keeps the routing key of the original event, retried messages come back with the queue name instead
+	routingKeyHeader = "x-original-routing-key"
+	// errorHeader keeps the last error of a dead-lettered message
+	errorHeader = "x-last-error"
+)
+
+// Handler processes a single event, returned errors trigger a retry unless they are permanent
+type Handler func(ctx context.Context, key string, payload []byte) error
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks an error as not worth retrying, such messages go straight to the dead-letter queue
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// IsPermanent reports whether the error was marked with Permanent
+func IsPermanent(err error) bool {
+	var permanent permanentError
+	return errors.As(err, &permanent)
+}
+
+// publisher is the part of a channel process needs, tests replace it
+type publisher interface {
+	PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+}
+
+type Options struct {
+	Exchange    string
+	Queue       string
+	Keys        []string
+	MaxAttempts int
+	RetryDelay  time.Duration
+}
+
+// Consumer reads events from a durable queue bound to the exchange.
+// Failed messages are parked in "<queue>.retry" for RetryDelay and then return to the queue,
+// after MaxAttempts they end up in "<queue>.dead-letter" for manual inspection.
+type Consumer struct {
+	conn    *amqp.Connection
+	options Options
+}
+
+func NewConsumer(conn *amqp.Connection, options Options) (*Consumer, error) {
+	consumer := &Consumer{conn: conn, options: options}
+	if err := consumer.setup(); err != nil {
+		return nil, err
+	}
+	return consumer, nil
+}
+
+func (c *Consumer) retryQueue() string {
+	return c.options.Queue + ".retry"
+}
+
+func (c *Consumer) deadLetterQueue() string {
+	return c.options.Queue + ".dead-letter"
+}
+
+func (c *Consumer) setup() error {
+	ch, err := c.conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	if err := rmqtools.DeclareExchange(ch, c.options.Exchange); err != nil {
+		return err
+	}
+
+	if _, err := ch.QueueDeclare(c.options.Queue, true, false, false, false, nil); err != nil {
+		return err
+	}
+	for _, key := range c.options.Keys {
+		if err := ch.QueueBind(c.options.Queue, key, c.options.Exchange, false, nil); err != nil {
+			return err
+		}
+	}
+
+	// expired messages of the retry queue are dead-lettered back into the main queue via the default exchange
+	_, err = ch.QueueDeclare(c.retryQueue(), true, false, false, false, amqp.Table{
+		"x-message-ttl":             c.options.RetryDelay.Milliseconds(),
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": c.options.Queue,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = ch.QueueDeclare(c.deadLetterQueue(), true, false, false, false, nil)
+	return err
+}
+
+// Listen consumes messages until the context is cancelled or the channel is closed
+func (c *Consumer) Listen(ctx context.Context, handler Handler) error {
+	ch, err := c.conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	if err := ch.Qos(10, 0, false); err != nil {
+		return err
+	}
+
+	deliveries, err := ch.Consume(c.options.Queue, "", false, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Waiting for messages [Exchange, Queue] [%s, %s]\n", c.options.Exchange, c.options.Queue)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case d, ok := <-deliveries:
+			if !ok {
+				return errors.New("deliveries channel is closed")
+			}
+			c.process(ctx, ch, d, handler)
+		}
+	}
+}
+
+func (c *Consumer) process(ctx context.Context, ch publisher, d amqp.Delivery, handler Handler) {
+	key := d.RoutingKey
+	if original, ok := d.Headers[routingKeyHeader].(string); ok {
+		key = original
+	}
+
+	handlerErr := handler(ctx, key, d.Body)
+	if handlerErr == nil {
+		c.ack(d)
+		return
+	}
+
+	attempt := retryCount(d) + 1
+	target := c.retryQueue()
+	if IsPermanent(handlerErr) || attempt >= c.options.MaxAttempts {
+		target = c.deadLetterQueue()
+	}
+	log.Printf("failed to handle %s (attempt %d), moving to %s: %s\n", key, attempt, target, handlerErr.Error())
+
+	headers := amqp.Table{}
+	for k, v := range d.Headers {
+		headers[k] = v
+	}
+	headers[retryCountHeader] = int32(attempt)
+	headers[routingKeyHeader] = key
+	headers[errorHeader] = handlerErr.Error()
+
+	err := ch.PublishWithContext(ctx, "", target, false, false, amqp.Publishing{
+		Headers:      headers,
+		ContentType:  d.ContentType,
+		DeliveryMode: amqp.Persistent,
+		Body:         d.Body,
+	})
+	if err != nil {
+		// the message stays in the queue and will be redelivered
+		log.Printf("failed to move message to %s: %s\n", target, err.Error())
+		if nackErr := d.Nack(false, true); nackErr != nil {
+			log.Printf("failed to nack message: %s\n", nackErr.Error())
+		}
+		return
+	}
+	c.ack(d)
+}
+
+func (c *Consumer) ack(d amqp.Delivery) {
+	if err := d.Ack(false); err != nil {
+		log.Printf("failed to ack message: %s\n", err.Error())
+	}
+}
+
+func retryCount(d amqp.Delivery) int {
+	switch v := d.Headers[retryCountHeader].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	default:
+		return 0
+	}
+}
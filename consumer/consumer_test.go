@@ -0,0 +1,177 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"testing"
+)
+
+const (
+	testQueue = "events-queue"
+	testKey   = "cards.job.queued"
+)
+
+// fakeChannel records published messages, it fails every publish while err is set
+type fakeChannel struct {
+	published []published
+	err       error
+}
+
+type published struct {
+	queue string
+	msg   amqp.Publishing
+}
+
+func (ch *fakeChannel) PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool,
+	msg amqp.Publishing) error {
+	if ch.err != nil {
+		return ch.err
+	}
+	ch.published = append(ch.published, published{queue: key, msg: msg})
+	return nil
+}
+
+// fakeAcknowledger records how a delivery was settled
+type fakeAcknowledger struct {
+	acked   bool
+	nacked  bool
+	requeue bool
+}
+
+func (a *fakeAcknowledger) Ack(tag uint64, multiple bool) error {
+	a.acked = true
+	return nil
+}
+
+func (a *fakeAcknowledger) Nack(tag uint64, multiple, requeue bool) error {
+	a.nacked, a.requeue = true, requeue
+	return nil
+}
+
+func (a *fakeAcknowledger) Reject(tag uint64, requeue bool) error {
+	return a.Nack(tag, false, requeue)
+}
+
+func newTestConsumer() *Consumer {
+	return &Consumer{options: Options{Queue: testQueue, MaxAttempts: 3}}
+}
+
+func delivery(ack *fakeAcknowledger, routingKey string, headers amqp.Table) amqp.Delivery {
+	return amqp.Delivery{
+		Acknowledger: ack,
+		RoutingKey:   routingKey,
+		Headers:      headers,
+		ContentType:  "application/json",
+		Body:         []byte(`{"jobId":"1"}`),
+	}
+}
+
+// recordingHandler records the keys it is called with and fails with err
+func recordingHandler(err error) (Handler, *[]string) {
+	var keys []string
+	return func(ctx context.Context, key string, payload []byte) error {
+		keys = append(keys, key)
+		return err
+	}, &keys
+}
+
+var errUnavailable = errors.New("service is unavailable")
+
+func TestProcessDelivers(t *testing.T) {
+	handler, keys := recordingHandler(nil)
+	ch, ack := &fakeChannel{}, &fakeAcknowledger{}
+
+	newTestConsumer().process(context.Background(), ch, delivery(ack, testKey, nil), handler)
+
+	if !ack.acked || len(ch.published) != 0 {
+		t.Fatalf("acked %v and published %d messages, want the message acked and nothing published", ack.acked, len(ch.published))
+	}
+	if len(*keys) != 1 || (*keys)[0] != testKey {
+		t.Errorf("handler got keys %v", *keys)
+	}
+}
+
+func TestProcessRetriesFailedDeliveries(t *testing.T) {
+	handler, _ := recordingHandler(errUnavailable)
+	ch, ack := &fakeChannel{}, &fakeAcknowledger{}
+
+	newTestConsumer().process(context.Background(), ch, delivery(ack, testKey, amqp.Table{"x-trace": "abc"}), handler)
+
+	if !ack.acked || len(ch.published) != 1 {
+		t.Fatalf("acked %v and published %d messages, want the message moved", ack.acked, len(ch.published))
+	}
+	moved := ch.published[0]
+	if moved.queue != testQueue+".retry" {
+		t.Errorf("moved to %s, want the retry queue", moved.queue)
+	}
+	headers := moved.msg.Headers
+	if headers[retryCountHeader] != int32(1) || headers[routingKeyHeader] != testKey || headers["x-trace"] != "abc" {
+		t.Errorf("retried message headers %v", headers)
+	}
+	if headers[errorHeader] == "" || string(moved.msg.Body) != `{"jobId":"1"}` {
+		t.Errorf("retried message lost its error or body: %v %q", headers, moved.msg.Body)
+	}
+	if moved.msg.DeliveryMode != amqp.Persistent {
+		t.Error("retried message isn't persistent")
+	}
+}
+
+func TestProcessRestoresRoutingKeyOfRetriedMessages(t *testing.T) {
+	handler, keys := recordingHandler(errUnavailable)
+	ch, ack := &fakeChannel{}, &fakeAcknowledger{}
+	// messages coming back from the retry queue are routed by the queue name
+	headers := amqp.Table{retryCountHeader: int32(1), routingKeyHeader: testKey}
+
+	newTestConsumer().process(context.Background(), ch, delivery(ack, testQueue, headers), handler)
+
+	if len(*keys) != 1 || (*keys)[0] != testKey {
+		t.Errorf("handler got keys %v, want the original routing key", *keys)
+	}
+	if len(ch.published) != 1 || ch.published[0].queue != testQueue+".retry" || ch.published[0].msg.Headers[retryCountHeader] != int32(2) {
+		t.Errorf("second failure published %+v, want a second retry", ch.published)
+	}
+}
+
+func TestProcessDeadLettersAfterMaxAttempts(t *testing.T) {
+	handler, _ := recordingHandler(errUnavailable)
+	ch, ack := &fakeChannel{}, &fakeAcknowledger{}
+	// retry counts may come back as int64 depending on the client that set them
+	headers := amqp.Table{retryCountHeader: int64(2), routingKeyHeader: testKey}
+
+	newTestConsumer().process(context.Background(), ch, delivery(ack, testQueue, headers), handler)
+
+	if !ack.acked || len(ch.published) != 1 || ch.published[0].queue != testQueue+".dead-letter" {
+		t.Fatalf("third failure published %+v, want the message dead-lettered", ch.published)
+	}
+	if ch.published[0].msg.Headers[retryCountHeader] != int32(3) {
+		t.Errorf("dead-lettered message headers %v", ch.published[0].msg.Headers)
+	}
+}
+
+func TestProcessDeadLettersPermanentFailures(t *testing.T) {
+	ch, ack := &fakeChannel{}, &fakeAcknowledger{}
+	handler := func(ctx context.Context, key string, payload []byte) error {
+		return Permanent(errors.New("invalid event"))
+	}
+
+	newTestConsumer().process(context.Background(), ch, delivery(ack, testKey, nil), handler)
+
+	if len(ch.published) != 1 || ch.published[0].queue != testQueue+".dead-letter" {
+		t.Fatalf("permanent failure published %+v, want the message dead-lettered at once", ch.published)
+	}
+	if ch.published[0].msg.Headers[errorHeader] != "invalid event" {
+		t.Errorf("dead-lettered message headers %v", ch.published[0].msg.Headers)
+	}
+}
+
+func TestProcessRequeuesWhenMoveFails(t *testing.T) {
+	handler, _ := recordingHandler(errUnavailable)
+	ch, ack := &fakeChannel{err: errors.New("channel closed")}, &fakeAcknowledger{}
+
+	newTestConsumer().process(context.Background(), ch, delivery(ack, testKey, nil), handler)
+
+	if ack.acked || !ack.nacked || !ack.requeue {
+		t.Errorf("acked %v, nacked %v, requeued %v, want the message nacked back into the queue", ack.acked, ack.nacked, ack.requeue)
+	}
+}
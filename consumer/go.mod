@@ -0,0 +1,8 @@
+module github.com/Salladin95/card-quizzler-microservices/consumer
+
+go 1.21.5
+
+require (
+	github.com/Salladin95/rmqtools v1.0.6
+	github.com/rabbitmq/amqp091-go v1.9.0
+)
@@ -0,0 +1,54 @@
+package handlers
+
+// Routing keys of the events the mailer consumes from the broker exchange
+const (
+	EmailVerificationRequestedKey = "auth.email-verification.requested"
+	PasswordResetRequestedKey     = "auth.password-reset.requested"
+	AccountLockedKey              = "auth.account.locked"
+	StudyReminderRequestedKey     = "study.reminder.requested"
+	DeckInvitedKey                = "cards.deck.invited"
+)
+
+// Keys lists every routing key handled by the mailer
+var Keys = []string{
+	EmailVerificationRequestedKey,
+	PasswordResetRequestedKey,
+	AccountLockedKey,
+	StudyReminderRequestedKey,
+	DeckInvitedKey,
+}
+
+// recipient is the part shared by every event addressed to a user
+type recipient struct {
+	UserID string `json:"userId"`
+	Email  string `json:"email" validate:"required,email"`
+	Name   string `json:"name"`
+	// Locale is optional, the default locale is used when it's empty or unsupported
+	Locale string `json:"locale"`
+}
+
+type tokenEvent struct {
+	recipient
+	Token     string `json:"token" validate:"required"`
+	ExpiresAt int64  `json:"expiresAt"`
+}
+
+type accountLockedEvent struct {
+	recipient
+	LockedUntil int64 `json:"lockedUntil"`
+}
+
+// deckInvitedEvent is published by the cards service, the invitee may have no account yet
+type deckInvitedEvent struct {
+	recipient
+	Token        string `json:"token" validate:"required"`
+	ExpiresAt    int64  `json:"expiresAt"`
+	DeckTitle    string `json:"deckTitle" validate:"required"`
+	InviterEmail string `json:"inviterEmail"`
+	Role         string `json:"role" validate:"oneof=viewer editor owner"`
+}
+
+type studyReminderEvent struct {
+	recipient
+	DueCards int `json:"dueCards" validate:"min=1"`
+}
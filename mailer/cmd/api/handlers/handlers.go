@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/Salladin95/card-quizzler-microservices/consumer"
+	"github.com/Salladin95/card-quizzler-microservices/mailer-service/cmd/api/config"
+	"github.com/Salladin95/card-quizzler-microservices/mailer-service/cmd/api/mail"
+	"github.com/Salladin95/card-quizzler-microservices/mailer-service/cmd/api/templates"
+	"github.com/go-playground/validator/v10"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type templateData struct {
+	Name        string
+	Link        string
+	ExpiresAt   string
+	LockedUntil string
+	DueCards    int
+	// DeckTitle, InviterEmail and Role describe a deck invitation
+	DeckTitle    string
+	InviterEmail string
+	Role         string
+}
+
+type MailerHandlers struct {
+	config   config.AppCfg
+	renderer *templates.Renderer
+	sender   mail.Sender
+	validate *validator.Validate
+}
+
+func NewMailerHandlers(cfg config.AppCfg, renderer *templates.Renderer, sender mail.Sender) *MailerHandlers {
+	return &MailerHandlers{
+		config:   cfg,
+		renderer: renderer,
+		sender:   sender,
+		validate: validator.New(),
+	}
+}
+
+// HandleEvent renders and sends the email matching the routing key.
+// Malformed events are reported as permanent failures, so they are dead-lettered right away.
+func (mh *MailerHandlers) HandleEvent(ctx context.Context, key string, payload []byte) error {
+	switch key {
+	case EmailVerificationRequestedKey:
+		var event tokenEvent
+		if err := mh.decode(payload, &event); err != nil {
+			return err
+		}
+		return mh.send(ctx, templates.EmailVerification, event.recipient, templateData{
+			Link:      mh.link("/verify-email", url.Values{"token": {event.Token}}),
+			ExpiresAt: formatTime(event.ExpiresAt),
+		})
+	case PasswordResetRequestedKey:
+		var event tokenEvent
+		if err := mh.decode(payload, &event); err != nil {
+			return err
+		}
+		return mh.send(ctx, templates.PasswordReset, event.recipient, templateData{
+			Link:      mh.link("/reset-password", url.Values{"token": {event.Token}}),
+			ExpiresAt: formatTime(event.ExpiresAt),
+		})
+	case AccountLockedKey:
+		var event accountLockedEvent
+		if err := mh.decode(payload, &event); err != nil {
+			return err
+		}
+		return mh.send(ctx, templates.AccountLocked, event.recipient, templateData{
+			Link:        mh.link("/forgot-password", nil),
+			LockedUntil: formatTime(event.LockedUntil),
+		})
+	case StudyReminderRequestedKey:
+		var event studyReminderEvent
+		if err := mh.decode(payload, &event); err != nil {
+			return err
+		}
+		return mh.send(ctx, templates.StudyReminder, event.recipient, templateData{
+			Link:     mh.link("/study", nil),
+			DueCards: event.DueCards,
+		})
+	case DeckInvitedKey:
+		var event deckInvitedEvent
+		if err := mh.decode(payload, &event); err != nil {
+			return err
+		}
+		return mh.send(ctx, templates.DeckInvitation, event.recipient, templateData{
+			Link:         mh.link("/invitations/accept", url.Values{"token": {event.Token}}),
+			ExpiresAt:    formatTime(event.ExpiresAt),
+			DeckTitle:    event.DeckTitle,
+			InviterEmail: event.InviterEmail,
+			Role:         event.Role,
+		})
+	default:
+		return consumer.Permanent(fmt.Errorf("unknown routing key - %s", key))
+	}
+}
+
+func (mh *MailerHandlers) decode(payload []byte, event any) error {
+	if err := json.Unmarshal(payload, event); err != nil {
+		return consumer.Permanent(fmt.Errorf("failed to unmarshal event: %s", err.Error()))
+	}
+	if err := mh.validate.Struct(event); err != nil {
+		return consumer.Permanent(fmt.Errorf("invalid event: %s", err.Error()))
+	}
+	return nil
+}
+
+func (mh *MailerHandlers) send(ctx context.Context, template string, to recipient, data templateData) error {
+	data.Name = to.Name
+	locale := to.Locale
+	if locale == "" {
+		locale = mh.config.DEFAULT_LOCALE
+	}
+
+	rendered, err := mh.renderer.Render(template, locale, data)
+	if err != nil {
+		return consumer.Permanent(fmt.Errorf("failed to render %s: %s", template, err.Error()))
+	}
+
+	return mh.sender.Send(ctx, mail.Message{
+		From:    mh.config.MAIL_FROM,
+		To:      to.Email,
+		Subject: rendered.Subject,
+		Text:    rendered.Text,
+		HTML:    rendered.HTML,
+	})
+}
+
+// link builds a link to the web app
+func (mh *MailerHandlers) link(path string, query url.Values) string {
+	link := strings.TrimSuffix(mh.config.APP_URL, "/") + path
+	if len(query) > 0 {
+		link += "?" + query.Encode()
+	}
+	return link
+}
+
+func formatTime(unix int64) string {
+	return time.Unix(unix, 0).UTC().Format("02 Jan 2006 15:04 MST")
+}
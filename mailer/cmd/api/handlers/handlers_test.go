@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/consumer"
+	"github.com/Salladin95/card-quizzler-microservices/mailer-service/cmd/api/config"
+	"github.com/Salladin95/card-quizzler-microservices/mailer-service/cmd/api/mail"
+	"github.com/Salladin95/card-quizzler-microservices/mailer-service/cmd/api/templates"
+	"io"
+	"mime/quotedprintable"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestHandlers(t *testing.T, mailbox string) *MailerHandlers {
+	t.Helper()
+	renderer, err := templates.NewRenderer("en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender, err := mail.NewFileSender(mailbox)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.AppCfg{MAIL_FROM: "quizzler@example.com", APP_URL: "https://quizzler.example.com/", DEFAULT_LOCALE: "en"}
+	return NewMailerHandlers(cfg, renderer, sender)
+}
+
+// readMailbox returns the messages written by the file sender with their bodies decoded
+func readMailbox(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		headers, body, _ := strings.Cut(string(data), "\r\n\r\n")
+		decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(body)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		messages = append(messages, headers+"\r\n\r\n"+string(decoded))
+	}
+	return messages
+}
+
+func TestHandleEventSendsMail(t *testing.T) {
+	tests := []struct {
+		key     string
+		payload string
+		want    []string
+	}{
+		{
+			key:     EmailVerificationRequestedKey,
+			payload: `{"userId":"1","email":"ann@example.com","name":"Ann","token":"a b&c","expiresAt":1704067200}`,
+			want:    []string{"To: ann@example.com", "Subject: Confirm your email", "https://quizzler.example.com/verify-email?token=a+b%26c", "01 Jan 2024 00:00 UTC"},
+		},
+		{
+			key:     PasswordResetRequestedKey,
+			payload: `{"email":"ann@example.com","name":"Анна","locale":"ru-RU","token":"xyz","expiresAt":1704067200}`,
+			want:    []string{"Анна", "https://quizzler.example.com/reset-password?token=xyz"},
+		},
+		{
+			key:     AccountLockedKey,
+			payload: `{"email":"ann@example.com","lockedUntil":1704070800}`,
+			want:    []string{"https://quizzler.example.com/forgot-password", "01 Jan 2024 01:00 UTC"},
+		},
+		{
+			key:     StudyReminderRequestedKey,
+			payload: `{"email":"ann@example.com","name":"Ann","dueCards":7}`,
+			want:    []string{"https://quizzler.example.com/study", "7"},
+		},
+		{
+			key:     DeckInvitedKey,
+			payload: `{"email":"bob@example.com","deckTitle":"Spanish verbs","inviterEmail":"ann@example.com","role":"editor","token":"xyz","expiresAt":1704067200}`,
+			want:    []string{"Spanish verbs", "ann@example.com", "an editor", "https://quizzler.example.com/invitations/accept?token=xyz"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := newTestHandlers(t, dir).HandleEvent(context.Background(), tt.key, []byte(tt.payload)); err != nil {
+				t.Fatal(err)
+			}
+			messages := readMailbox(t, dir)
+			if len(messages) != 1 {
+				t.Fatalf("mailbox holds %d messages, want 1", len(messages))
+			}
+			for _, want := range append(tt.want, "From: quizzler@example.com") {
+				if !strings.Contains(messages[0], want) {
+					t.Errorf("message lacks %q:\n%s", want, messages[0])
+				}
+			}
+		})
+	}
+}
+
+func TestHandleEventRejectsBadEventsPermanently(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		payload string
+	}{
+		{"unknown key", "auth.user.created", `{}`},
+		{"malformed json", EmailVerificationRequestedKey, `{"email":`},
+		{"invalid email", EmailVerificationRequestedKey, `{"email":"ann","token":"abc"}`},
+		{"missing token", PasswordResetRequestedKey, `{"email":"ann@example.com"}`},
+		{"nothing due", StudyReminderRequestedKey, `{"email":"ann@example.com","dueCards":0}`},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		err := newTestHandlers(t, dir).HandleEvent(context.Background(), tt.key, []byte(tt.payload))
+		if err == nil || !consumer.IsPermanent(err) {
+			t.Errorf("%s: %v, want a permanent error", tt.name, err)
+		}
+		if messages := readMailbox(t, dir); len(messages) != 0 {
+			t.Errorf("%s: sent %d messages", tt.name, len(messages))
+		}
+	}
+}
+
+func TestHandleEventRetriesSendFailures(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mailbox")
+	handlers := newTestHandlers(t, dir)
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	err := handlers.HandleEvent(context.Background(), AccountLockedKey, []byte(`{"email":"ann@example.com"}`))
+	if err == nil || consumer.IsPermanent(err) {
+		t.Errorf("failed send returned %v, want an error worth retrying", err)
+	}
+}
@@ -0,0 +1,33 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type fileSender struct {
+	dir string
+}
+
+// NewFileSender creates a Sender writing every message as an .eml file into dir,
+// it replaces a real mailbox for local development and tests
+func NewFileSender(dir string) (Sender, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &fileSender{dir: dir}, nil
+}
+
+func (s *fileSender) Send(ctx context.Context, message Message) error {
+	data, err := message.Bytes()
+	if err != nil {
+		return err
+	}
+	recipient := strings.NewReplacer("@", "_at_", "/", "_", "\\", "_").Replace(message.To)
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), recipient)
+	return os.WriteFile(filepath.Join(s.dir, name), data, 0o644)
+}
@@ -0,0 +1,8 @@
+package mail
+
+import "context"
+
+// Sender delivers rendered messages
+type Sender interface {
+	Send(ctx context.Context, message Message) error
+}
@@ -0,0 +1,34 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+)
+
+type smtpSender struct {
+	addr string
+	auth smtp.Auth
+}
+
+// NewSMTPSender creates a Sender delivering messages through the smtp server at host:port,
+// plain auth is used when the username is set
+func NewSMTPSender(host, port, username, password string) Sender {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &smtpSender{addr: fmt.Sprintf("%s:%s", host, port), auth: auth}
+}
+
+func (s *smtpSender) Send(ctx context.Context, message Message) error {
+	data, err := message.Bytes()
+	if err != nil {
+		return err
+	}
+	// net/smtp doesn't support contexts, so only check that the caller is still waiting
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return smtp.SendMail(s.addr, s.auth, message.From, []string{message.To}, data)
+}
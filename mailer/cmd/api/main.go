@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"github.com/Salladin95/card-quizzler-microservices/consumer"
+	"github.com/Salladin95/card-quizzler-microservices/mailer-service/cmd/api/config"
+	"github.com/Salladin95/card-quizzler-microservices/mailer-service/cmd/api/handlers"
+	"github.com/Salladin95/card-quizzler-microservices/mailer-service/cmd/api/mail"
+	"github.com/Salladin95/card-quizzler-microservices/mailer-service/cmd/api/templates"
+	"github.com/Salladin95/rmqtools"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const (
+	AmqpExchange = "broker"
+	AmqpQueue    = "mailer-queue"
+)
+
+func main() {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	renderer, err := templates.NewRenderer(cfg.AppCfg.DEFAULT_LOCALE)
+	if err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	sender, err := newSender(cfg.AppCfg)
+	if err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	rabbitConn, err := rmqtools.ConnectToRabbit(cfg.AppCfg.RABBIT_URL)
+	if err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+	defer rabbitConn.Close()
+
+	eventConsumer, err := consumer.NewConsumer(rabbitConn, consumer.Options{
+		Exchange:    AmqpExchange,
+		Queue:       AmqpQueue,
+		Keys:        handlers.Keys,
+		MaxAttempts: cfg.AppCfg.MAX_DELIVERY_ATTEMPTS,
+		RetryDelay:  cfg.AppCfg.RETRY_DELAY,
+	})
+	if err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	mailerHandlers := handlers.NewMailerHandlers(cfg.AppCfg, renderer, sender)
+	if err := eventConsumer.Listen(ctx, mailerHandlers.HandleEvent); err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+	log.Println("Received termination signal. Shutting down gracefully.")
+}
+
+func newSender(cfg config.AppCfg) (mail.Sender, error) {
+	if cfg.MAIL_SENDER == config.SenderSMTP {
+		return mail.NewSMTPSender(cfg.SMTP_HOST, cfg.SMTP_PORT, cfg.SMTP_USERNAME, cfg.SMTP_PASSWORD), nil
+	}
+	return mail.NewFileSender(cfg.MAILBOX_DIR)
+}
@@ -0,0 +1,127 @@
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmlTemplate "html/template"
+	"io/fs"
+	"strings"
+	textTemplate "text/template"
+)
+
+const (
+	EmailVerification = "email-verification"
+	PasswordReset     = "password-reset"
+	AccountLocked     = "account-locked"
+	StudyReminder     = "study-reminder"
+	DeckInvitation    = "deck-invitation"
+)
+
+// ErrUnknownTemplate is returned when neither the requested nor the default locale has the template
+var ErrUnknownTemplate = errors.New("unknown template")
+
+//go:embed files
+var files embed.FS
+
+type Rendered struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Renderer renders emails from templates stored as files/<locale>/<name>.txt and files/<locale>/<name>.html.
+// The text template must define a "subject" block.
+type Renderer struct {
+	defaultLocale string
+	text          map[string]*textTemplate.Template
+	html          map[string]*htmlTemplate.Template
+}
+
+func NewRenderer(defaultLocale string) (*Renderer, error) {
+	r := &Renderer{
+		defaultLocale: defaultLocale,
+		text:          make(map[string]*textTemplate.Template),
+		html:          make(map[string]*htmlTemplate.Template),
+	}
+	err := fs.WalkDir(files, "files", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		// files/<locale>/<name>.<ext>
+		parts := strings.Split(path, "/")
+		if len(parts) != 3 {
+			return fmt.Errorf("unexpected template path - %s", path)
+		}
+		locale, file := parts[1], parts[2]
+		switch {
+		case strings.HasSuffix(file, ".txt"):
+			tmpl, err := textTemplate.ParseFS(files, path)
+			if err != nil {
+				return err
+			}
+			if tmpl.Lookup("subject") == nil {
+				return fmt.Errorf("template %s doesn't define a subject", path)
+			}
+			r.text[key(locale, strings.TrimSuffix(file, ".txt"))] = tmpl
+		case strings.HasSuffix(file, ".html"):
+			tmpl, err := htmlTemplate.ParseFS(files, path)
+			if err != nil {
+				return err
+			}
+			r.html[key(locale, strings.TrimSuffix(file, ".html"))] = tmpl
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Render renders the template in the given locale and falls back to the default locale
+func (r *Renderer) Render(name, locale string, data any) (Rendered, error) {
+	textTmpl, htmlTmpl := r.lookup(name, locale)
+	if textTmpl == nil {
+		return Rendered{}, fmt.Errorf("%w - %s", ErrUnknownTemplate, name)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := textTmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Rendered{}, err
+	}
+	if err := textTmpl.Execute(&text, data); err != nil {
+		return Rendered{}, err
+	}
+	if htmlTmpl != nil {
+		if err := htmlTmpl.Execute(&html, data); err != nil {
+			return Rendered{}, err
+		}
+	}
+
+	return Rendered{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()),
+		HTML:    html.String(),
+	}, nil
+}
+
+func (r *Renderer) lookup(name, locale string) (*textTemplate.Template, *htmlTemplate.Template) {
+	for _, l := range []string{locale, baseLocale(locale), r.defaultLocale} {
+		if tmpl, ok := r.text[key(l, name)]; ok {
+			return tmpl, r.html[key(l, name)]
+		}
+	}
+	return nil, nil
+}
+
+// baseLocale turns "en-US" into "en"
+func baseLocale(locale string) string {
+	base, _, _ := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	return strings.ToLower(base)
+}
+
+func key(locale, name string) string {
+	return locale + "/" + name
+}
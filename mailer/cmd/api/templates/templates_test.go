@@ -0,0 +1,166 @@
+package templates
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testData struct {
+	Name         string
+	Link         string
+	ExpiresAt    string
+	LockedUntil  string
+	DueCards     int
+	DeckTitle    string
+	InviterEmail string
+	Role         string
+}
+
+var data = testData{
+	Name:         "Ann",
+	Link:         "https://quizzler.example.com/verify-email?token=abc",
+	ExpiresAt:    "02 Jan 2024 15:04 UTC",
+	LockedUntil:  "02 Jan 2024 16:04 UTC",
+	DueCards:     12,
+	DeckTitle:    "Spanish verbs",
+	InviterEmail: "bob@example.com",
+	Role:         "editor",
+}
+
+var names = []string{EmailVerification, PasswordReset, AccountLocked, StudyReminder, DeckInvitation}
+
+func newRenderer(t *testing.T) *Renderer {
+	t.Helper()
+	renderer, err := NewRenderer("en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return renderer
+}
+
+func TestRenderLocales(t *testing.T) {
+	renderer := newRenderer(t)
+	tests := []struct {
+		locale string
+		// greeting is how every template of the locale greets the user
+		greeting string
+	}{
+		{"en", "Hi Ann"},
+		{"ru", "Ann"},
+	}
+	subjects := make(map[string]string)
+	for _, tt := range tests {
+		for _, name := range names {
+			rendered, err := renderer.Render(name, tt.locale, data)
+			if err != nil {
+				t.Fatalf("%s/%s: %v", tt.locale, name, err)
+			}
+			if rendered.Subject == "" || strings.Contains(rendered.Subject, "\n") {
+				t.Errorf("%s/%s: subject %q", tt.locale, name, rendered.Subject)
+			}
+			if strings.Contains(rendered.Text, "{{") || strings.Contains(rendered.Text, "<no value>") {
+				t.Errorf("%s/%s: text has unrendered parts:\n%s", tt.locale, name, rendered.Text)
+			}
+			for _, part := range []string{rendered.Text, rendered.HTML} {
+				if !strings.Contains(part, tt.greeting) {
+					t.Errorf("%s/%s: %q doesn't greet with %q", tt.locale, name, part, tt.greeting)
+				}
+			}
+			if !strings.Contains(rendered.HTML, `<html lang="`+tt.locale+`">`) {
+				t.Errorf("%s/%s: html isn't marked as %s", tt.locale, name, tt.locale)
+			}
+			if strings.Contains(rendered.HTML, "ZgotmplZ") {
+				t.Errorf("%s/%s: html template rejected a value:\n%s", tt.locale, name, rendered.HTML)
+			}
+			if tt.locale == "en" {
+				subjects[name] = rendered.Subject
+			} else if rendered.Subject == subjects[name] {
+				t.Errorf("%s/%s: subject %q isn't translated", tt.locale, name, rendered.Subject)
+			}
+		}
+	}
+}
+
+func TestRenderTemplateData(t *testing.T) {
+	renderer := newRenderer(t)
+	rendered, err := renderer.Render(EmailVerification, "en", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rendered.Subject != "Confirm your email" {
+		t.Errorf("subject %q", rendered.Subject)
+	}
+	for _, want := range []string{data.Link, data.ExpiresAt} {
+		if !strings.Contains(rendered.Text, want) {
+			t.Errorf("text lacks %q:\n%s", want, rendered.Text)
+		}
+	}
+	// the link stays intact in the href attribute
+	if !strings.Contains(rendered.HTML, `href="https://quizzler.example.com/verify-email?token=abc"`) {
+		t.Errorf("html lacks the link:\n%s", rendered.HTML)
+	}
+
+	rendered, err = renderer.Render(StudyReminder, "ru", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(rendered.Text, "12") || !strings.Contains(rendered.HTML, "12") {
+		t.Errorf("study reminder doesn't tell the due cards:\n%s", rendered.Text)
+	}
+}
+
+func TestRenderEscapesHTML(t *testing.T) {
+	renderer := newRenderer(t)
+	hostile := data
+	hostile.Name = `<script>alert("x")</script>`
+	rendered, err := renderer.Render(PasswordReset, "en", hostile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(rendered.HTML, "<script>") {
+		t.Errorf("html doesn't escape the name:\n%s", rendered.HTML)
+	}
+	// the text part isn't html, it keeps the name as is
+	if !strings.Contains(rendered.Text, hostile.Name) {
+		t.Errorf("text altered the name:\n%s", rendered.Text)
+	}
+}
+
+func TestRenderFallsBackToDefaultLocale(t *testing.T) {
+	renderer := newRenderer(t)
+	english, err := renderer.Render(AccountLocked, "en", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	russian, err := renderer.Render(AccountLocked, "ru", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		locale string
+		want   Rendered
+	}{
+		{"", english},
+		{"de", english},
+		{"de-AT", english},
+		{"ru-RU", russian},
+		{"ru_RU", russian},
+		{"RU", russian},
+	}
+	for _, tt := range tests {
+		rendered, err := renderer.Render(AccountLocked, tt.locale, data)
+		if err != nil {
+			t.Fatalf("locale %q: %v", tt.locale, err)
+		}
+		if rendered.Subject != tt.want.Subject {
+			t.Errorf("locale %q rendered %q, want %q", tt.locale, rendered.Subject, tt.want.Subject)
+		}
+	}
+}
+
+func TestRenderUnknownTemplate(t *testing.T) {
+	if _, err := newRenderer(t).Render("welcome", "en", data); !errors.Is(err, ErrUnknownTemplate) {
+		t.Errorf("unknown template returned %v, want ErrUnknownTemplate", err)
+	}
+}
@@ -0,0 +1,24 @@
+module github.com/Salladin95/card-quizzler-microservices/mailer-service
+
+go 1.21.5
+
+require (
+	github.com/Salladin95/card-quizzler-microservices/consumer v0.0.0
+	github.com/Salladin95/rmqtools v1.0.6
+	github.com/go-playground/validator/v10 v10.17.0
+	github.com/joho/godotenv v1.5.1
+)
+
+require (
+	github.com/gabriel-vasile/mimetype v1.4.2 // indirect
+	github.com/go-playground/locales v0.14.1 // indirect
+	github.com/go-playground/universal-translator v0.18.1 // indirect
+	github.com/leodido/go-urn v1.2.4 // indirect
+	github.com/rabbitmq/amqp091-go v1.9.0 // indirect
+	golang.org/x/crypto v0.18.0 // indirect
+	golang.org/x/net v0.20.0 // indirect
+	golang.org/x/sys v0.16.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace github.com/Salladin95/card-quizzler-microservices/consumer => ../consumer